@@ -0,0 +1,136 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"landmark-api/internal/config"
+	"landmark-api/internal/contract"
+	"landmark-api/internal/models"
+	"landmark-api/internal/testharness"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestContract serves the documented routes from the real router, wrapped
+// in a contract.Checker, and fails on any response the spec doesn't allow.
+func TestContract(t *testing.T) {
+	ctx := context.Background()
+	env, err := testharness.Start(ctx)
+	if err != nil {
+		t.Fatalf("error starting test environment: %v", err)
+	}
+	defer env.Close()
+
+	t.Setenv("JWT_SECRET", "contract-test-secret")
+	t.Setenv("MAIL_DRIVER", config.MailDriverFile)
+	t.Setenv("MAIL_DIR", t.TempDir())
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("error loading config: %v", err)
+	}
+	cfg.Cache.RedisHost = env.CacheConfig.RedisHost
+	cfg.Cache.RedisPort = env.CacheConfig.RedisPort
+
+	app, err := newApp(cfg, env.DB)
+	if err != nil {
+		t.Fatalf("error building app: %v", err)
+	}
+	spec, err := contract.Load()
+	if err != nil {
+		t.Fatalf("error loading spec: %v", err)
+	}
+	checker := contract.NewChecker(spec)
+	handler := checker.Middleware(app.handler)
+
+	landmark, err := testharness.Landmark().Images("https://example.com/tower.jpg").Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path string, body interface{}, header map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		var payload bytes.Buffer
+		if body != nil {
+			if err := json.NewEncoder(&payload).Encode(body); err != nil {
+				t.Fatal(err)
+			}
+		}
+		req := httptest.NewRequest(method, path, &payload)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	credentials := map[string]string{
+		"name":     "Contract Test",
+		"email":    "contract@example.com",
+		"password": "correct-horse-battery",
+	}
+	if rec := do(http.MethodPost, "/auth/register", credentials, nil); rec.Code != http.StatusOK {
+		t.Fatalf("register: status %d: %s", rec.Code, rec.Body)
+	}
+	rec := do(http.MethodPost, "/auth/login", credentials, nil)
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&login); err != nil || login.Token == "" {
+		t.Fatalf("login: status %d, no token: %v", rec.Code, err)
+	}
+	var apiKey models.APIKey
+	if err := env.DB.Joins("JOIN users ON users.id = api_keys.user_id").Where("users.email = ?", credentials["email"]).First(&apiKey).Error; err != nil {
+		t.Fatalf("error finding API key: %v", err)
+	}
+
+	withKey := map[string]string{"x-api-key": apiKey.Key}
+	withToken := map[string]string{"Authorization": "Bearer " + login.Token}
+	requests := []struct {
+		method string
+		path   string
+		body   interface{}
+		header map[string]string
+	}{
+		{http.MethodGet, "/status", nil, nil},
+		{http.MethodGet, "/api/v1/landmarks", nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks?limit=1", nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks/" + landmark.ID.String(), nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks/country/France", nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks/city/Paris", nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks/name/" + url.PathEscape(landmark.Name), nil, withKey},
+		{http.MethodGet, "/api/v1/landmarks/category/Historical", nil, withKey},
+		{http.MethodGet, "/api/v1/categories/tree", nil, withKey},
+		{http.MethodGet, "/api/v1/meta/locales", nil, withKey},
+		{http.MethodGet, "/api/v1/stats/landmarks", nil, withKey},
+		{http.MethodGet, "/user/api/v1/notifications", nil, withToken},
+		{http.MethodPut, "/user/api/v1/notifications/read", nil, withToken},
+		{http.MethodGet, "/user/api/v1/usage/statements", nil, withToken},
+		{http.MethodPut, "/user/api/v1/update", map[string]interface{}{"name": "Contract Tester"}, withToken},
+		// Errors must match the spec as well
+		{http.MethodGet, "/api/v1/landmarks/not-a-uuid", nil, withKey},
+		{http.MethodGet, "/user/api/v1/export/00000000-0000-0000-0000-000000000000", nil, withToken},
+	}
+	for _, r := range requests {
+		do(r.method, r.path, r.body, r.header)
+	}
+
+	rec = do(http.MethodPost, "/user/api/v1/export", nil, withToken)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("create export: status %d: %s", rec.Code, rec.Body)
+	}
+	do(http.MethodGet, rec.Header().Get("Location"), nil, withToken)
+	do(http.MethodGet, rec.Header().Get("Location")+"/download", nil, withToken)
+
+	if err := checker.Err(); err != nil {
+		t.Errorf("responses don't match the spec:\n%v", err)
+	}
+}
@@ -23,7 +23,3494 @@ const docTemplate = `{
     },
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
-    "paths": {},
+    "paths": {
+        "/admin/landmarks/bulk": {
+            "post": {
+                "description": "Set the category or status of, add or remove tags on, or delete many landmarks, or publish many pending submissions, in one transaction. If any item fails nothing is changed; the per-item results say why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change many landmarks at once",
+                "parameters": [
+                    {
+                        "description": "Operation and IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BulkLandmarkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.BulkResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BulkConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/landmarks/upload-photo": {
+            "post": {
+                "description": "Uploads multiple files to S3 and returns their URLs",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "files"
+                ],
+                "summary": "Upload multiple files",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Files to upload",
+                        "name": "images",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.uploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/partners/contributions": {
+            "get": {
+                "description": "Count the landmarks each data partner proposed, and how many of them are pending, approved or rejected, most proposals first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List partner contribution stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PartnerContributions"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/tree": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all categories nested under their parent categories, with landmark counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get the category tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/contribution/submit-photo": {
+            "post": {
+                "description": "Uploads multiple photos to S3 and returns their URLs",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Submit photos",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Photos to upload",
+                        "name": "photos",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.uploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/geo/geocode": {
+            "get": {
+                "description": "Resolve a place name or address to candidate coordinates. Counts against the plan's daily geocoding quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geo"
+                ],
+                "summary": "Geocode a place name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place name or address",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/geo/reverse": {
+            "get": {
+                "description": "Resolve coordinates to the address at that point. Counts against the plan's daily geocoding quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geo"
+                ],
+                "summary": "Reverse geocode a point",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.GeocodingResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks": {
+            "get": {
+                "description": "Get a list of landmarks with optional filtering and sorting",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "List landmarks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field and order (e.g., '-name' for descending)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this exact name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks in this city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks in this country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks in this category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks open at this RFC 3339 time, by their opening hours",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks open now, by their opening hours",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at least this, in their local currency",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at most this, in their local currency",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks with free entry, or when false, those that charge",
+                        "name": "free_entry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'cursor' to page with meta.next_cursor instead of offset",
+                        "name": "paginate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor from the previous page; keep the same filters and sort",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/category/{category}": {
+            "get": {
+                "description": "Get a list of landmarks for a specific category, including its subcategories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "List landmarks by category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category name",
+                        "name": "category",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field and order (e.g., '-name' for descending)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks open at this RFC 3339 time, by their opening hours",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks open now, by their opening hours",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at least this, in their local currency",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at most this, in their local currency",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks with free entry, or when false, those that charge",
+                        "name": "free_entry",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/city/{city}": {
+            "get": {
+                "description": "Get a list of landmarks for a specific city",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "List landmarks by city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field and order (e.g., '-name' for descending)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks open at this RFC 3339 time, by their opening hours",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks open now, by their opening hours",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at least this, in their local currency",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at most this, in their local currency",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks with free entry, or when false, those that charge",
+                        "name": "free_entry",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/clusters": {
+            "get": {
+                "description": "Group the landmarks inside a bounding box into grid clusters sized for a map zoom level, each with its count and centroid",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Cluster landmarks for a map",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bounding box as minLon,minLat,maxLon,maxLat",
+                        "name": "bbox",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Map zoom level (0-20)",
+                        "name": "zoom",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ClusterList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/country/{country}": {
+            "get": {
+                "description": "Get a list of landmarks for a specific country",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "List landmarks by country",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Country name",
+                        "name": "country",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field and order (e.g., '-name' for descending)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks open at this RFC 3339 time, by their opening hours",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks open now, by their opening hours",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at least this, in their local currency",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at most this, in their local currency",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks with free entry, or when false, those that charge",
+                        "name": "free_entry",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/name/{name}": {
+            "get": {
+                "description": "Get a list of landmarks matching a given name (partial match)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "List landmarks by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark name (partial)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field and order (e.g., '-name' for descending)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of fields to include",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only landmarks open at this RFC 3339 time, by their opening hours",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks open now, by their opening hours",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at least this, in their local currency",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only landmarks whose entry price is at most this, in their local currency",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only landmarks with free entry, or when false, those that charge",
+                        "name": "free_entry",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/search": {
+            "post": {
+                "description": "Search for landmarks within a given radius of a point, nearest first, with each result's distance_km",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Search landmarks by proximity",
+                "parameters": [
+                    {
+                        "description": "Search parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LandmarkList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/{id}": {
+            "get": {
+                "description": "Get detailed information about a landmark",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Get a landmark by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default), jsonapi, hal or compact",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.LandmarkView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/landmarks/{id}/custom-fields": {
+            "get": {
+                "description": "Get the private custom fields your account attached to a landmark. Enterprise only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Get a landmark's custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Set the private custom fields your account attached to a landmark, removing any not given. Enterprise only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Replace a landmark's custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom fields",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReplaceCustomFieldsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove every private custom field your account attached to a landmark. Enterprise only.",
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Delete a landmark's custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Set the given private custom fields on a landmark, keeping the others. A null value removes a field. Enterprise only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Update some of a landmark's custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Landmark ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateCustomFieldsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/match": {
+            "post": {
+                "description": "Resolve a partner's landmark record (name, city, coordinates) to our best-matching landmark IDs with confidence scores",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Match a partner landmark record",
+                "parameters": [
+                    {
+                        "description": "Partner landmark record",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/meta/locales": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the currency, locale and phone prefix of every ISO 3166-1 country, so clients can format a landmark's prices, dates and phone numbers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meta"
+                ],
+                "summary": "List country locale metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/partner/contributions": {
+            "get": {
+                "description": "Count the landmarks the partner proposed, and how many of them are pending, approved or rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partner"
+                ],
+                "summary": "Get partner contribution stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartnerContributions"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/partner/landmarks": {
+            "post": {
+                "description": "Propose a landmark as an Enterprise data partner. The proposal joins the submission queue under the partner's ID, or is published at once for partners listed in PARTNER_TRUSTED_IDS, in which case the new landmark's ID is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "partner"
+                ],
+                "summary": "Propose a landmark",
+                "parameters": [
+                    {
+                        "description": "Proposed landmark",
+                        "name": "proposal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PartnerLandmarkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/services.PartnerProposal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/landmarks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get landmark counts by category, plus counts by country and recently added landmarks on paid plans. Refreshed hourly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get landmark statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PublicLandmarkStats"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync": {
+            "get": {
+                "description": "Get the landmarks created, updated and deleted since a checkpoint, so offline copies can be kept up to date without downloading everything again. Without since, returns every landmark as created. Landmarks that were unpublished since the checkpoint are listed as deleted. Follow meta.next_cursor until it is null, then keep meta.checkpoint as the since of the next sync.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "landmarks"
+                ],
+                "summary": "Sync landmarks for offline use",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "meta.checkpoint of the previous sync, an RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of changes per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "meta.next_cursor from the previous page; keep the same since",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or compact",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate a user with the provided email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Authenticate a user",
+                "parameters": [
+                    {
+                        "description": "Login details",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.loginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.authResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, with the invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Account temporarily locked",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts; see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with the provided email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Registration details",
+                        "name": "registration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.registrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.authResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, with the invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Get the status and check latency of each dependency (database, cache, storage, payments, weather provider) and recent incidents, for the status page",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get service status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/delete-account": {
+            "post": {
+                "description": "Delete the account of the logged in user after confirming their password. Tokens and API keys stop working immediately; the account, its keys, usage, request logs and Stripe customer are purged for good at purge_at. Accounts with a paid subscription must cancel it first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete account",
+                "parameters": [
+                    {
+                        "description": "Password of the account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.deleteAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AccountDeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/export": {
+            "post": {
+                "description": "Queue a zip archive of everything stored about the logged in user: profile, subscriptions, API keys, sessions, usage, usage reports, custom fields, notifications, onboarding emails sent, request credits bought, usage statements and request logs. The archive is built in the background; poll the export in the Location header and download it once completed. An export already queued is returned instead of a new one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export account data",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountExport"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the export"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/export/{id}": {
+            "get": {
+                "description": "Report the status of one of the logged in user's account exports.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get account export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/export/{id}/download": {
+            "get": {
+                "description": "Download a completed account export as a zip archive.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Download account export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/notifications": {
+            "get": {
+                "description": "Get the logged in user's notifications about their quota, submissions, billing and account security, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationList"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/notifications/read": {
+            "put": {
+                "tags": [
+                    "user"
+                ],
+                "summary": "Mark all notifications read",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/notifications/{id}/read": {
+            "put": {
+                "tags": [
+                    "user"
+                ],
+                "summary": "Mark a notification read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/update": {
+            "put": {
+                "description": "Update user's name, password and/or opt-out of onboarding emails",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Update user information",
+                "parameters": [
+                    {
+                        "description": "User update details",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, with the invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/usage/statements": {
+            "get": {
+                "description": "Get the statements of the user's ended billing periods, newest first: the requests made, the plan's quota and the overage past it, and the most requested endpoints.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "List usage statements",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UsageStatement"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/api/v1/usage/statements/{id}": {
+            "get": {
+                "description": "Get one of the user's usage statements as JSON, or download it as CSV or PDF with format.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Download a usage statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Statement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or pdf",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UsageStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "description": "PurgeAt is when the account and its data are permanently removed.",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BulkConflictResponse": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "Allowed are the values accepted where the request had an unknown\none, such as a filter or format.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "applied": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "$ref": "#/definitions/landmark-api_internal_errors.Code"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields are the fields of a request that failed validation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "operation": {
+                    "$ref": "#/definitions/services.BulkOperation"
+                },
+                "reset_at": {
+                    "description": "ResetAt and RetryAfter, in seconds as in the Retry-After header, say\nwhen a request refused for going over a limit can be retried.",
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.BulkItemResult"
+                    }
+                },
+                "retry_after": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BulkLandmarkRequest": {
+            "type": "object",
+            "required": [
+                "ids",
+                "operation"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "operation": {
+                    "type": "string",
+                    "enum": [
+                        "set_category",
+                        "set_status",
+                        "add_tags",
+                        "remove_tags",
+                        "delete",
+                        "publish_submissions"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.ClusterList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.LandmarkCluster"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/handlers.ClusterMeta"
+                }
+            }
+        },
+        "handlers.ClusterMeta": {
+            "type": "object",
+            "properties": {
+                "bbox": {
+                    "type": "string"
+                },
+                "cell_size": {
+                    "description": "CellSize is the side of the grid cells, in degrees.",
+                    "type": "number"
+                },
+                "zoom": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "Allowed are the values accepted where the request had an unknown\none, such as a filter or format.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "$ref": "#/definitions/landmark-api_internal_errors.Code"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Fields are the fields of a request that failed validation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "reset_at": {
+                    "description": "ResetAt and RetryAfter, in seconds as in the Retry-After header, say\nwhen a request refused for going over a limit can be retried.",
+                    "type": "string"
+                },
+                "retry_after": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.LandmarkList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {}
+                },
+                "meta": {
+                    "description": "Meta is a ListMeta, or a CursorListMeta for cursor pagination."
+                }
+            }
+        },
+        "handlers.MatchRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NotificationList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/handlers.NotificationListMeta"
+                }
+            }
+        },
+        "handlers.NotificationListMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unread": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.PartnerLandmarkRequest": {
+            "type": "object",
+            "properties": {
+                "image_urls": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "landmark": {
+                    "$ref": "#/definitions/models.SubmissionLandmark"
+                },
+                "landmark_detail": {
+                    "$ref": "#/definitions/models.SubmissionLandmarkDetail"
+                }
+            }
+        },
+        "handlers.ReplaceCustomFieldsRequest": {
+            "type": "object",
+            "required": [
+                "fields"
+            ],
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.SearchRequest": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "limit": {
+                    "description": "Limit defaults to and is capped at maxSearchPageSize",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "offset": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "radius": {
+                    "description": "in kilometers",
+                    "type": "number",
+                    "maximum": 20000
+                }
+            }
+        },
+        "handlers.SyncMeta": {
+            "type": "object",
+            "properties": {
+                "checkpoint": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "since": {
+                    "type": "string",
+                    "x-nullable": true
+                }
+            }
+        },
+        "handlers.SyncResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.LandmarkView"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/handlers.SyncMeta"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.LandmarkView"
+                    }
+                }
+            }
+        },
+        "handlers.UpdateCustomFieldsRequest": {
+            "type": "object",
+            "required": [
+                "fields"
+            ],
+            "properties": {
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.authResponse": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.deleteAccountRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.loginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.registrationRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                },
+                "plan": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateUserRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "onboarding_opt_out": {
+                    "description": "OnboardingOptOut stops or restarts the onboarding emails; left out,\nit is unchanged.",
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                }
+            }
+        },
+        "handlers.updateUserResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.uploadResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "landmark-api_internal_errors.Code": {
+            "type": "string",
+            "enum": [
+                "INVALID_REQUEST",
+                "VALIDATION_FAILED",
+                "UNKNOWN_FILTER",
+                "UNKNOWN_FORMAT",
+                "INVALID_CURSOR",
+                "CURSOR_MISMATCH",
+                "CURSOR_EXPIRED",
+                "PAYLOAD_TOO_LARGE",
+                "UNAUTHORIZED",
+                "MISSING_API_KEY",
+                "INVALID_API_KEY",
+                "INVALID_SIGNATURE",
+                "API_KEY_EXPIRED",
+                "INVALID_CREDENTIALS",
+                "LOGIN_THROTTLED",
+                "ACCOUNT_LOCKED",
+                "FORBIDDEN",
+                "SUBSCRIPTION_REQUIRED",
+                "NOT_FOUND",
+                "LANDMARK_NOT_FOUND",
+                "SUBMISSION_NOT_FOUND",
+                "CATEGORY_NOT_FOUND",
+                "API_KEY_NOT_FOUND",
+                "USER_NOT_FOUND",
+                "SESSION_NOT_FOUND",
+                "NOTIFICATION_NOT_FOUND",
+                "METHOD_NOT_ALLOWED",
+                "CONFLICT",
+                "RATE_LIMITED",
+                "QUOTA_EXCEEDED",
+                "TOO_MANY_CONCURRENT_REQUESTS",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "TIMEOUT"
+            ],
+            "x-enum-varnames": [
+                "CodeInvalidRequest",
+                "CodeValidationFailed",
+                "CodeUnknownFilter",
+                "CodeUnknownFormat",
+                "CodeInvalidCursor",
+                "CodeCursorMismatch",
+                "CodeCursorExpired",
+                "CodePayloadTooLarge",
+                "CodeUnauthorized",
+                "CodeMissingAPIKey",
+                "CodeInvalidAPIKey",
+                "CodeInvalidSignature",
+                "CodeAPIKeyExpired",
+                "CodeInvalidCredentials",
+                "CodeLoginThrottled",
+                "CodeAccountLocked",
+                "CodeForbidden",
+                "CodeSubscriptionRequired",
+                "CodeNotFound",
+                "CodeLandmarkNotFound",
+                "CodeSubmissionNotFound",
+                "CodeCategoryNotFound",
+                "CodeAPIKeyNotFound",
+                "CodeUserNotFound",
+                "CodeSessionNotFound",
+                "CodeNotificationNotFound",
+                "CodeMethodNotAllowed",
+                "CodeConflict",
+                "CodeRateLimited",
+                "CodeQuotaExceeded",
+                "CodeTooManyConcurrent",
+                "CodeInternal",
+                "CodeUnavailable",
+                "CodeTimeout"
+            ]
+        },
+        "locale.Country": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AccountExport": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ExportStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EndpointUsage": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "p95_latency_ms": {
+                    "type": "number"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "route": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ExportPending",
+                "ExportRunning",
+                "ExportCompleted",
+                "ExportFailed"
+            ]
+        },
+        "models.ImageLabel": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.JSON": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "models.LandmarkEnrichment": {
+            "type": "object",
+            "properties": {
+                "enriched_at": {
+                    "type": "string"
+                },
+                "heritage_status": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "official_website": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "wikidata_id": {
+                    "type": "string"
+                },
+                "wikipedia_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LandmarkStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "published",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "LandmarkDraft",
+                "LandmarkPublished",
+                "LandmarkArchived"
+            ]
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.NotificationKind"
+                },
+                "read_at": {
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "quota",
+                "submission",
+                "billing",
+                "security",
+                "onboarding"
+            ],
+            "x-enum-varnames": [
+                "NotificationQuota",
+                "NotificationSubmission",
+                "NotificationBilling",
+                "NotificationSecurity",
+                "NotificationOnboarding"
+            ]
+        },
+        "models.PartnerContributions": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "last_proposed_at": {
+                    "type": "string"
+                },
+                "partner_id": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "proposed": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PublicLandmarkStats": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "landmarks_by_category": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "landmarks_by_country": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "recently_added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecentLandmark"
+                    }
+                },
+                "total_landmarks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecentLandmark": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubmissionImageTags": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImageLabel"
+                    }
+                },
+                "rejected": {
+                    "type": "boolean"
+                },
+                "relevance": {
+                    "description": "Relevance is the confidence of the most confident label showing a\nlandmark, such as a building or monument",
+                    "type": "number"
+                },
+                "reviewed_at": {
+                    "description": "ReviewedAt is when an admin last accepted or rejected the image,\noverriding the job",
+                    "type": "string"
+                },
+                "tagged_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubmissionLandmark": {
+            "type": "object",
+            "required": [
+                "category",
+                "city",
+                "country",
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "category_confidence": {
+                    "type": "number"
+                },
+                "city": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "details": {
+                    "$ref": "#/definitions/models.SubmissionLandmarkDetail"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubmissionLandmarkImage"
+                    }
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "partner_id": {
+                    "description": "PartnerID is the Enterprise data partner who proposed it through the\npartner API; nil for other submissions",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source identifies where an imported submission came from, e.g.\n\"osm:node/123\", so it is not imported again; empty for user submissions",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"pending\", \"approved\", or \"rejected\"",
+                    "type": "string"
+                },
+                "suggested_category": {
+                    "description": "SuggestedCategory and SuggestedTags are what the image-tagging job\nrecognized in the submission's images, for reviewers to pick from.\nCategoryConfidence is the confidence of the category, from 0 to 1",
+                    "type": "string"
+                },
+                "suggested_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubmissionLandmarkDetail": {
+            "type": "object",
+            "properties": {
+                "accessibility_info": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "historical_significance": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "$ref": "#/definitions/models.JSON"
+                },
+                "ticket_prices": {
+                    "$ref": "#/definitions/models.JSON"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visitor_tips": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubmissionLandmarkImage": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags is nil until the image-tagging job has looked at the image",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubmissionImageTags"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubscriptionPlan": {
+            "type": "string",
+            "enum": [
+                "FREE",
+                "PRO",
+                "ENTERPRISE"
+            ],
+            "x-enum-varnames": [
+                "FreePlan",
+                "ProPlan",
+                "EnterprisePlan"
+            ]
+        },
+        "models.UsageStatement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "overage": {
+                    "description": "Overage is how many requests were made past the quota.",
+                    "type": "integer"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "Plan is the user's plan when the statement was made.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SubscriptionPlan"
+                        }
+                    ]
+                },
+                "quota": {
+                    "description": "Quota is the requests the plan included, -1 when unlimited.",
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "top_endpoints": {
+                    "description": "TopEndpoints are the routes the user requested most in the period,\nas far as the request logs still cover it.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EndpointUsage"
+                    }
+                }
+            }
+        },
+        "repository.LandmarkCluster": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "landmark_id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "landmark_id": {
+                    "description": "LandmarkID is the landmark a published submission became.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.BulkOperation": {
+            "type": "string",
+            "enum": [
+                "set_category",
+                "set_status",
+                "add_tags",
+                "remove_tags",
+                "delete",
+                "publish_submissions"
+            ],
+            "x-enum-varnames": [
+                "BulkSetCategory",
+                "BulkSetStatus",
+                "BulkAddTags",
+                "BulkRemoveTags",
+                "BulkDelete",
+                "BulkPublishSubmissions"
+            ]
+        },
+        "services.BulkResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "operation": {
+                    "$ref": "#/definitions/services.BulkOperation"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.BulkItemResult"
+                    }
+                }
+            }
+        },
+        "services.GeocodingResult": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "country_code": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "services.LandmarkImageView": {
+            "type": "object",
+            "properties": {
+                "alt_text": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "string"
+                },
+                "display_order": {
+                    "type": "integer"
+                },
+                "embed_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_primary": {
+                    "type": "boolean"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "stream_url": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.LandmarkView": {
+            "type": "object",
+            "properties": {
+                "accessibility_info": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "country_metadata": {
+                    "description": "CountryMetadata holds currency, locale and phone prefix for\nformatting, when the country is known.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/locale.Country"
+                        }
+                    ],
+                    "x-nullable": true
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data_confidence": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "description": "DistanceKM is how far the landmark is from the point of a search.",
+                    "type": "number"
+                },
+                "enrichment": {
+                    "$ref": "#/definitions/models.LandmarkEnrichment"
+                },
+                "historical_significance": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.LandmarkImageView"
+                    }
+                },
+                "last_verified_at": {
+                    "description": "LastVerifiedAt and DataConfidence let clients judge how current the\ndata is.",
+                    "type": "string",
+                    "x-nullable": true
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "description": "Status and the timestamps are only shown to admins.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LandmarkStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ticket_prices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visitor_tips": {
+                    "type": "string"
+                },
+                "weather_info": {
+                    "description": "WeatherInfo and Enrichment are looked up for each response, and\nleft out when they are unavailable.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/services.WeatherData"
+                        }
+                    ]
+                }
+            }
+        },
+        "services.PartnerProposal": {
+            "type": "object",
+            "properties": {
+                "landmark_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is pending until the proposal is reviewed, or approved for\ntrusted partners, whose proposals are published at once.",
+                    "type": "string"
+                },
+                "submission_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.WeatherData": {
+            "type": "object",
+            "properties": {
+                "main": {
+                    "type": "object",
+                    "properties": {
+                        "temp": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "weather": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "description": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        }
+    },
     "securityDefinitions": {
         "ApiKeyAuth": {
             "type": "apiKey",
@@ -37,7 +3524,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:5050",
-	BasePath:         "/api/v1",
+	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "Landmark API",
 	Description:      "This is a landmark API server.",
@@ -11,175 +11,470 @@
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:5050
-// @BasePath /api/v1
+// @BasePath /
 
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-API-Key
 package main
 
+//go:generate go run github.com/swaggo/swag/cmd/swag init -g main.go -d ./,../../internal/api/handlers --parseDependency --parseInternal -o docs
+
 import (
+	"context"
+	"fmt"
 	"landmark-api/internal/api/controllers"
 	"landmark-api/internal/api/handlers"
+	"landmark-api/internal/apiversion"
+	"landmark-api/internal/chaos"
 	"landmark-api/internal/config"
 	"landmark-api/internal/database"
+	"landmark-api/internal/health"
 	"landmark-api/internal/logger"
+	"landmark-api/internal/mail"
 	"landmark-api/internal/middleware"
+	"landmark-api/internal/pagination"
+	"landmark-api/internal/recorder"
 	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
+	"landmark-api/internal/tracing"
 	"log"
 	"net/http"
-	"os"
 	"time"
+	// Embedded zone data, so landmark local times work without system tzdata
+	_ "time/tzdata"
 
 	_ "landmark-api/cmd/api/docs"
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
-	"github.com/rs/cors"
 	"github.com/sirupsen/logrus"
 	"github.com/stripe/stripe-go/v72"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"gorm.io/gorm"
 )
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: Error loading .env file: %v", err)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
 	}
 
 	// Initialize database connection
-	db, err := database.InitDB()
+	db, err := database.InitDB(cfg.App.DatabaseURL)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	app, err := newApp(cfg, db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.startJobs()
+
+	// Create server with timeouts
+	srv := &http.Server{
+		Handler:      app.handler,
+		Addr:         ":" + cfg.App.Port,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+	}
+
+	// Start server
+	logger.LogEvent(logrus.InfoLevel, "API started", logrus.Fields{
+		"port":        cfg.App.Port,
+		"environment": cfg.App.Environment,
+	})
+	log.Fatal(srv.ListenAndServe())
+}
+
+// app is the API's HTTP handler and the background jobs that go with it,
+// built apart from main so tests can serve the real routes.
+type app struct {
+	handler http.Handler
+	// startJobs starts the background jobs: retention, purges, exports,
+	// imports, billing and the other periodic work.
+	startJobs func()
+}
+
+// newApp wires the services, handlers and routes of the API to db.
+func newApp(cfg *config.Config, db *gorm.DB) (*app, error) {
 	// Get underlying *sql.DB instance for connection pool settings
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to get underlying *sql.DB instance:", err)
+		return nil, fmt.Errorf("failed to get underlying *sql.DB instance: %w", err)
 	}
-	stripe.Key = os.Getenv("STRIPE_SECRET_KEY")
-	rateLimitConfig := config.NewRateLimitConfig()
-	cacheConfig := config.NewCacheConfig()
-	cacheService, err := services.NewRedisCacheService(cacheConfig)
+	stripe.Key = cfg.Stripe.SecretKey
+	services.SetWeatherAPIKey(cfg.App.OpenWeatherAPIKey)
+
+	integrationConfig := cfg.Integration
+	integrationMode := recorder.Mode(integrationConfig.Mode)
+	outboundClient := &http.Client{
+		Transport: tracing.NewTransport(recorder.NewTransport(integrationMode, integrationConfig.FixturesDir, nil)),
+		Timeout:   80 * time.Second,
+	}
+	stripe.SetHTTPClient(outboundClient)
+	services.SetWeatherHTTPClient(outboundClient)
+	if integrationMode != recorder.ModeLive {
+		log.Printf("Outbound integrations running in %s mode (fixtures: %s)", integrationMode, integrationConfig.FixturesDir)
+	}
+
+	rateLimitConfig := cfg.RateLimit
+	billingConfig := cfg.Billing
+	serverConfig := cfg.Server
+	retentionConfig := cfg.Retention
+	healthConfig := cfg.Health
+	redisCache, err := services.NewRedisCacheService(cfg.Cache)
 	if err != nil {
-		log.Fatal("Failed to initialize cache service")
+		return nil, fmt.Errorf("failed to initialize cache service: %w", err)
+	}
+	// Redis being down, even now, moves the cache into memory rather than
+	// stopping the API
+	var cacheService services.CacheService = services.NewFailoverCacheService(redisCache, cfg.Cache)
+
+	// Fault injection is for staging only; never enable it in production
+	var chaosInjector *chaos.Injector
+	if chaosConfig := cfg.Chaos; chaosConfig.Enabled {
+		chaosInjector = chaos.NewInjector(chaosConfig)
+		if err := chaosInjector.RegisterDB(db); err != nil {
+			return nil, fmt.Errorf("failed to register chaos database callbacks: %w", err)
+		}
+		cacheService = chaosInjector.WrapCache(cacheService)
+		log.Printf("Chaos mode enabled: %.0f%% of requests receive injected faults", chaosConfig.RequestRate*100)
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(25)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	// The local tier sits in front of any injected faults, as it would in
+	// front of a failing Redis
+	var localCache *services.LocalCacheService
+	if cfg.Cache.LocalEnabled {
+		localCache = services.NewLocalCacheService(cacheService, cfg.Cache)
+		cacheService = localCache
+	}
+
+	database.ConfigurePool(sqlDB, cfg.Database)
+	if err := database.RegisterQueryTimeout(db, cfg.Database.QueryTimeout); err != nil {
+		return nil, fmt.Errorf("failed to register query timeout callbacks: %w", err)
+	}
+
+	replicaDBs, err := database.RegisterReplicas(db, cfg.Database.ReplicaURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to read replicas: %w", err)
+	}
+	for _, replicaDB := range replicaDBs {
+		database.ConfigurePool(replicaDB, cfg.Database)
+	}
+	readDB := database.Reader(db)
 
 	userRepo := repository.NewUserRepository(db)
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	landmarkRepo := repository.NewLandmarkRepository(db)
 	apiKeyRepo := repository.NewAPIKeyRepository(db)
 	apiUsageRepo := repository.NewAPIUsageRepository(db)
-
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET environment variable is required")
-	}
+	apiKeyLimitRepo := repository.NewAPIKeyLimitRepository(db)
+	requestCreditRepo := repository.NewRequestCreditRepository(db)
 
 	apiKeyService := services.NewAPIKeyService(apiKeyRepo, userRepo, subscriptionRepo)
 
+	mailTemplates, err := mail.ParseTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email templates: %w", err)
+	}
+	var mailer mail.Mailer
+	switch cfg.Mail.Driver {
+	case config.MailDriverFile:
+		mailer = mail.NewFileMailer(cfg.Mail.Dir)
+	case config.MailDriverSES:
+		mailer, err = mail.NewSESMailer(cfg.Mail.SESRegion, cfg.Mail.FromName, cfg.Mail.FromAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SES mailer: %w", err)
+		}
+	case config.MailDriverSMTP:
+		mailer = mail.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.FromName, cfg.Mail.FromAddress)
+	default:
+		mailer = mail.NewSendGridMailer(cfg.App.SendGridAPIKey, cfg.Mail.FromName, cfg.Mail.FromAddress)
+	}
+	mailSuppressionRepo := repository.NewMailSuppressionRepository(db)
+	mailer = mail.NewSuppressingMailer(mail.NewRetryingMailer(mailer, cfg.Mail.MaxAttempts, cfg.Mail.RetryDelay), mailSuppressionRepo)
+	emailService := services.NewEmailService(mailer, mailTemplates)
+
+	var sendGridWebhook *mail.SendGridWebhook
+	if cfg.Mail.SendGridWebhookKey != "" {
+		sendGridWebhook, err = mail.NewSendGridWebhook(cfg.Mail.SendGridWebhookKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SendGrid webhook key: %w", err)
+		}
+	}
+	var sesWebhook *mail.SESWebhook
+	if cfg.Mail.SESBounceTopicARN != "" {
+		sesWebhook = mail.NewSESWebhook(cfg.Mail.SESBounceTopicARN)
+	}
+	mailWebhookHandler := handlers.NewMailWebhookHandler(mailSuppressionRepo, sendGridWebhook, sesWebhook)
+
+	sessionService := services.NewSessionService(repository.NewSessionRepository(db), cacheService)
 	authService := services.NewAuthService(
 		userRepo,
 		subscriptionRepo,
 		apiKeyService,
-		jwtSecret,
+		sessionService,
+		cfg.App.JWTKeys(),
+		emailService,
 	)
 
+	notificationService := services.NewNotificationService(repository.NewNotificationRepository(db), userRepo, emailService)
+	onboardingService := services.NewOnboardingService(repository.NewOnboardingRepository(db), userRepo, notificationService, cfg.Onboarding.TipsAfter)
+	notificationService.Subscribe(services.NotificationQuotaWarning, onboardingService.QuotaNearing)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
 	auditLogRepo := repository.NewAuditLogRepository(db)
 	auditLogService := services.NewAuditLogService(auditLogRepo)
 	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
 
-	landmarkService := services.NewLandmarkService(landmarkRepo)
-
-	authHandler := handlers.NewAuthHandler(authService)
-	landmarkHandler := handlers.NewLandmarkHandler(landmarkService, auditLogService, cacheService, db)
+	landmarkEnrichmentRepo := repository.NewLandmarkEnrichmentRepository(db)
+	landmarkService := services.NewLandmarkService(landmarkRepo, landmarkEnrichmentRepo)
+	planSerializer := services.NewPlanSerializer(landmarkService)
+	customFieldService := services.NewCustomFieldService(repository.NewLandmarkCustomFieldRepository(db), landmarkRepo)
+	landmarkBulkService := services.NewLandmarkBulkService(repository.NewLandmarkBulkRepository(db), notificationService)
 
-	config := &handlers.SuggestionsConfig{
+	requestLogRepo := repository.NewRequestLogRepository(db)
+	searchAnalyticsRepo := repository.NewSearchAnalyticsRepository(db)
+	reviewPriorityService := services.NewReviewPriorityService(requestLogRepo, searchAnalyticsRepo)
+	metaHandler := handlers.NewMetaHandler()
+	matchService := services.NewMatchService(landmarkRepo)
+	matchHandler := handlers.NewMatchHandler(matchService)
+	partnerHandler := handlers.NewPartnerHandler(services.NewPartnerService(repository.NewPartnerRepository(db), cfg.Partner), auditLogService)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService)
+	landmarkTimelineHandler := handlers.NewLandmarkTimelineHandler(services.NewLandmarkTimelineService(landmarkRepo, auditLogRepo))
+	cursorSigner := pagination.NewSigner(cfg.Pagination.CursorSecret, cfg.Pagination.CursorTTL)
+	cacheLoader := services.NewCacheLoader(cacheService, cfg.Cache)
+	landmarkHandler := handlers.NewLandmarkHandler(landmarkService, auditLogService, cacheService, cacheLoader, reviewPriorityService, planSerializer, customFieldService, landmarkBulkService, notificationService, cursorSigner, cfg.Pagination, db, readDB)
+	cacheWarmer := handlers.NewCacheWarmer(landmarkHandler, requestLogRepo, cfg.Cache)
+	cacheHandler := handlers.NewCacheHandler(localCache, cacheLoader, cacheWarmer)
+
+	suggestionsConfig := &handlers.SuggestionsConfig{
 		MaxResults:         15,
-		MinSimilarity:      50,
 		EnabledSearchTypes: []string{"city", "country", "category", "name"},
 		CacheDuration:      5 * time.Minute,
 	}
-	suggestionHandler, err := handlers.NewSuggestionsHandler(db, cacheService, config)
+	suggestionHandler, err := handlers.NewSuggestionsHandler(readDB, cacheLoader, searchAnalyticsRepo, suggestionsConfig)
 	if err != nil {
-		log.Fatalf("Failed to initialize search capabilities: %v", err)
+		return nil, fmt.Errorf("failed to initialize search capabilities: %w", err)
 	}
 
 	rateLimiter := middleware.NewRateLimiter(rateLimitConfig)
-	apiUsageService := services.NewAPIUsageService(apiUsageRepo, subscriptionRepo, rateLimitConfig)
+	concurrencyLimiter := middleware.NewConcurrencyLimiter(rateLimitConfig)
+	apiUsageService := services.NewAPIUsageService(apiUsageRepo, subscriptionRepo, apiKeyLimitRepo, requestCreditRepo, rateLimitConfig)
 	apiUsageHandler := handlers.NewUsageHandler(apiUsageService, authService)
 
-	requestLogRepo := repository.NewRequestLogRepository(db)
-	requestLogService := services.NewRequestLogService(requestLogRepo)
+	usageReportRepo := repository.NewUsageReportRepository(db)
+	usageReportingService := services.NewUsageReportingService(subscriptionRepo, apiUsageRepo, usageReportRepo, billingConfig)
+	usageStatementRepo := repository.NewUsageStatementRepository(db)
+	usageStatementService := services.NewUsageStatementService(usageStatementRepo, subscriptionRepo, requestLogRepo, apiUsageService, notificationService, billingConfig)
+	usageStatementHandler := handlers.NewUsageStatementHandler(usageStatementService)
+
+	requestLogExportRepo := repository.NewRequestLogExportRepository(db)
+	requestLogService := services.NewRequestLogService(requestLogRepo, requestLogExportRepo)
 	requestLogHandler := handlers.NewRequestLogHandler(requestLogService)
-	requestLogger := middleware.NewRequestLogger(requestLogService)
+	requestLogger := middleware.NewRequestLogger(requestLogService, cfg.RequestLog)
 
-	awsRegion := "eu-north-1"
-	awsBucket := "properties-photos"
-	if awsRegion == "" {
-		log.Fatal("AWS Region is nedeed")
-	}
-	if awsBucket == "" {
-		log.Fatal("AWS Bucket is nedeed")
+	fileUploadHandler, err := handlers.NewFileUploadHandler(cfg.Storage.Region, cfg.Storage.Bucket, auditLogService)
+	if err != nil {
+		return nil, fmt.Errorf("error with file handler: %w", err)
 	}
 
-	fileUploadHandler, err := handlers.NewFileUploadHandler(awsRegion, awsBucket)
+	var archiver services.Archiver
+	if retentionConfig.ArchiveBucket != "" {
+		archiver, err = services.NewS3Archiver(retentionConfig.ArchiveRegion, retentionConfig.ArchiveBucket, retentionConfig.ArchivePrefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize archiver: %w", err)
+		}
+	}
+	accountExportRepo := repository.NewAccountExportRepository(db)
+	retentionService := services.NewRetentionService(retentionConfig, requestLogRepo, searchAnalyticsRepo, requestLogExportRepo, accountExportRepo, archiver)
+	accountService := services.NewAccountService(userRepo, subscriptionRepo, repository.NewAccountRepository(db), requestLogRepo, accountExportRepo, archiver, retentionConfig.DeletedAccounts)
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	loginThrottleService := services.NewLoginThrottleService(cacheService, userRepo, notificationService, auditLogService, cfg.LoginThrottle)
+	authHandler := handlers.NewAuthHandler(authService, loginThrottleService, onboardingService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	apiKeySigningHandler := handlers.NewAPIKeySigningHandler(apiKeyService)
+	apiKeyRotationHandler := handlers.NewAPIKeyRotationHandler(apiKeyService, cfg.APIKey.RotationGrace)
+	apiKeyExpiryService := services.NewAPIKeyExpiryService(apiKeyRepo, userRepo, notificationService, cfg.APIKey)
+	geocodingProvider, err := services.NewGeocodingProvider(cfg.Geocoding, outboundClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize geocoding provider: %w", err)
+	}
+	geoHandler := handlers.NewGeoHandler(services.NewGeocodingService(geocodingProvider, cacheService, cfg.Geocoding))
+	timezoneProvider, err := services.NewTimezoneProvider(cfg.Timezone, outboundClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize timezone provider: %w", err)
+	}
+	timezoneService := services.NewTimezoneService(timezoneProvider, landmarkRepo, cfg.Timezone)
+	osmImportService := services.NewOSMImportService(repository.NewOSMImportRepository(db), matchService, geocodingProvider, outboundClient, cfg.Overpass)
+	osmImportHandler := handlers.NewOSMImportHandler(osmImportService, auditLogService)
+	enrichmentService := services.NewEnrichmentService(services.NewWikidataSource(cfg.Enrichment, outboundClient), landmarkEnrichmentRepo, cfg.Enrichment)
+	imageTagger, err := services.NewImageTagger(cfg.ImageTagging, outboundClient)
 	if err != nil {
-		log.Fatal("Error with file handler")
+		return nil, fmt.Errorf("failed to initialize image tagger: %w", err)
 	}
-	stripeHandler := handlers.NewStripeHandler(authService, subscriptionRepo, userRepo, apiKeyService)
+	altTextGenerator, err := services.NewAltTextGenerator(cfg.AltText, imageTagger, cfg.ImageTagging, outboundClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize alt text generator: %w", err)
+	}
+	altTextService := services.NewAltTextService(altTextGenerator, repository.NewAltTextRepository(db), cacheService, cfg.AltText)
+	adminSubscriptionHandler := handlers.NewAdminSubscriptionHandler(
+		services.NewSubscriptionOverrideService(subscriptionRepo, userRepo),
+		auditLogService,
+	)
+	adminAPIKeyHandler := handlers.NewAdminAPIKeyHandler(
+		services.NewAPIKeyLimitService(apiKeyRepo, apiKeyLimitRepo),
+		auditLogService,
+	)
+	stripeHandler := handlers.NewStripeHandler(authService, subscriptionRepo, userRepo, requestCreditRepo, repository.NewStripeEventRepository(db), apiKeyService, billingConfig, cfg.Stripe, notificationService, cacheService)
 
 	uptimeService := handlers.NewUptimeService()
 	uptimeHandler := handlers.NewUptimeHandler(uptimeService)
 	uptimeMiddleware := handlers.NewUptimeMiddleware(uptimeService)
+	// Readiness depends only on these; cache, payments and weather
+	// failures degrade individual features and are reported on /status
+	// instead
+	coreComponents := []health.Component{
+		{Name: "database", Check: health.DatabaseCheck(sqlDB), SlowThreshold: 500 * time.Millisecond},
+		{Name: "storage", Check: health.S3Check(fileUploadHandler.S3Client, cfg.Storage.Bucket), SlowThreshold: time.Second},
+	}
+	externalChecks, err := health.LoadExternalChecks(healthConfig.ChecksFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load health checks: %w", err)
+	}
+	var externalComponents []health.Component
+	for _, check := range externalChecks {
+		component, err := check.Component(outboundClient)
+		if err != nil {
+			log.Printf("Skipping health check: %v", err)
+			continue
+		}
+		externalComponents = append(externalComponents, component)
+	}
+	monitored := append([]health.Component{
+		// The cache falls back to memory while Redis is down
+		{Name: "cache", Check: health.PingCheck(redisCache), SlowThreshold: 100 * time.Millisecond},
+		{Name: "payments", Check: health.StripeCheck(), SlowThreshold: 2 * time.Second},
+	}, externalComponents...)
+	for i, replicaDB := range replicaDBs {
+		monitored = append(monitored, health.Component{
+			Name:          fmt.Sprintf("database_replica_%d", i+1),
+			Check:         health.DatabaseCheck(replicaDB),
+			SlowThreshold: 500 * time.Millisecond,
+		})
+	}
+	healthMonitor := health.NewMonitor(healthConfig.MonitorInterval, healthConfig.MonitorTimeout, append(coreComponents, monitored...)...)
+	healthMonitor.Start(context.Background())
+	statusHandler := handlers.NewStatusHandler(healthMonitor, uptimeService)
+	analyticsHandler := handlers.NewAnalyticsHandler(services.NewAnalyticsService(requestLogRepo), uptimeService)
 
 	categoryRepo := repository.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
-	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	categoryService := services.NewCategoryService(categoryRepo, cacheService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, auditLogService)
+	imageTaggingService := services.NewImageTaggingService(imageTagger, repository.NewImageTagRepository(db), categoryRepo, cfg.ImageTagging)
+	imageTagHandler := handlers.NewImageTagHandler(imageTaggingService, auditLogService)
+
+	featureFlagService := services.NewFeatureFlagService(repository.NewFeatureFlagRepository(db), cacheService)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService, auditLogService)
 
-	landmarkStatsRepo := repository.NewLandmarkStatsRepository(db)
-	landmarkStatsService := services.NewLandmarkStatsService(landmarkStatsRepo)
+	landmarkStatsRepo := repository.NewLandmarkStatsRepository(readDB)
+	landmarkStatsService := services.NewLandmarkStatsService(landmarkStatsRepo, cacheService)
 	landmarkStatsHandler := handlers.NewLandmarkStatsHandler(landmarkStatsService)
 
+	auth := middleware.NewAuthChain(map[middleware.AuthMethod]middleware.Authenticator{
+		middleware.AuthAPIKey: middleware.NewAPIKeyAuthenticator(apiKeyService),
+		middleware.AuthJWT:    middleware.NewJWTAuthenticator(authService),
+		middleware.AuthAdmin:  middleware.NewAdminAuthenticator(authService),
+		middleware.AuthMTLS:   middleware.NewMTLSAuthenticator(services.ClientCertAccountResolver(userRepo, subscriptionRepo)),
+	})
+	// Mounted ahead of the authenticator on every router that takes API keys
+	requireSignature := middleware.RequireSignature(apiKeyService)
+
 	router := mux.NewRouter()
-	router.Use(middleware.LoggingMiddleware)
+	router.Use(middleware.TracingMiddleware)
+	router.Use(middleware.NewLoadShedder(serverConfig).Middleware)
+	router.Use(middleware.Timeout(serverConfig))
+	router.Use(middleware.LimitBody(serverConfig))
+	router.Use(middleware.Compress(serverConfig.CompressMinBytes))
+	if chaosInjector != nil {
+		router.Use(chaosInjector.Middleware)
+	}
+	router.Use(middleware.LoggingMiddleware(cfg.RequestLog))
 	router.Use(uptimeMiddleware.Middleware)
 
 	// Public routes
 	router.HandleFunc("/auth/register", authHandler.Register).Methods("POST")
 	router.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
 	router.HandleFunc("/auth/register-email", authHandler.RegisterWithEmail).Methods("POST")
-	router.HandleFunc("/health", controllers.HealthCheckHandler(db)).Methods("GET")
+	router.HandleFunc("/health", controllers.HealthCheckHandler(db, externalComponents)).Methods("GET")
+	router.HandleFunc("/readyz", controllers.ReadinessHandler(healthConfig.ReadinessTimeout, coreComponents...)).Methods("GET")
 	router.HandleFunc("/swagger", httpSwagger.WrapHandler).Methods("GET")
 	router.HandleFunc("/uptime", uptimeHandler.ServeHTTP).Methods("GET")
+	router.HandleFunc("/status", statusHandler.GetStatus).Methods("GET")
 
+	// Contributions may be anonymous; signed in contributors are told how
+	// their submissions were reviewed
 	contributionRouter := router.PathPrefix("/api/v1/contribution").Subrouter()
+	contributionRouter.Use(requireSignature)
+	contributionRouter.Use(auth.Allow(middleware.AuthJWT, middleware.AuthAPIKey))
 	contributionRouter.HandleFunc("/submit-landmark", landmarkHandler.CreateSubmission).Methods("POST")
 	contributionRouter.HandleFunc("/submit-photo", fileUploadHandler.SubmitPhotos).Methods("POST")
 
-	// API routes (protected)
-	apiRouter := router.PathPrefix("/api/v1").Subrouter()
-	apiRouter.Use(middleware.APIKeyMiddleware(apiKeyService))
-	apiRouter.Use(rateLimiter.RateLimit(authService, apiUsageService))
-	apiRouter.Use(requestLogger.LogRequest)
-
-	// Landmarks routes
-	apiRouter.HandleFunc("/landmarks", landmarkHandler.ListLandmarks).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/{id}", landmarkHandler.GetLandmark).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/country/{country}", landmarkHandler.ListLandmarksByCountry).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/name/{name}", landmarkHandler.ListLandmarksByName).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/city/{city}", landmarkHandler.ListLandmarksByCity).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/category/{category}", landmarkHandler.ListLandmarkByCategory).Methods("GET")
-	apiRouter.HandleFunc("/landmarks/search", landmarkHandler.SearchLandmarks).Methods("POST")
+	// API routes (protected). Every version serves the same handlers; the
+	// version in the request context selects the response schema.
+	for _, version := range apiversion.All {
+		// The anonymous tier is matched first, and only by requests without
+		// credentials; everything else falls through to the routes below
+		if cfg.Anonymous.Enabled {
+			anonymousRouter := router.PathPrefix("/api/" + string(version)).MatcherFunc(middleware.Anonymous).Subrouter()
+			anonymousRouter.Use(middleware.APIVersion(version))
+			anonymousRouter.Use(middleware.AnonymousAccess(cacheService, cfg.Anonymous))
+			anonymousRouter.HandleFunc("/landmarks", landmarkHandler.ListLandmarks).Methods("GET")
+		}
+
+		apiRouter := router.PathPrefix("/api/" + string(version)).Subrouter()
+		apiRouter.Use(middleware.APIVersion(version))
+		apiRouter.Use(requireSignature)
+		apiRouter.Use(auth.Require(middleware.AuthAPIKey, middleware.AuthMTLS))
+		apiRouter.Use(concurrencyLimiter.Limit)
+		apiRouter.Use(rateLimiter.RateLimit(authService, apiUsageService, notificationService))
+		apiRouter.Use(requestLogger.LogRequest)
+
+		// Landmarks routes; Enterprise keys may bypass the cache of those
+		// whose responses are cached
+		cacheBypass := middleware.CacheBypass(cacheService, cfg.Cache)
+		apiRouter.Handle("/landmarks", cacheBypass(http.HandlerFunc(landmarkHandler.ListLandmarks))).Methods("GET")
+		// Registered before /landmarks/{id}, which would otherwise match it
+		apiRouter.Handle("/landmarks/clusters", cacheBypass(http.HandlerFunc(landmarkHandler.GetClusters))).Methods("GET")
+		apiRouter.Handle("/landmarks/{id}", cacheBypass(http.HandlerFunc(landmarkHandler.GetLandmark))).Methods("GET")
+		apiRouter.HandleFunc("/landmarks/{id}/custom-fields", customFieldHandler.GetCustomFields).Methods("GET")
+		apiRouter.HandleFunc("/landmarks/{id}/custom-fields", customFieldHandler.ReplaceCustomFields).Methods("PUT")
+		apiRouter.HandleFunc("/landmarks/{id}/custom-fields", customFieldHandler.UpdateCustomFields).Methods("PATCH")
+		apiRouter.HandleFunc("/landmarks/{id}/custom-fields", customFieldHandler.DeleteCustomFields).Methods("DELETE")
+		apiRouter.Handle("/landmarks/country/{country}", cacheBypass(http.HandlerFunc(landmarkHandler.ListLandmarksByCountry))).Methods("GET")
+		apiRouter.Handle("/landmarks/name/{name}", cacheBypass(http.HandlerFunc(landmarkHandler.ListLandmarksByName))).Methods("GET")
+		apiRouter.Handle("/landmarks/city/{city}", cacheBypass(http.HandlerFunc(landmarkHandler.ListLandmarksByCity))).Methods("GET")
+		apiRouter.Handle("/landmarks/category/{category}", cacheBypass(http.HandlerFunc(landmarkHandler.ListLandmarkByCategory))).Methods("GET")
+		apiRouter.HandleFunc("/landmarks/search", landmarkHandler.SearchLandmarks).Methods("POST")
+		apiRouter.HandleFunc("/match", matchHandler.MatchLandmark).Methods("POST")
+		apiRouter.HandleFunc("/partner/landmarks", partnerHandler.ProposeLandmark).Methods("POST")
+		apiRouter.HandleFunc("/partner/contributions", partnerHandler.GetContributions).Methods("GET")
+		apiRouter.HandleFunc("/sync", landmarkHandler.SyncLandmarks).Methods("GET")
+		apiRouter.HandleFunc("/stats/landmarks", landmarkStatsHandler.GetPublicLandmarkStats).Methods("GET")
+		apiRouter.HandleFunc("/categories/tree", categoryHandler.GetCategoryTree).Methods("GET")
+		apiRouter.HandleFunc("/geo/geocode", geoHandler.Geocode).Methods("GET")
+		apiRouter.HandleFunc("/geo/reverse", geoHandler.Reverse).Methods("GET")
+		apiRouter.HandleFunc("/meta/locales", metaHandler.GetLocales).Methods("GET")
+	}
 
 	suggestionRouter := router.PathPrefix("/api/v1/suggestions").Subrouter()
-	suggestionRouter.Use(middleware.APIKeyMiddleware(apiKeyService))
+	suggestionRouter.Use(requireSignature)
+	suggestionRouter.Use(auth.Require(middleware.AuthAPIKey))
 	suggestionRouter.HandleFunc("/{type}", suggestionHandler.GetSuggestions).Methods("GET").Queries("search", "{search}")
 	suggestionRouter.HandleFunc("/landmarks/{id}", landmarkHandler.GetLandmark).Methods("GET")
 	suggestionRouter.HandleFunc("/landmarks/country/{country}", landmarkHandler.ListLandmarksByCountry).Methods("GET")
@@ -188,91 +483,274 @@ func main() {
 	suggestionRouter.HandleFunc("/landmarks/category/{category}", landmarkHandler.ListLandmarkByCategory).Methods("GET")
 	// User check routes
 	userRouter := router.PathPrefix("/user/api/v1").Subrouter()
-	userRouter.Use(middleware.AuthMiddleware(authService))
-	userRouter.HandleFunc("/validate-token", authHandler.ValidateToken).Methods("GET")
-	userRouter.HandleFunc("/me", authHandler.CheckUser).Methods("GET")
-	userRouter.HandleFunc("/usage", apiUsageHandler.GetCurrentUsage).Methods("GET")
-	userRouter.HandleFunc("/requests/logs", requestLogHandler.GetUserLogs).Methods("GET")
-	userRouter.HandleFunc("/update", authHandler.UpdateUser).Methods("PUT")
+	userRouter.Handle("/validate-token", auth.Handle(authHandler.ValidateToken, middleware.AuthJWT)).Methods("GET")
+	userRouter.Handle("/me", auth.Handle(authHandler.CheckUser, middleware.AuthJWT)).Methods("GET")
+	// Usage can also be checked programmatically with an API key
+	userRouter.Handle("/usage", auth.Handle(apiUsageHandler.GetCurrentUsage, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/usage/statements", auth.Handle(usageStatementHandler.ListStatements, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/usage/statements/{id}", auth.Handle(usageStatementHandler.GetStatement, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/requests/logs", auth.Handle(requestLogHandler.GetUserLogs, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/requests/logs/exports", auth.Handle(requestLogHandler.CreateExport, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("POST")
+	userRouter.Handle("/requests/logs/exports/{id}", auth.Handle(requestLogHandler.GetExport, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/requests/logs/exports/{id}/download", auth.Handle(requestLogHandler.DownloadExport, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
+	userRouter.Handle("/update", auth.Handle(authHandler.UpdateUser, middleware.AuthJWT)).Methods("PUT")
+	// Deleting and exporting an account need a login, never just the key
+	userRouter.Handle("/delete-account", auth.Handle(accountHandler.DeleteAccount, middleware.AuthJWT)).Methods("POST")
+	userRouter.Handle("/export", auth.Handle(accountHandler.CreateExport, middleware.AuthJWT)).Methods("POST")
+	userRouter.Handle("/export/{id}", auth.Handle(accountHandler.GetExport, middleware.AuthJWT)).Methods("GET")
+	userRouter.Handle("/export/{id}/download", auth.Handle(accountHandler.DownloadExport, middleware.AuthJWT)).Methods("GET")
+	userRouter.Handle("/sessions", auth.Handle(sessionHandler.ListSessions, middleware.AuthJWT)).Methods("GET")
+	userRouter.Handle("/sessions/{id}", auth.Handle(sessionHandler.RevokeSession, middleware.AuthJWT)).Methods("DELETE")
+	userRouter.Handle("/notifications", auth.Handle(notificationHandler.ListNotifications, middleware.AuthJWT)).Methods("GET")
+	userRouter.Handle("/notifications/read", auth.Handle(notificationHandler.MarkAllNotificationsRead, middleware.AuthJWT)).Methods("PUT")
+	userRouter.Handle("/notifications/{id}/read", auth.Handle(notificationHandler.MarkNotificationRead, middleware.AuthJWT)).Methods("PUT")
+	// Signing is managed with a login token, never with the key itself
+	userRouter.Handle("/api-key/signing", auth.Handle(apiKeySigningHandler.EnableSigning, middleware.AuthJWT)).Methods("POST")
+	userRouter.Handle("/api-key/signing", auth.Handle(apiKeySigningHandler.DisableSigning, middleware.AuthJWT)).Methods("DELETE")
+	userRouter.Handle("/api-key/rotate", auth.Handle(apiKeyRotationHandler.RotateAPIKey, middleware.AuthJWT)).Methods("POST")
+	userRouter.Handle("/feature-flags", auth.Handle(featureFlagHandler.GetUserFlags, middleware.AuthJWT, middleware.AuthAPIKey)).Methods("GET")
 
 	subscriptionRouter := router.PathPrefix("/subscription").Subrouter()
 	subscriptionRouter.HandleFunc("/create-checkout", stripeHandler.HandleCreateCheckOut).Methods("POST")
 	subscriptionRouter.HandleFunc("/create-user-account", authHandler.RegisterSub).Methods("POST")
 	subscriptionRouter.HandleFunc("/stripe-webhook", stripeHandler.HandleStripeWebhook).Methods("POST")
 
+	// Mail providers report bounces and complaints here, signed
+	router.HandleFunc("/mail/webhooks/sendgrid", mailWebhookHandler.HandleSendGridEvents).Methods("POST")
+	router.HandleFunc("/mail/webhooks/ses", mailWebhookHandler.HandleSESNotifications).Methods("POST")
+
 	subscriptionRouterManage := router.PathPrefix("/subscription/manage").Subrouter()
-	subscriptionRouterManage.Use(middleware.AuthMiddleware(authService))
+	subscriptionRouterManage.Use(auth.Require(middleware.AuthJWT))
 	subscriptionRouterManage.HandleFunc("/get-billing", stripeHandler.HandleUserBillingInfo).Methods("GET")
+	subscriptionRouterManage.HandleFunc("/change-plan", stripeHandler.HandleChangePlan).Methods("PUT")
+	subscriptionRouterManage.HandleFunc("/cancel", stripeHandler.HandleCancelSubscription).Methods("POST")
+	subscriptionRouterManage.HandleFunc("/resume", stripeHandler.HandleResumeSubscription).Methods("POST")
+	subscriptionRouterManage.HandleFunc("/buy-credits", stripeHandler.HandleBuyCredits).Methods("POST")
 
 	adminRouter := router.PathPrefix("/admin").Subrouter()
-	adminRouter.Use(middleware.AdminMiddleware(authService))
+	adminRouter.Use(auth.RequireWith(middleware.AuthRequirement{
+		Methods: []middleware.AuthMethod{middleware.AuthAdmin},
+		Bypass:  []middleware.BypassRule{middleware.BypassMethods(http.MethodOptions)},
+	}))
+	adminRouter.Use(middleware.AuditMiddleware(auditLogService))
 	adminRouter.HandleFunc("/landmarks/upload-photo", fileUploadHandler.Upload).Methods("POST")
 	adminRouter.HandleFunc("/landmarks/create", landmarkHandler.CreateLandmark).Methods("POST")
 	adminRouter.HandleFunc("/landmarks", landmarkHandler.ListAdminLandmarks).Methods("GET")
+	adminRouter.HandleFunc("/landmarks/bulk", landmarkHandler.AdminBulkHandler).Methods("POST")
 	adminRouter.HandleFunc("/landmarks/{id}", landmarkHandler.AdminEditHandler).Methods("PUT")
 	adminRouter.HandleFunc("/landmarks/{id}", landmarkHandler.AdminDeleteHandler).Methods("DELETE")
+	adminRouter.HandleFunc("/landmarks/{id}/verify", landmarkHandler.AdminVerifyHandler).Methods("PUT")
+	adminRouter.HandleFunc("/landmarks/{id}/status", landmarkHandler.AdminSetStatusHandler).Methods("PUT")
+	adminRouter.HandleFunc("/landmarks/{id}/timeline", landmarkTimelineHandler.GetTimeline).Methods("GET")
+	// Registered before /landmarks/{id}/images/{imageId}, which would otherwise match it
+	adminRouter.HandleFunc("/landmarks/{id}/images/order", landmarkHandler.AdminReorderImagesHandler).Methods("PUT")
+	adminRouter.HandleFunc("/landmarks/{id}/images", landmarkHandler.AdminAddMediaHandler).Methods("POST")
+	adminRouter.HandleFunc("/landmarks/{id}/images/{imageId}", landmarkHandler.AdminUpdateImageHandler).Methods("PUT")
+	adminRouter.HandleFunc("/landmarks/{id}/images/{imageId}", landmarkHandler.AdminDeleteMediaHandler).Methods("DELETE")
+	adminRouter.HandleFunc("/landmarks/{id}/images/{imageId}/primary", landmarkHandler.AdminSetPrimaryImageHandler).Methods("PUT")
 	adminRouter.HandleFunc("/landmarks/category", categoryHandler.ListAdminCategories).Methods("GET")
+	adminRouter.HandleFunc("/landmarks/category", categoryHandler.CreateCategory).Methods("POST")
+	adminRouter.HandleFunc("/landmarks/category/{name}", categoryHandler.RenameCategory).Methods("PUT")
+	adminRouter.HandleFunc("/landmarks/category/{name}", categoryHandler.DeleteCategory).Methods("DELETE")
+	adminRouter.HandleFunc("/landmarks/category/{name}/merge", categoryHandler.MergeCategory).Methods("POST")
+	adminRouter.HandleFunc("/landmarks/category/{name}/parent", categoryHandler.SetParentCategory).Methods("PUT")
 	adminRouter.HandleFunc("/landmarks/stats", landmarkStatsHandler.GetLandmarkStats).Methods("GET")
+	adminRouter.HandleFunc("/landmarks/stats/growth", landmarkStatsHandler.GetWeeklyGrowth).Methods("GET")
 	adminRouter.HandleFunc("/audit-logs", auditLogHandler.ListAuditLogs).Methods("GET")
+	adminRouter.HandleFunc("/analytics", analyticsHandler.GetAnalytics).Methods("GET")
+	adminRouter.HandleFunc("/users/{id}/subscription", adminSubscriptionHandler.GrantSubscription).Methods("PUT")
+	adminRouter.HandleFunc("/users/{id}/subscription", adminSubscriptionHandler.RevokeSubscription).Methods("DELETE")
+	adminRouter.HandleFunc("/api-keys/{id}/limits", adminAPIKeyHandler.GetLimits).Methods("GET")
+	adminRouter.HandleFunc("/api-keys/{id}/limits", adminAPIKeyHandler.SetLimits).Methods("PUT")
+	adminRouter.HandleFunc("/api-keys/{id}/limits", adminAPIKeyHandler.ClearLimits).Methods("DELETE")
+	adminRouter.HandleFunc("/cache/stats", cacheHandler.GetStats).Methods("GET")
+	adminRouter.HandleFunc("/cache/warm", cacheHandler.WarmCache).Methods("POST")
+	adminRouter.HandleFunc("/feature-flags", featureFlagHandler.ListFlags).Methods("GET")
+	adminRouter.HandleFunc("/feature-flags", featureFlagHandler.CreateFlag).Methods("POST")
+	adminRouter.HandleFunc("/feature-flags/{key}", featureFlagHandler.UpdateFlag).Methods("PUT")
+	adminRouter.HandleFunc("/feature-flags/{key}", featureFlagHandler.DeleteFlag).Methods("DELETE")
 	adminRouter.HandleFunc("/submissions/landmarks", landmarkHandler.ListPendingSubmissions).Methods("GET")
 	adminRouter.HandleFunc("/submissions/landmarks/approve/{id}", landmarkHandler.ApproveSubmission).Methods("PUT")
 	adminRouter.HandleFunc("/submission/landmarks/reject/{id}", landmarkHandler.RejectSubmission).Methods("DELETE")
+	adminRouter.HandleFunc("/submissions/landmarks/images/{id}", imageTagHandler.ReviewImage).Methods("PUT")
+	adminRouter.HandleFunc("/partners/contributions", partnerHandler.ListContributions).Methods("GET")
+	adminRouter.HandleFunc("/imports/osm", osmImportHandler.CreateImport).Methods("POST")
+	adminRouter.HandleFunc("/imports/osm/{id}", osmImportHandler.GetImport).Methods("GET")
+
+	// Liveness bypasses load shedding and fault injection, so an overloaded
+	// instance is not restarted
+	rootMux := http.NewServeMux()
+	rootMux.Handle("/healthz", controllers.LivenessHandler())
+	rootMux.Handle("/", router)
+
+	// Error codes wrap the whole mux so the router's own 404 and 405
+	// responses carry a code too
+	handler := middleware.CORS(cfg.CORS)(middleware.ErrorCodes(rootMux))
+
+	startJobs := func() {
+		go func() {
+			for {
+				time.Sleep(retentionConfig.Interval)
+				// Batch deletes on large tables can outlast the default query deadline
+				ctx := database.WithQueryTimeout(context.Background(), 5*time.Minute)
+				if err := retentionService.Apply(ctx, time.Now()); err != nil {
+					log.Printf("Error applying retention policies: %v", err)
+				}
+			}
+		}()
+
+		go func() {
+			for {
+				time.Sleep(retentionConfig.Interval)
+				if purged, err := accountService.PurgeDeleted(context.Background(), time.Now()); err != nil {
+					log.Printf("Error purging deleted accounts: %v", err)
+				} else if purged > 0 {
+					log.Printf("Purged %d deleted accounts", purged)
+				}
+			}
+		}()
+
+		go func() {
+			for {
+				if err := landmarkStatsService.TakeDailySnapshots(context.Background(), time.Now()); err != nil {
+					log.Printf("Error taking landmark stats snapshots: %v", err)
+				}
+				time.Sleep(time.Hour)
+			}
+		}()
+
+		go func() {
+			for {
+				time.Sleep(cfg.APIKey.ReminderInterval)
+				if err := apiKeyExpiryService.SendReminders(context.Background(), time.Now()); err != nil {
+					log.Printf("Error sending API key expiry reminders: %v", err)
+				}
+			}
+		}()
+
+		// Runs at startup too, so a deployment starts with the popular queries
+		// cached
+		if cfg.Cache.WarmEnabled {
+			go func() {
+				for {
+					if warmed, err := cacheWarmer.Warm(context.Background(), time.Now()); err != nil {
+						log.Printf("Error warming cache: %v", err)
+					} else if warmed > 0 {
+						log.Printf("Warmed %d cached responses", warmed)
+					}
+					time.Sleep(cfg.Cache.WarmInterval)
+				}
+			}()
+		}
 
-	go func() {
-		for {
-			time.Sleep(4 * time.Hour)
-			if err := requestLogRepo.DeleteOldLogs(); err != nil {
-				log.Printf("Error deleting old logs: %v", err)
-			} else {
-				log.Println("Old logs deleted successfully")
+		go func() {
+			for {
+				time.Sleep(cfg.Onboarding.Interval)
+				if sent, err := onboardingService.SendTips(context.Background(), time.Now()); err != nil {
+					log.Printf("Error sending onboarding tips: %v", err)
+				} else if sent > 0 {
+					log.Printf("Sent onboarding tips to %d users", sent)
+				}
 			}
+		}()
+
+		if timezoneProvider != nil {
+			go func() {
+				for {
+					if _, err := timezoneService.BackfillTimezones(context.Background()); err != nil {
+						log.Printf("Error backfilling landmark timezones: %v", err)
+					}
+					time.Sleep(cfg.Timezone.BackfillInterval)
+				}
+			}()
 		}
-	}()
-
-	corsMiddleware := cors.New(cors.Options{
-		AllowedOrigins: []string{"*"}, // Allow all origins
-		AllowedMethods: []string{
-			http.MethodGet,
-			http.MethodPost,
-			http.MethodPut,
-			http.MethodPatch,
-			http.MethodDelete,
-			http.MethodOptions,
-		},
-		AllowedHeaders: []string{
-			"Accept",
-			"Authorization",
-			"Content-Type",
-			"X-CSRF-Token",
-			"X-API-Key",
-			"*", // Allow all headers
-		},
-		ExposedHeaders: []string{
-			"Link",
-		},
-		AllowCredentials: false, // Must be false when using AllowedOrigins: ["*"]
-		MaxAge:           300,
-	})
 
-	// Create server with timeouts
-	srv := &http.Server{
-		Handler:      corsMiddleware.Handler(router),
-		Addr:         ":" + getPort(),
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
-	}
+		if cfg.Enrichment.Enabled {
+			go func() {
+				for {
+					if matched, err := enrichmentService.EnrichDue(context.Background(), time.Now()); err != nil {
+						log.Printf("Error enriching landmarks: %v", err)
+					} else if matched > 0 {
+						log.Printf("Matched %d landmarks to Wikidata entities", matched)
+					}
+					time.Sleep(cfg.Enrichment.Interval)
+				}
+			}()
+		}
 
-	// Start server
-	logger.LogEvent(logrus.InfoLevel, "API started", logrus.Fields{
-		"port": "8080",
-	})
-	log.Fatal(srv.ListenAndServe())
-}
+		if imageTagger != nil {
+			go func() {
+				for {
+					if tagged, err := imageTaggingService.TagPending(context.Background(), time.Now()); err != nil {
+						log.Printf("Error tagging submission images: %v", err)
+					} else if tagged > 0 {
+						log.Printf("Tagged %d submission images", tagged)
+					}
+					time.Sleep(cfg.ImageTagging.Interval)
+				}
+			}()
+		}
+
+		if altTextGenerator != nil {
+			go func() {
+				for {
+					if described, err := altTextService.GenerateDue(context.Background(), time.Now()); err != nil {
+						log.Printf("Error generating alt text: %v", err)
+					} else if described > 0 {
+						log.Printf("Generated alt text for %d images", described)
+					}
+					time.Sleep(cfg.AltText.Interval)
+				}
+			}()
+		}
 
-func getPort() string {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "5050"
+		go func() {
+			for {
+				time.Sleep(30 * time.Second)
+				if err := requestLogService.ProcessPendingExports(context.Background()); err != nil {
+					log.Printf("Error processing request log exports: %v", err)
+				}
+				if err := accountService.ProcessPendingExports(context.Background()); err != nil {
+					log.Printf("Error processing account exports: %v", err)
+				}
+			}
+		}()
+
+		// Imports reverse geocode every point, so they run apart from the
+		// exports to not hold them up
+		go func() {
+			for {
+				time.Sleep(30 * time.Second)
+				if err := osmImportService.ProcessPendingImports(context.Background()); err != nil {
+					log.Printf("Error processing OpenStreetMap imports: %v", err)
+				}
+			}
+		}()
+
+		// Usage is reported at startup too, so a restart never delays a day's
+		// report by a whole interval; days already reported are skipped
+		go func() {
+			ticker := time.NewTicker(billingConfig.UsageReportInterval)
+			defer ticker.Stop()
+			for {
+				if err := usageReportingService.ReportDailyUsage(context.Background(), time.Now()); err != nil {
+					log.Printf("Error reporting metered usage: %v", err)
+				}
+				<-ticker.C
+			}
+		}()
+
+		go func() {
+			for {
+				time.Sleep(billingConfig.StatementInterval)
+				if made, err := usageStatementService.GenerateStatements(context.Background(), time.Now()); err != nil {
+					log.Printf("Error making usage statements: %v", err)
+				} else if made > 0 {
+					log.Printf("Made %d usage statements", made)
+				}
+			}
+		}()
 	}
-	return port
+
+	return &app{handler: handler, startJobs: startJobs}, nil
 }
@@ -0,0 +1,6 @@
+//go:build tools
+
+// Tools used by go generate, so go.mod keeps their versions.
+package main
+
+import _ "github.com/swaggo/swag/cmd/swag"
@@ -0,0 +1,394 @@
+// Command landmarkctl runs common operational tasks directly against the
+// database, using the same configuration as the API.
+//
+// Usage:
+//
+//	landmarkctl <command> [flags]
+//
+// Commands:
+//
+//	create-admin      create an admin user, or promote an existing one
+//	rotate-api-key    replace a user's API key
+//	import-landmarks  import landmarks from a JSON file
+//	migrate           apply, revert or list database migrations
+//	backfill-details  create empty details for landmarks without them
+//	seed              fill a development database with fixture data
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/database"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/migrations"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/seed"
+	"landmark-api/internal/services"
+	"landmark-api/internal/validation"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{"create-admin", "create an admin user, or promote an existing one", createAdmin},
+	{"rotate-api-key", "replace a user's API key", rotateAPIKey},
+	{"import-landmarks", "import landmarks from a JSON file", importLandmarks},
+	{"migrate", "apply, revert or list database migrations", migrate},
+	{"backfill-details", "create empty details for landmarks without them", backfillDetails},
+	{"seed", "fill a development database with fixture data", seedDatabase},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cmd.run(context.Background(), cfg, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: landmarkctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'landmarkctl <command> -h' for the command's flags.")
+}
+
+// openDB connects to the configured database. Connecting applies pending
+// migrations, as it does when the API starts.
+func openDB(cfg *config.Config) (*gorm.DB, error) {
+	if cfg.App.DatabaseURL == "" {
+		return nil, errors.New("DATABASE_URL is required")
+	}
+	return database.InitDB(cfg.App.DatabaseURL)
+}
+
+func createAdmin(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	email := fs.String("email", "", "admin email (required)")
+	name := fs.String("name", "", "admin name, for new users")
+	password := fs.String("password", "", "password for new users; generated when empty")
+	fs.Parse(args)
+
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db), userRepo, subscriptionRepo)
+
+	user, err := userRepo.GetByEmail(ctx, *email)
+	switch {
+	case err == nil:
+		fmt.Printf("Promoting existing user %s to admin\n", user.Email)
+	case errors.Is(err, apperrors.ErrNotFound):
+		generated := *password == ""
+		if generated {
+			if *password, err = randomPassword(); err != nil {
+				return err
+			}
+		}
+
+		// Registering a user issues no tokens and sends no email, so no
+		// session or email service is needed
+		authService := services.NewAuthService(userRepo, subscriptionRepo, apiKeyService, nil, cfg.App.JWTKeys(), nil)
+		user, err = authService.Register(ctx, *email, *password, *name)
+		if err != nil {
+			return fmt.Errorf("error creating user: %w", err)
+		}
+
+		fmt.Printf("Created user %s (%s)\n", user.Email, user.ID)
+		if generated {
+			fmt.Printf("Generated password: %s\n", *password)
+		}
+	default:
+		return fmt.Errorf("error looking up user: %w", err)
+	}
+
+	if err := userRepo.SetRole(ctx, user.ID, "admin"); err != nil {
+		return err
+	}
+	if err := userRepo.GrantAccess(ctx, user.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s is now an admin\n", user.Email)
+	return nil
+}
+
+func rotateAPIKey(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("rotate-api-key", flag.ExitOnError)
+	email := fs.String("email", "", "email of the key's owner (required)")
+	fs.Parse(args)
+
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	apiKeyService := services.NewAPIKeyService(repository.NewAPIKeyRepository(db), userRepo, repository.NewSubscriptionRepository(db))
+
+	user, err := userRepo.GetByEmail(ctx, *email)
+	if err != nil {
+		return fmt.Errorf("error looking up user: %w", err)
+	}
+
+	var key string
+	if _, err := apiKeyService.GetAPIKeyByUserID(ctx, user.ID); err != nil {
+		// Users created by hand may never have had a key.
+		apiKey, err := apiKeyService.AssignAPIKeyToUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("error assigning API key: %w", err)
+		}
+		key = apiKey.Key
+	} else {
+		key = apiKeyService.GenerateAPIKey()
+		if err := apiKeyService.UpdateAPIKey(ctx, user.ID, key); err != nil {
+			return fmt.Errorf("error rotating API key: %w", err)
+		}
+	}
+
+	fmt.Printf("New API key for %s: %s\n", user.Email, key)
+	return nil
+}
+
+// landmarkImport is one landmark in an import file. It matches the admin
+// create-landmark payload, so exported payloads can be replayed.
+type landmarkImport struct {
+	Landmark       models.Landmark       `json:"landmark"`
+	LandmarkDetail models.LandmarkDetail `json:"landmark_detail"`
+	ImageURLs      []string              `json:"image_urls" validate:"max=20,dive,url"`
+}
+
+func importLandmarks(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import-landmarks", flag.ExitOnError)
+	file := fs.String("file", "", "JSON file with an array of landmarks (required)")
+	dryRun := fs.Bool("dry-run", false, "validate the file without importing")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var items []landmarkImport
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("error parsing %s: %w", *file, err)
+	}
+
+	var invalid []string
+	for i, item := range items {
+		if err := validation.Struct(item); err != nil {
+			invalid = append(invalid, fmt.Sprintf("item %d: %v", i, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid import file:\n  %s", strings.Join(invalid, "\n  "))
+	}
+
+	if *dryRun {
+		fmt.Printf("%d landmarks are valid\n", len(items))
+		return nil
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	landmarkRepo := repository.NewLandmarkRepository(db)
+
+	failed := 0
+	for i := range items {
+		item := &items[i]
+		if err := landmarkRepo.CreateWithDetails(ctx, &item.Landmark, &item.LandmarkDetail, item.ImageURLs); err != nil {
+			fmt.Fprintf(os.Stderr, "item %d (%s): %v\n", i, item.Landmark.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Imported %s (%s)\n", item.Landmark.Name, item.Landmark.ID)
+	}
+
+	fmt.Printf("Imported %d of %d landmarks\n", len(items)-failed, len(items))
+	if failed > 0 {
+		return fmt.Errorf("%d landmarks failed to import", failed)
+	}
+	return nil
+}
+
+// migrate runs "migrate [up|down|status] [flags]"; the action defaults to up.
+func migrate(ctx context.Context, cfg *config.Config, args []string) error {
+	action := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 0, "number of migrations to apply or revert (up: all, down: 1)")
+	dryRun := fs.Bool("dry-run", false, "print the statements without committing them")
+	fs.Parse(args)
+
+	if cfg.App.DatabaseURL == "" {
+		return errors.New("DATABASE_URL is required")
+	}
+	db, err := database.Connect(cfg.App.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	// Keep the output to the migration statements themselves.
+	db.Logger = db.Logger.LogMode(logger.Silent)
+
+	migrator := migrations.NewMigrator(db)
+	opts := migrations.Options{Steps: *steps, DryRun: *dryRun, Out: os.Stdout}
+
+	var ran []string
+	switch action {
+	case "up":
+		ran, err = migrator.Up(ctx, opts)
+	case "down":
+		ran, err = migrator.Down(ctx, opts)
+	case "status":
+		return migrationStatus(ctx, migrator)
+	default:
+		return fmt.Errorf("unknown action %q; use up, down or status", action)
+	}
+
+	verb := map[string]string{"up": "Applied", "down": "Reverted"}[action]
+	if *dryRun {
+		verb = "Would have " + strings.ToLower(verb)
+	}
+	for _, id := range ran {
+		fmt.Printf("%s %s\n", verb, id)
+	}
+	if err != nil {
+		return err
+	}
+	if len(ran) == 0 {
+		fmt.Println("Nothing to do")
+	}
+	return nil
+}
+
+func migrationStatus(ctx context.Context, migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%-40s %s\n", status.ID, applied)
+	}
+	return nil
+}
+
+func backfillDetails(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("backfill-details", flag.ExitOnError)
+	fs.Parse(args)
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	created, err := repository.NewLandmarkRepository(db).BackfillMissingDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("error backfilling details: %w", err)
+	}
+
+	fmt.Printf("Created details for %d landmarks\n", created)
+	return nil
+}
+
+func seedDatabase(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	landmarks := fs.Int("landmarks", seed.DefaultLandmarks, "number of landmarks to generate")
+	randomSeed := fs.Int64("seed", 1, "random seed; the same seed generates the same data")
+	fs.Parse(args)
+
+	if cfg.App.IsProduction() {
+		return errors.New("refusing to seed a production database")
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	result, err := seed.Run(ctx, db, seed.Options{Landmarks: *landmarks, Seed: *randomSeed})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %d landmarks with %d images and %d request logs\n", result.Landmarks, result.Images, result.RequestLogs)
+	fmt.Printf("\nUsers (password %q):\n", seed.DefaultPassword)
+	for _, user := range result.Users {
+		fmt.Printf("  %-26s %-10s %-5s API key %s\n", user.Email, user.Plan, user.Role, user.APIKey)
+	}
+	return nil
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,28 @@
+// Command mockserver serves example responses for every route in the
+// API's OpenAPI spec, so clients can be developed without a database or
+// an API key.
+//
+// Usage:
+//
+//	mockserver [-addr :5051]
+package main
+
+import (
+	"flag"
+	"landmark-api/internal/contract"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":5051", "address to listen on")
+	flag.Parse()
+
+	spec, err := contract.Load()
+	if err != nil {
+		log.Fatalf("Error loading spec: %v", err)
+	}
+
+	log.Printf("Serving mock API on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, spec.MockHandler()))
+}
@@ -2,21 +2,25 @@ package controllers
 
 import (
 	"encoding/json"
+	"landmark-api/internal/health"
 	"net/http"
-	"os"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// externalCheckTimeout bounds each external service check.
+const externalCheckTimeout = 5 * time.Second
+
 type HealthCheckResponse struct {
 	Status           string            `json:"status"`
 	Database         string            `json:"database"`
 	ExternalServices map[string]string `json:"external_services"`
 }
 
-// HealthCheckHandler checks API health, database connection, and external services
-func HealthCheckHandler(db *gorm.DB) http.HandlerFunc {
+// HealthCheckHandler checks API health, database connection, and the
+// external services from the health check registry
+func HealthCheckHandler(db *gorm.DB, externalServices []health.Component) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		response := HealthCheckResponse{
 			ExternalServices: make(map[string]string),
@@ -40,10 +44,14 @@ func HealthCheckHandler(db *gorm.DB) http.HandlerFunc {
 
 		response.Status = "API is running"
 		response.Database = "Database connection is healthy"
-		apiKey := os.Getenv("OPEN_WEATHER_API_KEY")
-		weatherAPIURL := "http://api.openweathermap.org/data/2.5/weather?q=London&appid=" + apiKey
-		externalServiceStatus := checkExternalService(weatherAPIURL)
-		response.ExternalServices["Weather API"] = externalServiceStatus
+		failed := health.CheckNow(r.Context(), externalCheckTimeout, externalServices...)
+		for _, service := range externalServices {
+			if _, ok := failed[service.Name]; ok {
+				response.ExternalServices[service.Name] = "Unavailable"
+			} else {
+				response.ExternalServices[service.Name] = "Available"
+			}
+		}
 
 		// Respond with API, database, and external services status
 		respondWithJSON(w, http.StatusOK, response)
@@ -56,21 +64,3 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(payload)
 }
-
-// checkExternalService checks the status of an external service
-func checkExternalService(url string) string {
-	client := http.Client{
-		Timeout: 5 * time.Second, // Set a timeout for the request
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return "Unreachable"
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusOK {
-		return "Available"
-	}
-	return "Unavailable"
-}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"landmark-api/internal/health"
+	"log"
+	"net/http"
+	"time"
+)
+
+// LivenessHandler reports that the process is serving requests. It checks
+// no dependencies, so a failing dependency never gets the instance restarted.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		respondWithJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// ReadinessHandler checks the dependencies every request needs and returns
+// 503 if any fails within timeout, so traffic is routed elsewhere until the
+// instance recovers.
+func ReadinessHandler(timeout time.Duration, components ...health.Component) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		failed := health.CheckNow(r.Context(), timeout, components...)
+
+		checks := make(map[string]string, len(components))
+		for _, component := range components {
+			checks[component.Name] = "ok"
+		}
+		for name, err := range failed {
+			log.Printf("Readiness check %s failed: %v", name, err)
+			checks[name] = "failed"
+		}
+
+		if len(failed) > 0 {
+			respondWithJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+				"status": "not ready",
+				"checks": checks,
+			})
+			return
+		}
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"status": "ready",
+			"checks": checks,
+		})
+	}
+}
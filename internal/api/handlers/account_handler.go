@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// AccountHandler serves GDPR requests: deleting an account and exporting
+// everything stored about it.
+type AccountHandler struct {
+	accountService services.AccountService
+}
+
+func NewAccountHandler(accountService services.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+type deleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// AccountDeletionResponse confirms an account deletion.
+type AccountDeletionResponse struct {
+	Message string `json:"message"`
+	// PurgeAt is when the account and its data are permanently removed.
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Delete the account of the logged in user after confirming their password. Tokens and API keys stop working immediately; the account, its keys, usage, request logs and Stripe customer are purged for good at purge_at. Accounts with a paid subscription must cancel it first.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param request body deleteAccountRequest true "Password of the account"
+// @Success 202 {object} AccountDeletionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/delete-account [post]
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req deleteAccountRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	purgeAt, err := h.accountService.DeleteAccount(r.Context(), user.ID, req.Password)
+	if err != nil {
+		if apperrors.CodeOf(err) == apperrors.CodeInternal {
+			log.Printf("Error deleting account of user %s: %v", user.ID, err)
+		}
+		respondWithAppError(w, err, "Failed to delete account")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, AccountDeletionResponse{
+		Message: "Account deleted",
+		PurgeAt: purgeAt,
+	})
+}
+
+// CreateExport godoc
+// @Summary Export account data
+// @Description Queue a zip archive of everything stored about the logged in user: profile, subscriptions, API keys, sessions, usage, usage reports, custom fields, notifications, onboarding emails sent, request credits bought, usage statements and request logs. The archive is built in the background; poll the export in the Location header and download it once completed. An export already queued is returned instead of a new one.
+// @Tags user
+// @Produce json
+// @Success 202 {object} models.AccountExport
+// @Header 202 {string} Location "URL of the export"
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/export [post]
+func (h *AccountHandler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	export, err := h.accountService.CreateExport(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error creating account export for user %s: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create export")
+		return
+	}
+
+	w.Header().Set("Location", "/user/api/v1/export/"+export.ID.String())
+	respondWithJSON(w, http.StatusAccepted, export)
+}
+
+// GetExport godoc
+// @Summary Get account export
+// @Description Report the status of one of the logged in user's account exports.
+// @Tags user
+// @Produce json
+// @Param id path string true "Export ID"
+// @Success 200 {object} models.AccountExport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/export/{id} [get]
+func (h *AccountHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	export, ok := h.findExport(w, r)
+	if !ok {
+		return
+	}
+	respondWithJSON(w, http.StatusOK, export)
+}
+
+// DownloadExport godoc
+// @Summary Download account export
+// @Description Download a completed account export as a zip archive.
+// @Tags user
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/export/{id}/download [get]
+func (h *AccountHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	export, ok := h.findExport(w, r)
+	if !ok {
+		return
+	}
+
+	if export.Status != models.ExportCompleted {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("%s: status is %s", services.ErrExportNotReady, export.Status))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "landmark-api-export-"+export.CreatedAt.Format(auditLogDateLayout)+".zip"))
+	w.Write(export.Content)
+}
+
+func (h *AccountHandler) findExport(w http.ResponseWriter, r *http.Request) (*models.AccountExport, bool) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return nil, false
+	}
+
+	export, err := h.accountService.GetExport(r.Context(), id, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountExportNotFound) {
+			respondWithError(w, http.StatusNotFound, "Export not found")
+			return nil, false
+		}
+		log.Printf("Error fetching account export %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch export")
+		return nil, false
+	}
+	return export, true
+}
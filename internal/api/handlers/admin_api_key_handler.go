@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type AdminAPIKeyHandler struct {
+	limitService services.APIKeyLimitService
+	auditService services.AuditLogService
+}
+
+func NewAdminAPIKeyHandler(limitService services.APIKeyLimitService, auditService services.AuditLogService) *AdminAPIKeyHandler {
+	return &AdminAPIKeyHandler{
+		limitService: limitService,
+		auditService: auditService,
+	}
+}
+
+// GetLimits returns the key's custom request limit, if any.
+func (h *AdminAPIKeyHandler) GetLimits(w http.ResponseWriter, r *http.Request) {
+	apiKeyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	limit, err := h.limitService.GetLimit(r.Context(), apiKeyID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+			return
+		}
+		log.Printf("Error getting limits for API key %s: %v", apiKeyID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to get API key limits")
+		return
+	}
+
+	if limit == nil {
+		respondWithJSON(w, http.StatusOK, map[string]interface{}{
+			"api_key_id": apiKeyID,
+			"override":   false,
+		})
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"api_key_id":    apiKeyID,
+		"override":      true,
+		"request_limit": limit.RequestLimit,
+		"reason":        limit.Reason,
+		"updated_at":    limit.UpdatedAt,
+	})
+}
+
+// SetLimits sets a custom request limit on the key that takes precedence over
+// its plan's default. A limit of -1 makes the key unlimited.
+func (h *AdminAPIKeyHandler) SetLimits(w http.ResponseWriter, r *http.Request) {
+	apiKeyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	var req struct {
+		RequestLimit *int   `json:"request_limit"`
+		Reason       string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if req.RequestLimit == nil {
+		respondWithError(w, http.StatusBadRequest, "request_limit is required")
+		return
+	}
+
+	previous, err := h.limitService.GetLimit(r.Context(), apiKeyID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+			return
+		}
+		log.Printf("Error getting limits for API key %s: %v", apiKeyID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to set API key limits")
+		return
+	}
+
+	limit, err := h.limitService.SetLimit(r.Context(), apiKeyID, *req.RequestLimit, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRequestLimit):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrNotFound):
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+		default:
+			log.Printf("Error setting limits for API key %s: %v", apiKeyID, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to set API key limits")
+		}
+		return
+	}
+
+	details := fmt.Sprintf("Set request limit to %d", limit.RequestLimit)
+	if req.Reason != "" {
+		details += ": " + req.Reason
+	}
+	entry := services.AuditEntry{
+		Action:     "UPDATE",
+		EntityType: "API_KEY_LIMIT",
+		EntityID:   apiKeyID.String(),
+		Details:    details,
+	}
+	if previous != nil {
+		entry.Before = previous
+	}
+	if err := h.auditService.CreateAuditLog(r.Context(), entry); err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, limit)
+}
+
+// ClearLimits removes the key's custom limit so its plan default applies again.
+func (h *AdminAPIKeyHandler) ClearLimits(w http.ResponseWriter, r *http.Request) {
+	apiKeyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := h.limitService.ClearLimit(r.Context(), apiKeyID); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyLimitNotFound) {
+			respondWithError(w, http.StatusNotFound, "API key has no custom limit")
+			return
+		}
+		log.Printf("Error clearing limits for API key %s: %v", apiKeyID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to clear API key limits")
+		return
+	}
+
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "DELETE",
+		EntityType: "API_KEY_LIMIT",
+		EntityID:   apiKeyID.String(),
+		Details:    "Cleared custom request limit",
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Custom limit removed"})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type AdminSubscriptionHandler struct {
+	overrideService services.SubscriptionOverrideService
+	auditService    services.AuditLogService
+}
+
+func NewAdminSubscriptionHandler(overrideService services.SubscriptionOverrideService, auditService services.AuditLogService) *AdminSubscriptionHandler {
+	return &AdminSubscriptionHandler{
+		overrideService: overrideService,
+		auditService:    auditService,
+	}
+}
+
+// GrantSubscription comps a plan to a user until the given date without Stripe.
+func (h *AdminSubscriptionHandler) GrantSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req struct {
+		PlanType models.SubscriptionPlan `json:"plan_type"`
+		EndDate  time.Time               `json:"end_date"`
+		Reason   string                  `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	req.PlanType = models.SubscriptionPlan(strings.ToUpper(string(req.PlanType)))
+
+	subscription, err := h.overrideService.GrantComp(r.Context(), userID, req.PlanType, req.EndDate)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCompPlan), errors.Is(err, services.ErrInvalidCompEnd):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrNotFound):
+			respondWithCode(w, apperrors.CodeUserNotFound, "User not found")
+		default:
+			log.Printf("Error granting comp subscription to user %s: %v", userID, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to grant subscription")
+		}
+		return
+	}
+
+	details := fmt.Sprintf("Granted %s until %s", req.PlanType, req.EndDate.Format(time.RFC3339))
+	if req.Reason != "" {
+		details += ": " + req.Reason
+	}
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "GRANT",
+		EntityType: "SUBSCRIPTION",
+		EntityID:   userID.String(),
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, subscription)
+}
+
+// RevokeSubscription ends a user's comp early. Their regular subscription,
+// if any, applies again.
+func (h *AdminSubscriptionHandler) RevokeSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.overrideService.RevokeComp(r.Context(), userID); err != nil {
+		if errors.Is(err, services.ErrNoActiveComp) {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("Error revoking comp subscription for user %s: %v", userID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke subscription")
+		return
+	}
+
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "REVOKE",
+		EntityType: "SUBSCRIPTION",
+		EntityID:   userID.String(),
+		Details:    "Revoked comp subscription",
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Comp subscription revoked"})
+}
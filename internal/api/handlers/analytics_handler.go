@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"time"
+)
+
+const defaultAnalyticsWindow = "1h"
+
+type AnalyticsHandler struct {
+	analyticsService services.AnalyticsService
+	uptimeService    *UptimeService
+}
+
+func NewAnalyticsHandler(analyticsService services.AnalyticsService, uptimeService *UptimeService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+		uptimeService:    uptimeService,
+	}
+}
+
+// GetAnalytics returns request volume, error rates, latency, top endpoints,
+// top consumers and cache hit rate over ?window= (15m, 1h, 6h or 12h),
+// alongside this instance's uptime.
+func (h *AnalyticsHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = defaultAnalyticsWindow
+	}
+
+	analytics, err := h.analyticsService.GetAPIAnalytics(r.Context(), window, time.Now())
+	if errors.Is(err, services.ErrInvalidAnalyticsWindow) {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching API analytics: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching API analytics")
+		return
+	}
+
+	uptime := h.uptimeService.GetUptimeData()
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"analytics": analytics,
+		"uptime": map[string]interface{}{
+			"percentage":   uptime.Uptime,
+			"total_uptime": uptime.TotalUptime.String(),
+			"anomalies":    h.uptimeService.GetAnomalies(),
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"time"
+)
+
+// APIKeyRotationHandler replaces a user's API key in one call.
+type APIKeyRotationHandler struct {
+	apiKeyService services.APIKeyService
+	grace         time.Duration
+}
+
+func NewAPIKeyRotationHandler(apiKeyService services.APIKeyService, grace time.Duration) *APIKeyRotationHandler {
+	return &APIKeyRotationHandler{
+		apiKeyService: apiKeyService,
+		grace:         grace,
+	}
+}
+
+type rotateAPIKeyRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// RotateAPIKey issues a new API key. The old key keeps working until
+// previous_key_expires_at so clients can switch over without downtime.
+func (h *APIKeyRotationHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// The body is optional; without it the new key does not expire
+	var req rotateAPIKeyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	apiKey, err := h.apiKeyService.RotateAPIKey(r.Context(), user.ID, req.ExpiresAt, h.grace)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidKeyExpiry):
+			respondWithCode(w, apperrors.CodeValidationFailed, err.Error())
+		case errors.Is(err, apperrors.ErrNotFound):
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+		default:
+			log.Printf("Error rotating API key for user %s: %v", user.ID, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to rotate API key")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"api_key":                 apiKey.Key,
+		"expires_at":              apiKey.ExpiresAt,
+		"previous_key_expires_at": apiKey.PreviousKeyExpiresAt,
+	})
+}
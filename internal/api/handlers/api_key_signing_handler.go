@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+)
+
+// APIKeySigningHandler lets Enterprise users require HMAC-signed requests
+// for their API key.
+type APIKeySigningHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeySigningHandler(apiKeyService services.APIKeyService) *APIKeySigningHandler {
+	return &APIKeySigningHandler{apiKeyService: apiKeyService}
+}
+
+// EnableSigning issues a new signing secret and requires signed requests
+// from then on. The secret is only ever returned here.
+func (h *APIKeySigningHandler) EnableSigning(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	secret, err := h.apiKeyService.EnableSigning(r.Context(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSigningRequiresEnterprise):
+			respondWithCode(w, apperrors.CodeSubscriptionRequired, err.Error())
+		case errors.Is(err, apperrors.ErrNotFound):
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+		default:
+			log.Printf("Error enabling request signing for user %s: %v", user.ID, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to enable request signing")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{
+		"signing_secret":   secret,
+		"signature_header": services.SignatureHeader,
+		"timestamp_header": services.SignatureTimestampHeader,
+	})
+}
+
+// DisableSigning lets the API key be used without signatures again.
+func (h *APIKeySigningHandler) DisableSigning(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.apiKeyService.DisableSigning(r.Context(), user.ID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			respondWithCode(w, apperrors.CodeAPIKeyNotFound, "API key not found")
+			return
+		}
+		log.Printf("Error disabling request signing for user %s: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable request signing")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
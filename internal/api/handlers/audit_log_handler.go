@@ -1,11 +1,24 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+const auditLogDateLayout = "2006-01-02"
+
+var auditLogCSVHeader = []string{"id", "timestamp", "admin_id", "ip_address", "action", "entity_type", "entity_id", "details", "changes"}
+
 type AuditLogHandler struct {
 	auditLogService services.AuditLogService
 }
@@ -16,9 +29,23 @@ func NewAuditLogHandler(auditLogService services.AuditLogService) *AuditLogHandl
 	}
 }
 
+// ListAuditLogs lists audit logs newest first. It accepts the filters
+// admin_id, action, entity_type, from, to and q, and exports every match
+// as CSV with format=csv.
 func (h *AuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	filter, err := parseAuditLogFilter(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		h.exportCSV(w, r, filter)
+		return
+	}
+
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	if page < 1 {
 		page = 1
@@ -28,7 +55,7 @@ func (h *AuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request)
 		pageSize = 20
 	}
 
-	logs, total, err := h.auditLogService.GetAuditLogs(ctx, page, pageSize)
+	logs, total, err := h.auditLogService.GetAuditLogs(ctx, filter, page, pageSize)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Error fetching audit logs")
 		return
@@ -42,3 +69,85 @@ func (h *AuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request)
 
 	respondWithJSON(w, http.StatusOK, response)
 }
+
+func (h *AuditLogHandler) exportCSV(w http.ResponseWriter, r *http.Request, filter repository.AuditLogFilter) {
+	setCSVHeaders(w, fmt.Sprintf("audit-logs-%s.csv", time.Now().UTC().Format(auditLogDateLayout)))
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditLogCSVHeader); err != nil {
+		return
+	}
+
+	err := h.auditLogService.ExportAuditLogs(r.Context(), filter, func(entry models.AuditLog) error {
+		return writer.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			entry.AdminID.String(),
+			entry.IPAddress,
+			entry.Action,
+			entry.EntityType,
+			entry.EntityID,
+			entry.Details,
+			string(entry.Changes),
+		})
+	})
+	writer.Flush()
+
+	// The header is already sent, so a failure can only truncate the file
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		log.Printf("Error exporting audit logs: %v", err)
+	}
+}
+
+func parseAuditLogFilter(r *http.Request) (repository.AuditLogFilter, error) {
+	query := r.URL.Query()
+	filter := repository.AuditLogFilter{
+		Action:     strings.TrimSpace(query.Get("action")),
+		EntityType: strings.TrimSpace(query.Get("entity_type")),
+		Search:     strings.TrimSpace(query.Get("q")),
+	}
+
+	if adminID := query.Get("admin_id"); adminID != "" {
+		id, err := uuid.Parse(adminID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid admin_id")
+		}
+		filter.AdminID = id
+	}
+
+	var err error
+	if filter.From, err = parseAuditLogTime(query.Get("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: use RFC 3339 or YYYY-MM-DD")
+	}
+	if filter.To, err = parseAuditLogTime(query.Get("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: use RFC 3339 or YYYY-MM-DD")
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, fmt.Errorf("to must not be before from")
+	}
+
+	return filter, nil
+}
+
+// parseAuditLogTime accepts RFC 3339 timestamps or plain dates. A plain
+// date used as an upper bound covers the whole day.
+func parseAuditLogTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(auditLogDateLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
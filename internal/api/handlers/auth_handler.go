@@ -1,33 +1,43 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	apperrors "landmark-api/internal/errors"
 	"landmark-api/internal/services"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // AuthHandler handles authentication-related requests
 // @Description Handles user registration, login, and token verification
 type AuthHandler struct {
-	authService services.AuthService
+	authService   services.AuthService
+	loginThrottle services.LoginThrottleService
+	onboarding    services.OnboardingService
 }
 
 // NewAuthHandler creates a new AuthHandler
-// @Description Creates a new AuthHandler with the given AuthService
-// @Param authService services.AuthService
-// @Return *AuthHandler
-func NewAuthHandler(authService services.AuthService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService, loginThrottle services.LoginThrottleService, onboarding services.OnboardingService) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:   authService,
+		loginThrottle: loginThrottle,
+		onboarding:    onboarding,
 	}
 }
 
 // registrationRequest represents the structure of a registration request
 type registrationRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name     string `json:"name" validate:"required,max=255"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
 	Plan     string `json:"plan"`
 }
 
@@ -40,13 +50,13 @@ type registrationResponse struct {
 }
 
 type emailRegistrationRequest struct {
-	Email string `json:"email"`
+	Email string `json:"email" validate:"required,email,max=255"`
 }
 
 // loginRequest represents the structure of a login request
 type loginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 // authResponse represents the structure of an authentication response
@@ -61,15 +71,16 @@ type validateResponse struct {
 }
 
 type checkResponse struct {
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	APIKey      string `json:"apiKey"`
-	OnBoarding  bool   `json:"onboarding"`
-	PlanType    string `json:"planType"`
-	ApiCalls    uint   `json:"apiCalls"`
-	ApiLimit    uint   `json:"apiLimit"`
-	Landmarks   uint   `json:"landmarks"`
-	AccessToken string `json:"accessToken"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	APIKey           string `json:"apiKey"`
+	OnBoarding       bool   `json:"onboarding"`
+	OnboardingOptOut bool   `json:"onboardingOptOut"`
+	PlanType         string `json:"planType"`
+	ApiCalls         uint   `json:"apiCalls"`
+	ApiLimit         uint   `json:"apiLimit"`
+	Landmarks        uint   `json:"landmarks"`
+	AccessToken      string `json:"accessToken"`
 }
 
 // Register godoc
@@ -80,7 +91,7 @@ type checkResponse struct {
 // @Produce json
 // @Param registration body registrationRequest true "Registration details"
 // @Success 200 {object} authResponse
-// @Failure 400 {string} string "Invalid request body"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, with the invalid fields"
 // @Failure 500 {string} string "Internal server error"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -90,16 +101,16 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req registrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	user, err := h.authService.Register(r.Context(), req.Email, req.Password, req.Name)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondWithAppError(w, err, "Failed to register user")
 		return
 	}
+	h.startOnboarding(user.ID)
 
 	resp := registrationResponse{}
 	resp.User.ID = user.ID.String()
@@ -116,16 +127,16 @@ func (h *AuthHandler) RegisterWithEmail(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req emailRegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	user, err := h.authService.RegisterWithEmail(r.Context(), req.Email)
+	user, err := h.authService.RegisterWithEmail(r.Context(), req.Email, preferredLanguage(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondWithAppError(w, err, "Failed to register user")
 		return
 	}
+	h.startOnboarding(user.ID)
 
 	resp := registrationResponse{}
 	resp.User.ID = user.ID.String()
@@ -142,16 +153,16 @@ func (h *AuthHandler) RegisterSub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req registrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	user, err := h.authService.RegisterSub(r.Context(), req.Email, req.Password, req.Name)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondWithAppError(w, err, "Failed to register user")
 		return
 	}
+	h.startOnboarding(user.ID)
 
 	resp := registrationResponse{}
 	resp.User.ID = user.ID.String()
@@ -168,8 +179,10 @@ func (h *AuthHandler) RegisterSub(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param login body loginRequest true "Login details"
 // @Success 200 {object} authResponse
-// @Failure 400 {string} string "Invalid request body"
-// @Failure 401 {string} string "Unauthorized"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, with the invalid fields"
+// @Failure 401 {object} ErrorResponse "Invalid credentials"
+// @Failure 423 {object} ErrorResponse "Account temporarily locked"
+// @Failure 429 {object} ErrorResponse "Too many failed attempts; see Retry-After"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -178,16 +191,26 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req loginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	ctx := r.Context()
+	ip := loginIP(r)
+	if err := h.loginThrottle.Allow(ctx, req.Email, ip); err != nil {
+		respondWithLoginBlocked(w, err)
 		return
 	}
 
-	token, isAdmin, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	token, isAdmin, err := h.authService.Login(ctx, req.Email, req.Password, r.UserAgent(), ip)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			h.loginThrottle.RecordFailure(ctx, req.Email, ip)
+		}
+		respondWithAppError(w, err, "Failed to log in")
 		return
 	}
+	h.loginThrottle.RecordSuccess(ctx, req.Email)
 
 	resp := authResponse{
 		Token: token,
@@ -198,6 +221,36 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+func loginIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+func respondWithLoginBlocked(w http.ResponseWriter, err error) {
+	code := apperrors.CodeLoginThrottled
+	if errors.Is(err, services.ErrAccountLocked) {
+		code = apperrors.CodeAccountLocked
+	}
+	response := ErrorResponse{Error: err.Error(), Code: code}
+	var blocked *services.LoginBlockedError
+	if errors.As(err, &blocked) {
+		resetAt := time.Now().Add(blocked.RetryAfter).UTC().Truncate(time.Second)
+		response.ResetAt = &resetAt
+		response.RetryAfter = int(math.Ceil(blocked.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+	}
+	respondWithJSON(w, code.Status(), response)
+}
+
+// startOnboarding sends the first onboarding email in the background, so
+// registering does not wait for it.
+func (h *AuthHandler) startOnboarding(userID uuid.UUID) {
+	go h.onboarding.UserRegistered(context.Background(), userID)
+}
+
 func (h *AuthHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
 	resp := validateResponse{Validate: "Token valid"}
 	json.NewEncoder(w).Encode(resp)
@@ -229,6 +282,7 @@ func (h *AuthHandler) CheckUser(w http.ResponseWriter, r *http.Request) {
 	resp.PlanType = string(subscription.PlanType)
 	resp.AccessToken = ""
 	resp.OnBoarding = user.OnBoarding
+	resp.OnboardingOptOut = user.OnboardingOptOut
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -236,8 +290,11 @@ func (h *AuthHandler) CheckUser(w http.ResponseWriter, r *http.Request) {
 
 // updateUserRequest represents the structure of a user update request
 type updateUserRequest struct {
-	Name     string `json:"name,omitempty"`
-	Password string `json:"password,omitempty"`
+	Name     string `json:"name,omitempty" validate:"omitempty,max=255"`
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+	// OnboardingOptOut stops or restarts the onboarding emails; left out,
+	// it is unchanged.
+	OnboardingOptOut *bool `json:"onboarding_opt_out,omitempty"`
 }
 
 // updateUserResponse represents the structure of a user update response
@@ -248,16 +305,16 @@ type updateUserResponse struct {
 
 // UpdateUser godoc
 // @Summary Update user information
-// @Description Update user's name and/or password
+// @Description Update user's name, password and/or opt-out of onboarding emails
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param update body updateUserRequest true "User update details"
 // @Success 200 {object} updateUserResponse
-// @Failure 400 {string} string "Invalid request body"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, with the invalid fields"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Internal server error"
-// @Router /auth/update [put]
+// @Router /user/api/v1/update [put]
 func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -265,8 +322,7 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req updateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -276,7 +332,7 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.authService.UpdateUser(r.Context(), user.ID, req.Name, req.Password)
+	err := h.authService.UpdateUser(r.Context(), user.ID, req.Name, req.Password, req.OnboardingOptOut)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -286,30 +342,3 @@ func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
-
-// AuthMiddleware verifies the JWT token
-// @Description Middleware to verify JWT token and add user and subscription to context
-// @Param next http.HandlerFunc
-// @Return http.HandlerFunc
-func (h *AuthHandler) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		tokenString := r.Header.Get("Authorization")
-		if tokenString == "" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-
-		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
-			tokenString = tokenString[7:]
-		}
-
-		user, subscription, err := h.authService.VerifyToken(tokenString)
-		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		ctx := services.WithUserAndSubscriptionContext(r.Context(), user, subscription)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	}
-}
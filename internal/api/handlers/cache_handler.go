@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"time"
+)
+
+// CacheHandler reports on the cache and warms it.
+type CacheHandler struct {
+	// local is nil when the local cache is disabled.
+	local  *services.LocalCacheService
+	loader *services.CacheLoader
+	warmer *CacheWarmer
+}
+
+func NewCacheHandler(local *services.LocalCacheService, loader *services.CacheLoader, warmer *CacheWarmer) *CacheHandler {
+	return &CacheHandler{local: local, loader: loader, warmer: warmer}
+}
+
+// CacheStatsResponse reports how this instance's cache served requests
+// since startup.
+type CacheStatsResponse struct {
+	// Local is null when the local cache is disabled.
+	Local *services.LocalCacheStats `json:"local"`
+	// Namespaces are the cached responses' hits and misses, by namespace.
+	Namespaces map[string]services.CacheFetchStats `json:"namespaces"`
+}
+
+// CacheWarmResponse reports a cache warm run.
+type CacheWarmResponse struct {
+	// Warmed is how many responses were cached or refreshed; the others
+	// were cached already.
+	Warmed int `json:"warmed"`
+}
+
+// GetStats returns this instance's local cache hits, misses, evictions and
+// size, and the hits and misses of each namespace of cached responses.
+func (h *CacheHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	response := CacheStatsResponse{Namespaces: h.loader.Stats()}
+	if h.local != nil {
+		stats := h.local.Stats()
+		response.Local = &stats
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// WarmCache caches the most requested landmark queries now, as after a
+// cache flush, instead of waiting for the warm job's next run.
+func (h *CacheHandler) WarmCache(w http.ResponseWriter, r *http.Request) {
+	warmed, err := h.warmer.Warm(r.Context(), time.Now())
+	if err != nil {
+		log.Printf("Error warming cache: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to warm cache")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, CacheWarmResponse{Warmed: warmed})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"landmark-api/internal/apiversion"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// warmPlans are the plans responses are warmed for, as each plan has its
+// own cached responses.
+var warmPlans = []models.SubscriptionPlan{models.FreePlan, models.ProPlan, models.EnterprisePlan}
+
+// CacheWarmer caches the landmark responses most requested lately, by
+// replaying the popular requests in the request logs through the landmark
+// handlers for every plan.
+type CacheWarmer struct {
+	router  *mux.Router
+	routes  []string
+	logRepo repository.RequestLogRepository
+	config  *config.CacheConfig
+}
+
+func NewCacheWarmer(landmarks *LandmarkHandler, logRepo repository.RequestLogRepository, cfg *config.CacheConfig) *CacheWarmer {
+	// The landmark routes whose responses are cached, as the API serves
+	// them under every version
+	cached := []struct {
+		path    string
+		handler http.HandlerFunc
+	}{
+		{"/landmarks", landmarks.ListLandmarks},
+		{"/landmarks/clusters", landmarks.GetClusters},
+		{"/landmarks/{id}", landmarks.GetLandmark},
+		{"/landmarks/country/{country}", landmarks.ListLandmarksByCountry},
+		{"/landmarks/name/{name}", landmarks.ListLandmarksByName},
+		{"/landmarks/city/{city}", landmarks.ListLandmarksByCity},
+		{"/landmarks/category/{category}", landmarks.ListLandmarkByCategory},
+	}
+
+	warmer := &CacheWarmer{router: mux.NewRouter(), logRepo: logRepo, config: cfg}
+	for _, version := range apiversion.All {
+		prefix := "/api/" + string(version)
+		for _, route := range cached {
+			warmer.router.HandleFunc(prefix+route.path, func(w http.ResponseWriter, r *http.Request) {
+				route.handler(w, r.WithContext(apiversion.WithVersion(r.Context(), version)))
+			}).Methods("GET")
+			warmer.routes = append(warmer.routes, prefix+route.path)
+		}
+	}
+	return warmer
+}
+
+// Warm replays the requests most made to the cached routes over the warm
+// window and returns how many responses it cached or refreshed, the rest
+// being cached already.
+func (c *CacheWarmer) Warm(ctx context.Context, now time.Time) (int, error) {
+	endpoints, err := c.logRepo.PopularEndpoints(ctx, c.routes, now.Add(-c.config.WarmWindow), now, c.config.WarmTop)
+	if err != nil {
+		return 0, err
+	}
+
+	warmed := 0
+	for _, endpoint := range endpoints {
+		path, query, _ := strings.Cut(endpoint, "?")
+		// Scrubbed logs keep only the route template, and cursor pages and
+		// open_now lists are not cached
+		values, err := url.ParseQuery(query)
+		if strings.Contains(path, "{") || err != nil || values.Get("paginate") == "cursor" || values.Get("open_now") == "true" {
+			continue
+		}
+
+		for _, plan := range warmPlans {
+			r := (&http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Path: path, RawQuery: query},
+				Header: http.Header{},
+			}).WithContext(services.WithUserAndSubscriptionContext(ctx, &models.User{}, &models.Subscription{PlanType: plan}))
+			w := httptest.NewRecorder()
+			c.router.ServeHTTP(w, r)
+			if w.Code == http.StatusOK && w.Header().Get("X-Cache") != string(services.CacheHit) {
+				warmed++
+			}
+		}
+	}
+	return warmed, nil
+}
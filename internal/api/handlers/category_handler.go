@@ -1,18 +1,27 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
 	"log"
 	"net/http"
+
+	"github.com/gorilla/mux"
 )
 
 type CategoryHandler struct {
 	categoryService services.CategoryService
+	auditService    services.AuditLogService
 }
 
-func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandler {
+func NewCategoryHandler(categoryService services.CategoryService, auditService services.AuditLogService) *CategoryHandler {
 	return &CategoryHandler{
 		categoryService: categoryService,
+		auditService:    auditService,
 	}
 }
 
@@ -33,3 +42,168 @@ func (h *CategoryHandler) ListAdminCategories(w http.ResponseWriter, r *http.Req
 
 	respondWithJSON(w, http.StatusOK, response)
 }
+
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description Get all categories nested under their parent categories, with landmark counts
+// @Tags categories
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.categoryService.GetCategoryTree(r.Context())
+	if err != nil {
+		log.Printf("Error fetching category tree: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching categories")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"categories": tree})
+}
+
+// CreateCategory creates a category, optionally nested under a parent.
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name   string `json:"name"`
+		Parent string `json:"parent"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(r.Context(), req.Name, req.Parent)
+	if err != nil {
+		h.respondWithCategoryError(w, err, "create")
+		return
+	}
+
+	details := "Created category"
+	if req.Parent != "" {
+		details += " under " + req.Parent
+	}
+	h.audit(r, "CREATE", category.Name, details)
+	respondWithJSON(w, http.StatusCreated, category)
+}
+
+// RenameCategory renames a category on every landmark that uses it.
+func (h *CategoryHandler) RenameCategory(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	updated, err := h.categoryService.RenameCategory(r.Context(), name, req.Name)
+	if err != nil {
+		h.respondWithCategoryError(w, err, "rename")
+		return
+	}
+
+	h.audit(r, "RENAME", name, fmt.Sprintf("Renamed category to %s (%d landmarks)", req.Name, updated))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"name":               req.Name,
+		"landmarks_affected": updated,
+	})
+}
+
+// MergeCategory moves every landmark from the category in the path into the
+// target category and removes the former.
+func (h *CategoryHandler) MergeCategory(w http.ResponseWriter, r *http.Request) {
+	source := mux.Vars(r)["name"]
+
+	var req struct {
+		Target string `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	moved, err := h.categoryService.MergeCategories(r.Context(), source, req.Target)
+	if err != nil {
+		h.respondWithCategoryError(w, err, "merge")
+		return
+	}
+
+	h.audit(r, "MERGE", source, fmt.Sprintf("Merged category into %s (%d landmarks)", req.Target, moved))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"source":          source,
+		"target":          req.Target,
+		"landmarks_moved": moved,
+	})
+}
+
+// SetParentCategory nests a category under another one, or makes it a
+// top-level category when parent is empty.
+func (h *CategoryHandler) SetParentCategory(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var req struct {
+		Parent string `json:"parent"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.categoryService.SetParentCategory(r.Context(), name, req.Parent); err != nil {
+		h.respondWithCategoryError(w, err, "move")
+		return
+	}
+
+	details := "Made category top-level"
+	if req.Parent != "" {
+		details = "Moved category under " + req.Parent
+	}
+	h.audit(r, "MOVE", name, details)
+	respondWithJSON(w, http.StatusOK, map[string]string{"name": name, "parent": req.Parent})
+}
+
+// DeleteCategory deletes a category that no landmark uses. Its
+// subcategories move up to its parent.
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	if err := h.categoryService.DeleteCategory(r.Context(), name); err != nil {
+		h.respondWithCategoryError(w, err, "delete")
+		return
+	}
+
+	h.audit(r, "DELETE", name, "Deleted category")
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Category deleted successfully"})
+}
+
+func (h *CategoryHandler) respondWithCategoryError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCategoryName), errors.Is(err, services.ErrMergeIntoSelf), errors.Is(err, repository.ErrCategoryCycle):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		respondWithCode(w, apperrors.CodeCategoryNotFound, "Category not found")
+	case errors.Is(err, repository.ErrCategoryExists):
+		respondWithError(w, http.StatusConflict, "Category already exists; merge the categories instead")
+	case errors.Is(err, repository.ErrCategoryInUse):
+		respondWithError(w, http.StatusConflict, "Category still has landmarks; merge it into another category first")
+	default:
+		log.Printf("Error trying to %s category: %v", op, err)
+		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to %s category", op))
+	}
+}
+
+func (h *CategoryHandler) audit(r *http.Request, action, category, details string) {
+	err := h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     action,
+		EntityType: "CATEGORY",
+		EntityID:   category,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+}
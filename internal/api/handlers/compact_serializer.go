@@ -0,0 +1,101 @@
+package handlers
+
+import "net/http"
+
+// compactDictionaryFields are the landmark fields whose values repeat
+// across a list, so compact lists write them once in a dictionary and refer
+// to them by index.
+var compactDictionaryFields = []string{"country", "city", "category"}
+
+// compactSerializer is the plain representation made smaller for large
+// exports: null, empty string, empty list and empty object fields are left
+// out, so clients read a missing field as empty, and in lists the values of
+// compactDictionaryFields are replaced by their index in the list's
+// "dictionaries".
+type compactSerializer struct{}
+
+func (compactSerializer) contentType() string { return "application/json" }
+
+func (compactSerializer) landmark(r *http.Request, doc map[string]interface{}) interface{} {
+	compactDoc(doc)
+	return plainSerializer{}.landmark(r, doc)
+}
+
+func (compactSerializer) landmarkList(r *http.Request, list map[string]interface{}) interface{} {
+	items, _ := list["data"].([]interface{})
+	dictionaries := compactLandmarks(items)
+
+	result, _ := plainSerializer{}.landmarkList(r, list).(map[string]interface{})
+	result["dictionaries"] = dictionaries
+	return result
+}
+
+// compactLandmarks compacts the landmark docs in items in place and returns
+// the dictionaries their indexed fields now refer to.
+func compactLandmarks(items []interface{}) map[string][]string {
+	dictionaries := make(map[string][]string, len(compactDictionaryFields))
+	indexes := make(map[string]map[string]int, len(compactDictionaryFields))
+	for _, field := range compactDictionaryFields {
+		dictionaries[field] = []string{}
+		indexes[field] = map[string]int{}
+	}
+
+	for _, item := range items {
+		doc, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		compactDoc(doc)
+		for _, field := range compactDictionaryFields {
+			value, ok := doc[field].(string)
+			if !ok {
+				continue
+			}
+			index, ok := indexes[field][value]
+			if !ok {
+				index = len(dictionaries[field])
+				indexes[field][value] = index
+				dictionaries[field] = append(dictionaries[field], value)
+			}
+			doc[field] = index
+		}
+	}
+	return dictionaries
+}
+
+// compactDoc removes the empty fields of doc and of the objects nested in
+// it. Zero numbers and false are kept, as they carry meaning.
+func compactDoc(doc map[string]interface{}) {
+	for key, value := range doc {
+		if isEmptyValue(compactValue(value)) {
+			delete(doc, key)
+		}
+	}
+}
+
+// compactValue compacts the objects in value and returns it.
+func compactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		compactDoc(v)
+	case []interface{}:
+		for _, item := range v {
+			compactValue(item)
+		}
+	}
+	return value
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
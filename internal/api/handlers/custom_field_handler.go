@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CustomFieldHandler serves the private custom fields Enterprise accounts
+// attach to landmarks.
+type CustomFieldHandler struct {
+	customFieldService services.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFieldService services.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{customFieldService: customFieldService}
+}
+
+// ReplaceCustomFieldsRequest sets every custom field of a landmark.
+type ReplaceCustomFieldsRequest struct {
+	Fields map[string]string `json:"fields" validate:"required"`
+}
+
+// UpdateCustomFieldsRequest sets the given custom fields; null removes one.
+type UpdateCustomFieldsRequest struct {
+	Fields map[string]*string `json:"fields" validate:"required"`
+}
+
+// GetCustomFields godoc
+// @Summary Get a landmark's custom fields
+// @Description Get the private custom fields your account attached to a landmark. Enterprise only.
+// @Tags landmarks
+// @Produce json
+// @Param id path string true "Landmark ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/landmarks/{id}/custom-fields [get]
+func (h *CustomFieldHandler) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	subscription, id, ok := customFieldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	fields, err := h.customFieldService.Get(r.Context(), subscription, id)
+	if err != nil {
+		log.Printf("Error fetching custom fields of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to fetch custom fields")
+		return
+	}
+	respondWithCustomFields(w, id, fields)
+}
+
+// ReplaceCustomFields godoc
+// @Summary Replace a landmark's custom fields
+// @Description Set the private custom fields your account attached to a landmark, removing any not given. Enterprise only.
+// @Tags landmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "Landmark ID"
+// @Param request body ReplaceCustomFieldsRequest true "Custom fields"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/landmarks/{id}/custom-fields [put]
+func (h *CustomFieldHandler) ReplaceCustomFields(w http.ResponseWriter, r *http.Request) {
+	subscription, id, ok := customFieldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReplaceCustomFieldsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	fields, err := h.customFieldService.Replace(r.Context(), subscription, id, req.Fields)
+	if err != nil {
+		log.Printf("Error replacing custom fields of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to save custom fields")
+		return
+	}
+	respondWithCustomFields(w, id, fields)
+}
+
+// UpdateCustomFields godoc
+// @Summary Update some of a landmark's custom fields
+// @Description Set the given private custom fields on a landmark, keeping the others. A null value removes a field. Enterprise only.
+// @Tags landmarks
+// @Accept json
+// @Produce json
+// @Param id path string true "Landmark ID"
+// @Param request body UpdateCustomFieldsRequest true "Custom fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/landmarks/{id}/custom-fields [patch]
+func (h *CustomFieldHandler) UpdateCustomFields(w http.ResponseWriter, r *http.Request) {
+	subscription, id, ok := customFieldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateCustomFieldsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	fields, err := h.customFieldService.Update(r.Context(), subscription, id, req.Fields)
+	if err != nil {
+		log.Printf("Error updating custom fields of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to save custom fields")
+		return
+	}
+	respondWithCustomFields(w, id, fields)
+}
+
+// DeleteCustomFields godoc
+// @Summary Delete a landmark's custom fields
+// @Description Remove every private custom field your account attached to a landmark. Enterprise only.
+// @Tags landmarks
+// @Param id path string true "Landmark ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/landmarks/{id}/custom-fields [delete]
+func (h *CustomFieldHandler) DeleteCustomFields(w http.ResponseWriter, r *http.Request) {
+	subscription, id, ok := customFieldRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.customFieldService.Delete(r.Context(), subscription, id); err != nil {
+		log.Printf("Error deleting custom fields of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to delete custom fields")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// customFieldRequest returns the subscription and landmark ID of a custom
+// field request, or responds with the problem and returns false.
+func customFieldRequest(w http.ResponseWriter, r *http.Request) (*models.Subscription, uuid.UUID, bool) {
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Subscription not found")
+		return nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return nil, uuid.Nil, false
+	}
+	return subscription, id, true
+}
+
+func respondWithCustomFields(w http.ResponseWriter, landmarkID uuid.UUID, fields models.JSON) {
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"landmark_id": landmarkID,
+		"fields":      fields,
+	})
+}
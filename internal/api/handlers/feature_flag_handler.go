@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type FeatureFlagHandler struct {
+	flagService  services.FeatureFlagService
+	auditService services.AuditLogService
+}
+
+func NewFeatureFlagHandler(flagService services.FeatureFlagService, auditService services.AuditLogService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flagService:  flagService,
+		auditService: auditService,
+	}
+}
+
+func (h *FeatureFlagHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.flagService.ListFlags(r.Context())
+	if err != nil {
+		log.Printf("Error listing feature flags: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list feature flags")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"flags": flags,
+		"total": len(flags),
+	})
+}
+
+func (h *FeatureFlagHandler) CreateFlag(w http.ResponseWriter, r *http.Request) {
+	var flag models.FeatureFlag
+	if err := json.NewDecoder(r.Body).Decode(&flag); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.flagService.CreateFlag(r.Context(), &flag); err != nil {
+		h.respondWithFlagError(w, err)
+		return
+	}
+
+	h.audit(r, "CREATE", flag.Key, flagSummary("Created", &flag), nil)
+	respondWithJSON(w, http.StatusCreated, flag)
+}
+
+// UpdateFlag changes the fields present in the payload, e.g. only
+// rollout_percent when widening a rollout.
+func (h *FeatureFlagHandler) UpdateFlag(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	var req struct {
+		Description    *string            `json:"description"`
+		Enabled        *bool              `json:"enabled"`
+		RolloutPercent *int               `json:"rollout_percent"`
+		TargetPlans    *models.StringList `json:"target_plans"`
+		TargetUserIDs  *models.StringList `json:"target_user_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	flag, err := h.flagService.GetFlag(r.Context(), key)
+	if err != nil {
+		h.respondWithFlagError(w, err)
+		return
+	}
+	previous := *flag
+
+	if req.Description != nil {
+		flag.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		flag.Enabled = *req.Enabled
+	}
+	if req.RolloutPercent != nil {
+		flag.RolloutPercent = *req.RolloutPercent
+	}
+	if req.TargetPlans != nil {
+		flag.TargetPlans = *req.TargetPlans
+	}
+	if req.TargetUserIDs != nil {
+		flag.TargetUserIDs = *req.TargetUserIDs
+	}
+
+	if err := h.flagService.UpdateFlag(r.Context(), flag); err != nil {
+		h.respondWithFlagError(w, err)
+		return
+	}
+
+	h.audit(r, "UPDATE", key, flagSummary("Updated", flag), previous)
+	respondWithJSON(w, http.StatusOK, flag)
+}
+
+func (h *FeatureFlagHandler) DeleteFlag(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	if err := h.flagService.DeleteFlag(r.Context(), key); err != nil {
+		h.respondWithFlagError(w, err)
+		return
+	}
+
+	h.audit(r, "DELETE", key, "Deleted flag", nil)
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Feature flag deleted successfully"})
+}
+
+// GetUserFlags returns every flag's value for the authenticated user.
+func (h *FeatureFlagHandler) GetUserFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.flagService.EvaluateAll(r.Context())
+	if err != nil {
+		log.Printf("Error evaluating feature flags: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to evaluate feature flags")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"flags": flags})
+}
+
+func (h *FeatureFlagHandler) respondWithFlagError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidFlagKey), errors.Is(err, services.ErrInvalidFlagRollout),
+		errors.Is(err, services.ErrInvalidFlagPlan), errors.Is(err, services.ErrInvalidFlagUsers):
+		respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrFeatureFlagNotFound):
+		respondWithError(w, http.StatusNotFound, "Feature flag not found")
+	case errors.Is(err, repository.ErrFeatureFlagExists):
+		respondWithError(w, http.StatusConflict, "Feature flag already exists")
+	default:
+		log.Printf("Error managing feature flag: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to save feature flag")
+	}
+}
+
+func flagSummary(verb string, flag *models.FeatureFlag) string {
+	return fmt.Sprintf("%s flag (enabled=%t, rollout=%d%%, plans=%v, users=%d)",
+		verb, flag.Enabled, flag.RolloutPercent, []string(flag.TargetPlans), len(flag.TargetUserIDs))
+}
+
+func (h *FeatureFlagHandler) audit(r *http.Request, action, key, details string, before interface{}) {
+	err := h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     action,
+		EntityType: "FEATURE_FLAG",
+		EntityID:   key,
+		Details:    details,
+		Before:     before,
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+}
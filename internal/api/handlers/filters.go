@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/pagination"
+	"landmark-api/internal/validation"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// filter narrows a query by the value of its query parameter.
+type filter interface {
+	// check returns why value cannot be used, or nil.
+	check(value string) error
+	apply(query *gorm.DB, value string) *gorm.DB
+}
+
+// column matches a column on equality with the value.
+type column string
+
+func (c column) check(string) error {
+	return nil
+}
+
+func (c column) apply(query *gorm.DB, value string) *gorm.DB {
+	return query.Where(fmt.Sprintf("%s = ?", string(c)), value)
+}
+
+var errBoolFilter = errors.New("must be true or false")
+
+// checkBool returns errBoolFilter unless value is a boolean filter value.
+func checkBool(value string) error {
+	if value != "true" && value != "false" {
+		return errBoolFilter
+	}
+	return nil
+}
+
+// tagFilter keeps landmarks with a tag.
+type tagFilter struct{}
+
+func (tagFilter) check(string) error {
+	return nil
+}
+
+func (tagFilter) apply(query *gorm.DB, value string) *gorm.DB {
+	tag, _ := json.Marshal([]string{strings.ToLower(strings.TrimSpace(value))})
+	return query.Where("landmarks.tags @> CAST(? AS jsonb)", string(tag))
+}
+
+// filterSet is the filters an endpoint accepts, by query parameter. Only
+// columns registered here ever reach a WHERE clause.
+type filterSet map[string]filter
+
+// landmarkFilters are the filters of the landmark listing endpoints.
+var landmarkFilters = filterSet{
+	"name":       column("name"),
+	"city":       column("city"),
+	"country":    column("country"),
+	"category":   column("category"),
+	"tag":        tagFilter{},
+	"open_at":    openAtFilter{},
+	"open_now":   openNowFilter{},
+	"min_price":  priceFilter(">="),
+	"max_price":  priceFilter("<="),
+	"free_entry": freeEntryFilter{},
+}
+
+var (
+	countryFilters  = landmarkFilters.without("country")
+	cityFilters     = landmarkFilters.without("city")
+	categoryFilters = landmarkFilters.without("category")
+	nameFilters     = landmarkFilters.without("name")
+)
+
+// without returns a copy of s minus params, for endpoints whose path already
+// fixes those columns.
+func (s filterSet) without(params ...string) filterSet {
+	filters := make(filterSet, len(s))
+	for param, f := range s {
+		filters[param] = f
+	}
+	for _, param := range params {
+		delete(filters, param)
+	}
+	return filters
+}
+
+// names returns the accepted query parameters in order.
+func (s filterSet) names() []string {
+	names := make([]string, 0, len(s))
+	for param := range s {
+		names = append(names, param)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkFilters responds with a 400 and returns false if filters has any
+// parameter allowed does not accept, or a value its filter cannot use.
+func checkFilters(w http.ResponseWriter, filters map[string]string, allowed filterSet) bool {
+	var unknown []string
+	var invalid validation.Errors
+	for param, value := range filters {
+		f, ok := allowed[param]
+		if !ok {
+			unknown = append(unknown, param)
+			continue
+		}
+		if err := f.check(value); err != nil {
+			invalid = append(invalid, validation.FieldError{Field: param, Message: err.Error()})
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		respondWithJSON(w, apperrors.CodeUnknownFilter.Status(), ErrorResponse{
+			Error:   fmt.Sprintf("Unknown filter: %s", strings.Join(unknown, ", ")),
+			Code:    apperrors.CodeUnknownFilter,
+			Allowed: allowed.names(),
+		})
+		return false
+	}
+	if len(invalid) > 0 {
+		sort.Slice(invalid, func(i, j int) bool { return invalid[i].Field < invalid[j].Field })
+		respondWithFieldErrors(w, invalid)
+		return false
+	}
+	return true
+}
+
+// applyFilters adds the condition of each filter. Filters allowed does not
+// accept are skipped; callers reject them up front with checkFilters.
+func applyFilters(query *gorm.DB, filters map[string]string, allowed filterSet) *gorm.DB {
+	for param, value := range filters {
+		f, ok := allowed[param]
+		if !ok {
+			continue
+		}
+		query = f.apply(query, value)
+	}
+	return query
+}
+
+// filterKey identifies filters in cache keys.
+func filterKey(filters map[string]string) string {
+	return pagination.FilterHash(filters, "", "")
+}
+
+// cacheable reports whether responses filtered by filters may be cached.
+// open_now lists change as landmarks open and close, so they are always
+// built afresh.
+func cacheable(filters map[string]string) bool {
+	return filters["open_now"] != "true"
+}
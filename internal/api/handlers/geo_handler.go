@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxGeocodeQueryLength bounds the place names forwarded to the provider.
+const maxGeocodeQueryLength = 200
+
+// GeoHandler serves the geocoding helpers, so clients can turn place names
+// into coordinates for the proximity search and back.
+type GeoHandler struct {
+	geocodingService services.GeocodingService
+}
+
+func NewGeoHandler(geocodingService services.GeocodingService) *GeoHandler {
+	return &GeoHandler{geocodingService: geocodingService}
+}
+
+// Geocode godoc
+// @Summary Geocode a place name
+// @Description Resolve a place name or address to candidate coordinates. Counts against the plan's daily geocoding quota.
+// @Tags geo
+// @Produce json
+// @Param q query string true "Place name or address"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/geo/geocode [get]
+func (h *GeoHandler) Geocode(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Subscription not found")
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" || len(query) > maxGeocodeQueryLength {
+		respondWithError(w, http.StatusBadRequest, "q is required and must be at most 200 characters")
+		return
+	}
+
+	results, err := h.geocodingService.Geocode(r.Context(), subscription, query)
+	if err != nil {
+		respondWithAppError(w, err, "Error geocoding place")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"data": results})
+}
+
+// Reverse godoc
+// @Summary Reverse geocode a point
+// @Description Resolve coordinates to the address at that point. Counts against the plan's daily geocoding quota.
+// @Tags geo
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} services.GeocodingResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/geo/reverse [get]
+func (h *GeoHandler) Reverse(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Subscription not found")
+		return
+	}
+
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		respondWithError(w, http.StatusBadRequest, "Invalid coordinates")
+		return
+	}
+
+	result, err := h.geocodingService.Reverse(r.Context(), subscription, lat, lon)
+	if err != nil {
+		respondWithAppError(w, err, "Error reverse geocoding point")
+		return
+	}
+	if result == nil {
+		respondWithCode(w, apperrors.CodeNotFound, "No address found at this point")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, result)
+}
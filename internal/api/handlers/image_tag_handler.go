@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ImageTagHandler struct {
+	tagging      services.ImageTaggingService
+	auditService services.AuditLogService
+}
+
+func NewImageTagHandler(tagging services.ImageTaggingService, auditService services.AuditLogService) *ImageTagHandler {
+	return &ImageTagHandler{tagging: tagging, auditService: auditService}
+}
+
+// ReviewImage accepts or rejects a submitted image with {"rejected": bool},
+// overriding the tagging job. Rejected images are left out of the landmark
+// the submission is approved as.
+func (h *ImageTagHandler) ReviewImage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid image ID")
+		return
+	}
+
+	var req struct {
+		Rejected *bool `json:"rejected" validate:"required"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	tags, err := h.tagging.ReviewImage(r.Context(), id, *req.Rejected)
+	if err != nil {
+		log.Printf("Error reviewing submission image %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to review image")
+		return
+	}
+
+	action := "ACCEPT"
+	if tags.Rejected {
+		action = "REJECT"
+	}
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     action,
+		EntityType: "SUBMISSION_IMAGE",
+		EntityID:   id.String(),
+		Details:    fmt.Sprintf("Set submission image rejected to %t", tags.Rejected),
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	respondWithJSON(w, http.StatusOK, tags)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// BulkLandmarkRequest applies one change to many landmarks, or publishes
+// many submissions.
+type BulkLandmarkRequest struct {
+	Operation string      `json:"operation" validate:"required,oneof=set_category set_status add_tags remove_tags delete publish_submissions"`
+	IDs       []uuid.UUID `json:"ids" validate:"required,max=500"`
+	Category  string      `json:"category" validate:"max=50"`
+	Status    string      `json:"status" validate:"omitempty,oneof=draft published archived"`
+	Tags      []string    `json:"tags" validate:"max=20"`
+}
+
+// BulkConflictResponse is the error response of a bulk operation that was
+// not applied, with why each item failed.
+type BulkConflictResponse struct {
+	ErrorResponse
+	*services.BulkResult
+}
+
+// bulkAudit is how each applied bulk operation is recorded in the audit log.
+var bulkAudit = map[services.BulkOperation]struct {
+	action     string
+	entityType string
+}{
+	services.BulkSetCategory:        {"UPDATE", "LANDMARK"},
+	services.BulkSetStatus:          {"UPDATE", "LANDMARK"},
+	services.BulkAddTags:            {"UPDATE", "LANDMARK"},
+	services.BulkRemoveTags:         {"UPDATE", "LANDMARK"},
+	services.BulkDelete:             {"DELETE", "LANDMARK"},
+	services.BulkPublishSubmissions: {"APPROVE", "SUBMISSION_LANDMARK"},
+}
+
+// AdminBulkHandler godoc
+// @Summary Change many landmarks at once
+// @Description Set the category or status of, add or remove tags on, or delete many landmarks, or publish many pending submissions, in one transaction. If any item fails nothing is changed; the per-item results say why.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body BulkLandmarkRequest true "Operation and IDs"
+// @Success 200 {object} services.BulkResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} BulkConflictResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/landmarks/bulk [post]
+func (h *LandmarkHandler) AdminBulkHandler(w http.ResponseWriter, r *http.Request) {
+	var req BulkLandmarkRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	result, err := h.bulkService.Apply(r.Context(), services.BulkRequest{
+		Operation: services.BulkOperation(req.Operation),
+		IDs:       req.IDs,
+		Category:  req.Category,
+		Status:    models.LandmarkStatus(req.Status),
+		Tags:      req.Tags,
+	})
+	if err != nil {
+		log.Printf("Error applying bulk %s: %v", req.Operation, err)
+		respondWithAppError(w, err, "Failed to apply bulk operation")
+		return
+	}
+
+	if !result.Applied {
+		respondWithJSON(w, apperrors.CodeConflict.Status(), BulkConflictResponse{
+			ErrorResponse: ErrorResponse{
+				Error: "No changes were made because some items failed",
+				Code:  apperrors.CodeConflict,
+			},
+			BulkResult: result,
+		})
+		return
+	}
+
+	h.auditBulkChange(r.Context(), req, result)
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// auditBulkChange records each item of an applied bulk operation and drops
+// the cached responses of the landmarks it changed.
+func (h *LandmarkHandler) auditBulkChange(ctx context.Context, req BulkLandmarkRequest, result *services.BulkResult) {
+	audit := bulkAudit[result.Operation]
+	for _, item := range result.Results {
+		details := bulkAuditDetails(req, item)
+		err := h.auditService.CreateAuditLog(ctx, services.AuditEntry{
+			Action:     audit.action,
+			EntityType: audit.entityType,
+			EntityID:   item.ID.String(),
+			Details:    details,
+		})
+		if err != nil {
+			log.Printf("Failed to create audit log: %v", err)
+		}
+
+		if result.Operation != services.BulkPublishSubmissions {
+			h.forgetLandmark(ctx, item.ID)
+		}
+	}
+}
+
+func bulkAuditDetails(req BulkLandmarkRequest, item services.BulkItemResult) string {
+	switch services.BulkOperation(req.Operation) {
+	case services.BulkSetCategory:
+		return fmt.Sprintf("Bulk set category to %s", strings.TrimSpace(req.Category))
+	case services.BulkSetStatus:
+		return fmt.Sprintf("Bulk set landmark status to %s", req.Status)
+	case services.BulkAddTags:
+		return fmt.Sprintf("Bulk added tags %s", strings.Join(req.Tags, ", "))
+	case services.BulkRemoveTags:
+		return fmt.Sprintf("Bulk removed tags %s", strings.Join(req.Tags, ", "))
+	case services.BulkDelete:
+		return "Bulk deleted landmark"
+	case services.BulkPublishSubmissions:
+		return fmt.Sprintf("Bulk approved landmark submission as landmark %s", item.LandmarkID)
+	}
+	return "Bulk " + req.Operation
+}
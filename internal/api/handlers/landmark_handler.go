@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,15 +18,44 @@ import (
 	"github.com/gorilla/mux"
 	"gorm.io/gorm"
 
+	"landmark-api/internal/apiversion"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/geo"
 	"landmark-api/internal/models"
+	"landmark-api/internal/pagination"
+	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
 )
 
+const (
+	maxCursorPageSize = 100
+	maxSearchPageSize = 100
+)
+
 type LandmarkHandler struct {
 	landmarkService services.LandmarkService
 	auditService    services.AuditLogService
 	cacheService    services.CacheService
+	cacheLoader     *services.CacheLoader
+	priorityService services.ReviewPriorityService
+	planSerializer  services.PlanSerializer
+	customFields    services.CustomFieldService
+	bulkService     services.LandmarkBulkService
+	notifications   services.NotificationService
+	cursors         *pagination.Signer
 	db              *gorm.DB
+	// readDB serves public listings and may be a read replica
+	readDB            *gorm.DB
+	countCacheTTL     time.Duration
+	estimateThreshold int64
+}
+
+// landmarkTotal is the meta.total of a landmark list. Estimated totals come
+// from the planner's statistics rather than a count.
+type landmarkTotal struct {
+	Count     int64
+	Estimated bool
 }
 
 type QueryParams struct {
@@ -33,19 +65,68 @@ type QueryParams struct {
 	SortOrder string
 	Fields    []string
 	Filters   map[string]string
+	// Cursor is the signed token of the previous page in cursor pagination.
+	Cursor string
+	// UseCursor selects cursor pagination, via ?paginate=cursor or a cursor.
+	UseCursor bool
+	// Format is the ?format= serialization; empty is plain JSON.
+	Format string
+	// Version is the API version the request was made to.
+	Version apiversion.Version
 }
 
-func NewLandmarkHandler(landmarkService services.LandmarkService, as services.AuditLogService, cs services.CacheService, db *gorm.DB) *LandmarkHandler {
+func NewLandmarkHandler(landmarkService services.LandmarkService, as services.AuditLogService, cs services.CacheService, cacheLoader *services.CacheLoader, ps services.ReviewPriorityService, planSerializer services.PlanSerializer, customFields services.CustomFieldService, bulkService services.LandmarkBulkService, notifications services.NotificationService, cursors *pagination.Signer, paginationConfig *config.PaginationConfig, db, readDB *gorm.DB) *LandmarkHandler {
 	return &LandmarkHandler{
 		landmarkService: landmarkService,
 		cacheService:    cs,
+		cacheLoader:     cacheLoader,
 		auditService:    as,
+		priorityService: ps,
+		planSerializer:  planSerializer,
+		customFields:    customFields,
+		bulkService:     bulkService,
+		notifications:   notifications,
+		cursors:         cursors,
 		db:              db,
+		readDB:          readDB,
+
+		countCacheTTL:     paginationConfig.CountCacheTTL,
+		estimateThreshold: paginationConfig.EstimateCountThreshold,
 	}
 }
 
-func (h *LandmarkHandler) getCacheKey(params ...string) string {
-	return fmt.Sprintf("landmark:%s", strings.Join(params, ":"))
+// respondWithCachedList serves a landmark list page from the cache, for as
+// long as the plan caches responses, building it with build on a miss. Its
+// key is made of the list's scope, the page, its sort and filters, the plan
+// and the shape of the response. Lists whose filters can't be cached are
+// always built.
+func (h *LandmarkHandler) respondWithCachedList(w http.ResponseWriter, r *http.Request, params QueryParams, subscription *models.Subscription, namespace string, scope []string, build func(ctx context.Context) (interface{}, error)) {
+	if !cacheable(params.Filters) {
+		response, err := build(r.Context())
+		if err != nil {
+			log.Printf("Error fetching landmarks: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
+			return
+		}
+		h.respondWithLandmarkList(w, r, params, response)
+		return
+	}
+
+	parts := append(scope,
+		fmt.Sprintf("limit:%d", params.Limit),
+		fmt.Sprintf("offset:%d", params.Offset),
+		fmt.Sprintf("sort:%s:%s", params.SortBy, params.SortOrder),
+		"filters:"+filterKey(params.Filters),
+		string(subscription.PlanType))
+	parts = append(parts, params.shapeKey()...)
+	response, result, err := services.CachedFetch(r.Context(), h.cacheLoader, namespace, parts, h.cacheLoader.TTL(subscription.PlanType), build)
+	if err != nil {
+		log.Printf("Error fetching landmarks: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
+		return
+	}
+	w.Header().Set("X-Cache", string(result))
+	h.respondWithLandmarkList(w, r, params, response)
 }
 
 // GetLandmark godoc
@@ -55,11 +136,12 @@ func (h *LandmarkHandler) getCacheKey(params ...string) string {
 // @Accept json
 // @Produce json
 // @Param id path string true "Landmark ID"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Success 200 {object} services.LandmarkView
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/{id} [get]
 func (h *LandmarkHandler) GetLandmark(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -71,6 +153,10 @@ func (h *LandmarkHandler) GetLandmark(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
 		return
 	}
+	queryParams := parseQueryParams(r)
+	if !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok {
@@ -78,28 +164,23 @@ func (h *LandmarkHandler) GetLandmark(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Try to get from cache
-	cacheKey := h.getCacheKey("id", idStr, string(subscription.PlanType))
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
+	parts := append([]string{idStr, string(subscription.PlanType)}, queryParams.shapeKey()...)
+	response, result, err := services.CachedFetch(ctx, h.cacheLoader, "landmark:id", parts, h.cacheLoader.TTL(subscription.PlanType), func(ctx context.Context) (interface{}, error) {
+		var landmark models.Landmark
+		if err := h.db.Scopes(models.PublishedLandmarks).Preload("Images").First(&landmark, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, apperrors.New(apperrors.CodeLandmarkNotFound, "Landmark not found")
+			}
+			return nil, apperrors.Wrap(err, "failed to get landmark")
 		}
-	}
-
-	landmark, subscription, err := h.getLandmarkAndSubscription(ctx, id, w)
+		return h.prepareResponse(ctx, &landmark, subscription, queryParams), nil
+	})
 	if err != nil {
+		respondWithAppError(w, err, "Error fetching landmark")
 		return
 	}
-
-	response := h.prepareResponse(ctx, landmark, subscription, parseQueryParams(r))
-
-	// Cache the response
-	h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute)
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+	w.Header().Set("X-Cache", string(result))
+	h.respondWithLandmark(w, r, queryParams, response)
 }
 
 // ListLandmarks godoc
@@ -112,13 +193,31 @@ func (h *LandmarkHandler) GetLandmark(w http.ResponseWriter, r *http.Request) {
 // @Param offset query int false "Number of items to skip"
 // @Param sort query string false "Sort field and order (e.g., '-name' for descending)"
 // @Param fields query string false "Comma-separated list of fields to include"
-// @Success 200 {object} map[string]interface{}
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Param name query string false "Only landmarks with this exact name"
+// @Param city query string false "Only landmarks in this city"
+// @Param country query string false "Only landmarks in this country"
+// @Param category query string false "Only landmarks in this category"
+// @Param tag query string false "Only landmarks with this tag"
+// @Param open_at query string false "Only landmarks open at this RFC 3339 time, by their opening hours"
+// @Param open_now query bool false "Only landmarks open now, by their opening hours"
+// @Param min_price query number false "Only landmarks whose entry price is at least this, in their local currency"
+// @Param max_price query number false "Only landmarks whose entry price is at most this, in their local currency"
+// @Param free_entry query bool false "Only landmarks with free entry, or when false, those that charge"
+// @Param paginate query string false "Set to 'cursor' to page with meta.next_cursor instead of offset"
+// @Param cursor query string false "meta.next_cursor from the previous page; keep the same filters and sort"
+// @Success 200 {object} LandmarkList
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks [get]
 func (h *LandmarkHandler) ListLandmarks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	queryParams := parseQueryParams(r)
+	if !checkFilters(w, queryParams.Filters, landmarkFilters) || !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok {
@@ -126,40 +225,23 @@ func (h *LandmarkHandler) ListLandmarks(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Generate cache key based on query parameters
-	cacheKey := h.getCacheKey("list",
-		fmt.Sprintf("limit:%d", queryParams.Limit),
-		fmt.Sprintf("offset:%d", queryParams.Offset),
-		fmt.Sprintf("sort:%s:%s", queryParams.SortBy, queryParams.SortOrder),
-		string(subscription.PlanType))
-
-	// Try to get from cache
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
-		}
-	}
-
-	query := h.db.Model(&models.Landmark{}).Preload("Images")
-	query = applyFilters(query, queryParams.Filters)
-	query = applySorting(query, queryParams.SortBy, queryParams.SortOrder)
-
-	var landmarks []models.Landmark
-	if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
-		fmt.Print(err)
-		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
+	if queryParams.UseCursor {
+		h.listLandmarksByCursor(w, r, queryParams, subscription)
 		return
 	}
 
-	response := h.processLandmarkList(ctx, landmarks, subscription, queryParams)
-
-	// Cache the response
-	h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute)
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+	// Generate cache key based on query parameters
+	h.respondWithCachedList(w, r, queryParams, subscription, "landmark:list", nil, func(ctx context.Context) (interface{}, error) {
+		query := applyFilters(h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks), queryParams.Filters, landmarkFilters)
+		total := h.countLandmarks(ctx, query, "all", queryParams.Filters)
+		query = applySorting(query.Preload("Images"), queryParams.SortBy, queryParams.SortOrder)
+
+		var landmarks []models.Landmark
+		if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
+			return nil, err
+		}
+		return h.processLandmarkList(ctx, landmarks, subscription, queryParams, total), nil
+	})
 }
 
 func (h *LandmarkHandler) ListAdminLandmarks(w http.ResponseWriter, r *http.Request) {
@@ -178,9 +260,14 @@ func (h *LandmarkHandler) ListAdminLandmarks(w http.ResponseWriter, r *http.Requ
 
 	searchTerm := r.URL.Query().Get("search")
 	category := r.URL.Query().Get("category")
+	status := models.LandmarkStatus(r.URL.Query().Get("status"))
+	if status != "" && !status.Valid() {
+		respondWithError(w, http.StatusBadRequest, "status must be draft, published or archived")
+		return
+	}
 
-	// Fetch landmarks with pagination, search, and category filter
-	landmarks, total, err := h.landmarkService.GetLandmarksWithFilters(ctx, page, perPage, searchTerm, category)
+	// Fetch landmarks with pagination, search, category and status filter
+	landmarks, total, err := h.landmarkService.GetLandmarksWithFilters(ctx, page, perPage, searchTerm, category, status)
 	if err != nil {
 		log.Printf("Error fetching landmarks: %v", err)
 		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
@@ -188,7 +275,7 @@ func (h *LandmarkHandler) ListAdminLandmarks(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Prepare the response with full landmark information
-	var fullLandmarks []map[string]interface{}
+	views := make([]*services.LandmarkView, 0, len(landmarks))
 	for _, landmark := range landmarks {
 		// Fetch admin details for each landmark
 		details, err := h.landmarkService.GetLandmarkAdminDetails(ctx, landmark.ID)
@@ -198,41 +285,15 @@ func (h *LandmarkHandler) ListAdminLandmarks(w http.ResponseWriter, r *http.Requ
 			continue
 		}
 
-		fullLandmark := map[string]interface{}{
-			"id":          landmark.ID,
-			"name":        landmark.Name,
-			"description": landmark.Description,
-			"latitude":    landmark.Latitude,
-			"longitude":   landmark.Longitude,
-			"country":     landmark.Country,
-			"city":        landmark.City,
-			"category":    landmark.Category,
-			"image_url":   landmark.ImageUrl,
-			"images":      landmark.Images,
-			"created_at":  landmark.CreatedAt,
-			"updated_at":  landmark.UpdatedAt,
-		}
-
-		// Add admin details
-		if details != nil {
-			fullLandmark["opening_hours"] = details.OpeningHours
-			fullLandmark["ticket_prices"] = details.TicketPrices
-			fullLandmark["historical_significance"] = details.HistoricalSignificance
-			fullLandmark["visitor_tips"] = details.VisitorTips
-			fullLandmark["accessibility_info"] = details.AccessibilityInfo
-		}
-
-		fullLandmarks = append(fullLandmarks, fullLandmark)
+		views = append(views, h.planSerializer.AdminLandmarkSummary(&landmark, details))
 	}
 
-	response := map[string]interface{}{
-		"landmarks": fullLandmarks,
-		"total":     total,
-		"page":      page,
-		"per_page":  perPage,
-	}
-
-	respondWithJSON(w, http.StatusOK, response)
+	respondWithJSON(w, http.StatusOK, AdminLandmarkList{
+		Landmarks: views,
+		Total:     total,
+		Page:      page,
+		PerPage:   perPage,
+	})
 }
 
 // ListLandmarksByCountry godoc
@@ -246,15 +307,25 @@ func (h *LandmarkHandler) ListAdminLandmarks(w http.ResponseWriter, r *http.Requ
 // @Param offset query int false "Number of items to skip"
 // @Param sort query string false "Sort field and order (e.g., '-name' for descending)"
 // @Param fields query string false "Comma-separated list of fields to include"
-// @Success 200 {object} map[string]interface{}
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Param tag query string false "Only landmarks with this tag"
+// @Param open_at query string false "Only landmarks open at this RFC 3339 time, by their opening hours"
+// @Param open_now query bool false "Only landmarks open now, by their opening hours"
+// @Param min_price query number false "Only landmarks whose entry price is at least this, in their local currency"
+// @Param max_price query number false "Only landmarks whose entry price is at most this, in their local currency"
+// @Param free_entry query bool false "Only landmarks with free entry, or when false, those that charge"
+// @Success 200 {object} LandmarkList
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/country/{country} [get]
 func (h *LandmarkHandler) ListLandmarksByCountry(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 	country := vars["country"]
 	queryParams := parseQueryParams(r)
+	if !checkFilters(w, queryParams.Filters, countryFilters) || !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok {
@@ -263,43 +334,22 @@ func (h *LandmarkHandler) ListLandmarksByCountry(w http.ResponseWriter, r *http.
 	}
 
 	// Generate cache key
-	cacheKey := h.getCacheKey("country", country,
-		fmt.Sprintf("limit:%d", queryParams.Limit),
-		fmt.Sprintf("offset:%d", queryParams.Offset),
-		fmt.Sprintf("sort:%s:%s", queryParams.SortBy, queryParams.SortOrder),
-		string(subscription.PlanType))
-
-	// Try to get from cache
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
+	h.respondWithCachedList(w, r, queryParams, subscription, "landmark:country", []string{country}, func(ctx context.Context) (interface{}, error) {
+		query := applyFilters(h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).Where("country = ?", country), queryParams.Filters, countryFilters)
+		total := h.countLandmarks(ctx, query, "country:"+country, queryParams.Filters)
+		query = applySorting(query.Preload("Images"), queryParams.SortBy, queryParams.SortOrder)
+
+		var landmarks []models.Landmark
+		if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
+			return nil, err
 		}
-	}
-
-	query := h.db.Model(&models.Landmark{}).Where("country = ?", country).Preload("Images")
-	query = applyFilters(query, queryParams.Filters)
-	query = applySorting(query, queryParams.SortBy, queryParams.SortOrder)
-
-	var landmarks []models.Landmark
-	if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
-		return
-	}
-
-	response := h.processLandmarkList(ctx, landmarks, subscription, queryParams)
-
-	// Cache the response
-	h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute)
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+		return h.processLandmarkList(ctx, landmarks, subscription, queryParams, total), nil
+	})
 }
 
 // ListLandmarkByCategory godoc
 // @Summary List landmarks by category
-// @Description Get a list of landmarks for a specific category
+// @Description Get a list of landmarks for a specific category, including its subcategories
 // @Tags landmarks
 // @Accept json
 // @Produce json
@@ -308,15 +358,25 @@ func (h *LandmarkHandler) ListLandmarksByCountry(w http.ResponseWriter, r *http.
 // @Param offset query int false "Number of items to skip"
 // @Param sort query string false "Sort field and order (e.g., '-name' for descending)"
 // @Param fields query string false "Comma-separated list of fields to include"
-// @Success 200 {object} map[string]interface{}
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Param tag query string false "Only landmarks with this tag"
+// @Param open_at query string false "Only landmarks open at this RFC 3339 time, by their opening hours"
+// @Param open_now query bool false "Only landmarks open now, by their opening hours"
+// @Param min_price query number false "Only landmarks whose entry price is at least this, in their local currency"
+// @Param max_price query number false "Only landmarks whose entry price is at most this, in their local currency"
+// @Param free_entry query bool false "Only landmarks with free entry, or when false, those that charge"
+// @Success 200 {object} LandmarkList
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/category/{category} [get]
 func (h *LandmarkHandler) ListLandmarkByCategory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 	category := vars["category"]
 	queryParams := parseQueryParams(r)
+	if !checkFilters(w, queryParams.Filters, categoryFilters) || !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok {
@@ -325,64 +385,25 @@ func (h *LandmarkHandler) ListLandmarkByCategory(w http.ResponseWriter, r *http.
 	}
 
 	// Generate cache key based on category, query parameters, and subscription type
-	cacheKey := h.getCacheKey("category", category,
-		fmt.Sprintf("limit:%d", queryParams.Limit),
-		fmt.Sprintf("offset:%d", queryParams.Offset),
-		fmt.Sprintf("sort:%s:%s", queryParams.SortBy, queryParams.SortOrder),
-		string(subscription.PlanType))
-
-	// Try to get from cache first
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
+	h.respondWithCachedList(w, r, queryParams, subscription, "landmark:category", []string{category}, func(ctx context.Context) (interface{}, error) {
+		// Parent categories include the landmarks of all their subcategories
+		query := h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).
+			Where("category IN (?)", repository.CategoryWithDescendants(h.readDB, category))
+		query = applyFilters(query, queryParams.Filters, categoryFilters)
+		total := h.countLandmarks(ctx, query, "category:"+category, queryParams.Filters)
+		query = applySorting(query.Preload("Images"), queryParams.SortBy, queryParams.SortOrder)
+
+		var landmarks []models.Landmark
+		if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
+			return nil, err
 		}
-		// If unmarshal fails, log the error but continue to fetch from database
-		log.Printf("Error unmarshaling cached data: %v", err)
-	}
-
-	// Cache miss or error - fetch from database
-	query := h.db.Model(&models.Landmark{}).Where("category = ?", category).Preload("Images")
-	query = applyFilters(query, queryParams.Filters)
-	query = applySorting(query, queryParams.SortBy, queryParams.SortOrder)
-
-	var landmarks []models.Landmark
-	if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
-		return
-	}
 
-	// If no landmarks found, return empty result instead of error
-	if len(landmarks) == 0 {
-		emptyResponse := map[string]interface{}{
-			"data": []interface{}{},
-			"meta": map[string]interface{}{
-				"total":  0,
-				"limit":  queryParams.Limit,
-				"offset": queryParams.Offset,
-			},
+		// If no landmarks found, return empty result instead of error
+		if len(landmarks) == 0 {
+			return &LandmarkList{Data: []interface{}{}, Meta: newListMeta(total, queryParams)}, nil
 		}
-
-		// Cache the empty response too
-		h.cacheService.Set(ctx, cacheKey, emptyResponse, 15*time.Minute)
-		w.Header().Set("X-Cache", "MISS")
-		respondWithJSON(w, http.StatusOK, emptyResponse)
-		return
-	}
-
-	// Process the landmarks list based on subscription and query parameters
-	response := h.processLandmarkList(ctx, landmarks, subscription, queryParams)
-
-	// Cache the successful response
-	if err := h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute); err != nil {
-		// Log cache set error but continue with response
-		log.Printf("Error setting cache: %v", err)
-	}
-
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+		return h.processLandmarkList(ctx, landmarks, subscription, queryParams, total), nil
+	})
 }
 
 // ListLandmarksByCity godoc
@@ -396,15 +417,25 @@ func (h *LandmarkHandler) ListLandmarkByCategory(w http.ResponseWriter, r *http.
 // @Param offset query int false "Number of items to skip"
 // @Param sort query string false "Sort field and order (e.g., '-name' for descending)"
 // @Param fields query string false "Comma-separated list of fields to include"
-// @Success 200 {object} map[string]interface{}
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Param tag query string false "Only landmarks with this tag"
+// @Param open_at query string false "Only landmarks open at this RFC 3339 time, by their opening hours"
+// @Param open_now query bool false "Only landmarks open now, by their opening hours"
+// @Param min_price query number false "Only landmarks whose entry price is at least this, in their local currency"
+// @Param max_price query number false "Only landmarks whose entry price is at most this, in their local currency"
+// @Param free_entry query bool false "Only landmarks with free entry, or when false, those that charge"
+// @Success 200 {object} LandmarkList
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/city/{city} [get]
 func (h *LandmarkHandler) ListLandmarksByCity(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 	city := vars["city"]
 	queryParams := parseQueryParams(r)
+	if !checkFilters(w, queryParams.Filters, cityFilters) || !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok {
@@ -413,135 +444,182 @@ func (h *LandmarkHandler) ListLandmarksByCity(w http.ResponseWriter, r *http.Req
 	}
 
 	// Generate cache key based on city, query parameters, and subscription type
-	cacheKey := h.getCacheKey("city", city,
-		fmt.Sprintf("limit:%d", queryParams.Limit),
-		fmt.Sprintf("offset:%d", queryParams.Offset),
-		fmt.Sprintf("sort:%s:%s", queryParams.SortBy, queryParams.SortOrder),
-		string(subscription.PlanType))
-
-	// Try to get from cache first
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
+	h.respondWithCachedList(w, r, queryParams, subscription, "landmark:city", []string{city}, func(ctx context.Context) (interface{}, error) {
+		query := applyFilters(h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).Where("city ILIKE ?", city), queryParams.Filters, cityFilters)
+		total := h.countLandmarks(ctx, query, "city:"+strings.ToLower(city), queryParams.Filters)
+		query = applySorting(query.Preload("Images"), queryParams.SortBy, queryParams.SortOrder)
+
+		var landmarks []models.Landmark
+		if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
+			return nil, err
 		}
-		// If unmarshal fails, log the error but continue to fetch from database
-		log.Printf("Error unmarshaling cached data: %v", err)
-	}
 
-	// Cache miss or error - fetch from database
-	query := h.db.Model(&models.Landmark{}).Where("city ILIKE ?", city).Preload("Images")
-	query = applyFilters(query, queryParams.Filters)
-	query = applySorting(query, queryParams.SortBy, queryParams.SortOrder)
-
-	var landmarks []models.Landmark
-	if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
-		return
-	}
-
-	// If no landmarks found, return empty result instead of error
-	if len(landmarks) == 0 {
-		emptyResponse := map[string]interface{}{
-			"data": []interface{}{},
-			"meta": map[string]interface{}{
-				"total":  0,
-				"limit":  queryParams.Limit,
-				"offset": queryParams.Offset,
-			},
+		// If no landmarks found, return empty result instead of error
+		if len(landmarks) == 0 {
+			return &LandmarkList{Data: []interface{}{}, Meta: newListMeta(total, queryParams)}, nil
 		}
-
-		// Cache the empty response too
-		h.cacheService.Set(ctx, cacheKey, emptyResponse, 15*time.Minute)
-		w.Header().Set("X-Cache", "MISS")
-		respondWithJSON(w, http.StatusOK, emptyResponse)
-		return
-	}
-
-	// Process the landmarks list based on subscription and query parameters
-	response := h.processLandmarkList(ctx, landmarks, subscription, queryParams)
-
-	// Cache the successful response
-	if err := h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute); err != nil {
-		// Log cache set error but continue with response
-		log.Printf("Error setting cache: %v", err)
-	}
-
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+		return h.processLandmarkList(ctx, landmarks, subscription, queryParams, total), nil
+	})
 }
 
 // Define a struct for the search request
 type SearchRequest struct {
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Radius    float64 `json:"radius"` // in kilometers
+	Latitude  float64 `json:"latitude" validate:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" validate:"min=-180,max=180"`
+	Radius    float64 `json:"radius" validate:"gt=0,max=20000"` // in kilometers
+	// Limit defaults to and is capped at maxSearchPageSize
+	Limit  int `json:"limit" validate:"min=0"`
+	Offset int `json:"offset" validate:"min=0"`
 }
 
 // Function to calculate distance using Haversine formula
 func haversine(lat1, lon1, lat2, lon2 float64) float64 {
-	const R = 6371 // Radius of Earth in kilometers
-	dLat := (lat2 - lat1) * (math.Pi / 180)
-	dLon := (lon2 - lon1) * (math.Pi / 180)
-
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-		math.Cos(lat1*(math.Pi/180))*math.Cos(lat2*(math.Pi/180))*
-			math.Sin(dLon/2)*math.Sin(dLon/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-
-	return R * c
+	return geo.HaversineKm(lat1, lon1, lat2, lon2)
 }
 
 // SearchLandmarks godoc
 // @Summary Search landmarks by proximity
-// @Description Search for landmarks within a given radius of a point
+// @Description Search for landmarks within a given radius of a point, nearest first, with each result's distance_km
 // @Tags landmarks
 // @Accept json
 // @Produce json
 // @Param request body SearchRequest true "Search parameters"
-// @Success 200 {object} map[string]interface{}
-// @Failure 400 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Success 200 {object} LandmarkList
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/search [post]
 func (h *LandmarkHandler) SearchLandmarks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	subscription, ok := services.SubscriptionFromContext(ctx)
 	if !ok || subscription.PlanType != models.ProPlan {
-		respondWithError(w, http.StatusForbidden, "Forbidden: Pro subscription required")
+		respondWithCode(w, apperrors.CodeSubscriptionRequired, "Forbidden: Pro subscription required")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if !checkFormat(w, format) {
 		return
 	}
 	var req SearchRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
+	if req.Limit <= 0 || req.Limit > maxSearchPageSize {
+		req.Limit = maxSearchPageSize
+	}
+
+	// Narrow the candidates to the bounding box of the radius; the box only
+	// constrains longitude when it does not cross the antimeridian
+	minLat, maxLat, minLon, maxLon := geo.BoundingBox(req.Latitude, req.Longitude, req.Radius)
+	query := h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).Preload("Images").
+		Where("latitude BETWEEN ? AND ?", minLat, maxLat)
+	if minLon >= -180 && maxLon <= 180 {
+		query = query.Where("longitude BETWEEN ? AND ?", minLon, maxLon)
+	}
+
 	var landmarks []models.Landmark
-	if err := h.db.Model(&models.Landmark{}).Preload("Images").Find(&landmarks).Error; err != nil {
+	if err := query.Find(&landmarks).Error; err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
 		return
 	}
 
-	var results []models.Landmark
+	type searchResult struct {
+		landmark models.Landmark
+		distance float64
+	}
+	var results []searchResult
 	for _, landmark := range landmarks {
 		distance := haversine(req.Latitude, req.Longitude, landmark.Latitude, landmark.Longitude)
 		if distance <= req.Radius {
-			results = append(results, landmark)
+			results = append(results, searchResult{landmark: landmark, distance: distance})
 		}
 	}
-
-	response := h.processLandmarkList(ctx, results, subscription, QueryParams{
-		Limit:     len(results),        // Set limit to the number of results found
-		Offset:    0,                   // No offset for this search
-		SortBy:    "",                  // No specific sorting needed
-		SortOrder: "asc",               // Default order
-		Fields:    []string{},          // No field filtering specified
-		Filters:   map[string]string{}, // No filters
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].distance < results[j].distance
 	})
 
+	total := len(results)
+	if req.Offset < total {
+		results = results[req.Offset:]
+	} else {
+		results = nil
+	}
+	if len(results) > req.Limit {
+		results = results[:req.Limit]
+	}
+
+	page := make([]models.Landmark, len(results))
+	for i, result := range results {
+		page[i] = result.landmark
+	}
+
+	params := QueryParams{
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+		SortOrder: "asc",
+		Fields:    []string{},
+		Filters:   map[string]string{},
+		Format:    format,
+		Version:   apiversion.FromContext(ctx),
+	}
+	views := h.landmarkViews(ctx, page, subscription)
+	for i, view := range views {
+		distance := math.Round(results[i].distance*1000) / 1000
+		view.DistanceKM = &distance
+	}
+
+	response := newLandmarkList(views, params, newListMeta(landmarkTotal{Count: int64(total)}, params))
+	h.respondWithLandmarkList(w, r, params, response)
+}
+
+// GetClusters godoc
+// @Summary Cluster landmarks for a map
+// @Description Group the landmarks inside a bounding box into grid clusters sized for a map zoom level, each with its count and centroid
+// @Tags landmarks
+// @Produce json
+// @Param bbox query string true "Bounding box as minLon,minLat,maxLon,maxLat"
+// @Param zoom query int true "Map zoom level (0-20)"
+// @Success 200 {object} ClusterList
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/landmarks/clusters [get]
+func (h *LandmarkHandler) GetClusters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	bounds, err := geo.ParseBBox(query.Get("bbox"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	zoom, err := strconv.Atoi(query.Get("zoom"))
+	if err != nil || zoom < 0 || zoom > services.MaxClusterZoom {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("zoom must be an integer between 0 and %d", services.MaxClusterZoom))
+		return
+	}
+
+	response, result, err := services.CachedFetch(ctx, h.cacheLoader, "landmark:clusters", []string{bounds.String(), strconv.Itoa(zoom)}, h.cacheLoader.DefaultTTL(), func(ctx context.Context) (interface{}, error) {
+		clusters, err := h.landmarkService.Clusters(ctx, bounds, zoom)
+		if err != nil {
+			return nil, err
+		}
+		return ClusterList{
+			Data: clusters,
+			Meta: ClusterMeta{
+				BBox:     bounds.String(),
+				Zoom:     zoom,
+				CellSize: geo.ClusterCellSize(zoom),
+			},
+		}, nil
+	})
+	if err != nil {
+		log.Printf("Error clustering landmarks: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error clustering landmarks")
+		return
+	}
+	w.Header().Set("X-Cache", string(result))
 	respondWithJSON(w, http.StatusOK, response)
 }
 
@@ -556,15 +634,25 @@ func (h *LandmarkHandler) SearchLandmarks(w http.ResponseWriter, r *http.Request
 // @Param offset query int false "Number of items to skip"
 // @Param sort query string false "Sort field and order (e.g., '-name' for descending)"
 // @Param fields query string false "Comma-separated list of fields to include"
-// @Success 200 {object} map[string]interface{}
-// @Failure 403 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param format query string false "Response format: json (default), jsonapi, hal or compact"
+// @Param tag query string false "Only landmarks with this tag"
+// @Param open_at query string false "Only landmarks open at this RFC 3339 time, by their opening hours"
+// @Param open_now query bool false "Only landmarks open now, by their opening hours"
+// @Param min_price query number false "Only landmarks whose entry price is at least this, in their local currency"
+// @Param max_price query number false "Only landmarks whose entry price is at most this, in their local currency"
+// @Param free_entry query bool false "Only landmarks with free entry, or when false, those that charge"
+// @Success 200 {object} LandmarkList
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /api/v1/landmarks/name/{name} [get]
 func (h *LandmarkHandler) ListLandmarksByName(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 	name := vars["name"]
 	queryParams := parseQueryParams(r)
+	if !checkFilters(w, queryParams.Filters, nameFilters) || !checkFormat(w, queryParams.Format) {
+		return
+	}
 
 	// Get subscription from context
 	subscription, ok := services.SubscriptionFromContext(ctx)
@@ -573,53 +661,27 @@ func (h *LandmarkHandler) ListLandmarksByName(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	cacheKey := h.getCacheKey("name", name,
-		fmt.Sprintf("limit:%d", queryParams.Limit),
-		fmt.Sprintf("offset:%d", queryParams.Offset),
-		fmt.Sprintf("sort:%s:%s", queryParams.SortBy, queryParams.SortOrder),
-		string(subscription.PlanType))
+	h.respondWithCachedList(w, r, queryParams, subscription, "landmark:name", []string{name}, func(ctx context.Context) (interface{}, error) {
+		// Build the base query
+		query := h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).Where("name ILIKE ?", "%"+name+"%")
 
-	if cachedData, err := h.cacheService.Get(ctx, cacheKey); err == nil {
-		var response interface{}
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			w.Header().Set("X-Cache", "HIT")
-			respondWithJSON(w, http.StatusOK, response)
-			return
-		}
-	}
-
-	// Build the base query
-	query := h.db.Model(&models.Landmark{}).Where("name ILIKE ?", "%"+name+"%").Preload("Images")
-
-	// Apply additional filters and sorting
-	query = applyFilters(query, queryParams.Filters)
-	query = applySorting(query, queryParams.SortBy, queryParams.SortOrder)
-
-	// Execute the query
-	var landmarks []models.Landmark
-	if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
-		return
-	}
+		// Apply additional filters, count the matches and sort
+		query = applyFilters(query, queryParams.Filters, nameFilters)
+		total := h.countLandmarks(ctx, query, "name:"+strings.ToLower(name), queryParams.Filters)
+		query = applySorting(query.Preload("Images"), queryParams.SortBy, queryParams.SortOrder)
 
-	// If no landmarks found, return empty result instead of error
-	if len(landmarks) == 0 {
-		respondWithJSON(w, http.StatusOK, map[string]interface{}{
-			"data": []interface{}{},
-			"meta": map[string]interface{}{
-				"total":  0,
-				"limit":  queryParams.Limit,
-				"offset": queryParams.Offset,
-			},
-		})
-		return
-	}
+		// Execute the query
+		var landmarks []models.Landmark
+		if err := query.Offset(queryParams.Offset).Limit(queryParams.Limit).Find(&landmarks).Error; err != nil {
+			return nil, err
+		}
 
-	// Process the landmarks list based on subscription and query parameters
-	response := h.processLandmarkList(ctx, landmarks, subscription, queryParams)
-	h.cacheService.Set(ctx, cacheKey, response, 15*time.Minute)
-	w.Header().Set("X-Cache", "MISS")
-	respondWithJSON(w, http.StatusOK, response)
+		// If no landmarks found, return empty result instead of error
+		if len(landmarks) == 0 {
+			return &LandmarkList{Data: []interface{}{}, Meta: newListMeta(total, queryParams)}, nil
+		}
+		return h.processLandmarkList(ctx, landmarks, subscription, queryParams, total), nil
+	})
 }
 
 func (h *LandmarkHandler) CreateLandmark(w http.ResponseWriter, r *http.Request) {
@@ -627,12 +689,10 @@ func (h *LandmarkHandler) CreateLandmark(w http.ResponseWriter, r *http.Request)
 	var landmarkData struct {
 		Landmark       models.Landmark       `json:"landmark"`
 		LandmarkDetail models.LandmarkDetail `json:"landmark_detail"`
-		ImageURLs      []string              `json:"image_urls"`
+		ImageURLs      []string              `json:"image_urls" validate:"max=20,dive,url"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&landmarkData); err != nil {
-		log.Printf("Error decoding JSON: %v", err)
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &landmarkData) {
 		return
 	}
 
@@ -653,11 +713,12 @@ func (h *LandmarkHandler) CreateLandmark(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Create LandmarkImage entries
-	for _, url := range landmarkData.ImageURLs {
+	for i, url := range landmarkData.ImageURLs {
 		landmarkImage := models.LandmarkImage{
-			ID:         uuid.New(),
-			LandmarkID: landmarkData.Landmark.ID,
-			ImageURL:   url,
+			ID:           uuid.New(),
+			LandmarkID:   landmarkData.Landmark.ID,
+			ImageURL:     url,
+			DisplayOrder: i,
 		}
 		if err := tx.Create(&landmarkImage).Error; err != nil {
 			tx.Rollback()
@@ -688,14 +749,18 @@ func (h *LandmarkHandler) CreateLandmark(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	adminID := 0
-	err := h.auditService.CreateAuditLog(r.Context(), adminID, "CREATE", "LANDMARK", createdLandmark.ID.String(), "Created landmark")
+	err := h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "CREATE",
+		EntityType: "LANDMARK",
+		EntityID:   createdLandmark.ID.String(),
+		Details:    "Created landmark",
+	})
 	if err != nil {
 		log.Printf("Failed to create audit log: %v", err)
 	}
 
 	// Prepare the response
-	response := h.mergeLandmarkAndDetails(&createdLandmark, &landmarkData.LandmarkDetail)
+	response := h.planSerializer.AdminLandmark(r.Context(), &createdLandmark, &landmarkData.LandmarkDetail)
 
 	respondWithJSON(w, http.StatusCreated, response)
 }
@@ -719,6 +784,9 @@ func (h *LandmarkHandler) AdminEditHandler(w http.ResponseWriter, r *http.Reques
 			Country     string  `json:"country"`
 			City        string  `json:"city"`
 			Category    string  `json:"category"`
+			// DataConfidence is optional; editing a landmark always counts
+			// as verifying it.
+			DataConfidence *float64 `json:"data_confidence"`
 		} `json:"landmark"`
 		LandmarkDetail struct {
 			OpeningHours           string `json:"opening_hours"`
@@ -734,6 +802,46 @@ func (h *LandmarkHandler) AdminEditHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	landmarkUpdates := map[string]interface{}{
+		"name":             updateData.Landmark.Name,
+		"description":      updateData.Landmark.Description,
+		"latitude":         updateData.Landmark.Latitude,
+		"longitude":        updateData.Landmark.Longitude,
+		"country":          updateData.Landmark.Country,
+		"city":             updateData.Landmark.City,
+		"category":         updateData.Landmark.Category,
+		"last_verified_at": time.Now(),
+	}
+	if confidence := updateData.Landmark.DataConfidence; confidence != nil {
+		if !models.ValidDataConfidence(*confidence) {
+			respondWithError(w, http.StatusBadRequest, "data_confidence must be between 0 and 1")
+			return
+		}
+		landmarkUpdates["data_confidence"] = *confidence
+	}
+
+	// Keep the previous state for the audit log diff
+	var previous struct {
+		Landmark       models.Landmark       `json:"landmark"`
+		LandmarkDetail models.LandmarkDetail `json:"landmark_detail"`
+	}
+	if err := h.db.First(&previous.Landmark, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondWithCode(w, apperrors.CodeLandmarkNotFound, "Landmark not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch landmark")
+		return
+	}
+	if err := h.db.Where("landmark_id = ?", id).Limit(1).Find(&previous.LandmarkDetail).Error; err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch landmark details")
+		return
+	}
+	// Moved landmarks get their timezone looked up again
+	if previous.Landmark.Latitude != updateData.Landmark.Latitude || previous.Landmark.Longitude != updateData.Landmark.Longitude {
+		landmarkUpdates["timezone"] = ""
+	}
+
 	// Start a database transaction
 	tx := h.db.Begin()
 	if tx.Error != nil {
@@ -742,27 +850,25 @@ func (h *LandmarkHandler) AdminEditHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Update the Landmark
-	if err := tx.Model(&models.Landmark{}).Where("id = ?", id).Updates(map[string]interface{}{
-		"name":        updateData.Landmark.Name,
-		"description": updateData.Landmark.Description,
-		"latitude":    updateData.Landmark.Latitude,
-		"longitude":   updateData.Landmark.Longitude,
-		"country":     updateData.Landmark.Country,
-		"city":        updateData.Landmark.City,
-		"category":    updateData.Landmark.Category,
-	}).Error; err != nil {
+	if err := tx.Model(&models.Landmark{}).Where("id = ?", id).Updates(landmarkUpdates).Error; err != nil {
 		tx.Rollback()
 		respondWithError(w, http.StatusInternalServerError, "Failed to update landmark")
 		return
 	}
 
-	// Update the LandmarkDetail
+	// Update the LandmarkDetail. Map updates skip the model's hooks, so the
+	// entry price is normalized here.
+	var ticketPrices map[string]string
+	_ = json.Unmarshal([]byte(updateData.LandmarkDetail.TicketPrices), &ticketPrices)
+	entryPrice, entryCurrency := models.NormalizeTicketPrices(ticketPrices)
 	if err := tx.Model(&models.LandmarkDetail{}).Where("landmark_id = ?", id).Updates(map[string]interface{}{
 		"opening_hours":           updateData.LandmarkDetail.OpeningHours,
 		"ticket_prices":           updateData.LandmarkDetail.TicketPrices,
 		"historical_significance": updateData.LandmarkDetail.HistoricalSignificance,
 		"visitor_tips":            updateData.LandmarkDetail.VisitorTips,
 		"accessibility_info":      updateData.LandmarkDetail.AccessibilityInfo,
+		"entry_price":             entryPrice,
+		"entry_currency":          entryCurrency,
 	}).Error; err != nil {
 		tx.Rollback()
 		respondWithError(w, http.StatusInternalServerError, "Failed to update landmark details")
@@ -789,12 +895,307 @@ func (h *LandmarkHandler) AdminEditHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "UPDATE",
+		EntityType: "LANDMARK",
+		EntityID:   id.String(),
+		Details:    "Edited landmark",
+		Before:     previous,
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
 	// Prepare the response
-	response := h.mergeLandmarkAndDetails(&updatedLandmark, &updatedDetails)
+	response := h.planSerializer.AdminLandmark(r.Context(), &updatedLandmark, &updatedDetails)
 
 	respondWithJSON(w, http.StatusOK, response)
 }
 
+// AdminVerifyHandler marks a landmark's data as verified now, with an
+// optional confidence score.
+func (h *LandmarkHandler) AdminVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return
+	}
+
+	var req struct {
+		DataConfidence float64 `json:"data_confidence"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if err := h.landmarkService.VerifyLandmark(r.Context(), id, req.DataConfidence); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidInput):
+			respondWithError(w, http.StatusBadRequest, "data_confidence must be between 0 and 1")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			respondWithCode(w, apperrors.CodeLandmarkNotFound, "Landmark not found")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to verify landmark")
+		}
+		return
+	}
+
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "VERIFY",
+		EntityType: "LANDMARK",
+		EntityID:   id.String(),
+		Details:    fmt.Sprintf("Verified landmark with confidence %.2f", req.DataConfidence),
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	h.forgetLandmark(r.Context(), id)
+
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Landmark verified successfully"})
+}
+
+// ReorderImagesRequest lists every image of a landmark in gallery order.
+type ReorderImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required"`
+}
+
+// SetStatusRequest moves a landmark to another status.
+type SetStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=draft published archived"`
+}
+
+// AdminSetStatusHandler drafts, publishes or archives a landmark.
+func (h *LandmarkHandler) AdminSetStatusHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return
+	}
+
+	var req SetStatusRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	status := models.LandmarkStatus(req.Status)
+	if err := h.landmarkService.SetStatus(r.Context(), id, status); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			respondWithCode(w, apperrors.CodeLandmarkNotFound, "Landmark not found")
+		default:
+			log.Printf("Error setting status of landmark %s: %v", id, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to set landmark status")
+		}
+		return
+	}
+
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "UPDATE",
+		EntityType: "LANDMARK",
+		EntityID:   id.String(),
+		Details:    fmt.Sprintf("Set landmark status to %s", status),
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+	h.forgetLandmark(r.Context(), id)
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"id": id, "status": status})
+}
+
+// UpdateImageRequest sets the caption, alt text and attribution of an
+// image. Photos left without alt text get generated alt text.
+type UpdateImageRequest struct {
+	Caption string `json:"caption" validate:"max=1000"`
+	AltText string `json:"alt_text" validate:"max=1000"`
+	Credit  string `json:"credit" validate:"max=255"`
+}
+
+// AdminReorderImagesHandler sets the gallery order of a landmark's images.
+func (h *LandmarkHandler) AdminReorderImagesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return
+	}
+
+	var req ReorderImagesRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	images, err := h.landmarkService.ReorderImages(r.Context(), id, req.ImageIDs)
+	if err != nil {
+		log.Printf("Error reordering images of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to reorder images")
+		return
+	}
+
+	h.auditImageChange(r.Context(), id, "Reordered landmark images")
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"images": services.NewLandmarkImageViews(images)})
+}
+
+// AdminSetPrimaryImageHandler makes an image the one shown for its landmark.
+func (h *LandmarkHandler) AdminSetPrimaryImageHandler(w http.ResponseWriter, r *http.Request) {
+	id, imageID, ok := imageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	images, err := h.landmarkService.SetPrimaryImage(r.Context(), id, imageID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLandmarkImageNotFound) {
+			respondWithError(w, http.StatusNotFound, "Image not found")
+			return
+		}
+		log.Printf("Error setting primary image of landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to set primary image")
+		return
+	}
+
+	h.auditImageChange(r.Context(), id, fmt.Sprintf("Set image %s as primary", imageID))
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"images": services.NewLandmarkImageViews(images)})
+}
+
+// AdminUpdateImageHandler sets the caption, alt text and credit of an
+// image.
+func (h *LandmarkHandler) AdminUpdateImageHandler(w http.ResponseWriter, r *http.Request) {
+	id, imageID, ok := imageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateImageRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	image, err := h.landmarkService.UpdateImageCaption(r.Context(), id, imageID, strings.TrimSpace(req.Caption), strings.TrimSpace(req.AltText), strings.TrimSpace(req.Credit))
+	if err != nil {
+		if errors.Is(err, repository.ErrLandmarkImageNotFound) {
+			respondWithError(w, http.StatusNotFound, "Image not found")
+			return
+		}
+		log.Printf("Error updating image %s: %v", imageID, err)
+		respondWithAppError(w, err, "Failed to update image")
+		return
+	}
+
+	h.auditImageChange(r.Context(), id, fmt.Sprintf("Updated caption, alt text and credit of image %s", imageID))
+	respondWithJSON(w, http.StatusOK, services.NewLandmarkImageViews([]models.LandmarkImage{*image})[0])
+}
+
+// AddMediaRequest is a photo, video or 360° panorama to add to a gallery.
+// Videos come from YouTube or Vimeo by ID, or from an HLS stream on S3.
+type AddMediaRequest struct {
+	Type       string `json:"type" validate:"required,oneof=photo video pano"`
+	ImageURL   string `json:"image_url" validate:"omitempty,url,max=500"`
+	Provider   string `json:"provider" validate:"omitempty,oneof=youtube vimeo s3_hls"`
+	ProviderID string `json:"provider_id" validate:"max=64"`
+	StreamURL  string `json:"stream_url" validate:"omitempty,url,max=500"`
+	Caption    string `json:"caption" validate:"max=1000"`
+	AltText    string `json:"alt_text" validate:"max=1000"`
+	Credit     string `json:"credit" validate:"max=255"`
+}
+
+// AdminAddMediaHandler adds a photo, video or panorama to the end of a
+// landmark's gallery.
+func (h *LandmarkHandler) AdminAddMediaHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return
+	}
+
+	var req AddMediaRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	media, err := h.landmarkService.AddMedia(r.Context(), id, &models.LandmarkImage{
+		MediaType:  models.MediaType(req.Type),
+		ImageURL:   req.ImageURL,
+		Provider:   models.MediaProvider(req.Provider),
+		ProviderID: strings.TrimSpace(req.ProviderID),
+		StreamURL:  req.StreamURL,
+		Caption:    strings.TrimSpace(req.Caption),
+		AltText:    strings.TrimSpace(req.AltText),
+		Credit:     strings.TrimSpace(req.Credit),
+	})
+	if err != nil {
+		log.Printf("Error adding media to landmark %s: %v", id, err)
+		respondWithAppError(w, err, "Failed to add media")
+		return
+	}
+
+	h.auditImageChange(r.Context(), id, fmt.Sprintf("Added %s %s", media.Type(), media.ID))
+	respondWithJSON(w, http.StatusCreated, services.NewLandmarkImageViews([]models.LandmarkImage{*media})[0])
+}
+
+// AdminDeleteMediaHandler removes an item from a landmark's gallery.
+func (h *LandmarkHandler) AdminDeleteMediaHandler(w http.ResponseWriter, r *http.Request) {
+	id, imageID, ok := imageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.landmarkService.DeleteMedia(r.Context(), id, imageID); err != nil {
+		if errors.Is(err, repository.ErrLandmarkImageNotFound) {
+			respondWithError(w, http.StatusNotFound, "Image not found")
+			return
+		}
+		log.Printf("Error deleting image %s: %v", imageID, err)
+		respondWithAppError(w, err, "Failed to delete media")
+		return
+	}
+
+	h.auditImageChange(r.Context(), id, fmt.Sprintf("Deleted image %s", imageID))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// imageRequest returns the landmark and image IDs of an image request, or
+// responds with the problem and returns false.
+func imageRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	imageID, err := uuid.Parse(vars["imageId"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid image ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, imageID, true
+}
+
+// auditImageChange records a change to a landmark's images and drops the
+// landmark's cached responses, so the new gallery shows at once.
+func (h *LandmarkHandler) auditImageChange(ctx context.Context, id uuid.UUID, details string) {
+	err := h.auditService.CreateAuditLog(ctx, services.AuditEntry{
+		Action:     "UPDATE",
+		EntityType: "LANDMARK_IMAGE",
+		EntityID:   id.String(),
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+	h.forgetLandmark(ctx, id)
+}
+
+// forgetLandmark drops the cached responses of a landmark for every plan,
+// version and field selection.
+func (h *LandmarkHandler) forgetLandmark(ctx context.Context, id uuid.UUID) {
+	if err := h.cacheService.DeleteByPattern(ctx, "landmark:id:"+id.String()+":*"); err != nil {
+		log.Printf("Failed to delete cache entries: %v", err)
+	}
+}
+
 func (h *LandmarkHandler) AdminDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
@@ -832,13 +1233,20 @@ func (h *LandmarkHandler) AdminDeleteHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	cacheKey := h.getCacheKey("id", id.String())
-	if err := h.cacheService.Delete(r.Context(), cacheKey); err != nil {
-		log.Printf("Failed to delete cache entry: %v", err)
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "DELETE",
+		EntityType: "LANDMARK",
+		EntityID:   id.String(),
+		Details:    "Deleted landmark",
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
 	}
 
+	h.forgetLandmark(r.Context(), id)
+
 	// Respond with a success message
-	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Landmark deleted successfully"})
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Landmark deleted successfully"})
 }
 
 // Helper functions
@@ -859,7 +1267,7 @@ func parseQueryParams(r *http.Request) QueryParams {
 
 	filters := make(map[string]string)
 	for k, v := range query {
-		if k != "limit" && k != "offset" && k != "sort" && k != "fields" {
+		if k != "limit" && k != "offset" && k != "sort" && k != "fields" && k != "cursor" && k != "paginate" && k != "format" {
 			filters[k] = v[0]
 		}
 	}
@@ -871,6 +1279,8 @@ func parseQueryParams(r *http.Request) QueryParams {
 		sortOrder = "desc"
 	}
 
+	cursor := query.Get("cursor")
+
 	return QueryParams{
 		Limit:     limit,
 		Offset:    offset,
@@ -878,14 +1288,144 @@ func parseQueryParams(r *http.Request) QueryParams {
 		SortOrder: sortOrder,
 		Fields:    fields,
 		Filters:   filters,
+		Cursor:    cursor,
+		UseCursor: cursor != "" || query.Get("paginate") == "cursor",
+		Format:    query.Get("format"),
+		Version:   apiversion.FromContext(r.Context()),
 	}
 }
 
-func applyFilters(query *gorm.DB, filters map[string]string) *gorm.DB {
-	for field, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", field), value)
+// countLandmarks returns the number of landmarks matching query, cached per
+// scope and filter combination when the filters may be cached. When estimates are enabled, an unfiltered
+// count of a large table uses the planner's row estimate instead.
+func (h *LandmarkHandler) countLandmarks(ctx context.Context, query *gorm.DB, scope string, filters map[string]string) landmarkTotal {
+	if h.estimateThreshold > 0 && scope == "all" && len(filters) == 0 {
+		var estimate int64
+		err := h.readDB.WithContext(ctx).
+			Raw("SELECT reltuples::bigint FROM pg_class WHERE oid = 'landmarks'::regclass").
+			Scan(&estimate).Error
+		if err == nil && estimate >= h.estimateThreshold {
+			return landmarkTotal{Count: estimate, Estimated: true}
+		}
+	}
+
+	countQuery := func(ctx context.Context) (int64, error) {
+		var count int64
+		err := query.Session(&gorm.Session{}).WithContext(ctx).Count(&count).Error
+		return count, err
+	}
+	var count int64
+	var err error
+	if cacheable(filters) {
+		count, _, err = services.CachedFetch(ctx, h.cacheLoader, "landmark:count", []string{scope, filterKey(filters)}, h.countCacheTTL, countQuery)
+	} else {
+		count, err = countQuery(ctx)
+	}
+	if err != nil {
+		log.Printf("Error counting landmarks: %v", err)
+		return landmarkTotal{}
+	}
+	return landmarkTotal{Count: count}
+}
+
+// listLandmarksByCursor serves ListLandmarks with keyset pagination. Pages
+// are ordered by the sort column and then ID, and only include landmarks
+// created before the first page was served.
+func (h *LandmarkHandler) listLandmarksByCursor(w http.ResponseWriter, r *http.Request, params QueryParams, subscription *models.Subscription) {
+	ctx := r.Context()
+	sortBy, sortOrder := cursorSort(params.SortBy, params.SortOrder)
+	filterHash := pagination.FilterHash(params.Filters, sortBy, sortOrder)
+
+	if params.Limit < 1 || params.Limit > maxCursorPageSize {
+		params.Limit = maxCursorPageSize
+	}
+
+	query := h.readDB.Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).Preload("Images")
+	query = applyFilters(query, params.Filters, landmarkFilters)
+
+	now := time.Now()
+	snapshot := now
+	if params.Cursor != "" {
+		cursor, err := h.cursors.Decode(params.Cursor, filterHash, now)
+		if err != nil {
+			respondWithCursorError(w, err)
+			return
+		}
+		snapshot = cursor.Snapshot
+
+		op := ">"
+		if sortOrder == "desc" {
+			op = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortBy, op), cursor.SortValue, cursor.LastID)
+	}
+
+	query = query.Where("created_at <= ?", snapshot).
+		Order(fmt.Sprintf("%s %s, id %s", sortBy, sortOrder, sortOrder))
+
+	var landmarks []models.Landmark
+	if err := query.Limit(params.Limit + 1).Find(&landmarks).Error; err != nil {
+		log.Printf("Error fetching landmarks by cursor: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching landmarks")
+		return
+	}
+
+	var nextCursor *string
+	if len(landmarks) > params.Limit {
+		landmarks = landmarks[:params.Limit]
+		last := landmarks[len(landmarks)-1]
+		token := h.cursors.Encode(pagination.Cursor{
+			SortValue:  landmarkSortValue(&last, sortBy),
+			LastID:     last.ID,
+			FilterHash: filterHash,
+			Snapshot:   snapshot,
+		})
+		nextCursor = &token
+	}
+
+	response := newLandmarkList(h.landmarkViews(ctx, landmarks, subscription), params, CursorListMeta{
+		Limit:      params.Limit,
+		NextCursor: nextCursor,
+	})
+	h.respondWithLandmarkList(w, r, params, response)
+}
+
+// respondWithCursorError tells clients whether to fix their request or to
+// restart from the first page.
+func respondWithCursorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, pagination.ErrCursorExpired):
+		respondWithCode(w, apperrors.CodeCursorExpired, "Cursor has expired; restart from the first page")
+	case errors.Is(err, pagination.ErrCursorMismatch):
+		respondWithCode(w, apperrors.CodeCursorMismatch, "Cursor does not match the filters or sort of this request")
+	default:
+		respondWithCode(w, apperrors.CodeInvalidCursor, "Invalid cursor")
+	}
+}
+
+// cursorSort resolves the sort column and order the same way applySorting
+// does, so the cursor is keyed on the column actually used.
+func cursorSort(sortBy, sortOrder string) (string, string) {
+	switch sortBy {
+	case "name", "city", "country":
+	default:
+		return "name", "asc"
+	}
+	if sortOrder != "desc" {
+		sortOrder = "asc"
+	}
+	return sortBy, sortOrder
+}
+
+func landmarkSortValue(landmark *models.Landmark, sortBy string) string {
+	switch sortBy {
+	case "city":
+		return landmark.City
+	case "country":
+		return landmark.Country
+	default:
+		return landmark.Name
 	}
-	return query
 }
 
 func applySorting(query *gorm.DB, sortBy, sortOrder string) *gorm.DB {
@@ -913,12 +1453,10 @@ func (h *LandmarkHandler) CreateSubmission(w http.ResponseWriter, r *http.Reques
 	var submissionData struct {
 		Landmark       models.SubmissionLandmark       `json:"landmark"`
 		LandmarkDetail models.SubmissionLandmarkDetail `json:"landmark_detail"`
-		ImageURLs      []string                        `json:"image_urls"`
+		ImageURLs      []string                        `json:"image_urls" validate:"max=20,dive,url"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&submissionData); err != nil {
-		log.Printf("Error decoding JSON: %v", err)
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+	if !decodeJSON(w, r, &submissionData) {
 		return
 	}
 
@@ -932,6 +1470,13 @@ func (h *LandmarkHandler) CreateSubmission(w http.ResponseWriter, r *http.Reques
 	// Create the SubmissionLandmark
 	submissionData.Landmark.ID = uuid.New()
 	submissionData.Landmark.Status = "pending"
+	submissionData.Landmark.PartnerID = nil
+	submissionData.Landmark.SuggestedCategory = ""
+	submissionData.Landmark.CategoryConfidence = 0
+	submissionData.Landmark.SuggestedTags = nil
+	if user, ok := services.UserFromContext(r.Context()); ok {
+		submissionData.Landmark.UserID = &user.ID
+	}
 
 	if err := tx.Create(&submissionData.Landmark).Error; err != nil {
 		tx.Rollback()
@@ -970,8 +1515,12 @@ func (h *LandmarkHandler) CreateSubmission(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Log the submission
-	userID := getUserIDFromContext(r.Context()) // Implement this function to get the user ID from the context
-	err := h.auditService.CreateAuditLog(r.Context(), userID, "CREATE", "SUBMISSION_LANDMARK", submissionData.Landmark.ID.String(), "Created landmark submission")
+	err := h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "CREATE",
+		EntityType: "SUBMISSION_LANDMARK",
+		EntityID:   submissionData.Landmark.ID.String(),
+		Details:    "Created landmark submission",
+	})
 	if err != nil {
 		log.Printf("Failed to create audit log: %v", err)
 	}
@@ -984,7 +1533,7 @@ func (h *LandmarkHandler) ListPendingSubmissions(w http.ResponseWriter, r *http.
 
 	// First fetch landmarks with images only
 	if err := h.db.Where("status = ?", "pending").
-		Preload("Images").
+		Preload("Images.Tags").
 		Find(&submissions).Error; err != nil {
 		log.Printf("Error fetching submissions: %v", err)
 		respondWithError(w, http.StatusInternalServerError, "Failed to fetch pending submissions")
@@ -1015,29 +1564,42 @@ func (h *LandmarkHandler) ListPendingSubmissions(w http.ResponseWriter, r *http.
 		detailMap[d.SubmissionLandmarkID] = d
 	}
 
+	// Score submissions by traffic so popular content is reviewed first
+	scores, err := h.priorityService.ScoreSubmissions(r.Context(), submissions)
+	if err != nil {
+		log.Printf("Error scoring submissions: %v", err)
+		// Continue without priorities
+		scores = map[uuid.UUID]float64{}
+	}
+
 	// Create response structure
 	type Response struct {
 		*models.SubmissionLandmark
-		Details *models.SubmissionLandmarkDetail `json:"details,omitempty"`
+		Details       *models.SubmissionLandmarkDetail `json:"details,omitempty"`
+		PriorityScore float64                          `json:"priority_score"`
 	}
 
 	// Build final response
 	response := make([]Response, len(submissions))
 	for i, submission := range submissions {
+		response[i] = Response{
+			SubmissionLandmark: &submissions[i],
+			PriorityScore:      scores[submission.ID],
+		}
 		if detail, exists := detailMap[submission.ID]; exists {
 			detailCopy := detail // Make a copy to avoid reference issues
-			response[i] = Response{
-				SubmissionLandmark: &submissions[i],
-				Details:            &detailCopy,
-			}
-		} else {
-			response[i] = Response{
-				SubmissionLandmark: &submissions[i],
-				Details:            nil,
-			}
+			response[i].Details = &detailCopy
 		}
 	}
 
+	// Highest priority first, oldest first among equals
+	sort.SliceStable(response, func(i, j int) bool {
+		if response[i].PriorityScore != response[j].PriorityScore {
+			return response[i].PriorityScore > response[j].PriorityScore
+		}
+		return response[i].CreatedAt.Before(response[j].CreatedAt)
+	})
+
 	respondWithJSON(w, http.StatusOK, response)
 }
 func (h *LandmarkHandler) ApproveSubmission(w http.ResponseWriter, r *http.Request) {
@@ -1055,9 +1617,9 @@ func (h *LandmarkHandler) ApproveSubmission(w http.ResponseWriter, r *http.Reque
 	}
 
 	var submission models.SubmissionLandmark
-	if err := tx.Preload("Images").Preload("Detail").First(&submission, id).Error; err != nil {
+	if err := tx.Preload("Images.Tags").Preload("Detail").First(&submission, id).Error; err != nil {
 		tx.Rollback()
-		respondWithError(w, http.StatusNotFound, "Submission not found")
+		respondWithCode(w, apperrors.CodeSubmissionNotFound, "Submission not found")
 		return
 	}
 
@@ -1079,18 +1641,25 @@ func (h *LandmarkHandler) ApproveSubmission(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Create LandmarkImages
+	// Create LandmarkImages, leaving out those found not to show the
+	// landmark
+	order := 0
 	for _, img := range submission.Images {
+		if img.Rejected() {
+			continue
+		}
 		newImage := models.LandmarkImage{
-			ID:         uuid.New(),
-			LandmarkID: newLandmark.ID,
-			ImageURL:   img.ImageURL,
+			ID:           uuid.New(),
+			LandmarkID:   newLandmark.ID,
+			ImageURL:     img.ImageURL,
+			DisplayOrder: order,
 		}
 		if err := tx.Create(&newImage).Error; err != nil {
 			tx.Rollback()
 			respondWithError(w, http.StatusInternalServerError, "Failed to create landmark image")
 			return
 		}
+		order++
 	}
 
 	// Create LandmarkDetail
@@ -1123,11 +1692,18 @@ func (h *LandmarkHandler) ApproveSubmission(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Log the approval
-	adminID := getAdminIDFromContext(r.Context()) // Implement this function to get the admin ID from the context
-	err = h.auditService.CreateAuditLog(r.Context(), adminID, "APPROVE", "SUBMISSION_LANDMARK", submission.ID.String(), "Approved landmark submission")
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "APPROVE",
+		EntityType: "SUBMISSION_LANDMARK",
+		EntityID:   submission.ID.String(),
+		Details:    fmt.Sprintf("Approved landmark submission as landmark %s", newLandmark.ID),
+	})
 	if err != nil {
 		log.Printf("Failed to create audit log: %v", err)
 	}
+	if err := services.NotifySubmissionReviewed(r.Context(), h.notifications, &submission, true); err != nil {
+		log.Printf("Error notifying submitter of submission %s: %v", submission.ID, err)
+	}
 
 	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Landmark submission approved successfully", "new_landmark_id": newLandmark.ID.String()})
 }
@@ -1142,7 +1718,7 @@ func (h *LandmarkHandler) RejectSubmission(w http.ResponseWriter, r *http.Reques
 
 	var submission models.SubmissionLandmark
 	if err := h.db.First(&submission, id).Error; err != nil {
-		respondWithError(w, http.StatusNotFound, "Submission not found")
+		respondWithCode(w, apperrors.CodeSubmissionNotFound, "Submission not found")
 		return
 	}
 
@@ -1152,53 +1728,56 @@ func (h *LandmarkHandler) RejectSubmission(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Log the rejection
-	adminID := getAdminIDFromContext(r.Context()) // Implement this function to get the admin ID from the context
-	err = h.auditService.CreateAuditLog(r.Context(), adminID, "REJECT", "SUBMISSION_LANDMARK", submission.ID.String(), "Rejected landmark submission")
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "REJECT",
+		EntityType: "SUBMISSION_LANDMARK",
+		EntityID:   submission.ID.String(),
+		Details:    "Rejected landmark submission",
+	})
 	if err != nil {
 		log.Printf("Failed to create audit log: %v", err)
 	}
+	if err := services.NotifySubmissionReviewed(r.Context(), h.notifications, &submission, false); err != nil {
+		log.Printf("Error notifying submitter of submission %s: %v", submission.ID, err)
+	}
 
-	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Landmark submission rejected successfully"})
-}
-
-func getUserIDFromContext(ctx context.Context) int {
-	// Implement this function to get the user ID from the context
-	return 0
-}
-
-func getAdminIDFromContext(ctx context.Context) int {
-	// Implement this function to get the admin ID from the context
-	return 0
+	respondWithJSON(w, http.StatusOK, MessageResponse{Message: "Landmark submission rejected successfully"})
 }
 
 func (h *LandmarkHandler) prepareResponse(ctx context.Context, landmark *models.Landmark, subscription *models.Subscription, params QueryParams) interface{} {
-	var response interface{}
-
-	switch subscription.PlanType {
-	case models.FreePlan:
-		response = h.filterBasicLandmarkInfo(landmark)
-	case models.ProPlan, models.EnterprisePlan:
-		landmarkDetails, err := h.landmarkService.GetLandmarkDetails(ctx, landmark.ID, subscription.PlanType)
-		if err != nil {
-			return h.filterBasicLandmarkInfo(landmark)
-		}
-		response = h.mergeLandmarkAndDetails(landmark, landmarkDetails)
+	response := h.planSerializer.Landmark(ctx, landmark, subscription.PlanType)
+	if len(params.Fields) > 0 {
+		return filterFields(response, params.selectedFields())
 	}
+	return response
+}
 
-	if len(params.Fields) > 0 {
-		return filterFields(response, params.Fields)
+// selectedFields is the ?fields= selection. Formats other than plain JSON,
+// and every format from v2 on, identify resources by ID, so they always keep
+// it.
+func (p QueryParams) selectedFields() []string {
+	if (p.Format == "" || p.Format == "json") && p.Version == apiversion.V1 {
+		return p.Fields
 	}
+	return append([]string{"id"}, p.Fields...)
+}
 
-	return response
+// shapeKey identifies in cache keys what shapes a cached response besides
+// its query: the API version, whose envelope it is in, and the fields
+// selected, in a canonical order.
+func (p QueryParams) shapeKey() []string {
+	fields := "*"
+	if len(p.Fields) > 0 {
+		selected := append([]string{}, p.selectedFields()...)
+		sort.Strings(selected)
+		fields = strings.Join(slices.Compact(selected), ",")
+	}
+	return []string{"version:" + string(p.Version), "fields:" + fields}
 }
 
 func filterFields(data interface{}, fields []string) map[string]interface{} {
 	result := make(map[string]interface{})
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return result
-	}
-
+	dataMap := toJSONMap(data)
 	for _, field := range fields {
 		if value, exists := dataMap[field]; exists {
 			result[field] = value
@@ -1208,128 +1787,134 @@ func filterFields(data interface{}, fields []string) map[string]interface{} {
 }
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	respondWithJSONType(w, code, "application/json", payload)
+}
+
+func respondWithJSONType(w http.ResponseWriter, code int, contentType string, payload interface{}) {
 	response, _ := json.Marshal(payload)
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(code)
 	w.Write(response)
 }
 
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
+// respondWithError responds with message and the generic code for status.
+// Use respondWithCode when a more specific code applies.
+func respondWithError(w http.ResponseWriter, status int, message string) {
+	respondWithCode(w, apperrors.CodeForStatus(status), message)
 }
 
-// Existing helper methods remain largely unchanged but adapted for GORM
-func (h *LandmarkHandler) getLandmarkAndSubscription(ctx context.Context, id uuid.UUID, w http.ResponseWriter) (*models.Landmark, *models.Subscription, error) {
-	_, ok := services.UserFromContext(ctx)
-	if !ok {
-		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
-		return nil, nil, fmt.Errorf("unauthorized")
+// respondWithCode responds with message, code and the status code maps to.
+func respondWithCode(w http.ResponseWriter, code apperrors.Code, message string) {
+	respondWithJSON(w, code.Status(), ErrorResponse{Error: message, Code: code})
+}
+
+// respondWithAppError responds to a service or repository error with its
+// code. Internal errors get fallback as their message, so no details of
+// the failure leak to the client.
+func respondWithAppError(w http.ResponseWriter, err error, fallback string) {
+	code := apperrors.CodeOf(err)
+	if code == apperrors.CodeInternal {
+		respondWithCode(w, code, fallback)
+		return
 	}
+	respondWithCode(w, code, err.Error())
+}
 
-	subscription, ok := services.SubscriptionFromContext(ctx)
-	if !ok {
-		respondWithError(w, http.StatusForbidden, "Subscription not found")
-		return nil, nil, fmt.Errorf("subscription not found")
+// processLandmarkList handles the processing of multiple landmarks based on subscription and query parameters
+func (h *LandmarkHandler) processLandmarkList(ctx context.Context, landmarks []models.Landmark, subscription *models.Subscription, params QueryParams, total landmarkTotal) *LandmarkList {
+	return newLandmarkList(h.landmarkViews(ctx, landmarks, subscription), params, newListMeta(total, params))
+}
+
+// landmarkViews returns landmarks as the plan of subscription sees them, in
+// the same order.
+func (h *LandmarkHandler) landmarkViews(ctx context.Context, landmarks []models.Landmark, subscription *models.Subscription) []*services.LandmarkView {
+	views := make([]*services.LandmarkView, len(landmarks))
+	for i := range landmarks {
+		views[i] = h.planSerializer.Landmark(ctx, &landmarks[i], subscription.PlanType)
 	}
+	return views
+}
 
-	var landmark models.Landmark
-	if err := h.db.Preload("Images").First(&landmark, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			respondWithError(w, http.StatusNotFound, "Landmark not found")
+// newLandmarkList returns a page of views, keeping only the fields selected
+// by params.
+func newLandmarkList(views []*services.LandmarkView, params QueryParams, meta interface{}) *LandmarkList {
+	data := make([]interface{}, 0, len(views))
+	for _, view := range views {
+		if len(params.Fields) > 0 {
+			data = append(data, filterFields(view, params.selectedFields()))
 		} else {
-			respondWithError(w, http.StatusInternalServerError, "Error fetching landmark")
+			data = append(data, view)
 		}
-		return nil, nil, err
 	}
-
-	return &landmark, subscription, nil
+	return &LandmarkList{Data: data, Meta: meta}
 }
 
-func (h *LandmarkHandler) filterBasicLandmarkInfo(landmark *models.Landmark) map[string]interface{} {
-	return map[string]interface{}{
-		"id":          landmark.ID,
-		"name":        landmark.Name,
-		"description": landmark.Description,
-		"country":     landmark.Country,
-		"city":        landmark.City,
-		"category":    landmark.Category,
-		"latitude":    landmark.Latitude,
-		"longitude":   landmark.Longitude,
-		"image_url":   landmark.ImageUrl,
-		"images":      landmark.Images,
+func newListMeta(total landmarkTotal, params QueryParams) ListMeta {
+	return ListMeta{
+		Total:          total.Count,
+		TotalEstimated: total.Estimated,
+		Limit:          params.Limit,
+		Offset:         params.Offset,
 	}
 }
 
-// mergeLandmarkAndDetails combines landmark data with its details based on subscription
-func (h *LandmarkHandler) mergeLandmarkAndDetails(landmark *models.Landmark, details *models.LandmarkDetail) map[string]interface{} {
-	merged := h.filterBasicLandmarkInfo(landmark)
-
-	// Add image information
-	merged["images"] = landmark.Images
-
-	// Fetch weather data
-	weatherData, err := services.FetchWeatherData(landmark.Latitude, landmark.Longitude)
-	if err != nil {
-		log.Printf("Error fetching weather data: %v", err)
-		weatherData = nil
-	}
-
-	if details != nil {
-		additionalInfo := map[string]interface{}{
-			"opening_hours":           details.OpeningHours,
-			"ticket_prices":           details.TicketPrices,
-			"historical_significance": details.HistoricalSignificance,
-			"visitor_tips":            details.VisitorTips,
-			"accessibility_info":      details.AccessibilityInfo,
-			"weather_info":            weatherData,
-		}
-
-		// Add additional info based on subscription level
-		for k, v := range additionalInfo {
-			merged[k] = v
-		}
-	}
+// respondWithLandmark writes a landmark with the user's custom fields.
+func (h *LandmarkHandler) respondWithLandmark(w http.ResponseWriter, r *http.Request, params QueryParams, response interface{}) {
+	doc := toJSONMap(response)
+	h.addCustomFields(r.Context(), params, []interface{}{doc})
+	respondWithLandmark(w, r, params.Format, doc)
+}
 
-	return merged
+// respondWithLandmarkList writes a landmark list with the user's custom
+// fields. X-Result-Count is the number of landmarks on the page, which the
+// request log uses to summarize the request.
+func (h *LandmarkHandler) respondWithLandmarkList(w http.ResponseWriter, r *http.Request, params QueryParams, list interface{}) {
+	doc := toJSONMap(list)
+	data, _ := doc["data"].([]interface{})
+	w.Header().Set("X-Result-Count", strconv.Itoa(len(data)))
+	h.addCustomFields(r.Context(), params, data)
+	respondWithLandmarkList(w, r, params.Format, doc)
 }
 
-// processLandmarkList handles the processing of multiple landmarks based on subscription and query parameters
-func (h *LandmarkHandler) processLandmarkList(ctx context.Context, landmarks []models.Landmark, subscription *models.Subscription, params QueryParams) map[string]interface{} {
-	var processedLandmarks []map[string]interface{}
+// addCustomFields adds custom_fields to the landmarks in docs when the user
+// is on the Enterprise plan and did not leave them out with ?fields=. They
+// are private to the account, so they are added to each response rather
+// than to the responses cached for the whole plan.
+func (h *LandmarkHandler) addCustomFields(ctx context.Context, params QueryParams, docs []interface{}) {
+	subscription, ok := services.SubscriptionFromContext(ctx)
+	if !ok || subscription.PlanType != models.EnterprisePlan || len(docs) == 0 {
+		return
+	}
+	if len(params.Fields) > 0 && !models.StringList(params.Fields).Contains("custom_fields") {
+		return
+	}
 
-	for _, landmark := range landmarks {
-		var landmarkData map[string]interface{}
-
-		switch subscription.PlanType {
-		case models.FreePlan:
-			landmarkData = h.filterBasicLandmarkInfo(&landmark)
-		case models.ProPlan, models.EnterprisePlan:
-			details, err := h.landmarkService.GetLandmarkDetails(ctx, landmark.ID, subscription.PlanType)
-			if err != nil {
-				landmarkData = h.filterBasicLandmarkInfo(&landmark)
-			} else {
-				landmarkData = h.mergeLandmarkAndDetails(&landmark, details)
-			}
+	landmarks := make(map[uuid.UUID]map[string]interface{}, len(docs))
+	ids := make([]uuid.UUID, 0, len(docs))
+	for _, item := range docs {
+		doc, ok := item.(map[string]interface{})
+		if !ok {
+			continue
 		}
-
-		// Apply field filtering if specified
-		if len(params.Fields) > 0 {
-			landmarkData = filterFields(landmarkData, params.Fields)
+		idStr, _ := doc["id"].(string)
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			continue
 		}
-
-		processedLandmarks = append(processedLandmarks, landmarkData)
+		landmarks[id] = doc
+		ids = append(ids, id)
 	}
 
-	// Get total count for pagination
-	var totalCount int64
-	h.db.Model(&models.Landmark{}).Count(&totalCount)
-
-	return map[string]interface{}{
-		"data": processedLandmarks,
-		"meta": map[string]interface{}{
-			"total":  totalCount,
-			"limit":  params.Limit,
-			"offset": params.Offset,
-		},
+	fields, err := h.customFields.ForLandmarks(ctx, subscription.UserID, ids)
+	if err != nil {
+		log.Printf("Error fetching custom fields of user %s: %v", subscription.UserID, err)
+		return
+	}
+	for id, doc := range landmarks {
+		if custom, ok := fields[id]; ok {
+			doc["custom_fields"] = custom
+		} else {
+			doc["custom_fields"] = map[string]string{}
+		}
 	}
 }
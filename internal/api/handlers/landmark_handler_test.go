@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"landmark-api/internal/apiversion"
+	"slices"
+	"testing"
+)
+
+func TestShapeKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b QueryParams
+		same bool
+	}{
+		{"versions", QueryParams{Version: apiversion.V1}, QueryParams{Version: apiversion.V2}, false},
+		{"fields", QueryParams{Version: apiversion.V1}, QueryParams{Version: apiversion.V1, Fields: []string{"name"}}, false},
+		{"id added from v2", QueryParams{Version: apiversion.V2}, QueryParams{Version: apiversion.V2, Fields: []string{"id"}}, false},
+		{"id added for other formats", QueryParams{Version: apiversion.V1, Fields: []string{"name"}}, QueryParams{Version: apiversion.V1, Fields: []string{"name"}, Format: "hal"}, false},
+		{"field order", QueryParams{Version: apiversion.V1, Fields: []string{"name", "city"}}, QueryParams{Version: apiversion.V1, Fields: []string{"city", "name"}}, true},
+		{"repeated fields", QueryParams{Version: apiversion.V2, Fields: []string{"name", "name"}}, QueryParams{Version: apiversion.V2, Fields: []string{"id", "name"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a.shapeKey(), tt.b.shapeKey()
+			if slices.Equal(a, b) != tt.same {
+				t.Errorf("shapeKey %v and %v: same = %v, want %v", a, b, !tt.same, tt.same)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"landmark-api/internal/services"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type LandmarkStatsHandler struct {
@@ -28,3 +30,57 @@ func (h *LandmarkStatsHandler) GetLandmarkStats(w http.ResponseWriter, r *http.R
 
 	respondWithJSON(w, http.StatusOK, stats)
 }
+
+// GetWeeklyGrowth returns landmarks added, submissions and approval rate per
+// week for the admin dashboard. ?weeks= selects how many weeks to return.
+func (h *LandmarkStatsHandler) GetWeeklyGrowth(w http.ResponseWriter, r *http.Request) {
+	weeks := services.DefaultGrowthWeeks
+	if raw := r.URL.Query().Get("weeks"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > services.MaxGrowthWeeks {
+			respondWithError(w, http.StatusBadRequest, "weeks must be between 1 and "+strconv.Itoa(services.MaxGrowthWeeks))
+			return
+		}
+		weeks = parsed
+	}
+
+	growth, err := h.landmarkStatsService.GetWeeklyGrowth(r.Context(), weeks, time.Now())
+	if err != nil {
+		log.Printf("Error fetching landmark growth: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching landmark growth")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"weeks": growth,
+	})
+}
+
+// GetPublicLandmarkStats godoc
+// @Summary Get landmark statistics
+// @Description Get landmark counts by category, plus counts by country and recently added landmarks on paid plans. Refreshed hourly.
+// @Tags stats
+// @Produce json
+// @Success 200 {object} models.PublicLandmarkStats
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/stats/landmarks [get]
+func (h *LandmarkStatsHandler) GetPublicLandmarkStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscription, ok := services.SubscriptionFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Subscription not found")
+		return
+	}
+
+	stats, err := h.landmarkStatsService.GetPublicLandmarkStats(ctx, subscription.PlanType)
+	if err != nil {
+		log.Printf("Error fetching public landmark stats: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching landmark stats")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, stats)
+}
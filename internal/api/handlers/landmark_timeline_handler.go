@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type LandmarkTimelineHandler struct {
+	timelineService services.LandmarkTimelineService
+}
+
+func NewLandmarkTimelineHandler(timelineService services.LandmarkTimelineService) *LandmarkTimelineHandler {
+	return &LandmarkTimelineHandler{
+		timelineService: timelineService,
+	}
+}
+
+// GetTimeline returns everything that happened to a landmark, including
+// deleted ones, in chronological order.
+func (h *LandmarkTimelineHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid landmark ID")
+		return
+	}
+
+	timeline, err := h.timelineService.GetTimeline(r.Context(), id)
+	if err != nil {
+		log.Printf("Error building timeline for landmark %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch landmark timeline")
+		return
+	}
+	if timeline == nil {
+		respondWithCode(w, apperrors.CodeLandmarkNotFound, "Landmark not found")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, timeline)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"landmark-api/internal/mail"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"net/http"
+)
+
+// maxMailWebhookBytes bounds the webhook payloads read. SendGrid batches
+// events, so its posts can be large.
+const maxMailWebhookBytes = 1 << 20
+
+// MailWebhookHandler receives the bounce and complaint webhooks of the mail
+// providers and suppresses the addresses they report, so nothing is sent to
+// them again.
+type MailWebhookHandler struct {
+	suppressions repository.MailSuppressionRepository
+	// sendGrid and ses are nil when their webhook is not configured.
+	sendGrid *mail.SendGridWebhook
+	ses      *mail.SESWebhook
+}
+
+func NewMailWebhookHandler(suppressions repository.MailSuppressionRepository, sendGrid *mail.SendGridWebhook, ses *mail.SESWebhook) *MailWebhookHandler {
+	return &MailWebhookHandler{suppressions: suppressions, sendGrid: sendGrid, ses: ses}
+}
+
+// HandleSendGridEvents receives SendGrid's signed event webhook.
+func (h *MailWebhookHandler) HandleSendGridEvents(w http.ResponseWriter, r *http.Request) {
+	if h.sendGrid == nil {
+		respondWithError(w, http.StatusNotFound, "SendGrid webhook is not configured")
+		return
+	}
+	body, ok := readWebhookBody(w, r)
+	if !ok {
+		return
+	}
+
+	bounces, err := h.sendGrid.Bounces(
+		r.Header.Get("X-Twilio-Email-Event-Webhook-Signature"),
+		r.Header.Get("X-Twilio-Email-Event-Webhook-Timestamp"),
+		body,
+	)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+	h.suppress(r.Context(), w, bounces)
+}
+
+// HandleSESNotifications receives the SNS topic SES publishes bounces and
+// complaints to, confirming the subscription when SNS asks.
+func (h *MailWebhookHandler) HandleSESNotifications(w http.ResponseWriter, r *http.Request) {
+	if h.ses == nil {
+		respondWithError(w, http.StatusNotFound, "SES webhook is not configured")
+		return
+	}
+	body, ok := readWebhookBody(w, r)
+	if !ok {
+		return
+	}
+
+	message, err := h.ses.Parse(r.Context(), body)
+	if err != nil {
+		respondWithWebhookError(w, err)
+		return
+	}
+
+	switch message.Type {
+	case mail.SNSSubscriptionConfirmation:
+		if err := h.ses.Confirm(r.Context(), message); err != nil {
+			log.Printf("Error confirming SES notification subscription: %v", err)
+			respondWithError(w, http.StatusBadGateway, "Failed to confirm subscription")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case mail.SNSNotification:
+		bounces, err := h.ses.Bounces(message)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.suppress(r.Context(), w, bounces)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// suppress records bounces. A failure answers 500, so the provider posts
+// the batch again.
+func (h *MailWebhookHandler) suppress(ctx context.Context, w http.ResponseWriter, bounces []mail.Bounce) {
+	for _, bounce := range bounces {
+		err := h.suppressions.Suppress(ctx, &models.MailSuppression{
+			Address:  bounce.Address,
+			Type:     string(bounce.Type),
+			Reason:   bounce.Reason,
+			Provider: bounce.Provider,
+		})
+		if err != nil {
+			log.Printf("Error suppressing %s after %s from %s: %v", bounce.Address, bounce.Type, bounce.Provider, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to record bounce")
+			return
+		}
+		log.Printf("Suppressed %s after %s from %s: %s", bounce.Address, bounce.Type, bounce.Provider, bounce.Reason)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func readWebhookBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMailWebhookBytes))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Failed to read request body")
+		return nil, false
+	}
+	return body, true
+}
+
+func respondWithWebhookError(w http.ResponseWriter, err error) {
+	if errors.Is(err, mail.ErrInvalidSignature) {
+		respondWithError(w, http.StatusUnauthorized, "Invalid signature")
+		return
+	}
+	log.Printf("Error reading mail webhook: %v", err)
+	respondWithError(w, http.StatusBadRequest, err.Error())
+}
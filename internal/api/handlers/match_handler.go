@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type MatchHandler struct {
+	matchService services.MatchService
+}
+
+func NewMatchHandler(matchService services.MatchService) *MatchHandler {
+	return &MatchHandler{
+		matchService: matchService,
+	}
+}
+
+// MatchRequest is a partner landmark record plus the number of matches wanted.
+type MatchRequest struct {
+	services.MatchRecord
+	Limit int `json:"limit"`
+}
+
+// MatchLandmark godoc
+// @Summary Match a partner landmark record
+// @Description Resolve a partner's landmark record (name, city, coordinates) to our best-matching landmark IDs with confidence scores
+// @Tags landmarks
+// @Accept json
+// @Produce json
+// @Param request body MatchRequest true "Partner landmark record"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/match [post]
+func (h *MatchHandler) MatchLandmark(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	subscription, ok := services.SubscriptionFromContext(ctx)
+	if !ok || subscription.PlanType != models.EnterprisePlan {
+		respondWithCode(w, apperrors.CodeSubscriptionRequired, "Forbidden: Enterprise subscription required")
+		return
+	}
+
+	var req MatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		respondWithError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		respondWithError(w, http.StatusBadRequest, "latitude and longitude must be provided together")
+		return
+	}
+	if req.Latitude != nil && (*req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180) {
+		respondWithError(w, http.StatusBadRequest, "Invalid coordinates")
+		return
+	}
+
+	matches, err := h.matchService.Match(ctx, req.MatchRecord, req.Limit)
+	if err != nil {
+		log.Printf("Error matching landmark record: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error matching landmark")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"matches": matches,
+	})
+}
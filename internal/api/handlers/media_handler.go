@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"landmark-api/internal/services"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
@@ -19,12 +21,13 @@ import (
 
 // FileUploadHandler handles file upload requests
 type FileUploadHandler struct {
-	S3Client *s3.S3
-	Bucket   string
+	S3Client     *s3.S3
+	Bucket       string
+	auditService services.AuditLogService
 }
 
 // NewFileUploadHandler creates a new FileUploadHandler
-func NewFileUploadHandler(region, bucket string) (*FileUploadHandler, error) {
+func NewFileUploadHandler(region, bucket string, auditService services.AuditLogService) (*FileUploadHandler, error) {
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(region),
 	})
@@ -33,8 +36,9 @@ func NewFileUploadHandler(region, bucket string) (*FileUploadHandler, error) {
 	}
 
 	return &FileUploadHandler{
-		S3Client: s3.New(sess),
-		Bucket:   bucket,
+		S3Client:     s3.New(sess),
+		Bucket:       bucket,
+		auditService: auditService,
 	}, nil
 }
 
@@ -50,12 +54,11 @@ type uploadResponse struct {
 // @Tags files
 // @Accept multipart/form-data
 // @Produce json
-// @Param landmarkId path string true "Landmark ID"
 // @Param images formData file true "Files to upload"
 // @Success 200 {object} uploadResponse
 // @Failure 400 {string} string "Invalid request"
 // @Failure 500 {string} string "Internal server error"
-// @Router /upload/{landmarkId} [post]
+// @Router /admin/landmarks/upload-photo [post]
 func (h *FileUploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -91,6 +94,16 @@ func (h *FileUploadHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		urls = append(urls, url)
 	}
 
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "UPLOAD",
+		EntityType: "LANDMARK_PHOTO",
+		EntityID:   landmarkID,
+		Details:    fmt.Sprintf("Uploaded %d photos: %s", len(urls), strings.Join(urls, ", ")),
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
 	// Return the URLs to the client
 	resp := uploadResponse{URLs: urls}
 	w.Header().Set("Content-Type", "application/json")
@@ -142,7 +155,7 @@ func (h *FileUploadHandler) uploadFile(landmarkID string, fileHeader *multipart.
 // @Success 200 {object} uploadResponse
 // @Failure 400 {string} string "Invalid request"
 // @Failure 500 {string} string "Internal server error"
-// @Router /submit-photos [post]
+// @Router /api/v1/contribution/submit-photo [post]
 func (h *FileUploadHandler) SubmitPhotos(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
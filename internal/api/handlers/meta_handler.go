@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"landmark-api/internal/locale"
+	"net/http"
+)
+
+type MetaHandler struct{}
+
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// GetLocales godoc
+// @Summary List country locale metadata
+// @Description Get the currency, locale and phone prefix of every ISO 3166-1 country, so clients can format a landmark's prices, dates and phone numbers
+// @Tags meta
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /api/v1/meta/locales [get]
+func (h *MetaHandler) GetLocales(w http.ResponseWriter, r *http.Request) {
+	countries := locale.All()
+	// The dataset only changes with a release
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"countries": countries,
+		"total":     len(countries),
+	})
+}
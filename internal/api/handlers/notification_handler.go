@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+)
+
+// NotificationHandler serves the user's in-app notification feed.
+type NotificationHandler struct {
+	notifications services.NotificationService
+}
+
+func NewNotificationHandler(notifications services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// NotificationList is a page of the user's notifications, newest first.
+type NotificationList struct {
+	Data []models.Notification `json:"data"`
+	Meta NotificationListMeta  `json:"meta"`
+}
+
+// NotificationListMeta describes a page of notifications. Unread counts
+// all the user's unread notifications, whatever the filter.
+type NotificationListMeta struct {
+	Total  int64 `json:"total"`
+	Unread int64 `json:"unread"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Get the logged in user's notifications about their quota, submissions, billing and account security, newest first.
+// @Tags user
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Number of items to return, at most 100"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {object} NotificationList
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/notifications [get]
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultNotificationPageSize
+	}
+	if limit > maxNotificationPageSize {
+		limit = maxNotificationPageSize
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	unreadOnly := query.Get("unread") == "true"
+
+	notifications, total, unread, err := h.notifications.List(r.Context(), user.ID, unreadOnly, limit, offset)
+	if err != nil {
+		log.Printf("Error listing notifications of user %s: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list notifications")
+		return
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	respondWithJSON(w, http.StatusOK, NotificationList{
+		Data: notifications,
+		Meta: NotificationListMeta{Total: total, Unread: unread, Limit: limit, Offset: offset},
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification read
+// @Tags user
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	if err := h.notifications.MarkRead(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			respondWithCode(w, apperrors.CodeNotificationNotFound, "Notification not found")
+			return
+		}
+		log.Printf("Error marking notification %s read: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark notification read")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications read
+// @Tags user
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/notifications/read [put]
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.notifications.MarkAllRead(r.Context(), user.ID); err != nil {
+		log.Printf("Error marking notifications of user %s read: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark notifications read")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
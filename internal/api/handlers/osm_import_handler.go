@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"landmark-api/internal/geo"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type OSMImportHandler struct {
+	importService services.OSMImportService
+	auditService  services.AuditLogService
+}
+
+func NewOSMImportHandler(importService services.OSMImportService, auditService services.AuditLogService) *OSMImportHandler {
+	return &OSMImportHandler{
+		importService: importService,
+		auditService:  auditService,
+	}
+}
+
+// CreateImport queues an import of the OpenStreetMap points of the given
+// categories in a bounding box. The points become landmark submissions for
+// review.
+func (h *OSMImportHandler) CreateImport(w http.ResponseWriter, r *http.Request) {
+	admin, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		BBox       string   `json:"bbox"`
+		Categories []string `json:"categories"`
+	}
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	bounds, err := geo.ParseBBox(req.BBox)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	osmImport, err := h.importService.CreateImport(r.Context(), admin.ID, bounds, req.Categories)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownOSMCategory), errors.Is(err, services.ErrNoOSMCategories):
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%v; categories are %s", err, strings.Join(services.OSMCategories(), ", ")))
+		case errors.Is(err, services.ErrImportAreaTooLarge), errors.Is(err, services.ErrImportAntimeridian):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("Error creating OpenStreetMap import: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to create import")
+		}
+		return
+	}
+
+	err = h.auditService.CreateAuditLog(r.Context(), services.AuditEntry{
+		Action:     "CREATE",
+		EntityType: "OSM_IMPORT",
+		EntityID:   osmImport.ID.String(),
+		Details:    fmt.Sprintf("Queued OpenStreetMap import of %s in %s", strings.Join(osmImport.Categories, ", "), osmImport.BBox),
+	})
+	if err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+
+	w.Header().Set("Location", "/admin/imports/osm/"+osmImport.ID.String())
+	respondWithJSON(w, http.StatusAccepted, osmImport)
+}
+
+// GetImport reports the status of an import and, once it has completed,
+// how many submissions it created.
+func (h *OSMImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid import ID")
+		return
+	}
+
+	osmImport, err := h.importService.GetImport(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrOSMImportNotFound) {
+			respondWithError(w, http.StatusNotFound, "Import not found")
+			return
+		}
+		log.Printf("Error fetching OpenStreetMap import %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch import")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, osmImport)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+)
+
+// PartnerHandler serves the partner API, through which Enterprise data
+// partners propose landmarks and follow how their proposals were reviewed.
+type PartnerHandler struct {
+	partners     services.PartnerService
+	auditService services.AuditLogService
+}
+
+func NewPartnerHandler(partners services.PartnerService, auditService services.AuditLogService) *PartnerHandler {
+	return &PartnerHandler{partners: partners, auditService: auditService}
+}
+
+// PartnerLandmarkRequest is a landmark proposed by a partner.
+type PartnerLandmarkRequest struct {
+	Landmark       models.SubmissionLandmark       `json:"landmark"`
+	LandmarkDetail models.SubmissionLandmarkDetail `json:"landmark_detail"`
+	ImageURLs      []string                        `json:"image_urls" validate:"max=20,dive,url"`
+}
+
+// ProposeLandmark godoc
+// @Summary Propose a landmark
+// @Description Propose a landmark as an Enterprise data partner. The proposal joins the submission queue under the partner's ID, or is published at once for partners listed in PARTNER_TRUSTED_IDS, in which case the new landmark's ID is returned.
+// @Tags partner
+// @Accept json
+// @Produce json
+// @Param proposal body PartnerLandmarkRequest true "Proposed landmark"
+// @Success 201 {object} services.PartnerProposal
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/partner/landmarks [post]
+func (h *PartnerHandler) ProposeLandmark(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req PartnerLandmarkRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	submission := req.Landmark
+	submission.Detail = req.LandmarkDetail
+	submission.Images = make([]models.SubmissionLandmarkImage, len(req.ImageURLs))
+	for i, url := range req.ImageURLs {
+		submission.Images[i] = models.SubmissionLandmarkImage{ImageURL: url}
+	}
+
+	proposal, err := h.partners.Propose(r.Context(), user, subscription, &submission)
+	if err != nil {
+		log.Printf("Error creating proposal of partner %s: %v", user.ID, err)
+		respondWithAppError(w, err, "Failed to create landmark proposal")
+		return
+	}
+
+	h.audit(r, services.AuditEntry{
+		Action:     "CREATE",
+		EntityType: "SUBMISSION_LANDMARK",
+		EntityID:   proposal.SubmissionID.String(),
+		Details:    fmt.Sprintf("Created landmark submission for partner %s", user.ID),
+	})
+	if proposal.LandmarkID != nil {
+		h.audit(r, services.AuditEntry{
+			Action:     "APPROVE",
+			EntityType: "SUBMISSION_LANDMARK",
+			EntityID:   proposal.SubmissionID.String(),
+			Details:    fmt.Sprintf("Approved submission of trusted partner %s as landmark %s", user.ID, *proposal.LandmarkID),
+		})
+	}
+
+	respondWithJSON(w, http.StatusCreated, proposal)
+}
+
+// GetContributions godoc
+// @Summary Get partner contribution stats
+// @Description Count the landmarks the partner proposed, and how many of them are pending, approved or rejected.
+// @Tags partner
+// @Produce json
+// @Success 200 {object} models.PartnerContributions
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/partner/contributions [get]
+func (h *PartnerHandler) GetContributions(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	contributions, err := h.partners.Contributions(r.Context(), user, subscription)
+	if err != nil {
+		log.Printf("Error counting contributions of partner %s: %v", user.ID, err)
+		respondWithAppError(w, err, "Failed to fetch contributions")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, contributions)
+}
+
+// ListContributions godoc
+// @Summary List partner contribution stats
+// @Description Count the landmarks each data partner proposed, and how many of them are pending, approved or rejected, most proposals first.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.PartnerContributions
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/partners/contributions [get]
+func (h *PartnerHandler) ListContributions(w http.ResponseWriter, r *http.Request) {
+	contributions, err := h.partners.AllContributions(r.Context())
+	if err != nil {
+		log.Printf("Error counting partner contributions: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch contributions")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, contributions)
+}
+
+func (h *PartnerHandler) audit(r *http.Request, entry services.AuditEntry) {
+	if err := h.auditService.CreateAuditLog(r.Context(), entry); err != nil {
+		log.Printf("Failed to create audit log: %v", err)
+	}
+}
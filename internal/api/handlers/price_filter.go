@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+var errPriceFormat = errors.New("must be a non-negative number")
+
+// priceFilter compares the entry price of landmarks, as normalized from
+// their ticket prices, with its value using the operator it holds.
+// Landmarks without a known entry price never match.
+type priceFilter string
+
+func (f priceFilter) check(value string) error {
+	_, err := parsePrice(value)
+	return err
+}
+
+func (f priceFilter) apply(query *gorm.DB, value string) *gorm.DB {
+	price, err := parsePrice(value)
+	if err != nil {
+		return query
+	}
+	return entryPrice(query, "d.entry_price "+string(f)+" ?", price)
+}
+
+// freeEntryFilter keeps landmarks with free entry when true and those that
+// charge for it when false.
+type freeEntryFilter struct{}
+
+func (freeEntryFilter) check(value string) error {
+	return checkBool(value)
+}
+
+func (freeEntryFilter) apply(query *gorm.DB, value string) *gorm.DB {
+	if value == "true" {
+		return entryPrice(query, "d.entry_price = 0")
+	}
+	return entryPrice(query, "d.entry_price > 0")
+}
+
+// entryPrice keeps landmarks whose details match condition.
+func entryPrice(query *gorm.DB, condition string, args ...interface{}) *gorm.DB {
+	return query.Where(`EXISTS (
+	SELECT 1 FROM landmark_details d
+	WHERE d.landmark_id = landmarks.id AND d.deleted_at IS NULL AND `+condition+`
+)`, args...)
+}
+
+func parsePrice(value string) (float64, error) {
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 {
+		return 0, errPriceFormat
+	}
+	return price, nil
+}
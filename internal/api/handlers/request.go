@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/validation"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// decodeJSON decodes the request body into v and validates it against its
+// `validate` tags. If either fails it responds with the problem, naming the
+// offending fields where it can, and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			respondWithCode(w, apperrors.CodePayloadTooLarge, "Request body too large")
+		case errors.As(err, &typeErr) && typeErr.Field != "":
+			respondWithFieldErrors(w, validation.Errors{{
+				Field:   typeErr.Field,
+				Message: "must be " + jsonTypeName(typeErr.Type.Kind()),
+			}})
+		default:
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		}
+		return false
+	}
+
+	if err := validation.Struct(v); err != nil {
+		respondWithFieldErrors(w, err.(validation.Errors))
+		return false
+	}
+	return true
+}
+
+func respondWithFieldErrors(w http.ResponseWriter, fieldErrs validation.Errors) {
+	respondWithJSON(w, apperrors.CodeValidationFailed.Status(), ErrorResponse{
+		Error:  "Invalid request payload",
+		Code:   apperrors.CodeValidationFailed,
+		Fields: fieldErrs,
+	})
+}
+
+// preferredLanguage is the first language of the request's
+// Accept-Language header, e.g. "pl-PL" for "pl-PL,pl;q=0.9,en;q=0.8", or
+// empty when it has none.
+func preferredLanguage(r *http.Request) string {
+	first, _, _ := strings.Cut(r.Header.Get("Accept-Language"), ",")
+	tag, _, _ := strings.Cut(first, ";")
+	return strings.TrimSpace(tag)
+}
+
+// jsonTypeName names a Go kind the way a JSON client would know it.
+func jsonTypeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a number"
+}
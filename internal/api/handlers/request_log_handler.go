@@ -2,9 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
+	"log"
 	"net/http"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 type RequestLogHandler struct {
@@ -27,6 +35,11 @@ func (h *RequestLogHandler) GetUserLogs(w http.ResponseWriter, r *http.Request)
 	// Parse time range from query parameters
 	from, to := getTimeRange(r)
 
+	if r.URL.Query().Get("format") == "csv" {
+		h.streamCSV(w, r, user, from, to)
+		return
+	}
+
 	logs, err := h.logService.GetUserLogs(user.ID.String(), from, to)
 	if err != nil {
 		http.Error(w, "Error fetching logs", http.StatusInternalServerError)
@@ -37,6 +50,121 @@ func (h *RequestLogHandler) GetUserLogs(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(logs)
 }
 
+// streamCSV writes the user's logs as CSV while reading them, so exports
+// never sit in memory. Larger ranges must use an asynchronous export.
+func (h *RequestLogHandler) streamCSV(w http.ResponseWriter, r *http.Request, user *models.User, from, to time.Time) {
+	if !from.Before(to) {
+		respondWithError(w, http.StatusBadRequest, services.ErrInvalidExportRange.Error())
+		return
+	}
+	if to.Sub(from) > services.MaxStreamedExportRange {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf(
+			"CSV exports are limited to %d days; use POST /user/api/v1/requests/logs/exports for larger ranges",
+			int(services.MaxStreamedExportRange.Hours()/24),
+		))
+		return
+	}
+
+	setCSVHeaders(w, fmt.Sprintf("request-logs-%s-%s.csv", from.Format(auditLogDateLayout), to.Format(auditLogDateLayout)))
+
+	// The header is already sent, so a failure can only truncate the file
+	if _, err := h.logService.ExportUserLogsCSV(r.Context(), user.ID.String(), from, to, w); err != nil {
+		log.Printf("Error streaming request logs for user %s: %v", user.ID, err)
+	}
+}
+
+// CreateExport queues a CSV export of the user's logs for ranges too large
+// to stream.
+func (h *RequestLogHandler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: from and to must be RFC 3339 timestamps")
+		return
+	}
+
+	export, err := h.logService.CreateExport(r.Context(), user.ID, req.From, req.To)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidExportRange):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrExportRangeTooLarge):
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Exports are limited to %d days", int(services.MaxExportRange.Hours()/24)))
+		default:
+			log.Printf("Error creating request log export for user %s: %v", user.ID, err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to create export")
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/user/api/v1/requests/logs/exports/"+export.ID.String())
+	respondWithJSON(w, http.StatusAccepted, export)
+}
+
+// GetExport reports the status of one of the user's exports.
+func (h *RequestLogHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	export, ok := h.findExport(w, r)
+	if !ok {
+		return
+	}
+	respondWithJSON(w, http.StatusOK, export)
+}
+
+// DownloadExport returns a completed export's CSV.
+func (h *RequestLogHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	export, ok := h.findExport(w, r)
+	if !ok {
+		return
+	}
+
+	if export.Status != models.ExportCompleted {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("%s: status is %s", services.ErrExportNotReady, export.Status))
+		return
+	}
+
+	setCSVHeaders(w, fmt.Sprintf("request-logs-%s-%s.csv", export.From.Format(auditLogDateLayout), export.To.Format(auditLogDateLayout)))
+	w.Write(export.Content)
+}
+
+func (h *RequestLogHandler) findExport(w http.ResponseWriter, r *http.Request) (*models.RequestLogExport, bool) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return nil, false
+	}
+
+	export, err := h.logService.GetExport(r.Context(), id, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRequestLogExportNotFound) {
+			respondWithError(w, http.StatusNotFound, "Export not found")
+			return nil, false
+		}
+		log.Printf("Error fetching request log export %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch export")
+		return nil, false
+	}
+	return export, true
+}
+
+func setCSVHeaders(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+}
+
 func getTimeRange(r *http.Request) (time.Time, time.Time) {
 	now := time.Now()
 	from := now.AddDate(0, -1, 0) // Default to last 30 days
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"landmark-api/internal/validation"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Error string         `json:"error"`
+	Code  apperrors.Code `json:"code"`
+	// Fields are the fields of a request that failed validation.
+	Fields validation.Errors `json:"fields,omitempty"`
+	// Allowed are the values accepted where the request had an unknown
+	// one, such as a filter or format.
+	Allowed []string `json:"allowed,omitempty"`
+	// ResetAt and RetryAfter, in seconds as in the Retry-After header, say
+	// when a request refused for going over a limit can be retried.
+	ResetAt    *time.Time `json:"reset_at,omitempty"`
+	RetryAfter int        `json:"retry_after,omitempty"`
+}
+
+// LandmarkList is a page of landmarks. Data holds services.LandmarkView
+// items, or with ?fields= the selected fields of each.
+type LandmarkList struct {
+	Data []interface{} `json:"data"`
+	// Meta is a ListMeta, or a CursorListMeta for cursor pagination.
+	Meta interface{} `json:"meta"`
+}
+
+// ListMeta describes a page of an offset paginated list.
+type ListMeta struct {
+	Total int64 `json:"total"`
+	// TotalEstimated is true when Total is the planner's estimate rather
+	// than a count.
+	TotalEstimated bool `json:"total_estimated"`
+	Limit          int  `json:"limit"`
+	Offset         int  `json:"offset"`
+}
+
+// CursorListMeta describes a page of a cursor paginated list. NextCursor
+// is nil on the last page.
+type CursorListMeta struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor" extensions:"x-nullable"`
+}
+
+// MessageResponse is the body of a successful request that returns no
+// resource.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// ClusterList is the landmark clusters of a map view.
+type ClusterList struct {
+	Data []repository.LandmarkCluster `json:"data"`
+	Meta ClusterMeta                  `json:"meta"`
+}
+
+type ClusterMeta struct {
+	BBox string `json:"bbox"`
+	Zoom int    `json:"zoom"`
+	// CellSize is the side of the grid cells, in degrees.
+	CellSize float64 `json:"cell_size"`
+}
+
+// AdminLandmarkList is a page of the admin landmark list.
+type AdminLandmarkList struct {
+	Landmarks []*services.LandmarkView `json:"landmarks"`
+	Total     int64                    `json:"total"`
+	Page      int                      `json:"page"`
+	PerPage   int                      `json:"per_page"`
+}
+
+// SyncResponse is what changed in the catalog since a sync checkpoint.
+type SyncResponse struct {
+	Created []*services.LandmarkView `json:"created"`
+	Updated []*services.LandmarkView `json:"updated"`
+	Deleted []uuid.UUID              `json:"deleted"`
+	Meta    SyncMeta                 `json:"meta"`
+}
+
+// SyncMeta locates a sync page. Checkpoint is the since of the next sync,
+// once NextCursor is nil.
+type SyncMeta struct {
+	Since      *time.Time `json:"since" extensions:"x-nullable"`
+	Checkpoint string     `json:"checkpoint"`
+	Limit      int        `json:"limit"`
+	NextCursor *string    `json:"next_cursor" extensions:"x-nullable"`
+}
@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/apiversion"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// landmarkSerializer renders the landmark responses the handlers build, so
+// handlers produce one representation and ?format= and the API version pick
+// how it is written. Serializers receive the v1 JSON representation, either
+// freshly built or read back from the cache.
+type landmarkSerializer interface {
+	contentType() string
+	landmark(r *http.Request, doc map[string]interface{}) interface{}
+	landmarkList(r *http.Request, list map[string]interface{}) interface{}
+}
+
+// landmarkSerializers are the accepted values of ?format=.
+var landmarkSerializers = map[string]landmarkSerializer{
+	"json":    plainSerializer{},
+	"jsonapi": jsonAPISerializer{},
+	"hal":     halSerializer{},
+	"compact": compactSerializer{},
+}
+
+// checkFormat responds with a 400 and returns false if format is not a known
+// serialization. An empty format is the plain JSON default.
+func checkFormat(w http.ResponseWriter, format string) bool {
+	if format == "" {
+		return true
+	}
+	if _, ok := landmarkSerializers[format]; ok {
+		return true
+	}
+
+	formats := make([]string, 0, len(landmarkSerializers))
+	for name := range landmarkSerializers {
+		formats = append(formats, name)
+	}
+	respondWithUnknownFormat(w, format, formats)
+	return false
+}
+
+func respondWithUnknownFormat(w http.ResponseWriter, format string, allowed []string) {
+	sort.Strings(allowed)
+	respondWithJSON(w, apperrors.CodeUnknownFormat.Status(), ErrorResponse{
+		Error:   fmt.Sprintf("Unknown format: %s", format),
+		Code:    apperrors.CodeUnknownFormat,
+		Allowed: allowed,
+	})
+}
+
+// respondWithLandmark writes a single landmark in the requested format.
+func respondWithLandmark(w http.ResponseWriter, r *http.Request, format string, doc interface{}) {
+	serializer, ok := landmarkSerializers[format]
+	if !ok {
+		serializer = plainSerializer{}
+	}
+	landmark := toJSONMap(doc)
+	addLocalTime(landmark, time.Now())
+	respondWithJSONType(w, http.StatusOK, serializer.contentType(), serializer.landmark(r, landmark))
+}
+
+// addLocalTime adds the current local_time and utc_offset of a landmark
+// with a known timezone. They are computed per response rather than
+// stored, so cached landmarks never show a stale time.
+func addLocalTime(doc map[string]interface{}, now time.Time) {
+	timezone, _ := doc["timezone"].(string)
+	if timezone == "" {
+		return
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return
+	}
+	local := now.In(location)
+	doc["local_time"] = local.Format(time.RFC3339)
+	doc["utc_offset"] = local.Format("-07:00")
+}
+
+// respondWithLandmarkList writes a landmark list, a map with data and meta,
+// in the requested format.
+func respondWithLandmarkList(w http.ResponseWriter, r *http.Request, format string, list interface{}) {
+	serializer, ok := landmarkSerializers[format]
+	if !ok {
+		serializer = plainSerializer{}
+	}
+	respondWithJSONType(w, http.StatusOK, serializer.contentType(), serializer.landmarkList(r, toJSONMap(list)))
+}
+
+// landmarkPath is the path of the landmark with id in the version of r.
+func landmarkPath(r *http.Request, id string) string {
+	return "/api/" + string(apiversion.FromContext(r.Context())) + "/landmarks/" + id
+}
+
+// toJSONMap converts v, a JSON object or a struct encoding to one, to the
+// generic form it has after a JSON round trip, so fresh and cached
+// responses serialize the same way.
+func toJSONMap(v interface{}) map[string]interface{} {
+	if raw, err := json.Marshal(v); err == nil {
+		var doc map[string]interface{}
+		if json.Unmarshal(raw, &doc) == nil && doc != nil {
+			return doc
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// plainSerializer is the default representation. v1 has it as built,
+// minus the image IDs it predates; v2 wraps single landmarks in data and
+// replaces meta with a pagination object that carries the next and previous
+// page links.
+type plainSerializer struct{}
+
+func (plainSerializer) contentType() string { return "application/json" }
+
+func (plainSerializer) landmark(r *http.Request, doc map[string]interface{}) interface{} {
+	if apiversion.FromContext(r.Context()) == apiversion.V1 {
+		removeImageIDs(doc)
+		return doc
+	}
+	return map[string]interface{}{"data": doc}
+}
+
+func (plainSerializer) landmarkList(r *http.Request, list map[string]interface{}) interface{} {
+	if apiversion.FromContext(r.Context()) == apiversion.V1 {
+		items, _ := list["data"].([]interface{})
+		for _, item := range items {
+			if doc, ok := item.(map[string]interface{}); ok {
+				removeImageIDs(doc)
+			}
+		}
+		return list
+	}
+
+	data := list["data"]
+	if data == nil {
+		data = []interface{}{}
+	}
+	pagination := map[string]interface{}{}
+	if meta, ok := list["meta"].(map[string]interface{}); ok {
+		for key, value := range meta {
+			pagination[key] = value
+		}
+	}
+	links := paginationLinks(r, list["meta"])
+	pagination["next"] = nil
+	pagination["prev"] = nil
+	for _, rel := range []string{"next", "prev"} {
+		if href, ok := links[rel]; ok {
+			pagination[rel] = href
+		}
+	}
+
+	return map[string]interface{}{
+		"data":       data,
+		"pagination": pagination,
+	}
+}
+
+// jsonAPISerializer writes JSON:API documents. Landmarks are "landmarks"
+// resources with their images and details as related resources in
+// "included".
+type jsonAPISerializer struct{}
+
+func (jsonAPISerializer) contentType() string { return "application/vnd.api+json" }
+
+func (s jsonAPISerializer) landmark(r *http.Request, doc map[string]interface{}) interface{} {
+	resource, included := s.resource(r, doc)
+	return map[string]interface{}{
+		"data":     resource,
+		"included": included,
+	}
+}
+
+func (s jsonAPISerializer) landmarkList(r *http.Request, list map[string]interface{}) interface{} {
+	items, _ := list["data"].([]interface{})
+	data := make([]interface{}, 0, len(items))
+	included := []interface{}{}
+	for _, item := range items {
+		doc, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		resource, related := s.resource(r, doc)
+		data = append(data, resource)
+		included = append(included, related...)
+	}
+
+	return map[string]interface{}{
+		"data":     data,
+		"included": included,
+		"meta":     list["meta"],
+		"links":    paginationLinks(r, list["meta"]),
+	}
+}
+
+// resource splits a landmark into its resource object and the related
+// resources it references.
+func (jsonAPISerializer) resource(r *http.Request, doc map[string]interface{}) (map[string]interface{}, []interface{}) {
+	id, _ := doc["id"].(string)
+	attributes := make(map[string]interface{}, len(doc))
+	for key, value := range doc {
+		attributes[key] = value
+	}
+	delete(attributes, "id")
+
+	relationships := map[string]interface{}{}
+	included := []interface{}{}
+
+	if images, ok := attributes["images"].([]interface{}); ok {
+		delete(attributes, "images")
+		identifiers := make([]interface{}, 0, len(images))
+		for _, item := range images {
+			image, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			imageAttributes := make(map[string]interface{}, len(image))
+			for key, value := range image {
+				imageAttributes[key] = value
+			}
+			delete(imageAttributes, "id")
+
+			identifier := map[string]interface{}{"type": "landmark-images", "id": image["id"]}
+			identifiers = append(identifiers, identifier)
+			included = append(included, map[string]interface{}{
+				"type":       "landmark-images",
+				"id":         image["id"],
+				"attributes": imageAttributes,
+			})
+		}
+		relationships["images"] = map[string]interface{}{"data": identifiers}
+	}
+
+	if details := takeDetails(attributes); details != nil {
+		identifier := map[string]interface{}{"type": "landmark-details", "id": id}
+		relationships["details"] = map[string]interface{}{"data": identifier}
+		included = append(included, map[string]interface{}{
+			"type":       "landmark-details",
+			"id":         id,
+			"attributes": details,
+		})
+	}
+
+	resource := map[string]interface{}{
+		"type":       "landmarks",
+		"id":         id,
+		"attributes": attributes,
+		"links":      map[string]string{"self": landmarkPath(r, id)},
+	}
+	if len(relationships) > 0 {
+		resource["relationships"] = relationships
+	}
+	return resource, included
+}
+
+// halSerializer writes HAL documents: links under _links, images and
+// details under _embedded.
+type halSerializer struct{}
+
+func (halSerializer) contentType() string { return "application/hal+json" }
+
+func (halSerializer) landmark(r *http.Request, doc map[string]interface{}) interface{} {
+	id, _ := doc["id"].(string)
+	resource := make(map[string]interface{}, len(doc)+2)
+	for key, value := range doc {
+		resource[key] = value
+	}
+
+	links := map[string]interface{}{"self": map[string]string{"href": landmarkPath(r, id)}}
+	embedded := map[string]interface{}{}
+
+	if images, ok := resource["images"].([]interface{}); ok {
+		delete(resource, "images")
+		imageLinks := make([]interface{}, 0, len(images))
+		for _, item := range images {
+			if image, ok := item.(map[string]interface{}); ok {
+				imageLinks = append(imageLinks, map[string]interface{}{"href": image["image_url"]})
+			}
+		}
+		links["images"] = imageLinks
+		embedded["images"] = images
+	}
+	if details := takeDetails(resource); details != nil {
+		embedded["details"] = details
+	}
+
+	resource["_links"] = links
+	if len(embedded) > 0 {
+		resource["_embedded"] = embedded
+	}
+	return resource
+}
+
+func (s halSerializer) landmarkList(r *http.Request, list map[string]interface{}) interface{} {
+	items, _ := list["data"].([]interface{})
+	landmarks := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if doc, ok := item.(map[string]interface{}); ok {
+			landmarks = append(landmarks, s.landmark(r, doc))
+		}
+	}
+
+	links := map[string]interface{}{}
+	for rel, href := range paginationLinks(r, list["meta"]) {
+		links[rel] = map[string]string{"href": href}
+	}
+
+	return map[string]interface{}{
+		"_links":    links,
+		"_embedded": map[string]interface{}{"landmarks": landmarks},
+		"meta":      list["meta"],
+	}
+}
+
+// removeImageIDs drops the IDs of the images of doc.
+func removeImageIDs(doc map[string]interface{}) {
+	images, _ := doc["images"].([]interface{})
+	for _, item := range images {
+		if image, ok := item.(map[string]interface{}); ok {
+			delete(image, "id")
+		}
+	}
+}
+
+// takeDetails removes the detail fields from doc and returns them, or nil
+// if doc has none.
+func takeDetails(doc map[string]interface{}) map[string]interface{} {
+	var details map[string]interface{}
+	for _, field := range services.LandmarkDetailFields {
+		value, ok := doc[field]
+		if !ok {
+			continue
+		}
+		if details == nil {
+			details = map[string]interface{}{}
+		}
+		details[field] = value
+		delete(doc, field)
+	}
+	return details
+}
+
+// paginationLinks returns the self, next and prev URLs of a list page, from
+// meta.next_cursor when cursor paginating and from offset and limit
+// otherwise. Only GET lists get next and prev links.
+func paginationLinks(r *http.Request, meta interface{}) map[string]string {
+	links := map[string]string{"self": r.URL.RequestURI()}
+	if r.Method != http.MethodGet {
+		// Searches page through their request body, which a link cannot carry
+		return links
+	}
+	m, _ := meta.(map[string]interface{})
+
+	withQuery := func(key, value string) string {
+		u := *r.URL
+		query := u.Query()
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	if cursor, ok := m["next_cursor"].(string); ok && cursor != "" {
+		links["next"] = withQuery("cursor", cursor)
+		return links
+	}
+
+	limit, _ := m["limit"].(float64)
+	offset, _ := m["offset"].(float64)
+	total, _ := m["total"].(float64)
+	if limit <= 0 {
+		return links
+	}
+	if offset+limit < total {
+		links["next"] = withQuery("offset", strconv.Itoa(int(offset+limit)))
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links["prev"] = withQuery("offset", strconv.Itoa(int(prev)))
+	}
+	return links
+}
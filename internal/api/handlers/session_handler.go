@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SessionHandler lets users see where they are logged in and log out
+// sessions they do not recognise.
+type SessionHandler struct {
+	sessionService services.SessionService
+}
+
+func NewSessionHandler(sessionService services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// ListSessions returns the user's active sessions, newest first.
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	sessions, err := h.sessionService.List(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error listing sessions for user %s: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list sessions")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"sessions": sessions})
+}
+
+// RevokeSession revokes one of the user's sessions; its token stops working
+// immediately.
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	if err := h.sessionService.Revoke(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			respondWithCode(w, apperrors.CodeSessionNotFound, "Session not found")
+			return
+		}
+		log.Printf("Error revoking session %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"landmark-api/internal/health"
+	"net/http"
+)
+
+type StatusHandler struct {
+	monitor       *health.Monitor
+	uptimeService *UptimeService
+}
+
+func NewStatusHandler(monitor *health.Monitor, uptimeService *UptimeService) *StatusHandler {
+	return &StatusHandler{
+		monitor:       monitor,
+		uptimeService: uptimeService,
+	}
+}
+
+// GetStatus godoc
+// @Summary Get service status
+// @Description Get the status and check latency of each dependency (database, cache, storage, payments, weather provider) and recent incidents, for the status page
+// @Tags status
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /status [get]
+func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	report := h.monitor.Report()
+	uptime := h.uptimeService.GetUptimeData()
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     report.Status,
+		"uptime":     uptime.Uptime,
+		"components": report.Components,
+		"incidents":  report.Incidents,
+	})
+}
@@ -6,48 +6,71 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/logger"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
 	"landmark-api/internal/services"
 	"log"
 	"net/http"
 	"os"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/stripe/stripe-go/v72"
 	"github.com/stripe/stripe-go/v72/checkout/session"
 	"github.com/stripe/stripe-go/v72/invoice"
 	"github.com/stripe/stripe-go/v72/sub"
+	"github.com/stripe/stripe-go/v72/webhook"
 )
 
 type StripeHandler struct {
 	authService   services.AuthService
 	subRepo       repository.SubscriptionRepository
 	userRepo      repository.UserRepository
+	creditRepo    repository.RequestCreditRepository
+	eventRepo     repository.StripeEventRepository
 	apiKeyService services.APIKeyService
+	billingConfig *config.BillingConfig
+	stripeConfig  *config.StripeConfig
+	notifications services.NotificationService
+	cache         services.CacheService
 }
 
-func NewStripeHandler(auth services.AuthService, subRepo repository.SubscriptionRepository, userRepo repository.UserRepository, apiKeyService services.APIKeyService) *StripeHandler {
+func NewStripeHandler(auth services.AuthService, subRepo repository.SubscriptionRepository, userRepo repository.UserRepository, creditRepo repository.RequestCreditRepository, eventRepo repository.StripeEventRepository, apiKeyService services.APIKeyService, billingConfig *config.BillingConfig, stripeConfig *config.StripeConfig, notifications services.NotificationService, cache services.CacheService) *StripeHandler {
 	return &StripeHandler{
 		authService:   auth,
 		subRepo:       subRepo,
 		userRepo:      userRepo,
+		creditRepo:    creditRepo,
+		eventRepo:     eventRepo,
 		apiKeyService: apiKeyService,
+		billingConfig: billingConfig,
+		stripeConfig:  stripeConfig,
+		notifications: notifications,
+		cache:         cache,
 	}
 }
 
 const (
-	PlanTypeFree    = "free"
-	PlanTypeMonthly = "monthly"
-	PlanTypeAnnual  = "annual"
+	PlanTypeFree       = "free"
+	PlanTypeMonthly    = "monthly"
+	PlanTypeAnnual     = "annual"
+	PlanTypeEnterprise = "enterprise"
 
 	ErrUserNotFound    = "user not found"
 	ErrNoStripeID      = "user doesn't have a Stripe ID"
 	ErrInvalidPlanType = "invalid plan type"
 	ErrCreateCheckout  = "error creating checkout session"
 	ErrNoPriceID       = "no price ID found for the selected plan"
+	ErrNoStripeSub     = "no Stripe subscription to change, use checkout instead"
+	ErrSamePlan        = "subscription is already on the selected plan"
+	ErrChangePlan      = "error changing subscription plan"
+	ErrCancelPlan      = "error updating subscription cancellation"
+	ErrUnknownPack     = "unknown request pack"
 )
 
 func (h *StripeHandler) HandleCreateCheckOut(w http.ResponseWriter, r *http.Request) {
@@ -77,7 +100,13 @@ func (h *StripeHandler) HandleCreateCheckOut(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	sessionID, err := h.createStripeCheckoutSession(user.StripeID, priceID)
+	trialDays, err := h.trialDaysForCheckout(r.Context(), user.ID, req.PlanType)
+	if err != nil {
+		http.Error(w, ErrCreateCheckout, http.StatusInternalServerError)
+		return
+	}
+
+	sessionID, err := h.createStripeCheckoutSession(r.Context(), user.StripeID, priceID, trialDays)
 	if err != nil {
 		http.Error(w, ErrCreateCheckout, http.StatusInternalServerError)
 		return
@@ -90,18 +119,42 @@ func (h *StripeHandler) HandleCreateCheckOut(w http.ResponseWriter, r *http.Requ
 func (h *StripeHandler) getPriceIDForPlan(planType string) (string, error) {
 	switch planType {
 	case PlanTypeFree:
-		return os.Getenv("STRIPE_MONTHLY_FREE_PRICE_ID"), nil
+		return h.stripeConfig.FreePriceID, nil
 	case PlanTypeMonthly:
-		return os.Getenv("STRIPE_MONTHLY_PRICE_ID"), nil
+		return h.stripeConfig.MonthlyPriceID, nil
 	case PlanTypeAnnual:
-		return os.Getenv("STRIPE_ANNUAL_PRICE_ID"), nil
+		return h.stripeConfig.AnnualPriceID, nil
+	case PlanTypeEnterprise:
+		return h.stripeConfig.EnterprisePriceID, nil
 	default:
 		return "", errors.New(ErrInvalidPlanType)
 	}
 }
 
-func (h *StripeHandler) createStripeCheckoutSession(customerID, priceID string) (string, error) {
+// trialDaysForCheckout returns the free trial length for a checkout. Only Pro
+// plans have trials, and each user gets at most one.
+func (h *StripeHandler) trialDaysForCheckout(ctx context.Context, userID uuid.UUID, planType string) (int64, error) {
+	if planType != PlanTypeMonthly && planType != PlanTypeAnnual {
+		return 0, nil
+	}
+	if h.billingConfig.ProTrialDays <= 0 {
+		return 0, nil
+	}
+
+	usedTrial, err := h.subRepo.HasUsedTrial(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if usedTrial {
+		return 0, nil
+	}
+
+	return h.billingConfig.ProTrialDays, nil
+}
+
+func (h *StripeHandler) createStripeCheckoutSession(ctx context.Context, customerID, priceID string, trialDays int64) (string, error) {
 	params := &stripe.CheckoutSessionParams{
+		Params:   stripe.Params{Context: ctx},
 		Customer: stripe.String(customerID),
 		LineItems: []*stripe.CheckoutSessionLineItemParams{
 			{
@@ -114,7 +167,7 @@ func (h *StripeHandler) createStripeCheckoutSession(customerID, priceID string)
 		CancelURL:  stripe.String("https://www.landmark-api.com/cancel"),
 	}
 
-	if priceID == os.Getenv("STRIPE_MONTHLY_FREE_PRICE_ID") {
+	if priceID == h.stripeConfig.FreePriceID {
 		params.Discounts = []*stripe.CheckoutSessionDiscountParams{
 			{
 				Coupon: stripe.String("GMBDmApc"),
@@ -124,6 +177,12 @@ func (h *StripeHandler) createStripeCheckoutSession(customerID, priceID string)
 		params.PaymentMethodTypes = stripe.StringSlice([]string{"card"})
 	}
 
+	if trialDays > 0 {
+		params.SubscriptionData = &stripe.CheckoutSessionSubscriptionDataParams{
+			TrialPeriodDays: stripe.Int64(trialDays),
+		}
+	}
+
 	s, err := session.New(params)
 	if err != nil {
 		return "", err
@@ -132,6 +191,332 @@ func (h *StripeHandler) createStripeCheckoutSession(customerID, priceID string)
 	return s.ID, nil
 }
 
+// HandleBuyCredits starts a one-off Stripe checkout for a pack of requests
+// on top of the caller's quota. The credits are added once Stripe reports
+// the payment through the webhook.
+func (h *StripeHandler) HandleBuyCredits(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pack string `json:"pack"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusForbidden)
+		return
+	}
+
+	pack, ok := h.billingConfig.CreditPacks[req.Pack]
+	if !ok || pack.PriceID == "" {
+		http.Error(w, ErrUnknownPack, http.StatusBadRequest)
+		return
+	}
+
+	fullUser, err := h.authService.GetUserByID(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, ErrUserNotFound, http.StatusNotFound)
+		return
+	}
+	if fullUser.StripeID == "" {
+		http.Error(w, ErrNoStripeID, http.StatusBadRequest)
+		return
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Params:   stripe.Params{Context: r.Context()},
+		Customer: stripe.String(fullUser.StripeID),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Price:    stripe.String(pack.PriceID),
+				Quantity: stripe.Int64(1),
+			},
+		},
+		Mode:               stripe.String(string(stripe.CheckoutSessionModePayment)),
+		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
+		SuccessURL:         stripe.String("https://www.landmark-api.com/success"),
+		CancelURL:          stripe.String("https://www.landmark-api.com/cancel"),
+	}
+	// The pack is credited from the session's metadata, as sold, even if
+	// the packs are reconfigured before it is paid
+	params.AddMetadata("user_id", user.ID.String())
+	params.AddMetadata("pack", req.Pack)
+	params.AddMetadata("requests", strconv.FormatInt(pack.Requests, 10))
+
+	s, err := session.New(params)
+	if err != nil {
+		log.Printf("Error creating credits checkout for user %s: %v", user.ID, err)
+		http.Error(w, ErrCreateCheckout, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"sessionId": s.ID})
+}
+
+// HandleChangePlan moves the caller's existing Stripe subscription to another
+// plan with proration. The local subscription is updated once Stripe sends
+// the resulting customer.subscription.updated webhook.
+func (h *StripeHandler) HandleChangePlan(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PlanType string `json:"planType"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Subscription not found", http.StatusForbidden)
+		return
+	}
+
+	if subscription.StripePlanID == "" {
+		http.Error(w, ErrNoStripeSub, http.StatusBadRequest)
+		return
+	}
+
+	priceID, err := h.getPriceIDForPlan(req.PlanType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if priceID == "" {
+		http.Error(w, ErrNoPriceID, http.StatusBadRequest)
+		return
+	}
+
+	newPlan, err := h.getPlanTypeFromPriceID(priceID)
+	if err != nil {
+		http.Error(w, ErrNoPriceID, http.StatusBadRequest)
+		return
+	}
+
+	stripeSub, err := sub.Get(subscription.StripePlanID, &stripe.SubscriptionParams{
+		Params: stripe.Params{Context: r.Context()},
+	})
+	if err != nil {
+		http.Error(w, ErrChangePlan, http.StatusInternalServerError)
+		return
+	}
+
+	planItem := h.findPlanItem(stripeSub)
+	if planItem == nil {
+		http.Error(w, ErrNoStripeSub, http.StatusBadRequest)
+		return
+	}
+	if planItem.Price.ID == priceID {
+		http.Error(w, ErrSamePlan, http.StatusConflict)
+		return
+	}
+
+	params := &stripe.SubscriptionParams{
+		Params: stripe.Params{Context: r.Context()},
+		Items: []*stripe.SubscriptionItemsParams{
+			{
+				ID:    stripe.String(planItem.ID),
+				Price: stripe.String(priceID),
+			},
+		},
+		ProrationBehavior: stripe.String(string(stripe.SubscriptionProrationBehaviorCreateProrations)),
+		CancelAtPeriodEnd: stripe.Bool(false),
+	}
+	params.Items = append(params.Items, h.meteredItemChanges(stripeSub, newPlan)...)
+
+	updated, err := sub.Update(stripeSub.ID, params)
+	if err != nil {
+		log.Printf("Error changing plan for subscription %s: %v", stripeSub.ID, err)
+		http.Error(w, ErrChangePlan, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":  "Plan change requested",
+		"planType": string(newPlan),
+		"status":   string(updated.Status),
+	})
+}
+
+// HandleCancelSubscription cancels the caller's subscription at the end of
+// the current billing period. Access is kept until then.
+func (h *StripeHandler) HandleCancelSubscription(w http.ResponseWriter, r *http.Request) {
+	h.setCancelAtPeriodEnd(w, r, true)
+}
+
+// HandleResumeSubscription undoes a pending cancellation before the period ends.
+func (h *StripeHandler) HandleResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	h.setCancelAtPeriodEnd(w, r, false)
+}
+
+func (h *StripeHandler) setCancelAtPeriodEnd(w http.ResponseWriter, r *http.Request, cancel bool) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "User not found", http.StatusForbidden)
+		return
+	}
+
+	subscription, ok := services.SubscriptionFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Subscription not found", http.StatusForbidden)
+		return
+	}
+
+	if subscription.StripePlanID == "" {
+		http.Error(w, ErrNoStripeSub, http.StatusBadRequest)
+		return
+	}
+
+	if subscription.CancelAtPeriodEnd == cancel {
+		message := "Subscription is not scheduled for cancellation"
+		if cancel {
+			message = "Subscription is already scheduled for cancellation"
+		}
+		http.Error(w, message, http.StatusConflict)
+		return
+	}
+
+	updated, err := sub.Update(subscription.StripePlanID, &stripe.SubscriptionParams{
+		Params:            stripe.Params{Context: r.Context()},
+		CancelAtPeriodEnd: stripe.Bool(cancel),
+	})
+	if err != nil {
+		log.Printf("Error updating cancellation for subscription %s: %v", subscription.StripePlanID, err)
+		http.Error(w, ErrCancelPlan, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.subRepo.SetCancelAtPeriodEnd(r.Context(), subscription.ID, cancel); err != nil {
+		log.Printf("Error saving cancellation for subscription %s: %v", subscription.ID, err)
+		http.Error(w, ErrCancelPlan, http.StatusInternalServerError)
+		return
+	}
+
+	periodEnd := services.NotificationDate(time.Unix(updated.CurrentPeriodEnd, 0))
+	if cancel {
+		err = h.notifications.Notify(r.Context(), user.ID, services.NotificationSubscriptionCanceled, map[string]string{"AccessUntil": periodEnd})
+	} else {
+		err = h.notifications.Notify(r.Context(), user.ID, services.NotificationSubscriptionResumed, map[string]string{"RenewsAt": periodEnd})
+	}
+	if err != nil {
+		log.Printf("Error sending subscription confirmation to user %s: %v", user.ID, err)
+	}
+
+	message := "Subscription resumed"
+	if cancel {
+		message = "Subscription will be canceled at the end of the billing period"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":              message,
+		"cancel_at_period_end": updated.CancelAtPeriodEnd,
+		"current_period_end":   periodEnd,
+	})
+}
+
+// resolvePlanType maps the subscription's plan price to a local plan.
+func (h *StripeHandler) resolvePlanType(subscription *stripe.Subscription) (models.SubscriptionPlan, bool) {
+	item := h.findPlanItem(subscription)
+	if item == nil {
+		return "", false
+	}
+
+	planType, err := h.getPlanTypeFromPriceID(item.Price.ID)
+	if err != nil {
+		return "", false
+	}
+	return planType, true
+}
+
+// planRanks orders plans from least to most capable.
+var planRanks = map[models.SubscriptionPlan]int{
+	models.FreePlan:       0,
+	models.ProPlan:        1,
+	models.EnterprisePlan: 2,
+}
+
+// planChangeDirection reports whether going from one plan to the other is
+// an upgrade or a downgrade. A plan it doesn't know is neither, rather than
+// ranking as Free.
+func planChangeDirection(from, to models.SubscriptionPlan) string {
+	fromRank, fromKnown := planRanks[from]
+	toRank, toKnown := planRanks[to]
+	switch {
+	case !fromKnown || !toKnown:
+		return "none"
+	case toRank > fromRank:
+		return "upgrade"
+	case toRank < fromRank:
+		return "downgrade"
+	default:
+		return "none"
+	}
+}
+
+// findPlanItem returns the subscription item that carries the plan price,
+// skipping metered usage items.
+func (h *StripeHandler) findPlanItem(subscription *stripe.Subscription) *stripe.SubscriptionItem {
+	if subscription.Items == nil {
+		return nil
+	}
+	for _, item := range subscription.Items.Data {
+		if item.Price == nil {
+			continue
+		}
+		if _, err := h.getPlanTypeFromPriceID(item.Price.ID); err == nil {
+			return item
+		}
+	}
+	return nil
+}
+
+// meteredItemChanges adds the metered usage item required by the new plan and
+// removes metered items that belong to the old one.
+func (h *StripeHandler) meteredItemChanges(subscription *stripe.Subscription, newPlan models.SubscriptionPlan) []*stripe.SubscriptionItemsParams {
+	var changes []*stripe.SubscriptionItemsParams
+	wantedPriceID := h.billingConfig.MeteredPriceID(newPlan)
+	hasWanted := false
+
+	if subscription.Items != nil {
+		for _, item := range subscription.Items.Data {
+			if item.Price == nil || !h.isMeteredPrice(item.Price.ID) {
+				continue
+			}
+			if item.Price.ID == wantedPriceID {
+				hasWanted = true
+				continue
+			}
+			changes = append(changes, &stripe.SubscriptionItemsParams{
+				ID:         stripe.String(item.ID),
+				Deleted:    stripe.Bool(true),
+				ClearUsage: stripe.Bool(false),
+			})
+		}
+	}
+
+	if wantedPriceID != "" && !hasWanted {
+		changes = append(changes, &stripe.SubscriptionItemsParams{
+			Price: stripe.String(wantedPriceID),
+		})
+	}
+
+	return changes
+}
+
+func (h *StripeHandler) isMeteredPrice(priceID string) bool {
+	for _, meteredPriceID := range h.billingConfig.MeteredPriceIDs {
+		if meteredPriceID != "" && meteredPriceID == priceID {
+			return true
+		}
+	}
+	return false
+}
+
 // Other methods remain unchanged
 
 func (h *StripeHandler) HandleStripeWebhook(w http.ResponseWriter, r *http.Request) {
@@ -144,50 +529,181 @@ func (h *StripeHandler) HandleStripeWebhook(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	event := stripe.Event{}
-
-	if err := json.Unmarshal(payload, &event); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to parse webhook body json: %v\n", err.Error())
+	// Only signed events are trusted, or recorded: an unsigned one could
+	// claim the ID of a real event before Stripe delivers it
+	event, err := webhook.ConstructEvent(payload, r.Header.Get("Stripe-Signature"), h.stripeConfig.WebhookSecret)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error verifying webhook signature: %v\n", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if event.ID == "" {
+		fmt.Fprintf(os.Stderr, "Webhook event has no ID\n")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	claimed, err := h.eventRepo.Claim(r.Context(), &models.StripeEvent{
+		ID:         event.ID,
+		Type:       event.Type,
+		Payload:    payload,
+		ReceivedAt: time.Now(),
+	})
+	if err != nil {
+		// Unrecorded events can't be told apart from duplicates, so Stripe
+		// is left to retry
+		log.Printf("Error recording Stripe event %s: %v", event.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !claimed {
+		log.Printf("Skipping duplicate Stripe event %s (%s)", event.ID, event.Type)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	status, err := h.processEvent(r.Context(), event)
+	if err != nil {
+		log.Printf("Error processing Stripe event %s (%s): %v", event.ID, event.Type, err)
+		if markErr := h.eventRepo.MarkFailed(r.Context(), event.ID, err.Error()); markErr != nil {
+			log.Printf("Error marking Stripe event %s failed: %v", event.ID, markErr)
+		}
+	} else if markErr := h.eventRepo.MarkProcessed(r.Context(), event.ID, time.Now()); markErr != nil {
+		log.Printf("Error marking Stripe event %s processed: %v", event.ID, markErr)
+	}
+	w.WriteHeader(status)
+}
+
+// errUnusableEvent marks an event that a redelivery would fail on the same
+// way, like a subscription to a price this API doesn't sell.
+var errUnusableEvent = errors.New("unusable event")
+
+// eventStatus is the status to answer an event with once acting on it
+// returned err. Failures a retry can fix, like a database error or events
+// arriving out of order, are answered with a 500 so Stripe delivers the
+// event again. Events about a customer this API doesn't know, or otherwise
+// unusable, are acknowledged.
+func eventStatus(err error) int {
+	if err == nil || errors.Is(err, errUnusableEvent) || errors.Is(err, apperrors.ErrNotFound) {
+		return http.StatusOK
+	}
+	return http.StatusInternalServerError
+}
 
+// processEvent acts on a Stripe event and returns the status to answer
+// with, see eventStatus. A failed event is processed again only if Stripe
+// delivers it again.
+func (h *StripeHandler) processEvent(ctx context.Context, event stripe.Event) (int, error) {
 	switch event.Type {
 	case "customer.subscription.created":
-		var subscription stripe.Subscription
-		err := json.Unmarshal(event.Data.Raw, &subscription)
+		var sent stripe.Subscription
+		if err := json.Unmarshal(event.Data.Raw, &sent); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error parsing webhook JSON: %w", err)
+		}
+		subscription, err := retrieveSubscription(ctx, sent.ID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			return http.StatusInternalServerError, err
 		}
-		h.handleSubscriptionCreated(r.Context(), &subscription)
+		if subscription.Customer != nil {
+			defer h.invalidateBillingInfo(ctx, subscription.Customer.ID)
+		}
+		err = h.handleSubscriptionCreated(ctx, subscription)
+		return eventStatus(err), err
 	case "customer.subscription.updated", "customer.subscription.deleted":
-		var subscription stripe.Subscription
-		err := json.Unmarshal(event.Data.Raw, &subscription)
+		var sent stripe.Subscription
+		if err := json.Unmarshal(event.Data.Raw, &sent); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error parsing webhook JSON: %w", err)
+		}
+		subscription, err := retrieveSubscription(ctx, sent.ID)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing webhook JSON: %v\n", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			return http.StatusInternalServerError, err
+		}
+		if subscription.Customer != nil {
+			defer h.invalidateBillingInfo(ctx, subscription.Customer.ID)
+		}
+		err = h.handleSubscriptionUpdated(ctx, *subscription)
+		return eventStatus(err), err
+	case "customer.subscription.trial_will_end":
+		var subscription stripe.Subscription
+		if err := json.Unmarshal(event.Data.Raw, &subscription); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error parsing webhook JSON: %w", err)
+		}
+		err := h.handleTrialWillEnd(ctx, subscription)
+		return eventStatus(err), err
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		var checkout stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Raw, &checkout); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error parsing webhook JSON: %w", err)
+		}
+		// Subscription checkouts are handled through their subscription's
+		// events
+		if checkout.Mode == stripe.CheckoutSessionModePayment {
+			if err := h.handleCreditsPurchased(ctx, checkout.ID); err != nil {
+				// Crediting is idempotent, so Stripe can retry the event
+				err = fmt.Errorf("error crediting checkout session %s: %w", checkout.ID, err)
+				return eventStatus(err), err
+			}
+		}
+	case "invoice.created", "invoice.finalized", "invoice.paid", "invoice.payment_failed", "invoice.voided":
+		var inv stripe.Invoice
+		if err := json.Unmarshal(event.Data.Raw, &inv); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("error parsing webhook JSON: %w", err)
+		}
+		if inv.Customer != nil {
+			h.invalidateBillingInfo(ctx, inv.Customer.ID)
 		}
-		h.handleSubscriptionUpdated(r.Context(), subscription)
 	default:
 		fmt.Fprintf(os.Stderr, "Unhandled event type: %s\n", event.Type)
 	}
-
-	w.WriteHeader(http.StatusOK)
+	return http.StatusOK, nil
 }
 
+const (
+	defaultInvoicePageSize = 10
+	// maxInvoicePageSize is the most invoices Stripe lists at once.
+	maxInvoicePageSize = 100
+)
+
+// BillingInfo is a page of the user's invoices, newest first, and their
+// current Stripe subscription, with only what the dashboard shows.
 type BillingInfo struct {
-	Invoices        []stripe.Invoice     `json:"invoices"`
-	Subscription    *stripe.Subscription `json:"subscription,omitempty"`
+	Invoices        []BillingInvoice     `json:"invoices"`
+	Meta            CursorListMeta       `json:"meta"`
+	Subscription    *BillingSubscription `json:"subscription,omitempty"`
 	NextPaymentDate int64                `json:"next_payment_date,omitempty"`
 }
 
+// BillingInvoice is a Stripe invoice. Amounts are in the currency's
+// smallest unit and dates are Unix timestamps.
+type BillingInvoice struct {
+	ID               string `json:"id"`
+	Number           string `json:"number"`
+	Status           string `json:"status"`
+	AmountDue        int64  `json:"amount_due"`
+	AmountPaid       int64  `json:"amount_paid"`
+	Currency         string `json:"currency"`
+	Created          int64  `json:"created"`
+	PeriodStart      int64  `json:"period_start"`
+	PeriodEnd        int64  `json:"period_end"`
+	HostedInvoiceURL string `json:"hosted_invoice_url,omitempty"`
+	InvoicePDF       string `json:"invoice_pdf,omitempty"`
+}
+
+// BillingSubscription is the user's Stripe subscription.
+type BillingSubscription struct {
+	ID                string `json:"id"`
+	Status            string `json:"status"`
+	CurrentPeriodEnd  int64  `json:"current_period_end"`
+	CancelAtPeriodEnd bool   `json:"cancel_at_period_end"`
+}
+
+// HandleUserBillingInfo returns a page of the user's invoices, limit at a
+// time after the invoice in cursor. Pages are cached for
+// BillingInfoCacheTTL, and dropped when Stripe reports a change to the
+// customer's invoices or subscription.
 func (h *StripeHandler) HandleUserBillingInfo(w http.ResponseWriter, r *http.Request) {
-	tokenString := extractTokenFromHeader(r)
-	user, _, err := h.authService.VerifyToken(tokenString)
-	if err != nil {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
@@ -203,57 +719,113 @@ func (h *StripeHandler) HandleUserBillingInfo(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultInvoicePageSize
+	}
+	if limit > maxInvoicePageSize {
+		limit = maxInvoicePageSize
+	}
+	cursor := query.Get("cursor")
+
+	key := fmt.Sprintf("%s%s:%d:%s", billingInfoCachePrefix, fullUser.StripeID, limit, cursor)
+	if cached, err := h.cache.Get(r.Context(), key); err == nil {
+		var billingInfo BillingInfo
+		if err := json.Unmarshal([]byte(cached), &billingInfo); err == nil {
+			respondWithJSON(w, http.StatusOK, billingInfo)
+			return
+		}
+	}
+
+	billingInfo, err := fetchBillingInfo(r.Context(), fullUser.StripeID, limit, cursor)
+	if err != nil {
+		log.Printf("Error fetching billing info of user %s: %v", user.ID, err)
+		http.Error(w, "Failed to fetch billing info", http.StatusInternalServerError)
+		return
+	}
+	if err := h.cache.Set(r.Context(), key, billingInfo, h.billingConfig.BillingInfoCacheTTL); err != nil {
+		log.Printf("Failed to cache billing info of user %s: %v", user.ID, err)
+	}
+	respondWithJSON(w, http.StatusOK, billingInfo)
+}
+
+// billingInfoCachePrefix starts the keys of cached billing info, which go
+// on with the Stripe customer ID.
+const billingInfoCachePrefix = "billing:info:"
+
+func fetchBillingInfo(ctx context.Context, customerID string, limit int, cursor string) (*BillingInfo, error) {
 	params := &stripe.InvoiceListParams{
-		Customer: &fullUser.StripeID,
+		ListParams: stripe.ListParams{Context: ctx, Limit: stripe.Int64(int64(limit)), Single: true},
+		Customer:   stripe.String(customerID),
+	}
+	if cursor != "" {
+		params.StartingAfter = stripe.String(cursor)
+	}
+	billingInfo := &BillingInfo{
+		Invoices: make([]BillingInvoice, 0, limit),
+		Meta:     CursorListMeta{Limit: limit},
 	}
-	invoices := make([]stripe.Invoice, 0)
 	i := invoice.List(params)
 	for i.Next() {
-		invoices = append(invoices, *i.Invoice())
+		inv := i.Invoice()
+		billingInfo.Invoices = append(billingInfo.Invoices, BillingInvoice{
+			ID:               inv.ID,
+			Number:           inv.Number,
+			Status:           string(inv.Status),
+			AmountDue:        inv.AmountDue,
+			AmountPaid:       inv.AmountPaid,
+			Currency:         string(inv.Currency),
+			Created:          inv.Created,
+			PeriodStart:      inv.PeriodStart,
+			PeriodEnd:        inv.PeriodEnd,
+			HostedInvoiceURL: inv.HostedInvoiceURL,
+			InvoicePDF:       inv.InvoicePDF,
+		})
 	}
 	if err := i.Err(); err != nil {
-		http.Error(w, "Failed to fetch invoices", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to fetch invoices: %w", err)
 	}
-
-	subParams := &stripe.SubscriptionListParams{
-		Customer: fullUser.StripeID,
+	if i.Meta().HasMore && len(billingInfo.Invoices) > 0 {
+		next := billingInfo.Invoices[len(billingInfo.Invoices)-1].ID
+		billingInfo.Meta.NextCursor = &next
 	}
-	subs := sub.List(subParams)
-	var subscription *stripe.Subscription
+
+	subs := sub.List(&stripe.SubscriptionListParams{
+		ListParams: stripe.ListParams{Context: ctx, Limit: stripe.Int64(1), Single: true},
+		Customer:   customerID,
+	})
 	if subs.Next() {
-		subscription = subs.Subscription()
+		subscription := subs.Subscription()
+		billingInfo.Subscription = &BillingSubscription{
+			ID:                subscription.ID,
+			Status:            string(subscription.Status),
+			CurrentPeriodEnd:  subscription.CurrentPeriodEnd,
+			CancelAtPeriodEnd: subscription.CancelAtPeriodEnd,
+		}
+		billingInfo.NextPaymentDate = subscription.CurrentPeriodEnd
 	}
 	if err := subs.Err(); err != nil {
-		http.Error(w, "Failed to fetch subscription", http.StatusInternalServerError)
-		return
-	}
-
-	var nextPaymentDate int64
-	if subscription != nil {
-		nextPaymentDate = subscription.CurrentPeriodEnd
-	}
-
-	billingInfo := BillingInfo{
-		Invoices:        invoices,
-		Subscription:    subscription,
-		NextPaymentDate: nextPaymentDate,
+		return nil, fmt.Errorf("failed to fetch subscription: %w", err)
 	}
+	return billingInfo, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(billingInfo); err != nil {
-		http.Error(w, "Failed to encode billing info", http.StatusInternalServerError)
-		return
+// invalidateBillingInfo drops the customer's cached billing info, so the
+// dashboard sees a change Stripe reported.
+func (h *StripeHandler) invalidateBillingInfo(ctx context.Context, customerID string) {
+	if err := h.cache.DeleteByPattern(ctx, billingInfoCachePrefix+customerID+":*"); err != nil {
+		log.Printf("Failed to invalidate billing info of customer %s: %v", customerID, err)
 	}
 }
 
 func (h *StripeHandler) handleSubscriptionCreated(ctx context.Context, subscription *stripe.Subscription) error {
 	if subscription == nil {
-		return fmt.Errorf("subscription is nil")
+		return fmt.Errorf("%w: subscription is nil", errUnusableEvent)
 	}
 
 	if subscription.Customer == nil {
-		return fmt.Errorf("customer is nil in the subscription")
+		return fmt.Errorf("%w: customer is nil in the subscription", errUnusableEvent)
 	}
 
 	user, err := h.authService.GetUserByStripeCustomerID(ctx, subscription.Customer.ID)
@@ -261,18 +833,13 @@ func (h *StripeHandler) handleSubscriptionCreated(ctx context.Context, subscript
 		return fmt.Errorf("error retrieving user for customer %s: %w", subscription.Customer.ID, err)
 	}
 
-	if len(subscription.Items.Data) == 0 {
-		return fmt.Errorf("no subscription items found for customer %s", subscription.Customer.ID)
+	if subscription.Items == nil || len(subscription.Items.Data) == 0 {
+		return fmt.Errorf("%w: no subscription items found for customer %s", errUnusableEvent, subscription.Customer.ID)
 	}
 
-	priceID := subscription.Items.Data[0].Price.ID
-	if priceID == "" {
-		return fmt.Errorf("price ID is empty for customer %s", subscription.Customer.ID)
-	}
-
-	planType, err := h.getPlanTypeFromPriceID(priceID)
-	if err != nil {
-		return fmt.Errorf("error determining plan type for price ID %s: %w", priceID, err)
+	planType, ok := h.resolvePlanType(subscription)
+	if !ok {
+		return fmt.Errorf("%w: no known plan price on subscription %s for customer %s", errUnusableEvent, subscription.ID, subscription.Customer.ID)
 	}
 
 	subscriptionModel := &models.Subscription{
@@ -282,6 +849,7 @@ func (h *StripeHandler) handleSubscriptionCreated(ctx context.Context, subscript
 		Status:           string(subscription.Status),
 		PlanType:         planType,
 		EndDate:          time.Unix(subscription.CurrentPeriodEnd, 0),
+		TrialEndsAt:      trialEndsAt(subscription),
 	}
 
 	err = h.subRepo.Create(ctx, subscriptionModel)
@@ -325,15 +893,9 @@ func (h *StripeHandler) handleCheckoutSessionCompleted(ctx context.Context, sess
 		return
 	}
 
-	priceID := session.Subscription.Items.Data[0].Price.ID
-	if priceID == "" {
-		log.Printf("Error: Price ID is empty for customer %s", session.Customer.ID)
-		return
-	}
-
-	planType, err := h.getPlanTypeFromPriceID(priceID)
-	if err != nil {
-		log.Printf("Error determining plan type for price ID %s: %v", priceID, err)
+	planType, ok := h.resolvePlanType(session.Subscription)
+	if !ok {
+		log.Printf("Error: no known plan price on subscription %s for customer %s", session.Subscription.ID, session.Customer.ID)
 		return
 	}
 
@@ -344,6 +906,7 @@ func (h *StripeHandler) handleCheckoutSessionCompleted(ctx context.Context, sess
 		Status:           string(session.Subscription.Status),
 		PlanType:         planType,
 		EndDate:          time.Unix(session.Subscription.CurrentPeriodEnd, 0),
+		TrialEndsAt:      trialEndsAt(session.Subscription),
 	}
 
 	err = h.subRepo.Create(ctx, subscription)
@@ -368,62 +931,172 @@ func (h *StripeHandler) handleCheckoutSessionCompleted(ctx context.Context, sess
 }
 func (h *StripeHandler) getPlanTypeFromPriceID(priceID string) (models.SubscriptionPlan, error) {
 	switch priceID {
-	case os.Getenv("STRIPE_MONTHLY_FREE_PRICE_ID"):
+	case h.stripeConfig.FreePriceID:
 		return models.FreePlan, nil
-	case os.Getenv("STRIPE_MONTHLY_PRICE_ID"):
+	case h.stripeConfig.MonthlyPriceID, h.stripeConfig.AnnualPriceID:
 		return models.ProPlan, nil
-	case os.Getenv("STRIPE_ENTERPRISE_PLAN_PRICE_ID"):
+	case h.stripeConfig.EnterprisePriceID:
 		return models.EnterprisePlan, nil
 	default:
 		return "", fmt.Errorf("unknown price ID: %s", priceID)
 	}
 }
 
-func (h *StripeHandler) handleSubscriptionUpdated(ctx context.Context, subscription stripe.Subscription) {
+func (h *StripeHandler) handleSubscriptionUpdated(ctx context.Context, subscription stripe.Subscription) error {
 	// 1. Retrieve the user based on subscription.Customer
 	user, err := h.authService.GetUserByStripeCustomerID(ctx, subscription.Customer.ID)
 	if err != nil {
-		log.Printf("Error retrieving user for customer %s: %v", subscription.Customer.ID, err)
-		return
+		return fmt.Errorf("error retrieving user for customer %s: %w", subscription.Customer.ID, err)
+	}
+
+	// 2. Find the local record mirroring this Stripe subscription
+	existing, err := h.subRepo.GetByStripeSubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving subscription %s for user %s: %w", subscription.ID, user.ID, err)
+	}
+
+	// 3. Resolve the plan from the subscription's prices. Unknown prices
+	// keep the current plan rather than silently changing tiers.
+	planType, ok := h.resolvePlanType(&subscription)
+	if !ok {
+		log.Printf("Warning: no known plan price on subscription %s, keeping plan %s", subscription.ID, existing.PlanType)
+		planType = existing.PlanType
 	}
 
 	updatedSubscription := &models.Subscription{
-		UserID:           user.ID,
-		StripeCustomerID: subscription.Customer.ID,
-		StripePlanID:     subscription.ID,
-		Status:           string(subscription.Status),
-		PlanType:         "PRO",
-		EndDate:          time.Unix(subscription.CurrentPeriodEnd, 0),
+		ID:                existing.ID,
+		UserID:            user.ID,
+		StripeCustomerID:  subscription.Customer.ID,
+		StripePlanID:      subscription.ID,
+		Status:            string(subscription.Status),
+		CancelAtPeriodEnd: subscription.CancelAtPeriodEnd,
+		PlanType:          planType,
+		EndDate:           time.Unix(subscription.CurrentPeriodEnd, 0),
+		TrialEndsAt:       existing.TrialEndsAt,
+	}
+	if trialEnd := trialEndsAt(&subscription); trialEnd != nil {
+		updatedSubscription.TrialEndsAt = trialEnd
 	}
 
 	err = h.subRepo.Update(ctx, updatedSubscription)
 	if err != nil {
-		log.Printf("Error updating subscription for user %s: %v", user.ID, err)
-		return
+		return fmt.Errorf("error updating subscription for user %s: %w", user.ID, err)
 	}
 
-	if subscription.Status == stripe.SubscriptionStatusActive {
+	if planType != existing.PlanType {
+		logger.LogEvent(logrus.InfoLevel, "Subscription plan changed", logrus.Fields{
+			"user_id":         user.ID,
+			"subscription_id": existing.ID,
+			"from_plan":       existing.PlanType,
+			"to_plan":         planType,
+			"direction":       planChangeDirection(existing.PlanType, planType),
+		})
+	}
+
+	if subscription.Status == stripe.SubscriptionStatusActive ||
+		subscription.Status == stripe.SubscriptionStatusTrialing {
 		err = h.userRepo.GrantAccess(ctx, user.ID)
 		if err != nil {
-			log.Printf("Error granting service access to user %s: %v", user.ID, err)
-			return
+			return fmt.Errorf("error granting service access to user %s: %w", user.ID, err)
 		}
 	} else if subscription.Status == stripe.SubscriptionStatusCanceled ||
 		subscription.Status == stripe.SubscriptionStatusUnpaid {
 		err = h.userRepo.RevokeAccess(ctx, user.ID)
 		if err != nil {
-			log.Printf("Error revoking service access from user %s: %v", user.ID, err)
-			return
+			return fmt.Errorf("error revoking service access from user %s: %w", user.ID, err)
 		}
 	}
 
-	fmt.Printf("Subscription updated for customer: %s, status: %s\n", subscription.Customer.ID, subscription.Status)
+	fmt.Printf("Subscription updated for customer: %s, status: %s, plan: %s\n", subscription.Customer.ID, subscription.Status, planType)
+	return nil
+}
+
+// retrieveSubscription reads a subscription back from Stripe. Events can
+// arrive late or out of order, so the plan and status are taken from its
+// current state rather than from the event.
+func retrieveSubscription(ctx context.Context, id string) (*stripe.Subscription, error) {
+	subscription, err := sub.Get(id, &stripe.SubscriptionParams{
+		Params: stripe.Params{Context: ctx},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving subscription %s: %w", id, err)
+	}
+	return subscription, nil
+}
+
+// handleCreditsPurchased adds the requests of a paid credits checkout to
+// the buyer's balance, once per session. The session is read back from
+// Stripe, so it is credited as it is now rather than as it was when the
+// event was sent.
+func (h *StripeHandler) handleCreditsPurchased(ctx context.Context, sessionID string) error {
+	checkout, err := session.Get(sessionID, &stripe.CheckoutSessionParams{
+		Params: stripe.Params{Context: ctx},
+	})
+	if err != nil {
+		return fmt.Errorf("error retrieving checkout session: %w", err)
+	}
+	// Delayed payment methods complete the checkout before paying; they
+	// are credited on checkout.session.async_payment_succeeded
+	if checkout.Mode != stripe.CheckoutSessionModePayment || checkout.PaymentStatus != stripe.CheckoutSessionPaymentStatusPaid {
+		return nil
+	}
+
+	userID, err := uuid.Parse(checkout.Metadata["user_id"])
+	if err != nil {
+		return fmt.Errorf("checkout session has no valid user: %w", err)
+	}
+	requests, err := strconv.ParseInt(checkout.Metadata["requests"], 10, 64)
+	if err != nil || requests <= 0 {
+		return fmt.Errorf("checkout session has no valid request count")
+	}
+
+	created, err := h.creditRepo.Create(ctx, &models.RequestCredit{
+		UserID:          userID,
+		StripeSessionID: checkout.ID,
+		Pack:            checkout.Metadata["pack"],
+		Purchased:       requests,
+		Remaining:       requests,
+	})
+	if err != nil {
+		return err
+	}
+	if created {
+		log.Printf("Credited %d requests to user %s for checkout session %s", requests, userID, checkout.ID)
+	}
+	return nil
+}
+
+// handleTrialWillEnd sends the trial reminder. Stripe emits the event
+// three days before a trial ends.
+func (h *StripeHandler) handleTrialWillEnd(ctx context.Context, subscription stripe.Subscription) error {
+	if subscription.Customer == nil {
+		return fmt.Errorf("%w: customer is nil in the trial_will_end event for subscription %s", errUnusableEvent, subscription.ID)
+	}
+
+	user, err := h.authService.GetUserByStripeCustomerID(ctx, subscription.Customer.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving user for customer %s: %w", subscription.Customer.ID, err)
+	}
+
+	if subscription.CancelAtPeriodEnd {
+		return nil
+	}
+
+	err = h.notifications.Notify(ctx, user.ID, services.NotificationTrialEnding, map[string]string{
+		"TrialEndsAt": services.NotificationDate(time.Unix(subscription.TrialEnd, 0)),
+	})
+	if err != nil {
+		return fmt.Errorf("error sending trial reminder to user %s: %w", user.ID, err)
+	}
+	return nil
 }
 
-func extractTokenFromHeader(r *http.Request) string {
-	bearerToken := r.Header.Get("Authorization")
-	if len(strings.Split(bearerToken, " ")) == 2 {
-		return strings.Split(bearerToken, " ")[1]
+// trialEndsAt returns the end of the subscription's trial, or nil when it
+// never had one.
+func trialEndsAt(subscription *stripe.Subscription) *time.Time {
+	if subscription.TrialEnd == 0 {
+		return nil
 	}
-	return ""
+	trialEnd := time.Unix(subscription.TrialEnd, 0)
+	return &trialEnd
 }
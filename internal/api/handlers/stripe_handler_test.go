@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/logger"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+const (
+	freePrice       = "price_free"
+	monthlyPrice    = "price_monthly"
+	annualPrice     = "price_annual"
+	enterprisePrice = "price_enterprise"
+	meteredPrice    = "price_metered"
+	unknownPrice    = "price_unknown"
+
+	webhookSecret = "whsec_test"
+)
+
+func newTestStripeHandler() *StripeHandler {
+	return &StripeHandler{
+		stripeConfig: &config.StripeConfig{
+			FreePriceID:       freePrice,
+			MonthlyPriceID:    monthlyPrice,
+			AnnualPriceID:     annualPrice,
+			EnterprisePriceID: enterprisePrice,
+			WebhookSecret:     webhookSecret,
+		},
+	}
+}
+
+// stripeSubscription is a Stripe subscription carrying prices.
+func stripeSubscription(prices ...string) *stripe.Subscription {
+	items := &stripe.SubscriptionItemList{}
+	for _, price := range prices {
+		items.Data = append(items.Data, &stripe.SubscriptionItem{Price: &stripe.Price{ID: price}})
+	}
+	return &stripe.Subscription{
+		ID:       "sub_1",
+		Customer: &stripe.Customer{ID: "cus_1"},
+		Status:   stripe.SubscriptionStatusActive,
+		Items:    items,
+	}
+}
+
+func TestResolvePlanType(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   []string
+		wantPlan models.SubscriptionPlan
+		wantOK   bool
+	}{
+		{"free", []string{freePrice}, models.FreePlan, true},
+		{"monthly", []string{monthlyPrice}, models.ProPlan, true},
+		{"annual", []string{annualPrice}, models.ProPlan, true},
+		{"enterprise", []string{enterprisePrice}, models.EnterprisePlan, true},
+		{"metered item first", []string{meteredPrice, monthlyPrice}, models.ProPlan, true},
+		{"unknown price", []string{unknownPrice}, "", false},
+		{"metered item only", []string{meteredPrice}, "", false},
+		{"no items", nil, "", false},
+	}
+	h := newTestStripeHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, ok := h.resolvePlanType(stripeSubscription(tt.prices...))
+			if plan != tt.wantPlan || ok != tt.wantOK {
+				t.Errorf("resolvePlanType(%v) = %q, %v, want %q, %v", tt.prices, plan, ok, tt.wantPlan, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPlanChangeDirection(t *testing.T) {
+	tests := []struct {
+		from, to models.SubscriptionPlan
+		want     string
+	}{
+		{models.FreePlan, models.ProPlan, "upgrade"},
+		{models.ProPlan, models.EnterprisePlan, "upgrade"},
+		{models.FreePlan, models.EnterprisePlan, "upgrade"},
+		{models.EnterprisePlan, models.ProPlan, "downgrade"},
+		{models.ProPlan, models.FreePlan, "downgrade"},
+		{models.ProPlan, models.ProPlan, "none"},
+		{models.ProPlan, "", "none"},
+		{"", models.ProPlan, "none"},
+	}
+	for _, tt := range tests {
+		if got := planChangeDirection(tt.from, tt.to); got != tt.want {
+			t.Errorf("planChangeDirection(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+type fakeStripeAuth struct {
+	services.AuthService
+	user *models.User
+}
+
+func (a *fakeStripeAuth) GetUserByStripeCustomerID(ctx context.Context, customerID string) (*models.User, error) {
+	return a.user, nil
+}
+
+// fakeSubscriptions holds one subscription and records its updates.
+type fakeSubscriptions struct {
+	repository.SubscriptionRepository
+	existing *models.Subscription
+	updated  *models.Subscription
+}
+
+func (r *fakeSubscriptions) GetByStripeSubscriptionID(ctx context.Context, stripeSubscriptionID string) (*models.Subscription, error) {
+	return r.existing, nil
+}
+
+func (r *fakeSubscriptions) Update(ctx context.Context, subscription *models.Subscription) error {
+	r.updated = subscription
+	return nil
+}
+
+type fakeAccessUsers struct {
+	repository.UserRepository
+	granted bool
+}
+
+func (r *fakeAccessUsers) GrantAccess(ctx context.Context, id uuid.UUID) error {
+	r.granted = true
+	return nil
+}
+
+func TestHandleSubscriptionUpdated(t *testing.T) {
+	tests := []struct {
+		name          string
+		from          models.SubscriptionPlan
+		price         string
+		wantPlan      models.SubscriptionPlan
+		wantDirection string
+	}{
+		{"free to pro", models.FreePlan, monthlyPrice, models.ProPlan, "upgrade"},
+		{"pro to enterprise", models.ProPlan, enterprisePrice, models.EnterprisePlan, "upgrade"},
+		{"enterprise to pro", models.EnterprisePlan, annualPrice, models.ProPlan, "downgrade"},
+		{"pro to free", models.ProPlan, freePrice, models.FreePlan, "downgrade"},
+		{"same plan", models.ProPlan, annualPrice, models.ProPlan, ""},
+		{"unknown price keeps the plan", models.ProPlan, unknownPrice, models.ProPlan, ""},
+	}
+	hook := test.NewLocal(logger.Logger)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+			user := &models.User{ID: uuid.New()}
+			subscriptions := &fakeSubscriptions{existing: &models.Subscription{ID: uuid.New(), UserID: user.ID, PlanType: tt.from}}
+			users := &fakeAccessUsers{}
+			h := newTestStripeHandler()
+			h.authService = &fakeStripeAuth{user: user}
+			h.subRepo = subscriptions
+			h.userRepo = users
+
+			if err := h.handleSubscriptionUpdated(context.Background(), *stripeSubscription(tt.price)); err != nil {
+				t.Fatalf("handleSubscriptionUpdated: %v", err)
+			}
+
+			if subscriptions.updated == nil {
+				t.Fatal("subscription not updated")
+			}
+			if subscriptions.updated.PlanType != tt.wantPlan {
+				t.Errorf("plan = %q, want %q", subscriptions.updated.PlanType, tt.wantPlan)
+			}
+			if !users.granted {
+				t.Error("access of an active subscription not granted")
+			}
+
+			var direction string
+			for _, entry := range hook.AllEntries() {
+				if entry.Message == "Subscription plan changed" {
+					direction, _ = entry.Data["direction"].(string)
+				}
+			}
+			if direction != tt.wantDirection {
+				t.Errorf("logged direction = %q, want %q", direction, tt.wantDirection)
+			}
+		})
+	}
+}
+
+func TestEventStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"processed", nil, http.StatusOK},
+		{"unusable", fmt.Errorf("%w: no known plan price", errUnusableEvent), http.StatusOK},
+		{"unknown customer", fmt.Errorf("error retrieving user: %w", apperrors.ErrNotFound), http.StatusOK},
+		{"subscription not recorded yet", fmt.Errorf("error retrieving subscription: %w", repository.ErrSubscriptionNotFound), http.StatusInternalServerError},
+		{"database error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := eventStatus(tt.err); got != tt.want {
+				t.Errorf("eventStatus(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeEvents records the events claimed and how they ended.
+type fakeEvents struct {
+	repository.StripeEventRepository
+	claimed   []string
+	processed []string
+}
+
+func (r *fakeEvents) Claim(ctx context.Context, event *models.StripeEvent) (bool, error) {
+	r.claimed = append(r.claimed, event.ID)
+	return true, nil
+}
+
+func (r *fakeEvents) MarkProcessed(ctx context.Context, id string, at time.Time) error {
+	r.processed = append(r.processed, id)
+	return nil
+}
+
+// signature is the Stripe-Signature header of payload signed with secret.
+func signature(payload []byte, secret string) string {
+	now := time.Now()
+	return fmt.Sprintf("t=%d,v1=%s", now.Unix(), hex.EncodeToString(webhook.ComputeSignature(now, payload, secret)))
+}
+
+func TestHandleStripeWebhookSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","object":"event","type":"customer.created","data":{"object":{}}}`)
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+		wantClaim  bool
+	}{
+		{"signed", signature(payload, webhookSecret), http.StatusOK, true},
+		{"unsigned", "", http.StatusBadRequest, false},
+		{"signed with another secret", signature(payload, "whsec_other"), http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &fakeEvents{}
+			h := newTestStripeHandler()
+			h.eventRepo = events
+
+			req := httptest.NewRequest(http.MethodPost, "/subscription/stripe-webhook", strings.NewReader(string(payload)))
+			if tt.signature != "" {
+				req.Header.Set("Stripe-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			h.HandleStripeWebhook(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if claimed := len(events.claimed) > 0; claimed != tt.wantClaim {
+				t.Errorf("event claimed = %v, want %v", claimed, tt.wantClaim)
+			}
+			if tt.wantClaim && len(events.processed) != 1 {
+				t.Errorf("event marked processed %d times, want 1", len(events.processed))
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,10 +17,11 @@ import (
 
 // Constants for the handler
 const (
-	minSimilarityThreshold = 0.3
-	defaultCacheDuration   = 5 * time.Minute
-	defaultLimit           = 10
-	searchTimeout          = 3 * time.Second
+	defaultCacheDuration  = 5 * time.Minute
+	defaultLimit          = 10
+	searchTimeout         = 3 * time.Second
+	popularityWindow      = 30 * 24 * time.Hour
+	maxRecordedTermLength = 100
 )
 
 // SearchResult represents the basic search result
@@ -27,48 +30,54 @@ type SearchResult struct {
 	Similarity float64 `json:"similarity"`
 }
 
+// Suggestion is a single ranked suggestion with the number of landmarks it
+// matches, so clients can render e.g. "Paris (214 landmarks)".
+type Suggestion struct {
+	Value         string  `json:"value"`
+	LandmarkCount int64   `json:"landmark_count"`
+	Popularity    int64   `json:"-"`
+	Score         float64 `json:"-"`
+}
+
 // SuggestionResponse holds the structured response for different search types
 type SuggestionResponse struct {
-	Results []string `json:"results"`
+	Results []Suggestion `json:"results"`
 }
 
 // SuggestionsHandler handles all suggestion-related requests
 type SuggestionsHandler struct {
-	db           *gorm.DB
-	cacheService CacheService
-	config       *SuggestionsConfig
+	db            *gorm.DB
+	cacheLoader   *services.CacheLoader
+	analyticsRepo repository.SearchAnalyticsRepository
+	config        *SuggestionsConfig
 }
 
 // SuggestionsConfig contains configuration for the suggestions handler
 type SuggestionsConfig struct {
 	MaxResults         int
 	CacheDuration      time.Duration
-	MinSimilarity      float64
 	EnabledSearchTypes []string
 	Weights            SearchWeights
 }
 
 // SearchWeights contains weights for different search methods
 type SearchWeights struct {
-	ExactMatch  float64
-	Trigram     float64
-	Metaphone   float64
-	Levenshtein float64
-}
-
-// CacheService interface defines the required caching methods
-type CacheService interface {
-	Get(ctx context.Context, key string) (string, error)
-	Set(ctx context.Context, key string, value interface{}, duration time.Duration) error
+	ExactMatch    float64
+	Trigram       float64
+	Metaphone     float64
+	Levenshtein   float64
+	LandmarkCount float64
+	Popularity    float64
 }
 
 // NewSuggestionsHandler creates a new instance of SuggestionsHandler
-func NewSuggestionsHandler(db *gorm.DB, cacheService CacheService, config *SuggestionsConfig) (*SuggestionsHandler, error) {
+func NewSuggestionsHandler(db *gorm.DB, cacheLoader *services.CacheLoader, analyticsRepo repository.SearchAnalyticsRepository, config *SuggestionsConfig) (*SuggestionsHandler, error) {
 
 	handler := &SuggestionsHandler{
-		db:           db,
-		cacheService: cacheService,
-		config:       config,
+		db:            db,
+		cacheLoader:   cacheLoader,
+		analyticsRepo: analyticsRepo,
+		config:        config,
 	}
 
 	if err := handler.Initialize(); err != nil {
@@ -91,26 +100,36 @@ func (h *SuggestionsHandler) GetSuggestions(w http.ResponseWriter, r *http.Reque
 		respondWithError(w, http.StatusBadRequest, "Invalid search type")
 		return
 	}
+	if !h.isSearchTypeEnabled(searchType) {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Suggestions for %s are disabled", searchType))
+		return
+	}
 
 	// Handle empty search term
 	if searchTerm == "" {
-		respondWithJSON(w, http.StatusOK, SuggestionResponse{Results: []string{}})
+		respondWithJSON(w, http.StatusOK, h.getEmptyResponse(searchType))
 		return
 	}
 
-	// Perform search
-	results, err := h.searchLandmarks(ctx, searchType, searchTerm)
+	h.recordQuery(ctx, searchType, searchTerm)
+
+	parts := []string{searchType, strings.ToLower(strings.TrimSpace(searchTerm))}
+	response, _, err := services.CachedFetch(ctx, h.cacheLoader, "suggestions", parts, h.cacheDuration(), func(ctx context.Context) (SuggestionResponse, error) {
+		results, err := h.searchLandmarks(ctx, searchType, searchTerm)
+		if err != nil {
+			return SuggestionResponse{}, err
+		}
+		return SuggestionResponse{Results: results}, nil
+	})
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Error performing search")
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, SuggestionResponse{Results: results})
+	respondWithJSON(w, http.StatusOK, response)
 }
 
-func (h *SuggestionsHandler) searchLandmarks(ctx context.Context, searchType, searchTerm string) ([]string, error) {
-	var results []string
-
+func (h *SuggestionsHandler) searchLandmarks(ctx context.Context, searchType, searchTerm string) ([]Suggestion, error) {
 	// Build the search condition based on search type
 	column := getColumnForSearchType(searchType)
 	if column == "" {
@@ -120,21 +139,10 @@ func (h *SuggestionsHandler) searchLandmarks(ctx context.Context, searchType, se
 	// Clean and prepare the search term
 	searchTerm = strings.TrimSpace(searchTerm)
 	if searchTerm == "" {
-		return []string{}, nil
+		return []Suggestion{}, nil
 	}
 
-	// Create a more lenient search pattern
-	searchPattern := "%" + strings.ToLower(searchTerm) + "%"
-
-	// Query with additional logging and error handling
-	query := h.db.WithContext(ctx).
-		Model(&models.Landmark{}).
-		Where(fmt.Sprintf("LOWER(%s) LIKE ?", column), searchPattern).
-		Where("deleted_at IS NULL"). // Add soft delete check if you're using it
-		Order(column).
-		Distinct(column)
-
-	err := query.Pluck(column, &results).Error
+	results, err := h.rankedSuggestions(ctx, column, searchType, "%"+escapeLike(strings.ToLower(searchTerm))+"%")
 	if err != nil {
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
@@ -142,49 +150,147 @@ func (h *SuggestionsHandler) searchLandmarks(ctx context.Context, searchType, se
 	// If no results found, try a more lenient search
 	if len(results) == 0 {
 		// Try searching with each word separately
-		words := strings.Fields(searchTerm)
-		for _, word := range words {
-			pattern := "%" + strings.ToLower(word) + "%"
-			var tempResults []string
-
-			err := h.db.WithContext(ctx).
-				Model(&models.Landmark{}).
-				Where(fmt.Sprintf("LOWER(%s) LIKE ?", column), pattern).
-				Where("deleted_at IS NULL").
-				Order(column).
-				Distinct(column).
-				Pluck(column, &tempResults).
-				Error
-
+		seen := make(map[string]bool)
+		for _, word := range strings.Fields(searchTerm) {
+			wordResults, err := h.rankedSuggestions(ctx, column, searchType, "%"+escapeLike(strings.ToLower(word))+"%")
 			if err != nil {
 				continue
 			}
 
-			results = append(results, tempResults...)
-		}
-
-		// Remove duplicates if any were found in the second pass
-		if len(results) > 0 {
-			seen := make(map[string]bool)
-			unique := make([]string, 0, len(results))
-			for _, result := range results {
-				if !seen[result] {
-					seen[result] = true
-					unique = append(unique, result)
+			for _, result := range wordResults {
+				if !seen[result.Value] {
+					seen[result.Value] = true
+					results = append(results, result)
 				}
 			}
-			results = unique
 		}
+
+		sortSuggestions(results)
 	}
 
-	// Limit results if configured
-	if h.config != nil && h.config.MaxResults > 0 && len(results) > h.config.MaxResults {
-		results = results[:h.config.MaxResults]
+	// Limit results
+	if maxResults := h.maxResults(); len(results) > maxResults {
+		results = results[:maxResults]
 	}
 
 	return results, nil
 }
 
+// likeEscaper escapes the LIKE wildcards, and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match only itself in a LIKE pattern with ESCAPE '\'.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// rankedSuggestions returns distinct values of column matching pattern,
+// ranked by how many landmarks share the value and how often users searched
+// for it recently. Recorded search terms are matched as prefixes, with
+// their wildcards escaped, so a term like "%" doesn't match every value.
+func (h *SuggestionsHandler) rankedSuggestions(ctx context.Context, column, searchType, pattern string) ([]Suggestion, error) {
+	var results []Suggestion
+
+	popularitySince := time.Now().Add(-popularityWindow)
+	query := fmt.Sprintf(`
+		SELECT l.%[1]s AS value,
+			COUNT(*) AS landmark_count,
+			COALESCE((
+				SELECT SUM(sa.count)
+				FROM search_analytics sa
+				WHERE sa.search_type = ?
+					AND sa.day >= ?
+					AND LOWER(l.%[1]s) LIKE replace(replace(replace(sa.term, '\', '\\'), '%%', '\%%'), '_', '\_') || '%%' ESCAPE '\'
+			), 0) AS popularity
+		FROM landmarks l
+		WHERE LOWER(l.%[1]s) LIKE ? ESCAPE '\'
+			AND l.deleted_at IS NULL
+			AND l.status = 'published'
+		GROUP BY l.%[1]s`, column)
+
+	if err := h.db.WithContext(ctx).Raw(query, searchType, popularitySince, pattern).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	countWeight, popularityWeight := h.rankingWeights()
+	for i := range results {
+		results[i].Score = float64(results[i].LandmarkCount)*countWeight + float64(results[i].Popularity)*popularityWeight
+	}
+	sortSuggestions(results)
+
+	return results, nil
+}
+
+func (h *SuggestionsHandler) rankingWeights() (float64, float64) {
+	countWeight, popularityWeight := 1.0, 1.0
+	if h.config != nil {
+		if h.config.Weights.LandmarkCount > 0 {
+			countWeight = h.config.Weights.LandmarkCount
+		}
+		if h.config.Weights.Popularity > 0 {
+			popularityWeight = h.config.Weights.Popularity
+		}
+	}
+	return countWeight, popularityWeight
+}
+
+func (h *SuggestionsHandler) maxResults() int {
+	if h.config != nil && h.config.MaxResults > 0 {
+		return h.config.MaxResults
+	}
+	return defaultLimit
+}
+
+func (h *SuggestionsHandler) cacheDuration() time.Duration {
+	if h.config != nil && h.config.CacheDuration > 0 {
+		return h.config.CacheDuration
+	}
+	return defaultCacheDuration
+}
+
+// isSearchTypeEnabled reports whether suggestions are enabled for searchType.
+// An empty EnabledSearchTypes list enables every valid type.
+func (h *SuggestionsHandler) isSearchTypeEnabled(searchType string) bool {
+	if h.config == nil || len(h.config.EnabledSearchTypes) == 0 {
+		return true
+	}
+	for _, enabled := range h.config.EnabledSearchTypes {
+		if enabled == searchType {
+			return true
+		}
+	}
+	return false
+}
+
+func sortSuggestions(results []Suggestion) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Value < results[j].Value
+	})
+}
+
+// recordQuery stores the normalized search term for popularity ranking.
+// Failures are logged and never affect the response.
+func (h *SuggestionsHandler) recordQuery(ctx context.Context, searchType, searchTerm string) {
+	if h.analyticsRepo == nil {
+		return
+	}
+
+	term := strings.ToLower(strings.TrimSpace(searchTerm))
+	if len(term) < 2 {
+		return
+	}
+	if len(term) > maxRecordedTermLength {
+		term = term[:maxRecordedTermLength]
+	}
+
+	if err := h.analyticsRepo.RecordQuery(ctx, searchType, term); err != nil {
+		log.Printf("Error recording search query: %v", err)
+	}
+}
+
 // Utility functions
 func getColumnForSearchType(searchType string) string {
 	switch searchType {
@@ -213,22 +319,10 @@ func isValidSearchType(searchType string) bool {
 
 func (h *SuggestionsHandler) getEmptyResponse(searchType string) SuggestionResponse {
 	var response SuggestionResponse
-	response.Results = []string{}
+	response.Results = []Suggestion{}
 	return response
 }
 
-func (h *SuggestionsHandler) buildCacheKey(parts ...string) string {
-	return fmt.Sprintf("suggestions:%s", strings.Join(parts, ":"))
-}
-
-func (h *SuggestionsHandler) cacheResponse(ctx context.Context, key string, response SuggestionResponse) error {
-	data, err := json.Marshal(response)
-	if err != nil {
-		return err
-	}
-	return h.cacheService.Set(ctx, key, string(data), h.config.CacheDuration)
-}
-
 // Initialize function for setting up necessary database extensions and indexes
 func (h *SuggestionsHandler) Initialize() error {
 	// Enable PostgreSQL extensions
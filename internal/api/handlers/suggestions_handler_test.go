@@ -0,0 +1,20 @@
+package handlers
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"tower", "tower"},
+		{"100%", `100\%`},
+		{"st_paul", `st\_paul`},
+		{`a\b`, `a\\b`},
+		{`%_\`, `\%\_\\`},
+	}
+	for _, tt := range tests {
+		if got := escapeLike(tt.in); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
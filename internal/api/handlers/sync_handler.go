@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"landmark-api/internal/models"
+	"landmark-api/internal/pagination"
+	"landmark-api/internal/services"
+	"landmark-api/internal/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// syncChangedAt is when a landmark last changed, including its deletion.
+// GREATEST ignores the deleted_at of landmarks that still exist.
+const syncChangedAt = "GREATEST(landmarks.updated_at, landmarks.deleted_at)"
+
+// syncFormats are the accepted values of ?format= on sync.
+var syncFormats = []string{"json", "compact"}
+
+// syncSettleTime keeps changes this recent out of a sync, so a write whose
+// transaction commits after the sync ran is not left behind its checkpoint.
+const syncSettleTime = 5 * time.Second
+
+// SyncLandmarks godoc
+// @Summary Sync landmarks for offline use
+// @Description Get the landmarks created, updated and deleted since a checkpoint, so offline copies can be kept up to date without downloading everything again. Without since, returns every landmark as created. Landmarks that were unpublished since the checkpoint are listed as deleted. Follow meta.next_cursor until it is null, then keep meta.checkpoint as the since of the next sync.
+// @Tags landmarks
+// @Produce json
+// @Param since query string false "meta.checkpoint of the previous sync, an RFC 3339 time"
+// @Param limit query int false "Number of changes per page, at most 100"
+// @Param cursor query string false "meta.next_cursor from the previous page; keep the same since"
+// @Param format query string false "Response format: json (default) or compact"
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/sync [get]
+func (h *LandmarkHandler) SyncLandmarks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var since *time.Time
+	if value := query.Get("since"); value != "" {
+		at, err := parseTimeParam(value)
+		if err != nil {
+			respondWithFieldErrors(w, validation.Errors{{Field: "since", Message: err.Error()}})
+			return
+		}
+		since = &at
+	}
+
+	format := query.Get("format")
+	if format != "" && !models.StringList(syncFormats).Contains(format) {
+		respondWithUnknownFormat(w, format, append([]string(nil), syncFormats...))
+		return
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 || limit > maxCursorPageSize {
+		limit = maxCursorPageSize
+	}
+
+	subscription, ok := services.SubscriptionFromContext(ctx)
+	if !ok {
+		respondWithError(w, http.StatusForbidden, "Subscription not found")
+		return
+	}
+
+	filterHash := pagination.FilterHash(map[string]string{"since": query.Get("since")}, "changed_at", "asc")
+	now := time.Now()
+	checkpoint := now.Add(-syncSettleTime)
+
+	// A first sync only needs what clients should show; later ones also
+	// need what they should remove.
+	db := h.readDB.WithContext(ctx).Model(&models.Landmark{}).Scopes(models.PublishedLandmarks)
+	if since != nil {
+		db = h.readDB.WithContext(ctx).Unscoped().Model(&models.Landmark{}).
+			Where(syncChangedAt+" > ?", *since)
+	}
+
+	if token := query.Get("cursor"); token != "" {
+		cursor, err := h.cursors.Decode(token, filterHash, now)
+		if err != nil {
+			respondWithCursorError(w, err)
+			return
+		}
+		checkpoint = cursor.Snapshot
+
+		changedAt, err := time.Parse(time.RFC3339Nano, cursor.SortValue)
+		if err != nil {
+			respondWithCursorError(w, pagination.ErrInvalidCursor)
+			return
+		}
+		db = db.Where("("+syncChangedAt+", landmarks.id) > (?, ?)", changedAt, cursor.LastID)
+	}
+
+	var landmarks []models.Landmark
+	err := db.Preload("Images").
+		Where(syncChangedAt+" <= ?", checkpoint).
+		Order(syncChangedAt + " ASC, landmarks.id ASC").
+		Limit(limit + 1).
+		Find(&landmarks).Error
+	if err != nil {
+		log.Printf("Error fetching landmark changes: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Error fetching landmark changes")
+		return
+	}
+
+	var nextCursor *string
+	if len(landmarks) > limit {
+		landmarks = landmarks[:limit]
+		last := landmarks[len(landmarks)-1]
+		token := h.cursors.Encode(pagination.Cursor{
+			SortValue:  landmarkChangedAt(&last).Format(time.RFC3339Nano),
+			LastID:     last.ID,
+			FilterHash: filterHash,
+			Snapshot:   checkpoint,
+		})
+		nextCursor = &token
+	}
+
+	response := SyncResponse{
+		Created: []*services.LandmarkView{},
+		Updated: []*services.LandmarkView{},
+		Deleted: []uuid.UUID{},
+		Meta: SyncMeta{
+			Since:      since,
+			Checkpoint: checkpoint.UTC().Format(time.RFC3339Nano),
+			Limit:      limit,
+			NextCursor: nextCursor,
+		},
+	}
+	for i := range landmarks {
+		landmark := &landmarks[i]
+		switch {
+		case landmark.DeletedAt.Valid || landmark.Status != models.LandmarkPublished:
+			response.Deleted = append(response.Deleted, landmark.ID)
+		case since == nil || landmark.CreatedAt.After(*since):
+			response.Created = append(response.Created, h.planSerializer.Landmark(ctx, landmark, subscription.PlanType))
+		default:
+			response.Updated = append(response.Updated, h.planSerializer.Landmark(ctx, landmark, subscription.PlanType))
+		}
+	}
+
+	if format == "compact" {
+		respondWithJSON(w, http.StatusOK, compactSync(response))
+		return
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// compactSync compacts the created and updated landmarks of a sync
+// response the way compactSerializer does a list, with one set of
+// dictionaries for both.
+func compactSync(response SyncResponse) map[string]interface{} {
+	doc := toJSONMap(response)
+	created, _ := doc["created"].([]interface{})
+	updated, _ := doc["updated"].([]interface{})
+	doc["dictionaries"] = compactLandmarks(append(append([]interface{}{}, created...), updated...))
+	return doc
+}
+
+// landmarkChangedAt is syncChangedAt of a loaded landmark.
+func landmarkChangedAt(landmark *models.Landmark) time.Time {
+	if landmark.DeletedAt.Valid && landmark.DeletedAt.Time.After(landmark.UpdatedAt) {
+		return landmark.DeletedAt.Time
+	}
+	return landmark.UpdatedAt
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// statementFormats are the formats a statement can be downloaded in.
+var statementFormats = []string{"json", "csv", "pdf"}
+
+// UsageStatementHandler serves the statements of the user's ended billing
+// periods.
+type UsageStatementHandler struct {
+	statements services.UsageStatementService
+}
+
+func NewUsageStatementHandler(statements services.UsageStatementService) *UsageStatementHandler {
+	return &UsageStatementHandler{statements: statements}
+}
+
+// ListStatements godoc
+// @Summary List usage statements
+// @Description Get the statements of the user's ended billing periods, newest first: the requests made, the plan's quota and the overage past it, and the most requested endpoints.
+// @Tags user
+// @Produce json
+// @Success 200 {array} models.UsageStatement
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/usage/statements [get]
+func (h *UsageStatementHandler) ListStatements(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	statements, err := h.statements.List(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error listing usage statements of user %s: %v", user.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch statements")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, statements)
+}
+
+// GetStatement godoc
+// @Summary Download a usage statement
+// @Description Get one of the user's usage statements as JSON, or download it as CSV or PDF with format.
+// @Tags user
+// @Produce json
+// @Produce text/csv
+// @Produce application/pdf
+// @Param id path string true "Statement ID"
+// @Param format query string false "json (default), csv or pdf"
+// @Success 200 {object} models.UsageStatement
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /user/api/v1/usage/statements/{id} [get]
+func (h *UsageStatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid statement ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "csv", "pdf":
+	default:
+		respondWithUnknownFormat(w, format, statementFormats)
+		return
+	}
+
+	statement, err := h.statements.Get(r.Context(), id, user.ID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			respondWithCode(w, apperrors.CodeNotFound, "Statement not found")
+			return
+		}
+		log.Printf("Error fetching usage statement %s: %v", id, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch statement")
+		return
+	}
+
+	switch format {
+	case "csv":
+		setCSVHeaders(w, statementFilename(statement, "csv"))
+		err = h.statements.WriteCSV(statement, w)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", statementFilename(statement, "pdf")))
+		err = h.statements.WritePDF(statement, w)
+	default:
+		respondWithJSON(w, http.StatusOK, statement)
+	}
+	// The header is already sent, so a failure can only truncate the file
+	if err != nil {
+		log.Printf("Error writing usage statement %s: %v", id, err)
+	}
+}
+
+func statementFilename(statement *models.UsageStatement, extension string) string {
+	return fmt.Sprintf("usage-statement-%s.%s", statement.PeriodStart.Format(auditLogDateLayout), extension)
+}
@@ -0,0 +1,36 @@
+// Package apiversion tracks which version of the public API a request was
+// made to. Every version is served by the same handlers; the version only
+// selects the response schema, so older versions stay byte-compatible while
+// newer ones change shape.
+package apiversion
+
+import "context"
+
+// Version is a public API version, as it appears in the path.
+type Version string
+
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
+)
+
+// Header reports the version that served a response.
+const Header = "X-API-Version"
+
+// All are the versions currently served, oldest first.
+var All = []Version{V1, V2}
+
+type contextKey struct{}
+
+// WithVersion returns a copy of ctx for a request to version.
+func WithVersion(ctx context.Context, version Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, version)
+}
+
+// FromContext returns the version of the request, V1 if none was set.
+func FromContext(ctx context.Context) Version {
+	if version, ok := ctx.Value(contextKey{}).(Version); ok {
+		return version
+	}
+	return V1
+}
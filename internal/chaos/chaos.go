@@ -0,0 +1,178 @@
+// Package chaos injects faults into a running server: added latency, cache
+// errors and database timeouts on a configurable share of requests. It is
+// meant for staging, to check that the API degrades the way we expect.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/logger"
+	"landmark-api/internal/services"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Header is set on responses to requests selected for fault injection.
+const Header = "X-Chaos-Injected"
+
+// ErrInjected wraps every fault produced by this package.
+var ErrInjected = errors.New("chaos: injected fault")
+
+type contextKey struct{}
+
+// Injector decides which requests receive faults and produces them.
+type Injector struct {
+	config *config.ChaosConfig
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+func NewInjector(cfg *config.ChaosConfig) *Injector {
+	return &Injector{
+		config: cfg,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < rate
+}
+
+func (i *Injector) latency() time.Duration {
+	if i.config.MaxLatency <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return time.Duration(i.rand.Int63n(int64(i.config.MaxLatency)))
+}
+
+// selected reports whether faults may be injected for the request ctx
+// belongs to.
+func selected(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	chosen, _ := ctx.Value(contextKey{}).(bool)
+	return chosen
+}
+
+// Middleware selects a share of requests for fault injection and delays
+// them by a random latency up to MaxLatency.
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !i.roll(i.config.RequestRate) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		delay := i.latency()
+		logger.LogEvent(logrus.DebugLevel, "Chaos fault injection selected request", logrus.Fields{
+			"path":    r.URL.Path,
+			"latency": delay.String(),
+		})
+		w.Header().Set(Header, "true")
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, true)))
+	})
+}
+
+// WrapCache returns a cache that fails CacheErrorRate of the calls made for
+// selected requests, as if Redis were unreachable.
+func (i *Injector) WrapCache(cache services.CacheService) services.CacheService {
+	return &faultyCache{next: cache, injector: i}
+}
+
+type faultyCache struct {
+	next     services.CacheService
+	injector *Injector
+}
+
+func (c *faultyCache) fail(ctx context.Context, op string) error {
+	if selected(ctx) && c.injector.roll(c.injector.config.CacheErrorRate) {
+		return fmt.Errorf("%w: cache %s unavailable", ErrInjected, op)
+	}
+	return nil
+}
+
+func (c *faultyCache) Get(ctx context.Context, key string) (string, error) {
+	if err := c.fail(ctx, "get"); err != nil {
+		return "", err
+	}
+	return c.next.Get(ctx, key)
+}
+
+func (c *faultyCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if err := c.fail(ctx, "set"); err != nil {
+		return err
+	}
+	return c.next.Set(ctx, key, value, expiration)
+}
+
+func (c *faultyCache) Delete(ctx context.Context, key string) error {
+	if err := c.fail(ctx, "delete"); err != nil {
+		return err
+	}
+	return c.next.Delete(ctx, key)
+}
+
+func (c *faultyCache) DeleteByPattern(ctx context.Context, pattern string) error {
+	if err := c.fail(ctx, "delete"); err != nil {
+		return err
+	}
+	return c.next.DeleteByPattern(ctx, pattern)
+}
+
+func (c *faultyCache) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	if err := c.fail(ctx, "increment"); err != nil {
+		return 0, err
+	}
+	return c.next.Increment(ctx, key, window)
+}
+
+// RegisterDB installs GORM callbacks that fail DBTimeoutRate of the queries
+// made for selected requests with context.DeadlineExceeded. Only queries
+// that carry the request context are affected.
+func (i *Injector) RegisterDB(db *gorm.DB) error {
+	inject := func(tx *gorm.DB) {
+		if selected(tx.Statement.Context) && i.roll(i.config.DBTimeoutRate) {
+			tx.AddError(fmt.Errorf("%w: %w", ErrInjected, context.DeadlineExceeded))
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("chaos:query", inject); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("chaos:row", inject); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("chaos:raw", inject); err != nil {
+		return err
+	}
+	if err := callbacks.Create().Before("gorm:create").Register("chaos:create", inject); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("chaos:update", inject); err != nil {
+		return err
+	}
+	return callbacks.Delete().Before("gorm:delete").Register("chaos:delete", inject)
+}
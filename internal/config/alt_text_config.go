@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// AltTextConfig selects how alt text is generated for landmark photos:
+// "http" (a self-hosted captioning model at ModelURL) or "labels" (from the
+// labels of the image tagger, which IMAGE_TAGGING_PROVIDER must select).
+// Every Interval up to BatchSize photos without alt text are described;
+// those that failed are retried after RetryAfter. An empty Provider
+// disables generation.
+type AltTextConfig struct {
+	Provider   string
+	ModelURL   string
+	Interval   time.Duration
+	BatchSize  int
+	RetryAfter time.Duration
+	// MaxLabels is how many labels alt text built from labels names
+	MaxLabels int
+}
+
+func NewAltTextConfig() *AltTextConfig {
+	return &AltTextConfig{
+		Provider:   getEnv("ALT_TEXT_PROVIDER", ""),
+		ModelURL:   getEnv("ALT_TEXT_MODEL_URL", ""),
+		Interval:   getEnvDuration("ALT_TEXT_INTERVAL", 10*time.Minute),
+		BatchSize:  getEnvInt("ALT_TEXT_BATCH_SIZE", 50),
+		RetryAfter: getEnvDuration("ALT_TEXT_RETRY_AFTER", 24*time.Hour),
+		MaxLabels:  getEnvInt("ALT_TEXT_MAX_LABELS", 3),
+	}
+}
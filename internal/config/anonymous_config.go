@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// AnonymousConfig controls the anonymous tier, which lets requests without
+// credentials list landmarks' basic info so developers can try the API
+// before signing up. Each client IP may make RateLimit such requests per
+// RateWindow.
+type AnonymousConfig struct {
+	Enabled    bool
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+func NewAnonymousConfig() *AnonymousConfig {
+	return &AnonymousConfig{
+		Enabled:    getEnv("ANONYMOUS_ACCESS_ENABLED", "false") == "true",
+		RateLimit:  getEnvInt("ANONYMOUS_RATE_LIMIT", 30),
+		RateWindow: getEnvDuration("ANONYMOUS_RATE_WINDOW", time.Hour),
+	}
+}
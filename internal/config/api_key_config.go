@@ -0,0 +1,42 @@
+package config
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// APIKeyConfig controls API key rotation and expiry. After a rotation the
+// old key keeps working for RotationGrace. Owners of expiring keys are
+// emailed ExpiryReminderDays days before expiry, checked every
+// ReminderInterval.
+type APIKeyConfig struct {
+	RotationGrace      time.Duration
+	ExpiryReminderDays []int
+	ReminderInterval   time.Duration
+}
+
+func NewAPIKeyConfig() *APIKeyConfig {
+	return &APIKeyConfig{
+		RotationGrace:      getEnvDuration("API_KEY_ROTATION_GRACE", 24*time.Hour),
+		ExpiryReminderDays: parseReminderDays(getEnvList("API_KEY_EXPIRY_REMINDER_DAYS", "14,3")),
+		ReminderInterval:   getEnvDuration("API_KEY_REMINDER_INTERVAL", time.Hour),
+	}
+}
+
+// parseReminderDays reads positive day counts, smallest first. Malformed
+// entries are logged and skipped.
+func parseReminderDays(values []string) []int {
+	var days []int
+	for _, value := range values {
+		day, err := strconv.Atoi(value)
+		if err != nil || day <= 0 {
+			log.Printf("Ignoring invalid API_KEY_EXPIRY_REMINDER_DAYS entry %q", value)
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
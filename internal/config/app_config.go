@@ -0,0 +1,77 @@
+package config
+
+import (
+	"log"
+	"strings"
+)
+
+// Environments recognised by APP_ENV.
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// AppConfig holds the core settings and secrets the API cannot start
+// without.
+type AppConfig struct {
+	Environment string
+	Port        string
+	DatabaseURL string
+	// JWTSecret signs new tokens under the key ID JWTKeyID. To rotate it,
+	// move the old secret to JWTPreviousKeys, so tokens it signed stay
+	// valid until they expire.
+	JWTSecret         string
+	JWTKeyID          string
+	JWTPreviousKeys   []JWTKey
+	SendGridAPIKey    string
+	OpenWeatherAPIKey string
+}
+
+// JWTKey is an HMAC secret and the key ID ("kid" header) of the tokens it
+// signs.
+type JWTKey struct {
+	ID     string
+	Secret string
+}
+
+func NewAppConfig() *AppConfig {
+	return &AppConfig{
+		Environment:       getEnv("APP_ENV", EnvDevelopment),
+		Port:              getEnv("PORT", "5050"),
+		DatabaseURL:       getEnv("DATABASE_URL", ""),
+		JWTSecret:         getEnv("JWT_SECRET", ""),
+		JWTKeyID:          getEnv("JWT_KEY_ID", "default"),
+		JWTPreviousKeys:   parseJWTKeys(getEnv("JWT_PREVIOUS_KEYS", "")),
+		SendGridAPIKey:    getEnv("SENDGRID_API_KEY", ""),
+		OpenWeatherAPIKey: getEnv("OPEN_WEATHER_API_KEY", ""),
+	}
+}
+
+func (c *AppConfig) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// JWTKeys returns the signing key followed by the previous keys.
+func (c *AppConfig) JWTKeys() []JWTKey {
+	return append([]JWTKey{{ID: c.JWTKeyID, Secret: c.JWTSecret}}, c.JWTPreviousKeys...)
+}
+
+// parseJWTKeys reads comma-separated "kid:secret" entries. Malformed entries
+// are logged and skipped.
+func parseJWTKeys(value string) []JWTKey {
+	var keys []JWTKey
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			log.Printf("Ignoring invalid JWT_PREVIOUS_KEYS entry for key %q", id)
+			continue
+		}
+		keys = append(keys, JWTKey{ID: id, Secret: secret})
+	}
+	return keys
+}
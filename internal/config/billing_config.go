@@ -0,0 +1,61 @@
+package config
+
+import (
+	"landmark-api/internal/models"
+	"time"
+)
+
+type BillingConfig struct {
+	// MeteredPriceIDs maps a plan to the Stripe metered price whose
+	// subscription item receives usage records for that plan.
+	MeteredPriceIDs     map[models.SubscriptionPlan]string
+	UsageReportInterval time.Duration
+	// ProTrialDays is the length of the free trial offered on a user's
+	// first Pro checkout. Zero disables trials.
+	ProTrialDays int64
+	// CreditPacks are the request packs users can buy on top of their
+	// plan's quota, by name. Packs without a Stripe price are not sold.
+	CreditPacks map[string]CreditPack
+	// StatementInterval is how often statements are made for the billing
+	// periods that ended, up to StatementLookback ago; older periods are
+	// left without one.
+	StatementInterval time.Duration
+	StatementLookback time.Duration
+	// StatementTopEndpoints is how many of the most requested endpoints a
+	// statement lists.
+	StatementTopEndpoints int
+	// BillingInfoCacheTTL is how long a page of a user's invoices is
+	// cached, rather than fetched from Stripe on every request.
+	BillingInfoCacheTTL time.Duration
+}
+
+// CreditPack is a one-off purchase of requests.
+type CreditPack struct {
+	PriceID  string
+	Requests int64
+}
+
+func NewBillingConfig() *BillingConfig {
+	return &BillingConfig{
+		MeteredPriceIDs: map[models.SubscriptionPlan]string{
+			models.EnterprisePlan: getEnv("STRIPE_ENTERPRISE_METERED_PRICE_ID", ""),
+		},
+		UsageReportInterval: 24 * time.Hour,
+		ProTrialDays:        14,
+		CreditPacks: map[string]CreditPack{
+			"small":  {PriceID: getEnv("STRIPE_CREDITS_SMALL_PRICE_ID", ""), Requests: int64(getEnvInt("CREDITS_SMALL_REQUESTS", 10000))},
+			"medium": {PriceID: getEnv("STRIPE_CREDITS_MEDIUM_PRICE_ID", ""), Requests: int64(getEnvInt("CREDITS_MEDIUM_REQUESTS", 100000))},
+			"large":  {PriceID: getEnv("STRIPE_CREDITS_LARGE_PRICE_ID", ""), Requests: int64(getEnvInt("CREDITS_LARGE_REQUESTS", 1000000))},
+		},
+		StatementInterval:     getEnvDuration("STATEMENT_INTERVAL", time.Hour),
+		StatementLookback:     getEnvDuration("STATEMENT_LOOKBACK", 7*24*time.Hour),
+		StatementTopEndpoints: getEnvInt("STATEMENT_TOP_ENDPOINTS", 5),
+		BillingInfoCacheTTL:   getEnvDuration("BILLING_INFO_CACHE_TTL", time.Minute),
+	}
+}
+
+// MeteredPriceID returns the metered price for a plan, or an empty string
+// when usage for that plan is not billed by Stripe.
+func (c *BillingConfig) MeteredPriceID(plan models.SubscriptionPlan) string {
+	return c.MeteredPriceIDs[plan]
+}
@@ -1,26 +1,100 @@
 package config
 
 import (
+	"landmark-api/internal/models"
 	"os"
 	"time"
 )
 
+const (
+	RedisSingle   = "single"
+	RedisCluster  = "cluster"
+	RedisSentinel = "sentinel"
+)
+
 type CacheConfig struct {
 	RedisHost     string
 	RedisPort     string
 	RedisPassword string
 	RedisDB       int
+	// RedisMode is single, which connects to RedisHost:RedisPort, cluster
+	// or sentinel, which connect through RedisAddrs: the cluster's nodes or
+	// the sentinels watching RedisMasterName.
+	RedisMode             string
+	RedisAddrs            []string
+	RedisMasterName       string
+	RedisSentinelPassword string
+	// While Redis can't be reached the cache keeps up to FallbackSize
+	// values in memory, and Redis is checked for every RetryInterval.
+	FallbackSize  int
+	RetryInterval time.Duration
 	DefaultTTL    time.Duration
+	// PlanTTLs are how long each plan's landmark responses are cached.
+	PlanTTLs map[models.SubscriptionPlan]time.Duration
+	// Enterprise requests with Cache-Control: no-cache skip the cache, up
+	// to BypassLimit of them per BypassWindow.
+	BypassLimit  int
+	BypassWindow time.Duration
+	// StaleFor is how long past their TTL cached responses are still
+	// served while one request rebuilds them.
+	StaleFor time.Duration
+	// Version ends every cached response's key; bump it after changing
+	// what is cached so old values are not served.
+	Version string
+	// TTLJitter is the largest fraction a cached response's TTL is
+	// randomly stretched by.
+	TTLJitter float64
+
+	// The local cache keeps up to LocalSize values of keys starting with
+	// one of LocalPrefixes in memory for LocalTTL, in front of Redis.
+	LocalEnabled  bool
+	LocalSize     int
+	LocalTTL      time.Duration
+	LocalPrefixes []string
+
+	// The warm job caches the WarmTop landmark queries most requested over
+	// the last WarmWindow at startup and every WarmInterval, so they are
+	// cached again soon after a deployment or a flush.
+	WarmEnabled  bool
+	WarmInterval time.Duration
+	WarmWindow   time.Duration
+	WarmTop      int
 }
 
 func NewCacheConfig() *CacheConfig {
-	return &CacheConfig{
-		RedisHost:     getEnv("REDISHOST", "localhost"),
-		RedisPort:     getEnv("REDISPORT", "6379"),
-		RedisPassword: getEnv("REDISPASSWORD", ""),
-		RedisDB:       0,
-		DefaultTTL:    15 * time.Minute,
+	cfg := &CacheConfig{
+		RedisHost:             getEnv("REDISHOST", "localhost"),
+		RedisPort:             getEnv("REDISPORT", "6379"),
+		RedisPassword:         getEnv("REDISPASSWORD", ""),
+		RedisDB:               0,
+		RedisMode:             getEnv("REDIS_MODE", RedisSingle),
+		RedisAddrs:            getEnvList("REDIS_ADDRS", ""),
+		RedisMasterName:       getEnv("REDIS_SENTINEL_MASTER", ""),
+		RedisSentinelPassword: getEnv("REDIS_SENTINEL_PASSWORD", ""),
+		FallbackSize:          getEnvInt("CACHE_FALLBACK_SIZE", 10000),
+		RetryInterval:         getEnvDuration("REDIS_RETRY_INTERVAL", 10*time.Second),
+		DefaultTTL:            getEnvDuration("CACHE_TTL", 15*time.Minute),
+		BypassLimit:           getEnvInt("CACHE_BYPASS_LIMIT", 100),
+		BypassWindow:          getEnvDuration("CACHE_BYPASS_WINDOW", time.Hour),
+		StaleFor:              getEnvDuration("CACHE_STALE_FOR", time.Minute),
+		Version:               getEnv("CACHE_VERSION", "1"),
+		TTLJitter:             getEnvFloat("CACHE_TTL_JITTER", 0.1),
+		LocalEnabled:          getEnv("CACHE_LOCAL_ENABLED", "true") == "true",
+		LocalSize:             getEnvInt("CACHE_LOCAL_SIZE", 1000),
+		LocalTTL:              getEnvDuration("CACHE_LOCAL_TTL", 5*time.Second),
+		LocalPrefixes:         getEnvList("CACHE_LOCAL_PREFIXES", "landmark:id:,landmark:category:,categories:"),
+		WarmEnabled:           getEnv("CACHE_WARM_ENABLED", "true") == "true",
+		WarmInterval:          getEnvDuration("CACHE_WARM_INTERVAL", 10*time.Minute),
+		WarmWindow:            getEnvDuration("CACHE_WARM_WINDOW", 24*time.Hour),
+		WarmTop:               getEnvInt("CACHE_WARM_TOP", 50),
+	}
+	// Plans without a TTL of their own use the default
+	cfg.PlanTTLs = map[models.SubscriptionPlan]time.Duration{
+		models.FreePlan:       getEnvDuration("CACHE_TTL_FREE", cfg.DefaultTTL),
+		models.ProPlan:        getEnvDuration("CACHE_TTL_PRO", cfg.DefaultTTL),
+		models.EnterprisePlan: getEnvDuration("CACHE_TTL_ENTERPRISE", cfg.DefaultTTL),
 	}
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// ChaosConfig controls fault injection, used in staging to exercise failure
+// handling before it is needed in production. RequestRate is the fraction of
+// requests selected for faults; the remaining rates apply to selected
+// requests only.
+type ChaosConfig struct {
+	Enabled        bool
+	RequestRate    float64
+	MaxLatency     time.Duration
+	CacheErrorRate float64
+	DBTimeoutRate  float64
+}
+
+func NewChaosConfig() *ChaosConfig {
+	return &ChaosConfig{
+		Enabled:        getEnv("CHAOS_ENABLED", "false") == "true",
+		RequestRate:    getEnvFloat("CHAOS_REQUEST_RATE", 0.1),
+		MaxLatency:     getEnvDuration("CHAOS_MAX_LATENCY", 2*time.Second),
+		CacheErrorRate: getEnvFloat("CHAOS_CACHE_ERROR_RATE", 0.5),
+		DBTimeoutRate:  getEnvFloat("CHAOS_DB_TIMEOUT_RATE", 0.2),
+	}
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
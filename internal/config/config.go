@@ -0,0 +1,339 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+)
+
+// Config is the typed configuration of the whole API, loaded once at
+// startup.
+type Config struct {
+	App           *AppConfig
+	Database      *DatabaseConfig
+	Stripe        *StripeConfig
+	Storage       *StorageConfig
+	Cache         *CacheConfig
+	RateLimit     *RateLimitConfig
+	Billing       *BillingConfig
+	Server        *ServerConfig
+	Retention     *RetentionConfig
+	RequestLog    *RequestLogConfig
+	Mail          *MailConfig
+	Onboarding    *OnboardingConfig
+	Health        *HealthConfig
+	Chaos         *ChaosConfig
+	Integration   *IntegrationConfig
+	Pagination    *PaginationConfig
+	LoginThrottle *LoginThrottleConfig
+	CORS          *CORSConfig
+	APIKey        *APIKeyConfig
+	Geocoding     *GeocodingConfig
+	Timezone      *TimezoneConfig
+	Enrichment    *EnrichmentConfig
+	Overpass      *OverpassConfig
+	Anonymous     *AnonymousConfig
+	Partner       *PartnerConfig
+	ImageTagging  *ImageTaggingConfig
+	AltText       *AltTextConfig
+}
+
+// Load reads the environment, filling unset variables from .env.<APP_ENV>
+// and then .env. Variables already set in the environment always win, and
+// the per-environment file overrides the shared .env.
+func Load() (*Config, error) {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		if values, err := godotenv.Read(".env"); err == nil {
+			env = values["APP_ENV"]
+		}
+	}
+	if env == "" {
+		env = EnvDevelopment
+	}
+
+	for _, file := range []string{".env." + env, ".env"} {
+		if err := godotenv.Load(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("error loading %s: %w", file, err)
+		}
+	}
+
+	cfg := &Config{
+		App:           NewAppConfig(),
+		Database:      NewDatabaseConfig(),
+		Stripe:        NewStripeConfig(),
+		Storage:       NewStorageConfig(),
+		Cache:         NewCacheConfig(),
+		RateLimit:     NewRateLimitConfig(),
+		Billing:       NewBillingConfig(),
+		Server:        NewServerConfig(),
+		Retention:     NewRetentionConfig(),
+		RequestLog:    NewRequestLogConfig(),
+		Mail:          NewMailConfig(),
+		Onboarding:    NewOnboardingConfig(),
+		Health:        NewHealthConfig(),
+		Chaos:         NewChaosConfig(),
+		Integration:   NewIntegrationConfig(),
+		Pagination:    NewPaginationConfig(),
+		LoginThrottle: NewLoginThrottleConfig(),
+		CORS:          NewCORSConfig(),
+		APIKey:        NewAPIKeyConfig(),
+		Geocoding:     NewGeocodingConfig(),
+		Timezone:      NewTimezoneConfig(),
+		Enrichment:    NewEnrichmentConfig(),
+		Overpass:      NewOverpassConfig(),
+		Anonymous:     NewAnonymousConfig(),
+		Partner:       NewPartnerConfig(),
+		ImageTagging:  NewImageTaggingConfig(),
+		AltText:       NewAltTextConfig(),
+	}
+	cfg.App.Environment = env
+	if cfg.Pagination.CursorSecret == "" {
+		cfg.Pagination.CursorSecret = cfg.App.JWTSecret
+	}
+	return cfg, nil
+}
+
+// Validate reports every missing or invalid setting at once, so a broken
+// deployment fails at startup with the full list.
+func (c *Config) Validate() error {
+	var problems []string
+	require := func(name, value string) {
+		if value == "" {
+			problems = append(problems, name+" is required")
+		}
+	}
+
+	require("DATABASE_URL", c.App.DatabaseURL)
+	require("JWT_SECRET", c.App.JWTSecret)
+	require("JWT_KEY_ID", c.App.JWTKeyID)
+	require("AWS_REGION", c.Storage.Region)
+	require("AWS_BUCKET", c.Storage.Bucket)
+
+	switch c.App.Environment {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		problems = append(problems, fmt.Sprintf("APP_ENV must be %s, %s or %s", EnvDevelopment, EnvStaging, EnvProduction))
+	}
+
+	// Outside production, billing and email may be left unconfigured
+	if c.App.IsProduction() {
+		require("STRIPE_SECRET_KEY", c.Stripe.SecretKey)
+		require("STRIPE_WEBHOOK_SECRET", c.Stripe.WebhookSecret)
+		require("STRIPE_MONTHLY_FREE_PRICE_ID", c.Stripe.FreePriceID)
+		require("STRIPE_MONTHLY_PRICE_ID", c.Stripe.MonthlyPriceID)
+		require("STRIPE_ANNUAL_PRICE_ID", c.Stripe.AnnualPriceID)
+		require("STRIPE_ENTERPRISE_PLAN_PRICE_ID", c.Stripe.EnterprisePriceID)
+		if c.Mail.Driver == MailDriverFile {
+			problems = append(problems, "MAIL_DRIVER must not be file in production")
+		}
+		if c.Chaos.Enabled {
+			problems = append(problems, "CHAOS_ENABLED must be false in production")
+		}
+		if c.Integration.Mode != "live" {
+			problems = append(problems, "INTEGRATION_MODE must be live in production")
+		}
+	}
+
+	switch c.Mail.Driver {
+	case MailDriverSendGrid:
+		if c.App.IsProduction() {
+			require("SENDGRID_API_KEY", c.App.SendGridAPIKey)
+		}
+	case MailDriverSES:
+		require("MAIL_SES_REGION", c.Mail.SESRegion)
+	case MailDriverSMTP:
+		require("MAIL_SMTP_HOST", c.Mail.SMTPHost)
+	case MailDriverFile:
+	default:
+		problems = append(problems, fmt.Sprintf("MAIL_DRIVER must be %s, %s, %s or %s", MailDriverSendGrid, MailDriverSES, MailDriverSMTP, MailDriverFile))
+	}
+	if c.Mail.MaxAttempts < 1 || c.Mail.RetryDelay <= 0 {
+		problems = append(problems, "MAIL_MAX_ATTEMPTS and MAIL_RETRY_DELAY must be positive")
+	}
+
+	switch c.Integration.Mode {
+	case "live", "record", "replay":
+	default:
+		problems = append(problems, "INTEGRATION_MODE must be live, record or replay")
+	}
+	if c.Pagination.CursorTTL <= 0 {
+		problems = append(problems, "CURSOR_TTL must be positive")
+	}
+	if c.Server.HandlerTimeout < 0 || c.Server.MaxQueued < 0 {
+		problems = append(problems, "HANDLER_TIMEOUT and SHED_MAX_QUEUED must not be negative")
+	}
+	if c.Database.MaxOpenConns <= 0 || c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "DB_MAX_OPEN_CONNS must be positive and DB_MAX_IDLE_CONNS between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.QueryTimeout < 0 {
+		problems = append(problems, "DB_QUERY_TIMEOUT must not be negative")
+	}
+	if c.Retention.Interval <= 0 {
+		problems = append(problems, "RETENTION_INTERVAL must be positive")
+	}
+	if c.RateLimit.QuotaWarningPercent < 1 || c.RateLimit.QuotaWarningPercent > 99 {
+		problems = append(problems, "QUOTA_WARNING_PERCENT must be between 1 and 99")
+	}
+	for name, pack := range c.Billing.CreditPacks {
+		if pack.PriceID != "" && pack.Requests <= 0 {
+			problems = append(problems, fmt.Sprintf("CREDITS_%s_REQUESTS must be positive", strings.ToUpper(name)))
+		}
+	}
+	if c.Billing.StatementInterval <= 0 || c.Billing.StatementLookback <= 0 || c.Billing.StatementTopEndpoints <= 0 {
+		problems = append(problems, "STATEMENT_INTERVAL, STATEMENT_LOOKBACK and STATEMENT_TOP_ENDPOINTS must be positive")
+	}
+	for _, id := range c.Partner.TrustedIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			problems = append(problems, fmt.Sprintf("PARTNER_TRUSTED_IDS has an invalid user ID: %s", id))
+		}
+	}
+	if c.Billing.BillingInfoCacheTTL <= 0 {
+		problems = append(problems, "BILLING_INFO_CACHE_TTL must be positive")
+	}
+	for plan, rate := range c.RateLimit.RatePerMinute {
+		if rate < 0 {
+			problems = append(problems, fmt.Sprintf("RATE_LIMIT_PER_MINUTE_%s must not be negative", plan))
+		}
+		if rate > 0 && c.RateLimit.Burst[plan] < 1 {
+			problems = append(problems, fmt.Sprintf("RATE_LIMIT_BURST_%s must be positive", plan))
+		}
+	}
+	if c.RateLimit.IPBurstLimit < 0 {
+		problems = append(problems, "RATE_LIMIT_IP_PER_MINUTE must not be negative")
+	}
+	for plan, limit := range c.RateLimit.Concurrency {
+		if limit < 0 {
+			problems = append(problems, fmt.Sprintf("CONCURRENCY_LIMIT_%s must not be negative", plan))
+		}
+	}
+	switch c.Cache.RedisMode {
+	case RedisSingle:
+	case RedisCluster:
+		if len(c.Cache.RedisAddrs) == 0 {
+			problems = append(problems, "REDIS_ADDRS is required in cluster mode")
+		}
+	case RedisSentinel:
+		if len(c.Cache.RedisAddrs) == 0 {
+			problems = append(problems, "REDIS_ADDRS is required in sentinel mode")
+		}
+		require("REDIS_SENTINEL_MASTER", c.Cache.RedisMasterName)
+	default:
+		problems = append(problems, fmt.Sprintf("REDIS_MODE must be %s, %s or %s", RedisSingle, RedisCluster, RedisSentinel))
+	}
+	if c.Cache.FallbackSize <= 0 || c.Cache.RetryInterval <= 0 {
+		problems = append(problems, "CACHE_FALLBACK_SIZE and REDIS_RETRY_INTERVAL must be positive")
+	}
+	if c.Cache.StaleFor < 0 {
+		problems = append(problems, "CACHE_STALE_FOR must not be negative")
+	}
+	for plan, ttl := range c.Cache.PlanTTLs {
+		if ttl <= 0 {
+			problems = append(problems, fmt.Sprintf("CACHE_TTL_%s must be positive", plan))
+		}
+	}
+	if c.Cache.BypassLimit < 0 || c.Cache.BypassWindow <= 0 {
+		problems = append(problems, "CACHE_BYPASS_LIMIT must not be negative and CACHE_BYPASS_WINDOW must be positive")
+	}
+	if c.Cache.Version == "" {
+		problems = append(problems, "CACHE_VERSION is required")
+	}
+	if c.Cache.TTLJitter < 0 || c.Cache.TTLJitter > 1 {
+		problems = append(problems, "CACHE_TTL_JITTER must be between 0 and 1")
+	}
+	if c.Cache.LocalEnabled && (c.Cache.LocalSize <= 0 || c.Cache.LocalTTL <= 0) {
+		problems = append(problems, "CACHE_LOCAL_SIZE and CACHE_LOCAL_TTL must be positive when CACHE_LOCAL_ENABLED is set")
+	}
+	if c.Cache.WarmEnabled && (c.Cache.WarmInterval <= 0 || c.Cache.WarmWindow <= 0 || c.Cache.WarmTop <= 0) {
+		problems = append(problems, "CACHE_WARM_INTERVAL, CACHE_WARM_WINDOW and CACHE_WARM_TOP must be positive when CACHE_WARM_ENABLED is set")
+	}
+	if c.Onboarding.TipsAfter <= 0 || c.Onboarding.Interval <= 0 {
+		problems = append(problems, "ONBOARDING_TIPS_AFTER and ONBOARDING_INTERVAL must be positive")
+	}
+	if c.RequestLog.CaptureBodies && c.RequestLog.MaxBodyBytes <= 0 {
+		problems = append(problems, "REQUEST_LOG_MAX_BODY_BYTES must be positive when REQUEST_LOG_CAPTURE_BODIES is set")
+	}
+	if c.Retention.DeletedAccounts <= 0 {
+		problems = append(problems, "RETENTION_DELETED_ACCOUNTS must be positive, as deleted accounts must be purged")
+	}
+	for _, origin := range c.CORS.DashboardOrigins {
+		if strings.Contains(origin, "*") {
+			problems = append(problems, "CORS_DASHBOARD_ORIGINS must list origins explicitly, without wildcards")
+			break
+		}
+	}
+	if c.APIKey.RotationGrace < 0 || c.APIKey.ReminderInterval <= 0 {
+		problems = append(problems, "API_KEY_ROTATION_GRACE must not be negative and API_KEY_REMINDER_INTERVAL must be positive")
+	}
+	switch c.Geocoding.Provider {
+	case "nominatim":
+	case "google":
+		require("GEOCODING_GOOGLE_API_KEY", c.Geocoding.GoogleAPIKey)
+	default:
+		problems = append(problems, "GEOCODING_PROVIDER must be nominatim or google")
+	}
+	switch c.Timezone.Provider {
+	case "":
+	case "geonames":
+		require("GEONAMES_USERNAME", c.Timezone.GeoNamesUsername)
+	case "google":
+		require("TIMEZONE_GOOGLE_API_KEY", c.Timezone.GoogleAPIKey)
+	default:
+		problems = append(problems, "TIMEZONE_PROVIDER must be geonames, google or empty")
+	}
+	if c.Timezone.BackfillInterval <= 0 || c.Timezone.BatchSize <= 0 {
+		problems = append(problems, "TIMEZONE_BACKFILL_INTERVAL and TIMEZONE_BATCH_SIZE must be positive")
+	}
+	switch c.ImageTagging.Provider {
+	case "", "rekognition":
+	case "http":
+		require("IMAGE_TAGGING_MODEL_URL", c.ImageTagging.ModelURL)
+	default:
+		problems = append(problems, "IMAGE_TAGGING_PROVIDER must be rekognition, http or empty")
+	}
+	if c.ImageTagging.Provider != "" && (c.ImageTagging.Interval <= 0 || c.ImageTagging.BatchSize <= 0 || c.ImageTagging.MaxTags <= 0 || c.ImageTagging.MaxImageBytes <= 0) {
+		problems = append(problems, "IMAGE_TAGGING_INTERVAL, IMAGE_TAGGING_BATCH_SIZE, IMAGE_TAGGING_MAX_TAGS and IMAGE_TAGGING_MAX_IMAGE_BYTES must be positive")
+	}
+	if c.ImageTagging.MinConfidence < 0 || c.ImageTagging.MinConfidence > 1 || c.ImageTagging.MinRelevance < 0 || c.ImageTagging.MinRelevance > 1 {
+		problems = append(problems, "IMAGE_TAGGING_MIN_CONFIDENCE and IMAGE_TAGGING_MIN_RELEVANCE must be between 0 and 1")
+	}
+	switch c.AltText.Provider {
+	case "":
+	case "http":
+		require("ALT_TEXT_MODEL_URL", c.AltText.ModelURL)
+	case "labels":
+		if c.ImageTagging.Provider == "" {
+			problems = append(problems, "ALT_TEXT_PROVIDER labels requires IMAGE_TAGGING_PROVIDER")
+		}
+	default:
+		problems = append(problems, "ALT_TEXT_PROVIDER must be http, labels or empty")
+	}
+	if c.AltText.Provider != "" && (c.AltText.Interval <= 0 || c.AltText.BatchSize <= 0 || c.AltText.RetryAfter <= 0 || c.AltText.MaxLabels <= 0) {
+		problems = append(problems, "ALT_TEXT_INTERVAL, ALT_TEXT_BATCH_SIZE, ALT_TEXT_RETRY_AFTER and ALT_TEXT_MAX_LABELS must be positive")
+	}
+	if c.Enrichment.Enabled && (c.Enrichment.Interval <= 0 || c.Enrichment.BatchSize <= 0 || c.Enrichment.MatchRadiusKm <= 0) {
+		problems = append(problems, "ENRICHMENT_INTERVAL, ENRICHMENT_BATCH_SIZE and ENRICHMENT_MATCH_RADIUS_KM must be positive")
+	}
+	if c.Overpass.MaxArea <= 0 || c.Overpass.MaxResults <= 0 || c.Overpass.QueryTimeout <= 0 {
+		problems = append(problems, "OVERPASS_MAX_AREA, OVERPASS_MAX_RESULTS and OVERPASS_QUERY_TIMEOUT must be positive")
+	}
+	if c.Anonymous.Enabled && (c.Anonymous.RateLimit <= 0 || c.Anonymous.RateWindow <= 0) {
+		problems = append(problems, "ANONYMOUS_RATE_LIMIT and ANONYMOUS_RATE_WINDOW must be positive")
+	}
+	if c.LoginThrottle.MaxAccountFailures <= 0 || c.LoginThrottle.MaxIPFailures <= 0 {
+		problems = append(problems, "LOGIN_MAX_ACCOUNT_FAILURES and LOGIN_MAX_IP_FAILURES must be positive")
+	}
+	if c.LoginThrottle.FailureWindow <= 0 || c.LoginThrottle.LockoutDuration <= 0 {
+		problems = append(problems, "LOGIN_FAILURE_WINDOW and LOGIN_LOCKOUT_DURATION must be positive")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
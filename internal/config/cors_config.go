@@ -0,0 +1,34 @@
+package config
+
+import "strings"
+
+// CORSConfig holds the cross-origin policy. AllowedOrigins may call the
+// API without credentials; "*" allows any origin. DashboardOrigins, which
+// must be listed explicitly, may also send cookies and auth headers. Set
+// different values per environment in .env.<APP_ENV>.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	DashboardOrigins []string
+	AllowedHeaders   []string
+	MaxAge           int
+}
+
+func NewCORSConfig() *CORSConfig {
+	return &CORSConfig{
+		AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", "*"),
+		DashboardOrigins: getEnvList("CORS_DASHBOARD_ORIGINS", ""),
+		AllowedHeaders:   getEnvList("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-CSRF-Token,X-API-Key,X-Signature,X-Signature-Timestamp,traceparent,tracestate"),
+		MaxAge:           getEnvInt("CORS_MAX_AGE", 300),
+	}
+}
+
+// getEnvList reads a comma-separated list, dropping empty entries.
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
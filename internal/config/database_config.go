@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// DatabaseConfig holds the connection pool, query deadline and replica
+// settings. The primary URL is part of AppConfig.
+type DatabaseConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// QueryTimeout bounds every query that does not set its own deadline;
+	// 0 disables it.
+	QueryTimeout time.Duration
+	// ReplicaURLs are read replicas for queries that tolerate replication
+	// lag. Empty means all queries go to the primary.
+	ReplicaURLs []string
+}
+
+func NewDatabaseConfig() *DatabaseConfig {
+	return &DatabaseConfig{
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 25),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+		ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 0),
+		QueryTimeout:    getEnvDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+		ReplicaURLs:     getEnvList("DATABASE_REPLICA_URLS", ""),
+	}
+}
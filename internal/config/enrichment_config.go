@@ -0,0 +1,34 @@
+package config
+
+import "time"
+
+// EnrichmentConfig controls the job that matches landmarks to Wikidata
+// entities. Every Interval up to BatchSize landmarks that were never
+// enriched, or were enriched more than RefreshAfter ago, are looked up. An
+// entity matches when it has the landmark's name and lies within
+// MatchRadiusKm of it.
+type EnrichmentConfig struct {
+	Enabled       bool
+	Interval      time.Duration
+	BatchSize     int
+	RefreshAfter  time.Duration
+	MatchRadiusKm float64
+	// Language selects the Wikipedia edition summaries come from and the
+	// language labels are searched in
+	Language string
+	// UserAgent identifies the API to Wikimedia, whose usage policy
+	// requires it
+	UserAgent string
+}
+
+func NewEnrichmentConfig() *EnrichmentConfig {
+	return &EnrichmentConfig{
+		Enabled:       getEnv("ENRICHMENT_ENABLED", "false") == "true",
+		Interval:      getEnvDuration("ENRICHMENT_INTERVAL", time.Hour),
+		BatchSize:     getEnvInt("ENRICHMENT_BATCH_SIZE", 50),
+		RefreshAfter:  getEnvDuration("ENRICHMENT_REFRESH_AFTER", 30*24*time.Hour),
+		MatchRadiusKm: getEnvFloat("ENRICHMENT_MATCH_RADIUS_KM", 1),
+		Language:      getEnv("ENRICHMENT_LANGUAGE", "en"),
+		UserAgent:     getEnv("ENRICHMENT_USER_AGENT", "landmark-api"),
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"landmark-api/internal/models"
+	"time"
+)
+
+// GeocodingConfig selects the provider behind the geocoding endpoints.
+// Results are cached for CacheTTL, and each plan may make DailyQuotas
+// requests per user per day (-1 for no limit).
+type GeocodingConfig struct {
+	Provider     string
+	NominatimURL string
+	// UserAgent identifies the API to Nominatim, whose usage policy
+	// requires it
+	UserAgent    string
+	GoogleAPIKey string
+	CacheTTL     time.Duration
+	DailyQuotas  map[models.SubscriptionPlan]int
+}
+
+func NewGeocodingConfig() *GeocodingConfig {
+	return &GeocodingConfig{
+		Provider:     getEnv("GEOCODING_PROVIDER", "nominatim"),
+		NominatimURL: getEnv("GEOCODING_NOMINATIM_URL", "https://nominatim.openstreetmap.org"),
+		UserAgent:    getEnv("GEOCODING_USER_AGENT", "landmark-api"),
+		GoogleAPIKey: getEnv("GEOCODING_GOOGLE_API_KEY", ""),
+		CacheTTL:     getEnvDuration("GEOCODING_CACHE_TTL", 24*time.Hour),
+		DailyQuotas: map[models.SubscriptionPlan]int{
+			models.FreePlan:       getEnvInt("GEOCODING_DAILY_QUOTA_FREE", 100),
+			models.ProPlan:        getEnvInt("GEOCODING_DAILY_QUOTA_PRO", 5000),
+			models.EnterprisePlan: getEnvInt("GEOCODING_DAILY_QUOTA_ENTERPRISE", -1),
+		},
+	}
+}
+
+// DailyQuota returns how many geocoding requests a plan may make per day,
+// or -1 when it is unlimited.
+func (c *GeocodingConfig) DailyQuota(plan models.SubscriptionPlan) int {
+	if quota, ok := c.DailyQuotas[plan]; ok {
+		return quota
+	}
+	return 0
+}
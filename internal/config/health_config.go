@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// HealthConfig controls dependency health checks. ChecksFile is the registry
+// of external HTTP dependencies to check.
+type HealthConfig struct {
+	ChecksFile       string
+	MonitorInterval  time.Duration
+	MonitorTimeout   time.Duration
+	ReadinessTimeout time.Duration
+}
+
+func NewHealthConfig() *HealthConfig {
+	return &HealthConfig{
+		ChecksFile:       getEnv("HEALTH_CHECKS_FILE", "health_checks.json"),
+		MonitorInterval:  getEnvDuration("HEALTH_MONITOR_INTERVAL", 30*time.Second),
+		MonitorTimeout:   getEnvDuration("HEALTH_MONITOR_TIMEOUT", 5*time.Second),
+		ReadinessTimeout: getEnvDuration("HEALTH_READINESS_TIMEOUT", 2*time.Second),
+	}
+}
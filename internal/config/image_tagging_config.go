@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+// ImageTaggingConfig selects the provider submitted images are tagged with:
+// "rekognition" (Amazon Rekognition in Region) or "http" (a self-hosted
+// model at ModelURL). Every Interval the images of up to BatchSize pending
+// submissions are tagged. An empty Provider disables tagging.
+type ImageTaggingConfig struct {
+	Provider string
+	Region   string
+	ModelURL string
+	Interval time.Duration
+	// BatchSize counts submissions, each tagged with all its images
+	BatchSize int
+	// MinConfidence is the confidence, from 0 to 1, labels need to be kept
+	// and suggested as tags or categories
+	MinConfidence float64
+	// RelevantLabels are the labels that show a landmark. Images with none
+	// of them at MinRelevance or more are rejected
+	RelevantLabels []string
+	MinRelevance   float64
+	MaxTags        int
+	// MaxImageBytes caps the images downloaded for Rekognition, which takes
+	// up to 5 MB
+	MaxImageBytes int64
+}
+
+func NewImageTaggingConfig() *ImageTaggingConfig {
+	return &ImageTaggingConfig{
+		Provider:       getEnv("IMAGE_TAGGING_PROVIDER", ""),
+		Region:         getEnv("IMAGE_TAGGING_REGION", getEnv("AWS_REGION", "eu-north-1")),
+		ModelURL:       getEnv("IMAGE_TAGGING_MODEL_URL", ""),
+		Interval:       getEnvDuration("IMAGE_TAGGING_INTERVAL", 5*time.Minute),
+		BatchSize:      getEnvInt("IMAGE_TAGGING_BATCH_SIZE", 20),
+		MinConfidence:  getEnvFloat("IMAGE_TAGGING_MIN_CONFIDENCE", 0.7),
+		RelevantLabels: getEnvList("IMAGE_TAGGING_RELEVANT_LABELS", "Landmark,Building,Architecture,Monument,Tower,Castle,Church,Cathedral,Temple,Mosque,Bridge,Statue,Sculpture,Museum,Palace,Ruins,Fountain,Mountain,Waterfall,Nature,Outdoors,Tourist Attraction"),
+		MinRelevance:   getEnvFloat("IMAGE_TAGGING_MIN_RELEVANCE", 0.5),
+		MaxTags:        getEnvInt("IMAGE_TAGGING_MAX_TAGS", 10),
+		MaxImageBytes:  int64(getEnvInt("IMAGE_TAGGING_MAX_IMAGE_BYTES", 5<<20)),
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+// IntegrationConfig controls how outbound integrations (Stripe, weather)
+// reach their providers. In "record" mode live responses are written to
+// FixturesDir; in "replay" mode they are served from it without network
+// access, so local development works without live credentials.
+type IntegrationConfig struct {
+	Mode        string
+	FixturesDir string
+}
+
+func NewIntegrationConfig() *IntegrationConfig {
+	return &IntegrationConfig{
+		Mode:        getEnv("INTEGRATION_MODE", "live"),
+		FixturesDir: getEnv("INTEGRATION_FIXTURES_DIR", "fixtures"),
+	}
+}
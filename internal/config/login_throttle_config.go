@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// LoginThrottleConfig bounds failed logins. Each failure for an account
+// doubles the wait before its next attempt, from BaseBackoff up to
+// MaxBackoff. An account with MaxAccountFailures failures, or a client IP
+// with MaxIPFailures, within FailureWindow is locked for LockoutDuration.
+type LoginThrottleConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	BaseBackoff        time.Duration
+	MaxBackoff         time.Duration
+	LockoutDuration    time.Duration
+}
+
+func NewLoginThrottleConfig() *LoginThrottleConfig {
+	return &LoginThrottleConfig{
+		MaxAccountFailures: getEnvInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+		MaxIPFailures:      getEnvInt("LOGIN_MAX_IP_FAILURES", 20),
+		FailureWindow:      getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		BaseBackoff:        getEnvDuration("LOGIN_BASE_BACKOFF", time.Second),
+		MaxBackoff:         getEnvDuration("LOGIN_MAX_BACKOFF", time.Minute),
+		LockoutDuration:    getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+	}
+}
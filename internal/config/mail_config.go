@@ -0,0 +1,56 @@
+package config
+
+import "time"
+
+// Mail drivers recognised by MAIL_DRIVER.
+const (
+	MailDriverSendGrid = "sendgrid"
+	MailDriverSES      = "ses"
+	MailDriverSMTP     = "smtp"
+	MailDriverFile     = "file"
+)
+
+// MailConfig selects how emails are delivered: through SendGrid, Amazon
+// SES or an SMTP server, or, for development, written as HTML files to Dir.
+type MailConfig struct {
+	Driver      string
+	Dir         string
+	FromName    string
+	FromAddress string
+
+	SESRegion    string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// MaxAttempts is how many times a failed send is tried, waiting
+	// RetryDelay before the first retry and twice as long before each one
+	// after.
+	MaxAttempts int
+	RetryDelay  time.Duration
+
+	// SendGridWebhookKey is the verification key of SendGrid's signed
+	// event webhook, and SESBounceTopicARN the SNS topic SES publishes
+	// bounces and complaints to. Each enables its bounce webhook.
+	SendGridWebhookKey string
+	SESBounceTopicARN  string
+}
+
+func NewMailConfig() *MailConfig {
+	return &MailConfig{
+		Driver:             getEnv("MAIL_DRIVER", MailDriverSendGrid),
+		Dir:                getEnv("MAIL_DIR", "tmp/mail"),
+		FromName:           getEnv("MAIL_FROM_NAME", "Landmark API"),
+		FromAddress:        getEnv("MAIL_FROM_ADDRESS", "noreply@landmark-api.com"),
+		SESRegion:          getEnv("MAIL_SES_REGION", getEnv("AWS_REGION", "")),
+		SMTPHost:           getEnv("MAIL_SMTP_HOST", ""),
+		SMTPPort:           getEnvInt("MAIL_SMTP_PORT", 587),
+		SMTPUsername:       getEnv("MAIL_SMTP_USERNAME", ""),
+		SMTPPassword:       getEnv("MAIL_SMTP_PASSWORD", ""),
+		MaxAttempts:        getEnvInt("MAIL_MAX_ATTEMPTS", 3),
+		RetryDelay:         getEnvDuration("MAIL_RETRY_DELAY", time.Second),
+		SendGridWebhookKey: getEnv("MAIL_SENDGRID_WEBHOOK_KEY", ""),
+		SESBounceTopicARN:  getEnv("MAIL_SES_BOUNCE_TOPIC_ARN", ""),
+	}
+}
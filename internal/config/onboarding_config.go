@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// OnboardingConfig times the onboarding email sequence: tips are sent
+// TipsAfter registering, checked every Interval.
+type OnboardingConfig struct {
+	TipsAfter time.Duration
+	Interval  time.Duration
+}
+
+func NewOnboardingConfig() *OnboardingConfig {
+	return &OnboardingConfig{
+		TipsAfter: getEnvDuration("ONBOARDING_TIPS_AFTER", 72*time.Hour),
+		Interval:  getEnvDuration("ONBOARDING_INTERVAL", time.Hour),
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// OverpassConfig controls imports of OpenStreetMap points of interest from
+// the Overpass API at URL. An import covers at most MaxArea square degrees
+// and MaxResults points, and a point counts as a duplicate when it matches
+// an existing landmark with at least DuplicateConfidence. Cities and
+// countries are reverse geocoded, waiting GeocodeDelay between lookups to
+// respect the provider's usage policy.
+type OverpassConfig struct {
+	URL                 string
+	UserAgent           string
+	QueryTimeout        time.Duration
+	MaxArea             float64
+	MaxResults          int
+	DuplicateConfidence float64
+	GeocodeDelay        time.Duration
+}
+
+func NewOverpassConfig() *OverpassConfig {
+	return &OverpassConfig{
+		URL:                 getEnv("OVERPASS_URL", "https://overpass-api.de/api/interpreter"),
+		UserAgent:           getEnv("OVERPASS_USER_AGENT", "landmark-api"),
+		QueryTimeout:        getEnvDuration("OVERPASS_QUERY_TIMEOUT", time.Minute),
+		MaxArea:             getEnvFloat("OVERPASS_MAX_AREA", 0.25),
+		MaxResults:          getEnvInt("OVERPASS_MAX_RESULTS", 500),
+		DuplicateConfidence: getEnvFloat("OVERPASS_DUPLICATE_CONFIDENCE", 0.8),
+		GeocodeDelay:        getEnvDuration("OVERPASS_GEOCODE_DELAY", time.Second),
+	}
+}
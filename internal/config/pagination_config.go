@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// PaginationConfig holds the secret used to sign list cursors and how long a
+// cursor stays valid after the first page is served. An empty secret falls
+// back to the JWT secret.
+type PaginationConfig struct {
+	CursorSecret string
+	CursorTTL    time.Duration
+	// CountCacheTTL is how long list totals are cached per filter
+	// combination.
+	CountCacheTTL time.Duration
+	// EstimateCountThreshold switches unfiltered list totals to the planner's
+	// row estimate once the table is at least this large; 0 always counts.
+	EstimateCountThreshold int64
+}
+
+func NewPaginationConfig() *PaginationConfig {
+	return &PaginationConfig{
+		CursorSecret:           getEnv("CURSOR_SECRET", ""),
+		CursorTTL:              getEnvDuration("CURSOR_TTL", time.Hour),
+		CountCacheTTL:          getEnvDuration("COUNT_CACHE_TTL", 5*time.Minute),
+		EstimateCountThreshold: int64(getEnvInt("COUNT_ESTIMATE_THRESHOLD", 0)),
+	}
+}
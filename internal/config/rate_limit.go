@@ -5,8 +5,23 @@ import (
 )
 
 type RateLimitConfig struct {
-	Limits       map[models.SubscriptionPlan]int
+	Limits map[models.SubscriptionPlan]int
+	// RatePerMinute is the steady rate at which each key of a plan may send
+	// requests, and Burst how many it may send at once above that rate
+	// after a quiet spell. A rate of 0 leaves the plan's requests unsmoothed.
+	RatePerMinute map[models.SubscriptionPlan]int
+	Burst         map[models.SubscriptionPlan]int
+	// IPBurstLimit is how many requests a client IP may send per minute,
+	// whatever keys it uses, so one host can't spread a flood over many
+	// keys; 0 doesn't limit them.
 	IPBurstLimit int
+	// QuotaWarningPercent is the share of their quota after which users are
+	// warned that they are running out; they are told again when it is
+	// used up.
+	QuotaWarningPercent int
+	// Concurrency is how many requests a key of each plan may have in
+	// flight on an instance at once; 0 doesn't cap them.
+	Concurrency map[models.SubscriptionPlan]int
 }
 
 func NewRateLimitConfig() *RateLimitConfig {
@@ -16,5 +31,22 @@ func NewRateLimitConfig() *RateLimitConfig {
 			models.ProPlan:        300000,
 			models.EnterprisePlan: -1, // No limit for Enterprise
 		},
+		RatePerMinute: map[models.SubscriptionPlan]int{
+			models.FreePlan:       getEnvInt("RATE_LIMIT_PER_MINUTE_FREE", 30),
+			models.ProPlan:        getEnvInt("RATE_LIMIT_PER_MINUTE_PRO", 300),
+			models.EnterprisePlan: getEnvInt("RATE_LIMIT_PER_MINUTE_ENTERPRISE", 1200),
+		},
+		Burst: map[models.SubscriptionPlan]int{
+			models.FreePlan:       getEnvInt("RATE_LIMIT_BURST_FREE", 200),
+			models.ProPlan:        getEnvInt("RATE_LIMIT_BURST_PRO", 2000),
+			models.EnterprisePlan: getEnvInt("RATE_LIMIT_BURST_ENTERPRISE", 10000),
+		},
+		IPBurstLimit:        getEnvInt("RATE_LIMIT_IP_PER_MINUTE", 3000),
+		QuotaWarningPercent: getEnvInt("QUOTA_WARNING_PERCENT", 80),
+		Concurrency: map[models.SubscriptionPlan]int{
+			models.FreePlan:       getEnvInt("CONCURRENCY_LIMIT_FREE", 5),
+			models.ProPlan:        getEnvInt("CONCURRENCY_LIMIT_PRO", 20),
+			models.EnterprisePlan: getEnvInt("CONCURRENCY_LIMIT_ENTERPRISE", 50),
+		},
 	}
 }
@@ -0,0 +1,26 @@
+package config
+
+// RequestLogConfig controls how much of each request the request log and
+// the access log keep. By default neither keeps query strings or bodies.
+type RequestLogConfig struct {
+	// ScrubPaths logs the matched route, e.g. /api/v1/landmarks/name/{name},
+	// instead of the path, so search terms in paths are not kept. Review
+	// priority counts requests per landmark path, so it sees no traffic
+	// while this is set.
+	ScrubPaths bool
+	// StripQueryStrings leaves query strings out of logged paths.
+	StripQueryStrings bool
+	// CaptureBodies keeps up to MaxBodyBytes of each response body in the
+	// request log. Bodies are only buffered while it is set.
+	CaptureBodies bool
+	MaxBodyBytes  int
+}
+
+func NewRequestLogConfig() *RequestLogConfig {
+	return &RequestLogConfig{
+		ScrubPaths:        getEnv("REQUEST_LOG_SCRUB_PATHS", "false") == "true",
+		StripQueryStrings: getEnv("REQUEST_LOG_STRIP_QUERY_STRINGS", "true") == "true",
+		CaptureBodies:     getEnv("REQUEST_LOG_CAPTURE_BODIES", "false") == "true",
+		MaxBodyBytes:      getEnvInt("REQUEST_LOG_MAX_BODY_BYTES", 2048),
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import "time"
+
+// RetentionConfig sets how long each class of data is kept; zero keeps it
+// forever. Expired rows are removed every Interval. What request logs hold
+// in the first place is set by RequestLogConfig.
+type RetentionConfig struct {
+	Interval time.Duration
+	// RequestLogs are archived to ArchiveBucket before they are deleted
+	// when a bucket is set.
+	RequestLogs       time.Duration
+	SearchAnalytics   time.Duration
+	RequestLogExports time.Duration
+	AccountExports    time.Duration
+	// DeletedAccounts is how long deleted accounts are kept before they
+	// are purged with all their data.
+	DeletedAccounts time.Duration
+	ArchiveRegion   string
+	ArchiveBucket   string
+	ArchivePrefix   string
+}
+
+func NewRetentionConfig() *RetentionConfig {
+	return &RetentionConfig{
+		Interval:          getEnvDuration("RETENTION_INTERVAL", 4*time.Hour),
+		RequestLogs:       getEnvDuration("RETENTION_REQUEST_LOGS", 12*time.Hour),
+		SearchAnalytics:   getEnvDuration("RETENTION_SEARCH_ANALYTICS", 0),
+		RequestLogExports: getEnvDuration("RETENTION_REQUEST_LOG_EXPORTS", 7*24*time.Hour),
+		AccountExports:    getEnvDuration("RETENTION_ACCOUNT_EXPORTS", 7*24*time.Hour),
+		DeletedAccounts:   getEnvDuration("RETENTION_DELETED_ACCOUNTS", 30*24*time.Hour),
+		ArchiveRegion:     getEnv("ARCHIVE_REGION", "eu-north-1"),
+		ArchiveBucket:     getEnv("ARCHIVE_BUCKET", ""),
+		ArchivePrefix:     getEnv("ARCHIVE_PREFIX", "archive/"),
+	}
+}
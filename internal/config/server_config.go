@@ -0,0 +1,74 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerConfig bounds how long requests may take and how many run at once.
+// RouteTimeouts overrides HandlerTimeout per route, keyed by method and route
+// template, e.g. "POST /api/v1/landmarks/search". Requests beyond MaxInFlight
+// wait in a queue of at most MaxQueued for up to QueueTimeout before being
+// shed with a 503. Request bodies are capped at MaxBodyBytes, or
+// MaxUploadBytes for multipart uploads, and responses of at least
+// CompressMinBytes are compressed.
+type ServerConfig struct {
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	HandlerTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+	MaxInFlight    int
+	MaxQueued      int
+	QueueTimeout   time.Duration
+
+	MaxBodyBytes     int64
+	MaxUploadBytes   int64
+	CompressMinBytes int
+}
+
+func NewServerConfig() *ServerConfig {
+	return &ServerConfig{
+		ReadTimeout:    getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:   getEnvDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+		HandlerTimeout: getEnvDuration("HANDLER_TIMEOUT", 10*time.Second),
+		RouteTimeouts:  parseRouteTimeouts(getEnv("ROUTE_TIMEOUTS", "")),
+		MaxInFlight:    getEnvInt("SHED_MAX_IN_FLIGHT", 200),
+		MaxQueued:      getEnvInt("SHED_MAX_QUEUED", 100),
+		QueueTimeout:   getEnvDuration("SHED_QUEUE_TIMEOUT", time.Second),
+
+		MaxBodyBytes:     int64(getEnvInt("MAX_BODY_BYTES", 1<<20)),
+		MaxUploadBytes:   int64(getEnvInt("MAX_UPLOAD_BYTES", 32<<20)),
+		CompressMinBytes: getEnvInt("COMPRESS_MIN_BYTES", 1024),
+	}
+}
+
+// parseRouteTimeouts reads comma-separated "METHOD /template=duration"
+// entries. Malformed entries are logged and skipped.
+func parseRouteTimeouts(value string) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, rawTimeout, _ := strings.Cut(entry, "=")
+		parts := strings.Fields(route)
+		timeout, err := time.ParseDuration(strings.TrimSpace(rawTimeout))
+		if len(parts) != 2 || err != nil || timeout <= 0 {
+			log.Printf("Ignoring invalid ROUTE_TIMEOUTS entry %q", entry)
+			continue
+		}
+		timeouts[strings.ToUpper(parts[0])+" "+parts[1]] = timeout
+	}
+	return timeouts
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,14 @@
+package config
+
+// StorageConfig locates the S3 bucket for landmark photos.
+type StorageConfig struct {
+	Region string
+	Bucket string
+}
+
+func NewStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Region: getEnv("AWS_REGION", "eu-north-1"),
+		Bucket: getEnv("AWS_BUCKET", "properties-photos"),
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+// StripeConfig holds the Stripe secret key and the price behind each
+// checkout plan.
+type StripeConfig struct {
+	SecretKey string
+	// WebhookSecret is the signing secret of the webhook endpoint; events
+	// not signed with it are refused.
+	WebhookSecret     string
+	FreePriceID       string
+	MonthlyPriceID    string
+	AnnualPriceID     string
+	EnterprisePriceID string
+}
+
+func NewStripeConfig() *StripeConfig {
+	return &StripeConfig{
+		SecretKey:         getEnv("STRIPE_SECRET_KEY", ""),
+		WebhookSecret:     getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		FreePriceID:       getEnv("STRIPE_MONTHLY_FREE_PRICE_ID", ""),
+		MonthlyPriceID:    getEnv("STRIPE_MONTHLY_PRICE_ID", ""),
+		AnnualPriceID:     getEnv("STRIPE_ANNUAL_PRICE_ID", ""),
+		EnterprisePriceID: getEnv("STRIPE_ENTERPRISE_PLAN_PRICE_ID", ""),
+	}
+}
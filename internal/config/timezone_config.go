@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// TimezoneConfig selects the provider landmark timezones are looked up
+// with: "geonames" (needs GeoNamesUsername) or "google" (needs
+// GoogleAPIKey). Every BackfillInterval up to BatchSize landmarks without a
+// timezone are looked up. An empty Provider disables lookups.
+type TimezoneConfig struct {
+	Provider         string
+	GeoNamesUsername string
+	GoogleAPIKey     string
+	BackfillInterval time.Duration
+	BatchSize        int
+}
+
+func NewTimezoneConfig() *TimezoneConfig {
+	return &TimezoneConfig{
+		Provider:         getEnv("TIMEZONE_PROVIDER", ""),
+		GeoNamesUsername: getEnv("GEONAMES_USERNAME", ""),
+		GoogleAPIKey:     getEnv("TIMEZONE_GOOGLE_API_KEY", ""),
+		BackfillInterval: getEnvDuration("TIMEZONE_BACKFILL_INTERVAL", time.Hour),
+		BatchSize:        getEnvInt("TIMEZONE_BATCH_SIZE", 100),
+	}
+}
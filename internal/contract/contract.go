@@ -0,0 +1,128 @@
+// Package contract checks the API against its published OpenAPI spec, the
+// one swag generates from the handlers' annotations into cmd/api/docs (run
+// go generate ./cmd/api after changing them). In tests, wrap the router in
+// a Checker so every response it serves is validated against the spec;
+// MockHandler serves example responses from the spec for client
+// development.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"landmark-api/cmd/api/docs"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ErrUndocumentedRoute is returned for requests to a route the spec does
+// not describe.
+var ErrUndocumentedRoute = errors.New("route is not in the spec")
+
+// Spec is the API's OpenAPI spec, ready to match requests against.
+type Spec struct {
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// Load reads the spec generated into cmd/api/docs.
+func Load() (*Spec, error) {
+	var swagger openapi2.T
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &swagger); err != nil {
+		return nil, fmt.Errorf("error reading spec: %w", err)
+	}
+	doc, err := openapi2conv.ToV3(&swagger)
+	if err != nil {
+		return nil, fmt.Errorf("error converting spec: %w", err)
+	}
+	// Paths are absolute, so requests to any host match them.
+	doc.Servers = nil
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error routing spec: %w", err)
+	}
+	return &Spec{doc: doc, router: router}, nil
+}
+
+// Validate checks a response to r against the spec: its route must be
+// documented, its status must be one of the route's responses, and its
+// content type and body must match that response.
+func (s *Spec) Validate(r *http.Request, status int, header http.Header, body []byte) error {
+	route, params, err := s.router.FindRoute(r)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", r.Method, r.URL.Path, ErrUndocumentedRoute)
+	}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+		},
+		Status:  status,
+		Header:  header,
+		Options: &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	}
+	input.SetBodyBytes(body)
+	if err := openapi3filter.ValidateResponse(context.Background(), input); err != nil {
+		return fmt.Errorf("%s %s %d: %w", r.Method, route.Path, status, err)
+	}
+	return nil
+}
+
+// Checker is a middleware that validates every response against the spec,
+// collecting the ones that don't match for Err.
+type Checker struct {
+	spec *Spec
+
+	mu         sync.Mutex
+	violations []error
+}
+
+func NewChecker(spec *Spec) *Checker {
+	return &Checker{spec: spec}
+}
+
+func (c *Checker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := httptest.NewRecorder()
+		next.ServeHTTP(recorder, r)
+
+		result := recorder.Result()
+		if err := c.spec.Validate(r, result.StatusCode, result.Header, recorder.Body.Bytes()); err != nil {
+			c.mu.Lock()
+			c.violations = append(c.violations, err)
+			c.mu.Unlock()
+		}
+
+		for key, values := range result.Header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(result.StatusCode)
+		w.Write(recorder.Body.Bytes())
+	})
+}
+
+// Err returns every response so far that didn't match the spec, or nil.
+func (c *Checker) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.Join(c.violations...)
+}
+
+// Reset forgets the violations found so far.
+func (c *Checker) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.violations = nil
+}
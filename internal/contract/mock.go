@@ -0,0 +1,105 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// mockDepth bounds how deep examples of recursive schemas, such as the
+// category tree, are built.
+const mockDepth = 4
+
+// MockHandler answers every documented route with the first success
+// response of its operation, with a body built from the response schema:
+// the schema's example where it has one, otherwise a placeholder of its
+// type. Undocumented routes get a 404.
+func (s *Spec) MockHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, _, err := s.router.FindRoute(r)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": ErrUndocumentedRoute.Error()})
+			return
+		}
+
+		status, response := successResponse(route.Operation)
+		if response == nil || response.Content.Get("application/json") == nil {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(example(response.Content.Get("application/json").Schema, 0))
+	})
+}
+
+// successResponse is the lowest 2xx response of operation, or an empty
+// 200 when it documents none.
+func successResponse(operation *openapi3.Operation) (int, *openapi3.Response) {
+	var statuses []int
+	for code := range operation.Responses.Map() {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return http.StatusOK, nil
+	}
+	sort.Ints(statuses)
+	return statuses[0], operation.Responses.Status(statuses[0]).Value
+}
+
+// example builds a value that satisfies ref.
+func example(ref *openapi3.SchemaRef, depth int) interface{} {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	schema := ref.Value
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if len(schema.AllOf) > 0 {
+		return example(schema.AllOf[0], depth)
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeObject) || len(schema.Properties) > 0:
+		object := map[string]interface{}{}
+		if depth >= mockDepth {
+			return object
+		}
+		for name, property := range schema.Properties {
+			object[name] = example(property, depth+1)
+		}
+		return object
+	case schema.Type.Is(openapi3.TypeArray):
+		if depth >= mockDepth {
+			return []interface{}{}
+		}
+		return []interface{}{example(schema.Items, depth+1)}
+	case schema.Type.Is(openapi3.TypeString):
+		switch schema.Format {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		}
+		return "string"
+	case schema.Type.Is(openapi3.TypeInteger):
+		return 0
+	case schema.Type.Is(openapi3.TypeNumber):
+		return 0.0
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return false
+	}
+	// Untyped schemas, such as interface{} fields, accept any value.
+	return map[string]interface{}{}
+}
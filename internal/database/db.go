@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"landmark-api/internal/migrations"
 	"log"
 	"os"
 	"time"
@@ -11,10 +13,23 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func InitDB() (*gorm.DB, error) {
-	dbURL := os.Getenv("DATABASE_URL")
+// InitDB connects to the database and applies pending migrations.
+func InitDB(dbURL string) (*gorm.DB, error) {
+	db, err := Connect(dbURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := migrations.NewMigrator(db).Up(context.Background(), migrations.Options{}); err != nil {
+		return nil, fmt.Errorf("error migrating database: %v", err)
+	}
+	return db, nil
+}
+
+// Connect opens the database without touching its schema.
+func Connect(dbURL string) (*gorm.DB, error) {
 	if dbURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL environment variable is required")
+		return nil, fmt.Errorf("database URL is required")
 	}
 
 	// Configure GORM logger
@@ -35,19 +50,5 @@ func InitDB() (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error opening database: %v", err)
 	}
-
-	if err := autoMigrate(db); err != nil {
-		return nil, fmt.Errorf("error migrating database: %v", err)
-	}
-	//migrations.MigrateLandmarks(db)
 	return db, nil
 }
-
-func autoMigrate(db *gorm.DB) error {
-	/*err := db.Migrator().DropTable(&models.SubmissionLandmark{}, &models.SubmissionLandmarkDetail{}, &models.SubmissionLandmarkImage{})
-	if err != nil {
-		log.Fatal("Failed to drop tables: ", err)
-	}
-	return db.AutoMigrate(&models.SubmissionLandmark{}, &models.SubmissionLandmarkDetail{}, &models.SubmissionLandmarkImage{})*/
-	return db.AutoMigrate()
-}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replicaResolver names the resolver that serves reads made through Reader.
+// Nothing else uses it, so every other query stays on the primary.
+const replicaResolver = "landmark:read_replica"
+
+// Reader returns a handle on db whose reads may be served by a read replica.
+// Only use it for queries that tolerate replication lag, such as public
+// listings and stats. Writes and transactions through it still use the
+// primary, and without replicas it behaves exactly like db.
+func Reader(db *gorm.DB) *gorm.DB {
+	tx := db.Clauses(dbresolver.Use(replicaResolver))
+	// Adding the clause already resolves a replica; drop it so a transaction
+	// begun through the handle starts on the primary
+	tx.Statement.ConnPool = db.Statement.ConnPool
+	return tx.Session(&gorm.Session{})
+}
+
+// RegisterReplicas connects to the read replicas and registers a dbresolver
+// that sends reads made through Reader to them in turn. It returns the
+// replica connections so callers can configure and monitor them.
+func RegisterReplicas(db *gorm.DB, urls []string) ([]*sql.DB, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	pools := make([]*sql.DB, 0, len(urls))
+	replicas := make([]gorm.Dialector, 0, len(urls))
+	for i, url := range urls {
+		replica, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			return nil, fmt.Errorf("error opening read replica %d: %v", i+1, err)
+		}
+		pool, err := replica.DB()
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+		replicas = append(replicas, postgres.New(postgres.Config{Conn: pool}))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.StrictRoundRobinPolicy(),
+	}, replicaResolver)
+	if err := db.Use(resolver); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
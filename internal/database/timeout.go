@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"landmark-api/internal/config"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const deadlineSetting = "landmark:query_deadline"
+
+// queryDeadline is the context a statement ran with before its deadline was
+// added. It is restored afterwards because GORM reuses statements when a
+// query is chained, e.g. Count followed by Find.
+type queryDeadline struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout overrides the default per-query deadline for queries
+// made with ctx, e.g. for background jobs that delete in large batches.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, timeout)
+}
+
+// ConfigurePool applies the connection pool settings to pool.
+func ConfigurePool(pool *sql.DB, cfg *config.DatabaseConfig) {
+	pool.SetMaxOpenConns(cfg.MaxOpenConns)
+	pool.SetMaxIdleConns(cfg.MaxIdleConns)
+	pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	pool.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// RegisterQueryTimeout installs GORM callbacks that give every query,
+// create, update, delete and exec a deadline of timeout, or of the
+// WithQueryTimeout override, so one slow query cannot hold a connection
+// indefinitely. An earlier deadline on the caller's context still wins.
+//
+// Row and Rows are left alone: their results are read after the callbacks
+// return, so the deadline could not be released in time.
+func RegisterQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	start := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		d := timeout
+		if override, ok := parent.Value(queryTimeoutKey{}).(time.Duration); ok {
+			d = override
+		}
+
+		ctx, cancel := context.WithTimeout(parent, d)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(deadlineSetting, queryDeadline{parent: parent, cancel: cancel})
+	}
+	finish := func(tx *gorm.DB) {
+		if value, ok := tx.InstanceGet(deadlineSetting); ok {
+			deadline := value.(queryDeadline)
+			deadline.cancel()
+			tx.Statement.Context = deadline.parent
+			tx.Statement.Settings.Delete(fmt.Sprintf("%p", tx.Statement) + deadlineSetting)
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("timeout:query_start", start); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:after_query").Register("timeout:query_finish", finish); err != nil {
+		return err
+	}
+	if err := callbacks.Create().Before("gorm:begin_transaction").Register("timeout:create_start", start); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:commit_or_rollback_transaction").Register("timeout:create_finish", finish); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:begin_transaction").Register("timeout:update_start", start); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("timeout:update_finish", finish); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:begin_transaction").Register("timeout:delete_start", start); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register("timeout:delete_finish", finish); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("timeout:raw_start", start); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("timeout:raw_finish", finish)
+}
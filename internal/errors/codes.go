@@ -0,0 +1,148 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code is a stable, machine-readable identifier for an error, returned as
+// "code" in every error response so clients can branch on it instead of on
+// the message. Codes are part of the API: add new ones, never rename them.
+type Code string
+
+const (
+	CodeInvalidRequest       Code = "INVALID_REQUEST"
+	CodeValidationFailed     Code = "VALIDATION_FAILED"
+	CodeUnknownFilter        Code = "UNKNOWN_FILTER"
+	CodeUnknownFormat        Code = "UNKNOWN_FORMAT"
+	CodeInvalidCursor        Code = "INVALID_CURSOR"
+	CodeCursorMismatch       Code = "CURSOR_MISMATCH"
+	CodeCursorExpired        Code = "CURSOR_EXPIRED"
+	CodePayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeMissingAPIKey        Code = "MISSING_API_KEY"
+	CodeInvalidAPIKey        Code = "INVALID_API_KEY"
+	CodeInvalidSignature     Code = "INVALID_SIGNATURE"
+	CodeAPIKeyExpired        Code = "API_KEY_EXPIRED"
+	CodeInvalidCredentials   Code = "INVALID_CREDENTIALS"
+	CodeLoginThrottled       Code = "LOGIN_THROTTLED"
+	CodeAccountLocked        Code = "ACCOUNT_LOCKED"
+	CodeForbidden            Code = "FORBIDDEN"
+	CodeSubscriptionRequired Code = "SUBSCRIPTION_REQUIRED"
+	CodeNotFound             Code = "NOT_FOUND"
+	CodeLandmarkNotFound     Code = "LANDMARK_NOT_FOUND"
+	CodeSubmissionNotFound   Code = "SUBMISSION_NOT_FOUND"
+	CodeCategoryNotFound     Code = "CATEGORY_NOT_FOUND"
+	CodeAPIKeyNotFound       Code = "API_KEY_NOT_FOUND"
+	CodeUserNotFound         Code = "USER_NOT_FOUND"
+	CodeSessionNotFound      Code = "SESSION_NOT_FOUND"
+	CodeNotificationNotFound Code = "NOTIFICATION_NOT_FOUND"
+	CodeMethodNotAllowed     Code = "METHOD_NOT_ALLOWED"
+	CodeConflict             Code = "CONFLICT"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeQuotaExceeded        Code = "QUOTA_EXCEEDED"
+	CodeTooManyConcurrent    Code = "TOO_MANY_CONCURRENT_REQUESTS"
+	CodeInternal             Code = "INTERNAL_ERROR"
+	CodeUnavailable          Code = "SERVICE_UNAVAILABLE"
+	CodeTimeout              Code = "TIMEOUT"
+)
+
+// codeStatus is the HTTP status each code is returned with.
+var codeStatus = map[Code]int{
+	CodeInvalidRequest:       http.StatusBadRequest,
+	CodeValidationFailed:     http.StatusBadRequest,
+	CodeUnknownFilter:        http.StatusBadRequest,
+	CodeUnknownFormat:        http.StatusBadRequest,
+	CodeInvalidCursor:        http.StatusBadRequest,
+	CodeCursorMismatch:       http.StatusBadRequest,
+	CodeCursorExpired:        http.StatusGone,
+	CodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	CodeUnauthorized:         http.StatusUnauthorized,
+	CodeMissingAPIKey:        http.StatusUnauthorized,
+	CodeInvalidAPIKey:        http.StatusUnauthorized,
+	CodeInvalidSignature:     http.StatusUnauthorized,
+	CodeAPIKeyExpired:        http.StatusUnauthorized,
+	CodeInvalidCredentials:   http.StatusUnauthorized,
+	CodeLoginThrottled:       http.StatusTooManyRequests,
+	CodeAccountLocked:        http.StatusLocked,
+	CodeForbidden:            http.StatusForbidden,
+	CodeSubscriptionRequired: http.StatusForbidden,
+	CodeNotFound:             http.StatusNotFound,
+	CodeLandmarkNotFound:     http.StatusNotFound,
+	CodeSubmissionNotFound:   http.StatusNotFound,
+	CodeCategoryNotFound:     http.StatusNotFound,
+	CodeAPIKeyNotFound:       http.StatusNotFound,
+	CodeUserNotFound:         http.StatusNotFound,
+	CodeSessionNotFound:      http.StatusNotFound,
+	CodeNotificationNotFound: http.StatusNotFound,
+	CodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	CodeConflict:             http.StatusConflict,
+	CodeRateLimited:          http.StatusTooManyRequests,
+	CodeQuotaExceeded:        http.StatusTooManyRequests,
+	CodeTooManyConcurrent:    http.StatusTooManyRequests,
+	CodeInternal:             http.StatusInternalServerError,
+	CodeUnavailable:          http.StatusServiceUnavailable,
+	CodeTimeout:              http.StatusGatewayTimeout,
+}
+
+// statusCode is the code used for an error response that did not name one.
+var statusCode = map[int]Code{
+	http.StatusBadRequest:            CodeInvalidRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusGone:                  CodeCursorExpired,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+	http.StatusGatewayTimeout:        CodeTimeout,
+}
+
+// sentinelCodes maps the package's sentinel errors to their codes.
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{ErrNotFound, CodeNotFound},
+	{ErrAlreadyExists, CodeConflict},
+	{ErrInvalidInput, CodeInvalidRequest},
+	{ErrInsufficientPermission, CodeForbidden},
+	{ErrInvalidCredentials, CodeInvalidCredentials},
+	{ErrInsufficientSubscription, CodeSubscriptionRequired},
+}
+
+// Status returns the HTTP status that code is returned with.
+func (code Code) Status() int {
+	if status, ok := codeStatus[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// CodeForStatus returns the generic code for an HTTP error status.
+func CodeForStatus(status int) Code {
+	if code, ok := statusCode[status]; ok {
+		return code
+	}
+	if status >= 400 && status < 500 {
+		return CodeInvalidRequest
+	}
+	return CodeInternal
+}
+
+// CodeOf maps err to its code: the Code of an *Error in its chain, or that
+// of a sentinel error it wraps. Anything else is an internal error.
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Code != "" && appErr.Code != CodeInternal {
+		return appErr.Code
+	}
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+	return CodeInternal
+}
@@ -16,17 +16,29 @@ var (
 type Error struct {
 	Err     error
 	Message string
-	Code    string
+	Code    Code
 }
 
 func (e *Error) Error() string {
 	return e.Message
 }
 
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error with a client-facing code and message.
+func New(code Code, message string) *Error {
+	return &Error{
+		Message: message,
+		Code:    code,
+	}
+}
+
 func Wrap(err error, message string) *Error {
 	return &Error{
 		Err:     err,
 		Message: message,
-		Code:    "INTERNAL_ERROR",
+		Code:    CodeInternal,
 	}
 }
@@ -0,0 +1,91 @@
+// Package geo holds small geographic helpers shared by handlers and services.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusKm is the mean radius of the Earth in kilometers.
+const EarthRadiusKm = 6371
+
+// HaversineKm returns the great-circle distance between two points in
+// kilometers.
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	dLat := (lat2 - lat1) * (math.Pi / 180)
+	dLon := (lon2 - lon1) * (math.Pi / 180)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*(math.Pi/180))*math.Cos(lat2*(math.Pi/180))*
+			math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return EarthRadiusKm * c
+}
+
+// BoundingBox returns the latitude and longitude bounds of a square around a
+// point that contains every point within radiusKm. It is meant as a cheap
+// index-friendly prefilter before an exact distance check.
+func BoundingBox(lat, lon, radiusKm float64) (minLat, maxLat, minLon, maxLon float64) {
+	latDelta := radiusKm / EarthRadiusKm * (180 / math.Pi)
+	lonDelta := 180.0
+	if cosLat := math.Cos(lat * math.Pi / 180); cosLat > 1e-9 {
+		lonDelta = math.Min(180, latDelta/cosLat)
+	}
+
+	return lat - latDelta, lat + latDelta, lon - lonDelta, lon + lonDelta
+}
+
+// BBox is a bounding box in degrees. When MinLon is greater than MaxLon the
+// box crosses the antimeridian.
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// ParseBBox parses "minLon,minLat,maxLon,maxLat", the order map libraries
+// use for bounding boxes.
+func ParseBBox(s string) (BBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BBox{}, errors.New("bbox must be minLon,minLat,maxLon,maxLat")
+	}
+
+	var values [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BBox{}, fmt.Errorf("invalid bbox coordinate %q", part)
+		}
+		values[i] = value
+	}
+
+	box := BBox{MinLon: values[0], MinLat: values[1], MaxLon: values[2], MaxLat: values[3]}
+	if box.MinLat < -90 || box.MaxLat > 90 || box.MinLat > box.MaxLat {
+		return BBox{}, errors.New("bbox latitudes must be within -90 and 90, min first")
+	}
+	if box.MinLon < -180 || box.MinLon > 180 || box.MaxLon < -180 || box.MaxLon > 180 {
+		return BBox{}, errors.New("bbox longitudes must be within -180 and 180")
+	}
+	return box, nil
+}
+
+// String formats the box the way ParseBBox reads it.
+func (b BBox) String() string {
+	return fmt.Sprintf("%g,%g,%g,%g", b.MinLon, b.MinLat, b.MaxLon, b.MaxLat)
+}
+
+// clusterCellsPerTile is how many grid cells span one map tile, so a cluster
+// covers roughly 64 of a tile's 256 pixels.
+const clusterCellsPerTile = 4
+
+// ClusterCellSize returns the side in degrees of the grid cells landmarks
+// are clustered into at a web map zoom level.
+func ClusterCellSize(zoom int) float64 {
+	return 360 / (math.Exp2(float64(zoom)) * clusterCellsPerTile)
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/balance"
+)
+
+// Pinger is implemented by connections that can be pinged, e.g. the Redis
+// cache.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+func DatabaseCheck(db *sql.DB) CheckFunc {
+	return db.PingContext
+}
+
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+func S3Check(client *s3.S3, bucket string) CheckFunc {
+	return func(ctx context.Context) error {
+		_, err := client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+		return err
+	}
+}
+
+// StripeCheck fetches the account balance, which any valid secret key may
+// read.
+func StripeCheck() CheckFunc {
+	return func(ctx context.Context) error {
+		params := &stripe.BalanceParams{}
+		params.Context = ctx
+		_, err := balance.Get(params)
+		return err
+	}
+}
+
+// HTTPCheck expects a 200 response from a GET to url.
+func HTTPCheck(client *http.Client, url string) CheckFunc {
+	return RequestCheck(client, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	}, http.StatusOK)
+}
+
+// RequestCheck sends the request built by newRequest and expects the given
+// status code.
+func RequestCheck(client *http.Client, newRequest func(ctx context.Context) (*http.Request, error), expectedStatus int) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := newRequest(ctx)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != expectedStatus {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+// Package health checks the API's dependencies in the background and keeps
+// their status, latency and incident history for the public status page.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusOperational Status = "operational"
+	StatusDegraded    Status = "degraded"
+	StatusDown        Status = "down"
+
+	latencySamples = 100
+	maxIncidents   = 20
+)
+
+// CheckFunc returns an error when the dependency is unavailable.
+type CheckFunc func(ctx context.Context) error
+
+// Component is one dependency to check. Checks slower than SlowThreshold
+// mark it degraded.
+type Component struct {
+	Name          string
+	Check         CheckFunc
+	SlowThreshold time.Duration
+}
+
+// ComponentStatus is the latest known state of a component. Latencies are in
+// milliseconds over the most recent checks.
+type ComponentStatus struct {
+	Name        string    `json:"name"`
+	Status      Status    `json:"status"`
+	LastChecked time.Time `json:"last_checked"`
+	LatencyP50  float64   `json:"latency_p50_ms"`
+	LatencyP95  float64   `json:"latency_p95_ms"`
+	LatencyP99  float64   `json:"latency_p99_ms"`
+}
+
+// Incident is a period during which a component was not operational.
+type Incident struct {
+	Component  string     `json:"component"`
+	Status     Status     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Report is the overall status: down if any component is down, degraded if
+// any is degraded, operational otherwise.
+type Report struct {
+	Status     Status            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+	Incidents  []Incident        `json:"incidents"`
+}
+
+type componentState struct {
+	component Component
+	status    ComponentStatus
+	latencies []time.Duration
+	// incident indexes the component's open incident, or is -1
+	incident int
+}
+
+// Monitor runs every component's check on an interval.
+type Monitor struct {
+	interval  time.Duration
+	timeout   time.Duration
+	mu        sync.RWMutex
+	states    []*componentState
+	incidents []Incident
+}
+
+// NewMonitor checks components every interval, giving each check up to
+// timeout. Components start as operational until first checked.
+func NewMonitor(interval, timeout time.Duration, components ...Component) *Monitor {
+	m := &Monitor{interval: interval, timeout: timeout}
+	for _, component := range components {
+		m.states = append(m.states, &componentState{
+			component: component,
+			status:    ComponentStatus{Name: component.Name, Status: StatusOperational},
+			incident:  -1,
+		})
+	}
+	return m
+}
+
+// Start runs the checks now and then on every interval until ctx is done.
+func (m *Monitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CheckAll runs every check concurrently and records the results.
+func (m *Monitor) CheckAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, state := range m.states {
+		wg.Add(1)
+		go func(state *componentState) {
+			defer wg.Done()
+			m.check(ctx, state)
+		}(state)
+	}
+	wg.Wait()
+}
+
+func (m *Monitor) check(ctx context.Context, state *componentState) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := state.component.Check(ctx)
+	latency := time.Since(start)
+
+	status := StatusOperational
+	switch {
+	case err != nil:
+		status = StatusDown
+	case state.component.SlowThreshold > 0 && latency > state.component.SlowThreshold:
+		status = StatusDegraded
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state.latencies = append(state.latencies, latency)
+	if len(state.latencies) > latencySamples {
+		state.latencies = state.latencies[1:]
+	}
+	state.status.Status = status
+	state.status.LastChecked = start
+	state.status.LatencyP50 = percentileMs(state.latencies, 0.50)
+	state.status.LatencyP95 = percentileMs(state.latencies, 0.95)
+	state.status.LatencyP99 = percentileMs(state.latencies, 0.99)
+	m.trackIncident(state, status, start)
+}
+
+// trackIncident opens an incident when a component stops being operational,
+// escalates it if the component goes down, and resolves it on recovery.
+func (m *Monitor) trackIncident(state *componentState, status Status, now time.Time) {
+	if state.incident >= 0 {
+		incident := &m.incidents[state.incident]
+		if status == StatusOperational {
+			incident.ResolvedAt = &now
+			state.incident = -1
+		} else if status == StatusDown {
+			incident.Status = StatusDown
+		}
+		return
+	}
+	if status == StatusOperational {
+		return
+	}
+
+	if len(m.incidents) == maxIncidents {
+		m.incidents = m.incidents[1:]
+		for _, other := range m.states {
+			if other.incident >= 0 {
+				other.incident--
+			}
+		}
+	}
+	m.incidents = append(m.incidents, Incident{
+		Component: state.component.Name,
+		Status:    status,
+		StartedAt: now,
+	})
+	state.incident = len(m.incidents) - 1
+}
+
+// Report returns the current status of every component and recent
+// incidents, newest first.
+func (m *Monitor) Report() Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	report := Report{
+		Status:     StatusOperational,
+		Components: make([]ComponentStatus, 0, len(m.states)),
+		Incidents:  make([]Incident, 0, len(m.incidents)),
+	}
+	for _, state := range m.states {
+		report.Components = append(report.Components, state.status)
+		switch state.status.Status {
+		case StatusDown:
+			report.Status = StatusDown
+		case StatusDegraded:
+			if report.Status == StatusOperational {
+				report.Status = StatusDegraded
+			}
+		}
+	}
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		report.Incidents = append(report.Incidents, m.incidents[i])
+	}
+	return report
+}
+
+func percentileMs(latencies []time.Duration, p float64) float64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(p*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return float64(sorted[index].Microseconds()) / 1000
+}
+
+// CheckNow runs the components' checks concurrently, each within timeout,
+// and returns the error of every failed check by component name.
+func CheckNow(ctx context.Context, timeout time.Duration, components ...Component) map[string]error {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = make(map[string]error)
+	)
+	for _, component := range components {
+		wg.Add(1)
+		go func(component Component) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if err := component.Check(ctx); err != nil {
+				mu.Lock()
+				failed[component.Name] = err
+				mu.Unlock()
+			}
+		}(component)
+	}
+	wg.Wait()
+	return failed
+}
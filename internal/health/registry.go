@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ExternalCheck describes an HTTP dependency to health-check. Secrets are
+// never stored in the registry: SecretEnv names the environment variable
+// holding one, sent as the SecretQuery parameter or SecretHeader header.
+type ExternalCheck struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Method         string `json:"method"`
+	ExpectedStatus int    `json:"expected_status"`
+	SecretEnv      string `json:"secret_env"`
+	SecretQuery    string `json:"secret_query"`
+	SecretHeader   string `json:"secret_header"`
+	SlowThreshold  string `json:"slow_threshold"`
+}
+
+// LoadExternalChecks reads the registry file at path, a JSON array of
+// ExternalCheck. A missing file means no external checks.
+func LoadExternalChecks(path string) ([]ExternalCheck, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []ExternalCheck
+	if err := json.Unmarshal(data, &checks); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return checks, nil
+}
+
+// Component validates the check and builds the component that runs it.
+func (c ExternalCheck) Component(client *http.Client) (Component, error) {
+	if c.Name == "" {
+		return Component{}, errors.New("external check has no name")
+	}
+	target, err := url.Parse(c.URL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return Component{}, fmt.Errorf("external check %s has an invalid url", c.Name)
+	}
+
+	method := strings.ToUpper(c.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	expectedStatus := c.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+
+	var slowThreshold time.Duration
+	if c.SlowThreshold != "" {
+		if slowThreshold, err = time.ParseDuration(c.SlowThreshold); err != nil {
+			return Component{}, fmt.Errorf("external check %s has an invalid slow_threshold: %w", c.Name, err)
+		}
+	}
+
+	var secret string
+	if c.SecretEnv != "" {
+		secret = os.Getenv(c.SecretEnv)
+		if secret == "" {
+			return Component{}, fmt.Errorf("external check %s needs %s to be set", c.Name, c.SecretEnv)
+		}
+	}
+
+	newRequest := func(ctx context.Context) (*http.Request, error) {
+		requestURL := *target
+		if c.SecretQuery != "" {
+			query := requestURL.Query()
+			query.Set(c.SecretQuery, secret)
+			requestURL.RawQuery = query.Encode()
+		}
+		req, err := http.NewRequestWithContext(ctx, method, requestURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.SecretHeader != "" {
+			req.Header.Set(c.SecretHeader, secret)
+		}
+		return req, nil
+	}
+
+	return Component{
+		Name:          c.Name,
+		Check:         RequestCheck(client, newRequest, expectedStatus),
+		SlowThreshold: slowThreshold,
+	}, nil
+}
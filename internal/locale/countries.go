@@ -0,0 +1,256 @@
+package locale
+
+// countries is the bundled ISO 3166-1 country list with each country's
+// ISO 4217 currency, its most widely used BCP 47 locale and its ITU calling
+// code. Aliases are other names landmarks may be filed under.
+var countries = []Country{
+	{"AD", "Andorra", "EUR", "ca-AD", "+376", nil},
+	{"AE", "United Arab Emirates", "AED", "ar-AE", "+971", []string{"UAE"}},
+	{"AF", "Afghanistan", "AFN", "ps-AF", "+93", nil},
+	{"AG", "Antigua and Barbuda", "XCD", "en-AG", "+1", nil},
+	{"AI", "Anguilla", "XCD", "en-AI", "+1", nil},
+	{"AL", "Albania", "ALL", "sq-AL", "+355", nil},
+	{"AM", "Armenia", "AMD", "hy-AM", "+374", nil},
+	{"AO", "Angola", "AOA", "pt-AO", "+244", nil},
+	{"AQ", "Antarctica", "", "en", "+672", nil},
+	{"AR", "Argentina", "ARS", "es-AR", "+54", nil},
+	{"AS", "American Samoa", "USD", "en-AS", "+1", nil},
+	{"AT", "Austria", "EUR", "de-AT", "+43", nil},
+	{"AU", "Australia", "AUD", "en-AU", "+61", nil},
+	{"AW", "Aruba", "AWG", "nl-AW", "+297", nil},
+	{"AX", "Åland Islands", "EUR", "sv-AX", "+358", []string{"Aland Islands"}},
+	{"AZ", "Azerbaijan", "AZN", "az-AZ", "+994", nil},
+	{"BA", "Bosnia and Herzegovina", "BAM", "bs-BA", "+387", nil},
+	{"BB", "Barbados", "BBD", "en-BB", "+1", nil},
+	{"BD", "Bangladesh", "BDT", "bn-BD", "+880", nil},
+	{"BE", "Belgium", "EUR", "nl-BE", "+32", nil},
+	{"BF", "Burkina Faso", "XOF", "fr-BF", "+226", nil},
+	{"BG", "Bulgaria", "BGN", "bg-BG", "+359", nil},
+	{"BH", "Bahrain", "BHD", "ar-BH", "+973", nil},
+	{"BI", "Burundi", "BIF", "rn-BI", "+257", nil},
+	{"BJ", "Benin", "XOF", "fr-BJ", "+229", nil},
+	{"BL", "Saint Barthélemy", "EUR", "fr-BL", "+590", []string{"Saint Barthelemy"}},
+	{"BM", "Bermuda", "BMD", "en-BM", "+1", nil},
+	{"BN", "Brunei", "BND", "ms-BN", "+673", []string{"Brunei Darussalam"}},
+	{"BO", "Bolivia", "BOB", "es-BO", "+591", nil},
+	{"BQ", "Caribbean Netherlands", "USD", "nl-BQ", "+599", []string{"Bonaire, Sint Eustatius and Saba"}},
+	{"BR", "Brazil", "BRL", "pt-BR", "+55", nil},
+	{"BS", "Bahamas", "BSD", "en-BS", "+1", nil},
+	{"BT", "Bhutan", "BTN", "dz-BT", "+975", nil},
+	{"BV", "Bouvet Island", "NOK", "nb-NO", "+47", nil},
+	{"BW", "Botswana", "BWP", "en-BW", "+267", nil},
+	{"BY", "Belarus", "BYN", "be-BY", "+375", nil},
+	{"BZ", "Belize", "BZD", "en-BZ", "+501", nil},
+	{"CA", "Canada", "CAD", "en-CA", "+1", nil},
+	{"CC", "Cocos (Keeling) Islands", "AUD", "en-CC", "+61", []string{"Cocos Islands"}},
+	{"CD", "Democratic Republic of the Congo", "CDF", "fr-CD", "+243", []string{"DR Congo", "Congo-Kinshasa"}},
+	{"CF", "Central African Republic", "XAF", "fr-CF", "+236", nil},
+	{"CG", "Republic of the Congo", "XAF", "fr-CG", "+242", []string{"Congo", "Congo-Brazzaville"}},
+	{"CH", "Switzerland", "CHF", "de-CH", "+41", nil},
+	{"CI", "Côte d'Ivoire", "XOF", "fr-CI", "+225", []string{"Ivory Coast", "Cote d'Ivoire"}},
+	{"CK", "Cook Islands", "NZD", "en-CK", "+682", nil},
+	{"CL", "Chile", "CLP", "es-CL", "+56", nil},
+	{"CM", "Cameroon", "XAF", "fr-CM", "+237", nil},
+	{"CN", "China", "CNY", "zh-CN", "+86", nil},
+	{"CO", "Colombia", "COP", "es-CO", "+57", nil},
+	{"CR", "Costa Rica", "CRC", "es-CR", "+506", nil},
+	{"CU", "Cuba", "CUP", "es-CU", "+53", nil},
+	{"CV", "Cabo Verde", "CVE", "pt-CV", "+238", []string{"Cape Verde"}},
+	{"CW", "Curaçao", "ANG", "nl-CW", "+599", []string{"Curacao"}},
+	{"CX", "Christmas Island", "AUD", "en-CX", "+61", nil},
+	{"CY", "Cyprus", "EUR", "el-CY", "+357", nil},
+	{"CZ", "Czechia", "CZK", "cs-CZ", "+420", []string{"Czech Republic"}},
+	{"DE", "Germany", "EUR", "de-DE", "+49", nil},
+	{"DJ", "Djibouti", "DJF", "fr-DJ", "+253", nil},
+	{"DK", "Denmark", "DKK", "da-DK", "+45", nil},
+	{"DM", "Dominica", "XCD", "en-DM", "+1", nil},
+	{"DO", "Dominican Republic", "DOP", "es-DO", "+1", nil},
+	{"DZ", "Algeria", "DZD", "ar-DZ", "+213", nil},
+	{"EC", "Ecuador", "USD", "es-EC", "+593", nil},
+	{"EE", "Estonia", "EUR", "et-EE", "+372", nil},
+	{"EG", "Egypt", "EGP", "ar-EG", "+20", nil},
+	{"EH", "Western Sahara", "MAD", "ar-EH", "+212", nil},
+	{"ER", "Eritrea", "ERN", "ti-ER", "+291", nil},
+	{"ES", "Spain", "EUR", "es-ES", "+34", nil},
+	{"ET", "Ethiopia", "ETB", "am-ET", "+251", nil},
+	{"FI", "Finland", "EUR", "fi-FI", "+358", nil},
+	{"FJ", "Fiji", "FJD", "en-FJ", "+679", nil},
+	{"FK", "Falkland Islands", "FKP", "en-FK", "+500", nil},
+	{"FM", "Micronesia", "USD", "en-FM", "+691", []string{"Federated States of Micronesia"}},
+	{"FO", "Faroe Islands", "DKK", "fo-FO", "+298", nil},
+	{"FR", "France", "EUR", "fr-FR", "+33", nil},
+	{"GA", "Gabon", "XAF", "fr-GA", "+241", nil},
+	{"GB", "United Kingdom", "GBP", "en-GB", "+44", []string{"UK", "Great Britain", "England", "Scotland", "Wales", "Northern Ireland"}},
+	{"GD", "Grenada", "XCD", "en-GD", "+1", nil},
+	{"GE", "Georgia", "GEL", "ka-GE", "+995", nil},
+	{"GF", "French Guiana", "EUR", "fr-GF", "+594", nil},
+	{"GG", "Guernsey", "GBP", "en-GG", "+44", nil},
+	{"GH", "Ghana", "GHS", "en-GH", "+233", nil},
+	{"GI", "Gibraltar", "GIP", "en-GI", "+350", nil},
+	{"GL", "Greenland", "DKK", "kl-GL", "+299", nil},
+	{"GM", "Gambia", "GMD", "en-GM", "+220", nil},
+	{"GN", "Guinea", "GNF", "fr-GN", "+224", nil},
+	{"GP", "Guadeloupe", "EUR", "fr-GP", "+590", nil},
+	{"GQ", "Equatorial Guinea", "XAF", "es-GQ", "+240", nil},
+	{"GR", "Greece", "EUR", "el-GR", "+30", nil},
+	{"GS", "South Georgia and the South Sandwich Islands", "GBP", "en-GS", "+500", nil},
+	{"GT", "Guatemala", "GTQ", "es-GT", "+502", nil},
+	{"GU", "Guam", "USD", "en-GU", "+1", nil},
+	{"GW", "Guinea-Bissau", "XOF", "pt-GW", "+245", nil},
+	{"GY", "Guyana", "GYD", "en-GY", "+592", nil},
+	{"HK", "Hong Kong", "HKD", "zh-HK", "+852", nil},
+	{"HM", "Heard Island and McDonald Islands", "AUD", "en-HM", "+672", nil},
+	{"HN", "Honduras", "HNL", "es-HN", "+504", nil},
+	{"HR", "Croatia", "EUR", "hr-HR", "+385", nil},
+	{"HT", "Haiti", "HTG", "fr-HT", "+509", nil},
+	{"HU", "Hungary", "HUF", "hu-HU", "+36", nil},
+	{"ID", "Indonesia", "IDR", "id-ID", "+62", nil},
+	{"IE", "Ireland", "EUR", "en-IE", "+353", nil},
+	{"IL", "Israel", "ILS", "he-IL", "+972", nil},
+	{"IM", "Isle of Man", "GBP", "en-IM", "+44", nil},
+	{"IN", "India", "INR", "hi-IN", "+91", nil},
+	{"IO", "British Indian Ocean Territory", "USD", "en-IO", "+246", nil},
+	{"IQ", "Iraq", "IQD", "ar-IQ", "+964", nil},
+	{"IR", "Iran", "IRR", "fa-IR", "+98", nil},
+	{"IS", "Iceland", "ISK", "is-IS", "+354", nil},
+	{"IT", "Italy", "EUR", "it-IT", "+39", nil},
+	{"JE", "Jersey", "GBP", "en-JE", "+44", nil},
+	{"JM", "Jamaica", "JMD", "en-JM", "+1", nil},
+	{"JO", "Jordan", "JOD", "ar-JO", "+962", nil},
+	{"JP", "Japan", "JPY", "ja-JP", "+81", nil},
+	{"KE", "Kenya", "KES", "sw-KE", "+254", nil},
+	{"KG", "Kyrgyzstan", "KGS", "ky-KG", "+996", nil},
+	{"KH", "Cambodia", "KHR", "km-KH", "+855", nil},
+	{"KI", "Kiribati", "AUD", "en-KI", "+686", nil},
+	{"KM", "Comoros", "KMF", "ar-KM", "+269", nil},
+	{"KN", "Saint Kitts and Nevis", "XCD", "en-KN", "+1", nil},
+	{"KP", "North Korea", "KPW", "ko-KP", "+850", nil},
+	{"KR", "South Korea", "KRW", "ko-KR", "+82", []string{"Korea"}},
+	{"KW", "Kuwait", "KWD", "ar-KW", "+965", nil},
+	{"KY", "Cayman Islands", "KYD", "en-KY", "+1", nil},
+	{"KZ", "Kazakhstan", "KZT", "kk-KZ", "+7", nil},
+	{"LA", "Laos", "LAK", "lo-LA", "+856", nil},
+	{"LB", "Lebanon", "LBP", "ar-LB", "+961", nil},
+	{"LC", "Saint Lucia", "XCD", "en-LC", "+1", nil},
+	{"LI", "Liechtenstein", "CHF", "de-LI", "+423", nil},
+	{"LK", "Sri Lanka", "LKR", "si-LK", "+94", nil},
+	{"LR", "Liberia", "LRD", "en-LR", "+231", nil},
+	{"LS", "Lesotho", "LSL", "en-LS", "+266", nil},
+	{"LT", "Lithuania", "EUR", "lt-LT", "+370", nil},
+	{"LU", "Luxembourg", "EUR", "lb-LU", "+352", nil},
+	{"LV", "Latvia", "EUR", "lv-LV", "+371", nil},
+	{"LY", "Libya", "LYD", "ar-LY", "+218", nil},
+	{"MA", "Morocco", "MAD", "ar-MA", "+212", nil},
+	{"MC", "Monaco", "EUR", "fr-MC", "+377", nil},
+	{"MD", "Moldova", "MDL", "ro-MD", "+373", nil},
+	{"ME", "Montenegro", "EUR", "sr-ME", "+382", nil},
+	{"MF", "Saint Martin", "EUR", "fr-MF", "+590", nil},
+	{"MG", "Madagascar", "MGA", "mg-MG", "+261", nil},
+	{"MH", "Marshall Islands", "USD", "en-MH", "+692", nil},
+	{"MK", "North Macedonia", "MKD", "mk-MK", "+389", []string{"Macedonia"}},
+	{"ML", "Mali", "XOF", "fr-ML", "+223", nil},
+	{"MM", "Myanmar", "MMK", "my-MM", "+95", []string{"Burma"}},
+	{"MN", "Mongolia", "MNT", "mn-MN", "+976", nil},
+	{"MO", "Macao", "MOP", "zh-MO", "+853", []string{"Macau"}},
+	{"MP", "Northern Mariana Islands", "USD", "en-MP", "+1", nil},
+	{"MQ", "Martinique", "EUR", "fr-MQ", "+596", nil},
+	{"MR", "Mauritania", "MRU", "ar-MR", "+222", nil},
+	{"MS", "Montserrat", "XCD", "en-MS", "+1", nil},
+	{"MT", "Malta", "EUR", "mt-MT", "+356", nil},
+	{"MU", "Mauritius", "MUR", "en-MU", "+230", nil},
+	{"MV", "Maldives", "MVR", "dv-MV", "+960", nil},
+	{"MW", "Malawi", "MWK", "en-MW", "+265", nil},
+	{"MX", "Mexico", "MXN", "es-MX", "+52", nil},
+	{"MY", "Malaysia", "MYR", "ms-MY", "+60", nil},
+	{"MZ", "Mozambique", "MZN", "pt-MZ", "+258", nil},
+	{"NA", "Namibia", "NAD", "en-NA", "+264", nil},
+	{"NC", "New Caledonia", "XPF", "fr-NC", "+687", nil},
+	{"NE", "Niger", "XOF", "fr-NE", "+227", nil},
+	{"NF", "Norfolk Island", "AUD", "en-NF", "+672", nil},
+	{"NG", "Nigeria", "NGN", "en-NG", "+234", nil},
+	{"NI", "Nicaragua", "NIO", "es-NI", "+505", nil},
+	{"NL", "Netherlands", "EUR", "nl-NL", "+31", []string{"The Netherlands", "Holland"}},
+	{"NO", "Norway", "NOK", "nb-NO", "+47", nil},
+	{"NP", "Nepal", "NPR", "ne-NP", "+977", nil},
+	{"NR", "Nauru", "AUD", "en-NR", "+674", nil},
+	{"NU", "Niue", "NZD", "en-NU", "+683", nil},
+	{"NZ", "New Zealand", "NZD", "en-NZ", "+64", nil},
+	{"OM", "Oman", "OMR", "ar-OM", "+968", nil},
+	{"PA", "Panama", "PAB", "es-PA", "+507", nil},
+	{"PE", "Peru", "PEN", "es-PE", "+51", nil},
+	{"PF", "French Polynesia", "XPF", "fr-PF", "+689", nil},
+	{"PG", "Papua New Guinea", "PGK", "en-PG", "+675", nil},
+	{"PH", "Philippines", "PHP", "en-PH", "+63", nil},
+	{"PK", "Pakistan", "PKR", "ur-PK", "+92", nil},
+	{"PL", "Poland", "PLN", "pl-PL", "+48", nil},
+	{"PM", "Saint Pierre and Miquelon", "EUR", "fr-PM", "+508", nil},
+	{"PN", "Pitcairn Islands", "NZD", "en-PN", "+64", []string{"Pitcairn"}},
+	{"PR", "Puerto Rico", "USD", "es-PR", "+1", nil},
+	{"PS", "Palestine", "ILS", "ar-PS", "+970", nil},
+	{"PT", "Portugal", "EUR", "pt-PT", "+351", nil},
+	{"PW", "Palau", "USD", "en-PW", "+680", nil},
+	{"PY", "Paraguay", "PYG", "es-PY", "+595", nil},
+	{"QA", "Qatar", "QAR", "ar-QA", "+974", nil},
+	{"RE", "Réunion", "EUR", "fr-RE", "+262", []string{"Reunion"}},
+	{"RO", "Romania", "RON", "ro-RO", "+40", nil},
+	{"RS", "Serbia", "RSD", "sr-RS", "+381", nil},
+	{"RU", "Russia", "RUB", "ru-RU", "+7", []string{"Russian Federation"}},
+	{"RW", "Rwanda", "RWF", "rw-RW", "+250", nil},
+	{"SA", "Saudi Arabia", "SAR", "ar-SA", "+966", nil},
+	{"SB", "Solomon Islands", "SBD", "en-SB", "+677", nil},
+	{"SC", "Seychelles", "SCR", "en-SC", "+248", nil},
+	{"SD", "Sudan", "SDG", "ar-SD", "+249", nil},
+	{"SE", "Sweden", "SEK", "sv-SE", "+46", nil},
+	{"SG", "Singapore", "SGD", "en-SG", "+65", nil},
+	{"SH", "Saint Helena, Ascension and Tristan da Cunha", "SHP", "en-SH", "+290", []string{"Saint Helena"}},
+	{"SI", "Slovenia", "EUR", "sl-SI", "+386", nil},
+	{"SJ", "Svalbard and Jan Mayen", "NOK", "nb-SJ", "+47", nil},
+	{"SK", "Slovakia", "EUR", "sk-SK", "+421", nil},
+	{"SL", "Sierra Leone", "SLE", "en-SL", "+232", nil},
+	{"SM", "San Marino", "EUR", "it-SM", "+378", nil},
+	{"SN", "Senegal", "XOF", "fr-SN", "+221", nil},
+	{"SO", "Somalia", "SOS", "so-SO", "+252", nil},
+	{"SR", "Suriname", "SRD", "nl-SR", "+597", nil},
+	{"SS", "South Sudan", "SSP", "en-SS", "+211", nil},
+	{"ST", "São Tomé and Príncipe", "STN", "pt-ST", "+239", []string{"Sao Tome and Principe"}},
+	{"SV", "El Salvador", "USD", "es-SV", "+503", nil},
+	{"SX", "Sint Maarten", "ANG", "nl-SX", "+1", nil},
+	{"SY", "Syria", "SYP", "ar-SY", "+963", nil},
+	{"SZ", "Eswatini", "SZL", "en-SZ", "+268", []string{"Swaziland"}},
+	{"TC", "Turks and Caicos Islands", "USD", "en-TC", "+1", nil},
+	{"TD", "Chad", "XAF", "fr-TD", "+235", nil},
+	{"TF", "French Southern Territories", "EUR", "fr-TF", "+262", nil},
+	{"TG", "Togo", "XOF", "fr-TG", "+228", nil},
+	{"TH", "Thailand", "THB", "th-TH", "+66", nil},
+	{"TJ", "Tajikistan", "TJS", "tg-TJ", "+992", nil},
+	{"TK", "Tokelau", "NZD", "en-TK", "+690", nil},
+	{"TL", "Timor-Leste", "USD", "pt-TL", "+670", []string{"East Timor"}},
+	{"TM", "Turkmenistan", "TMT", "tk-TM", "+993", nil},
+	{"TN", "Tunisia", "TND", "ar-TN", "+216", nil},
+	{"TO", "Tonga", "TOP", "to-TO", "+676", nil},
+	{"TR", "Türkiye", "TRY", "tr-TR", "+90", []string{"Turkey", "Turkiye"}},
+	{"TT", "Trinidad and Tobago", "TTD", "en-TT", "+1", nil},
+	{"TV", "Tuvalu", "AUD", "en-TV", "+688", nil},
+	{"TW", "Taiwan", "TWD", "zh-TW", "+886", nil},
+	{"TZ", "Tanzania", "TZS", "sw-TZ", "+255", nil},
+	{"UA", "Ukraine", "UAH", "uk-UA", "+380", nil},
+	{"UG", "Uganda", "UGX", "en-UG", "+256", nil},
+	{"UM", "United States Minor Outlying Islands", "USD", "en-UM", "+1", nil},
+	{"US", "United States", "USD", "en-US", "+1", []string{"USA", "United States of America"}},
+	{"UY", "Uruguay", "UYU", "es-UY", "+598", nil},
+	{"UZ", "Uzbekistan", "UZS", "uz-UZ", "+998", nil},
+	{"VA", "Vatican City", "EUR", "it-VA", "+39", []string{"Holy See", "Vatican"}},
+	{"VC", "Saint Vincent and the Grenadines", "XCD", "en-VC", "+1", nil},
+	{"VE", "Venezuela", "VES", "es-VE", "+58", nil},
+	{"VG", "British Virgin Islands", "USD", "en-VG", "+1", nil},
+	{"VI", "U.S. Virgin Islands", "USD", "en-VI", "+1", []string{"US Virgin Islands"}},
+	{"VN", "Vietnam", "VND", "vi-VN", "+84", []string{"Viet Nam"}},
+	{"VU", "Vanuatu", "VUV", "bi-VU", "+678", nil},
+	{"WF", "Wallis and Futuna", "XPF", "fr-WF", "+681", nil},
+	{"WS", "Samoa", "WST", "sm-WS", "+685", nil},
+	{"YE", "Yemen", "YER", "ar-YE", "+967", nil},
+	{"YT", "Mayotte", "EUR", "fr-YT", "+262", nil},
+	{"ZA", "South Africa", "ZAR", "en-ZA", "+27", nil},
+	{"ZM", "Zambia", "ZMW", "en-ZM", "+260", nil},
+	{"ZW", "Zimbabwe", "ZWL", "en-ZW", "+263", nil},
+}
@@ -0,0 +1,42 @@
+// Package locale holds the country metadata clients need to format prices,
+// dates and phone numbers for a landmark's country.
+package locale
+
+import "strings"
+
+// Country is the formatting metadata of an ISO 3166-1 country.
+type Country struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	Currency    string   `json:"currency"`
+	Locale      string   `json:"locale"`
+	PhonePrefix string   `json:"phone_prefix"`
+	Aliases     []string `json:"-"`
+}
+
+// byName indexes countries by lowercased code, name and alias.
+var byName = func() map[string]Country {
+	index := make(map[string]Country, len(countries)*2)
+	for _, country := range countries {
+		index[strings.ToLower(country.Code)] = country
+		index[strings.ToLower(country.Name)] = country
+		for _, alias := range country.Aliases {
+			index[strings.ToLower(alias)] = country
+		}
+	}
+	return index
+}()
+
+// All returns every country, ordered by code.
+func All() []Country {
+	all := make([]Country, len(countries))
+	copy(all, countries)
+	return all
+}
+
+// Lookup finds a country by its ISO code, name or a common alternative
+// name, ignoring case.
+func Lookup(country string) (Country, bool) {
+	c, ok := byName[strings.ToLower(strings.TrimSpace(country))]
+	return c, ok
+}
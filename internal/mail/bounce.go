@@ -0,0 +1,20 @@
+package mail
+
+// BounceType is why an address stopped taking mail.
+type BounceType string
+
+const (
+	// BounceHard is a permanent delivery failure, such as an address that
+	// does not exist.
+	BounceHard BounceType = "bounce"
+	// BounceComplaint is a recipient marking a message as spam.
+	BounceComplaint BounceType = "complaint"
+)
+
+// Bounce is an address a provider reported as not to be mailed again.
+type Bounce struct {
+	Address  string
+	Type     BounceType
+	Reason   string
+	Provider string
+}
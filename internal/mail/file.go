@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars are replaced in the file names of written messages.
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+type fileMailer struct {
+	dir string
+}
+
+// NewFileMailer writes each message to an HTML file in dir instead of
+// sending it, for development. The subject and recipient go in the file
+// name and in a comment at its top.
+func NewFileMailer(dir string) Mailer {
+	return &fileMailer{dir: dir}
+}
+
+func (m *fileMailer) Send(ctx context.Context, message Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.html",
+		time.Now().UTC().Format("20060102T150405.000000000"),
+		unsafeFileChars.ReplaceAllString(message.To, "_"),
+		unsafeFileChars.ReplaceAllString(message.Subject, "_"))
+	path := filepath.Join(m.dir, name)
+
+	content := fmt.Sprintf("<!-- To: %s\n     Subject: %s -->\n%s", message.To, message.Subject, message.HTML)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("error writing email to %s: %w", message.To, err)
+	}
+	log.Printf("Wrote email %q to %s: %s", message.Subject, message.To, path)
+	return nil
+}
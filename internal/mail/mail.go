@@ -0,0 +1,22 @@
+// Package mail renders transactional emails from the html/templates in
+// templates/ and delivers them through a Mailer: SendGrid, Amazon SES, an
+// SMTP server or, for development, files on disk. Mailers wrap each other
+// to retry failed sends and to skip addresses that bounced, which the
+// providers' webhooks report.
+package mail
+
+import (
+	"context"
+)
+
+// Message is a rendered email.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
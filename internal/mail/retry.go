@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// permanentError is a failure that sending again won't fix, such as a
+// rejected address.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type retryingMailer struct {
+	next     Mailer
+	attempts int
+	delay    time.Duration
+}
+
+// NewRetryingMailer sends through next up to attempts times, waiting delay
+// before the second attempt and doubling it, with jitter, before each one
+// after. Permanent errors and cancelled contexts end it early.
+func NewRetryingMailer(next Mailer, attempts int, delay time.Duration) Mailer {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &retryingMailer{next: next, attempts: attempts, delay: delay}
+}
+
+func (m *retryingMailer) Send(ctx context.Context, message Message) error {
+	wait := m.delay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = m.next.Send(ctx, message)
+		if err == nil || IsPermanent(err) || attempt == m.attempts {
+			return err
+		}
+		log.Printf("Attempt %d of %d to email %s failed, retrying: %v", attempt, m.attempts, message.To, err)
+
+		// Up to half the wait again, so failed sends don't retry in step
+		jittered := wait + time.Duration(rand.Int63n(int64(wait)/2+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(jittered):
+		}
+		wait *= 2
+	}
+}
@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sendgrid/sendgrid-go"
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// ErrInvalidSignature is returned for webhook payloads whose signature
+// doesn't match.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+type sendGridMailer struct {
+	client *sendgrid.Client
+	from   *sgmail.Email
+}
+
+// NewSendGridMailer sends messages through SendGrid from the given sender.
+func NewSendGridMailer(apiKey, fromName, fromAddress string) Mailer {
+	return &sendGridMailer{
+		client: sendgrid.NewSendClient(apiKey),
+		from:   sgmail.NewEmail(fromName, fromAddress),
+	}
+}
+
+func (m *sendGridMailer) Send(ctx context.Context, message Message) error {
+	email := sgmail.NewSingleEmail(m.from, message.Subject, sgmail.NewEmail("", message.To), "", message.HTML)
+	response, err := m.client.SendWithContext(ctx, email)
+	if err != nil {
+		return fmt.Errorf("error sending email to %s: %w", message.To, err)
+	}
+	if response.StatusCode >= 400 {
+		err := fmt.Errorf("error sending email to %s: %d %s", message.To, response.StatusCode, response.Body)
+		// Other client errors are the request's fault and fail again
+		if response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// SendGridWebhook verifies and reads SendGrid's signed event webhook.
+type SendGridWebhook struct {
+	key *ecdsa.PublicKey
+}
+
+// NewSendGridWebhook verifies events with the webhook's verification key,
+// as shown base64 encoded in SendGrid's mail settings.
+func NewSendGridWebhook(verificationKey string) (*SendGridWebhook, error) {
+	der, err := base64.StdEncoding.DecodeString(verificationKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding SendGrid verification key: %w", err)
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SendGrid verification key: %w", err)
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("SendGrid verification key is not an ECDSA key")
+	}
+	return &SendGridWebhook{key: ecdsaKey}, nil
+}
+
+// sendGridEvent is the part of an event webhook entry bounces are read
+// from.
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// Bounces checks the signature of a webhook request, from its
+// X-Twilio-Email-Event-Webhook-Signature and -Timestamp headers, and
+// returns the hard bounces, drops and spam reports in body.
+func (h *SendGridWebhook) Bounces(signature, timestamp string, body []byte) ([]Bounce, error) {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	if !ecdsa.VerifyASN1(h.key, digest[:], sig) {
+		return nil, ErrInvalidSignature
+	}
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("error parsing SendGrid events: %w", err)
+	}
+	var bounces []Bounce
+	for _, event := range events {
+		switch {
+		// Blocks are bounces the receiving server may lift later
+		case event.Event == "bounce" && event.Type != "blocked", event.Event == "dropped":
+			bounces = append(bounces, Bounce{Address: event.Email, Type: BounceHard, Reason: event.Reason, Provider: "sendgrid"})
+		case event.Event == "spamreport":
+			bounces = append(bounces, Bounce{Address: event.Email, Type: BounceComplaint, Reason: "spam report", Provider: "sendgrid"})
+		}
+	}
+	return bounces, nil
+}
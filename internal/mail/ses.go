@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
+)
+
+// sesPermanentErrors are the SES error codes that sending again won't fix.
+var sesPermanentErrors = map[string]bool{
+	ses.ErrCodeMessageRejected:                        true,
+	ses.ErrCodeMailFromDomainNotVerifiedException:     true,
+	ses.ErrCodeConfigurationSetDoesNotExistException:  true,
+	ses.ErrCodeAccountSendingPausedException:          true,
+	ses.ErrCodeConfigurationSetSendingPausedException: true,
+}
+
+type sesMailer struct {
+	client *ses.SES
+	from   string
+}
+
+// NewSESMailer sends messages through Amazon SES in region from the given
+// sender, with the credentials of the environment.
+func NewSESMailer(region, fromName, fromAddress string) (Mailer, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	from := netmail.Address{Name: fromName, Address: fromAddress}
+	return &sesMailer{client: ses.New(sess), from: from.String()}, nil
+}
+
+func (m *sesMailer) Send(ctx context.Context, message Message) error {
+	_, err := m.client.SendEmailWithContext(ctx, &ses.SendEmailInput{
+		Source:      aws.String(m.from),
+		Destination: &ses.Destination{ToAddresses: []*string{aws.String(message.To)}},
+		Message: &ses.Message{
+			Subject: &ses.Content{Data: aws.String(message.Subject), Charset: aws.String("UTF-8")},
+			Body: &ses.Body{
+				Html: &ses.Content{Data: aws.String(message.HTML), Charset: aws.String("UTF-8")},
+			},
+		},
+	})
+	if err != nil {
+		err = fmt.Errorf("error sending email to %s: %w", message.To, err)
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && sesPermanentErrors[awsErr.Code()] {
+			return Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
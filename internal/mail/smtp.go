@@ -0,0 +1,133 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// smtpImplicitTLSPort is the submission port that speaks TLS from the
+// start rather than upgrading with STARTTLS.
+const smtpImplicitTLSPort = 465
+
+type smtpMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     netmail.Address
+}
+
+// NewSMTPMailer sends messages through an SMTP server from the given
+// sender, upgrading the connection with STARTTLS when the server offers it
+// and logging in when username is set.
+func NewSMTPMailer(host string, port int, username, password, fromName, fromAddress string) Mailer {
+	return &smtpMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     netmail.Address{Name: fromName, Address: fromAddress},
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	if err := m.send(ctx, message); err != nil {
+		err = fmt.Errorf("error sending email to %s: %w", message.To, err)
+		// 5xx replies are rejections, such as an unknown mailbox
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *smtpMailer) send(ctx context.Context, message Message) error {
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if m.port == smtpImplicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.compose(message)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose builds the MIME message: headers and a quoted-printable HTML
+// body.
+func (m *smtpMailer) compose(message Message) []byte {
+	var b bytes.Buffer
+	headers := [][2]string{
+		{"From", m.from.String()},
+		{"To", (&netmail.Address{Address: message.To}).String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/html; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", header[0], header[1])
+	}
+	b.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&b)
+	body.Write([]byte(message.HTML))
+	body.Close()
+	return b.Bytes()
+}
@@ -0,0 +1,262 @@
+package mail
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snsCertHost matches the hosts SNS serves its signing certificates from,
+// so a forged message can't point at a certificate of its own.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNS message types.
+const (
+	SNSNotification             = "Notification"
+	SNSSubscriptionConfirmation = "SubscriptionConfirmation"
+)
+
+// SNSMessage is a message Amazon SNS posts to an HTTP subscription.
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Token            string `json:"Token"`
+}
+
+// SESWebhook verifies and reads the SES bounce and complaint notifications
+// SNS delivers from a topic.
+type SESWebhook struct {
+	topicArn string
+	client   *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSESWebhook accepts messages from the SNS topic topicArn only.
+func NewSESWebhook(topicArn string) *SESWebhook {
+	return &SESWebhook{
+		topicArn: topicArn,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		certs:    make(map[string]*x509.Certificate),
+	}
+}
+
+// Parse reads an SNS message and checks it comes from the topic, signed by
+// SNS.
+func (h *SESWebhook) Parse(ctx context.Context, body []byte) (*SNSMessage, error) {
+	var message SNSMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, fmt.Errorf("error parsing SNS message: %w", err)
+	}
+	if message.TopicArn != h.topicArn {
+		return nil, fmt.Errorf("SNS message from unexpected topic %s: %w", message.TopicArn, ErrInvalidSignature)
+	}
+	if err := h.verify(ctx, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// Confirm confirms the subscription a SubscriptionConfirmation message
+// asks for.
+func (h *SESWebhook) Confirm(ctx context.Context, message *SNSMessage) error {
+	if err := checkSNSURL(message.SubscribeURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, message.SubscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error confirming SNS subscription: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error confirming SNS subscription: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sesNotification is the part of an SES notification bounces are read
+// from.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Bounce           struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// Bounces returns the permanent bounces and complaints in a Notification
+// message. Transient bounces, such as full mailboxes, are left out.
+func (h *SESWebhook) Bounces(message *SNSMessage) ([]Bounce, error) {
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message.Message), &notification); err != nil {
+		return nil, fmt.Errorf("error parsing SES notification: %w", err)
+	}
+
+	var bounces []Bounce
+	switch notification.NotificationType {
+	case "Bounce":
+		if notification.Bounce.BounceType != "Permanent" {
+			return nil, nil
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			reason := recipient.DiagnosticCode
+			if reason == "" {
+				reason = notification.Bounce.BounceSubType
+			}
+			bounces = append(bounces, Bounce{Address: recipient.EmailAddress, Type: BounceHard, Reason: reason, Provider: "ses"})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			bounces = append(bounces, Bounce{Address: recipient.EmailAddress, Type: BounceComplaint, Reason: notification.Complaint.ComplaintFeedbackType, Provider: "ses"})
+		}
+	}
+	return bounces, nil
+}
+
+// verify checks message's signature against the SNS certificate it names.
+func (h *SESWebhook) verify(ctx context.Context, message *SNSMessage) error {
+	var hashFunc crypto.Hash
+	var digest hash.Hash
+	switch message.SignatureVersion {
+	case "1":
+		hashFunc, digest = crypto.SHA1, sha1.New()
+	case "2":
+		hashFunc, digest = crypto.SHA256, sha256.New()
+	default:
+		return ErrInvalidSignature
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	cert, err := h.cert(ctx, message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	digest.Write([]byte(snsStringToSign(message)))
+	if err := rsa.VerifyPKCS1v15(key, hashFunc, digest.Sum(nil), signature); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// snsStringToSign is the text SNS signs: the message's fields for its
+// type, in order, each name and value on a line of its own.
+func snsStringToSign(message *SNSMessage) string {
+	fields := [][2]string{{"Message", message.Message}, {"MessageId", message.MessageID}}
+	if message.Type == SNSNotification {
+		if message.Subject != "" {
+			fields = append(fields, [2]string{"Subject", message.Subject})
+		}
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", message.SubscribeURL})
+	}
+	fields = append(fields, [2]string{"Timestamp", message.Timestamp})
+	if message.Type != SNSNotification {
+		fields = append(fields, [2]string{"Token", message.Token})
+	}
+	fields = append(fields, [2]string{"TopicArn", message.TopicArn}, [2]string{"Type", message.Type})
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
+
+// cert fetches the signing certificate at certURL, caching it.
+func (h *SESWebhook) cert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	h.mu.Lock()
+	cert, ok := h.certs[certURL]
+	h.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	if err := checkSNSURL(certURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching SNS certificate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching SNS certificate: status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching SNS certificate: %w", err)
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("SNS certificate is not PEM encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SNS certificate: %w", err)
+	}
+
+	h.mu.Lock()
+	h.certs[certURL] = cert
+	h.mu.Unlock()
+	return cert, nil
+}
+
+// checkSNSURL rejects URLs that are not served by SNS over HTTPS.
+func checkSNSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || !snsCertHost.MatchString(u.Hostname()) {
+		return fmt.Errorf("SNS URL %q is not on an SNS host: %w", rawURL, ErrInvalidSignature)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSuppressed is returned for messages to addresses that bounced or
+// complained, which are not sent again.
+var ErrSuppressed = Permanent(errors.New("address is suppressed"))
+
+// Suppressions knows the addresses mail must no longer go to.
+type Suppressions interface {
+	IsSuppressed(ctx context.Context, address string) (bool, error)
+}
+
+type suppressingMailer struct {
+	next         Mailer
+	suppressions Suppressions
+}
+
+// NewSuppressingMailer drops messages to suppressed addresses with
+// ErrSuppressed and sends the others through next.
+func NewSuppressingMailer(next Mailer, suppressions Suppressions) Mailer {
+	return &suppressingMailer{next: next, suppressions: suppressions}
+}
+
+func (m *suppressingMailer) Send(ctx context.Context, message Message) error {
+	suppressed, err := m.suppressions.IsSuppressed(ctx, message.To)
+	if err != nil {
+		return fmt.Errorf("error checking suppression of %s: %w", message.To, err)
+	}
+	if suppressed {
+		return fmt.Errorf("not emailing %s: %w", message.To, ErrSuppressed)
+	}
+	return m.next.Send(ctx, message)
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// defaultLocale is the locale of templates without one in their name.
+const defaultLocale = "en"
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates are the emails in templates/. Each email is a file defining a
+// "subject" and a "content" template, rendered into the "layout" defined
+// by templates/layout.html with the partials in templates/partials.
+// welcome.html is the default variant of the welcome email, and
+// welcome.pl.html its Polish one.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// link is the argument of the button partial, built in templates with
+// {{template "button" (link .URL "Label")}}.
+type link struct {
+	URL   string
+	Label string
+}
+
+var templateFuncs = template.FuncMap{
+	"link": func(url, label string) link { return link{URL: url, Label: label} },
+}
+
+// ParseTemplates parses the embedded templates.
+func ParseTemplates() (*Templates, error) {
+	base, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/layout.html", "templates/partials/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing email layout: %w", err)
+	}
+
+	files, err := fs.Glob(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := &Templates{templates: make(map[string]*template.Template)}
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".html")
+		if name == "layout" {
+			continue
+		}
+		tmpl, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.ParseFS(templateFS, file); err != nil {
+			return nil, fmt.Errorf("error parsing email %s: %w", name, err)
+		}
+		templates.templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// Render renders the named email for the recipient, in locale when it has
+// a variant in that language and in the default language otherwise.
+// Locale is a language tag such as "pl" or "pl-PL".
+func (t *Templates) Render(name, locale, to string, data interface{}) (Message, error) {
+	tmpl, ok := t.templates[name+"."+language(locale)]
+	if !ok {
+		tmpl, ok = t.templates[name]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering subject of %s: %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering %s: %w", name, err)
+	}
+	return Message{
+		To: to,
+		// The subject is plain text, but html/template escapes it as HTML
+		Subject: html.UnescapeString(strings.TrimSpace(subject.String())),
+		HTML:    body.String(),
+	}, nil
+}
+
+// language is the primary language of a tag, lower case: "pl" for "pl-PL".
+// The default locale maps to the templates without a locale.
+func language(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == defaultLocale {
+		return ""
+	}
+	return tag
+}
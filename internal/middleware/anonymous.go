@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Anonymous matches requests that carry no credentials of any kind, so
+// routes of the anonymous tier never shadow authenticated ones.
+func Anonymous(r *http.Request, _ *mux.RouteMatch) bool {
+	return r.Header.Get("x-api-key") == "" &&
+		r.Header.Get("Authorization") == "" &&
+		(r.TLS == nil || len(r.TLS.PeerCertificates) == 0)
+}
+
+// AnonymousAccess serves requests as the free plan without a user, limited
+// per client IP. Counts are kept in the cache so the limit holds across
+// instances, or per instance while Redis is down; if the cache fails
+// anonymous requests are refused rather than let through unlimited.
+func AnonymousAccess(cache services.CacheService, cfg *config.AnonymousConfig) mux.MiddlewareFunc {
+	subscription := &models.Subscription{PlanType: models.FreePlan, Status: models.SubscriptionStatusActive}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				http.Error(w, "Invalid IP address", http.StatusBadRequest)
+				return
+			}
+
+			window := time.Now().Truncate(cfg.RateWindow)
+			reset := window.Add(cfg.RateWindow)
+			count, err := cache.Increment(r.Context(), fmt.Sprintf("anonymous:ratelimit:%s:%d", ip, window.Unix()), cfg.RateWindow)
+			if err != nil {
+				log.Printf("Error counting anonymous requests from %s: %v", ip, err)
+				writeError(w, apperrors.CodeUnavailable, "Anonymous access is temporarily unavailable. Use an API key instead.")
+				return
+			}
+
+			remaining := cfg.RateLimit - int(count)
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(cfg.RateLimit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if count > int64(cfg.RateLimit) {
+				writeRateLimitError(w, apperrors.CodeRateLimited, "Anonymous rate limit exceeded. Create an account for an API key with higher limits.", reset)
+				return
+			}
+
+			// There is no user, only the plan the handlers shape responses by
+			ctx := context.WithValue(r.Context(), services.SubscriptionContextKey, subscription)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
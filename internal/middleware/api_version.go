@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"landmark-api/internal/apiversion"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// APIVersion marks requests to a versioned router group so handlers respond
+// with that version's schema.
+func APIVersion(version apiversion.Version) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(apiversion.Header, string(version))
+			next.ServeHTTP(w, r.WithContext(apiversion.WithVersion(r.Context(), version)))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"landmark-api/internal/logger"
+	"landmark-api/internal/services"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxAuditPayload caps how much of a request body is kept for diffing.
+const maxAuditPayload = 1 << 20
+
+// AuditMiddleware captures the client IP and JSON payload of mutating admin
+// requests for the audit log. Handlers record specific entries through the
+// AuditLogService; a successful mutation whose handler did not gets a
+// generic entry, so no admin change goes unrecorded.
+func AuditMiddleware(auditService services.AuditLogService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var payload []byte
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") && r.Body != nil {
+				body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditPayload+1))
+				if err != nil {
+					http.Error(w, "Failed to read request body", http.StatusBadRequest)
+					return
+				}
+				r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+				if len(body) <= maxAuditPayload {
+					payload = body
+				}
+			}
+
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+
+			ctx := services.WithAuditRequest(r.Context(), ip, payload)
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			if recorder.status >= http.StatusBadRequest || services.AuditRecorded(ctx) {
+				return
+			}
+
+			entry := services.AuditEntry{
+				Action:     r.Method,
+				EntityType: "REQUEST",
+				EntityID:   r.URL.Path,
+				Details:    fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+			}
+			if err := auditService.CreateAuditLog(ctx, entry); err != nil {
+				logger.LogEvent(logrus.ErrorLevel, "Failed to create audit log", logrus.Fields{
+					"path":  r.URL.Path,
+					"error": err.Error(),
+				})
+			}
+		})
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// AuthMethod names a way a request can authenticate.
+type AuthMethod string
+
+const (
+	AuthAPIKey AuthMethod = "api_key"
+	AuthJWT    AuthMethod = "jwt"
+	AuthAdmin  AuthMethod = "admin"
+	AuthMTLS   AuthMethod = "mtls"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request does not
+// carry its kind of credentials, so the chain can try the next one.
+var ErrNoCredentials = errors.New("no credentials")
+
+var errUnauthorized = apperrors.New(apperrors.CodeUnauthorized, "Unauthorized")
+
+// Authenticator resolves the user and subscription behind a request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*models.User, *models.Subscription, error)
+	// MissingError and InvalidError are the 401 responses used when this
+	// is the only accepted method.
+	MissingError() *apperrors.Error
+	InvalidError() *apperrors.Error
+}
+
+// BypassRule lets matching requests through without authentication.
+type BypassRule func(r *http.Request) bool
+
+// BypassPaths skips authentication for exact path matches.
+func BypassPaths(paths ...string) BypassRule {
+	return func(r *http.Request) bool {
+		for _, path := range paths {
+			if r.URL.Path == path {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// BypassMethods skips authentication for the given HTTP methods, e.g. OPTIONS.
+func BypassMethods(methods ...string) BypassRule {
+	return func(r *http.Request) bool {
+		for _, method := range methods {
+			if r.Method == method {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AuthRequirement declares how a route authenticates. Methods are tried in
+// order; the first one whose credentials are present decides the outcome.
+type AuthRequirement struct {
+	Methods []AuthMethod
+	Bypass  []BypassRule
+	// Optional lets requests without credentials through anonymously.
+	// Requests whose credentials are invalid are still rejected.
+	Optional bool
+}
+
+// AuthChain holds the available authenticators and builds per-route
+// middleware from AuthRequirements.
+type AuthChain struct {
+	authenticators map[AuthMethod]Authenticator
+}
+
+func NewAuthChain(authenticators map[AuthMethod]Authenticator) *AuthChain {
+	return &AuthChain{authenticators: authenticators}
+}
+
+// Require accepts any of the given methods.
+func (c *AuthChain) Require(methods ...AuthMethod) mux.MiddlewareFunc {
+	return c.RequireWith(AuthRequirement{Methods: methods})
+}
+
+// Allow accepts any of the given methods, and requests without
+// credentials, which reach the handler without a user in their context.
+func (c *AuthChain) Allow(methods ...AuthMethod) mux.MiddlewareFunc {
+	return c.RequireWith(AuthRequirement{Methods: methods, Optional: true})
+}
+
+// Handle wraps a single route's handler, for routes whose requirement
+// differs from the rest of their router.
+func (c *AuthChain) Handle(handler http.HandlerFunc, methods ...AuthMethod) http.Handler {
+	return c.Require(methods...)(handler)
+}
+
+func (c *AuthChain) RequireWith(requirement AuthRequirement) mux.MiddlewareFunc {
+	authenticators := make([]Authenticator, 0, len(requirement.Methods))
+	for _, method := range requirement.Methods {
+		authenticator, ok := c.authenticators[method]
+		if !ok {
+			panic("middleware: no authenticator registered for " + string(method))
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+
+	missing, invalid := errUnauthorized, errUnauthorized
+	if len(authenticators) == 1 {
+		missing = authenticators[0].MissingError()
+		invalid = authenticators[0].InvalidError()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, bypass := range requirement.Bypass {
+				if bypass(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			for _, authenticator := range authenticators {
+				user, subscription, err := authenticator.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if errors.Is(err, services.ErrAPIKeyExpired) {
+					writeError(w, apperrors.CodeAPIKeyExpired, "API key has expired")
+					return
+				}
+				if err != nil {
+					writeError(w, invalid.Code, invalid.Message)
+					return
+				}
+
+				ctx := services.WithUserAndSubscriptionContext(r.Context(), user, subscription)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			if requirement.Optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeError(w, missing.Code, missing.Message)
+		})
+	}
+}
+
+type apiKeyAuthenticator struct {
+	apiKeyService services.APIKeyService
+}
+
+// NewAPIKeyAuthenticator authenticates the x-api-key header.
+func NewAPIKeyAuthenticator(apiKeyService services.APIKeyService) Authenticator {
+	return &apiKeyAuthenticator{apiKeyService: apiKeyService}
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*models.User, *models.Subscription, error) {
+	apiKey := r.Header.Get("x-api-key")
+	if apiKey == "" {
+		return nil, nil, ErrNoCredentials
+	}
+	// RequireSignature has already looked the key up
+	if found, ok := services.APIKeyFromContext(r.Context()); ok {
+		return a.apiKeyService.GetUserAndSubscriptionForAPIKey(r.Context(), found, apiKey)
+	}
+	return a.apiKeyService.GetUserAndSubscriptionByAPIKey(r.Context(), apiKey)
+}
+
+func (a *apiKeyAuthenticator) MissingError() *apperrors.Error {
+	return apperrors.New(apperrors.CodeMissingAPIKey, "API key is required")
+}
+
+func (a *apiKeyAuthenticator) InvalidError() *apperrors.Error {
+	return apperrors.New(apperrors.CodeInvalidAPIKey, "Invalid API key")
+}
+
+type jwtAuthenticator struct {
+	verify func(token string) (*models.User, *models.Subscription, error)
+}
+
+// NewJWTAuthenticator authenticates a bearer token issued at login.
+func NewJWTAuthenticator(authService services.AuthService) Authenticator {
+	return &jwtAuthenticator{verify: authService.VerifyToken}
+}
+
+// NewAdminAuthenticator authenticates a bearer token that belongs to an admin.
+func NewAdminAuthenticator(authService services.AuthService) Authenticator {
+	return &jwtAuthenticator{verify: authService.VerifyTokenAdmin}
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*models.User, *models.Subscription, error) {
+	tokenString := extractTokenFromHeader(r)
+	if tokenString == "" {
+		return nil, nil, ErrNoCredentials
+	}
+	return a.verify(tokenString)
+}
+
+func (a *jwtAuthenticator) MissingError() *apperrors.Error { return errUnauthorized }
+func (a *jwtAuthenticator) InvalidError() *apperrors.Error { return errUnauthorized }
+
+// ClientCertResolver maps a verified client certificate to an account.
+type ClientCertResolver func(ctx context.Context, cert *x509.Certificate) (*models.User, *models.Subscription, error)
+
+type mtlsAuthenticator struct {
+	resolve ClientCertResolver
+}
+
+// NewMTLSAuthenticator authenticates client certificates that the TLS
+// server has already verified against its client CA pool.
+func NewMTLSAuthenticator(resolve ClientCertResolver) Authenticator {
+	return &mtlsAuthenticator{resolve: resolve}
+}
+
+func (a *mtlsAuthenticator) Authenticate(r *http.Request) (*models.User, *models.Subscription, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil, ErrNoCredentials
+	}
+	return a.resolve(r.Context(), r.TLS.VerifiedChains[0][0])
+}
+
+func (a *mtlsAuthenticator) MissingError() *apperrors.Error {
+	return apperrors.New(apperrors.CodeUnauthorized, "Client certificate is required")
+}
+
+func (a *mtlsAuthenticator) InvalidError() *apperrors.Error {
+	return apperrors.New(apperrors.CodeUnauthorized, "Invalid client certificate")
+}
+
+func extractTokenFromHeader(r *http.Request) string {
+	bearerToken := r.Header.Get("Authorization")
+	if len(strings.Split(bearerToken, " ")) == 2 {
+		return strings.Split(bearerToken, " ")[1]
+	}
+	return ""
+}
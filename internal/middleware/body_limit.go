@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"net/http"
+	"strings"
+)
+
+// LimitBody caps request bodies at cfg.MaxBodyBytes, or cfg.MaxUploadBytes
+// for multipart uploads. Requests that declare a larger Content-Length are
+// rejected with a 413 up front; others fail when the handler reads past the
+// limit.
+func LimitBody(cfg *config.ServerConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := cfg.MaxBodyBytes
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				limit = cfg.MaxUploadBytes
+			}
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				writeError(w, apperrors.CodePayloadTooLarge, "Request body too large")
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
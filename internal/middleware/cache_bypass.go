@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"fmt"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheBypass lets Enterprise keys that need fresh data skip the response
+// cache by sending Cache-Control: no-cache. Each key may bypass the cache
+// BypassLimit times per BypassWindow; past that the request is refused.
+// Other plans, and requests without the header, are served from the cache.
+func CacheBypass(cache services.CacheService, cfg *config.CacheConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subscription, ok := services.SubscriptionFromContext(r.Context())
+			if !ok || subscription.PlanType != models.EnterprisePlan || !noCache(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			user, ok := services.UserFromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			window := time.Now().Truncate(cfg.BypassWindow)
+			reset := window.Add(cfg.BypassWindow)
+			key := fmt.Sprintf("cache:bypass:%s:%d", user.ID, window.Unix())
+			count, err := cache.Increment(r.Context(), key, cfg.BypassWindow)
+			if err != nil {
+				// Without the count the quota can't be enforced, so the
+				// request is served from the cache
+				log.Printf("Error counting cache bypasses of user %s: %v", user.ID, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			remaining := int64(cfg.BypassLimit) - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-Cache-Bypass-Limit", strconv.Itoa(cfg.BypassLimit))
+			w.Header().Set("X-Cache-Bypass-Remaining", strconv.FormatInt(remaining, 10))
+			w.Header().Set("X-Cache-Bypass-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if count > int64(cfg.BypassLimit) {
+				writeRateLimitError(w, apperrors.CodeRateLimited, "Cache bypass limit exceeded. Retry without Cache-Control: no-cache or try again later.", reset)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(services.WithCacheBypass(r.Context())))
+		})
+	}
+}
+
+// noCache reports whether the request asks for a response not served from
+// a cache.
+func noCache(r *http.Request) bool {
+	for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// compressibleTypes are the response content types worth compressing.
+var compressibleTypes = []string{"application/json", "application/problem+json", "text/"}
+
+// encoders are the supported Content-Encodings, in order of preference.
+var encoders = []struct {
+	name string
+	pool *sync.Pool
+}{
+	{"gzip", &sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}},
+}
+
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// Compress encodes JSON and text responses of at least minSize bytes with an
+// encoding the client accepts. Smaller responses are sent as they are, since
+// compressing them costs more than it saves.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding, pool := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if pool == nil || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, pool: pool, minSize: minSize}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+func negotiateEncoding(acceptEncoding string) (string, *sync.Pool) {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.ReplaceAll(strings.TrimSpace(params), " ", "") == "q=0" {
+			continue
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	for _, encoder := range encoders {
+		if accepted[encoder.name] {
+			return encoder.name, encoder.pool
+		}
+	}
+	return "", nil
+}
+
+// compressWriter buffers the start of the response until it knows whether
+// the response is large and compressible enough to encode.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	encoder resettableWriter
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.decided {
+		return cw.write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// decide starts the response, encoded or not, and writes what was buffered.
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	header := cw.Header()
+
+	if len(cw.buf) >= cw.minSize && cw.compressible() {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		cw.encoder = cw.pool.Get().(resettableWriter)
+		cw.encoder.Reset(cw.ResponseWriter)
+	}
+
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := cw.write(buf)
+	return err
+}
+
+func (cw *compressWriter) write(p []byte) (int, error) {
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch cw.status {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close finishes the response once the handler returns.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			// The handler wrote nothing; let net/http send its default.
+			return nil
+		}
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+
+	err := cw.encoder.Close()
+	cw.encoder.Reset(io.Discard)
+	cw.pool.Put(cw.encoder)
+	cw.encoder = nil
+	return err
+}
+
+// Flush sends buffered data to the client, deciding on the encoding early
+// if necessary, so streaming responses keep working.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide()
+	}
+	if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ConcurrencyLimiter caps how many requests each API key has in flight at
+// once, so a burst of parallel requests can't take the whole database pool
+// even while under its quota. Counts are kept per instance: they only live
+// as long as the requests, which a crashed instance would leave counted
+// forever in a shared store.
+type ConcurrencyLimiter struct {
+	config *config.RateLimitConfig
+
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+func NewConcurrencyLimiter(config *config.RateLimitConfig) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{config: config, inFlight: make(map[string]int)}
+}
+
+// Limit refuses requests past their plan's cap with a 429, before they
+// count against the quota. Requests without a subscription, like those of
+// routes that don't require one, are let through.
+func (cl *ConcurrencyLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := services.UserFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		subscription, ok := services.SubscriptionFromContext(r.Context())
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		limit := cl.config.Concurrency[subscription.PlanType]
+		if limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := limiterKey(r, user)
+		w.Header().Set("X-Concurrency-Limit", strconv.Itoa(limit))
+		if !cl.acquire(key, limit) {
+			// Requests in flight finish in moments, unlike rate limits
+			w.Header().Set("Retry-After", "1")
+			writeError(w, apperrors.CodeTooManyConcurrent, "Too many concurrent requests for this API key. Wait for some to finish before sending more.")
+			return
+		}
+		defer cl.release(key)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limiterKey is what a request is limited by: its API key, or its user for
+// requests signed with a certificate, which count as one.
+func limiterKey(r *http.Request, user *models.User) string {
+	key := user.ID.String()
+	if apiKey := r.Header.Get("x-api-key"); apiKey != "" {
+		key += ":" + apiKey
+	}
+	return key
+}
+
+func (cl *ConcurrencyLimiter) acquire(key string, limit int) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.inFlight[key] >= limit {
+		return false
+	}
+	cl.inFlight[key]++
+	return true
+}
+
+func (cl *ConcurrencyLimiter) release(key string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.inFlight[key]--; cl.inFlight[key] <= 0 {
+		delete(cl.inFlight, key)
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"landmark-api/internal/apiversion"
+	"landmark-api/internal/chaos"
+	"landmark-api/internal/config"
+	"landmark-api/internal/tracing"
+	"net/http"
+
+	"github.com/rs/cors"
+)
+
+// exposedHeaders are the response headers browser clients may read.
+var exposedHeaders = []string{
+	"Link",
+	"Retry-After",
+	"X-Cache",
+	"X-Cache-Bypass-Limit",
+	"X-Cache-Bypass-Remaining",
+	"X-Cache-Bypass-Reset",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"X-RateLimit-Credits",
+	"X-RateLimit-Burst-Limit",
+	"X-RateLimit-Burst-Remaining",
+	"X-Concurrency-Limit",
+	"X-Result-Count",
+	apiversion.Header,
+	tracing.TraceparentHeader,
+	tracing.TracestateHeader,
+	tracing.TraceIDHeader,
+	chaos.Header,
+}
+
+// CORS applies the cross-origin policy in cfg. Requests from a dashboard
+// origin get a policy that allows credentials; all others get the public
+// policy, which never does.
+func CORS(cfg *config.CORSConfig) func(http.Handler) http.Handler {
+	options := cors.Options{
+		AllowedMethods: []string{
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodPatch,
+			http.MethodDelete,
+			http.MethodOptions,
+		},
+		AllowedHeaders: cfg.AllowedHeaders,
+		ExposedHeaders: exposedHeaders,
+		MaxAge:         cfg.MaxAge,
+	}
+
+	public := options
+	public.AllowedOrigins = cfg.AllowedOrigins
+	publicCORS := cors.New(public)
+
+	dashboard := options
+	dashboard.AllowedOrigins = cfg.DashboardOrigins
+	dashboard.AllowCredentials = true
+	dashboardCORS := cors.New(dashboard)
+
+	dashboardOrigins := make(map[string]bool, len(cfg.DashboardOrigins))
+	for _, origin := range cfg.DashboardOrigins {
+		dashboardOrigins[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		publicHandler := publicCORS.Handler(next)
+		dashboardHandler := dashboardCORS.Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if dashboardOrigins[r.Header.Get("Origin")] {
+				dashboardHandler.ServeHTTP(w, r)
+				return
+			}
+			publicHandler.ServeHTTP(w, r)
+		})
+	}
+}
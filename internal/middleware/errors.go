@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBody is the largest error response ErrorCodes rewrites; anything
+// bigger is passed through as it is.
+const maxErrorBody = 64 << 10
+
+// writeError writes the standard error body: the message and its code.
+func writeError(w http.ResponseWriter, code apperrors.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code.Status())
+	json.NewEncoder(w).Encode(map[string]string{"error": message, "code": string(code)})
+}
+
+// rateLimitError is the error body of a request refused for going over a
+// limit: the standard body and when the limit resets, both as a timestamp
+// and as the seconds of Retry-After.
+type rateLimitError struct {
+	Error      string         `json:"error"`
+	Code       apperrors.Code `json:"code"`
+	ResetAt    time.Time      `json:"reset_at"`
+	RetryAfter int            `json:"retry_after"`
+}
+
+// writeRateLimitError refuses a request over a limit that resets at reset,
+// telling the client when to retry in the Retry-After header and the body.
+// Callers set the headers describing the limit itself.
+func writeRateLimitError(w http.ResponseWriter, code apperrors.Code, message string, reset time.Time) {
+	retryAfter := max(int(math.Ceil(time.Until(reset).Seconds())), 0)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(code.Status())
+	json.NewEncoder(w).Encode(rateLimitError{
+		Error:      message,
+		Code:       code,
+		ResetAt:    reset.UTC().Truncate(time.Second),
+		RetryAfter: retryAfter,
+	})
+}
+
+// ErrorCodes makes every error response a JSON object with an "error"
+// message and a "code" from the catalogue in internal/errors. Responses
+// that already carry a code are left alone; plain-text errors, such as
+// those from http.Error or the router's 404, and JSON errors without a code
+// get the generic code for their status.
+func ErrorCodes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ew := &errorWriter{ResponseWriter: w}
+		next.ServeHTTP(ew, r)
+		ew.finish()
+	})
+}
+
+type errorWriter struct {
+	http.ResponseWriter
+	status    int
+	buffering bool
+	buf       bytes.Buffer
+}
+
+func (ew *errorWriter) WriteHeader(status int) {
+	if ew.status != 0 {
+		return
+	}
+	ew.status = status
+	if status >= 400 && ew.Header().Get("Content-Encoding") == "" {
+		ew.buffering = true
+		return
+	}
+	ew.ResponseWriter.WriteHeader(status)
+}
+
+func (ew *errorWriter) Write(p []byte) (int, error) {
+	if ew.status == 0 {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if !ew.buffering {
+		return ew.ResponseWriter.Write(p)
+	}
+	if ew.buf.Len()+len(p) > maxErrorBody {
+		// Too big to be an error message; send it unchanged
+		ew.buffering = false
+		ew.ResponseWriter.WriteHeader(ew.status)
+		if _, err := ew.ResponseWriter.Write(ew.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		ew.buf.Reset()
+		return ew.ResponseWriter.Write(p)
+	}
+	return ew.buf.Write(p)
+}
+
+func (ew *errorWriter) Flush() {
+	if ew.buffering {
+		return
+	}
+	if flusher, ok := ew.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (ew *errorWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := ew.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+func (ew *errorWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// finish writes the buffered error response with its code.
+func (ew *errorWriter) finish() {
+	if !ew.buffering {
+		return
+	}
+
+	body := map[string]interface{}{}
+	if !strings.HasPrefix(ew.Header().Get("Content-Type"), "application/json") ||
+		json.Unmarshal(ew.buf.Bytes(), &body) != nil || body == nil {
+		body = map[string]interface{}{}
+		if message := strings.TrimSpace(ew.buf.String()); message != "" {
+			body["error"] = message
+		}
+	}
+	if _, ok := body["code"]; !ok {
+		body["code"] = apperrors.CodeForStatus(ew.status)
+	}
+	if _, ok := body["error"]; !ok {
+		body["error"] = http.StatusText(ew.status)
+	}
+
+	header := ew.Header()
+	header.Set("Content-Type", "application/json")
+	header.Del("Content-Length")
+	ew.ResponseWriter.WriteHeader(ew.status)
+	json.NewEncoder(ew.ResponseWriter).Encode(body)
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"landmark-api/internal/services"
+	"net/http"
+)
+
+// RequireFeature hides a route behind a feature flag: callers the flag is
+// off for get a 404, as if the route did not exist.
+func RequireFeature(flags services.FeatureFlagService, key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !flags.IsEnabled(r.Context(), key) {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
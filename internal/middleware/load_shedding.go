@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// LoadShedder caps concurrent requests. Requests over the cap queue briefly;
+// when the queue is full or the wait runs out they are rejected with a 503
+// straight away, so latency stays bounded under overload. A MaxInFlight of
+// zero or less disables shedding.
+type LoadShedder struct {
+	slots        chan struct{}
+	queued       atomic.Int64
+	maxQueued    int64
+	queueTimeout time.Duration
+}
+
+func NewLoadShedder(cfg *config.ServerConfig) *LoadShedder {
+	if cfg.MaxInFlight <= 0 {
+		return &LoadShedder{}
+	}
+	return &LoadShedder{
+		slots:        make(chan struct{}, cfg.MaxInFlight),
+		maxQueued:    int64(cfg.MaxQueued),
+		queueTimeout: cfg.QueueTimeout,
+	}
+}
+
+func (s *LoadShedder) Middleware(next http.Handler) http.Handler {
+	if s.slots == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.acquire(r) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.queueTimeout.Seconds())+1))
+			writeError(w, apperrors.CodeUnavailable, "Server is overloaded. Please try again later.")
+			return
+		}
+		defer func() { <-s.slots }()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *LoadShedder) acquire(r *http.Request) bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if s.queued.Add(1) > s.maxQueued {
+		s.queued.Add(-1)
+		return false
+	}
+	defer s.queued.Add(-1)
+
+	timer := time.NewTimer(s.queueTimeout)
+	defer timer.Stop()
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -1,33 +1,43 @@
 package middleware
 
 import (
+	"landmark-api/internal/config"
 	"landmark-api/internal/logger"
+	"landmark-api/internal/tracing"
 	"net/http"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-// LoggingMiddleware logs the details of each request and response
-func LoggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Create a response writer to capture the status code
-		rw := &responseWriter{w, http.StatusOK}
-
-		// Call the next handler
-		next.ServeHTTP(rw, r)
-
-		// Log request details
-		logger.LogEvent(logrus.InfoLevel, "Request handled", logrus.Fields{
-			"method":        r.Method,
-			"url":           r.URL.Path,
-			"status_code":   rw.statusCode,
-			"response_time": time.Since(start).Milliseconds(),
-			"ip":            r.RemoteAddr,
+// LoggingMiddleware logs the details of each request and response, with
+// paths scrubbed as cfg sets
+func LoggingMiddleware(cfg *config.RequestLogConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Create a response writer to capture the status code
+			rw := &responseWriter{w, http.StatusOK}
+
+			// Call the next handler
+			next.ServeHTTP(rw, r)
+
+			// Log request details
+			fields := logrus.Fields{
+				"method":        r.Method,
+				"url":           loggedPath(r, cfg),
+				"status_code":   rw.statusCode,
+				"response_time": time.Since(start).Milliseconds(),
+				"ip":            r.RemoteAddr,
+			}
+			if sc, ok := tracing.FromContext(r.Context()); ok {
+				fields["trace_id"] = sc.TraceID
+				fields["span_id"] = sc.SpanID
+			}
+			logger.LogEvent(logrus.InfoLevel, "Request handled", fields)
 		})
-	})
+	}
 }
 
 // responseWriter is a wrapper around http.ResponseWriter to capture the status code
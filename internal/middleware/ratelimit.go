@@ -1,35 +1,35 @@
 package middleware
 
 import (
+	"context"
 	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
 	"landmark-api/internal/models"
 	"landmark-api/internal/services"
+	"log"
 	"net"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// RateLimiter enforces each user's quota for the billing period, and
+// smooths each key's requests with a token bucket so they can burst above
+// their plan's steady rate without being refused while under the quota.
+// Each client IP is also held to a rate of its own, whatever keys it uses.
 type RateLimiter struct {
-	config   *config.RateLimitConfig
-	users    map[string]int
-	ipLimits map[string]*IPLimit
-	mu       sync.Mutex
-	reset    map[string]time.Time
-}
-
-type IPLimit struct {
-	count    int
-	lastSeen time.Time
+	config    *config.RateLimitConfig
+	buckets   *tokenBuckets
+	ipBuckets *tokenBuckets
 }
 
 func NewRateLimiter(config *config.RateLimitConfig) *RateLimiter {
 	return &RateLimiter{
-		config:   config,
-		users:    make(map[string]int),
-		ipLimits: make(map[string]*IPLimit),
-		reset:    make(map[string]time.Time),
+		config:    config,
+		buckets:   newTokenBuckets(),
+		ipBuckets: newTokenBuckets(),
 	}
 }
 
@@ -37,7 +37,7 @@ func (rl *RateLimiter) GetLimit(plan models.SubscriptionPlan) int {
 	return rl.config.Limits[plan]
 }
 
-func (rl *RateLimiter) RateLimit(authService services.AuthService, apiUsageService services.APIUsageService) func(http.Handler) http.Handler {
+func (rl *RateLimiter) RateLimit(authService services.AuthService, apiUsageService services.APIUsageService, notifications services.NotificationService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ip, _, err := net.SplitHostPort(r.RemoteAddr)
@@ -45,9 +45,7 @@ func (rl *RateLimiter) RateLimit(authService services.AuthService, apiUsageServi
 				http.Error(w, "Invalid IP address", http.StatusBadRequest)
 				return
 			}
-
-			if rl.isIPRateLimited(ip) {
-				http.Error(w, "IP rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			if !rl.limitIP(w, ip) {
 				return
 			}
 
@@ -63,59 +61,149 @@ func (rl *RateLimiter) RateLimit(authService services.AuthService, apiUsageServi
 				return
 			}
 
+			if !rl.smooth(w, r, user, subscription.PlanType) {
+				return
+			}
+
 			usageStats, err := apiUsageService.GetCurrentUsage(r.Context(), user.ID, subscription.PlanType)
 			if err != nil {
 				http.Error(w, "Failed to get usage statistics", http.StatusInternalServerError)
 				return
 			}
 
-			limit := rl.config.Limits[subscription.PlanType]
-			if limit >= 0 && usageStats.CurrentCount >= limit {
+			// Once the quota is used up, requests use up the credits bought
+			// on top of it
+			limit := usageStats.Limit
+			overQuota := limit >= 0 && usageStats.CurrentCount >= limit
+			if overQuota && usageStats.Credits <= 0 {
 				rl.setRateLimitHeaders(w, limit, 0, usageStats.PeriodEnd)
-				http.Error(w, "Rate limit exceeded. Please upgrade your subscription for higher limits.", http.StatusTooManyRequests)
+				writeRateLimitError(w, apperrors.CodeQuotaExceeded, "Rate limit exceeded. Please upgrade your subscription for higher limits.", usageStats.PeriodEnd)
 				return
 			}
 
-			wrappedWriter := &responseWriterWrapper{ResponseWriter: w}
+			// The headers count this request unless it isn't billed, which
+			// is only known once the handler responds
+			wrappedWriter := &responseWriterWrapper{ResponseWriter: w, beforeHeader: func(status int, header http.Header) {
+				remaining, credits := limit-usageStats.CurrentCount, usageStats.Credits
+				if billed(status, header) {
+					if overQuota {
+						credits--
+					} else {
+						remaining--
+					}
+				}
+				if overQuota {
+					remaining = 0
+				}
+				rl.setRateLimitHeaders(w, limit, remaining, usageStats.PeriodEnd)
+				if limit >= 0 {
+					w.Header().Set("X-RateLimit-Credits", strconv.FormatInt(credits, 10))
+				}
+			}}
 			next.ServeHTTP(wrappedWriter, r)
+			wrappedWriter.writeHeaderOnce()
 
-			isCacheHit := wrappedWriter.Header().Get("X-Cache") == "HIT"
-
-			if !isCacheHit {
+			if billed(wrappedWriter.status, wrappedWriter.Header()) {
 				if err := apiUsageService.IncrementUsage(user.ID); err != nil {
 					// Log the error, but don't fail the request
 					println("Error incrementing usage:", err.Error())
 				}
+				if overQuota {
+					if _, err := apiUsageService.ConsumeCredit(r.Context(), user.ID); err != nil {
+						log.Printf("Error consuming request credit of user %s: %v", user.ID, err)
+					}
+				}
 				usageStats.CurrentCount++
+				rl.notifyQuota(notifications, user.ID, usageStats)
 			}
-
-			rl.setRateLimitHeaders(w, limit, limit-usageStats.CurrentCount, usageStats.PeriodEnd)
 		})
 	}
 }
 
-func (rl *RateLimiter) isIPRateLimited(ip string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// billed reports whether a response counts against the quota. Cache hits
+// cost next to nothing, server errors and authentication failures are not
+// the client's doing, and a 429 from a limiter behind this one, like the
+// cache bypass limit, served nothing, so none of them count.
+func billed(status int, header http.Header) bool {
+	if header.Get("X-Cache") == "HIT" {
+		return false
+	}
+	return status < http.StatusInternalServerError &&
+		status != http.StatusUnauthorized &&
+		status != http.StatusForbidden &&
+		status != http.StatusTooManyRequests
+}
 
-	now := time.Now()
-	limit, exists := rl.ipLimits[ip]
+// notifyQuota tells the user when the request just counted took them past
+// the warning share of their quota, or used it up. Each count is reached
+// once per period, so each notification is sent once.
+func (rl *RateLimiter) notifyQuota(notifications services.NotificationService, userID uuid.UUID, usage *services.UsageStats) {
+	if usage.Limit <= 0 {
+		return
+	}
 
-	if !exists {
-		rl.ipLimits[ip] = &IPLimit{count: 1, lastSeen: now}
-		return false
+	data := map[string]string{
+		"Used":     strconv.Itoa(usage.CurrentCount),
+		"Limit":    strconv.Itoa(usage.Limit),
+		"Percent":  strconv.Itoa(rl.config.QuotaWarningPercent),
+		"ResetsAt": services.NotificationDate(usage.PeriodEnd),
+	}
+	var name string
+	switch usage.CurrentCount {
+	case usage.Limit * rl.config.QuotaWarningPercent / 100:
+		name = services.NotificationQuotaWarning
+	case usage.Limit:
+		name = services.NotificationQuotaExceeded
+	default:
+		return
+	}
+
+	// Notifying can mean sending an email, which the request should not
+	// wait for
+	go func() {
+		if err := notifications.Notify(context.Background(), userID, name, data); err != nil {
+			log.Printf("Error sending %s notification to user %s: %v", name, userID, err)
+		}
+	}()
+}
+
+// limitIP takes a token from the bucket of ip and reports whether the
+// request may go on. The bucket holds a minute's worth of requests, so an
+// IP may send IPBurstLimit at once but no more than that per minute.
+func (rl *RateLimiter) limitIP(w http.ResponseWriter, ip string) bool {
+	limit := rl.config.IPBurstLimit
+	if limit <= 0 {
+		return true
 	}
 
-	if now.Sub(limit.lastSeen) > time.Minute {
-		limit.count = 1
-		limit.lastSeen = now
+	now := time.Now()
+	if ok, _, wait := rl.ipBuckets.take(ip, limit, limit, now); !ok {
+		writeRateLimitError(w, apperrors.CodeRateLimited, "IP rate limit exceeded. Please try again later.", now.Add(wait))
 		return false
 	}
+	return true
+}
 
-	limit.count++
-	limit.lastSeen = now
+// smooth takes a token from the bucket of the request's key and reports
+// whether the request may go on. Tokens refill at the plan's steady rate,
+// so a client syncing in bursts is only refused once it has kept above
+// that rate for longer than its burst allows; it is then told how long
+// until a token is back.
+func (rl *RateLimiter) smooth(w http.ResponseWriter, r *http.Request, user *models.User, plan models.SubscriptionPlan) bool {
+	perMinute, burst := rl.config.RatePerMinute[plan], rl.config.Burst[plan]
+	if perMinute <= 0 {
+		return true
+	}
 
-	return limit.count > rl.config.IPBurstLimit
+	now := time.Now()
+	ok, remaining, wait := rl.buckets.take(limiterKey(r, user), perMinute, burst, now)
+	w.Header().Set("X-RateLimit-Burst-Limit", strconv.Itoa(burst))
+	w.Header().Set("X-RateLimit-Burst-Remaining", strconv.Itoa(remaining))
+	if !ok {
+		writeRateLimitError(w, apperrors.CodeRateLimited, "Too many requests in a short time. Slow down and retry after the given delay.", now.Add(wait))
+		return false
+	}
+	return true
 }
 
 func (rl *RateLimiter) setRateLimitHeaders(w http.ResponseWriter, limit, remaining int, reset time.Time) {
@@ -127,9 +215,20 @@ func (rl *RateLimiter) setRateLimitHeaders(w http.ResponseWriter, limit, remaini
 type responseWriterWrapper struct {
 	http.ResponseWriter
 	wroteHeader bool
+	// status is the status the response was written with.
+	status int
+	// beforeHeader, if set, is called with the status and header before
+	// they are written.
+	beforeHeader func(status int, header http.Header)
 }
 
 func (rww *responseWriterWrapper) WriteHeader(statusCode int) {
+	if !rww.wroteHeader {
+		rww.status = statusCode
+		if rww.beforeHeader != nil {
+			rww.beforeHeader(statusCode, rww.Header())
+		}
+	}
 	rww.ResponseWriter.WriteHeader(statusCode)
 	rww.wroteHeader = true
 }
@@ -140,3 +239,11 @@ func (rww *responseWriterWrapper) Write(b []byte) (int, error) {
 	}
 	return rww.ResponseWriter.Write(b)
 }
+
+// writeHeaderOnce writes the header of handlers that responded without a
+// body or status, so beforeHeader still runs.
+func (rww *responseWriterWrapper) writeHeaderOnce() {
+	if !rww.wroteHeader {
+		rww.WriteHeader(http.StatusOK)
+	}
+}
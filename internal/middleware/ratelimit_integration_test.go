@@ -0,0 +1,137 @@
+//go:build integration
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/services"
+	"landmark-api/internal/testharness"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeNotifications passes on the names of the notifications sent.
+type fakeNotifications struct {
+	services.NotificationService
+	sent chan string
+}
+
+func (n *fakeNotifications) Notify(ctx context.Context, userID uuid.UUID, name string, data map[string]string) error {
+	n.sent <- name
+	return nil
+}
+
+// TestRateLimitQuota spends a Free user's quota against the real usage
+// tables and checks the request past it is refused and not counted.
+func TestRateLimitQuota(t *testing.T) {
+	ctx := context.Background()
+	env, err := testharness.Start(ctx)
+	if err != nil {
+		t.Fatalf("error starting test environment: %v", err)
+	}
+	defer env.Close()
+
+	periodStart := time.Now().Truncate(time.Second)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	user, subscription, err := testharness.User().Period(periodStart, periodEnd).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rateConfig := &config.RateLimitConfig{
+		Limits:              map[models.SubscriptionPlan]int{models.FreePlan: 2},
+		QuotaWarningPercent: 50,
+	}
+	usageRepo := repository.NewAPIUsageRepository(env.DB)
+	usage := services.NewAPIUsageService(
+		usageRepo,
+		repository.NewSubscriptionRepository(env.DB),
+		repository.NewAPIKeyLimitRepository(env.DB),
+		repository.NewRequestCreditRepository(env.DB),
+		rateConfig,
+	)
+	notifications := &fakeNotifications{sent: make(chan string, 2)}
+	status := http.StatusOK
+	handler := NewRateLimiter(rateConfig).RateLimit(nil, usage, notifications)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/landmarks", nil)
+		req = req.WithContext(services.WithUserAndSubscriptionContext(req.Context(), user, subscription))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	count := func() int {
+		t.Helper()
+		current, err := usageRepo.GetCurrentUsage(user.ID.String(), periodStart, periodEnd)
+		if err != nil {
+			t.Fatalf("GetCurrentUsage: %v", err)
+		}
+		if current == nil {
+			return 0
+		}
+		return current.RequestCount
+	}
+
+	// Server errors don't use the quota
+	status = http.StatusInternalServerError
+	if rec := do(); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := count(); got != 0 {
+		t.Fatalf("usage after a server error = %d, want 0", got)
+	}
+
+	status = http.StatusOK
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := do()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %q", i+1, got, wantRemaining)
+		}
+	}
+	if got := count(); got != 2 {
+		t.Fatalf("usage after spending the quota = %d, want 2", got)
+	}
+
+	rec := do()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request past the quota: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	var body rateLimitError
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("error decoding refusal: %v", err)
+	}
+	if body.Code != apperrors.CodeQuotaExceeded {
+		t.Errorf("refusal code = %s, want %s", body.Code, apperrors.CodeQuotaExceeded)
+	}
+	if got := count(); got != 2 {
+		t.Errorf("usage after a refused request = %d, want 2", got)
+	}
+
+	// Notifications are sent in the background, in no particular order
+	sent := map[string]bool{}
+	for range 2 {
+		select {
+		case name := <-notifications.sent:
+			sent[name] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("notifications sent = %v, want the warning and the exceeded one", sent)
+		}
+	}
+	if !sent[services.NotificationQuotaWarning] || !sent[services.NotificationQuotaExceeded] {
+		t.Errorf("notifications sent = %v, want the warning and the exceeded one", sent)
+	}
+}
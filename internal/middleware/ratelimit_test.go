@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBilled(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		cache  string
+		want   bool
+	}{
+		{"ok", http.StatusOK, "", true},
+		{"created", http.StatusCreated, "", true},
+		{"no content", http.StatusNoContent, "", true},
+		{"cache miss", http.StatusOK, "MISS", true},
+		{"cache bypass", http.StatusOK, "BYPASS", true},
+		{"bad request", http.StatusBadRequest, "", true},
+		{"not found", http.StatusNotFound, "", true},
+		{"cache hit", http.StatusOK, "HIT", false},
+		{"unauthorized", http.StatusUnauthorized, "", false},
+		{"forbidden", http.StatusForbidden, "", false},
+		{"too many requests", http.StatusTooManyRequests, "", false},
+		{"internal server error", http.StatusInternalServerError, "", false},
+		{"bad gateway", http.StatusBadGateway, "", false},
+		{"service unavailable", http.StatusServiceUnavailable, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.cache != "" {
+				header.Set("X-Cache", tt.cache)
+			}
+			if got := billed(tt.status, header); got != tt.want {
+				t.Errorf("billed(%d, X-Cache %q) = %v, want %v", tt.status, tt.cache, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeUsage serves a fixed usage and counts the requests billed.
+type fakeUsage struct {
+	services.APIUsageService
+	stats      services.UsageStats
+	increments int
+}
+
+func (u *fakeUsage) GetCurrentUsage(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan) (*services.UsageStats, error) {
+	stats := u.stats
+	return &stats, nil
+}
+
+func (u *fakeUsage) IncrementUsage(userID uuid.UUID) error {
+	u.increments++
+	return nil
+}
+
+func TestRateLimitBilling(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		wantIncrements int
+		wantRemaining  string
+	}{
+		{"success is billed", http.StatusOK, 1, "89"},
+		{"server error is not billed", http.StatusInternalServerError, 0, "90"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := &fakeUsage{stats: services.UsageStats{
+				CurrentCount: 10,
+				Limit:        100,
+				PeriodEnd:    time.Now().Add(time.Hour),
+			}}
+			limiter := NewRateLimiter(&config.RateLimitConfig{QuotaWarningPercent: 80})
+			handler := limiter.RateLimit(nil, usage, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+
+			user := &models.User{ID: uuid.New()}
+			subscription := &models.Subscription{PlanType: models.ProPlan}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/landmarks", nil)
+			req = req.WithContext(services.WithUserAndSubscriptionContext(req.Context(), user, subscription))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if usage.increments != tt.wantIncrements {
+				t.Errorf("IncrementUsage called %d times, want %d", usage.increments, tt.wantIncrements)
+			}
+			if got := rec.Header().Get("X-RateLimit-Remaining"); got != tt.wantRemaining {
+				t.Errorf("X-RateLimit-Remaining = %q, want %q", got, tt.wantRemaining)
+			}
+		})
+	}
+}
@@ -3,20 +3,29 @@ package middleware
 import (
 	"bytes"
 	"fmt"
+	"landmark-api/internal/apiversion"
+	"landmark-api/internal/config"
 	"landmark-api/internal/logger"
 	"landmark-api/internal/models"
 	"landmark-api/internal/services"
-	"math/rand"
+	"landmark-api/internal/tracing"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 )
 
 type ResponseWriter struct {
 	http.ResponseWriter
 	status int
-	body   bytes.Buffer
+	// body holds up to maxBody bytes of the response, nil when bodies are
+	// not captured.
+	body    *bytes.Buffer
+	maxBody int
 }
 
 func (rw *ResponseWriter) WriteHeader(status int) {
@@ -25,17 +34,26 @@ func (rw *ResponseWriter) WriteHeader(status int) {
 }
 
 func (rw *ResponseWriter) Write(b []byte) (int, error) {
-	rw.body.Write(b)
+	if rw.body != nil {
+		if room := rw.maxBody - rw.body.Len(); room > 0 {
+			if len(b) < room {
+				room = len(b)
+			}
+			rw.body.Write(b[:room])
+		}
+	}
 	return rw.ResponseWriter.Write(b)
 }
 
 type RequestLogger struct {
 	logService services.RequestLogService
+	config     *config.RequestLogConfig
 }
 
-func NewRequestLogger(logService services.RequestLogService) *RequestLogger {
+func NewRequestLogger(logService services.RequestLogService, config *config.RequestLogConfig) *RequestLogger {
 	return &RequestLogger{
 		logService: logService,
+		config:     config,
 	}
 }
 
@@ -46,6 +64,10 @@ func (rl *RequestLogger) LogRequest(next http.Handler) http.Handler {
 			ResponseWriter: w,
 			status:         http.StatusOK,
 		}
+		if rl.config.CaptureBodies {
+			rw.body = &bytes.Buffer{}
+			rw.maxBody = rl.config.MaxBodyBytes
+		}
 
 		// Get user from context
 		user, ok := services.UserFromContext(r.Context())
@@ -54,11 +76,13 @@ func (rl *RequestLogger) LogRequest(next http.Handler) http.Handler {
 			return
 		}
 
-		// Create summary based on the endpoint
-		summary := createRequestSummary(r)
-
 		// Execute the request
+		start := time.Now()
 		next.ServeHTTP(rw, r)
+		duration := time.Since(start)
+
+		// Create summary based on the endpoint and the number of results
+		summary := createRequestSummary(r, rl.config.ScrubPaths, rw.Header().Get("X-Result-Count"))
 
 		// Determine status
 		status := models.StatusSuccess
@@ -66,42 +90,83 @@ func (rl *RequestLogger) LogRequest(next http.Handler) http.Handler {
 			status = models.StatusError
 		}
 
+		var body string
+		if rw.body != nil {
+			body = rw.body.String()
+		}
+
 		// Log to database
-		err := rl.logService.LogRequest(
-			user.ID.String(),
-			r.URL.Path,
-			r.Method,
-			rw.status,
-			status,
-			summary,
-		)
+		err := rl.logService.LogRequest(&models.RequestLog{
+			UserID:       user.ID.String(),
+			Endpoint:     loggedPath(r, rl.config),
+			Route:        routeTemplate(r),
+			Method:       r.Method,
+			Status:       status,
+			StatusCode:   rw.status,
+			Summary:      summary,
+			TraceID:      tracing.TraceID(r.Context()),
+			DurationMs:   duration.Milliseconds(),
+			CacheStatus:  rw.Header().Get("X-Cache"),
+			ResponseBody: body,
+		})
 
 		if err != nil {
 			logger.Logger.WithFields(logrus.Fields{
-				"error": err,
-				"user":  user.ID,
-				"path":  r.URL.Path,
+				"error":    err,
+				"user":     user.ID,
+				"path":     loggedPath(r, rl.config),
+				"trace_id": tracing.TraceID(r.Context()),
 			}).Error("Failed to log request")
 		}
 	})
 }
 
-func createRequestSummary(r *http.Request) string {
+// routeTemplate returns the matched route's path template, falling back to
+// the request path when no route matched.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
+// loggedPath is the path of r as logs keep it: the route template when
+// paths are scrubbed, with the query string only when it is not stripped.
+func loggedPath(r *http.Request, cfg *config.RequestLogConfig) string {
+	if cfg.ScrubPaths {
+		return routeTemplate(r)
+	}
+	if !cfg.StripQueryStrings && r.URL.RawQuery != "" {
+		return r.URL.Path + "?" + r.URL.RawQuery
+	}
+	return r.URL.Path
+}
+
+// createRequestSummary describes the request for the user's history.
+// resultCount is the X-Result-Count response header, empty when the
+// response did not list landmarks. With scrub set it leaves out the search
+// term of name searches.
+func createRequestSummary(r *http.Request, scrub bool, resultCount string) string {
 	parts := strings.Split(r.URL.Path, "/")
 	summary := "API request"
 
-	if len(parts) >= 4 && parts[1] == "api" && parts[2] == "v1" && parts[3] == "landmarks" {
+	if len(parts) >= 4 && parts[1] == "api" && slices.Contains(apiversion.All, apiversion.Version(parts[2])) && parts[3] == "landmarks" {
+		landmarks := countLandmarks(resultCount)
 		switch {
 		case len(parts) > 5 && parts[4] == "country":
-			summary = fmt.Sprintf("Explored %d landmarks across the beautiful country of %s", rand.Intn(16)+5, parts[5])
+			summary = fmt.Sprintf("Explored %s across the beautiful country of %s", landmarks, parts[5])
 		case len(parts) > 5 && parts[4] == "city":
-			summary = fmt.Sprintf("Discovered %d fascinating landmarks in the vibrant city of %s", rand.Intn(16)+5, parts[5])
+			summary = fmt.Sprintf("Discovered %s in the vibrant city of %s", landmarks, parts[5])
+		case len(parts) > 5 && parts[4] == "name" && scrub:
+			summary = fmt.Sprintf("Searched for landmark by name and found %s", landmarks)
 		case len(parts) > 5 && parts[4] == "name":
-			summary = fmt.Sprintf("Searched for landmark by name: %s", parts[5])
+			summary = fmt.Sprintf("Searched for landmark by name: %s and found %s", parts[5], landmarks)
 		case len(parts) > 5 && parts[4] == "category":
-			summary = fmt.Sprintf("Explored %d landmarks in the %s category", rand.Intn(16)+5, parts[5])
+			summary = fmt.Sprintf("Explored %s in the %s category", landmarks, parts[5])
 		case len(parts) == 4:
-			summary = fmt.Sprintf("Retrieved overview of %d landmarks", rand.Intn(51)+50)
+			summary = fmt.Sprintf("Retrieved overview of %s", landmarks)
 		default:
 			summary = "Processed landmarks data request"
 		}
@@ -109,3 +174,16 @@ func createRequestSummary(r *http.Request) string {
 
 	return summary
 }
+
+// countLandmarks phrases a result count, such as "3 landmarks", or just
+// "landmarks" when the count is unknown.
+func countLandmarks(resultCount string) string {
+	count, err := strconv.Atoi(resultCount)
+	switch {
+	case err != nil:
+		return "landmarks"
+	case count == 1:
+		return "1 landmark"
+	}
+	return fmt.Sprintf("%d landmarks", count)
+}
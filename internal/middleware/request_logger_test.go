@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateRequestSummary(t *testing.T) {
+	tests := []struct {
+		path  string
+		count string
+		want  string
+	}{
+		{"/api/v1/landmarks", "3", "Retrieved overview of 3 landmarks"},
+		{"/api/v2/landmarks", "3", "Retrieved overview of 3 landmarks"},
+		{"/api/v2/landmarks/city/Paris", "1", "Discovered 1 landmark in the vibrant city of Paris"},
+		{"/api/v2/landmarks/country/France", "", "Explored landmarks across the beautiful country of France"},
+		{"/api/v9/landmarks", "3", "API request"},
+		{"/user/api/v1/usage", "", "API request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.path, nil)
+			if got := createRequestSummary(r, false, tt.count); got != tt.want {
+				t.Errorf("createRequestSummary(%s) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
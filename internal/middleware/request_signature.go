@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxSignatureSkew is how far a signature's timestamp may be from the
+// server clock, which bounds how long a captured request can be replayed.
+const maxSignatureSkew = 5 * time.Minute
+
+// RequireSignature rejects requests made with an API key that requires
+// signing unless they carry a valid, recent signature. It runs before the
+// API key authenticator, which would otherwise grant access on the key
+// alone, and passes the key it looked up on to it in the request context.
+// Unknown keys are rejected; requests with other keys, or none, pass
+// through untouched.
+func RequireSignature(apiKeyService services.APIKeyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("x-api-key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			apiKey, err := apiKeyService.GetAPIKeyByKey(r.Context(), key)
+			if errors.Is(err, apperrors.ErrNotFound) {
+				writeError(w, apperrors.CodeInvalidAPIKey, "Invalid API key")
+				return
+			}
+			if err != nil {
+				writeError(w, apperrors.CodeInternal, "Failed to verify API key")
+				return
+			}
+			r = r.WithContext(services.WithAPIKeyContext(r.Context(), apiKey))
+			if !apiKey.RequireSignature {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			timestamp, err := strconv.ParseInt(r.Header.Get(services.SignatureTimestampHeader), 10, 64)
+			if err != nil {
+				writeError(w, apperrors.CodeInvalidSignature, "This API key requires signed requests")
+				return
+			}
+			if skew := time.Since(time.Unix(timestamp, 0)); skew > maxSignatureSkew || skew < -maxSignatureSkew {
+				writeError(w, apperrors.CodeInvalidSignature, "Request signature has expired")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					writeError(w, apperrors.CodePayloadTooLarge, "Request body too large")
+					return
+				}
+				writeError(w, apperrors.CodeInvalidRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			signature := r.Header.Get(services.SignatureHeader)
+			if !services.ValidRequestSignature(apiKey.SigningSecret, signature, timestamp, r.Method, r.URL.RequestURI(), body) {
+				writeError(w, apperrors.CodeInvalidSignature, "Invalid request signature")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeAPIKeys looks keys up in keys, failing with err if it is set.
+type fakeAPIKeys struct {
+	services.APIKeyService
+	keys map[string]*models.APIKey
+	err  error
+}
+
+func (s *fakeAPIKeys) GetAPIKeyByKey(ctx context.Context, key string) (*models.APIKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	apiKey, ok := s.keys[key]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return apiKey, nil
+}
+
+func TestRequireSignature(t *testing.T) {
+	plain := &models.APIKey{Key: "plain"}
+	signed := &models.APIKey{Key: "signed", RequireSignature: true, SigningSecret: "secret"}
+	keys := map[string]*models.APIKey{plain.Key: plain, signed.Key: signed}
+
+	tests := []struct {
+		name    string
+		key     string
+		err     error
+		status  int
+		wantKey *models.APIKey
+	}{
+		{"no key", "", nil, http.StatusOK, nil},
+		{"key without signing", plain.Key, nil, http.StatusOK, plain},
+		{"unsigned request", signed.Key, nil, http.StatusUnauthorized, nil},
+		{"unknown key", "unknown", nil, http.StatusUnauthorized, nil},
+		{"lookup error", plain.Key, errors.New("database down"), http.StatusInternalServerError, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKey *models.APIKey
+			handler := RequireSignature(&fakeAPIKeys{keys: keys, err: tt.err})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotKey, _ = services.APIKeyFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/landmarks", nil)
+			if tt.key != "" {
+				req.Header.Set("x-api-key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if gotKey != tt.wantKey {
+				t.Errorf("key in context = %v, want %v", gotKey, tt.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Timeout gives each request a context deadline: the route's entry in
+// cfg.RouteTimeouts, or cfg.HandlerTimeout. Handlers stop their database and
+// cache work once the deadline passes.
+func Timeout(cfg *config.ServerConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := routeTimeout(cfg, r)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func routeTimeout(cfg *config.ServerConfig, r *http.Request) time.Duration {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			if timeout, ok := cfg.RouteTimeouts[r.Method+" "+template]; ok {
+				return timeout
+			}
+		}
+	}
+	return cfg.HandlerTimeout
+}
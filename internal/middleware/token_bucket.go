@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBuckets smooths each key's requests to a steady rate while letting
+// short bursts through: a key's bucket holds up to burst tokens, refilled
+// at the rate per minute, and each request takes one. Buckets are kept per
+// instance, like the in-flight counts of ConcurrencyLimiter.
+type tokenBuckets struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket will be full again if left alone.
+	full time.Time
+}
+
+func newTokenBuckets() *tokenBuckets {
+	return &tokenBuckets{buckets: make(map[string]*tokenBucket), lastSweep: time.Now()}
+}
+
+// take takes a token from key's bucket, which starts full, and returns how
+// many are left. When the bucket is empty it returns false and how long
+// until a token is back.
+func (b *tokenBuckets) take(key string, perMinute, burst int, now time.Time) (bool, int, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rate := float64(perMinute) / 60
+	b.sweep(now)
+
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), updated: now}
+		b.buckets[key] = bucket
+	}
+	// The capacity and rate come with every call, so a changed plan
+	// applies to the bucket from its next request
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	taken := bucket.tokens >= 1
+	if taken {
+		bucket.tokens--
+	}
+	bucket.full = now.Add(refillTime(float64(burst)-bucket.tokens, rate))
+	if !taken {
+		return false, 0, refillTime(1-bucket.tokens, rate)
+	}
+	return true, int(bucket.tokens), 0
+}
+
+// refillTime is how long refilling tokens takes at rate per second.
+func refillTime(tokens, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+// sweep drops, once a minute, the buckets idle long enough to be full
+// again, as a new bucket would be the same. b.mu must be held.
+func (b *tokenBuckets) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < time.Minute {
+		return
+	}
+	b.lastSweep = now
+	for key, bucket := range b.buckets {
+		if !now.Before(bucket.full) {
+			delete(b.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"landmark-api/internal/tracing"
+	"net/http"
+)
+
+// TracingMiddleware continues the caller's W3C trace, or starts a new one,
+// and echoes the trace context in the response headers.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := tracing.FromRequest(r)
+
+		w.Header().Set(tracing.TraceparentHeader, sc.Traceparent())
+		w.Header().Set(tracing.TraceIDHeader, sc.TraceID)
+		if sc.TraceState != "" {
+			w.Header().Set(tracing.TracestateHeader, sc.TraceState)
+		}
+
+		next.ServeHTTP(w, r.WithContext(tracing.NewContext(r.Context(), sc)))
+	})
+}
@@ -1,210 +1,237 @@
-// internal/migrations/landmarks.go
-
+// Package migrations applies and reverts versioned schema changes. Applied
+// versions are recorded in the schema_migrations table. Seed data does not
+// belong here; see the seed package.
 package migrations
 
-/*
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"landmark-api/internal/models"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
-func MigrateLandmarks(db *gorm.DB) error {
-	// First, migrate the schema
-	err := db.AutoMigrate(&models.Landmark{}, &models.LandmarkDetail{})
-	if err != nil {
-		return err
+var (
+	ErrUnknownMigration = errors.New("database has a migration this build does not know")
+	ErrNoDownMigration  = errors.New("migration cannot be reverted")
+
+	errDryRun = errors.New("dry run")
+)
+
+// lockID is the Postgres advisory lock held while migrating, so instances
+// rolling out together don't race on the schema.
+const lockID int64 = 0x6c616e646d61726b
+
+// Migration is one versioned schema change. IDs sort in the order the
+// migrations apply, e.g. 0002_add_sessions. Down reverts Up; it may be nil
+// for changes that cannot be undone.
+type Migration struct {
+	ID   string
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// Status is whether a known migration has been applied.
+type Status struct {
+	ID        string
+	AppliedAt *time.Time
+}
+
+// Options control a run of Up or Down.
+type Options struct {
+	// Steps limits how many migrations are applied or reverted. Up applies
+	// all pending migrations when Steps is 0; Down reverts one.
+	Steps int
+	// DryRun runs the migrations in a transaction that is rolled back,
+	// printing the statements they would execute to Out.
+	DryRun bool
+	Out    io.Writer
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a Migrator for the given migrations, or for All when
+// none are given.
+func NewMigrator(db *gorm.DB, migrations ...Migration) *Migrator {
+	if len(migrations) == 0 {
+		migrations = All
 	}
+	return &Migrator{db: db, migrations: migrations}
+}
 
-	// Check if we already have landmarks
-	var count int64
-	db.Model(&models.Landmark{}).Count(&count)
-	if count > 0 {
-		return nil // Skip if landmarks already exist
+// Status lists every known migration in order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
 	}
 
-	// Create landmarks with their details
-	landmarks := []struct {
-		landmark models.Landmark
-		details  models.LandmarkDetail
-	}{
-		{
-			landmark: models.Landmark{
-				ID:          uuid.New(),
-				Name:        "Eiffel Tower",
-				Description: "Iconic iron lattice tower located on the Champ de Mars in Paris. Built in 1889, it has become both a global cultural icon of France and one of the most recognizable structures in the world.",
-				Latitude:    48.8584,
-				Longitude:   2.2945,
-				Country:     "France",
-				City:        "Paris",
-				Category:    "Architecture",
-				ImageUrl:    "https://images.example.com/eiffel-tower.jpg",
-			},
-			details: models.LandmarkDetail{
-				ID: uuid.New(),
-				OpeningHours: map[string]string{
-					"Monday-Sunday": "09:00-00:45",
-				},
-				TicketPrices: map[string]float64{
-					"Adult":    26.10,
-					"Youth":    13.10,
-					"Children": 6.60,
-				},
-				HistoricalSignificance: "Built for the 1889 World's Fair, the Eiffel Tower commemorated the centennial of the French Revolution.",
-				VisitorTips:            "Book tickets online in advance to avoid long queues. Visit during sunset for spectacular views.",
-				AccessibilityInfo:      "Accessible by elevator to the second floor. Wheelchair access available.",
-			},
-		},
-		{
-			landmark: models.Landmark{
-				ID:          uuid.New(),
-				Name:        "Great Wall of China",
-				Description: "Ancient series of walls and fortifications, built across the historical northern borders of ancient Chinese states and Imperial China as protection.",
-				Latitude:    40.4319,
-				Longitude:   116.5704,
-				Country:     "China",
-				City:        "Beijing",
-				Category:    "Historical",
-				ImageUrl:    "https://images.example.com/great-wall.jpg",
-			},
-			details: models.LandmarkDetail{
-				ID: uuid.New(),
-				OpeningHours: map[string]string{
-					"Monday-Sunday": "07:30-17:30",
-				},
-				TicketPrices: map[string]float64{
-					"Adult":    45.00,
-					"Student":  25.00,
-					"Children": 0.00,
-				},
-				HistoricalSignificance: "Built over many centuries by various dynasties, it's one of the most impressive architectural feats in human history.",
-				VisitorTips:            "Visit the Mutianyu section for fewer crowds and better preserved walls. Bring comfortable walking shoes.",
-				AccessibilityInfo:      "Some sections have cable cars for easier access.",
-			},
-		},
-		{
-			landmark: models.Landmark{
-				ID:          uuid.New(),
-				Name:        "Taj Mahal",
-				Description: "An ivory-white marble mausoleum on the right bank of the river Yamuna, commissioned in 1632 by the Mughal emperor Shah Jahan to house the tomb of his favorite wife.",
-				Latitude:    27.1751,
-				Longitude:   78.0421,
-				Country:     "India",
-				City:        "Agra",
-				Category:    "Architecture",
-				ImageUrl:    "https://images.example.com/taj-mahal.jpg",
-			},
-			details: models.LandmarkDetail{
-				ID: uuid.New(),
-				OpeningHours: map[string]string{
-					"Monday-Sunday": "06:00-18:30",
-					"Friday":        "Closed",
-				},
-				TicketPrices: map[string]float64{
-					"Foreign Tourist": 1100.00,
-					"Indian Citizen":  50.00,
-					"Children":        0.00,
-				},
-				HistoricalSignificance: "Built as a testament of love by Shah Jahan for his beloved wife Mumtaz Mahal.",
-				VisitorTips:            "Visit during sunrise for the best photographs. Bring shoe covers or be prepared to remove shoes.",
-				AccessibilityInfo:      "Wheelchair availability at entrance. Golf cart transport available.",
-			},
-		},
-		{
-			landmark: models.Landmark{
-				ID:          uuid.New(),
-				Name:        "Machu Picchu",
-				Description: "15th-century Inca citadel set high in the Andes Mountains, built with sophisticated dry stone walls that fuse huge blocks without mortar.",
-				Latitude:    -13.1631,
-				Longitude:   -72.5450,
-				Country:     "Peru",
-				City:        "Cusco Region",
-				Category:    "Archaeological",
-				ImageUrl:    "https://images.example.com/machu-picchu.jpg",
-			},
-			details: models.LandmarkDetail{
-				ID: uuid.New(),
-				OpeningHours: map[string]string{
-					"Monday-Sunday": "06:00-17:30",
-				},
-				TicketPrices: map[string]float64{
-					"Foreign Adult": 152.00,
-					"Student":       77.00,
-					"Children":      70.00,
-				},
-				HistoricalSignificance: "Ancient Incan city that shows their architectural and agricultural prowess.",
-				VisitorTips:            "Book tickets months in advance. Acclimatize to the altitude before visiting.",
-				AccessibilityInfo:      "Limited accessibility due to steep terrain and historic preservation.",
-			},
-		},
-		{
-			landmark: models.Landmark{
-				ID:          uuid.New(),
-				Name:        "Petra",
-				Description: "Ancient city famous for its rock-cut architecture and water conduit system, established possibly as early as 312 BCE.",
-				Latitude:    30.3285,
-				Longitude:   35.4444,
-				Country:     "Jordan",
-				City:        "Ma'an Governorate",
-				Category:    "Archaeological",
-				ImageUrl:    "https://images.example.com/petra.jpg",
-			},
-			details: models.LandmarkDetail{
-				ID: uuid.New(),
-				OpeningHours: map[string]string{
-					"Summer": "06:00-18:00",
-					"Winter": "06:00-16:00",
-				},
-				TicketPrices: map[string]float64{
-					"One Day":    50.00,
-					"Two Days":   55.00,
-					"Three Days": 60.00,
-				},
-				HistoricalSignificance: "Capital of the Nabataean Kingdom, showcasing advanced architectural and engineering capabilities.",
-				VisitorTips:            "Start early to avoid heat. Comfortable walking shoes essential.",
-				AccessibilityInfo:      "Horse carriages available for transportation within the site.",
-			},
-		},
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i].ID = migration.ID
+		if record, ok := applied[migration.ID]; ok {
+			appliedAt := record.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
 	}
+	return statuses, nil
+}
+
+// Up applies pending migrations in order and returns the IDs it applied.
+func (m *Migrator) Up(ctx context.Context, opts Options) (ran []string, err error) {
+	err = m.locked(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
 
-	// Create each landmark and its details in a transaction
-	for _, item := range landmarks {
-		err := db.Transaction(func(tx *gorm.DB) error {
-			// Set timestamps
-			now := time.Now()
-			item.landmark.CreatedAt = now
-			item.landmark.UpdatedAt = now
-			item.details.CreatedAt = now
-			item.details.UpdatedAt = now
-
-			// Create the landmark
-			if err := tx.Create(&item.landmark).Error; err != nil {
-				return err
+		for _, migration := range m.migrations {
+			if opts.Steps > 0 && len(ran) == opts.Steps {
+				break
+			}
+			if _, ok := applied[migration.ID]; ok {
+				continue
 			}
 
-			// Set the landmark ID in details
-			item.details.LandmarkID = item.landmark.ID
+			err := m.run(db, opts, migration.ID, migration.Up, func(tx *gorm.DB) error {
+				return tx.Create(&models.SchemaMigration{ID: migration.ID, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error applying migration %s: %w", migration.ID, err)
+			}
+			ran = append(ran, migration.ID)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// Down reverts the most recently applied migrations, newest first, and
+// returns the IDs it reverted.
+func (m *Migrator) Down(ctx context.Context, opts Options) (ran []string, err error) {
+	err = m.locked(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(db)
+		if err != nil {
+			return err
+		}
 
-			// Create the details
-			if err := tx.Create(&item.details).Error; err != nil {
-				return err
+		steps := opts.Steps
+		if steps <= 0 {
+			steps = 1
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.ID]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("%w: %s", ErrNoDownMigration, migration.ID)
 			}
 
-			return nil
-		})
+			err := m.run(db, opts, migration.ID, migration.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&models.SchemaMigration{ID: migration.ID}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("error reverting migration %s: %w", migration.ID, err)
+			}
+			ran = append(ran, migration.ID)
+		}
+		return nil
+	})
+	return ran, err
+}
 
-		if err != nil {
+// locked runs fn on a single connection holding the migration lock.
+// Instances starting together wait for the first, then find its migrations
+// applied.
+func (m *Migrator) locked(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(db *gorm.DB) error {
+		if err := db.Exec("SELECT pg_advisory_lock(?)", lockID).Error; err != nil {
+			return fmt.Errorf("error taking the migration lock: %w", err)
+		}
+		defer db.Exec("SELECT pg_advisory_unlock(?)", lockID)
+		return fn(db)
+	})
+}
+
+// run executes change and record in one transaction. Postgres DDL is
+// transactional, so a failed migration leaves no partial schema behind and
+// a dry run can simply roll back.
+func (m *Migrator) run(db *gorm.DB, opts Options, id string, change, record func(tx *gorm.DB) error) error {
+	if opts.DryRun {
+		fmt.Fprintf(opts.Out, "-- %s\n", id)
+		db = db.Session(&gorm.Session{Logger: &statementPrinter{out: opts.Out}})
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := change(tx); err != nil {
 			return err
 		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return record(tx)
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
 	}
+	return err
+}
 
-	return nil
+// applied returns the recorded migrations, creating the bookkeeping table
+// on first use. It fails when the database is ahead of this build.
+func (m *Migrator) applied(db *gorm.DB) (map[string]models.SchemaMigration, error) {
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	var records []models.SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	known := make(map[string]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.ID] = true
+	}
+
+	applied := make(map[string]models.SchemaMigration, len(records))
+	for _, record := range records {
+		if !known[record.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMigration, record.ID)
+		}
+		applied[record.ID] = record
+	}
+	return applied, nil
 }
 
-// Helper function to create a UUID
-func newUUID() uuid.UUID {
-	return uuid.New()
+// statementPrinter is a GORM logger that prints the statements a dry run
+// would execute. Reads, such as schema introspection, are left out.
+type statementPrinter struct {
+	out io.Writer
+}
+
+func (p *statementPrinter) LogMode(logger.LogLevel) logger.Interface { return p }
+
+func (p *statementPrinter) Info(context.Context, string, ...interface{}) {}
+
+func (p *statementPrinter) Warn(context.Context, string, ...interface{}) {}
+
+func (p *statementPrinter) Error(context.Context, string, ...interface{}) {}
+
+func (p *statementPrinter) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+		return
+	}
+	fmt.Fprintf(p.out, "%s;\n", sql)
 }
-*/
@@ -0,0 +1,609 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The structs below are frozen copies of the tables and columns each
+// migration creates, so a migration applies the same schema however the
+// models change after it has shipped. Structs of migrations that add
+// columns to an existing table only list those columns, as AutoMigrate
+// never drops the ones it is not given. Fields only carry the column
+// definitions, so JSON columns are plain strings.
+//
+// Never edit a snapshot; change the schema with a new migration instead.
+
+// Baseline: the schema as it stood when versioned migrations were
+// introduced.
+
+type baselineUser struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	Name            string           `gorm:"type:varchar(255);not null"`
+	Email           string           `gorm:"type:varchar(255);uniqueIndex;not null"`
+	PasswordHash    string           `gorm:"type:varchar(255);not null"`
+	Role            string           `gorm:"type:varchar(255);not null;default:'user'"`
+	APIKeys         []baselineAPIKey `gorm:"foreignkey:UserID"`
+	StripeID        string           `gorm:"type:varchar(255);not null;default:''"`
+	HasAccess       bool             `gorm:"type:boolean;not null;default:false"`
+	OnBoarding      bool             `gorm:"type:boolean;not null;default:false"`
+	AccessGrantedAt time.Time        `gorm:"default:null"`
+	AccessRevokedAt time.Time        `gorm:"default:null"`
+	CreatedAt       time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt       gorm.DeletedAt   `gorm:"index"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineAPIKey struct {
+	ID        uuid.UUID `gorm:"type:uuid"`
+	UserID    uuid.UUID `gorm:"type:uuid"`
+	Key       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineAPIKey) TableName() string { return "api_keys" }
+
+type baselineSubscription struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	UserID            uuid.UUID      `gorm:"type:uuid;not null;index"`
+	PlanType          string         `gorm:"type:varchar(20);not null"`
+	StripeCustomerID  string         `gorm:"type:varchar(255);not null;default:''"`
+	StripePlanID      string         `gorm:"type:varchar(255);not nulldefault:''"`
+	StartDate         time.Time      `gorm:"not null"`
+	EndDate           time.Time      `gorm:"default:null"`
+	Status            string         `gorm:"type:varchar(50);not null"`
+	CancelAtPeriodEnd bool           `gorm:"not null;default:false"`
+	TrialEndsAt       *time.Time     `gorm:"default:null"`
+	IsComp            bool           `gorm:"not null;default:false"`
+	CreatedAt         time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt         time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+	User              baselineUser   `gorm:"foreignKey:UserID"`
+}
+
+func (baselineSubscription) TableName() string { return "subscriptions" }
+
+type baselineLandmark struct {
+	ID             uuid.UUID               `gorm:"type:uuid;primaryKey"`
+	Name           string                  `gorm:"type:varchar(255);not null"`
+	Description    string                  `gorm:"type:text;not null"`
+	Latitude       float64                 `gorm:"type:decimal(10,8);not null"`
+	Longitude      float64                 `gorm:"type:decimal(11,8);not null"`
+	Country        string                  `gorm:"type:varchar(100);not null"`
+	City           string                  `gorm:"type:varchar(100);not null"`
+	Category       string                  `gorm:"type:varchar(50);not null"`
+	ImageUrl       string                  `gorm:"type:varchar(255)"`
+	Images         []baselineLandmarkImage `gorm:"foreignKey:LandmarkID"`
+	LastVerifiedAt *time.Time              `gorm:"default:null"`
+	DataConfidence float64                 `gorm:"type:decimal(3,2);not null;default:0"`
+	CreatedAt      time.Time               `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time               `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt      gorm.DeletedAt          `gorm:"index"`
+}
+
+func (baselineLandmark) TableName() string { return "landmarks" }
+
+type baselineLandmarkImage struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LandmarkID uuid.UUID `gorm:"type:uuid;not null"`
+	ImageURL   string    `gorm:"type:varchar(500);not null"`
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineLandmarkImage) TableName() string { return "landmark_images" }
+
+type baselineLandmarkDetail struct {
+	ID                     uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	LandmarkID             uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex"`
+	OpeningHours           string         `gorm:"type:jsonb"`
+	TicketPrices           string         `gorm:"type:jsonb"`
+	HistoricalSignificance string         `gorm:"type:text"`
+	VisitorTips            string         `gorm:"type:text"`
+	AccessibilityInfo      string         `gorm:"type:text"`
+	CreatedAt              time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt              time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt              gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineLandmarkDetail) TableName() string { return "landmark_details" }
+
+type baselineSubmissionLandmark struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name        string    `gorm:"type:varchar(255);not null"`
+	Description string    `gorm:"type:text;not null"`
+	Latitude    float64   `gorm:"type:decimal(10,8);not null"`
+	Longitude   float64   `gorm:"type:decimal(11,8);not null"`
+	Country     string    `gorm:"type:varchar(100);not null"`
+	City        string    `gorm:"type:varchar(100);not null"`
+	Category    string    `gorm:"type:varchar(50);not null"`
+	Status      string
+	Images      []baselineSubmissionLandmarkImage `gorm:"foreignKey:SubmissionLandmarkID"`
+	Detail      baselineSubmissionLandmarkDetail  `gorm:"foreignKey:SubmissionLandmarkID;references:ID"`
+	CreatedAt   time.Time                         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time                         `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineSubmissionLandmark) TableName() string { return "submission_landmarks" }
+
+type baselineSubmissionLandmarkImage struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubmissionLandmarkID uuid.UUID `gorm:"type:uuid;not null"`
+	ImageURL             string    `gorm:"type:varchar(500);not null"`
+	CreatedAt            time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt            time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineSubmissionLandmarkImage) TableName() string { return "submission_landmark_images" }
+
+type baselineSubmissionLandmarkDetail struct {
+	ID                     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubmissionLandmarkID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	OpeningHours           string    `gorm:"type:jsonb"`
+	TicketPrices           string    `gorm:"type:jsonb"`
+	HistoricalSignificance string    `gorm:"type:text"`
+	VisitorTips            string    `gorm:"type:text"`
+	AccessibilityInfo      string    `gorm:"type:text"`
+	CreatedAt              time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt              time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineSubmissionLandmarkDetail) TableName() string { return "submission_landmark_details" }
+
+type baselineRequestLog struct {
+	ID          uint   `gorm:"primarykey"`
+	UserID      string `gorm:"index"`
+	Endpoint    string `gorm:"index"`
+	Route       string `gorm:"index"`
+	Method      string
+	Status      string
+	StatusCode  int
+	Summary     string
+	TraceID     string `gorm:"index"`
+	DurationMs  int64
+	CacheStatus string
+	Timestamp   time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (baselineRequestLog) TableName() string { return "request_logs" }
+
+type baselineUsageReport struct {
+	ID                       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubscriptionID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_usage_report_sub_date"`
+	UserID                   uuid.UUID `gorm:"type:uuid;not null;index"`
+	StripeSubscriptionItemID string    `gorm:"type:varchar(255);not null"`
+	ReportDate               time.Time `gorm:"type:date;not null;uniqueIndex:idx_usage_report_sub_date"`
+	PeriodStart              time.Time `gorm:"not null"`
+	PeriodEnd                time.Time `gorm:"not null"`
+	Quantity                 int64     `gorm:"not null"`
+	IdempotencyKey           string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	StripeUsageRecordID      string    `gorm:"type:varchar(255)"`
+	Status                   string    `gorm:"type:varchar(20);not null"`
+	Error                    string    `gorm:"type:text"`
+	CreatedAt                time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt                time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineUsageReport) TableName() string { return "usage_reports" }
+
+type baselineSearchAnalytics struct {
+	ID         uint      `gorm:"primarykey"`
+	SearchType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_search_analytics_type_term_day"`
+	Term       string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_search_analytics_type_term_day"`
+	Day        time.Time `gorm:"type:date;not null;uniqueIndex:idx_search_analytics_type_term_day;index"`
+	Count      int64     `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (baselineSearchAnalytics) TableName() string { return "search_analytics" }
+
+type baselineAPIKeyLimit struct {
+	ID           uint      `gorm:"primarykey"`
+	APIKeyID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	RequestLimit int       `gorm:"not null"`
+	Reason       string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineAPIKeyLimit) TableName() string { return "api_key_limits" }
+
+type baselineAuditLog struct {
+	gorm.Model
+	AdminID    uuid.UUID `gorm:"type:uuid;index"`
+	IPAddress  string    `gorm:"type:varchar(45)"`
+	Action     string    `gorm:"index"`
+	EntityType string    `gorm:"index"`
+	EntityID   string
+	Details    string
+	Changes    string    `gorm:"type:jsonb"`
+	Timestamp  time.Time `gorm:"index"`
+}
+
+func (baselineAuditLog) TableName() string { return "audit_logs" }
+
+type baselineRequestLogExport struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	From        time.Time `gorm:"not null"`
+	To          time.Time `gorm:"not null"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	RowCount    int64     `gorm:"not null;default:0"`
+	Content     []byte    `gorm:"type:bytea"`
+	Error       string    `gorm:"type:text"`
+	CompletedAt *time.Time
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineRequestLogExport) TableName() string { return "request_log_exports" }
+
+type baselineCategory struct {
+	ID        uint      `gorm:"primarykey"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex"`
+	ParentID  *uint     `gorm:"index"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineCategory) TableName() string { return "categories" }
+
+type baselineFeatureFlag struct {
+	ID             uint      `gorm:"primarykey"`
+	Key            string    `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description    string    `gorm:"type:text"`
+	Enabled        bool      `gorm:"not null;default:false"`
+	RolloutPercent int       `gorm:"not null;default:0"`
+	TargetPlans    string    `gorm:"type:jsonb;not null;default:'[]'"`
+	TargetUserIDs  string    `gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineFeatureFlag) TableName() string { return "feature_flags" }
+
+type baselineLandmarkStatsSnapshot struct {
+	ID                  uint      `gorm:"primarykey"`
+	Date                time.Time `gorm:"type:date;not null;uniqueIndex"`
+	TotalLandmarks      int64     `gorm:"not null"`
+	LandmarksAdded      int64     `gorm:"not null"`
+	SubmissionsCreated  int64     `gorm:"not null"`
+	SubmissionsApproved int64     `gorm:"not null"`
+	SubmissionsRejected int64     `gorm:"not null"`
+	CreatedAt           time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt           time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineLandmarkStatsSnapshot) TableName() string { return "landmark_stats_snapshots" }
+
+// 0002_sessions
+
+type session0002 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Device    string    `gorm:"type:varchar(255)"`
+	IPAddress string    `gorm:"type:varchar(45)"`
+	IssuedAt  time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	RevokedAt *time.Time
+}
+
+func (session0002) TableName() string { return "sessions" }
+
+// 0003_api_key_signing
+
+type apiKey0003 struct {
+	SigningSecret    string `gorm:"type:varchar(64)"`
+	RequireSignature bool   `gorm:"not null;default:false"`
+}
+
+func (apiKey0003) TableName() string { return "api_keys" }
+
+// 0004_api_key_expiry
+
+type apiKey0004 struct {
+	ExpiresAt            *time.Time `gorm:"index"`
+	ExpiryReminderDays   int        `gorm:"not null;default:0"`
+	PreviousKey          string     `gorm:"index"`
+	PreviousKeyExpiresAt *time.Time
+}
+
+func (apiKey0004) TableName() string { return "api_keys" }
+
+// 0005_landmark_timezone
+
+type landmark0005 struct {
+	Timezone string `gorm:"type:varchar(64);not null;default:''"`
+}
+
+func (landmark0005) TableName() string { return "landmarks" }
+
+// 0006_landmark_enrichments
+
+type landmarkEnrichment0006 struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LandmarkID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	WikidataID      string    `gorm:"type:varchar(32);not null;default:''"`
+	WikipediaURL    string    `gorm:"type:varchar(500)"`
+	Summary         string    `gorm:"type:text"`
+	OfficialWebsite string    `gorm:"type:varchar(500)"`
+	HeritageStatus  string    `gorm:"type:jsonb"`
+	Images          string    `gorm:"type:jsonb"`
+	EnrichedAt      time.Time `gorm:"not null;index"`
+	CreatedAt       time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (landmarkEnrichment0006) TableName() string { return "landmark_enrichments" }
+
+// 0007_osm_imports
+
+type osmImport0007 struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	AdminID     uuid.UUID `gorm:"type:uuid;not null"`
+	BBox        string    `gorm:"type:varchar(100);not null"`
+	Categories  string    `gorm:"type:jsonb;not null"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	Created     int       `gorm:"not null;default:0"`
+	Duplicates  int       `gorm:"not null;default:0"`
+	Skipped     int       `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"`
+	CompletedAt *time.Time
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (osmImport0007) TableName() string { return "osm_imports" }
+
+type submissionLandmark0007 struct {
+	Source string `gorm:"type:varchar(64);index"`
+}
+
+func (submissionLandmark0007) TableName() string { return "submission_landmarks" }
+
+// 0008_landmark_custom_fields
+
+type landmarkCustomFields0008 struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_landmark_custom_fields_user_landmark"`
+	LandmarkID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_landmark_custom_fields_user_landmark;index"`
+	Fields     string    `gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (landmarkCustomFields0008) TableName() string { return "landmark_custom_fields" }
+
+// 0009_landmark_image_gallery
+
+type landmarkImage0009 struct {
+	Caption      string `gorm:"type:text;not null;default:''"`
+	Credit       string `gorm:"type:varchar(255);not null;default:''"`
+	DisplayOrder int    `gorm:"not null;default:0"`
+	IsPrimary    bool   `gorm:"not null;default:false"`
+}
+
+func (landmarkImage0009) TableName() string { return "landmark_images" }
+
+// 0010_landmark_media_types
+
+type landmarkImage0010 struct {
+	MediaType  string `gorm:"type:varchar(16);not null;default:'photo'"`
+	Provider   string `gorm:"type:varchar(16);not null;default:''"`
+	ProviderID string `gorm:"type:varchar(64);not null;default:''"`
+	StreamURL  string `gorm:"type:varchar(500);not null;default:''"`
+}
+
+func (landmarkImage0010) TableName() string { return "landmark_images" }
+
+// 0011_landmark_entry_prices
+
+type landmarkDetail0011 struct {
+	EntryPrice    *float64 `gorm:"type:decimal(10,2);index"`
+	EntryCurrency string   `gorm:"type:varchar(3);not null;default:''"`
+}
+
+func (landmarkDetail0011) TableName() string { return "landmark_details" }
+
+// 0012_landmark_tags
+
+type landmark0012 struct {
+	Tags string `gorm:"type:jsonb;not null;default:'[]'"`
+}
+
+func (landmark0012) TableName() string { return "landmarks" }
+
+// 0013_landmark_status
+
+type landmark0013 struct {
+	Status string `gorm:"type:varchar(16);not null;default:'published';index"`
+}
+
+func (landmark0013) TableName() string { return "landmarks" }
+
+// 0014_request_log_response_body
+
+type requestLog0014 struct {
+	ResponseBody string `gorm:"type:text"`
+}
+
+func (requestLog0014) TableName() string { return "request_logs" }
+
+// 0015_notifications
+
+type notification0015 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_notification_user_created"`
+	Kind      string    `gorm:"type:varchar(20);not null"`
+	Type      string    `gorm:"type:varchar(50);not null"`
+	Title     string    `gorm:"type:varchar(255);not null"`
+	Body      string    `gorm:"type:text;not null"`
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"not null;index:idx_notification_user_created"`
+}
+
+func (notification0015) TableName() string { return "notifications" }
+
+type submissionLandmark0015 struct {
+	UserID *uuid.UUID `gorm:"type:uuid;index"`
+}
+
+func (submissionLandmark0015) TableName() string { return "submission_landmarks" }
+
+// 0016_mail_suppressions
+
+type mailSuppression0016 struct {
+	Address   string    `gorm:"type:varchar(255);primaryKey"`
+	Type      string    `gorm:"type:varchar(20);not null"`
+	Reason    string    `gorm:"type:text"`
+	Provider  string    `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (mailSuppression0016) TableName() string { return "mail_suppressions" }
+
+// 0017_onboarding_emails
+
+type onboardingEmail0017 struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Step   string    `gorm:"type:varchar(20);primaryKey"`
+	SentAt time.Time `gorm:"not null"`
+}
+
+func (onboardingEmail0017) TableName() string { return "onboarding_emails" }
+
+type user0017 struct {
+	OnboardingOptOut bool `gorm:"type:boolean;not null;default:false"`
+}
+
+func (user0017) TableName() string { return "users" }
+
+// 0018_request_credits
+
+type requestCredit0018 struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	StripeSessionID string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Pack            string    `gorm:"type:varchar(20);not null"`
+	Purchased       int64     `gorm:"not null"`
+	Remaining       int64     `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"not null"`
+}
+
+func (requestCredit0018) TableName() string { return "request_credits" }
+
+// 0019_usage_statements
+
+type usageStatement0019 struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_usage_statement_user_period"`
+	PeriodStart  time.Time `gorm:"not null;uniqueIndex:idx_usage_statement_user_period"`
+	PeriodEnd    time.Time `gorm:"not null"`
+	Plan         string    `gorm:"type:varchar(20);not null"`
+	Requests     int64     `gorm:"not null"`
+	Quota        int64     `gorm:"not null"`
+	Overage      int64     `gorm:"not null"`
+	TopEndpoints string    `gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+func (usageStatement0019) TableName() string { return "usage_statements" }
+
+// 0020_stripe_events
+
+type stripeEvent0020 struct {
+	ID          string    `gorm:"type:varchar(255);primaryKey"`
+	Type        string    `gorm:"type:varchar(100);not null;index"`
+	Payload     string    `gorm:"type:jsonb;not null"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	Error       string    `gorm:"type:text"`
+	Attempts    int       `gorm:"not null"`
+	ReceivedAt  time.Time `gorm:"not null"`
+	ClaimedAt   time.Time `gorm:"not null"`
+	ProcessedAt *time.Time
+}
+
+func (stripeEvent0020) TableName() string { return "stripe_events" }
+
+// 0021_partner_submissions
+
+type submissionLandmark0021 struct {
+	PartnerID *uuid.UUID `gorm:"type:uuid;index"`
+}
+
+func (submissionLandmark0021) TableName() string { return "submission_landmarks" }
+
+// 0022_submission_image_tags
+
+type submissionLandmark0022 struct {
+	SuggestedCategory  string  `gorm:"type:varchar(50)"`
+	CategoryConfidence float64 `gorm:"not null;default:0"`
+	SuggestedTags      string  `gorm:"type:jsonb;not null;default:'[]'"`
+}
+
+func (submissionLandmark0022) TableName() string { return "submission_landmarks" }
+
+type submissionImageTags0022 struct {
+	ID                        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubmissionLandmarkImageID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Labels                    string    `gorm:"type:jsonb;not null;default:'[]'"`
+	Relevance                 float64   `gorm:"not null;default:0"`
+	Rejected                  bool      `gorm:"not null;default:false"`
+	Error                     string    `gorm:"type:text"`
+	TaggedAt                  time.Time `gorm:"not null"`
+	ReviewedAt                *time.Time
+	CreatedAt                 time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt                 time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (submissionImageTags0022) TableName() string { return "submission_image_tags" }
+
+// 0023_landmark_image_alt_text
+
+type landmarkImage0023 struct {
+	AltText          string `gorm:"type:text;not null;default:''"`
+	AltTextCheckedAt *time.Time
+}
+
+func (landmarkImage0023) TableName() string { return "landmark_images" }
+
+// 0024_api_usages
+
+type apiUsage0024 struct {
+	ID           uint   `gorm:"primarykey"`
+	UserID       string `gorm:"index"`
+	RequestCount int
+	PeriodStart  time.Time `gorm:"index"`
+	PeriodEnd    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (apiUsage0024) TableName() string { return "api_usages" }
+
+// 0025_account_exports
+
+type accountExport0025 struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Status      string    `gorm:"type:varchar(20);not null;index"`
+	Size        int64     `gorm:"not null;default:0"`
+	Content     []byte    `gorm:"type:bytea"`
+	Error       string    `gorm:"type:text"`
+	CompletedAt *time.Time
+	CreatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (accountExport0025) TableName() string { return "account_exports" }
@@ -0,0 +1,150 @@
+package migrations
+
+import (
+	"landmark-api/internal/models"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// migratedModels are the models stored in tables the migrations create.
+var migratedModels = []interface{}{
+	&models.User{},
+	&models.APIKey{},
+	&models.Subscription{},
+	&models.Landmark{},
+	&models.LandmarkImage{},
+	&models.LandmarkDetail{},
+	&models.SubmissionLandmark{},
+	&models.SubmissionLandmarkImage{},
+	&models.SubmissionLandmarkDetail{},
+	&models.RequestLog{},
+	&models.UsageReport{},
+	&models.SearchAnalytics{},
+	&models.APIKeyLimit{},
+	&models.AuditLog{},
+	&models.RequestLogExport{},
+	&models.Category{},
+	&models.FeatureFlag{},
+	&models.LandmarkStatsSnapshot{},
+	&models.Session{},
+	&models.LandmarkEnrichment{},
+	&models.OSMImport{},
+	&models.LandmarkCustomFields{},
+	&models.Notification{},
+	&models.MailSuppression{},
+	&models.OnboardingEmail{},
+	&models.RequestCredit{},
+	&models.UsageStatement{},
+	&models.StripeEvent{},
+	&models.SubmissionImageTags{},
+	&models.APIUsage{},
+	&models.AccountExport{},
+}
+
+// snapshots are every snapshot the migrations apply, in order.
+var snapshots = append(append([]interface{}{}, baselineTables...),
+	&session0002{},
+	&apiKey0003{},
+	&apiKey0004{},
+	&landmark0005{},
+	&landmarkEnrichment0006{},
+	&osmImport0007{},
+	&submissionLandmark0007{},
+	&landmarkCustomFields0008{},
+	&landmarkImage0009{},
+	&landmarkImage0010{},
+	&landmarkDetail0011{},
+	&landmark0012{},
+	&landmark0013{},
+	&requestLog0014{},
+	&notification0015{},
+	&submissionLandmark0015{},
+	&mailSuppression0016{},
+	&onboardingEmail0017{},
+	&user0017{},
+	&requestCredit0018{},
+	&usageStatement0019{},
+	&stripeEvent0020{},
+	&submissionLandmark0021{},
+	&submissionLandmark0022{},
+	&submissionImageTags0022{},
+	&landmarkImage0023{},
+	&apiUsage0024{},
+	&accountExport0025{},
+)
+
+// column is what the migrations decide about a column.
+type column struct {
+	dataType   schema.DataType
+	primaryKey bool
+	notNull    bool
+	unique     bool
+	defaultSQL string
+	size       int
+	precision  int
+	scale      int
+}
+
+func columnOf(field *schema.Field) column {
+	return column{
+		dataType:   field.DataType,
+		primaryKey: field.PrimaryKey,
+		notNull:    field.NotNull,
+		unique:     field.Unique,
+		defaultSQL: field.DefaultValue,
+		size:       field.Size,
+		precision:  field.Precision,
+		scale:      field.Scale,
+	}
+}
+
+// TestSnapshotsMatchModels checks that applying every migration gives each
+// model's table the columns the model expects, so a model change without a
+// migration is caught.
+func TestSnapshotsMatchModels(t *testing.T) {
+	cache := &sync.Map{}
+	parse := func(value interface{}) *schema.Schema {
+		s, err := schema.Parse(value, cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("error parsing %T: %v", value, err)
+		}
+		return s
+	}
+
+	migrated := map[string]map[string]column{}
+	for _, snapshot := range snapshots {
+		s := parse(snapshot)
+		if migrated[s.Table] == nil {
+			migrated[s.Table] = map[string]column{}
+		}
+		for _, field := range s.Fields {
+			if field.DBName != "" && !field.IgnoreMigration {
+				migrated[s.Table][field.DBName] = columnOf(field)
+			}
+		}
+	}
+
+	for _, model := range migratedModels {
+		s := parse(model)
+		columns, ok := migrated[s.Table]
+		if !ok {
+			t.Errorf("no migration creates %s", s.Table)
+			continue
+		}
+		for _, field := range s.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			got, ok := columns[field.DBName]
+			if !ok {
+				t.Errorf("no migration adds %s.%s", s.Table, field.DBName)
+				continue
+			}
+			if want := columnOf(field); got != want {
+				t.Errorf("%s.%s is migrated as %+v, the model expects %+v", s.Table, field.DBName, got, want)
+			}
+		}
+	}
+}
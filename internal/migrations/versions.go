@@ -0,0 +1,373 @@
+package migrations
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// All is every migration, in the order they apply. Append new migrations to
+// the end and never edit one that has shipped.
+var All = []Migration{
+	{
+		ID:   "0001_baseline",
+		Up:   baselineUp,
+		Down: baselineDown,
+	},
+	{
+		ID:   "0002_sessions",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&session0002{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("sessions") },
+	},
+	{
+		ID:   "0003_api_key_signing",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&apiKey0003{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "api_keys", "signing_secret", "require_signature") },
+	},
+	{
+		ID: "0004_api_key_expiry",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&apiKey0004{}) },
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, "api_keys", "expires_at", "expiry_reminder_days", "previous_key", "previous_key_expires_at")
+		},
+	},
+	{
+		ID:   "0005_landmark_timezone",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmark0005{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "landmarks", "timezone") },
+	},
+	{
+		ID:   "0006_landmark_enrichments",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmarkEnrichment0006{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("landmark_enrichments") },
+	},
+	{
+		ID: "0007_osm_imports",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&osmImport0007{}, &submissionLandmark0007{}) },
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("osm_imports"); err != nil {
+				return err
+			}
+			return dropColumns(tx, "submission_landmarks", "source")
+		},
+	},
+	{
+		ID:   "0008_landmark_custom_fields",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmarkCustomFields0008{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("landmark_custom_fields") },
+	},
+	{
+		ID: "0009_landmark_image_gallery",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&landmarkImage0009{}) },
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, "landmark_images", "caption", "credit", "display_order", "is_primary")
+		},
+	},
+	{
+		ID: "0010_landmark_media_types",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&landmarkImage0010{}) },
+		Down: func(tx *gorm.DB) error {
+			return dropColumns(tx, "landmark_images", "media_type", "provider", "provider_id", "stream_url")
+		},
+	},
+	{
+		ID:   "0011_landmark_entry_prices",
+		Up:   landmarkEntryPricesUp,
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "landmark_details", "entry_price", "entry_currency") },
+	},
+	{
+		ID:   "0012_landmark_tags",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmark0012{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "landmarks", "tags") },
+	},
+	{
+		ID:   "0013_landmark_status",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmark0013{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "landmarks", "status") },
+	},
+	{
+		ID:   "0014_request_log_response_body",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&requestLog0014{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "request_logs", "response_body") },
+	},
+	{
+		ID: "0015_notifications",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&notification0015{}, &submissionLandmark0015{}) },
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("notifications"); err != nil {
+				return err
+			}
+			return dropColumns(tx, "submission_landmarks", "user_id")
+		},
+	},
+	{
+		ID:   "0016_mail_suppressions",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&mailSuppression0016{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("mail_suppressions") },
+	},
+	{
+		ID: "0017_onboarding_emails",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&onboardingEmail0017{}, &user0017{}) },
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("onboarding_emails"); err != nil {
+				return err
+			}
+			return dropColumns(tx, "users", "onboarding_opt_out")
+		},
+	},
+	{
+		ID:   "0018_request_credits",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&requestCredit0018{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("request_credits") },
+	},
+	{
+		ID:   "0019_usage_statements",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&usageStatement0019{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("usage_statements") },
+	},
+	{
+		ID:   "0020_stripe_events",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&stripeEvent0020{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("stripe_events") },
+	},
+	{
+		ID:   "0021_partner_submissions",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&submissionLandmark0021{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "submission_landmarks", "partner_id") },
+	},
+	{
+		ID: "0022_submission_image_tags",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&submissionLandmark0022{}, &submissionImageTags0022{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("submission_image_tags"); err != nil {
+				return err
+			}
+			return dropColumns(tx, "submission_landmarks", "suggested_category", "category_confidence", "suggested_tags")
+		},
+	},
+	{
+		ID:   "0023_landmark_image_alt_text",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&landmarkImage0023{}) },
+		Down: func(tx *gorm.DB) error { return dropColumns(tx, "landmark_images", "alt_text", "alt_text_checked_at") },
+	},
+	{
+		// api_usages was never migrated, only created by hand where it
+		// exists, so it is left in place when reverting
+		ID: "0024_api_usages",
+		Up: func(tx *gorm.DB) error { return tx.AutoMigrate(&apiUsage0024{}) },
+	},
+	{
+		ID:   "0025_account_exports",
+		Up:   func(tx *gorm.DB) error { return tx.AutoMigrate(&accountExport0025{}) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("account_exports") },
+	},
+}
+
+// baselineTables is the schema as it stood when versioned migrations were
+// introduced, parents before children.
+var baselineTables = []interface{}{
+	&baselineUser{},
+	&baselineAPIKey{},
+	&baselineSubscription{},
+	&baselineLandmark{},
+	&baselineLandmarkImage{},
+	&baselineLandmarkDetail{},
+	&baselineSubmissionLandmark{},
+	&baselineSubmissionLandmarkImage{},
+	&baselineSubmissionLandmarkDetail{},
+	&baselineRequestLog{},
+	&baselineUsageReport{},
+	&baselineSearchAnalytics{},
+	&baselineAPIKeyLimit{},
+	&baselineAuditLog{},
+	&baselineRequestLogExport{},
+	&baselineCategory{},
+	&baselineFeatureFlag{},
+	&baselineLandmarkStatsSnapshot{},
+}
+
+// baselineUp creates the baseline schema, or brings a database created
+// before versioned migrations up to it.
+func baselineUp(tx *gorm.DB) error {
+	if err := dropIntegerAuditAdminID(tx); err != nil {
+		return err
+	}
+	return tx.AutoMigrate(baselineTables...)
+}
+
+func baselineDown(tx *gorm.DB) error {
+	for i := len(baselineTables) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(baselineTables[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// landmarkEntryPricesUp adds the normalized entry price columns and fills
+// them in from the ticket prices of existing details.
+func landmarkEntryPricesUp(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&landmarkDetail0011{}); err != nil {
+		return err
+	}
+
+	var last uuid.UUID
+	for {
+		var rows []struct {
+			ID           uuid.UUID
+			TicketPrices string
+		}
+		err := tx.Table("landmark_details").
+			Select("id, COALESCE(ticket_prices, '{}'::jsonb)::text AS ticket_prices").
+			Where("id > ?", last).
+			Order("id").
+			Limit(500).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			// Prices that are not an object have no entry price
+			var prices map[string]string
+			_ = json.Unmarshal([]byte(row.TicketPrices), &prices)
+			price, currency := normalizeTicketPrices0011(prices)
+			err := tx.Table("landmark_details").Where("id = ?", row.ID).UpdateColumns(map[string]interface{}{
+				"entry_price":    price,
+				"entry_currency": currency,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		last = rows[len(rows)-1].ID
+	}
+}
+
+// The entry price rules as they stood for 0011, a frozen copy of
+// models.NormalizeTicketPrices so the backfill gives the same result
+// however the live rules change.
+
+var entryPriceKeys0011 = []string{"adult", "adults", "general", "standard", "regular", "admission", "entry"}
+
+var freePrices0011 = map[string]bool{
+	"free":       true,
+	"free entry": true,
+	"gratis":     true,
+	"none":       true,
+}
+
+var currencySymbols0011 = map[string]string{
+	"€": "EUR",
+	"$": "USD",
+	"£": "GBP",
+	"¥": "JPY",
+	"₹": "INR",
+	"₩": "KRW",
+}
+
+var (
+	priceAmountPattern0011   = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+	priceCurrencyPattern0011 = regexp.MustCompile(`\b[A-Z]{3}\b`)
+)
+
+// normalizeTicketPrices0011 returns the adult price when one is listed,
+// otherwise the lowest listed price, with its currency.
+func normalizeTicketPrices0011(prices map[string]string) (amount *float64, currency string) {
+	for _, key := range entryPriceKeys0011 {
+		for name, value := range prices {
+			if strings.EqualFold(strings.TrimSpace(name), key) {
+				if price, ok := parseTicketPrice0011(value); ok {
+					return &price, ticketPriceCurrency0011(value, price)
+				}
+			}
+		}
+	}
+
+	for _, value := range prices {
+		price, ok := parseTicketPrice0011(value)
+		if ok && (amount == nil || price < *amount) {
+			amount = &price
+			currency = ticketPriceCurrency0011(value, price)
+		}
+	}
+	return amount, currency
+}
+
+func parseTicketPrice0011(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if freePrices0011[strings.ToLower(value)] {
+		return 0, true
+	}
+
+	match := priceAmountPattern0011.FindString(value)
+	if match == "" {
+		return 0, false
+	}
+	var number strings.Builder
+	for i, group := range strings.FieldsFunc(match, func(r rune) bool { return r == '.' || r == ',' }) {
+		if i > 0 && len(group) != 3 {
+			number.WriteByte('.')
+		}
+		number.WriteString(group)
+	}
+	price, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+func ticketPriceCurrency0011(value string, price float64) string {
+	if price == 0 {
+		return ""
+	}
+	if code := priceCurrencyPattern0011.FindString(value); code != "" {
+		return code
+	}
+	for symbol, code := range currencySymbols0011 {
+		if strings.Contains(value, symbol) {
+			return code
+		}
+	}
+	return ""
+}
+
+func dropColumns(tx *gorm.DB, table string, columns ...string) error {
+	for _, column := range columns {
+		if err := tx.Migrator().DropColumn(table, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropIntegerAuditAdminID drops the legacy integer audit_logs.admin_id
+// column so AutoMigrate can recreate it as a UUID. The integer column was
+// always written as 0, so no admin identity is lost.
+func dropIntegerAuditAdminID(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasTable("audit_logs") {
+		return nil
+	}
+
+	columns, err := migrator.ColumnTypes("audit_logs")
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if column.Name() == "admin_id" && !strings.EqualFold(column.DatabaseTypeName(), "uuid") {
+			return migrator.DropColumn("audit_logs", "admin_id")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountExport is an asynchronous zip archive of everything stored about a
+// user, for GDPR access requests. It is built in the background as active
+// accounts have too many request logs to archive within a request.
+type AccountExport struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"-"`
+	Status      ExportStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Size        int64        `gorm:"not null;default:0" json:"size"`
+	Content     []byte       `gorm:"type:bytea" json:"-"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (e *AccountExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (AccountExport) TableName() string {
+	return "account_exports"
+}
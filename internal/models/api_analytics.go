@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// APIAnalytics summarises API traffic over a time window for the admin
+// dashboard. Rates are fractions between 0 and 1.
+type APIAnalytics struct {
+	Window          string    `json:"window"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	Requests        int64     `json:"requests"`
+	ErrorRate       float64   `json:"error_rate"`
+	ServerErrorRate float64   `json:"server_error_rate"`
+	P95LatencyMs    float64   `json:"p95_latency_ms"`
+	// CacheHitRate is nil when no request in the window used the cache.
+	CacheHitRate *float64        `json:"cache_hit_rate"`
+	Volume       []RequestVolume `json:"volume"`
+	TopEndpoints []EndpointUsage `json:"top_endpoints"`
+	TopConsumers []ConsumerUsage `json:"top_consumers"`
+}
+
+// RequestLogSummary holds the raw totals behind APIAnalytics.
+type RequestLogSummary struct {
+	Requests     int64
+	Errors       int64
+	ServerErrors int64
+	P95LatencyMs float64
+	CacheHits    int64
+	CacheLookups int64
+}
+
+// RequestVolume counts requests in the bucket starting at Start.
+type RequestVolume struct {
+	Start    time.Time `json:"start"`
+	Requests int64     `json:"requests"`
+	Errors   int64     `json:"errors"`
+}
+
+// EndpointUsage aggregates requests to one route, e.g. GET /api/v1/landmarks/{id}.
+type EndpointUsage struct {
+	Method       string  `json:"method"`
+	Route        string  `json:"route"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+}
+
+// ConsumerUsage aggregates requests made by one user.
+type ConsumerUsage struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	Requests int64  `json:"requests"`
+	Errors   int64  `json:"errors"`
+}
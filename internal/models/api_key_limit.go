@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UnlimitedRequests disables the request limit, as for the Enterprise plan.
+const UnlimitedRequests = -1
+
+// APIKeyLimit is a negotiated request limit for one API key. It takes
+// precedence over the plan's default limit.
+type APIKeyLimit struct {
+	ID           uint      `gorm:"primarykey" json:"-"`
+	APIKeyID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"api_key_id"`
+	RequestLimit int       `gorm:"not null" json:"request_limit"`
+	Reason       string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (APIKeyLimit) TableName() string {
+	return "api_key_limits"
+}
@@ -7,9 +7,28 @@ import (
 )
 
 type APIKey struct {
-	ID        uuid.UUID `gorm:"type:uuid" json:"id"`
-	UserID    uuid.UUID `gorm:"type:uuid" json:"user_id"`
-	Key       string    `json:"key"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID     uuid.UUID `gorm:"type:uuid" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid" json:"user_id"`
+	Key    string    `json:"key"`
+	// SigningSecret signs requests made with the key. When RequireSignature
+	// is set, requests without a valid signature are rejected, so the key
+	// alone is not enough to call the API.
+	SigningSecret    string `gorm:"type:varchar(64)" json:"-"`
+	RequireSignature bool   `gorm:"not null;default:false" json:"require_signature"`
+	// ExpiresAt, when set, is when the key stops working.
+	// ExpiryReminderDays is the smallest reminder threshold, in days before
+	// expiry, the owner has been emailed about; 0 when none has been sent.
+	ExpiresAt          *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	ExpiryReminderDays int        `gorm:"not null;default:0" json:"-"`
+	// PreviousKey is the key this one replaced. It keeps working until
+	// PreviousKeyExpiresAt, so clients can switch over without downtime.
+	PreviousKey          string     `gorm:"index" json:"-"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// Expired reports whether the key has passed its expiry at now.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
 }
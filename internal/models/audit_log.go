@@ -1,17 +1,23 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type AuditLog struct {
 	gorm.Model
-	AdminID    int       `json:"adminId"`
-	Action     string    `json:"action"`
-	EntityType string    `json:"entityType"`
+	AdminID    uuid.UUID `gorm:"type:uuid;index" json:"adminId"`
+	IPAddress  string    `gorm:"type:varchar(45)" json:"ipAddress"`
+	Action     string    `gorm:"index" json:"action"`
+	EntityType string    `gorm:"index" json:"entityType"`
 	EntityID   string    `json:"entityId"`
 	Details    string    `json:"details"`
-	Timestamp  time.Time `json:"timestamp"`
+	// Changes maps each field in the request payload to its previous and
+	// new value, e.g. {"landmark.name": {"from": "Old", "to": "New"}}.
+	Changes   json.RawMessage `gorm:"type:jsonb" json:"changes,omitempty"`
+	Timestamp time.Time       `gorm:"index" json:"timestamp"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Category is a landmark category managed by admins. Landmarks reference
+// categories by name. Categories may be nested, e.g. Historical → Castle.
+type Category struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
+	ParentID  *uint     `gorm:"index" json:"-"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+// CategoryNode is a category with its subcategories and the number of
+// landmarks filed directly under it.
+type CategoryNode struct {
+	Name          string          `json:"name"`
+	LandmarkCount int64           `json:"landmark_count"`
+	Children      []*CategoryNode `json:"children"`
+}
@@ -38,3 +38,45 @@ func (j JSON) Value() (driver.Value, error) {
 	}
 	return json.Marshal(j)
 }
+
+// StringList stores a list of strings in a JSONB column
+type StringList []string
+
+// Scan implements the sql.Scanner interface
+func (l *StringList) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, (*[]string)(l))
+}
+
+// Value implements the driver.Valuer interface
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// Contains reports whether the list includes s
+func (l StringList) Contains(s string) bool {
+	for _, item := range l {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
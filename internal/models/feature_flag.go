@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// FeatureFlag gates a feature at runtime. An enabled flag is always on for
+// the users in TargetUserIDs. Otherwise it applies to RolloutPercent of the
+// users on TargetPlans (all plans when empty), chosen by a stable hash so
+// each user keeps the same answer as the rollout grows.
+type FeatureFlag struct {
+	ID             uint       `gorm:"primarykey" json:"-"`
+	Key            string     `gorm:"type:varchar(100);not null;uniqueIndex" json:"key"`
+	Description    string     `gorm:"type:text" json:"description"`
+	Enabled        bool       `gorm:"not null;default:false" json:"enabled"`
+	RolloutPercent int        `gorm:"not null;default:0" json:"rollout_percent"`
+	TargetPlans    StringList `gorm:"type:jsonb;not null;default:'[]'" json:"target_plans"`
+	TargetUserIDs  StringList `gorm:"type:jsonb;not null;default:'[]'" json:"target_user_ids"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageLabel is something an image-tagging provider recognized in an image,
+// with its confidence from 0 to 1.
+type ImageLabel struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ImageLabels stores labels in a JSONB column, most confident first.
+type ImageLabels []ImageLabel
+
+// Scan implements the sql.Scanner interface
+func (l *ImageLabels) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, (*[]ImageLabel)(l))
+}
+
+// Value implements the driver.Valuer interface
+func (l ImageLabels) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal([]ImageLabel(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
+
+// SubmissionImageTags is what the tagging job recognized in a submitted
+// image. Images showing nothing that looks like a landmark are rejected and
+// left out of the landmark the submission is approved as, unless an admin
+// accepts them. Images the provider failed on keep the Error and are not
+// rejected.
+type SubmissionImageTags struct {
+	ID                        uuid.UUID   `gorm:"type:uuid;primaryKey" json:"-"`
+	SubmissionLandmarkImageID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex" json:"-"`
+	Labels                    ImageLabels `gorm:"type:jsonb;not null;default:'[]'" json:"labels"`
+	// Relevance is the confidence of the most confident label showing a
+	// landmark, such as a building or monument
+	Relevance float64   `gorm:"not null;default:0" json:"relevance"`
+	Rejected  bool      `gorm:"not null;default:false" json:"rejected"`
+	Error     string    `gorm:"type:text" json:"error,omitempty"`
+	TaggedAt  time.Time `gorm:"not null" json:"tagged_at"`
+	// ReviewedAt is when an admin last accepted or rejected the image,
+	// overriding the job
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+	UpdatedAt  time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+func (SubmissionImageTags) TableName() string {
+	return "submission_image_tags"
+}
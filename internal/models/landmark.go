@@ -9,26 +9,120 @@ import (
 
 type Landmark struct {
 	ID          uuid.UUID       `gorm:"type:uuid;primaryKey" json:"-"`
-	Name        string          `gorm:"type:varchar(255);not null" json:"name"`
+	Name        string          `gorm:"type:varchar(255);not null" json:"name" validate:"required,max=255"`
 	Description string          `gorm:"type:text;not null" json:"description"`
-	Latitude    float64         `gorm:"type:decimal(10,8);not null" json:"latitude"`
-	Longitude   float64         `gorm:"type:decimal(11,8);not null" json:"longitude"`
-	Country     string          `gorm:"type:varchar(100);not null" json:"country"`
-	City        string          `gorm:"type:varchar(100);not null" json:"city"`
-	Category    string          `gorm:"type:varchar(50);not null" json:"category"`
-	ImageUrl    string          `gorm:"type:varchar(255)" json:"image_url"`
+	Latitude    float64         `gorm:"type:decimal(10,8);not null" json:"latitude" validate:"min=-90,max=90"`
+	Longitude   float64         `gorm:"type:decimal(11,8);not null" json:"longitude" validate:"min=-180,max=180"`
+	Country     string          `gorm:"type:varchar(100);not null" json:"country" validate:"required,max=100"`
+	City        string          `gorm:"type:varchar(100);not null" json:"city" validate:"required,max=100"`
+	Category    string          `gorm:"type:varchar(50);not null" json:"category" validate:"required,max=50"`
+	ImageUrl    string          `gorm:"type:varchar(255)" json:"image_url" validate:"omitempty,url,max=255"`
 	Images      []LandmarkImage `gorm:"foreignKey:LandmarkID" json:"images"`
-	CreatedAt   time.Time       `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time       `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`
+	// LastVerifiedAt is when an editor or enrichment job last confirmed the
+	// landmark's data, and DataConfidence (0 to 1) how sure they were.
+	LastVerifiedAt *time.Time `gorm:"default:null" json:"last_verified_at"`
+	DataConfidence float64    `gorm:"type:decimal(3,2);not null;default:0" json:"data_confidence" validate:"min=0,max=1"`
+	// Timezone is the IANA zone at the landmark's coordinates, looked up in
+	// the background; empty until then.
+	Timezone string `gorm:"type:varchar(64);not null;default:''" json:"timezone"`
+	// Status is whether the landmark is shown; public endpoints only list
+	// published landmarks.
+	Status LandmarkStatus `gorm:"type:varchar(16);not null;default:'published';index" json:"status" validate:"omitempty,oneof=draft published archived"`
+	// Tags are free-form lowercase labels admins group landmarks by.
+	Tags      StringList     `gorm:"type:jsonb;not null;default:'[]'" json:"tags"`
+	CreatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// LandmarkStatus is the editorial state of a landmark.
+type LandmarkStatus string
+
+const (
+	// LandmarkDraft landmarks are being prepared and only admins see them.
+	LandmarkDraft     LandmarkStatus = "draft"
+	LandmarkPublished LandmarkStatus = "published"
+	// LandmarkArchived landmarks are withdrawn but kept, unlike deleted ones.
+	LandmarkArchived LandmarkStatus = "archived"
+)
+
+// Valid reports whether s is a known status.
+func (s LandmarkStatus) Valid() bool {
+	return s == LandmarkDraft || s == LandmarkPublished || s == LandmarkArchived
+}
+
+// PublishedLandmarks is a query scope limiting landmarks to the published
+// ones, for everything the public API returns.
+func PublishedLandmarks(db *gorm.DB) *gorm.DB {
+	return db.Where("landmarks.status = ?", LandmarkPublished)
+}
+
+// MaxLandmarkTags is how many tags a landmark can have.
+const MaxLandmarkTags = 50
+
+// ValidDataConfidence reports whether c is a usable confidence score.
+func ValidDataConfidence(c float64) bool {
+	return c >= 0 && c <= 1
+}
+
+type MediaType string
+
+const (
+	MediaPhoto MediaType = "photo"
+	MediaVideo MediaType = "video"
+	// MediaPano is a 360° panorama, stored as an equirectangular image
+	MediaPano MediaType = "pano"
+)
+
+// MediaProvider is where a video is served from.
+type MediaProvider string
+
+const (
+	ProviderYouTube MediaProvider = "youtube"
+	ProviderVimeo   MediaProvider = "vimeo"
+	// ProviderS3HLS is an HLS stream we host on S3
+	ProviderS3HLS MediaProvider = "s3_hls"
+)
+
+// LandmarkImage is an item of a landmark's media gallery. Despite the name
+// it may be a photo, a video or a 360° panorama. ImageURL is the photo or
+// panorama itself, and the thumbnail of a video.
 type LandmarkImage struct {
 	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
 	LandmarkID uuid.UUID `gorm:"type:uuid;not null" json:"-"`
 	ImageURL   string    `gorm:"type:varchar(500);not null" json:"image_url"`
-	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	MediaType  MediaType `gorm:"type:varchar(16);not null;default:'photo'" json:"type"`
+	// Provider and ProviderID identify a video on YouTube or Vimeo;
+	// StreamURL is the playlist of an HLS video on S3. All are empty for
+	// photos and panoramas.
+	Provider   MediaProvider `gorm:"type:varchar(16);not null;default:''" json:"provider,omitempty"`
+	ProviderID string        `gorm:"type:varchar(64);not null;default:''" json:"provider_id,omitempty"`
+	StreamURL  string        `gorm:"type:varchar(500);not null;default:''" json:"stream_url,omitempty"`
+	Caption    string        `gorm:"type:text;not null;default:''" json:"caption"`
+	// AltText describes the image for screen readers. Photos and panoramas
+	// left without it get generated alt text, and captions, from the
+	// alt-text job, which last looked at them at AltTextCheckedAt
+	AltText          string     `gorm:"type:text;not null;default:''" json:"alt_text"`
+	AltTextCheckedAt *time.Time `json:"-"`
+	// Credit attributes the image to its author or source
+	Credit string `gorm:"type:varchar(255);not null;default:''" json:"credit"`
+	// DisplayOrder positions the image in the landmark's gallery, lowest
+	// first
+	DisplayOrder int `gorm:"not null;default:0" json:"display_order"`
+	// IsPrimary marks the image shown for the landmark; at most one image
+	// of a landmark has it
+	IsPrimary bool      `gorm:"not null;default:false" json:"is_primary"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Type is the media type of the item. Items created before media types
+// were stored are photos.
+func (i LandmarkImage) Type() MediaType {
+	if i.MediaType == "" {
+		return MediaPhoto
+	}
+	return i.MediaType
 }
 
 type LandmarkDetail struct {
@@ -39,33 +133,60 @@ type LandmarkDetail struct {
 	HistoricalSignificance string            `gorm:"type:text" json:"historical_significance"`
 	VisitorTips            string            `gorm:"type:text" json:"visitor_tips"`
 	AccessibilityInfo      string            `gorm:"type:text" json:"accessibility_info"`
-	CreatedAt              time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt              time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt              gorm.DeletedAt    `gorm:"index" json:"-"`
+	// EntryPrice and EntryCurrency are the adult admission read from
+	// TicketPrices, for price filters. EntryPrice is 0 for free entry and
+	// nil when no price could be read.
+	EntryPrice    *float64       `gorm:"type:decimal(10,2);index" json:"-"`
+	EntryCurrency string         `gorm:"type:varchar(3);not null;default:''" json:"-"`
+	CreatedAt     time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type SubmissionLandmark struct {
-	ID          uuid.UUID                 `gorm:"type:uuid;primaryKey" json:"id"`
-	Name        string                    `gorm:"type:varchar(255);not null" json:"name"`
-	Description string                    `gorm:"type:text;not null" json:"description"`
-	Latitude    float64                   `gorm:"type:decimal(10,8);not null" json:"latitude"`
-	Longitude   float64                   `gorm:"type:decimal(11,8);not null" json:"longitude"`
-	Country     string                    `gorm:"type:varchar(100);not null" json:"country"`
-	City        string                    `gorm:"type:varchar(100);not null" json:"city"`
-	Category    string                    `gorm:"type:varchar(50);not null" json:"category"`
-	Status      string                    // "pending", "approved", or "rejected"
-	Images      []SubmissionLandmarkImage `gorm:"foreignKey:SubmissionLandmarkID" json:"images"`
-	Detail      SubmissionLandmarkDetail  `gorm:"foreignKey:SubmissionLandmarkID;references:ID" json:"details"`
-	CreatedAt   time.Time                 `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time                 `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name" validate:"required,max=255"`
+	Description string    `gorm:"type:text;not null" json:"description"`
+	Latitude    float64   `gorm:"type:decimal(10,8);not null" json:"latitude" validate:"min=-90,max=90"`
+	Longitude   float64   `gorm:"type:decimal(11,8);not null" json:"longitude" validate:"min=-180,max=180"`
+	Country     string    `gorm:"type:varchar(100);not null" json:"country" validate:"required,max=100"`
+	City        string    `gorm:"type:varchar(100);not null" json:"city" validate:"required,max=100"`
+	Category    string    `gorm:"type:varchar(50);not null" json:"category" validate:"required,max=50"`
+	Status      string    // "pending", "approved", or "rejected"
+	// Source identifies where an imported submission came from, e.g.
+	// "osm:node/123", so it is not imported again; empty for user submissions
+	Source string `gorm:"type:varchar(64);index" json:"source,omitempty"`
+	// UserID is who submitted it, to tell them how it was reviewed; nil for
+	// anonymous submissions and imports
+	UserID *uuid.UUID `gorm:"type:uuid;index" json:"-"`
+	// PartnerID is the Enterprise data partner who proposed it through the
+	// partner API; nil for other submissions
+	PartnerID *uuid.UUID `gorm:"type:uuid;index" json:"partner_id,omitempty"`
+	// SuggestedCategory and SuggestedTags are what the image-tagging job
+	// recognized in the submission's images, for reviewers to pick from.
+	// CategoryConfidence is the confidence of the category, from 0 to 1
+	SuggestedCategory  string                    `gorm:"type:varchar(50)" json:"suggested_category,omitempty"`
+	CategoryConfidence float64                   `gorm:"not null;default:0" json:"category_confidence,omitempty"`
+	SuggestedTags      StringList                `gorm:"type:jsonb;not null;default:'[]'" json:"suggested_tags"`
+	Images             []SubmissionLandmarkImage `gorm:"foreignKey:SubmissionLandmarkID" json:"images"`
+	Detail             SubmissionLandmarkDetail  `gorm:"foreignKey:SubmissionLandmarkID;references:ID" json:"details"`
+	CreatedAt          time.Time                 `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt          time.Time                 `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
 }
 
 type SubmissionLandmarkImage struct {
 	ID                   uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
 	SubmissionLandmarkID uuid.UUID `gorm:"type:uuid;not null" json:"-"`
 	ImageURL             string    `gorm:"type:varchar(500);not null" json:"image_url"`
-	CreatedAt            time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt            time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	// Tags is nil until the image-tagging job has looked at the image
+	Tags      *SubmissionImageTags `gorm:"foreignKey:SubmissionLandmarkImageID" json:"tags,omitempty"`
+	CreatedAt time.Time            `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time            `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// Rejected reports whether the image was found not to show the landmark.
+func (i SubmissionLandmarkImage) Rejected() bool {
+	return i.Tags != nil && i.Tags.Rejected
 }
 
 type SubmissionLandmarkDetail struct {
@@ -127,6 +248,11 @@ func (ld *LandmarkDetail) BeforeUpdate(tx *gorm.DB) error {
 }
 
 func (l *Landmark) GetMainImage() string {
+	for _, image := range l.Images {
+		if image.IsPrimary && image.ImageURL != "" {
+			return image.ImageURL
+		}
+	}
 	if len(l.Images) > 0 {
 		return l.Images[0].ImageURL
 	}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LandmarkCustomFields are the private key/value fields an Enterprise
+// account attached to a landmark. Each account has at most one row per
+// landmark, and only that account sees it.
+type LandmarkCustomFields struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_landmark_custom_fields_user_landmark" json:"-"`
+	LandmarkID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_landmark_custom_fields_user_landmark;index" json:"-"`
+	Fields     JSON      `gorm:"type:jsonb;not null" json:"fields"`
+	CreatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (LandmarkCustomFields) TableName() string {
+	return "landmark_custom_fields"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LandmarkEnrichment is what the enrichment job found about a landmark on
+// Wikidata and Wikipedia. Landmarks without a matching entity get a row with
+// an empty WikidataID, so they are not looked up again until it is refreshed.
+type LandmarkEnrichment struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"-"`
+	LandmarkID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"-"`
+	WikidataID      string     `gorm:"type:varchar(32);not null;default:''" json:"wikidata_id"`
+	WikipediaURL    string     `gorm:"type:varchar(500)" json:"wikipedia_url"`
+	Summary         string     `gorm:"type:text" json:"summary"`
+	OfficialWebsite string     `gorm:"type:varchar(500)" json:"official_website"`
+	HeritageStatus  StringList `gorm:"type:jsonb" json:"heritage_status"`
+	Images          StringList `gorm:"type:jsonb" json:"images"`
+	EnrichedAt      time.Time  `gorm:"not null;index" json:"enriched_at"`
+	CreatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+	UpdatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"-"`
+}
+
+func (LandmarkEnrichment) TableName() string {
+	return "landmark_enrichments"
+}
@@ -1,8 +1,65 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type LandmarkStats struct {
 	TotalLandmarks      int64            `json:"totalLandmarks"`
 	LandmarksByCategory map[string]int64 `json:"landmarksByCategory"`
 	LandmarksByCountry  map[string]int64 `json:"landmarksByCountry"`
 	RecentlyAdded       []Landmark       `json:"recentlyAdded"`
 }
+
+// PublicLandmarkStats is the customer-facing version of LandmarkStats.
+// Fields beyond the totals are filled in depending on the caller's plan.
+type PublicLandmarkStats struct {
+	TotalLandmarks      int64            `json:"total_landmarks"`
+	LandmarksByCategory map[string]int64 `json:"landmarks_by_category"`
+	LandmarksByCountry  map[string]int64 `json:"landmarks_by_country,omitempty"`
+	RecentlyAdded       []RecentLandmark `json:"recently_added,omitempty"`
+	GeneratedAt         time.Time        `json:"generated_at"`
+}
+
+// RecentLandmark summarizes a newly added landmark for PublicLandmarkStats.
+type RecentLandmark struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	City     string    `json:"city"`
+	Country  string    `json:"country"`
+	Category string    `json:"category"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// LandmarkStatsSnapshot records one day of catalog activity. Snapshots are
+// taken by a daily job so growth can be charted over time.
+type LandmarkStatsSnapshot struct {
+	ID                  uint      `gorm:"primarykey" json:"-"`
+	Date                time.Time `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	TotalLandmarks      int64     `gorm:"not null" json:"total_landmarks"`
+	LandmarksAdded      int64     `gorm:"not null" json:"landmarks_added"`
+	SubmissionsCreated  int64     `gorm:"not null" json:"submissions_created"`
+	SubmissionsApproved int64     `gorm:"not null" json:"submissions_approved"`
+	SubmissionsRejected int64     `gorm:"not null" json:"submissions_rejected"`
+	CreatedAt           time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt           time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (LandmarkStatsSnapshot) TableName() string {
+	return "landmark_stats_snapshots"
+}
+
+// WeeklyGrowth sums snapshots over one week starting on WeekStart (Monday).
+type WeeklyGrowth struct {
+	WeekStart           time.Time `json:"week_start"`
+	TotalLandmarks      int64     `json:"total_landmarks"`
+	LandmarksAdded      int64     `json:"landmarks_added"`
+	SubmissionsCreated  int64     `json:"submissions_created"`
+	SubmissionsApproved int64     `json:"submissions_approved"`
+	SubmissionsRejected int64     `json:"submissions_rejected"`
+	// ApprovalRate is approved / (approved + rejected), or nil when no
+	// submission was reviewed that week.
+	ApprovalRate *float64 `json:"approval_rate"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MailSuppression is an address that bounced or complained, reported by
+// the mail provider's webhook. Nothing is emailed to it again.
+type MailSuppression struct {
+	Address   string    `gorm:"type:varchar(255);primaryKey"`
+	Type      string    `gorm:"type:varchar(20);not null"`
+	Reason    string    `gorm:"type:text"`
+	Provider  string    `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (MailSuppression) TableName() string {
+	return "mail_suppressions"
+}
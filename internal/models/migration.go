@@ -1,5 +1,13 @@
 package models
 
-type MigrationRecord struct {
-	Name string `json:"name"`
+import "time"
+
+// SchemaMigration records a schema migration applied to the database.
+type SchemaMigration struct {
+	ID        string    `gorm:"type:varchar(255);primaryKey" json:"id"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationKind groups notifications by what they are about.
+type NotificationKind string
+
+const (
+	NotificationQuota      NotificationKind = "quota"
+	NotificationSubmission NotificationKind = "submission"
+	NotificationBilling    NotificationKind = "billing"
+	NotificationSecurity   NotificationKind = "security"
+	NotificationOnboarding NotificationKind = "onboarding"
+)
+
+// Notification is an entry in a user's in-app feed. Type is the template
+// it was rendered from, e.g. "quota_warning".
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index:idx_notification_user_created" json:"-"`
+	Kind      NotificationKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Type      string           `gorm:"type:varchar(50);not null" json:"type"`
+	Title     string           `gorm:"type:varchar(255);not null" json:"title"`
+	Body      string           `gorm:"type:text;not null" json:"body"`
+	ReadAt    *time.Time       `json:"read_at" extensions:"x-nullable"`
+	CreatedAt time.Time        `gorm:"not null;index:idx_notification_user_created" json:"created_at"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Onboarding email sequence steps, in the order they are usually sent.
+const (
+	OnboardingWelcome = "welcome"
+	OnboardingTips    = "tips"
+	OnboardingQuota   = "quota"
+)
+
+// OnboardingEmail records a step of the onboarding sequence sent to a
+// user, so each is sent once.
+type OnboardingEmail struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Step   string    `gorm:"type:varchar(20);primaryKey" json:"step"`
+	SentAt time.Time `gorm:"not null" json:"sent_at"`
+}
+
+func (OnboardingEmail) TableName() string {
+	return "onboarding_emails"
+}
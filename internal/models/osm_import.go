@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// OSMImport is an admin-requested import of OpenStreetMap points of
+// interest in a bounding box. Imported points become landmark submissions
+// for review; points we already have are counted as duplicates.
+type OSMImport struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	AdminID     uuid.UUID    `gorm:"type:uuid;not null" json:"admin_id"`
+	BBox        string       `gorm:"type:varchar(100);not null" json:"bbox"`
+	Categories  StringList   `gorm:"type:jsonb;not null" json:"categories"`
+	Status      ImportStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Created     int          `gorm:"not null;default:0" json:"created"`
+	Duplicates  int          `gorm:"not null;default:0" json:"duplicates"`
+	Skipped     int          `gorm:"not null;default:0" json:"skipped"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (i *OSMImport) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+func (OSMImport) TableName() string {
+	return "osm_imports"
+}
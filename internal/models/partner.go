@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PartnerContributions counts the landmarks a data partner proposed, by
+// how their review went.
+type PartnerContributions struct {
+	PartnerID      uuid.UUID `json:"partner_id"`
+	Email          string    `json:"email"`
+	Proposed       int64     `json:"proposed"`
+	Pending        int64     `json:"pending"`
+	Approved       int64     `json:"approved"`
+	Rejected       int64     `json:"rejected"`
+	LastProposedAt time.Time `json:"last_proposed_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequestCredit is a pack of requests a user bought on top of their plan's
+// quota. Once the quota of a period is used up, each request uses up a
+// credit of the oldest pack with some left; credits don't expire.
+type RequestCredit struct {
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	// StripeSessionID is the checkout session that paid for the pack, so
+	// a purchase is credited once however often Stripe reports it.
+	StripeSessionID string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	Pack            string    `gorm:"type:varchar(20);not null" json:"pack"`
+	Purchased       int64     `gorm:"not null" json:"purchased"`
+	Remaining       int64     `gorm:"not null" json:"remaining"`
+	CreatedAt       time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (c *RequestCredit) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+func (RequestCredit) TableName() string {
+	return "request_credits"
+}
@@ -14,15 +14,25 @@ const (
 )
 
 type RequestLog struct {
-	ID         uint   `gorm:"primarykey"`
-	UserID     string `gorm:"index"`
-	Endpoint   string `gorm:"index"`
+	ID       uint   `gorm:"primarykey"`
+	UserID   string `gorm:"index"`
+	Endpoint string `gorm:"index"`
+	// Route is the matched route template, e.g. /api/v1/landmarks/{id}
+	Route      string `gorm:"index"`
 	Method     string
 	Status     RequestStatus
 	StatusCode int
 	Summary    string
-	Timestamp  time.Time `gorm:"index"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	TraceID    string `gorm:"index"`
+	DurationMs int64
+	// CacheStatus is the X-Cache response header: HIT, MISS, STALE, BYPASS
+	// or empty when the endpoint is not cached.
+	CacheStatus string
+	// ResponseBody is the start of the response body, only kept when
+	// REQUEST_LOG_CAPTURE_BODIES is set.
+	ResponseBody string    `gorm:"type:text"`
+	Timestamp    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportRunning   ExportStatus = "running"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+// RequestLogExport is an asynchronous CSV export of a user's request logs,
+// used for ranges too large to stream in a single response.
+type RequestLogExport struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null;index" json:"-"`
+	From        time.Time    `gorm:"not null" json:"from"`
+	To          time.Time    `gorm:"not null" json:"to"`
+	Status      ExportStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	RowCount    int64        `gorm:"not null;default:0" json:"row_count"`
+	Content     []byte       `gorm:"type:bytea" json:"-"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (e *RequestLogExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (RequestLogExport) TableName() string {
+	return "request_log_exports"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SearchAnalytics aggregates suggestion queries per type, normalized term and
+// day. It feeds popularity ranking of suggestions.
+type SearchAnalytics struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	SearchType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_search_analytics_type_term_day" json:"search_type"`
+	Term       string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_search_analytics_type_term_day" json:"term"`
+	Day        time.Time `gorm:"type:date;not null;uniqueIndex:idx_search_analytics_type_term_day;index" json:"day"`
+	Count      int64     `gorm:"not null;default:0" json:"count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (SearchAnalytics) TableName() string {
+	return "search_analytics"
+}
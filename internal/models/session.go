@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Session is a JWT issued at login. Its ID is the token's jti claim, so the
+// token can be revoked before it expires.
+type Session struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	Device    string     `gorm:"type:varchar(255)" json:"device"`
+	IPAddress string     `gorm:"type:varchar(45)" json:"ip_address"`
+	IssuedAt  time.Time  `gorm:"not null" json:"issued_at"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
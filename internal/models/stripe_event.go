@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StripeEventStatus is how far processing a Stripe event got.
+type StripeEventStatus string
+
+const (
+	StripeEventProcessing StripeEventStatus = "processing"
+	StripeEventProcessed  StripeEventStatus = "processed"
+	StripeEventFailed     StripeEventStatus = "failed"
+)
+
+// StripeEvent is a webhook event received from Stripe. Stripe redelivers
+// events it doesn't see acknowledged, so they are recorded by ID and each
+// is processed once.
+type StripeEvent struct {
+	ID      string            `gorm:"type:varchar(255);primaryKey" json:"id"`
+	Type    string            `gorm:"type:varchar(100);not null;index" json:"type"`
+	Payload json.RawMessage   `gorm:"type:jsonb;not null" json:"payload"`
+	Status  StripeEventStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	// Error is why the last attempt failed.
+	Error      string    `gorm:"type:text" json:"error,omitempty"`
+	Attempts   int       `gorm:"not null" json:"attempts"`
+	ReceivedAt time.Time `gorm:"not null" json:"received_at"`
+	// ClaimedAt is when the last attempt started.
+	ClaimedAt   time.Time  `gorm:"not null" json:"claimed_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+func (StripeEvent) TableName() string {
+	return "stripe_events"
+}
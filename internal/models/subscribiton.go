@@ -15,19 +15,30 @@ const (
 	EnterprisePlan SubscriptionPlan = "ENTERPRISE"
 )
 
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusTrialing = "trialing"
+	SubscriptionStatusCanceled = "cancelled"
+)
+
 type Subscription struct {
-	ID               uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID           uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
-	PlanType         SubscriptionPlan `gorm:"type:varchar(20);not null" json:"plan_type"`
-	StripeCustomerID string           `gorm:"type:varchar(255);not null;default:''" json:"stripe_customer_id"`
-	StripePlanID     string           `gorm:"type:varchar(255);not nulldefault:''" json:"stripe_plan_id"`
-	StartDate        time.Time        `gorm:"not null" json:"start_date"`
-	EndDate          time.Time        `gorm:"default:null" json:"end_date"`
-	Status           string           `gorm:"type:varchar(50);not null" json:"status"`
-	CreatedAt        time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt        time.Time        `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt        gorm.DeletedAt   `gorm:"index" json:"-"`
-	User             User             `gorm:"foreignKey:UserID" json:"-"`
+	ID                uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID            uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	PlanType          SubscriptionPlan `gorm:"type:varchar(20);not null" json:"plan_type"`
+	StripeCustomerID  string           `gorm:"type:varchar(255);not null;default:''" json:"stripe_customer_id"`
+	StripePlanID      string           `gorm:"type:varchar(255);not nulldefault:''" json:"stripe_plan_id"`
+	StartDate         time.Time        `gorm:"not null" json:"start_date"`
+	EndDate           time.Time        `gorm:"default:null" json:"end_date"`
+	Status            string           `gorm:"type:varchar(50);not null" json:"status"`
+	CancelAtPeriodEnd bool             `gorm:"not null;default:false" json:"cancel_at_period_end"`
+	TrialEndsAt       *time.Time       `gorm:"default:null" json:"trial_ends_at,omitempty"`
+	// IsComp marks subscriptions granted by an admin without Stripe. While
+	// active they take precedence over the user's regular subscription.
+	IsComp    bool           `gorm:"not null;default:false" json:"is_comp"`
+	CreatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	User      User           `gorm:"foreignKey:UserID" json:"-"`
 }
 
 func (Subscription) TableName() string {
@@ -50,6 +61,11 @@ func (s *Subscription) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsTrialing reports whether the subscription is in its free trial.
+func (s *Subscription) IsTrialing() bool {
+	return s.Status == SubscriptionStatusTrialing
+}
+
 func (s *Subscription) BeforeUpdate(tx *gorm.DB) error {
 	s.UpdatedAt = time.Now()
 	return nil
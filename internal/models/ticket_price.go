@@ -0,0 +1,112 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// entryPriceKeys are the ticket price keys that name the standard adult
+// admission, most specific first.
+var entryPriceKeys = []string{"adult", "adults", "general", "standard", "regular", "admission", "entry"}
+
+// freePrices are the prices that mean admission costs nothing.
+var freePrices = map[string]bool{
+	"free":       true,
+	"free entry": true,
+	"gratis":     true,
+	"none":       true,
+}
+
+var currencySymbols = map[string]string{
+	"€": "EUR",
+	"$": "USD",
+	"£": "GBP",
+	"¥": "JPY",
+	"₹": "INR",
+	"₩": "KRW",
+}
+
+var (
+	priceAmountPattern   = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+	priceCurrencyPattern = regexp.MustCompile(`\b[A-Z]{3}\b`)
+)
+
+// NormalizeTicketPrices returns the entry price of a landmark from its
+// ticket prices, like {"adult": "15 EUR", "child": "Free"}: the adult
+// price when one is listed, otherwise the lowest listed price, with its
+// ISO 4217 currency when the price names one. Free entry is 0. amount is
+// nil when no price can be read.
+func NormalizeTicketPrices(prices map[string]string) (amount *float64, currency string) {
+	for _, key := range entryPriceKeys {
+		for name, value := range prices {
+			if strings.EqualFold(strings.TrimSpace(name), key) {
+				if price, ok := parseTicketPrice(value); ok {
+					return &price, ticketPriceCurrency(value, price)
+				}
+			}
+		}
+	}
+
+	for _, value := range prices {
+		price, ok := parseTicketPrice(value)
+		if ok && (amount == nil || price < *amount) {
+			amount = &price
+			currency = ticketPriceCurrency(value, price)
+		}
+	}
+	return amount, currency
+}
+
+// parseTicketPrice reads the amount of a price like "15 EUR", "€12.50",
+// "1,500 JPY" or "Free". A separator followed by exactly three digits
+// groups thousands; any other separates decimals.
+func parseTicketPrice(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if freePrices[strings.ToLower(value)] {
+		return 0, true
+	}
+
+	match := priceAmountPattern.FindString(value)
+	if match == "" {
+		return 0, false
+	}
+	var number strings.Builder
+	for i, group := range strings.FieldsFunc(match, func(r rune) bool { return r == '.' || r == ',' }) {
+		if i > 0 && len(group) != 3 {
+			number.WriteByte('.')
+		}
+		number.WriteString(group)
+	}
+	price, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+// ticketPriceCurrency returns the currency a price names, if any. Free
+// entry has none.
+func ticketPriceCurrency(value string, price float64) string {
+	if price == 0 {
+		return ""
+	}
+	if code := priceCurrencyPattern.FindString(value); code != "" {
+		return code
+	}
+	for symbol, code := range currencySymbols {
+		if strings.Contains(value, symbol) {
+			return code
+		}
+	}
+	return ""
+}
+
+// BeforeSave keeps the normalized entry price in step with the ticket
+// prices.
+func (d *LandmarkDetail) BeforeSave(tx *gorm.DB) error {
+	d.EntryPrice, d.EntryCurrency = NormalizeTicketPrices(d.TicketPrices)
+	return nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UsageReportStatus string
+
+const (
+	UsageReportPending  UsageReportStatus = "pending"
+	UsageReportReported UsageReportStatus = "reported"
+	UsageReportFailed   UsageReportStatus = "failed"
+)
+
+// UsageReport records a single usage submission to Stripe for a metered
+// subscription. One row exists per subscription and day so that a report
+// can be retried with the same idempotency key without double billing.
+type UsageReport struct {
+	ID                       uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	SubscriptionID           uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_usage_report_sub_date" json:"subscription_id"`
+	UserID                   uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	StripeSubscriptionItemID string            `gorm:"type:varchar(255);not null" json:"stripe_subscription_item_id"`
+	ReportDate               time.Time         `gorm:"type:date;not null;uniqueIndex:idx_usage_report_sub_date" json:"report_date"`
+	PeriodStart              time.Time         `gorm:"not null" json:"period_start"`
+	PeriodEnd                time.Time         `gorm:"not null" json:"period_end"`
+	Quantity                 int64             `gorm:"not null" json:"quantity"`
+	IdempotencyKey           string            `gorm:"type:varchar(255);not null;uniqueIndex" json:"idempotency_key"`
+	StripeUsageRecordID      string            `gorm:"type:varchar(255)" json:"stripe_usage_record_id"`
+	Status                   UsageReportStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Error                    string            `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt                time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt                time.Time         `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (UsageReport) TableName() string {
+	return "usage_reports"
+}
+
+func (u *UsageReport) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	now := time.Now()
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = now
+	}
+	if u.UpdatedAt.IsZero() {
+		u.UpdatedAt = now
+	}
+	return nil
+}
+
+func (u *UsageReport) BeforeUpdate(tx *gorm.DB) error {
+	u.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UsageStatement sums up a user's usage over a billing period that has
+// ended: how many requests they made, what their plan included and how
+// many went past it.
+type UsageStatement struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_usage_statement_user_period" json:"-"`
+	PeriodStart time.Time `gorm:"not null;uniqueIndex:idx_usage_statement_user_period" json:"period_start"`
+	PeriodEnd   time.Time `gorm:"not null" json:"period_end"`
+	// Plan is the user's plan when the statement was made.
+	Plan     SubscriptionPlan `gorm:"type:varchar(20);not null" json:"plan"`
+	Requests int64            `gorm:"not null" json:"requests"`
+	// Quota is the requests the plan included, -1 when unlimited.
+	Quota int64 `gorm:"not null" json:"quota"`
+	// Overage is how many requests were made past the quota.
+	Overage int64 `gorm:"not null" json:"overage"`
+	// TopEndpoints are the routes the user requested most in the period,
+	// as far as the request logs still cover it.
+	TopEndpoints EndpointUsageList `gorm:"type:jsonb;not null;default:'[]'" json:"top_endpoints"`
+	CreatedAt    time.Time         `gorm:"not null" json:"created_at"`
+}
+
+func (s *UsageStatement) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (UsageStatement) TableName() string {
+	return "usage_statements"
+}
+
+// EndpointUsageList stores a list of EndpointUsage in a JSONB column
+type EndpointUsageList []EndpointUsage
+
+// Scan implements the sql.Scanner interface
+func (l *EndpointUsageList) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, (*[]EndpointUsage)(l))
+}
+
+// Value implements the driver.Valuer interface
+func (l EndpointUsageList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal([]EndpointUsage(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(bytes), nil
+}
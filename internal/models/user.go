@@ -8,20 +8,22 @@ import (
 )
 
 type User struct {
-	ID              uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
-	Name            string         `gorm:"type:varchar(255);not null" json:"name"`
-	Email           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	PasswordHash    string         `gorm:"type:varchar(255);not null" json:"-"`
-	Role            string         `gorm:"type:varchar(255);not null;default:'user'" json:"role"`
-	APIKeys         []APIKey       `gorm:"foreignkey:UserID" json:"api_keys,omitempty"` // Add this line
-	StripeID        string         `gorm:"type:varchar(255);not null;default:''" json:"stripe_id"`
-	HasAccess       bool           `gorm:"type:boolean;not null;default:false" json:"has_access"`
-	OnBoarding      bool           `gorm:"type:boolean;not null;default:false" json:"on_boarding"`
-	AccessGrantedAt time.Time      `gorm:"default:null" json:"access_granted_at"`
-	AccessRevokedAt time.Time      `gorm:"default:null" json:"access_revoked_at"`
-	CreatedAt       time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt       time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"` // Adds soft delete capability
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	Email        string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	PasswordHash string    `gorm:"type:varchar(255);not null" json:"-"`
+	Role         string    `gorm:"type:varchar(255);not null;default:'user'" json:"role"`
+	APIKeys      []APIKey  `gorm:"foreignkey:UserID" json:"api_keys,omitempty"` // Add this line
+	StripeID     string    `gorm:"type:varchar(255);not null;default:''" json:"stripe_id"`
+	HasAccess    bool      `gorm:"type:boolean;not null;default:false" json:"has_access"`
+	OnBoarding   bool      `gorm:"type:boolean;not null;default:false" json:"on_boarding"`
+	// OnboardingOptOut stops the onboarding email sequence for the user.
+	OnboardingOptOut bool           `gorm:"type:boolean;not null;default:false" json:"onboarding_opt_out"`
+	AccessGrantedAt  time.Time      `gorm:"default:null" json:"access_granted_at"`
+	AccessRevokedAt  time.Time      `gorm:"default:null" json:"access_revoked_at"`
+	CreatedAt        time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"` // Adds soft delete capability
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
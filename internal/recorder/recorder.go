@@ -0,0 +1,244 @@
+// Package recorder provides a VCR-style http.RoundTripper that records
+// outbound requests to sanitized JSON fixtures and replays them later.
+package recorder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Mode string
+
+const (
+	ModeLive   Mode = "live"
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveParams are query or form parameters whose values never reach a
+// fixture file.
+var sensitiveParams = map[string]bool{
+	"appid":    true,
+	"api_key":  true,
+	"apikey":   true,
+	"key":      true,
+	"token":    true,
+	"email":    true,
+	"password": true,
+}
+
+// sensitiveHeaders are dropped from recorded requests and responses.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Stripe-Account",
+	"Idempotency-Key",
+}
+
+// Fixture is the on-disk representation of a recorded exchange.
+type Fixture struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Transport records or replays requests depending on its mode.
+type Transport struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+}
+
+// NewTransport wraps next. An empty or unknown mode behaves like ModeLive.
+func NewTransport(mode Mode, dir string, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{mode: mode, dir: dir, next: next}
+}
+
+// NewClient returns an HTTP client using a recording Transport.
+func NewClient(mode Mode, dir string) *http.Client {
+	return &http.Client{Transport: NewTransport(mode, dir, nil)}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch t.mode {
+	case ModeRecord:
+		return t.record(req)
+	case ModeReplay:
+		return t.replay(req)
+	default:
+		return t.next.RoundTrip(req)
+	}
+}
+
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	reqBody, err := readAndRestore(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readAndRestore(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := sanitizeRequest(req, reqBody)
+	fixture := Fixture{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     sanitizeHeader(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+
+	if err := t.save(fixture); err != nil {
+		return nil, fmt.Errorf("failed to save fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	reqBody, err := readAndRestore(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := sanitizeRequest(req, reqBody)
+	path := t.fixturePath(recorded)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture recorded for %s %s: %w", recorded.Method, recorded.URL, err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	header := fixture.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		StatusCode:    fixture.Response.StatusCode,
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(fixture.Response.Body)),
+		ContentLength: int64(len(fixture.Response.Body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}, nil
+}
+
+func (t *Transport) save(fixture Fixture) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	path := t.fixturePath(fixture.Request)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// fixturePath derives a stable file name from the sanitized request so the
+// same call maps to the same fixture in record and replay mode.
+func (t *Transport) fixturePath(req RecordedRequest) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL + "\n" + req.Body))
+	host := "unknown"
+	if u, err := url.Parse(req.URL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return filepath.Join(t.dir, host, hex.EncodeToString(sum[:8])+".json")
+}
+
+func sanitizeRequest(req *http.Request, body []byte) RecordedRequest {
+	u := *req.URL
+	u.RawQuery = sanitizeValues(u.Query()).Encode()
+
+	recordedBody := string(body)
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if values, err := url.ParseQuery(recordedBody); err == nil {
+			recordedBody = sanitizeValues(values).Encode()
+		}
+	}
+
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    u.String(),
+		Body:   recordedBody,
+	}
+}
+
+func sanitizeValues(values url.Values) url.Values {
+	for key := range values {
+		if sensitiveParams[strings.ToLower(key)] {
+			values.Set(key, redacted)
+		}
+	}
+	return values
+}
+
+func sanitizeHeader(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range sensitiveHeaders {
+		clean.Del(name)
+	}
+	return clean
+}
+
+func readAndRestore(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
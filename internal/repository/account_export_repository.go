@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAccountExportNotFound = errors.New("account export not found")
+
+type AccountExportRepository interface {
+	Create(ctx context.Context, export *models.AccountExport) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.AccountExport, error)
+	// GetUnfinished returns the user's pending or running export, or nil
+	// when they have none.
+	GetUnfinished(ctx context.Context, userID uuid.UUID) (*models.AccountExport, error)
+	// ClaimPending marks the oldest pending export as running and returns it,
+	// or nil when none is pending.
+	ClaimPending(ctx context.Context) (*models.AccountExport, error)
+	Complete(ctx context.Context, id uuid.UUID, content []byte) error
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+	// DeleteFinishedBefore deletes completed and failed exports created
+	// before cutoff. Pending and running exports are kept.
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type accountExportRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountExportRepository(db *gorm.DB) AccountExportRepository {
+	return &accountExportRepository{db: db}
+}
+
+func (r *accountExportRepository) Create(ctx context.Context, export *models.AccountExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *accountExportRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.AccountExport, error) {
+	var export models.AccountExport
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAccountExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *accountExportRepository) GetUnfinished(ctx context.Context, userID uuid.UUID) (*models.AccountExport, error) {
+	var export models.AccountExport
+	err := r.db.WithContext(ctx).
+		Omit("content").
+		Where("user_id = ? AND status IN ?", userID, []models.ExportStatus{models.ExportPending, models.ExportRunning}).
+		Order("created_at ASC").
+		First(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *accountExportRepository) ClaimPending(ctx context.Context) (*models.AccountExport, error) {
+	for {
+		var export models.AccountExport
+		err := r.db.WithContext(ctx).
+			Omit("content").
+			Where("status = ?", models.ExportPending).
+			Order("created_at ASC").
+			First(&export).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Another worker may have claimed it in the meantime
+		result := r.db.WithContext(ctx).Model(&models.AccountExport{}).
+			Where("id = ? AND status = ?", export.ID, models.ExportPending).
+			Updates(map[string]interface{}{
+				"status":     models.ExportRunning,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			export.Status = models.ExportRunning
+			return &export, nil
+		}
+	}
+}
+
+func (r *accountExportRepository) Complete(ctx context.Context, id uuid.UUID, content []byte) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.AccountExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportCompleted,
+			"content":      content,
+			"size":         len(content),
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *accountExportRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.AccountExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *accountExportRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND created_at < ?", []models.ExportStatus{models.ExportCompleted, models.ExportFailed}, cutoff).
+		Delete(&models.AccountExport{})
+	return result.RowsAffected, result.Error
+}
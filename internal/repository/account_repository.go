@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountData is everything stored about a user but their request logs,
+// which are read separately as they can be large.
+type AccountData struct {
+	User          models.User
+	Subscriptions []models.Subscription
+	APIKeys       []models.APIKey
+	Sessions      []models.Session
+	Usage         []models.APIUsage
+	UsageReports  []models.UsageReport
+	CustomFields  []models.LandmarkCustomFields
+	Notifications []models.Notification
+	Onboarding    []models.OnboardingEmail
+	Credits       []models.RequestCredit
+	Statements    []models.UsageStatement
+}
+
+// AccountRepository manages a user's account as a whole, for deleting it
+// and exporting its data.
+type AccountRepository interface {
+	// Export loads the user's data, ErrNotFound if the user is deleted.
+	Export(ctx context.Context, userID uuid.UUID) (*AccountData, error)
+	// Delete soft deletes the user and revokes their sessions, so none of
+	// their tokens or keys work anymore.
+	Delete(ctx context.Context, userID uuid.UUID, at time.Time) error
+	// ListDeletedBefore returns up to limit users deleted before cutoff,
+	// oldest first.
+	ListDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.User, error)
+	// Purge permanently deletes the user and every row about them.
+	Purge(ctx context.Context, userID uuid.UUID) error
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+func (r *accountRepository) Export(ctx context.Context, userID uuid.UUID) (*AccountData, error) {
+	db := r.db.WithContext(ctx)
+	var data AccountData
+	if err := db.First(&data.User, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound
+		}
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+
+	queries := []struct {
+		dest   interface{}
+		userID interface{}
+		order  string
+	}{
+		{&data.Subscriptions, userID, "created_at"},
+		{&data.APIKeys, userID, "created_at"},
+		{&data.Sessions, userID, "issued_at"},
+		{&data.Usage, userID.String(), "period_start"},
+		{&data.UsageReports, userID, "created_at"},
+		{&data.CustomFields, userID, "created_at"},
+		{&data.Notifications, userID, "created_at"},
+		{&data.Onboarding, userID, "sent_at"},
+		{&data.Credits, userID, "created_at"},
+		{&data.Statements, userID, "period_start"},
+	}
+	for _, query := range queries {
+		if err := db.Where("user_id = ?", query.userID).Order(query.order).Find(query.dest).Error; err != nil {
+			return nil, errors.Wrap(err, "failed to export account data")
+		}
+	}
+	return &data, nil
+}
+
+func (r *accountRepository) Delete(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.User{}, "id = ?", userID)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "failed to delete user")
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+		err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", at).Error
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke sessions")
+		}
+		return nil
+	})
+}
+
+func (r *accountRepository) ListDeletedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list deleted users")
+	}
+	return users, nil
+}
+
+func (r *accountRepository) Purge(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("api_key_id IN (?)", tx.Model(&models.APIKey{}).Select("id").Where("user_id = ?", userID)).
+			Delete(&models.APIKeyLimit{}).Error
+		if err != nil {
+			return errors.Wrap(err, "failed to purge API key limits")
+		}
+
+		// Usage and request logs store the user ID as text
+		rows := []struct {
+			model  interface{}
+			userID interface{}
+		}{
+			{&models.APIKey{}, userID},
+			{&models.Session{}, userID},
+			{&models.Subscription{}, userID},
+			{&models.UsageReport{}, userID},
+			{&models.LandmarkCustomFields{}, userID},
+			{&models.RequestLogExport{}, userID},
+			{&models.AccountExport{}, userID},
+			{&models.Notification{}, userID},
+			{&models.OnboardingEmail{}, userID},
+			{&models.RequestCredit{}, userID},
+			{&models.UsageStatement{}, userID},
+			{&models.APIUsage{}, userID.String()},
+			{&models.RequestLog{}, userID.String()},
+		}
+		for _, row := range rows {
+			if err := tx.Unscoped().Where("user_id = ?", row.userID).Delete(row.model).Error; err != nil {
+				return errors.Wrap(err, "failed to purge account data")
+			}
+		}
+
+		if err := tx.Unscoped().Delete(&models.User{}, "id = ?", userID).Error; err != nil {
+			return errors.Wrap(err, "failed to purge user")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AltTextImage is a photo without alt text and the landmark it shows.
+type AltTextImage struct {
+	Image    models.LandmarkImage
+	Landmark models.Landmark
+}
+
+// AltTextRepository stores the alt text generated for landmark photos.
+type AltTextRepository interface {
+	// ListDue returns up to limit photos and panoramas without alt text
+	// that were never described, or whose description failed before
+	// retryBefore, least recently tried first.
+	ListDue(ctx context.Context, retryBefore time.Time, limit int) ([]AltTextImage, error)
+	// Save fills in the alt text and caption of an image where they are
+	// still empty, as admins may have written them meanwhile, and records
+	// that it was described at now. Empty values only record the attempt.
+	Save(ctx context.Context, image models.LandmarkImage, altText, caption string, now time.Time) error
+}
+
+type altTextRepository struct {
+	db *gorm.DB
+}
+
+func NewAltTextRepository(db *gorm.DB) AltTextRepository {
+	return &altTextRepository{db: db}
+}
+
+func (r *altTextRepository) ListDue(ctx context.Context, retryBefore time.Time, limit int) ([]AltTextImage, error) {
+	db := r.db.WithContext(ctx)
+	var images []models.LandmarkImage
+	err := db.
+		Where("alt_text = '' AND image_url <> ''").
+		Where("media_type IN ?", []models.MediaType{models.MediaPhoto, models.MediaPano}).
+		Where("alt_text_checked_at IS NULL OR alt_text_checked_at < ?", retryBefore).
+		Order("alt_text_checked_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&images).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list images without alt text")
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, image.LandmarkID)
+	}
+	var landmarks []models.Landmark
+	if err := db.Select("id", "name", "city", "country").Where("id IN ?", ids).Find(&landmarks).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get landmarks of images")
+	}
+	byID := make(map[uuid.UUID]models.Landmark, len(landmarks))
+	for _, landmark := range landmarks {
+		byID[landmark.ID] = landmark
+	}
+
+	due := make([]AltTextImage, 0, len(images))
+	for _, image := range images {
+		due = append(due, AltTextImage{Image: image, Landmark: byID[image.LandmarkID]})
+	}
+	return due, nil
+}
+
+func (r *altTextRepository) Save(ctx context.Context, image models.LandmarkImage, altText, caption string, now time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"alt_text_checked_at": now}
+		if altText != "" {
+			updates["alt_text"] = gorm.Expr("CASE WHEN alt_text = '' THEN ? ELSE alt_text END", altText)
+		}
+		if caption != "" {
+			updates["caption"] = gorm.Expr("CASE WHEN caption = '' THEN ? ELSE caption END", caption)
+		}
+		if altText == "" && caption == "" {
+			return tx.Model(&image).UpdateColumns(updates).Error
+		}
+
+		updates["updated_at"] = now
+		if err := tx.Model(&image).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+		// Syncing clients see the landmark changed
+		return touchLandmark(tx, image.LandmarkID, now)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save alt text")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrAPIKeyLimitNotFound = errors.New("api key limit not found")
+
+type APIKeyLimitRepository interface {
+	GetByAPIKeyID(ctx context.Context, apiKeyID uuid.UUID) (*models.APIKeyLimit, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.APIKeyLimit, error)
+	Upsert(ctx context.Context, limit *models.APIKeyLimit) error
+	Delete(ctx context.Context, apiKeyID uuid.UUID) error
+}
+
+type apiKeyLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyLimitRepository(db *gorm.DB) APIKeyLimitRepository {
+	return &apiKeyLimitRepository{db: db}
+}
+
+func (r *apiKeyLimitRepository) GetByAPIKeyID(ctx context.Context, apiKeyID uuid.UUID) (*models.APIKeyLimit, error) {
+	var limit models.APIKeyLimit
+
+	err := r.db.WithContext(ctx).First(&limit, "api_key_id = ?", apiKeyID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &limit, err
+}
+
+// GetByUserID returns the limit on the user's API key, or nil when the key
+// has no override.
+func (r *apiKeyLimitRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.APIKeyLimit, error) {
+	var limit models.APIKeyLimit
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN api_keys ON api_keys.id = api_key_limits.api_key_id").
+		Where("api_keys.user_id = ?", userID).
+		First(&limit).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &limit, err
+}
+
+func (r *apiKeyLimitRepository) Upsert(ctx context.Context, limit *models.APIKeyLimit) error {
+	now := time.Now()
+	limit.UpdatedAt = now
+	if limit.CreatedAt.IsZero() {
+		limit.CreatedAt = now
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "api_key_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"request_limit", "reason", "updated_at"}),
+	}).Create(limit).Error
+}
+
+func (r *apiKeyLimitRepository) Delete(ctx context.Context, apiKeyID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&models.APIKeyLimit{}, "api_key_id = ?", apiKeyID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyLimitNotFound
+	}
+	return nil
+}
@@ -13,9 +13,20 @@ import (
 type APIKeyRepository interface {
 	Create(ctx context.Context, apiKey *models.APIKey) error
 	GetByKey(ctx context.Context, key string) (*models.APIKey, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*models.APIKey, error)
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 	UpdateAPIKey(ctx context.Context, userID uuid.UUID, apiKey string) error
+	// UpdateSigning sets the signing secret of the user's key and whether
+	// requests must be signed with it.
+	UpdateSigning(ctx context.Context, userID uuid.UUID, secret string, required bool) error
+	// Rotate replaces the user's key with newKey, which expires at
+	// expiresAt if set. The old key keeps working until graceUntil.
+	Rotate(ctx context.Context, userID uuid.UUID, newKey string, expiresAt *time.Time, graceUntil time.Time) (*models.APIKey, error)
+	// ListExpiring returns keys expiring between now and before whose owners
+	// have not yet been reminded reminderDays or fewer days ahead.
+	ListExpiring(ctx context.Context, now, before time.Time, reminderDays int) ([]models.APIKey, error)
+	MarkExpiryReminded(ctx context.Context, id uuid.UUID, reminderDays int) error
 }
 
 type apiKeyRepository struct {
@@ -36,7 +47,10 @@ func (r *apiKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) er
 
 func (r *apiKeyRepository) GetByKey(ctx context.Context, key string) (*models.APIKey, error) {
 	var apiKey models.APIKey
-	result := r.db.WithContext(ctx).First(&apiKey, "key = ?", key)
+	// A replaced key still matches until its grace period ends
+	result := r.db.WithContext(ctx).
+		Where("key = ? OR (previous_key = ? AND previous_key_expires_at > ?)", key, key, time.Now()).
+		First(&apiKey)
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
@@ -48,6 +62,18 @@ func (r *apiKeyRepository) GetByKey(ctx context.Context, key string) (*models.AP
 	return &apiKey, nil
 }
 
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	result := r.db.WithContext(ctx).First(&apiKey, "id = ?", id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, errors.ErrNotFound
+		}
+		return nil, errors.Wrap(result.Error, "failed to get API key by id")
+	}
+	return &apiKey, nil
+}
+
 func (r *apiKeyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.APIKey, error) {
 	var apiKey models.APIKey
 	result := r.db.WithContext(ctx).First(&apiKey, "user_id = ?", userID)
@@ -90,3 +116,63 @@ func (r *apiKeyRepository) UpdateAPIKey(ctx context.Context, userID uuid.UUID, a
 
 	return nil
 }
+
+func (r *apiKeyRepository) UpdateSigning(ctx context.Context, userID uuid.UUID, secret string, required bool) error {
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"signing_secret":    secret,
+		"require_signature": required,
+		"updated_at":        time.Now(),
+	})
+
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to update API key signing")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) Rotate(ctx context.Context, userID uuid.UUID, newKey string, expiresAt *time.Time, graceUntil time.Time) (*models.APIKey, error) {
+	// previous_key is set from the key column's value before the update
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"key":                     newKey,
+		"previous_key":            gorm.Expr("key"),
+		"previous_key_expires_at": graceUntil,
+		"expires_at":              expiresAt,
+		"expiry_reminder_days":    0,
+		"updated_at":              time.Now(),
+	})
+
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "failed to rotate API key")
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, errors.ErrNotFound
+	}
+
+	return r.GetByUserID(ctx, userID)
+}
+
+func (r *apiKeyRepository) ListExpiring(ctx context.Context, now, before time.Time, reminderDays int) ([]models.APIKey, error) {
+	var apiKeys []models.APIKey
+	result := r.db.WithContext(ctx).
+		Where("expires_at > ? AND expires_at <= ?", now, before).
+		Where("expiry_reminder_days = 0 OR expiry_reminder_days > ?", reminderDays).
+		Find(&apiKeys)
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "failed to list expiring API keys")
+	}
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) MarkExpiryReminded(ctx context.Context, id uuid.UUID, reminderDays int) error {
+	result := r.db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", id).Update("expiry_reminder_days", reminderDays)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to mark API key expiry reminder")
+	}
+	return nil
+}
@@ -3,12 +3,34 @@ package repository
 import (
 	"context"
 	"landmark-api/internal/models"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// auditLogExportBatch is how many rows EachAuditLog loads at a time.
+const auditLogExportBatch = 500
+
+// AuditLogFilter narrows audit log queries. Zero values are ignored.
+type AuditLogFilter struct {
+	AdminID    uuid.UUID
+	Action     string
+	EntityType string
+	From       time.Time
+	To         time.Time
+	// Search matches details and entity IDs case-insensitively.
+	Search string
+}
+
 type AuditLogRepository interface {
-	ListAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, int64, error)
+	ListAuditLogs(ctx context.Context, filter AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error)
+	// EachAuditLog calls fn for every matching log, newest first, loading
+	// them in batches so large exports do not sit in memory.
+	EachAuditLog(ctx context.Context, filter AuditLogFilter, fn func(models.AuditLog) error) error
+	// ListForLandmark returns the landmark's entries and the approval of the
+	// submission it was created from, oldest first.
+	ListForLandmark(ctx context.Context, landmarkID uuid.UUID) ([]models.AuditLog, error)
 	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
 }
 
@@ -22,18 +44,44 @@ func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
 	}
 }
 
-func (r *auditLogRepository) ListAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, int64, error) {
+func (r *auditLogRepository) filtered(ctx context.Context, filter AuditLogFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+
+	if filter.AdminID != uuid.Nil {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.Action != "" {
+		query = query.Where("UPPER(action) = UPPER(?)", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("UPPER(entity_type) = UPPER(?)", filter.EntityType)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("timestamp >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("timestamp <= ?", filter.To)
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		query = query.Where("details ILIKE ? OR entity_id ILIKE ?", pattern, pattern)
+	}
+
+	return query
+}
+
+func (r *auditLogRepository) ListAuditLogs(ctx context.Context, filter AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error) {
 	var logs []models.AuditLog
 	var total int64
 
 	offset := (page - 1) * pageSize
 
-	err := r.db.WithContext(ctx).Model(&models.AuditLog{}).Count(&total).Error
+	err := r.filtered(ctx, filter).Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
 
-	err = r.db.WithContext(ctx).
+	err = r.filtered(ctx, filter).
 		Order("timestamp DESC").
 		Offset(offset).
 		Limit(pageSize).
@@ -42,6 +90,44 @@ func (r *auditLogRepository) ListAuditLogs(ctx context.Context, page, pageSize i
 	return logs, total, err
 }
 
+func (r *auditLogRepository) EachAuditLog(ctx context.Context, filter AuditLogFilter, fn func(models.AuditLog) error) error {
+	offset := 0
+	for {
+		var batch []models.AuditLog
+		err := r.filtered(ctx, filter).
+			Order("timestamp DESC, id DESC").
+			Offset(offset).
+			Limit(auditLogExportBatch).
+			Find(&batch).Error
+		if err != nil {
+			return err
+		}
+
+		for _, log := range batch {
+			if err := fn(log); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < auditLogExportBatch {
+			return nil
+		}
+		offset += len(batch)
+	}
+}
+
+func (r *auditLogRepository) ListForLandmark(ctx context.Context, landmarkID uuid.UUID) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	id := landmarkID.String()
+
+	err := r.db.WithContext(ctx).
+		Where("entity_id = ? AND entity_type IN ?", id, []string{"LANDMARK", "LANDMARK_PHOTO"}).
+		Or("entity_type = ? AND action = ? AND details LIKE ?", "SUBMISSION_LANDMARK", "APPROVE", "%"+id+"%").
+		Order("timestamp ASC").
+		Find(&logs).Error
+	return logs, err
+}
+
 func (r *auditLogRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
 	return r.db.WithContext(ctx).Create(log).Error
 }
@@ -2,13 +2,53 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"landmark-api/internal/models"
+	"sort"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryExists   = errors.New("category already exists")
+	ErrCategoryInUse    = errors.New("category still has landmarks")
+	ErrCategoryCycle    = errors.New("a category cannot be nested under itself or its subcategories")
+)
+
+// categoryTreeCTE walks from a managed category down through all categories
+// nested under it.
+const categoryTreeCTE = `WITH RECURSIVE tree AS (
+	SELECT id, name FROM categories WHERE name = ?
+	UNION ALL
+	SELECT c.id, c.name FROM categories c JOIN tree t ON c.parent_id = t.id
+)`
+
+const descendantsSQL = categoryTreeCTE + ` SELECT name FROM tree`
+
+// CategoryWithDescendants returns a subquery of the category's name and all
+// of its subcategories' names, for filtering landmarks by a parent category.
+func CategoryWithDescendants(db *gorm.DB, name string) *gorm.DB {
+	return db.Raw(descendantsSQL+" UNION SELECT ?", name, name)
+}
+
 type CategoryRepository interface {
 	ListAllCategories(ctx context.Context) ([]string, error)
+	// Create adds a category, nested under parent unless parent is empty.
+	Create(ctx context.Context, name, parent string) (*models.Category, error)
+	// Rename renames a category on every landmark and pending submission
+	// that uses it, returning the number of landmarks updated.
+	Rename(ctx context.Context, oldName, newName string) (int64, error)
+	// Merge moves every landmark from source to target and removes source,
+	// returning the number of landmarks moved.
+	Merge(ctx context.Context, source, target string) (int64, error)
+	Delete(ctx context.Context, name string) error
+	// Tree returns the category hierarchy. Categories that are only set on
+	// landmarks appear as roots.
+	Tree(ctx context.Context) ([]*models.CategoryNode, error)
+	// SetParent nests name under parent, or makes it a root when parent is
+	// empty.
+	SetParent(ctx context.Context, name, parent string) error
 }
 
 type categoryRepository struct {
@@ -21,12 +61,343 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	}
 }
 
+// ListAllCategories returns managed categories together with any category
+// that is only set on landmarks.
 func (r *categoryRepository) ListAllCategories(ctx context.Context) ([]string, error) {
-	var categories []string
+	var used []string
 	err := r.db.WithContext(ctx).
 		Model(&models.Landmark{}).
 		Distinct("category").
-		Pluck("category", &categories).
+		Pluck("category", &used).
 		Error
-	return categories, err
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []string
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Pluck("name", &managed).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(used)+len(managed))
+	categories := make([]string, 0, len(used)+len(managed))
+	for _, name := range append(used, managed...) {
+		if !seen[name] {
+			seen[name] = true
+			categories = append(categories, name)
+		}
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
+
+func categoryExists(tx *gorm.DB, name string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.Category{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	if err := tx.Model(&models.Landmark{}).Where("category = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *categoryRepository) Create(ctx context.Context, name, parent string) (*models.Category, error) {
+	category := &models.Category{Name: name}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found, err := categoryExists(tx, name)
+		if err != nil {
+			return err
+		}
+		if found {
+			return ErrCategoryExists
+		}
+
+		if parent != "" {
+			found, err := categoryExists(tx, parent)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return ErrCategoryNotFound
+			}
+			parentRow, err := ensureCategory(tx, parent)
+			if err != nil {
+				return err
+			}
+			category.ParentID = &parentRow.ID
+		}
+
+		return tx.Create(category).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *categoryRepository) Rename(ctx context.Context, oldName, newName string) (int64, error) {
+	var moved int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found, err := categoryExists(tx, oldName)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCategoryNotFound
+		}
+
+		taken, err := categoryExists(tx, newName)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrCategoryExists
+		}
+
+		moved, err = recategorize(tx, oldName, newName)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Category{}).Where("name = ?", oldName).Update("name", newName).Error
+	})
+	return moved, err
+}
+
+func (r *categoryRepository) Merge(ctx context.Context, source, target string) (int64, error) {
+	var moved int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found, err := categoryExists(tx, source)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCategoryNotFound
+		}
+
+		found, err = categoryExists(tx, target)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCategoryNotFound
+		}
+
+		if err := checkNotDescendant(tx, source, target); err != nil {
+			return err
+		}
+
+		moved, err = recategorize(tx, source, target)
+		if err != nil {
+			return err
+		}
+
+		sourceRow, err := findCategory(tx, source)
+		if err != nil || sourceRow == nil {
+			return err
+		}
+
+		// Subcategories of the source move under the target
+		targetRow, err := ensureCategory(tx, target)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", sourceRow.ID).Update("parent_id", targetRow.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(sourceRow).Error
+	})
+	return moved, err
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, name string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Landmark{}).Where("category = ?", name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCategoryInUse
+		}
+
+		row, err := findCategory(tx, name)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return ErrCategoryNotFound
+		}
+
+		// Subcategories move up to the deleted category's parent
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", row.ID).Update("parent_id", row.ParentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(row).Error
+	})
+}
+
+func (r *categoryRepository) Tree(ctx context.Context) ([]*models.CategoryNode, error) {
+	var rows []models.Category
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var counts []struct {
+		Category string
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Landmark{}).
+		Select("category, COUNT(*) AS count").
+		Group("category").
+		Order("category ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*models.CategoryNode, len(rows)+len(counts))
+	byID := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		nodes[row.Name] = &models.CategoryNode{Name: row.Name, Children: []*models.CategoryNode{}}
+		byID[row.ID] = row.Name
+	}
+
+	var roots []*models.CategoryNode
+	for _, count := range counts {
+		node, ok := nodes[count.Category]
+		if !ok {
+			node = &models.CategoryNode{Name: count.Category, Children: []*models.CategoryNode{}}
+			nodes[count.Category] = node
+			roots = append(roots, node)
+		}
+		node.LandmarkCount = count.Count
+	}
+
+	for _, row := range rows {
+		node := nodes[row.Name]
+		if row.ParentID != nil {
+			if parent, ok := nodes[byID[*row.ParentID]]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].Name < roots[j].Name
+	})
+	return roots, nil
+}
+
+func (r *categoryRepository) SetParent(ctx context.Context, name, parent string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		found, err := categoryExists(tx, name)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCategoryNotFound
+		}
+
+		row, err := ensureCategory(tx, name)
+		if err != nil {
+			return err
+		}
+
+		if parent == "" {
+			return tx.Model(row).Update("parent_id", nil).Error
+		}
+
+		found, err = categoryExists(tx, parent)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrCategoryNotFound
+		}
+
+		if err := checkNotDescendant(tx, name, parent); err != nil {
+			return err
+		}
+
+		parentRow, err := ensureCategory(tx, parent)
+		if err != nil {
+			return err
+		}
+		return tx.Model(row).Update("parent_id", parentRow.ID).Error
+	})
+}
+
+// checkNotDescendant returns ErrCategoryCycle when other is ancestor itself
+// or one of its subcategories.
+func checkNotDescendant(tx *gorm.DB, ancestor, other string) error {
+	if ancestor == other {
+		return ErrCategoryCycle
+	}
+
+	var descendants []string
+	if err := tx.Raw(descendantsSQL, ancestor).Scan(&descendants).Error; err != nil {
+		return err
+	}
+	for _, descendant := range descendants {
+		if descendant == other {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+func findCategory(tx *gorm.DB, name string) (*models.Category, error) {
+	var category models.Category
+	err := tx.Where("name = ?", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ensureCategory returns the managed row for name, creating it for
+// categories that so far only existed on landmarks.
+func ensureCategory(tx *gorm.DB, name string) (*models.Category, error) {
+	category, err := findCategory(tx, name)
+	if err != nil || category != nil {
+		return category, err
+	}
+
+	category = &models.Category{Name: name}
+	if err := tx.Create(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// recategorize moves landmarks and pending submissions from one category to
+// another.
+func recategorize(tx *gorm.DB, from, to string) (int64, error) {
+	result := tx.Model(&models.Landmark{}).Where("category = ?", from).Update("category", to)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	err := tx.Model(&models.SubmissionLandmark{}).
+		Where("category = ? AND status = ?", from, "pending").
+		Update("category", to).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
 }
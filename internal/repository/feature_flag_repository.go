@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+	ErrFeatureFlagExists   = errors.New("feature flag already exists")
+)
+
+type FeatureFlagRepository interface {
+	List(ctx context.Context) ([]models.FeatureFlag, error)
+	GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	Create(ctx context.Context, flag *models.FeatureFlag) error
+	Update(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, key string) error
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) List(ctx context.Context) ([]models.FeatureFlag, error) {
+	var flags []models.FeatureFlag
+	err := r.db.WithContext(ctx).Order("key ASC").Find(&flags).Error
+	return flags, err
+}
+
+func (r *featureFlagRepository) GetByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	err := r.db.WithContext(ctx).Where("key = ?", key).First(&flag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) Create(ctx context.Context, flag *models.FeatureFlag) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.FeatureFlag{}).Where("key = ?", flag.Key).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrFeatureFlagExists
+	}
+	return r.db.WithContext(ctx).Create(flag).Error
+}
+
+func (r *featureFlagRepository) Update(ctx context.Context, flag *models.FeatureFlag) error {
+	result := r.db.WithContext(ctx).Model(&models.FeatureFlag{}).
+		Where("key = ?", flag.Key).
+		Updates(map[string]interface{}{
+			"description":     flag.Description,
+			"enabled":         flag.Enabled,
+			"rollout_percent": flag.RolloutPercent,
+			"target_plans":    flag.TargetPlans,
+			"target_user_ids": flag.TargetUserIDs,
+			"updated_at":      gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeatureFlagNotFound
+	}
+	return nil
+}
+
+func (r *featureFlagRepository) Delete(ctx context.Context, key string) error {
+	result := r.db.WithContext(ctx).Where("key = ?", key).Delete(&models.FeatureFlag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFeatureFlagNotFound
+	}
+	return nil
+}
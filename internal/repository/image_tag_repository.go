@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImageTagRepository stores what the image-tagging job recognized in
+// submitted images.
+type ImageTagRepository interface {
+	// ListUntagged returns up to limit pending submissions with images the
+	// job hasn't looked at, oldest first, with their images and tags.
+	ListUntagged(ctx context.Context, limit int) ([]models.SubmissionLandmark, error)
+	// Save stores the tags of a submission's images, and the category and
+	// tags suggested from them.
+	Save(ctx context.Context, submission *models.SubmissionLandmark, tags []models.SubmissionImageTags) error
+	// Review accepts or rejects a submitted image at now, over what the job
+	// made of it, and returns its tags.
+	Review(ctx context.Context, imageID uuid.UUID, rejected bool, now time.Time) (*models.SubmissionImageTags, error)
+}
+
+type imageTagRepository struct {
+	db *gorm.DB
+}
+
+func NewImageTagRepository(db *gorm.DB) ImageTagRepository {
+	return &imageTagRepository{db: db}
+}
+
+func (r *imageTagRepository) ListUntagged(ctx context.Context, limit int) ([]models.SubmissionLandmark, error) {
+	var submissions []models.SubmissionLandmark
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "pending").
+		Where(`EXISTS (
+			SELECT 1 FROM submission_landmark_images
+			LEFT JOIN submission_image_tags ON submission_image_tags.submission_landmark_image_id = submission_landmark_images.id
+			WHERE submission_landmark_images.submission_landmark_id = submission_landmarks.id
+				AND submission_image_tags.id IS NULL)`).
+		Preload("Images.Tags").
+		Order("created_at").
+		Limit(limit).
+		Find(&submissions).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list untagged submissions")
+	}
+	return submissions, nil
+}
+
+func (r *imageTagRepository) Save(ctx context.Context, submission *models.SubmissionLandmark, tags []models.SubmissionImageTags) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range tags {
+			if tags[i].ID == uuid.Nil {
+				tags[i].ID = uuid.New()
+			}
+			// An admin may have reviewed the image meanwhile
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "submission_landmark_image_id"}},
+				DoNothing: true,
+			}).Create(&tags[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(submission).Updates(map[string]interface{}{
+			"suggested_category":  submission.SuggestedCategory,
+			"category_confidence": submission.CategoryConfidence,
+			"suggested_tags":      submission.SuggestedTags,
+		}).Error
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save image tags")
+	}
+	return nil
+}
+
+func (r *imageTagRepository) Review(ctx context.Context, imageID uuid.UUID, rejected bool, now time.Time) (*models.SubmissionImageTags, error) {
+	var tags models.SubmissionImageTags
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var image models.SubmissionLandmarkImage
+		if err := tx.Select("id").First(&image, "id = ?", imageID).Error; err != nil {
+			return err
+		}
+
+		// Images the job hasn't looked at yet get empty tags, which also
+		// keeps the job from overriding the admin
+		tags = models.SubmissionImageTags{
+			ID:                        uuid.New(),
+			SubmissionLandmarkImageID: imageID,
+			Rejected:                  rejected,
+			TaggedAt:                  now,
+			ReviewedAt:                &now,
+		}
+		return tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "submission_landmark_image_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"rejected", "reviewed_at", "updated_at"}),
+			},
+			clause.Returning{},
+		).Create(&tags).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to review submission image")
+	}
+	return &tags, nil
+}
@@ -0,0 +1,239 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"landmark-api/internal/testharness"
+	"os"
+	"testing"
+	"time"
+)
+
+var env *testharness.Env
+
+func TestMain(m *testing.M) {
+	var err error
+	env, err = testharness.Start(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error starting test environment: %v\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	env.Close()
+	os.Exit(code)
+}
+
+// reset empties the database before a test.
+func reset(t *testing.T) context.Context {
+	t.Helper()
+	ctx := context.Background()
+	if err := env.Reset(ctx); err != nil {
+		t.Fatalf("error resetting test environment: %v", err)
+	}
+	return ctx
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := reset(t)
+	users := repository.NewUserRepository(env.DB)
+	user, _, err := testharness.User().Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := users.GetByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if found.ID != user.ID {
+		t.Errorf("GetByEmail found user %s, want %s", found.ID, user.ID)
+	}
+
+	if err := users.RevokeAccess(ctx, user.ID); err != nil {
+		t.Fatalf("RevokeAccess: %v", err)
+	}
+	found, err = users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if found.HasAccess {
+		t.Error("user still has access after RevokeAccess")
+	}
+}
+
+func TestSubscriptionRepository(t *testing.T) {
+	ctx := reset(t)
+	subscriptions := repository.NewSubscriptionRepository(env.DB)
+
+	user, subscription, err := testharness.User().Plan(models.ProPlan).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := subscriptions.GetActiveByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetActiveByUserID: %v", err)
+	}
+	if active.ID != subscription.ID || active.PlanType != models.ProPlan {
+		t.Errorf("active subscription = %s on %s, want %s on %s", active.ID, active.PlanType, subscription.ID, models.ProPlan)
+	}
+
+	active.PlanType = models.EnterprisePlan
+	if err := subscriptions.Update(ctx, active); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	updated, err := subscriptions.GetByID(ctx, subscription.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if updated.PlanType != models.EnterprisePlan {
+		t.Errorf("plan after Update = %s, want %s", updated.PlanType, models.EnterprisePlan)
+	}
+
+	// Canceled and ended subscriptions don't count as active
+	canceled, _, err := testharness.User().Status(models.SubscriptionStatusCanceled).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	ended, _, err := testharness.User().Period(now.AddDate(0, -2, 0), now.AddDate(0, -1, 0)).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []*models.User{canceled, ended} {
+		if _, err := subscriptions.GetActiveByUserID(ctx, user.ID); !errors.Is(err, repository.ErrSubscriptionNotFound) {
+			t.Errorf("GetActiveByUserID(%s) error = %v, want ErrSubscriptionNotFound", user.Email, err)
+		}
+	}
+}
+
+func TestIncrementUsage(t *testing.T) {
+	ctx := reset(t)
+	usage := repository.NewAPIUsageRepository(env.DB)
+	periodStart := time.Now().Truncate(time.Second)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	user, _, err := testharness.User().Period(periodStart, periodEnd).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if err := usage.IncrementUsage(user.ID); err != nil {
+			t.Fatalf("IncrementUsage: %v", err)
+		}
+	}
+
+	current, err := usage.GetCurrentUsage(user.ID.String(), periodStart, periodEnd)
+	if err != nil {
+		t.Fatalf("GetCurrentUsage: %v", err)
+	}
+	if current == nil || current.RequestCount != 3 {
+		t.Errorf("usage = %+v, want 3 requests", current)
+	}
+}
+
+func TestIncrementUsageRollsOverPeriod(t *testing.T) {
+	ctx := reset(t)
+	usage := repository.NewAPIUsageRepository(env.DB)
+
+	// The period ended a day ago, so the next one runs until a month after
+	// that. Postgres keeps microseconds, so whole seconds compare equal.
+	periodEnd := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	periodStart := periodEnd.AddDate(0, -1, 0)
+	user, subscription, err := testharness.User().Plan(models.ProPlan).Period(periodStart, periodEnd).Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testharness.APIUsage(ctx, env.DB, user.ID, periodStart, periodEnd, 50); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := usage.IncrementUsage(user.ID); err != nil {
+		t.Fatalf("IncrementUsage: %v", err)
+	}
+
+	nextEnd := periodEnd.AddDate(0, 1, 0)
+	rolled, err := repository.NewSubscriptionRepository(env.DB).GetByID(ctx, subscription.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !rolled.StartDate.Equal(periodEnd) || !rolled.EndDate.Equal(nextEnd) {
+		t.Errorf("period after rollover = %s to %s, want %s to %s", rolled.StartDate, rolled.EndDate, periodEnd, nextEnd)
+	}
+
+	current, err := usage.GetCurrentUsage(user.ID.String(), periodEnd, nextEnd)
+	if err != nil {
+		t.Fatalf("GetCurrentUsage: %v", err)
+	}
+	if current == nil || current.RequestCount != 1 {
+		t.Errorf("usage of the new period = %+v, want 1 request", current)
+	}
+	previous, err := usage.GetCurrentUsage(user.ID.String(), periodStart, periodEnd)
+	if err != nil {
+		t.Fatalf("GetCurrentUsage: %v", err)
+	}
+	if previous == nil || previous.RequestCount != 50 {
+		t.Errorf("usage of the ended period = %+v, want 50 requests", previous)
+	}
+}
+
+func TestAccountExportClaim(t *testing.T) {
+	ctx := reset(t)
+	exports := repository.NewAccountExportRepository(env.DB)
+	user, _, err := testharness.User().Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	export := &models.AccountExport{UserID: user.ID, Status: models.ExportPending}
+	if err := exports.Create(ctx, export); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	claimed, err := exports.ClaimPending(ctx)
+	if err != nil {
+		t.Fatalf("ClaimPending: %v", err)
+	}
+	if claimed == nil || claimed.ID != export.ID || claimed.Status != models.ExportRunning {
+		t.Fatalf("ClaimPending = %+v, want export %s running", claimed, export.ID)
+	}
+	if again, err := exports.ClaimPending(ctx); err != nil || again != nil {
+		t.Fatalf("second ClaimPending = %+v, %v, want nothing to claim", again, err)
+	}
+
+	// A running export is still the one the user gets back
+	unfinished, err := exports.GetUnfinished(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetUnfinished: %v", err)
+	}
+	if unfinished == nil || unfinished.ID != export.ID {
+		t.Errorf("GetUnfinished = %+v, want export %s", unfinished, export.ID)
+	}
+
+	content := []byte("zip")
+	if err := exports.Complete(ctx, export.ID, content); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	completed, err := exports.GetByID(ctx, export.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if completed.Status != models.ExportCompleted || string(completed.Content) != string(content) || completed.Size != int64(len(content)) {
+		t.Errorf("completed export = %s with %q (%d bytes), want %s with %q", completed.Status, completed.Content, completed.Size, models.ExportCompleted, content)
+	}
+	if unfinished, err := exports.GetUnfinished(ctx, user.ID); err != nil || unfinished != nil {
+		t.Errorf("GetUnfinished after Complete = %+v, %v, want nothing", unfinished, err)
+	}
+
+	// Exports belong to their user
+	other, _, err := testharness.User().Create(ctx, env.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exports.GetByID(ctx, export.ID, other.ID); !errors.Is(err, repository.ErrAccountExportNotFound) {
+		t.Errorf("GetByID by another user error = %v, want ErrAccountExportNotFound", err)
+	}
+}
@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBulkItemNotFound     = errors.New("not found")
+	ErrSubmissionNotPending = errors.New("submission is not pending")
+	ErrTooManyTags          = errors.New("landmark would have too many tags")
+
+	// errBulkFailed rolls back a bulk transaction in which an item failed.
+	errBulkFailed = errors.New("bulk operation failed")
+)
+
+// LandmarkBulkRepository changes many landmarks or submissions in one
+// transaction. Each method returns an error per ID, in the order of ids,
+// for the items that could not be changed; if any could not, nothing is.
+// The error it returns on its own is a database failure.
+type LandmarkBulkRepository interface {
+	SetCategory(ctx context.Context, ids []uuid.UUID, category string) ([]error, error)
+	SetStatus(ctx context.Context, ids []uuid.UUID, status models.LandmarkStatus) ([]error, error)
+	AddTags(ctx context.Context, ids []uuid.UUID, tags []string) ([]error, error)
+	RemoveTags(ctx context.Context, ids []uuid.UUID, tags []string) ([]error, error)
+	// Delete removes landmarks with their images and details.
+	Delete(ctx context.Context, ids []uuid.UUID) ([]error, error)
+	// PublishSubmissions turns pending submissions into landmarks, returning
+	// the ID of the landmark created from each.
+	PublishSubmissions(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, []error, error)
+	// GetSubmissions returns the submissions with the given IDs, in any
+	// order.
+	GetSubmissions(ctx context.Context, ids []uuid.UUID) ([]models.SubmissionLandmark, error)
+}
+
+type landmarkBulkRepository struct {
+	db *gorm.DB
+}
+
+func NewLandmarkBulkRepository(db *gorm.DB) LandmarkBulkRepository {
+	return &landmarkBulkRepository{db: db}
+}
+
+func (r *landmarkBulkRepository) SetCategory(ctx context.Context, ids []uuid.UUID, category string) ([]error, error) {
+	return r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		result := tx.Model(&models.Landmark{}).Where("id = ?", id).Update("category", category)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBulkItemNotFound
+		}
+		return nil
+	})
+}
+
+func (r *landmarkBulkRepository) SetStatus(ctx context.Context, ids []uuid.UUID, status models.LandmarkStatus) ([]error, error) {
+	return r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		result := tx.Model(&models.Landmark{}).Where("id = ?", id).Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBulkItemNotFound
+		}
+		return nil
+	})
+}
+
+func (r *landmarkBulkRepository) AddTags(ctx context.Context, ids []uuid.UUID, tags []string) ([]error, error) {
+	return r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		return updateTags(tx, id, func(current models.StringList) (models.StringList, error) {
+			for _, tag := range tags {
+				if !current.Contains(tag) {
+					current = append(current, tag)
+				}
+			}
+			if len(current) > models.MaxLandmarkTags {
+				return nil, ErrTooManyTags
+			}
+			return current, nil
+		})
+	})
+}
+
+func (r *landmarkBulkRepository) RemoveTags(ctx context.Context, ids []uuid.UUID, tags []string) ([]error, error) {
+	remove := models.StringList(tags)
+	return r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		return updateTags(tx, id, func(current models.StringList) (models.StringList, error) {
+			kept := models.StringList{}
+			for _, tag := range current {
+				if !remove.Contains(tag) {
+					kept = append(kept, tag)
+				}
+			}
+			return kept, nil
+		})
+	})
+}
+
+func (r *landmarkBulkRepository) Delete(ctx context.Context, ids []uuid.UUID) ([]error, error) {
+	return r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		result := tx.Delete(&models.Landmark{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBulkItemNotFound
+		}
+		if err := tx.Where("landmark_id = ?", id).Delete(&models.LandmarkImage{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("landmark_id = ?", id).Delete(&models.LandmarkDetail{}).Error
+	})
+}
+
+func (r *landmarkBulkRepository) PublishSubmissions(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, []error, error) {
+	created := make(map[uuid.UUID]uuid.UUID, len(ids))
+	itemErrs, err := r.each(ctx, ids, func(tx *gorm.DB, id uuid.UUID) error {
+		landmarkID, err := publishSubmission(tx, id)
+		if err != nil {
+			return err
+		}
+		created[id] = landmarkID
+		return nil
+	})
+	if err != nil || itemErrs != nil {
+		return nil, itemErrs, err
+	}
+
+	landmarkIDs := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		landmarkIDs[i] = created[id]
+	}
+	return landmarkIDs, nil, nil
+}
+
+func (r *landmarkBulkRepository) GetSubmissions(ctx context.Context, ids []uuid.UUID) ([]models.SubmissionLandmark, error) {
+	var submissions []models.SubmissionLandmark
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&submissions).Error
+	return submissions, err
+}
+
+// publishSubmission creates a landmark from a pending submission and marks
+// the submission approved.
+func publishSubmission(tx *gorm.DB, id uuid.UUID) (uuid.UUID, error) {
+	var submission models.SubmissionLandmark
+	err := tx.Preload("Images.Tags").Preload("Detail").First(&submission, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, ErrBulkItemNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if submission.Status != "pending" {
+		return uuid.Nil, ErrSubmissionNotPending
+	}
+
+	landmark := models.Landmark{
+		ID:          uuid.New(),
+		Name:        submission.Name,
+		Description: submission.Description,
+		Latitude:    submission.Latitude,
+		Longitude:   submission.Longitude,
+		Country:     submission.Country,
+		City:        submission.City,
+		Category:    submission.Category,
+	}
+	if err := tx.Omit("Images").Create(&landmark).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	// Images found not to show the landmark are left out
+	order := 0
+	for _, img := range submission.Images {
+		if img.Rejected() {
+			continue
+		}
+		image := models.LandmarkImage{
+			ID:           uuid.New(),
+			LandmarkID:   landmark.ID,
+			ImageURL:     img.ImageURL,
+			DisplayOrder: order,
+		}
+		if err := tx.Create(&image).Error; err != nil {
+			return uuid.Nil, err
+		}
+		order++
+	}
+
+	detail := models.LandmarkDetail{
+		ID:                     uuid.New(),
+		LandmarkID:             landmark.ID,
+		OpeningHours:           submission.Detail.OpeningHours,
+		TicketPrices:           submission.Detail.TicketPrices,
+		HistoricalSignificance: submission.Detail.HistoricalSignificance,
+		VisitorTips:            submission.Detail.VisitorTips,
+		AccessibilityInfo:      submission.Detail.AccessibilityInfo,
+	}
+	if err := tx.Create(&detail).Error; err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Model(&submission).Update("status", "approved").Error; err != nil {
+		return uuid.Nil, err
+	}
+	return landmark.ID, nil
+}
+
+// updateTags replaces the tags of a landmark with what change makes of
+// them.
+func updateTags(tx *gorm.DB, id uuid.UUID, change func(models.StringList) (models.StringList, error)) error {
+	var landmark models.Landmark
+	err := tx.Select("id", "tags").First(&landmark, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBulkItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	tags, err := change(landmark.Tags)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Landmark{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"tags":       tags,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// each applies apply to every ID in one transaction, each item in a
+// savepoint of its own so one failing item does not hide why the others
+// would fail. Item errors are the sentinel errors of this file; any other
+// error aborts the run.
+func (r *landmarkBulkRepository) each(ctx context.Context, ids []uuid.UUID, apply func(tx *gorm.DB, id uuid.UUID) error) ([]error, error) {
+	itemErrs := make([]error, len(ids))
+	failed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			err := tx.Transaction(func(item *gorm.DB) error {
+				return apply(item, id)
+			})
+			if isBulkItemError(err) {
+				itemErrs[i] = err
+				failed = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if failed {
+			return errBulkFailed
+		}
+		return nil
+	})
+	if failed && errors.Is(err, errBulkFailed) {
+		return itemErrs, nil
+	}
+	return nil, err
+}
+
+func isBulkItemError(err error) bool {
+	return errors.Is(err, ErrBulkItemNotFound) || errors.Is(err, ErrSubmissionNotPending) || errors.Is(err, ErrTooManyTags)
+}
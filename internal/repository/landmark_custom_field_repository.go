@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LandmarkCustomFieldRepository interface {
+	// Get returns the custom fields userID attached to a landmark, or nil if
+	// it has none.
+	Get(ctx context.Context, userID, landmarkID uuid.UUID) (*models.LandmarkCustomFields, error)
+	// ListForLandmarks returns the custom fields userID attached to any of
+	// landmarkIDs.
+	ListForLandmarks(ctx context.Context, userID uuid.UUID, landmarkIDs []uuid.UUID) ([]models.LandmarkCustomFields, error)
+	// Save stores custom fields, replacing the ones the user had on the
+	// landmark.
+	Save(ctx context.Context, fields *models.LandmarkCustomFields) error
+	Delete(ctx context.Context, userID, landmarkID uuid.UUID) error
+}
+
+type landmarkCustomFieldRepository struct {
+	db *gorm.DB
+}
+
+func NewLandmarkCustomFieldRepository(db *gorm.DB) LandmarkCustomFieldRepository {
+	return &landmarkCustomFieldRepository{db: db}
+}
+
+func (r *landmarkCustomFieldRepository) Get(ctx context.Context, userID, landmarkID uuid.UUID) (*models.LandmarkCustomFields, error) {
+	var fields models.LandmarkCustomFields
+	err := r.db.WithContext(ctx).Where("user_id = ? AND landmark_id = ?", userID, landmarkID).First(&fields).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fields, nil
+}
+
+func (r *landmarkCustomFieldRepository) ListForLandmarks(ctx context.Context, userID uuid.UUID, landmarkIDs []uuid.UUID) ([]models.LandmarkCustomFields, error) {
+	var fields []models.LandmarkCustomFields
+	if len(landmarkIDs) == 0 {
+		return fields, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND landmark_id IN ?", userID, landmarkIDs).
+		Find(&fields).Error
+	return fields, err
+}
+
+func (r *landmarkCustomFieldRepository) Save(ctx context.Context, fields *models.LandmarkCustomFields) error {
+	if fields.ID == uuid.Nil {
+		fields.ID = uuid.New()
+	}
+	fields.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "landmark_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fields", "updated_at"}),
+	}).Create(fields).Error
+}
+
+func (r *landmarkCustomFieldRepository) Delete(ctx context.Context, userID, landmarkID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND landmark_id = ?", userID, landmarkID).
+		Delete(&models.LandmarkCustomFields{}).Error
+}
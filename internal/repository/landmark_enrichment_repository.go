@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LandmarkEnrichmentRepository interface {
+	// GetByLandmarkID returns the enrichment of a landmark, or nil if it was
+	// never enriched.
+	GetByLandmarkID(ctx context.Context, landmarkID uuid.UUID) (*models.LandmarkEnrichment, error)
+	// ListDue returns up to limit landmarks that were never enriched or were
+	// last enriched before staleBefore, least recently enriched first.
+	ListDue(ctx context.Context, staleBefore time.Time, limit int) ([]models.Landmark, error)
+	// Save stores an enrichment, replacing any earlier one for its landmark.
+	Save(ctx context.Context, enrichment *models.LandmarkEnrichment) error
+}
+
+type landmarkEnrichmentRepository struct {
+	db *gorm.DB
+}
+
+func NewLandmarkEnrichmentRepository(db *gorm.DB) LandmarkEnrichmentRepository {
+	return &landmarkEnrichmentRepository{db: db}
+}
+
+func (r *landmarkEnrichmentRepository) GetByLandmarkID(ctx context.Context, landmarkID uuid.UUID) (*models.LandmarkEnrichment, error) {
+	var enrichment models.LandmarkEnrichment
+	err := r.db.WithContext(ctx).Where("landmark_id = ?", landmarkID).First(&enrichment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enrichment, nil
+}
+
+func (r *landmarkEnrichmentRepository) ListDue(ctx context.Context, staleBefore time.Time, limit int) ([]models.Landmark, error) {
+	var landmarks []models.Landmark
+	err := r.db.WithContext(ctx).
+		Joins("LEFT JOIN landmark_enrichments ON landmark_enrichments.landmark_id = landmarks.id").
+		Where("landmark_enrichments.id IS NULL OR landmark_enrichments.enriched_at < ?", staleBefore).
+		Order("landmark_enrichments.enriched_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&landmarks).Error
+	return landmarks, err
+}
+
+func (r *landmarkEnrichmentRepository) Save(ctx context.Context, enrichment *models.LandmarkEnrichment) error {
+	if enrichment.ID == uuid.Nil {
+		enrichment.ID = uuid.New()
+	}
+	enrichment.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "landmark_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"wikidata_id",
+			"wikipedia_url",
+			"summary",
+			"official_website",
+			"heritage_status",
+			"images",
+			"enriched_at",
+			"updated_at",
+		}),
+	}).Create(enrichment).Error
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"landmark-api/internal/geo"
 	"landmark-api/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -12,14 +15,93 @@ import (
 
 type LandmarkRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Landmark, error)
+	// GetWithHistory returns the landmark and its images even when the
+	// landmark was deleted, or nil when it never existed.
+	GetWithHistory(ctx context.Context, id uuid.UUID) (*models.Landmark, error)
 	List(ctx context.Context, limit, offset int) ([]models.Landmark, error)
-	ListWithFilters(ctx context.Context, page, perPage int, searchTerm, category string) ([]models.Landmark, int64, error)
+	ListWithFilters(ctx context.Context, page, perPage int, searchTerm, category string, status models.LandmarkStatus) ([]models.Landmark, int64, error)
 	Create(ctx context.Context, landmark *models.Landmark) error
+	// CreateWithDetails creates the landmark, its detail row and images in
+	// one transaction. IDs are assigned here.
+	CreateWithDetails(ctx context.Context, landmark *models.Landmark, detail *models.LandmarkDetail, imageURLs []string) error
+	// BackfillMissingDetails creates an empty detail row for every landmark
+	// without one and returns how many were created.
+	BackfillMissingDetails(ctx context.Context) (int64, error)
 	Update(ctx context.Context, landmark *models.Landmark) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetDetails(ctx context.Context, id uuid.UUID) (*models.LandmarkDetail, error)
 	FindByCountry(ctx context.Context, country string) ([]models.Landmark, error)
 	FindByName(ctx context.Context, name string) ([]models.Landmark, error)
+	MarkVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time, confidence float64) error
+	SetStatus(ctx context.Context, id uuid.UUID, status models.LandmarkStatus) error
+	FindMatchCandidates(ctx context.Context, query MatchCandidateQuery) ([]MatchCandidate, error)
+	// Clusters groups the landmarks inside query.Bounds into a grid of
+	// query.CellSize degree cells, largest clusters first.
+	Clusters(ctx context.Context, query ClusterQuery) ([]LandmarkCluster, error)
+	// ListMissingTimezone returns up to limit landmarks whose timezone has
+	// not been looked up yet.
+	ListMissingTimezone(ctx context.Context, limit int) ([]models.Landmark, error)
+	SetTimezone(ctx context.Context, id uuid.UUID, timezone string) error
+	// ListImages returns the images of a landmark in display order.
+	ListImages(ctx context.Context, landmarkID uuid.UUID) ([]models.LandmarkImage, error)
+	// ReorderImages gives the images of a landmark the display order of
+	// imageIDs.
+	ReorderImages(ctx context.Context, landmarkID uuid.UUID, imageIDs []uuid.UUID) error
+	// SetPrimaryImage makes an image the only primary image of its landmark
+	// and the landmark's image_url.
+	SetPrimaryImage(ctx context.Context, landmarkID, imageID uuid.UUID) error
+	UpdateImageCaption(ctx context.Context, landmarkID, imageID uuid.UUID, caption, altText, credit string) (*models.LandmarkImage, error)
+	AddImage(ctx context.Context, image *models.LandmarkImage) error
+	// DeleteImage removes an image from its landmark. If it was the primary
+	// image, the first remaining photo takes its place.
+	DeleteImage(ctx context.Context, landmarkID, imageID uuid.UUID) error
+}
+
+var ErrLandmarkImageNotFound = errors.New("landmark image not found")
+
+// MatchCandidateQuery selects landmarks that could correspond to an external
+// record: similar names anywhere, or any name inside the bounding box.
+type MatchCandidateQuery struct {
+	Name          string
+	MinSimilarity float64
+	HasBounds     bool
+	MinLat        float64
+	MaxLat        float64
+	MinLon        float64
+	MaxLon        float64
+	Limit         int
+	// IncludeUnpublished also considers draft and archived landmarks.
+	IncludeUnpublished bool
+}
+
+// MatchCandidate is a landmark with its trigram name similarity to the query.
+// pg_trgm similarity is case-insensitive.
+type MatchCandidate struct {
+	ID             uuid.UUID
+	Name           string
+	City           string
+	Country        string
+	Latitude       float64
+	Longitude      float64
+	NameSimilarity float64
+}
+
+// ClusterQuery selects the landmarks to cluster and the grid to cluster them
+// on.
+type ClusterQuery struct {
+	Bounds   geo.BBox
+	CellSize float64
+	Limit    int
+}
+
+// LandmarkCluster is the landmarks of one grid cell: how many there are and
+// their centroid. LandmarkID is set when the cell holds a single landmark,
+// so clients can link straight to it.
+type LandmarkCluster struct {
+	Count      int64      `json:"count"`
+	Latitude   float64    `json:"latitude"`
+	Longitude  float64    `json:"longitude"`
+	LandmarkID *uuid.UUID `json:"landmark_id,omitempty"`
 }
 
 type landmarkRepository struct {
@@ -40,7 +122,17 @@ func (r *landmarkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	return &landmark, err
 }
 
-func (r *landmarkRepository) ListWithFilters(ctx context.Context, page, perPage int, searchTerm, category string) ([]models.Landmark, int64, error) {
+func (r *landmarkRepository) GetWithHistory(ctx context.Context, id uuid.UUID) (*models.Landmark, error) {
+	var landmark models.Landmark
+
+	err := r.db.WithContext(ctx).Unscoped().Preload("Images").First(&landmark, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &landmark, err
+}
+
+func (r *landmarkRepository) ListWithFilters(ctx context.Context, page, perPage int, searchTerm, category string, status models.LandmarkStatus) ([]models.Landmark, int64, error) {
 	var landmarks []models.Landmark
 	var total int64
 
@@ -54,6 +146,10 @@ func (r *landmarkRepository) ListWithFilters(ctx context.Context, page, perPage
 		query = query.Where("category = ?", category)
 	}
 
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
 	err := query.Count(&total).Error
 	if err != nil {
 		return nil, 0, err
@@ -81,6 +177,47 @@ func (r *landmarkRepository) Create(ctx context.Context, landmark *models.Landma
 	return r.db.WithContext(ctx).Create(landmark).Error
 }
 
+func (r *landmarkRepository) CreateWithDetails(ctx context.Context, landmark *models.Landmark, detail *models.LandmarkDetail, imageURLs []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		landmark.ID = uuid.New()
+		if err := tx.Omit("Images").Create(landmark).Error; err != nil {
+			return fmt.Errorf("error creating landmark: %w", err)
+		}
+
+		for i, url := range imageURLs {
+			image := models.LandmarkImage{
+				ID:           uuid.New(),
+				LandmarkID:   landmark.ID,
+				ImageURL:     url,
+				DisplayOrder: i,
+			}
+			if err := tx.Create(&image).Error; err != nil {
+				return fmt.Errorf("error creating landmark image: %w", err)
+			}
+		}
+
+		detail.ID = uuid.New()
+		detail.LandmarkID = landmark.ID
+		if err := tx.Create(detail).Error; err != nil {
+			return fmt.Errorf("error creating landmark details: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *landmarkRepository) BackfillMissingDetails(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO landmark_details (id, landmark_id, opening_hours, ticket_prices, historical_significance, visitor_tips, accessibility_info, created_at, updated_at)
+		SELECT gen_random_uuid(), l.id, '{}'::jsonb, '{}'::jsonb, '', '', '', NOW(), NOW()
+		FROM landmarks l
+		WHERE l.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM landmark_details d WHERE d.landmark_id = l.id)`)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
 func (r *landmarkRepository) Update(ctx context.Context, landmark *models.Landmark) error {
 	err := r.db.WithContext(ctx).Model(&models.Landmark{}).
 		Where("id = ?", landmark.ID).
@@ -97,6 +234,256 @@ func (r *landmarkRepository) Update(ctx context.Context, landmark *models.Landma
 	return err
 }
 
+func (r *landmarkRepository) SetStatus(ctx context.Context, id uuid.UUID, status models.LandmarkStatus) error {
+	result := r.db.WithContext(ctx).Model(&models.Landmark{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *landmarkRepository) MarkVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time, confidence float64) error {
+	result := r.db.WithContext(ctx).Model(&models.Landmark{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_verified_at": verifiedAt,
+			"data_confidence":  confidence,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *landmarkRepository) FindMatchCandidates(ctx context.Context, query MatchCandidateQuery) ([]MatchCandidate, error) {
+	var candidates []MatchCandidate
+
+	similarity := gorm.Expr("similarity(name, ?)", query.Name)
+	db := r.db.WithContext(ctx).Model(&models.Landmark{}).
+		Select("id, name, city, country, latitude, longitude, ? AS name_similarity", similarity)
+	if !query.IncludeUnpublished {
+		db = db.Scopes(models.PublishedLandmarks)
+	}
+
+	if query.HasBounds {
+		db = db.Where("similarity(name, ?) >= ? OR (latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?)",
+			query.Name, query.MinSimilarity, query.MinLat, query.MaxLat, query.MinLon, query.MaxLon)
+	} else {
+		db = db.Where("similarity(name, ?) >= ?", query.Name, query.MinSimilarity)
+	}
+
+	err := db.Order("name_similarity DESC").
+		Limit(query.Limit).
+		Scan(&candidates).Error
+
+	return candidates, err
+}
+
+func (r *landmarkRepository) Clusters(ctx context.Context, query ClusterQuery) ([]LandmarkCluster, error) {
+	var rows []struct {
+		Count     int64
+		Latitude  float64
+		Longitude float64
+		FirstID   string
+	}
+
+	db := r.db.WithContext(ctx).Model(&models.Landmark{}).Scopes(models.PublishedLandmarks).
+		Select("COUNT(*) AS count, AVG(latitude) AS latitude, AVG(longitude) AS longitude, MIN(id::text) AS first_id").
+		Where("latitude BETWEEN ? AND ?", query.Bounds.MinLat, query.Bounds.MaxLat)
+	if query.Bounds.MinLon <= query.Bounds.MaxLon {
+		db = db.Where("longitude BETWEEN ? AND ?", query.Bounds.MinLon, query.Bounds.MaxLon)
+	} else {
+		db = db.Where("(longitude >= ? OR longitude <= ?)", query.Bounds.MinLon, query.Bounds.MaxLon)
+	}
+
+	// The cell size is computed from the zoom level, never client input
+	err := db.Group(fmt.Sprintf("FLOOR(latitude / %[1]g), FLOOR(longitude / %[1]g)", query.CellSize)).
+		Order("count DESC").
+		Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]LandmarkCluster, 0, len(rows))
+	for _, row := range rows {
+		cluster := LandmarkCluster{Count: row.Count, Latitude: row.Latitude, Longitude: row.Longitude}
+		if row.Count == 1 {
+			if id, err := uuid.Parse(row.FirstID); err == nil {
+				cluster.LandmarkID = &id
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func (r *landmarkRepository) ListMissingTimezone(ctx context.Context, limit int) ([]models.Landmark, error) {
+	var landmarks []models.Landmark
+	err := r.db.WithContext(ctx).
+		Where("timezone = ''").
+		Order("created_at").
+		Limit(limit).
+		Find(&landmarks).Error
+	return landmarks, err
+}
+
+func (r *landmarkRepository) SetTimezone(ctx context.Context, id uuid.UUID, timezone string) error {
+	return r.db.WithContext(ctx).Model(&models.Landmark{}).
+		Where("id = ?", id).
+		UpdateColumn("timezone", timezone).Error
+}
+
+func (r *landmarkRepository) ListImages(ctx context.Context, landmarkID uuid.UUID) ([]models.LandmarkImage, error) {
+	var images []models.LandmarkImage
+	err := r.db.WithContext(ctx).
+		Where("landmark_id = ?", landmarkID).
+		Order("display_order ASC, created_at ASC").
+		Find(&images).Error
+	return images, err
+}
+
+func (r *landmarkRepository) ReorderImages(ctx context.Context, landmarkID uuid.UUID, imageIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range imageIDs {
+			result := tx.Model(&models.LandmarkImage{}).
+				Where("id = ? AND landmark_id = ?", id, landmarkID).
+				Updates(map[string]interface{}{"display_order": i, "updated_at": time.Now()})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrLandmarkImageNotFound
+			}
+		}
+		return touchLandmark(tx, landmarkID, time.Now())
+	})
+}
+
+func (r *landmarkRepository) SetPrimaryImage(ctx context.Context, landmarkID, imageID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var image models.LandmarkImage
+		err := tx.Where("id = ? AND landmark_id = ?", imageID, landmarkID).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLandmarkImageNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.LandmarkImage{}).
+			Where("landmark_id = ? AND is_primary", landmarkID).
+			Updates(map[string]interface{}{"is_primary": false, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&image).
+			Updates(map[string]interface{}{"is_primary": true, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Landmark{}).
+			Where("id = ?", landmarkID).
+			UpdateColumns(map[string]interface{}{"image_url": image.ImageURL, "updated_at": now}).Error
+	})
+}
+
+func (r *landmarkRepository) UpdateImageCaption(ctx context.Context, landmarkID, imageID uuid.UUID, caption, altText, credit string) (*models.LandmarkImage, error) {
+	var image models.LandmarkImage
+	err := r.db.WithContext(ctx).Where("id = ? AND landmark_id = ?", imageID, landmarkID).First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLandmarkImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	image.Caption = caption
+	image.AltText = altText
+	image.Credit = credit
+	image.UpdatedAt = time.Now()
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&image).
+			Select("caption", "alt_text", "credit", "updated_at").
+			Updates(&image).Error; err != nil {
+			return err
+		}
+		return touchLandmark(tx, landmarkID, image.UpdatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *landmarkRepository) AddImage(ctx context.Context, image *models.LandmarkImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		return touchLandmark(tx, image.LandmarkID, time.Now())
+	})
+}
+
+func (r *landmarkRepository) DeleteImage(ctx context.Context, landmarkID, imageID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var image models.LandmarkImage
+		err := tx.Where("id = ? AND landmark_id = ?", imageID, landmarkID).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLandmarkImageNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+		if !image.IsPrimary {
+			return touchLandmark(tx, landmarkID, time.Now())
+		}
+
+		var next models.LandmarkImage
+		err = tx.Where("landmark_id = ? AND media_type = ?", landmarkID, models.MediaPhoto).
+			Order("display_order ASC, created_at ASC").
+			First(&next).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		if next.ID != uuid.Nil {
+			if err := tx.Model(&next).
+				Updates(map[string]interface{}{"is_primary": true, "updated_at": now}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Landmark{}).
+			Where("id = ?", landmarkID).
+			UpdateColumns(map[string]interface{}{"image_url": next.ImageURL, "updated_at": now}).Error
+	})
+}
+
+// touchLandmark moves the updated_at of a landmark to at, for changes to
+// its gallery that do not otherwise update it, so that syncing clients see
+// the landmark changed.
+func touchLandmark(tx *gorm.DB, id uuid.UUID, at time.Time) error {
+	return tx.Model(&models.Landmark{}).
+		Where("id = ?", id).
+		UpdateColumn("updated_at", at).Error
+}
+
 func (r *landmarkRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result := r.db.WithContext(ctx).Delete(&models.Landmark{}, "id = ?", id)
 	if result.Error != nil {
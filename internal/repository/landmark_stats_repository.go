@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"landmark-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LandmarkStatsRepository interface {
@@ -12,6 +14,11 @@ type LandmarkStatsRepository interface {
 	GetLandmarksByCategory(ctx context.Context) (map[string]int64, error)
 	GetLandmarksByCountry(ctx context.Context) (map[string]int64, error)
 	GetRecentlyAddedLandmarks(ctx context.Context, limit int) ([]models.Landmark, error)
+	// BuildSnapshot computes the activity for the day starting at day.
+	BuildSnapshot(ctx context.Context, day time.Time) (*models.LandmarkStatsSnapshot, error)
+	// SaveSnapshot stores a snapshot, replacing any earlier one for its date.
+	SaveSnapshot(ctx context.Context, snapshot *models.LandmarkStatsSnapshot) error
+	ListSnapshots(ctx context.Context, from, to time.Time) ([]models.LandmarkStatsSnapshot, error)
 }
 
 type landmarkStatsRepository struct {
@@ -80,3 +87,59 @@ func (r *landmarkStatsRepository) GetRecentlyAddedLandmarks(ctx context.Context,
 		Find(&landmarks).Error
 	return landmarks, err
 }
+
+func (r *landmarkStatsRepository) BuildSnapshot(ctx context.Context, day time.Time) (*models.LandmarkStatsSnapshot, error) {
+	end := day.AddDate(0, 0, 1)
+	snapshot := &models.LandmarkStatsSnapshot{Date: day}
+	db := r.db.WithContext(ctx)
+
+	// Deleted landmarks still count as added on their day
+	if err := db.Model(&models.Landmark{}).Where("created_at < ?", end).Count(&snapshot.TotalLandmarks).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Unscoped().Model(&models.Landmark{}).Where("created_at >= ? AND created_at < ?", day, end).Count(&snapshot.LandmarksAdded).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&models.SubmissionLandmark{}).Where("created_at >= ? AND created_at < ?", day, end).Count(&snapshot.SubmissionsCreated).Error; err != nil {
+		return nil, err
+	}
+
+	// A submission's updated_at is when it was reviewed
+	reviewed := func(status string, count *int64) error {
+		return db.Model(&models.SubmissionLandmark{}).
+			Where("status = ? AND updated_at >= ? AND updated_at < ?", status, day, end).
+			Count(count).Error
+	}
+	if err := reviewed("approved", &snapshot.SubmissionsApproved); err != nil {
+		return nil, err
+	}
+	if err := reviewed("rejected", &snapshot.SubmissionsRejected); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *landmarkStatsRepository) SaveSnapshot(ctx context.Context, snapshot *models.LandmarkStatsSnapshot) error {
+	snapshot.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"total_landmarks",
+			"landmarks_added",
+			"submissions_created",
+			"submissions_approved",
+			"submissions_rejected",
+			"updated_at",
+		}),
+	}).Create(snapshot).Error
+}
+
+func (r *landmarkStatsRepository) ListSnapshots(ctx context.Context, from, to time.Time) ([]models.LandmarkStatsSnapshot, error) {
+	var snapshots []models.LandmarkStatsSnapshot
+	err := r.db.WithContext(ctx).
+		Where("date >= ? AND date < ?", from, to).
+		Order("date ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MailSuppressionRepository stores the addresses mail must no longer go
+// to. Addresses are compared case-insensitively.
+type MailSuppressionRepository interface {
+	// Suppress records the address, replacing the reason of an earlier
+	// suppression of it.
+	Suppress(ctx context.Context, suppression *models.MailSuppression) error
+	IsSuppressed(ctx context.Context, address string) (bool, error)
+}
+
+type mailSuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewMailSuppressionRepository(db *gorm.DB) MailSuppressionRepository {
+	return &mailSuppressionRepository{db: db}
+}
+
+func (r *mailSuppressionRepository) Suppress(ctx context.Context, suppression *models.MailSuppression) error {
+	suppression.Address = strings.ToLower(suppression.Address)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "reason", "provider", "updated_at"}),
+	}).Create(suppression).Error
+}
+
+func (r *mailSuppressionRepository) IsSuppressed(ctx context.Context, address string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.MailSuppression{}).
+		Where("address = ?", strings.ToLower(address)).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	// List returns a page of the user's notifications, newest first, with
+	// the number of them in total and unread.
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) (notifications []models.Notification, total, unread int64, err error)
+	// MarkRead marks one of the user's notifications read.
+	MarkRead(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+	// MarkAllRead marks all of the user's notifications read.
+	MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, int64, error) {
+	db := r.db.WithContext(ctx)
+
+	var unread int64
+	err := db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	query := db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	var notifications []models.Notification
+	err = query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&notifications).Error
+	return notifications, total, unread, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotificationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&notification).Update("read_at", at).Error
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OnboardingRepository tracks the onboarding sequence steps sent to each
+// user.
+type OnboardingRepository interface {
+	// Claim records step as sent to the user, returning false if it
+	// already was.
+	Claim(ctx context.Context, userID uuid.UUID, step string, at time.Time) (bool, error)
+	// ListDue returns up to limit users who were sent step after, but not
+	// step, and registered before cutoff. Users who opted out of the
+	// sequence are left out.
+	ListDue(ctx context.Context, step, after string, cutoff time.Time, limit int) ([]uuid.UUID, error)
+}
+
+type onboardingRepository struct {
+	db *gorm.DB
+}
+
+func NewOnboardingRepository(db *gorm.DB) OnboardingRepository {
+	return &onboardingRepository{db: db}
+}
+
+func (r *onboardingRepository) Claim(ctx context.Context, userID uuid.UUID, step string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.OnboardingEmail{
+		UserID: userID,
+		Step:   step,
+		SentAt: at,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *onboardingRepository) ListDue(ctx context.Context, step, after string, cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Joins("JOIN onboarding_emails sent ON sent.user_id = users.id AND sent.step = ?", after).
+		Where("users.created_at < ? AND NOT users.onboarding_opt_out", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM onboarding_emails due WHERE due.user_id = users.id AND due.step = ?)", step).
+		Order("users.created_at").
+		Limit(limit).
+		Pluck("users.id", &userIDs).Error
+	return userIDs, err
+}
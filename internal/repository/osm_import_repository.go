@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrOSMImportNotFound = errors.New("OpenStreetMap import not found")
+
+type OSMImportRepository interface {
+	Create(ctx context.Context, osmImport *models.OSMImport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.OSMImport, error)
+	// ClaimPending marks the oldest pending import as running and returns
+	// it, or nil when none is pending.
+	ClaimPending(ctx context.Context) (*models.OSMImport, error)
+	Complete(ctx context.Context, id uuid.UUID, created, duplicates, skipped int) error
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+	// SubmissionSourceExists reports whether a submission, in any status,
+	// was already imported from source.
+	SubmissionSourceExists(ctx context.Context, source string) (bool, error)
+	// CreateSubmission stores a pending submission with its images and
+	// details.
+	CreateSubmission(ctx context.Context, submission *models.SubmissionLandmark) error
+}
+
+type osmImportRepository struct {
+	db *gorm.DB
+}
+
+func NewOSMImportRepository(db *gorm.DB) OSMImportRepository {
+	return &osmImportRepository{db: db}
+}
+
+func (r *osmImportRepository) Create(ctx context.Context, osmImport *models.OSMImport) error {
+	return r.db.WithContext(ctx).Create(osmImport).Error
+}
+
+func (r *osmImportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.OSMImport, error) {
+	var osmImport models.OSMImport
+	err := r.db.WithContext(ctx).First(&osmImport, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOSMImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &osmImport, nil
+}
+
+func (r *osmImportRepository) ClaimPending(ctx context.Context) (*models.OSMImport, error) {
+	for {
+		var osmImport models.OSMImport
+		err := r.db.WithContext(ctx).
+			Where("status = ?", models.ImportPending).
+			Order("created_at ASC").
+			First(&osmImport).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Another worker may have claimed it in the meantime
+		result := r.db.WithContext(ctx).Model(&models.OSMImport{}).
+			Where("id = ? AND status = ?", osmImport.ID, models.ImportPending).
+			Updates(map[string]interface{}{
+				"status":     models.ImportRunning,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			osmImport.Status = models.ImportRunning
+			return &osmImport, nil
+		}
+	}
+}
+
+func (r *osmImportRepository) Complete(ctx context.Context, id uuid.UUID, created, duplicates, skipped int) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.OSMImport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ImportCompleted,
+			"created":      created,
+			"duplicates":   duplicates,
+			"skipped":      skipped,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *osmImportRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.OSMImport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ImportFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *osmImportRepository) SubmissionSourceExists(ctx context.Context, source string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SubmissionLandmark{}).
+		Where("source = ?", source).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *osmImportRepository) CreateSubmission(ctx context.Context, submission *models.SubmissionLandmark) error {
+	if submission.ID == uuid.Nil {
+		submission.ID = uuid.New()
+	}
+	for i := range submission.Images {
+		submission.Images[i].ID = uuid.New()
+	}
+	submission.Detail.ID = uuid.New()
+	// Images and details are created through their associations
+	return r.db.WithContext(ctx).Create(submission).Error
+}
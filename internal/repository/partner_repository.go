@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PartnerRepository stores the landmarks Enterprise data partners propose.
+type PartnerRepository interface {
+	// CreateSubmission saves a proposed landmark with its images and
+	// details. With publish, it is published at once and the ID of the
+	// landmark made from it is returned.
+	CreateSubmission(ctx context.Context, submission *models.SubmissionLandmark, publish bool) (*uuid.UUID, error)
+	// Contributions counts the proposals of every partner, most proposals
+	// first, or of partnerID alone when it is not nil.
+	Contributions(ctx context.Context, partnerID *uuid.UUID) ([]models.PartnerContributions, error)
+}
+
+type partnerRepository struct {
+	db *gorm.DB
+}
+
+func NewPartnerRepository(db *gorm.DB) PartnerRepository {
+	return &partnerRepository{db: db}
+}
+
+func (r *partnerRepository) CreateSubmission(ctx context.Context, submission *models.SubmissionLandmark, publish bool) (*uuid.UUID, error) {
+	if submission.ID == uuid.Nil {
+		submission.ID = uuid.New()
+	}
+	for i := range submission.Images {
+		submission.Images[i].ID = uuid.New()
+	}
+	submission.Detail.ID = uuid.New()
+
+	var landmarkID *uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Images and details are created through their associations
+		if err := tx.Create(submission).Error; err != nil {
+			return err
+		}
+		if !publish {
+			return nil
+		}
+		id, err := publishSubmission(tx, submission.ID)
+		if err != nil {
+			return err
+		}
+		submission.Status = "approved"
+		landmarkID = &id
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create partner submission")
+	}
+	return landmarkID, nil
+}
+
+func (r *partnerRepository) Contributions(ctx context.Context, partnerID *uuid.UUID) ([]models.PartnerContributions, error) {
+	query := r.db.WithContext(ctx).Model(&models.SubmissionLandmark{}).
+		Select(`submission_landmarks.partner_id,
+			users.email,
+			COUNT(*) AS proposed,
+			COUNT(*) FILTER (WHERE submission_landmarks.status = 'pending') AS pending,
+			COUNT(*) FILTER (WHERE submission_landmarks.status = 'approved') AS approved,
+			COUNT(*) FILTER (WHERE submission_landmarks.status = 'rejected') AS rejected,
+			MAX(submission_landmarks.created_at) AS last_proposed_at`).
+		Joins("JOIN users ON users.id = submission_landmarks.partner_id").
+		Where("submission_landmarks.partner_id IS NOT NULL")
+	if partnerID != nil {
+		query = query.Where("submission_landmarks.partner_id = ?", *partnerID)
+	}
+
+	var contributions []models.PartnerContributions
+	err := query.
+		Group("submission_landmarks.partner_id, users.email").
+		Order("proposed DESC").
+		Scan(&contributions).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count partner contributions")
+	}
+	return contributions, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RequestCreditRepository stores the request packs users bought.
+type RequestCreditRepository interface {
+	// Create records a purchased pack. It reports false, without an error,
+	// when the checkout session was credited already.
+	Create(ctx context.Context, credit *models.RequestCredit) (bool, error)
+	// Balance is how many purchased requests the user has left.
+	Balance(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Consume uses up a credit of the user's oldest pack with some left.
+	// It reports false when they have none.
+	Consume(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type requestCreditRepository struct {
+	db *gorm.DB
+}
+
+func NewRequestCreditRepository(db *gorm.DB) RequestCreditRepository {
+	return &requestCreditRepository{db: db}
+}
+
+func (r *requestCreditRepository) Create(ctx context.Context, credit *models.RequestCredit) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stripe_session_id"}},
+		DoNothing: true,
+	}).Create(credit)
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to record request credits")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *requestCreditRepository) Balance(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var balance int64
+	err := r.db.WithContext(ctx).Model(&models.RequestCredit{}).
+		Select("COALESCE(SUM(remaining), 0)").
+		Where("user_id = ?", userID).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get request credit balance")
+	}
+	return balance, nil
+}
+
+func (r *requestCreditRepository) Consume(ctx context.Context, userID uuid.UUID) (bool, error) {
+	// Concurrent requests wait for one another on the pack, and the last
+	// credit is only taken once
+	oldest := r.db.Model(&models.RequestCredit{}).
+		Select("id").
+		Where("user_id = ? AND remaining > 0", userID).
+		Order("created_at").
+		Limit(1).
+		Clauses(clause.Locking{Strength: "UPDATE"})
+	result := r.db.WithContext(ctx).Model(&models.RequestCredit{}).
+		Where("id = (?) AND remaining > 0", oldest).
+		Update("remaining", gorm.Expr("remaining - 1"))
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to consume request credit")
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrRequestLogExportNotFound = errors.New("request log export not found")
+
+type RequestLogExportRepository interface {
+	Create(ctx context.Context, export *models.RequestLogExport) error
+	GetByID(ctx context.Context, id, userID uuid.UUID) (*models.RequestLogExport, error)
+	// ClaimPending marks the oldest pending export as running and returns it,
+	// or nil when none is pending.
+	ClaimPending(ctx context.Context) (*models.RequestLogExport, error)
+	Complete(ctx context.Context, id uuid.UUID, content []byte, rowCount int64) error
+	Fail(ctx context.Context, id uuid.UUID, reason string) error
+	// DeleteFinishedBefore deletes completed and failed exports created
+	// before cutoff. Pending and running exports are kept.
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type requestLogExportRepository struct {
+	db *gorm.DB
+}
+
+func NewRequestLogExportRepository(db *gorm.DB) RequestLogExportRepository {
+	return &requestLogExportRepository{db: db}
+}
+
+func (r *requestLogExportRepository) Create(ctx context.Context, export *models.RequestLogExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *requestLogExportRepository) GetByID(ctx context.Context, id, userID uuid.UUID) (*models.RequestLogExport, error) {
+	var export models.RequestLogExport
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRequestLogExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *requestLogExportRepository) ClaimPending(ctx context.Context) (*models.RequestLogExport, error) {
+	for {
+		var export models.RequestLogExport
+		err := r.db.WithContext(ctx).
+			Omit("content").
+			Where("status = ?", models.ExportPending).
+			Order("created_at ASC").
+			First(&export).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Another worker may have claimed it in the meantime
+		result := r.db.WithContext(ctx).Model(&models.RequestLogExport{}).
+			Where("id = ? AND status = ?", export.ID, models.ExportPending).
+			Updates(map[string]interface{}{
+				"status":     models.ExportRunning,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			export.Status = models.ExportRunning
+			return &export, nil
+		}
+	}
+}
+
+func (r *requestLogExportRepository) Complete(ctx context.Context, id uuid.UUID, content []byte, rowCount int64) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.RequestLogExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportCompleted,
+			"content":      content,
+			"row_count":    rowCount,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *requestLogExportRepository) Fail(ctx context.Context, id uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.RequestLogExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.ExportFailed,
+			"error":        reason,
+			"completed_at": now,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *requestLogExportRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND created_at < ?", []models.ExportStatus{models.ExportCompleted, models.ExportFailed}, cutoff).
+		Delete(&models.RequestLogExport{})
+	return result.RowsAffected, result.Error
+}
@@ -1,17 +1,43 @@
 package repository
 
 import (
+	"context"
 	"landmark-api/internal/models"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// requestLogBatchSize is how many rows EachUserLog loads at a time.
+const requestLogBatchSize = 1000
+
 type RequestLogRepository interface {
 	Create(log *models.RequestLog) error
 	GetUserLogs(userID string, from, to time.Time) ([]models.RequestLog, error)
+	// EachUserLog calls fn for each of the user's logs in the range, oldest
+	// first, loading them in batches.
+	EachUserLog(ctx context.Context, userID string, from, to time.Time, fn func(models.RequestLog) error) error
 	GetEndpointLogs(endpoint string, from, to time.Time) ([]models.RequestLog, error)
-	DeleteOldLogs() error
+	CountByEndpoints(endpoints []string, since time.Time) (map[string]int64, error)
+	Summarize(ctx context.Context, from, to time.Time) (*models.RequestLogSummary, error)
+	// Volume counts requests in consecutive buckets of the given size.
+	Volume(ctx context.Context, from, to time.Time, bucket time.Duration) ([]models.RequestVolume, error)
+	TopEndpoints(ctx context.Context, from, to time.Time, limit int) ([]models.EndpointUsage, error)
+	// UserTopEndpoints is TopEndpoints for the requests of one user.
+	UserTopEndpoints(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.EndpointUsage, error)
+	TopConsumers(ctx context.Context, from, to time.Time, limit int) ([]models.ConsumerUsage, error)
+	// PopularEndpoints returns up to limit paths, with their query strings
+	// when logged, of the successful GET requests to routes in the range,
+	// most requested first.
+	PopularEndpoints(ctx context.Context, routes []string, from, to time.Time, limit int) ([]string, error)
+	// ListBefore returns up to limit logs, including soft-deleted ones, from
+	// before cutoff in ID order.
+	ListBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.RequestLog, error)
+	// DeleteBefore permanently deletes logs from before cutoff. When throughID
+	// is non-zero only logs up to that ID are deleted, so rows written to an
+	// archive are exactly the rows removed.
+	DeleteBefore(ctx context.Context, cutoff time.Time, throughID uint) (int64, error)
 }
 
 type requestLogRepository struct {
@@ -34,6 +60,21 @@ func (r *requestLogRepository) GetUserLogs(userID string, from, to time.Time) ([
 	return logs, err
 }
 
+func (r *requestLogRepository) EachUserLog(ctx context.Context, userID string, from, to time.Time, fn func(models.RequestLog) error) error {
+	var batch []models.RequestLog
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND timestamp BETWEEN ? AND ?", userID, from, to).
+		Order("id ASC").
+		FindInBatches(&batch, requestLogBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, log := range batch {
+				if err := fn(log); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
 func (r *requestLogRepository) GetEndpointLogs(endpoint string, from, to time.Time) ([]models.RequestLog, error) {
 	var logs []models.RequestLog
 	err := r.db.Where("endpoint = ? AND timestamp BETWEEN ? AND ?", endpoint, from, to).
@@ -42,8 +83,137 @@ func (r *requestLogRepository) GetEndpointLogs(endpoint string, from, to time.Ti
 	return logs, err
 }
 
-func (r *requestLogRepository) DeleteOldLogs() error {
-	// Calculate the timestamp for 12 hours ago
-	twelveHoursAgo := time.Now().Add(-12 * time.Hour)
-	return r.db.Where("timestamp < ?", twelveHoursAgo).Delete(&models.RequestLog{}).Error
+// CountByEndpoints returns request counts since the given time, keyed by the
+// lowercased endpoint. Endpoints are matched case-insensitively.
+func (r *requestLogRepository) CountByEndpoints(endpoints []string, since time.Time) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(endpoints) == 0 {
+		return counts, nil
+	}
+
+	lowered := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		lowered[i] = strings.ToLower(endpoint)
+	}
+
+	var rows []struct {
+		Endpoint string
+		Count    int64
+	}
+	err := r.db.Model(&models.RequestLog{}).
+		Select("LOWER(endpoint) AS endpoint, COUNT(*) AS count").
+		Where("LOWER(endpoint) IN ? AND timestamp >= ?", lowered, since).
+		Group("LOWER(endpoint)").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.Endpoint] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *requestLogRepository) PopularEndpoints(ctx context.Context, routes []string, from, to time.Time, limit int) ([]string, error) {
+	var endpoints []string
+	err := r.db.WithContext(ctx).Model(&models.RequestLog{}).
+		Where("method = ? AND status_code = ? AND route IN ?", "GET", 200, routes).
+		Where("timestamp >= ? AND timestamp < ?", from, to).
+		Group("endpoint").
+		Order("COUNT(*) DESC").
+		Limit(limit).
+		Pluck("endpoint", &endpoints).Error
+	return endpoints, err
+}
+
+func (r *requestLogRepository) ListBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.RequestLog, error) {
+	var logs []models.RequestLog
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("timestamp < ?", cutoff).
+		Order("id ASC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *requestLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time, throughID uint) (int64, error) {
+	query := r.db.WithContext(ctx).Unscoped().Where("timestamp < ?", cutoff)
+	if throughID != 0 {
+		query = query.Where("id <= ?", throughID)
+	}
+	result := query.Delete(&models.RequestLog{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *requestLogRepository) Summarize(ctx context.Context, from, to time.Time) (*models.RequestLogSummary, error) {
+	var summary models.RequestLogSummary
+	err := r.db.WithContext(ctx).Model(&models.RequestLog{}).
+		Select(`COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE status_code >= 400) AS errors,
+			COUNT(*) FILTER (WHERE status_code >= 500) AS server_errors,
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY duration_ms), 0) AS p95_latency_ms,
+			COUNT(*) FILTER (WHERE cache_status = 'HIT') AS cache_hits,
+			COUNT(*) FILTER (WHERE cache_status <> '') AS cache_lookups`).
+		Where("timestamp >= ? AND timestamp < ?", from, to).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *requestLogRepository) Volume(ctx context.Context, from, to time.Time, bucket time.Duration) ([]models.RequestVolume, error) {
+	seconds := int64(bucket.Seconds())
+	var volume []models.RequestVolume
+	err := r.db.WithContext(ctx).Model(&models.RequestLog{}).
+		Select(`TO_TIMESTAMP(FLOOR(EXTRACT(EPOCH FROM timestamp) / ?) * ?) AS start,
+			COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE status_code >= 400) AS errors`, seconds, seconds).
+		Where("timestamp >= ? AND timestamp < ?", from, to).
+		Group("start").
+		Order("start ASC").
+		Scan(&volume).Error
+	return volume, err
+}
+
+func (r *requestLogRepository) TopEndpoints(ctx context.Context, from, to time.Time, limit int) ([]models.EndpointUsage, error) {
+	return r.topEndpoints(r.db.WithContext(ctx), from, to, limit)
+}
+
+func (r *requestLogRepository) UserTopEndpoints(ctx context.Context, userID string, from, to time.Time, limit int) ([]models.EndpointUsage, error) {
+	return r.topEndpoints(r.db.WithContext(ctx).Where("user_id = ?", userID), from, to, limit)
+}
+
+func (r *requestLogRepository) topEndpoints(db *gorm.DB, from, to time.Time, limit int) ([]models.EndpointUsage, error) {
+	var usage []models.EndpointUsage
+	// Logs written before routes were recorded fall back to their path
+	err := db.Model(&models.RequestLog{}).
+		Select(`method,
+			COALESCE(NULLIF(route, ''), endpoint) AS route,
+			COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE status_code >= 400) AS errors,
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY duration_ms) AS p95_latency_ms`).
+		Where("timestamp >= ? AND timestamp < ?", from, to).
+		Group("method, COALESCE(NULLIF(route, ''), endpoint)").
+		Order("requests DESC").
+		Limit(limit).
+		Scan(&usage).Error
+	return usage, err
+}
+
+func (r *requestLogRepository) TopConsumers(ctx context.Context, from, to time.Time, limit int) ([]models.ConsumerUsage, error) {
+	var usage []models.ConsumerUsage
+	err := r.db.WithContext(ctx).Model(&models.RequestLog{}).
+		Select(`request_logs.user_id,
+			COALESCE(users.email, '') AS email,
+			COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE request_logs.status_code >= 400) AS errors`).
+		Joins("LEFT JOIN users ON users.id::text = request_logs.user_id").
+		Where("request_logs.timestamp >= ? AND request_logs.timestamp < ?", from, to).
+		Group("request_logs.user_id, users.email").
+		Order("requests DESC").
+		Limit(limit).
+		Scan(&usage).Error
+	return usage, err
 }
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SearchAnalyticsRepository interface {
+	RecordQuery(ctx context.Context, searchType, term string) error
+	TermCounts(ctx context.Context, searchType string, terms []string, since time.Time) (map[string]int64, error)
+	// DeleteBefore deletes the counts for days before cutoff.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type searchAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchAnalyticsRepository(db *gorm.DB) SearchAnalyticsRepository {
+	return &searchAnalyticsRepository{db: db}
+}
+
+// RecordQuery increments today's counter for a search type and term.
+func (r *searchAnalyticsRepository) RecordQuery(ctx context.Context, searchType, term string) error {
+	now := time.Now()
+	year, month, day := now.Date()
+
+	entry := models.SearchAnalytics{
+		SearchType: searchType,
+		Term:       term,
+		Day:        time.Date(year, month, day, 0, 0, 0, 0, now.Location()),
+		Count:      1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "search_type"}, {Name: "term"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("search_analytics.count + 1"),
+			"updated_at": now,
+		}),
+	}).Create(&entry).Error
+}
+
+// TermCounts sums the searches for each term since the given day. Terms are
+// stored lowercased, so callers should pass lowercased terms.
+func (r *searchAnalyticsRepository) TermCounts(ctx context.Context, searchType string, terms []string, since time.Time) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(terms) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Term  string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.SearchAnalytics{}).
+		Select("term, SUM(count) AS count").
+		Where("search_type = ? AND term IN ? AND day >= ?", searchType, terms, since).
+		Group("term").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.Term] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *searchAnalyticsRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("day < ?", cutoff).Delete(&models.SearchAnalytics{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error)
+	// ListActive returns the user's unrevoked, unexpired sessions, newest
+	// first.
+	ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	// Revoke marks one of the user's sessions revoked and returns it.
+	Revoke(ctx context.Context, id, userID uuid.UUID) (*models.Session, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("issued_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id, userID uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if session.RevokedAt == nil {
+		now := time.Now()
+		if err := r.db.WithContext(ctx).Model(&session).Update("revoked_at", now).Error; err != nil {
+			return nil, err
+		}
+		session.RevokedAt = &now
+	}
+	return &session, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stripeEventClaimTimeout is how long an event may stay in processing
+// before a redelivery takes it over, as the instance processing it may
+// have died.
+const stripeEventClaimTimeout = 10 * time.Minute
+
+// StripeEventRepository records the webhook events received from Stripe.
+type StripeEventRepository interface {
+	// Claim records a delivery of event, received at event.ReceivedAt, and
+	// reports whether it should be processed: true for an event not seen
+	// before, one that failed or one whose processing stalled; false for
+	// a duplicate of one processed or being processed.
+	Claim(ctx context.Context, event *models.StripeEvent) (bool, error)
+	MarkProcessed(ctx context.Context, id string, at time.Time) error
+	MarkFailed(ctx context.Context, id string, reason string) error
+}
+
+type stripeEventRepository struct {
+	db *gorm.DB
+}
+
+func NewStripeEventRepository(db *gorm.DB) StripeEventRepository {
+	return &stripeEventRepository{db: db}
+}
+
+func (r *stripeEventRepository) Claim(ctx context.Context, event *models.StripeEvent) (bool, error) {
+	event.Status = models.StripeEventProcessing
+	event.Attempts = 1
+	event.ClaimedAt = event.ReceivedAt
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to record stripe event")
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// A redelivery is claimed in a single update, so only one of several
+	// arriving at once gets it
+	result = r.db.WithContext(ctx).Model(&models.StripeEvent{}).
+		Where("id = ? AND (status = ? OR (status = ? AND claimed_at < ?))",
+			event.ID, models.StripeEventFailed, models.StripeEventProcessing, event.ReceivedAt.Add(-stripeEventClaimTimeout)).
+		Updates(map[string]interface{}{
+			"status":     models.StripeEventProcessing,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"claimed_at": event.ReceivedAt,
+		})
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to claim stripe event")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *stripeEventRepository) MarkProcessed(ctx context.Context, id string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.StripeEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.StripeEventProcessed,
+			"error":        "",
+			"processed_at": at,
+		}).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to mark stripe event processed")
+	}
+	return nil
+}
+
+func (r *stripeEventRepository) MarkFailed(ctx context.Context, id string, reason string) error {
+	err := r.db.WithContext(ctx).Model(&models.StripeEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status": models.StripeEventFailed,
+			"error":  reason,
+		}).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to mark stripe event failed")
+	}
+	return nil
+}
@@ -14,11 +14,21 @@ type SubscriptionRepository interface {
 	Create(ctx context.Context, subscription *models.Subscription) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error)
 	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*models.Subscription, error)
+	GetByStripeSubscriptionID(ctx context.Context, stripeSubscriptionID string) (*models.Subscription, error)
 	Update(ctx context.Context, subscription *models.Subscription) error
 	CancelSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	SetCancelAtPeriodEnd(ctx context.Context, subscriptionID uuid.UUID, cancel bool) error
 	GetSubscriptionHistory(ctx context.Context, userID uuid.UUID) ([]*models.Subscription, error)
+	ListActiveByPlan(ctx context.Context, plan models.SubscriptionPlan) ([]*models.Subscription, error)
+	HasUsedTrial(ctx context.Context, userID uuid.UUID) (bool, error)
+	CreateComp(ctx context.Context, subscription *models.Subscription) error
+	RevokeComp(ctx context.Context, userID uuid.UUID) error
 }
 
+// accessStatuses are the subscription statuses that grant access to the API.
+// Trials get the same access as the plan they are trialing.
+var accessStatuses = []string{models.SubscriptionStatusActive, models.SubscriptionStatusTrialing}
+
 var (
 	ErrSubscriptionNotFound = errors.New("subscription not found")
 	ErrSubscriptionExists   = errors.New("active subscription already exists")
@@ -67,7 +77,22 @@ func (r *subscriptionRepository) GetActiveByUserID(ctx context.Context, userID u
 	var subscription models.Subscription
 
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND status = 'active' AND (end_date IS NULL OR end_date > ?)", userID, time.Now()).
+		Where("user_id = ? AND status IN ? AND (end_date IS NULL OR end_date > ?)", userID, accessStatuses, time.Now()).
+		Order("created_at DESC").
+		First(&subscription).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	return &subscription, err
+}
+
+func (r *subscriptionRepository) GetByStripeSubscriptionID(ctx context.Context, stripeSubscriptionID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+
+	err := r.db.WithContext(ctx).
+		Where("stripe_plan_id = ?", stripeSubscriptionID).
 		Order("created_at DESC").
 		First(&subscription).Error
 
@@ -79,21 +104,23 @@ func (r *subscriptionRepository) GetActiveByUserID(ctx context.Context, userID u
 }
 
 func (r *subscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) error {
-	err := r.db.WithContext(ctx).Model(&models.Subscription{}).
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
 		Where("id = ?", subscription.ID).
 		Updates(map[string]interface{}{
-			"plan_type":  subscription.PlanType,
-			"end_date":   subscription.EndDate,
-			"status":     subscription.Status,
-			"updated_at": time.Now(),
-		}).Error
-
-	if err != nil {
-		return err
+			"plan_type":            subscription.PlanType,
+			"end_date":             subscription.EndDate,
+			"status":               subscription.Status,
+			"cancel_at_period_end": subscription.CancelAtPeriodEnd,
+			"trial_ends_at":        subscription.TrialEndsAt,
+			"updated_at":           time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
 	}
 
 	// Check if no rows were affected
-	if r.db.RowsAffected == 0 {
+	if result.RowsAffected == 0 {
 		return ErrSubscriptionNotFound
 	}
 
@@ -101,19 +128,38 @@ func (r *subscriptionRepository) Update(ctx context.Context, subscription *model
 }
 
 func (r *subscriptionRepository) CancelSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
-	err := r.db.WithContext(ctx).Model(&models.Subscription{}).
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
 		Where("id = ? AND status = 'active'", subscriptionID).
 		Updates(map[string]interface{}{
 			"status":     "cancelled",
 			"end_date":   time.Now(),
 			"updated_at": time.Now(),
-		}).Error
+		})
 
-	if err != nil {
-		return err
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *subscriptionRepository) SetCancelAtPeriodEnd(ctx context.Context, subscriptionID uuid.UUID, cancel bool) error {
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("id = ? AND status IN ?", subscriptionID, accessStatuses).
+		Updates(map[string]interface{}{
+			"cancel_at_period_end": cancel,
+			"updated_at":           time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
 	}
 
-	if r.db.RowsAffected == 0 {
+	if result.RowsAffected == 0 {
 		return ErrSubscriptionNotFound
 	}
 
@@ -130,3 +176,68 @@ func (r *subscriptionRepository) GetSubscriptionHistory(ctx context.Context, use
 
 	return subscriptions, err
 }
+
+func (r *subscriptionRepository) ListActiveByPlan(ctx context.Context, plan models.SubscriptionPlan) ([]*models.Subscription, error) {
+	var subscriptions []*models.Subscription
+
+	err := r.db.WithContext(ctx).
+		Where("plan_type = ? AND status = 'active' AND (end_date IS NULL OR end_date > ?)", plan, time.Now()).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+
+	return subscriptions, err
+}
+
+func (r *subscriptionRepository) HasUsedTrial(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Subscription{}).
+		Where("user_id = ? AND trial_ends_at IS NOT NULL", userID).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// CreateComp stores an admin-granted subscription. It replaces any comp the
+// user already has but leaves their regular subscription untouched, so it
+// applies again once the comp ends.
+func (r *subscriptionRepository) CreateComp(ctx context.Context, subscription *models.Subscription) error {
+	subscription.IsComp = true
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := endActiveComps(tx, subscription.UserID); err != nil {
+			return err
+		}
+		return tx.Create(subscription).Error
+	})
+}
+
+func (r *subscriptionRepository) RevokeComp(ctx context.Context, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&models.Subscription{}).
+		Where("user_id = ? AND is_comp = ? AND status IN ?", userID, true, accessStatuses).
+		Updates(map[string]interface{}{
+			"status":     models.SubscriptionStatusCanceled,
+			"end_date":   time.Now(),
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func endActiveComps(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&models.Subscription{}).
+		Where("user_id = ? AND is_comp = ? AND status IN ?", userID, true, accessStatuses).
+		Updates(map[string]interface{}{
+			"status":     models.SubscriptionStatusCanceled,
+			"end_date":   time.Now(),
+			"updated_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UsageReportRepository interface {
+	Create(ctx context.Context, report *models.UsageReport) error
+	GetBySubscriptionAndDate(ctx context.Context, subscriptionID uuid.UUID, reportDate time.Time) (*models.UsageReport, error)
+	MarkReported(ctx context.Context, id uuid.UUID, stripeUsageRecordID string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+	SumReportedForPeriod(ctx context.Context, subscriptionID uuid.UUID, periodStart, periodEnd time.Time) (int64, error)
+}
+
+type usageReportRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageReportRepository(db *gorm.DB) UsageReportRepository {
+	return &usageReportRepository{db: db}
+}
+
+func (r *usageReportRepository) Create(ctx context.Context, report *models.UsageReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+func (r *usageReportRepository) GetBySubscriptionAndDate(ctx context.Context, subscriptionID uuid.UUID, reportDate time.Time) (*models.UsageReport, error) {
+	var report models.UsageReport
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ? AND report_date = ?", subscriptionID, reportDate).
+		First(&report).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &report, err
+}
+
+func (r *usageReportRepository) MarkReported(ctx context.Context, id uuid.UUID, stripeUsageRecordID string) error {
+	return r.db.WithContext(ctx).Model(&models.UsageReport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":                 models.UsageReportReported,
+			"stripe_usage_record_id": stripeUsageRecordID,
+			"error":                  "",
+			"updated_at":             time.Now(),
+		}).Error
+}
+
+func (r *usageReportRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&models.UsageReport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.UsageReportFailed,
+			"error":      reason,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// SumReportedForPeriod returns the quantity already submitted to Stripe for
+// a billing period, including reports that are still awaiting a retry.
+func (r *usageReportRepository) SumReportedForPeriod(ctx context.Context, subscriptionID uuid.UUID, periodStart, periodEnd time.Time) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.UsageReport{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("subscription_id = ? AND period_start = ? AND period_end = ?", subscriptionID, periodStart, periodEnd).
+		Scan(&total).Error
+	return total, err
+}
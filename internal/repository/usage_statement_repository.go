@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UsageStatementRepository stores the statements of ended billing periods.
+type UsageStatementRepository interface {
+	// ListUnstated returns up to limit usage periods of users that still
+	// exist which ended in [from, to) and have no statement yet, oldest
+	// first.
+	ListUnstated(ctx context.Context, from, to time.Time, limit int) ([]models.APIUsage, error)
+	// Create saves a statement. It reports false, without an error, when
+	// the user already has one for the period.
+	Create(ctx context.Context, statement *models.UsageStatement) (bool, error)
+	// ListByUser returns the user's statements, newest first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.UsageStatement, error)
+	// Get returns one of the user's statements, ErrNotFound if they have
+	// none with that ID.
+	Get(ctx context.Context, id, userID uuid.UUID) (*models.UsageStatement, error)
+}
+
+type usageStatementRepository struct {
+	db *gorm.DB
+}
+
+func NewUsageStatementRepository(db *gorm.DB) UsageStatementRepository {
+	return &usageStatementRepository{db: db}
+}
+
+func (r *usageStatementRepository) ListUnstated(ctx context.Context, from, to time.Time, limit int) ([]models.APIUsage, error) {
+	var usage []models.APIUsage
+	// Usage stores the user ID as text
+	err := r.db.WithContext(ctx).
+		Joins("JOIN users ON users.id::text = api_usages.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN usage_statements ON usage_statements.user_id::text = api_usages.user_id AND usage_statements.period_start = api_usages.period_start").
+		Where("api_usages.period_end >= ? AND api_usages.period_end < ?", from, to).
+		Where("usage_statements.id IS NULL").
+		Order("api_usages.period_end").
+		Limit(limit).
+		Find(&usage).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list usage periods without statements")
+	}
+	return usage, nil
+}
+
+func (r *usageStatementRepository) Create(ctx context.Context, statement *models.UsageStatement) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "period_start"}},
+		DoNothing: true,
+	}).Create(statement)
+	if result.Error != nil {
+		return false, errors.Wrap(result.Error, "failed to save usage statement")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *usageStatementRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.UsageStatement, error) {
+	var statements []models.UsageStatement
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("period_start DESC").
+		Find(&statements).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list usage statements")
+	}
+	return statements, nil
+}
+
+func (r *usageStatementRepository) Get(ctx context.Context, id, userID uuid.UUID) (*models.UsageStatement, error) {
+	var statement models.UsageStatement
+	err := r.db.WithContext(ctx).First(&statement, "id = ? AND user_id = ?", id, userID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get usage statement")
+	}
+	return &statement, nil
+}
@@ -13,11 +13,15 @@ import (
 
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
+	// CreateAccount creates a user together with their API key and
+	// subscription, so a failure leaves none of them behind.
+	CreateAccount(ctx context.Context, user *models.User, apiKey *models.APIKey, subscription *models.Subscription) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	GetByStripeCustomerID(ctx context.Context, id string) (*models.User, error)
 	GrantAccess(ctx context.Context, id uuid.UUID) error
 	RevokeAccess(ctx context.Context, id uuid.UUID) error
+	SetRole(ctx context.Context, id uuid.UUID, role string) error
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -38,6 +42,28 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+func (r *userRepository) CreateAccount(ctx context.Context, user *models.User, apiKey *models.APIKey, subscription *models.Subscription) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("APIKeys").Create(user).Error; err != nil {
+			return errors.Wrap(err, "failed to create user")
+		}
+		apiKey.UserID = user.ID
+		if err := tx.Create(apiKey).Error; err != nil {
+			return errors.Wrap(err, "failed to create API key")
+		}
+		subscription.UserID = user.ID
+		if err := tx.Create(subscription).Error; err != nil {
+			return errors.Wrap(err, "failed to create subscription")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	user.APIKeys = []models.APIKey{*apiKey}
+	return nil
+}
+
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
 	result := r.db.WithContext(ctx).First(&user, "id = ?", id)
@@ -80,11 +106,12 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	result := r.db.WithContext(ctx).Model(user).Updates(map[string]interface{}{
-		"email":         user.Email,
-		"name":          user.Name,
-		"password_hash": user.PasswordHash,
-		"on_boarding":   false,
-		"updated_at":    user.UpdatedAt,
+		"email":              user.Email,
+		"name":               user.Name,
+		"password_hash":      user.PasswordHash,
+		"on_boarding":        false,
+		"onboarding_opt_out": user.OnboardingOptOut,
+		"updated_at":         user.UpdatedAt,
 	})
 
 	if result.Error != nil {
@@ -118,6 +145,25 @@ func (r *userRepository) GrantAccess(ctx context.Context, userID uuid.UUID) erro
 	return nil
 }
 
+func (r *userRepository) SetRole(ctx context.Context, userID uuid.UUID, role string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return fmt.Errorf("failed to set role: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no user found with ID: %s", userID)
+	}
+
+	return nil
+}
+
 func (r *userRepository) RevokeAccess(ctx context.Context, userID uuid.UUID) error {
 	var user models.User
 	result := r.db.WithContext(ctx).Model(&user).
@@ -0,0 +1,117 @@
+package seed
+
+type city struct {
+	Name      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+var cities = []city{
+	{"Paris", "France", 48.8566, 2.3522},
+	{"Lyon", "France", 45.7640, 4.8357},
+	{"Rome", "Italy", 41.9028, 12.4964},
+	{"Florence", "Italy", 43.7696, 11.2558},
+	{"Barcelona", "Spain", 41.3874, 2.1686},
+	{"Madrid", "Spain", 40.4168, -3.7038},
+	{"Berlin", "Germany", 52.5200, 13.4050},
+	{"Munich", "Germany", 48.1351, 11.5820},
+	{"London", "United Kingdom", 51.5072, -0.1276},
+	{"Edinburgh", "United Kingdom", 55.9533, -3.1883},
+	{"Amsterdam", "Netherlands", 52.3676, 4.9041},
+	{"Prague", "Czech Republic", 50.0755, 14.4378},
+	{"Vienna", "Austria", 48.2082, 16.3738},
+	{"Krakow", "Poland", 50.0647, 19.9450},
+	{"Warsaw", "Poland", 52.2297, 21.0122},
+	{"Lisbon", "Portugal", 38.7223, -9.1393},
+	{"Athens", "Greece", 37.9838, 23.7275},
+	{"Istanbul", "Turkey", 41.0082, 28.9784},
+	{"Cairo", "Egypt", 30.0444, 31.2357},
+	{"Kyoto", "Japan", 35.0116, 135.7681},
+	{"Tokyo", "Japan", 35.6762, 139.6503},
+	{"Beijing", "China", 39.9042, 116.4074},
+	{"New York", "United States", 40.7128, -74.0060},
+	{"San Francisco", "United States", 37.7749, -122.4194},
+	{"Mexico City", "Mexico", 19.4326, -99.1332},
+	{"Rio de Janeiro", "Brazil", -22.9068, -43.1729},
+	{"Cusco", "Peru", -13.5319, -71.9675},
+	{"Sydney", "Australia", -33.8688, 151.2093},
+}
+
+type category struct {
+	Name  string
+	Kinds []string
+}
+
+var categories = []category{
+	{"Historical", []string{"Castle", "Fortress", "Palace", "Old Town Gate", "City Walls"}},
+	{"Religious", []string{"Cathedral", "Basilica", "Temple", "Monastery", "Chapel"}},
+	{"Museum", []string{"Art Museum", "History Museum", "Science Museum", "Gallery"}},
+	{"Nature", []string{"Botanical Garden", "Park", "Lookout", "Lake", "Waterfall"}},
+	{"Architecture", []string{"Tower", "Bridge", "Opera House", "Town Hall", "Library"}},
+	{"Monument", []string{"Memorial", "Triumphal Arch", "Column", "Statue"}},
+}
+
+var namePrefixes = []string{
+	"Royal", "Old", "Grand", "St. Mary's", "Imperial", "National", "Great",
+	"Hidden", "Northern", "Southern", "Riverside", "Hilltop", "Golden", "Ancient",
+}
+
+// descriptions are formatted with the city and then the kind of landmark.
+var descriptions = []string{
+	"One of the most visited sites in %[1]s, known for its striking %[2]s.",
+	"A %[2]s in the heart of %[1]s, restored after centuries of neglect.",
+	"Locals and visitors alike come to this %[2]s for views over %[1]s.",
+	"This %[2]s has shaped the skyline of %[1]s for generations.",
+}
+
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+var visitorTips = []string{
+	"Arrive early to avoid the crowds.",
+	"Guided tours run every hour and are worth the extra cost.",
+	"Photography without flash is allowed inside.",
+	"The upper levels close an hour before the main site.",
+}
+
+var accessibility = []string{
+	"Step-free access to the ground floor; lifts to upper levels.",
+	"Partially accessible; some areas are only reachable by stairs.",
+	"Fully wheelchair accessible, with accessible toilets on site.",
+}
+
+// endpoints is the route mix for generated request logs, most popular
+// first. Each route has at most one path parameter, filled from a random
+// landmark.
+var endpoints = []string{
+	"/api/v1/landmarks",
+	"/api/v1/landmarks/{id}",
+	"/api/v1/landmarks/country/{country}",
+	"/api/v1/landmarks/city/{city}",
+	"/api/v1/landmarks/category/{category}",
+	"/api/v1/landmarks/name/{name}",
+}
+
+var currencies = map[string]string{
+	"United Kingdom": "GBP",
+	"Czech Republic": "CZK",
+	"Poland":         "PLN",
+	"Turkey":         "TRY",
+	"Egypt":          "EGP",
+	"Japan":          "JPY",
+	"China":          "CNY",
+	"United States":  "USD",
+	"Mexico":         "MXN",
+	"Brazil":         "BRL",
+	"Peru":           "PEN",
+	"Australia":      "AUD",
+}
+
+// currencyFor returns the ticket price currency for country, defaulting to
+// the euro.
+func currencyFor(country string) string {
+	if currency, ok := currencies[country]; ok {
+		return currency
+	}
+	return "EUR"
+}
@@ -0,0 +1,429 @@
+// Package seed fills a development database with realistic fixture data:
+// landmarks with details and images, a user on each plan with an API key,
+// and recent usage history.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLandmarks = 300
+	// MaxLandmarks keeps generated names unique.
+	MaxLandmarks = 5000
+	// DefaultPassword is the password of every seeded user.
+	DefaultPassword = "landmark-dev"
+
+	requestLogWindow   = 12 * time.Hour
+	searchHistoryDays  = 30
+	usageHistoryDays   = 7
+	imagesPerLandmark  = 3
+	insertBatchSize    = 500
+	searchTermsPerType = 10
+)
+
+var (
+	ErrAlreadySeeded    = errors.New("database already contains seed users")
+	ErrTooManyLandmarks = fmt.Errorf("at most %d landmarks can be seeded", MaxLandmarks)
+)
+
+// Options control what Run generates. The same Seed always produces the
+// same data, including API keys, so integration tests can rely on them.
+type Options struct {
+	Landmarks int
+	Seed      int64
+	Now       time.Time
+}
+
+// User is a seeded account and the credentials to use it.
+type User struct {
+	Email  string
+	Plan   models.SubscriptionPlan
+	Role   string
+	APIKey string
+}
+
+// Result summarizes what Run created.
+type Result struct {
+	Users       []User
+	Landmarks   int
+	Images      int
+	RequestLogs int
+}
+
+type userFixture struct {
+	name     string
+	email    string
+	plan     models.SubscriptionPlan
+	role     string
+	requests int // request logs over the last requestLogWindow
+}
+
+var userFixtures = []userFixture{
+	{"Free User", "free@landmark.test", models.FreePlan, "user", 60},
+	{"Pro User", "pro@landmark.test", models.ProPlan, "user", 400},
+	{"Enterprise User", "enterprise@landmark.test", models.EnterprisePlan, "user", 1200},
+	{"Admin User", "admin@landmark.test", models.EnterprisePlan, "admin", 40},
+}
+
+// Run generates the fixture data in a single transaction. It refuses to run
+// twice against the same database.
+func Run(ctx context.Context, db *gorm.DB, opts Options) (*Result, error) {
+	if opts.Landmarks <= 0 {
+		opts.Landmarks = DefaultLandmarks
+	}
+	if opts.Landmarks > MaxLandmarks {
+		return nil, ErrTooManyLandmarks
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	var existing int64
+	emails := make([]string, len(userFixtures))
+	for i, fixture := range userFixtures {
+		emails[i] = fixture.email
+	}
+	if err := db.WithContext(ctx).Model(&models.User{}).Where("email IN ?", emails).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("error checking for seed users: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrAlreadySeeded
+	}
+
+	g := &generator{rng: rand.New(rand.NewSource(opts.Seed)), now: opts.Now}
+	result := &Result{}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		landmarks, err := g.seedLandmarks(tx, opts.Landmarks, result)
+		if err != nil {
+			return err
+		}
+		if err := g.seedUsers(tx, landmarks, result); err != nil {
+			return err
+		}
+		return g.seedSearchHistory(tx, landmarks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type generator struct {
+	rng *rand.Rand
+	now time.Time
+}
+
+func (g *generator) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+// uuid returns a UUID drawn from the generator, so IDs and keys are
+// reproducible.
+func (g *generator) uuid() uuid.UUID {
+	id, _ := uuid.NewRandomFromReader(g.rng)
+	return id
+}
+
+func (g *generator) seedLandmarks(tx *gorm.DB, count int, result *Result) ([]models.Landmark, error) {
+	seen := make(map[string]bool, count)
+	landmarks := make([]models.Landmark, 0, count)
+	details := make([]models.LandmarkDetail, 0, count)
+	images := make([]models.LandmarkImage, 0, count*imagesPerLandmark)
+
+	for len(landmarks) < count {
+		c := cities[g.rng.Intn(len(cities))]
+		cat := categories[g.rng.Intn(len(categories))]
+		kind := g.pick(cat.Kinds)
+
+		name := fmt.Sprintf("%s %s of %s", g.pick(namePrefixes), kind, c.Name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		createdAt := g.now.Add(-time.Duration(g.rng.Intn(365*24)) * time.Hour)
+		landmark := models.Landmark{
+			ID:          g.uuid(),
+			Name:        name,
+			Description: fmt.Sprintf(g.pick(descriptions), c.Name, strings.ToLower(kind)),
+			// Jitter within roughly 5 km of the city centre.
+			Latitude:       c.Latitude + (g.rng.Float64()-0.5)*0.09,
+			Longitude:      c.Longitude + (g.rng.Float64()-0.5)*0.09,
+			Country:        c.Country,
+			City:           c.Name,
+			Category:       cat.Name,
+			DataConfidence: float64(50+g.rng.Intn(51)) / 100,
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+		}
+
+		for i := 0; i < imagesPerLandmark; i++ {
+			images = append(images, models.LandmarkImage{
+				ID:         g.uuid(),
+				LandmarkID: landmark.ID,
+				ImageURL:   fmt.Sprintf("https://picsum.photos/seed/%s-%d/1200/800", landmark.ID, i),
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+			})
+		}
+		landmark.ImageUrl = images[len(images)-imagesPerLandmark].ImageURL
+
+		details = append(details, g.detail(landmark))
+		landmarks = append(landmarks, landmark)
+	}
+
+	if err := tx.CreateInBatches(landmarks, insertBatchSize).Error; err != nil {
+		return nil, fmt.Errorf("error creating landmarks: %w", err)
+	}
+	if err := tx.CreateInBatches(details, insertBatchSize).Error; err != nil {
+		return nil, fmt.Errorf("error creating landmark details: %w", err)
+	}
+	if err := tx.CreateInBatches(images, insertBatchSize).Error; err != nil {
+		return nil, fmt.Errorf("error creating landmark images: %w", err)
+	}
+
+	for _, cat := range categories {
+		category := models.Category{Name: cat.Name}
+		if err := tx.Where(models.Category{Name: cat.Name}).FirstOrCreate(&category).Error; err != nil {
+			return nil, fmt.Errorf("error creating category %s: %w", cat.Name, err)
+		}
+	}
+
+	result.Landmarks = len(landmarks)
+	result.Images = len(images)
+	return landmarks, nil
+}
+
+func (g *generator) detail(landmark models.Landmark) models.LandmarkDetail {
+	opens := 8 + g.rng.Intn(3)
+	closes := 17 + g.rng.Intn(5)
+	closedDay := g.rng.Intn(len(weekdays) + 2) // sometimes open every day
+
+	hours := make(map[string]string, len(weekdays))
+	for i, day := range weekdays {
+		if i == closedDay {
+			hours[day] = "Closed"
+			continue
+		}
+		hours[day] = fmt.Sprintf("%02d:00-%02d:00", opens, closes)
+	}
+
+	currency := currencyFor(landmark.Country)
+	adult := 5 + g.rng.Intn(26)
+	prices := map[string]string{
+		"adult":   fmt.Sprintf("%d %s", adult, currency),
+		"child":   fmt.Sprintf("%d %s", adult/2, currency),
+		"student": fmt.Sprintf("%d %s", adult*3/4, currency),
+	}
+	if landmark.Category == "Religious" || landmark.Category == "Nature" {
+		prices = map[string]string{"adult": "Free", "child": "Free"}
+	}
+
+	return models.LandmarkDetail{
+		ID:                     g.uuid(),
+		LandmarkID:             landmark.ID,
+		OpeningHours:           hours,
+		TicketPrices:           prices,
+		HistoricalSignificance: fmt.Sprintf("Built in the %dth century, the %s is one of the landmarks that define %s.", 12+g.rng.Intn(8), landmark.Name, landmark.City),
+		VisitorTips:            g.pick(visitorTips),
+		AccessibilityInfo:      g.pick(accessibility),
+		CreatedAt:              landmark.CreatedAt,
+		UpdatedAt:              landmark.CreatedAt,
+	}
+}
+
+func (g *generator) seedUsers(tx *gorm.DB, landmarks []models.Landmark, result *Result) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(DefaultPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	for _, fixture := range userFixtures {
+		user := models.User{
+			ID:              g.uuid(),
+			Name:            fixture.name,
+			Email:           fixture.email,
+			PasswordHash:    string(hash),
+			Role:            fixture.role,
+			HasAccess:       true,
+			AccessGrantedAt: g.now,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("error creating user %s: %w", fixture.email, err)
+		}
+
+		apiKey := models.APIKey{ID: g.uuid(), UserID: user.ID, Key: g.uuid().String()}
+		if err := tx.Create(&apiKey).Error; err != nil {
+			return fmt.Errorf("error creating API key for %s: %w", fixture.email, err)
+		}
+
+		subscription := models.Subscription{
+			ID:        g.uuid(),
+			UserID:    user.ID,
+			PlanType:  fixture.plan,
+			StartDate: g.now.AddDate(0, -3, 0),
+			Status:    models.SubscriptionStatusActive,
+		}
+		if fixture.plan != models.FreePlan {
+			subscription.StripeCustomerID = "cus_seed_" + strings.SplitN(fixture.email, "@", 2)[0]
+			subscription.EndDate = g.now.AddDate(0, 1, 0)
+		}
+		if err := tx.Create(&subscription).Error; err != nil {
+			return fmt.Errorf("error creating subscription for %s: %w", fixture.email, err)
+		}
+
+		logs := g.requestLogs(user.ID, fixture.requests, landmarks)
+		if err := tx.CreateInBatches(logs, insertBatchSize).Error; err != nil {
+			return fmt.Errorf("error creating request logs for %s: %w", fixture.email, err)
+		}
+		result.RequestLogs += len(logs)
+
+		if fixture.plan != models.FreePlan {
+			if err := tx.Create(g.usageReports(subscription, fixture.requests)).Error; err != nil {
+				return fmt.Errorf("error creating usage reports for %s: %w", fixture.email, err)
+			}
+		}
+
+		result.Users = append(result.Users, User{
+			Email:  fixture.email,
+			Plan:   fixture.plan,
+			Role:   fixture.role,
+			APIKey: apiKey.Key,
+		})
+	}
+
+	return nil
+}
+
+// requestLogs spreads count requests over the last requestLogWindow, the
+// period request logs are retained for. Earlier endpoints in the mix are
+// requested more often.
+func (g *generator) requestLogs(userID uuid.UUID, count int, landmarks []models.Landmark) []models.RequestLog {
+	logs := make([]models.RequestLog, 0, count)
+	for i := 0; i < count; i++ {
+		route := endpoints[min(g.rng.Intn(len(endpoints)), g.rng.Intn(len(endpoints)))]
+		landmark := landmarks[g.rng.Intn(len(landmarks))]
+
+		endpoint := strings.NewReplacer(
+			"{id}", landmark.ID.String(),
+			"{country}", strings.ToLower(landmark.Country),
+			"{city}", strings.ToLower(landmark.City),
+			"{category}", strings.ToLower(landmark.Category),
+			"{name}", strings.ToLower(landmark.Name),
+		).Replace(route)
+
+		statusCode, status := 200, models.StatusSuccess
+		switch n := g.rng.Intn(100); {
+		case n < 3:
+			statusCode, status = 500, models.StatusError
+		case n < 8:
+			statusCode, status = 404, models.StatusError
+		}
+
+		cacheStatus := "MISS"
+		if g.rng.Intn(10) < 7 {
+			cacheStatus = "HIT"
+		}
+
+		duration := int64(5 + g.rng.Intn(40))
+		if cacheStatus == "MISS" {
+			duration += int64(50 + g.rng.Intn(250))
+		}
+
+		timestamp := g.now.Add(-time.Duration(g.rng.Int63n(int64(requestLogWindow))))
+		logs = append(logs, models.RequestLog{
+			UserID:      userID.String(),
+			Endpoint:    endpoint,
+			Route:       route,
+			Method:      "GET",
+			Status:      status,
+			StatusCode:  statusCode,
+			Summary:     fmt.Sprintf("GET %s", endpoint),
+			DurationMs:  duration,
+			CacheStatus: cacheStatus,
+			Timestamp:   timestamp,
+			CreatedAt:   timestamp,
+			UpdatedAt:   timestamp,
+		})
+	}
+	return logs
+}
+
+// usageReports records daily usage already reported to Stripe, scaled from
+// the user's recent request rate.
+func (g *generator) usageReports(subscription models.Subscription, requests int) []models.UsageReport {
+	perDay := int64(requests) * int64(24*time.Hour/requestLogWindow)
+	today := time.Date(g.now.Year(), g.now.Month(), g.now.Day(), 0, 0, 0, 0, time.UTC)
+
+	reports := make([]models.UsageReport, 0, usageHistoryDays)
+	for day := 1; day <= usageHistoryDays; day++ {
+		start := today.AddDate(0, 0, -day)
+		quantity := perDay/2 + g.rng.Int63n(perDay)
+		reports = append(reports, models.UsageReport{
+			ID:                       g.uuid(),
+			SubscriptionID:           subscription.ID,
+			UserID:                   subscription.UserID,
+			StripeSubscriptionItemID: "si_seed_" + subscription.ID.String()[:8],
+			ReportDate:               start,
+			PeriodStart:              start,
+			PeriodEnd:                start.AddDate(0, 0, 1),
+			Quantity:                 quantity,
+			IdempotencyKey:           fmt.Sprintf("seed-%s-%s", subscription.ID, start.Format("2006-01-02")),
+			StripeUsageRecordID:      "mbur_seed_" + g.uuid().String()[:8],
+			Status:                   models.UsageReportReported,
+		})
+	}
+	return reports
+}
+
+// seedSearchHistory records daily suggestion searches for a handful of
+// terms per search type, so popularity ranking has something to rank.
+func (g *generator) seedSearchHistory(tx *gorm.DB, landmarks []models.Landmark) error {
+	searchTypes := []string{"country", "city", "name"}
+	terms := make(map[string][]string, len(searchTypes))
+	seen := make(map[string]bool)
+	for _, landmark := range landmarks {
+		for i, term := range []string{landmark.Country, landmark.City, landmark.Name} {
+			searchType := searchTypes[i]
+			term = strings.ToLower(term)
+			if len(terms[searchType]) < searchTermsPerType && !seen[searchType+":"+term] {
+				seen[searchType+":"+term] = true
+				terms[searchType] = append(terms[searchType], term)
+			}
+		}
+	}
+
+	today := time.Date(g.now.Year(), g.now.Month(), g.now.Day(), 0, 0, 0, 0, time.UTC)
+	var rows []models.SearchAnalytics
+	for _, searchType := range searchTypes {
+		for _, term := range terms[searchType] {
+			popularity := 1 + g.rng.Intn(50)
+			for day := 0; day < searchHistoryDays; day++ {
+				rows = append(rows, models.SearchAnalytics{
+					SearchType: searchType,
+					Term:       term,
+					Day:        today.AddDate(0, 0, -day),
+					Count:      int64(g.rng.Intn(popularity) + 1),
+				})
+			}
+		}
+	}
+
+	if err := tx.CreateInBatches(rows, insertBatchSize).Error; err != nil {
+		return fmt.Errorf("error creating search history: %w", err)
+	}
+	return nil
+}
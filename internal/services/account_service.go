@@ -0,0 +1,331 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/customer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// purgeBatchSize is how many deleted accounts one purge run loads at a
+// time.
+const purgeBatchSize = 100
+
+// ErrPaidSubscriptionActive is returned when deleting an account that is
+// still billed, as deleting it would not stop the billing until the purge.
+var ErrPaidSubscriptionActive = apperrors.New(apperrors.CodeConflict, "Cancel your subscription before deleting your account")
+
+// AccountService deletes accounts and exports their data, for GDPR
+// erasure and access requests.
+type AccountService interface {
+	// DeleteAccount checks the user's password and soft deletes their
+	// account, which is purged for good at the returned time.
+	DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (purgeAt time.Time, err error)
+	// CreateExport queues a zip archive of everything stored about the
+	// user, or returns the export already queued for them.
+	CreateExport(ctx context.Context, userID uuid.UUID) (*models.AccountExport, error)
+	GetExport(ctx context.Context, id, userID uuid.UUID) (*models.AccountExport, error)
+	// ProcessPendingExports builds queued exports until none are left.
+	ProcessPendingExports(ctx context.Context) error
+	// PurgeDeleted permanently removes the accounts deleted more than the
+	// purge delay before now, with their Stripe customers and archived
+	// request logs, and returns how many it removed.
+	PurgeDeleted(ctx context.Context, now time.Time) (int, error)
+}
+
+type accountService struct {
+	userRepo          repository.UserRepository
+	subscriptionRepo  repository.SubscriptionRepository
+	accountRepo       repository.AccountRepository
+	requestLogRepo    repository.RequestLogRepository
+	accountExportRepo repository.AccountExportRepository
+	archiver          Archiver
+	// purgeAfter is how long deleted accounts are kept, so a deletion made
+	// by mistake can still be undone by support.
+	purgeAfter time.Duration
+}
+
+// NewAccountService builds the service; archiver may be nil when request
+// logs are not archived.
+func NewAccountService(
+	userRepo repository.UserRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	accountRepo repository.AccountRepository,
+	requestLogRepo repository.RequestLogRepository,
+	accountExportRepo repository.AccountExportRepository,
+	archiver Archiver,
+	purgeAfter time.Duration,
+) AccountService {
+	return &accountService{
+		userRepo:          userRepo,
+		subscriptionRepo:  subscriptionRepo,
+		accountRepo:       accountRepo,
+		requestLogRepo:    requestLogRepo,
+		accountExportRepo: accountExportRepo,
+		archiver:          archiver,
+		purgeAfter:        purgeAfter,
+	}
+}
+
+func (s *accountService) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return time.Time{}, ErrInvalidCredentials
+	}
+
+	subscription, err := s.subscriptionRepo.GetActiveByUserID(ctx, userID)
+	switch {
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+	case err != nil:
+		return time.Time{}, err
+	case isBilled(subscription):
+		return time.Time{}, ErrPaidSubscriptionActive
+	}
+
+	now := time.Now()
+	if err := s.accountRepo.Delete(ctx, userID, now); err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(s.purgeAfter), nil
+}
+
+// isBilled reports whether subscription renews through Stripe.
+func isBilled(subscription *models.Subscription) bool {
+	return subscription.PlanType != models.FreePlan &&
+		!subscription.IsComp &&
+		subscription.StripePlanID != "" &&
+		!subscription.CancelAtPeriodEnd
+}
+
+// exportedCustomFields are a user's custom fields with the landmark they
+// belong to, which the model leaves out of its JSON.
+type exportedCustomFields struct {
+	LandmarkID uuid.UUID   `json:"landmark_id"`
+	Fields     models.JSON `json:"fields"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+func (s *accountService) CreateExport(ctx context.Context, userID uuid.UUID) (*models.AccountExport, error) {
+	export, err := s.accountExportRepo.GetUnfinished(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding account export: %w", err)
+	}
+	if export != nil {
+		return export, nil
+	}
+
+	export = &models.AccountExport{
+		UserID: userID,
+		Status: models.ExportPending,
+	}
+	if err := s.accountExportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("error creating account export: %w", err)
+	}
+	return export, nil
+}
+
+func (s *accountService) GetExport(ctx context.Context, id, userID uuid.UUID) (*models.AccountExport, error) {
+	return s.accountExportRepo.GetByID(ctx, id, userID)
+}
+
+func (s *accountService) ProcessPendingExports(ctx context.Context) error {
+	for {
+		export, err := s.accountExportRepo.ClaimPending(ctx)
+		if err != nil {
+			return fmt.Errorf("error claiming account export: %w", err)
+		}
+		if export == nil {
+			return nil
+		}
+
+		var buf bytes.Buffer
+		if err := s.writeExport(ctx, export.UserID, &buf); err != nil {
+			if failErr := s.accountExportRepo.Fail(ctx, export.ID, err.Error()); failErr != nil {
+				return fmt.Errorf("error marking export %s failed: %w", export.ID, failErr)
+			}
+			continue
+		}
+
+		if err := s.accountExportRepo.Complete(ctx, export.ID, buf.Bytes()); err != nil {
+			return fmt.Errorf("error completing export %s: %w", export.ID, err)
+		}
+	}
+}
+
+// writeExport writes everything stored about the user to w as a zip
+// archive.
+func (s *accountService) writeExport(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	data, err := s.accountRepo.Export(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	customFields := make([]exportedCustomFields, len(data.CustomFields))
+	for i, fields := range data.CustomFields {
+		customFields[i] = exportedCustomFields{
+			LandmarkID: fields.LandmarkID,
+			Fields:     fields.Fields,
+			CreatedAt:  fields.CreatedAt,
+			UpdatedAt:  fields.UpdatedAt,
+		}
+	}
+
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", data.User},
+		{"subscriptions.json", data.Subscriptions},
+		{"api_keys.json", data.APIKeys},
+		{"sessions.json", data.Sessions},
+		{"usage.json", data.Usage},
+		{"usage_reports.json", data.UsageReports},
+		{"custom_fields.json", customFields},
+		{"notifications.json", data.Notifications},
+		{"onboarding_emails.json", data.Onboarding},
+		{"request_credits.json", data.Credits},
+		{"usage_statements.json", data.Statements},
+	}
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return fmt.Errorf("error writing %s: %w", file.name, err)
+		}
+	}
+
+	// Request logs are streamed, one per line, as there can be many
+	f, err := archive.Create("request_logs.ndjson")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	err = s.requestLogRepo.EachUserLog(ctx, userID.String(), time.Time{}, time.Now(), func(entry models.RequestLog) error {
+		return encoder.Encode(entry)
+	})
+	if err != nil {
+		return fmt.Errorf("error writing request_logs.ndjson: %w", err)
+	}
+	return archive.Close()
+}
+
+func (s *accountService) PurgeDeleted(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-s.purgeAfter)
+	purged := 0
+	for {
+		users, err := s.accountRepo.ListDeletedBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		// The Stripe customers and archives go first: if either fails the
+		// accounts are kept, so the next run can try again
+		var erase []models.User
+		for _, user := range users {
+			if err := deleteStripeCustomer(user.StripeID); err != nil {
+				log.Printf("Error deleting Stripe customer of user %s: %v", user.ID, err)
+				continue
+			}
+			erase = append(erase, user)
+		}
+		if err := s.eraseArchivedLogs(ctx, erase); err != nil {
+			return purged, fmt.Errorf("error erasing archived request logs: %w", err)
+		}
+
+		for _, user := range erase {
+			if err := s.accountRepo.Purge(ctx, user.ID); err != nil {
+				return purged, fmt.Errorf("error purging user %s: %w", user.ID, err)
+			}
+			purged++
+		}
+		if len(users) < purgeBatchSize || len(erase) == 0 {
+			return purged, nil
+		}
+	}
+}
+
+// eraseArchivedLogs rewrites the request log archives without the logs of
+// users, deleting archives left empty. Archives mix the logs of every user,
+// so all of them are read.
+func (s *accountService) eraseArchivedLogs(ctx context.Context, users []models.User) error {
+	if s.archiver == nil || len(users) == 0 {
+		return nil
+	}
+	erase := make(map[string]bool, len(users))
+	for _, user := range users {
+		erase[user.ID.String()] = true
+	}
+
+	keys, err := s.archiver.List(ctx, requestLogArchivePrefix)
+	if err != nil {
+		return fmt.Errorf("error listing archives: %w", err)
+	}
+	for _, key := range keys {
+		body, err := s.archiver.Fetch(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error fetching %s: %w", key, err)
+		}
+		logs, err := decodeNDJSONGzip(body)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", key, err)
+		}
+
+		kept := logs[:0]
+		for _, entry := range logs {
+			if !erase[entry.UserID] {
+				kept = append(kept, entry)
+			}
+		}
+		switch {
+		case len(kept) == len(logs):
+			continue
+		case len(kept) == 0:
+			err = s.archiver.Delete(ctx, key)
+		default:
+			body, err = encodeNDJSONGzip(kept)
+			if err == nil {
+				err = s.archiver.Archive(ctx, key, body)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("error rewriting %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// deleteStripeCustomer deletes a customer from Stripe, which also cancels
+// their subscriptions. Customers that are already gone are fine.
+func deleteStripeCustomer(id string) error {
+	if id == "" {
+		return nil
+	}
+	_, err := customer.Del(id, nil)
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodeResourceMissing {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"net/http"
+	"strings"
+)
+
+// ImageDescription is what a generator made of an image. Either field may
+// be empty when it had nothing to say.
+type ImageDescription struct {
+	AltText string `json:"alt_text"`
+	Caption string `json:"caption"`
+}
+
+// AltTextGenerator describes landmark photos for screen readers.
+type AltTextGenerator interface {
+	Describe(ctx context.Context, image models.LandmarkImage, landmark models.Landmark) (*ImageDescription, error)
+}
+
+// NewAltTextGenerator returns the generator cfg selects, or nil when
+// generation is disabled. The labels generator needs tagger, and keeps the
+// labels with taggingCfg's MinConfidence.
+func NewAltTextGenerator(cfg *config.AltTextConfig, tagger ImageTagger, taggingCfg *config.ImageTaggingConfig, client *http.Client) (AltTextGenerator, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "http":
+		return &httpAltTextGenerator{client: client, modelURL: cfg.ModelURL}, nil
+	case "labels":
+		if tagger == nil {
+			return nil, fmt.Errorf("alt text from labels needs an image tagging provider")
+		}
+		return &labelAltTextGenerator{tagger: tagger, minConfidence: taggingCfg.MinConfidence, maxLabels: cfg.MaxLabels}, nil
+	default:
+		return nil, fmt.Errorf("unknown alt text provider %q", cfg.Provider)
+	}
+}
+
+// httpAltTextGenerator describes images with a self-hosted captioning
+// model, which is sent the image URL with the landmark's name and place
+// and answers {"alt_text": ..., "caption": ...}.
+type httpAltTextGenerator struct {
+	client   *http.Client
+	modelURL string
+}
+
+func (g *httpAltTextGenerator) Describe(ctx context.Context, image models.LandmarkImage, landmark models.Landmark) (*ImageDescription, error) {
+	body, err := json.Marshal(map[string]string{
+		"image_url": image.ImageURL,
+		"type":      string(image.Type()),
+		"landmark":  landmark.Name,
+		"city":      landmark.City,
+		"country":   landmark.Country,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.modelURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("model returned status %d", resp.StatusCode)
+	}
+	var description ImageDescription
+	if err := json.NewDecoder(resp.Body).Decode(&description); err != nil {
+		return nil, err
+	}
+	description.AltText = strings.TrimSpace(description.AltText)
+	description.Caption = strings.TrimSpace(description.Caption)
+	return &description, nil
+}
+
+// labelAltTextGenerator builds alt text like "Photo of Wawel Castle in
+// Kraków, Poland, showing castle, tower and sky" from the image's most
+// confident labels. Labels make poor captions, so it leaves them empty.
+type labelAltTextGenerator struct {
+	tagger        ImageTagger
+	minConfidence float64
+	maxLabels     int
+}
+
+func (g *labelAltTextGenerator) Describe(ctx context.Context, image models.LandmarkImage, landmark models.Landmark) (*ImageDescription, error) {
+	labels, err := g.tagger.Labels(ctx, image.ImageURL, g.minConfidence)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := "Photo"
+	if image.Type() == models.MediaPano {
+		kind = "360° panorama"
+	}
+	altText := kind + " of " + landmark.Name
+	var place []string
+	for _, part := range []string{landmark.City, landmark.Country} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	if len(place) > 0 {
+		altText += " in " + strings.Join(place, ", ")
+	}
+
+	var shown []string
+	for _, label := range labels {
+		if len(shown) == g.maxLabels {
+			break
+		}
+		shown = append(shown, strings.ToLower(label.Name))
+	}
+	switch len(shown) {
+	case 0:
+	case 1:
+		altText += ", showing " + shown[0]
+	default:
+		altText += ", showing " + strings.Join(shown[:len(shown)-1], ", ") + " and " + shown[len(shown)-1]
+	}
+	return &ImageDescription{AltText: altText}, nil
+}
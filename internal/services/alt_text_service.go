@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AltTextService fills in the alt text, and captions, that landmark photos
+// lack, so clients can meet accessibility requirements without writing
+// them all.
+type AltTextService interface {
+	// GenerateDue describes a batch of photos without alt text and returns
+	// how many got it.
+	GenerateDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type altTextService struct {
+	generator AltTextGenerator
+	repo      repository.AltTextRepository
+	cache     CacheService
+	config    *config.AltTextConfig
+}
+
+func NewAltTextService(generator AltTextGenerator, repo repository.AltTextRepository, cache CacheService, cfg *config.AltTextConfig) AltTextService {
+	return &altTextService{
+		generator: generator,
+		repo:      repo,
+		cache:     cache,
+		config:    cfg,
+	}
+}
+
+func (s *altTextService) GenerateDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDue(ctx, now.Add(-s.config.RetryAfter), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	described := 0
+	changed := make(map[uuid.UUID]bool)
+	for _, item := range due {
+		description, err := s.generator.Describe(ctx, item.Image, item.Landmark)
+		if err != nil {
+			// Recorded as tried, so it is retried after RetryAfter
+			log.Printf("Error generating alt text of image %s: %v", item.Image.ID, err)
+			description = &ImageDescription{}
+		}
+		if err := s.repo.Save(ctx, item.Image, description.AltText, description.Caption, now); err != nil {
+			return described, err
+		}
+		if description.AltText != "" {
+			described++
+		}
+		if description.AltText != "" || description.Caption != "" {
+			changed[item.Image.LandmarkID] = true
+		}
+	}
+
+	for id := range changed {
+		s.forgetLandmark(ctx, id)
+	}
+	return described, nil
+}
+
+// forgetLandmark drops the cached responses of a landmark for every plan,
+// so its images are served with their new alt text.
+func (s *altTextService) forgetLandmark(ctx context.Context, id uuid.UUID) {
+	if err := s.cache.DeleteByPattern(ctx, "landmark:id:"+id.String()+":*"); err != nil {
+		log.Printf("Failed to delete cache entries: %v", err)
+	}
+}
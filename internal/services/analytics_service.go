@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"time"
+)
+
+const analyticsTopLimit = 10
+
+var ErrInvalidAnalyticsWindow = errors.New("window must be one of 15m, 1h, 6h or 12h")
+
+// analyticsWindows maps the selectable windows to their length and volume
+// bucket size. Windows longer than the request log retention only cover the
+// logs still kept.
+var analyticsWindows = map[string]struct {
+	length time.Duration
+	bucket time.Duration
+}{
+	"15m": {15 * time.Minute, time.Minute},
+	"1h":  {time.Hour, 5 * time.Minute},
+	"6h":  {6 * time.Hour, 15 * time.Minute},
+	"12h": {12 * time.Hour, 30 * time.Minute},
+}
+
+// AnalyticsService aggregates request logs into API-wide analytics for admins.
+type AnalyticsService interface {
+	GetAPIAnalytics(ctx context.Context, window string, now time.Time) (*models.APIAnalytics, error)
+}
+
+type analyticsService struct {
+	requestLogRepo repository.RequestLogRepository
+}
+
+func NewAnalyticsService(requestLogRepo repository.RequestLogRepository) AnalyticsService {
+	return &analyticsService{requestLogRepo: requestLogRepo}
+}
+
+func (s *analyticsService) GetAPIAnalytics(ctx context.Context, window string, now time.Time) (*models.APIAnalytics, error) {
+	spec, ok := analyticsWindows[window]
+	if !ok {
+		return nil, ErrInvalidAnalyticsWindow
+	}
+	to := now.UTC()
+	from := to.Add(-spec.length)
+
+	summary, err := s.requestLogRepo.Summarize(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error summarizing request logs: %w", err)
+	}
+	volume, err := s.requestLogRepo.Volume(ctx, from, to, spec.bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error counting request volume: %w", err)
+	}
+	endpoints, err := s.requestLogRepo.TopEndpoints(ctx, from, to, analyticsTopLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing top endpoints: %w", err)
+	}
+	consumers, err := s.requestLogRepo.TopConsumers(ctx, from, to, analyticsTopLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing top consumers: %w", err)
+	}
+
+	analytics := &models.APIAnalytics{
+		Window:       window,
+		From:         from,
+		To:           to,
+		Requests:     summary.Requests,
+		P95LatencyMs: summary.P95LatencyMs,
+		Volume:       volume,
+		TopEndpoints: endpoints,
+		TopConsumers: consumers,
+	}
+	if summary.Requests > 0 {
+		analytics.ErrorRate = float64(summary.Errors) / float64(summary.Requests)
+		analytics.ServerErrorRate = float64(summary.ServerErrors) / float64(summary.Requests)
+	}
+	if summary.CacheLookups > 0 {
+		rate := float64(summary.CacheHits) / float64(summary.CacheLookups)
+		analytics.CacheHitRate = &rate
+	}
+
+	return analytics, nil
+}
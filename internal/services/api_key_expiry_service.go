@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+)
+
+// APIKeyExpiryService reminds owners of expiring API keys to rotate them.
+type APIKeyExpiryService interface {
+	// SendReminders notifies the owner of each key that has entered one of
+	// the reminder windows since it was last reminded.
+	SendReminders(ctx context.Context, now time.Time) error
+}
+
+type apiKeyExpiryService struct {
+	apiKeyRepo    repository.APIKeyRepository
+	userRepo      repository.UserRepository
+	notifications NotificationService
+	config        *config.APIKeyConfig
+}
+
+func NewAPIKeyExpiryService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, notifications NotificationService, cfg *config.APIKeyConfig) APIKeyExpiryService {
+	return &apiKeyExpiryService{
+		apiKeyRepo:    apiKeyRepo,
+		userRepo:      userRepo,
+		notifications: notifications,
+		config:        cfg,
+	}
+}
+
+func (s *apiKeyExpiryService) SendReminders(ctx context.Context, now time.Time) error {
+	// Smallest window first, so a key that is already close to expiry gets
+	// only the most urgent reminder
+	for _, days := range s.config.ExpiryReminderDays {
+		apiKeys, err := s.apiKeyRepo.ListExpiring(ctx, now, now.AddDate(0, 0, days), days)
+		if err != nil {
+			return err
+		}
+
+		for _, apiKey := range apiKeys {
+			user, err := s.userRepo.GetByID(ctx, apiKey.UserID)
+			if err != nil {
+				log.Printf("Error getting owner of API key %s: %v", apiKey.ID, err)
+				continue
+			}
+			err = s.notifications.Notify(ctx, user.ID, NotificationAPIKeyExpiring, map[string]string{
+				"ExpiresAt": NotificationDate(*apiKey.ExpiresAt),
+			})
+			if err != nil {
+				log.Printf("Error sending API key expiry reminder to %s: %v", user.Email, err)
+				continue
+			}
+			if err := s.apiKeyRepo.MarkExpiryReminded(ctx, apiKey.ID, days); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRequestLimit = errors.New("request_limit must be -1 (unlimited) or greater")
+
+// APIKeyLimitService manages negotiated per-key request limits, e.g. for
+// enterprise customers whose quota differs from their plan.
+type APIKeyLimitService interface {
+	GetLimit(ctx context.Context, apiKeyID uuid.UUID) (*models.APIKeyLimit, error)
+	SetLimit(ctx context.Context, apiKeyID uuid.UUID, requestLimit int, reason string) (*models.APIKeyLimit, error)
+	ClearLimit(ctx context.Context, apiKeyID uuid.UUID) error
+}
+
+type apiKeyLimitService struct {
+	apiKeyRepo repository.APIKeyRepository
+	limitRepo  repository.APIKeyLimitRepository
+}
+
+func NewAPIKeyLimitService(apiKeyRepo repository.APIKeyRepository, limitRepo repository.APIKeyLimitRepository) APIKeyLimitService {
+	return &apiKeyLimitService{
+		apiKeyRepo: apiKeyRepo,
+		limitRepo:  limitRepo,
+	}
+}
+
+// GetLimit returns the key's override, or nil when the plan default applies.
+func (s *apiKeyLimitService) GetLimit(ctx context.Context, apiKeyID uuid.UUID) (*models.APIKeyLimit, error) {
+	if _, err := s.apiKeyRepo.GetByID(ctx, apiKeyID); err != nil {
+		return nil, err
+	}
+	return s.limitRepo.GetByAPIKeyID(ctx, apiKeyID)
+}
+
+func (s *apiKeyLimitService) SetLimit(ctx context.Context, apiKeyID uuid.UUID, requestLimit int, reason string) (*models.APIKeyLimit, error) {
+	if requestLimit < models.UnlimitedRequests {
+		return nil, ErrInvalidRequestLimit
+	}
+
+	if _, err := s.apiKeyRepo.GetByID(ctx, apiKeyID); err != nil {
+		return nil, err
+	}
+
+	limit := &models.APIKeyLimit{
+		APIKeyID:     apiKeyID,
+		RequestLimit: requestLimit,
+		Reason:       reason,
+	}
+	if err := s.limitRepo.Upsert(ctx, limit); err != nil {
+		return nil, fmt.Errorf("error saving api key limit: %w", err)
+	}
+
+	return limit, nil
+}
+
+func (s *apiKeyLimitService) ClearLimit(ctx context.Context, apiKeyID uuid.UUID) error {
+	return s.limitRepo.Delete(ctx, apiKeyID)
+}
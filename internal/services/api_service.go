@@ -2,6 +2,10 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	apperrors "landmark-api/internal/errors"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
 	"time"
@@ -14,11 +18,28 @@ type APIKeyService interface {
 	AssignAPIKeyToUser(ctx context.Context, userID uuid.UUID) (*models.APIKey, error)
 	GetAPIKeyByKey(ctx context.Context, key string) (*models.APIKey, error)
 	GetUserAndSubscriptionByAPIKey(ctx context.Context, key string) (*models.User, *models.Subscription, error)
+	// GetUserAndSubscriptionForAPIKey is GetUserAndSubscriptionByAPIKey for
+	// apiKey, already found by GetAPIKeyByKey(key).
+	GetUserAndSubscriptionForAPIKey(ctx context.Context, apiKey *models.APIKey, key string) (*models.User, *models.Subscription, error)
 	GetAPIKeyByUserID(ctx context.Context, userID uuid.UUID) (*models.APIKey, error)
 	UpdateAPIKey(ctx context.Context, userID uuid.UUID, newKey string) error
 	DeleteAPIKey(ctx context.Context, userID uuid.UUID) error
+	// EnableSigning gives the user's key a new signing secret and requires
+	// every request made with it to be signed. Only Enterprise plans can
+	// enable signing.
+	EnableSigning(ctx context.Context, userID uuid.UUID) (secret string, err error)
+	DisableSigning(ctx context.Context, userID uuid.UUID) error
+	// RotateAPIKey issues the user a new key, expiring at expiresAt if set.
+	// The old key keeps working for grace.
+	RotateAPIKey(ctx context.Context, userID uuid.UUID, expiresAt *time.Time, grace time.Duration) (*models.APIKey, error)
 }
 
+var (
+	ErrSigningRequiresEnterprise = errors.New("request signing requires an Enterprise subscription")
+	ErrAPIKeyExpired             = apperrors.New(apperrors.CodeAPIKeyExpired, "API key has expired")
+	ErrInvalidKeyExpiry          = errors.New("expires_at must be in the future")
+)
+
 type apiKeyService struct {
 	apiKeyRepo repository.APIKeyRepository
 	userRepo   repository.UserRepository
@@ -34,18 +55,26 @@ func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repositor
 }
 
 func (s *apiKeyService) GenerateAPIKey() string {
+	return generateAPIKey()
+}
+
+func generateAPIKey() string {
 	return uuid.NewString()
 }
 
-func (s *apiKeyService) AssignAPIKeyToUser(ctx context.Context, userID uuid.UUID) (*models.APIKey, error) {
-	apiKey := &models.APIKey{
+// newAPIKey builds a new key for userID without saving it.
+func newAPIKey(userID uuid.UUID) *models.APIKey {
+	return &models.APIKey{
 		ID:        uuid.New(),
 		UserID:    userID,
-		Key:       s.GenerateAPIKey(),
+		Key:       generateAPIKey(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+}
 
+func (s *apiKeyService) AssignAPIKeyToUser(ctx context.Context, userID uuid.UUID) (*models.APIKey, error) {
+	apiKey := newAPIKey(userID)
 	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
 		return nil, err
 	}
@@ -62,6 +91,14 @@ func (s *apiKeyService) GetUserAndSubscriptionByAPIKey(ctx context.Context, key
 	if err != nil {
 		return nil, nil, err
 	}
+	return s.GetUserAndSubscriptionForAPIKey(ctx, apiKey, key)
+}
+
+func (s *apiKeyService) GetUserAndSubscriptionForAPIKey(ctx context.Context, apiKey *models.APIKey, key string) (*models.User, *models.Subscription, error) {
+	// A previous key matched only if it is still within its grace period
+	if apiKey.Key == key && apiKey.Expired(time.Now()) {
+		return nil, nil, ErrAPIKeyExpired
+	}
 
 	user, err := s.userRepo.GetByID(ctx, apiKey.UserID)
 	if err != nil {
@@ -87,3 +124,36 @@ func (s *apiKeyService) UpdateAPIKey(ctx context.Context, userID uuid.UUID, newK
 func (s *apiKeyService) DeleteAPIKey(ctx context.Context, userID uuid.UUID) error {
 	return s.apiKeyRepo.DeleteByUserID(ctx, userID)
 }
+
+func (s *apiKeyService) EnableSigning(ctx context.Context, userID uuid.UUID) (string, error) {
+	subscription, err := s.subRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if subscription.PlanType != models.EnterprisePlan {
+		return "", ErrSigningRequiresEnterprise
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(raw)
+
+	if err := s.apiKeyRepo.UpdateSigning(ctx, userID, secret, true); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (s *apiKeyService) DisableSigning(ctx context.Context, userID uuid.UUID) error {
+	return s.apiKeyRepo.UpdateSigning(ctx, userID, "", false)
+}
+
+func (s *apiKeyService) RotateAPIKey(ctx context.Context, userID uuid.UUID, expiresAt *time.Time, grace time.Duration) (*models.APIKey, error) {
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidKeyExpiry
+	}
+	return s.apiKeyRepo.Rotate(ctx, userID, s.GenerateAPIKey(), expiresAt, now.Add(grace))
+}
@@ -13,6 +13,12 @@ import (
 type APIUsageService interface {
 	GetCurrentUsage(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan) (*UsageStats, error)
 	IncrementUsage(userID uuid.UUID) error
+	// ConsumeCredit uses up one of the requests the user bought, for a
+	// request past their quota. It reports false when they have none left.
+	ConsumeCredit(ctx context.Context, userID uuid.UUID) (bool, error)
+	// QuotaLimit returns the requests per period the user may make on the
+	// plan, -1 when unlimited.
+	QuotaLimit(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan) (int, error)
 }
 
 type UsageStats struct {
@@ -20,18 +26,25 @@ type UsageStats struct {
 	Limit             int
 	RemainingRequests int
 	PeriodEnd         time.Time
+	// Credits are the requests bought on top of the quota that are left,
+	// used once the quota is.
+	Credits int64
 }
 
 type apiUsageService struct {
 	repo       repository.APIUsageRepository
 	subRepo    repository.SubscriptionRepository
+	limitRepo  repository.APIKeyLimitRepository
+	creditRepo repository.RequestCreditRepository
 	rateConfig *config.RateLimitConfig
 }
 
-func NewAPIUsageService(repo repository.APIUsageRepository, subRepo repository.SubscriptionRepository, rateConfig *config.RateLimitConfig) APIUsageService {
+func NewAPIUsageService(repo repository.APIUsageRepository, subRepo repository.SubscriptionRepository, limitRepo repository.APIKeyLimitRepository, creditRepo repository.RequestCreditRepository, rateConfig *config.RateLimitConfig) APIUsageService {
 	return &apiUsageService{
 		repo:       repo,
 		subRepo:    subRepo,
+		limitRepo:  limitRepo,
+		creditRepo: creditRepo,
 		rateConfig: rateConfig,
 	}
 }
@@ -43,17 +56,7 @@ func (s *apiUsageService) GetCurrentUsage(ctx context.Context, userID uuid.UUID,
 		return nil, err
 	}
 
-	now := time.Now()
-	periodStart := subscription.StartDate
-	periodEnd := subscription.EndDate
-
-	// If the current time is past the billing date, adjust the period
-	if now.After(periodEnd) {
-		for periodEnd.Before(now) {
-			periodStart = periodEnd
-			periodEnd = periodEnd.AddDate(0, 1, 0)
-		}
-	}
+	periodStart, periodEnd := billingPeriod(subscription, time.Now())
 
 	usage, err := s.repo.GetCurrentUsage(userID.String(), periodStart, periodEnd)
 	if err != nil {
@@ -69,16 +72,45 @@ func (s *apiUsageService) GetCurrentUsage(ctx context.Context, userID uuid.UUID,
 		}
 	}
 
-	limit := s.rateConfig.Limits[plan]
+	limit, err := s.QuotaLimit(ctx, userID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlimited plans never need credits
+	var credits int64
+	if limit >= 0 {
+		credits, err = s.creditRepo.Balance(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &UsageStats{
 		CurrentCount:      usage.RequestCount,
 		Limit:             limit,
 		RemainingRequests: limit - usage.RequestCount,
 		PeriodEnd:         periodEnd,
+		Credits:           credits,
 	}, nil
 }
 
 func (s *apiUsageService) IncrementUsage(userID uuid.UUID) error {
 	return s.repo.IncrementUsage(userID)
 }
+
+func (s *apiUsageService) ConsumeCredit(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return s.creditRepo.Consume(ctx, userID)
+}
+
+func (s *apiUsageService) QuotaLimit(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan) (int, error) {
+	// A negotiated per-key limit takes precedence over the plan default
+	override, err := s.limitRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if override != nil {
+		return override.RequestLimit, nil
+	}
+	return s.rateConfig.Limits[plan], nil
+}
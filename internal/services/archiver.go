@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Archiver stores compressed archives of expired data for compliance.
+// Keys are relative to the archiver's own prefix.
+type Archiver interface {
+	Archive(ctx context.Context, key string, body []byte) error
+	// List returns the keys of the archives whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Fetch returns the body of an archive. HTTP clients may undo its gzip
+	// content encoding on the way, so it can come back decompressed.
+	Fetch(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type s3Archiver struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+// NewS3Archiver writes archives to bucket under prefix.
+func NewS3Archiver(region, bucket, prefix string) (Archiver, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Archiver{
+		client: s3.New(sess),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (a *s3Archiver) Archive(ctx context.Context, key string, body []byte) error {
+	_, err := a.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(a.bucket),
+		Key:             aws.String(a.prefix + key),
+		Body:            bytes.NewReader(body),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return err
+}
+
+func (a *s3Archiver) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := a.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(a.bucket),
+		Prefix: aws.String(a.prefix + prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(aws.StringValue(object.Key), a.prefix))
+		}
+		return true
+	})
+	return keys, err
+}
+
+func (a *s3Archiver) Fetch(ctx context.Context, key string) ([]byte, error) {
+	out, err := a.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(a.prefix + key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (a *s3Archiver) Delete(ctx context.Context, key string) error {
+	_, err := a.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(a.prefix + key),
+	})
+	return err
+}
@@ -2,14 +2,29 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
+	"reflect"
 	"time"
 )
 
+// AuditEntry describes one admin mutation. Before, when set, is the entity's
+// state prior to the change and is diffed against the request payload.
+type AuditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Details    string
+	Before     interface{}
+}
+
 type AuditLogService interface {
-	GetAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, int64, error)
-	CreateAuditLog(ctx context.Context, adminID int, action, entityType, entityID, details string) error
+	GetAuditLogs(ctx context.Context, filter repository.AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error)
+	ExportAuditLogs(ctx context.Context, filter repository.AuditLogFilter, fn func(models.AuditLog) error) error
+	// CreateAuditLog records entry on behalf of the user in ctx, with the
+	// client IP and payload captured by WithAuditRequest.
+	CreateAuditLog(ctx context.Context, entry AuditEntry) error
 }
 
 type auditLogService struct {
@@ -22,18 +37,111 @@ func NewAuditLogService(auditLogRepo repository.AuditLogRepository) AuditLogServ
 	}
 }
 
-func (s *auditLogService) GetAuditLogs(ctx context.Context, page, pageSize int) ([]models.AuditLog, int64, error) {
-	return s.auditLogRepo.ListAuditLogs(ctx, page, pageSize)
+func (s *auditLogService) GetAuditLogs(ctx context.Context, filter repository.AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error) {
+	return s.auditLogRepo.ListAuditLogs(ctx, filter, page, pageSize)
+}
+
+func (s *auditLogService) ExportAuditLogs(ctx context.Context, filter repository.AuditLogFilter, fn func(models.AuditLog) error) error {
+	return s.auditLogRepo.EachAuditLog(ctx, filter, fn)
 }
 
-func (s *auditLogService) CreateAuditLog(ctx context.Context, adminID int, action, entityType, entityID, details string) error {
+func (s *auditLogService) CreateAuditLog(ctx context.Context, entry AuditEntry) error {
 	log := &models.AuditLog{
-		AdminID:    adminID,
-		Action:     action,
-		EntityType: entityType,
-		EntityID:   entityID,
-		Details:    details,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Details:    entry.Details,
 		Timestamp:  time.Now(),
 	}
+
+	if user, ok := UserFromContext(ctx); ok {
+		log.AdminID = user.ID
+	}
+
+	if req, ok := ctx.Value(auditRequestKey).(*auditRequest); ok {
+		req.recorded = true
+		log.IPAddress = req.ip
+		log.Changes = payloadChanges(entry.Before, req.payload)
+	}
+
 	return s.auditLogRepo.CreateAuditLog(ctx, log)
 }
+
+const auditRequestKey contextKey = "audit_request"
+
+type auditRequest struct {
+	ip       string
+	payload  []byte
+	recorded bool
+}
+
+// WithAuditRequest attaches the client IP and JSON payload of a mutating
+// request to ctx so audit entries created while handling it include them.
+func WithAuditRequest(ctx context.Context, ip string, payload []byte) context.Context {
+	return context.WithValue(ctx, auditRequestKey, &auditRequest{ip: ip, payload: payload})
+}
+
+// AuditRecorded reports whether an audit entry was created for the request
+// ctx was built by WithAuditRequest for.
+func AuditRecorded(ctx context.Context) bool {
+	req, ok := ctx.Value(auditRequestKey).(*auditRequest)
+	return ok && req.recorded
+}
+
+type fieldChange struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to"`
+}
+
+// payloadChanges diffs each field of a JSON object payload against before.
+// Unchanged fields are left out; it returns nil when nothing changed or the
+// payload is not a JSON object.
+func payloadChanges(before interface{}, payload []byte) json.RawMessage {
+	var after map[string]interface{}
+	if len(payload) == 0 || json.Unmarshal(payload, &after) != nil {
+		return nil
+	}
+
+	previous := map[string]interface{}{}
+	if before != nil {
+		var beforeMap map[string]interface{}
+		if data, err := json.Marshal(before); err == nil && json.Unmarshal(data, &beforeMap) == nil {
+			flattenFields("", beforeMap, previous)
+		}
+	}
+
+	current := map[string]interface{}{}
+	flattenFields("", after, current)
+
+	changes := make(map[string]fieldChange)
+	for field, value := range current {
+		old, existed := previous[field]
+		if existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+		changes[field] = fieldChange{From: old, To: value}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// flattenFields copies nested objects in src into dst with dotted keys.
+func flattenFields(prefix string, src, dst map[string]interface{}) {
+	for key, value := range src {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenFields(key, nested, dst)
+			continue
+		}
+		dst[key] = value
+	}
+}
@@ -3,18 +3,16 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
 	"log"
 	"math/rand"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/stripe/stripe-go/v72"
 	"github.com/stripe/stripe-go/v72/customer"
 	"golang.org/x/crypto/bcrypt"
@@ -25,19 +23,26 @@ type contextKey string
 const (
 	UserContextKey         contextKey = "user"
 	SubscriptionContextKey contextKey = "subscription"
+	APIKeyContextKey       contextKey = "api_key"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidCredentials = apperrors.ErrInvalidCredentials
 	ErrInvalidToken       = errors.New("invalid token")
 )
 
 type AuthService interface {
 	Register(ctx context.Context, email, password, name string) (*models.User, error)
 	RegisterSub(ctx context.Context, email, password, name string) (*models.User, error)
-	RegisterWithEmail(ctx context.Context, email string) (*models.User, error)
-	Login(ctx context.Context, email, password string) (token string, isAdmin bool, err error)
-	UpdateUser(ctx context.Context, userID uuid.UUID, name, password string) error
+	// RegisterWithEmail creates an account with a generated password and
+	// emails it to the user, in locale's language when there is a
+	// translation.
+	RegisterWithEmail(ctx context.Context, email, locale string) (*models.User, error)
+	// Login issues a token for a new session on the given device and IP.
+	Login(ctx context.Context, email, password, device, ip string) (token string, isAdmin bool, err error)
+	// UpdateUser changes the user's name, password and onboarding email
+	// opt-out; empty and nil values are left as they are.
+	UpdateUser(ctx context.Context, userID uuid.UUID, name, password string, onboardingOptOut *bool) error
 	VerifyToken(token string) (*models.User, *models.Subscription, error)
 	VerifyTokenAdmin(token string) (*models.User, *models.Subscription, error)
 	GetAPIKey(ctx context.Context, userID uuid.UUID) (*models.APIKey, error)
@@ -50,20 +55,28 @@ type authService struct {
 	userRepo         repository.UserRepository
 	subscriptionRepo repository.SubscriptionRepository
 	apiKeyService    APIKeyService
-	jwtSecret        string
+	sessions         SessionService
+	// jwtKeys are the keys tokens are verified with; the first signs new
+	// tokens.
+	jwtKeys      []config.JWTKey
+	emailService EmailService
 }
 
 func NewAuthService(
 	userRepo repository.UserRepository,
 	subscriptionRepo repository.SubscriptionRepository,
 	apiKeyService APIKeyService,
-	jwtSecret string,
+	sessions SessionService,
+	jwtKeys []config.JWTKey,
+	emailService EmailService,
 ) AuthService {
 	return &authService{
 		userRepo:         userRepo,
 		subscriptionRepo: subscriptionRepo,
 		apiKeyService:    apiKeyService,
-		jwtSecret:        jwtSecret,
+		sessions:         sessions,
+		jwtKeys:          jwtKeys,
+		emailService:     emailService,
 	}
 }
 
@@ -82,31 +95,26 @@ func (s *authService) Register(ctx context.Context, email, password, name string
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	if err := s.createAccount(ctx, user); err != nil {
 		return nil, err
 	}
+	return user, nil
+}
 
-	_, err = s.apiKeyService.AssignAPIKeyToUser(ctx, user.ID)
-	if err != nil {
-		return user, err
-	}
-
+// createAccount creates user with an API key and an active Free
+// subscription, all or nothing, so no user is left without a key or plan.
+func (s *authService) createAccount(ctx context.Context, user *models.User) error {
+	now := time.Now()
 	subscription := &models.Subscription{
 		ID:        uuid.New(),
 		UserID:    user.ID,
 		PlanType:  models.FreePlan,
-		StartDate: time.Now(),
+		StartDate: now,
 		Status:    "active",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
-		// Consider handling this error appropriately
-		return user, err
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
-
-	return user, nil
+	return s.userRepo.CreateAccount(ctx, user, newAPIKey(user.ID), subscription)
 }
 
 func (s *authService) RegisterSub(ctx context.Context, email, password, name string) (*models.User, error) {
@@ -137,7 +145,7 @@ func (s *authService) RegisterSub(ctx context.Context, email, password, name str
 	return user, nil
 }
 
-func (s *authService) RegisterWithEmail(ctx context.Context, email string) (*models.User, error) {
+func (s *authService) RegisterWithEmail(ctx context.Context, email, locale string) (*models.User, error) {
 	// Generate a random password
 	password := generateRandomPassword(12)
 
@@ -157,33 +165,12 @@ func (s *authService) RegisterWithEmail(ctx context.Context, email string) (*mod
 		UpdatedAt:    time.Now(),
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	if err := s.createAccount(ctx, user); err != nil {
 		return nil, err
 	}
 
-	// Assign API key
-	_, err = s.apiKeyService.AssignAPIKeyToUser(ctx, user.ID)
-	if err != nil {
-		return user, err
-	}
-
-	// Create subscription
-	subscription := &models.Subscription{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		PlanType:  models.FreePlan,
-		StartDate: time.Now(),
-		Status:    "active",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
-		return user, err
-	}
-
-	if err := s.sendPasswordEmail(user.Email, password); err != nil {
-		return user, nil
+	if err := s.emailService.SendWelcome(ctx, user.Email, password, locale); err != nil {
+		log.Printf("Error sending welcome email to user %s: %v", user.ID, err)
 	}
 
 	return user, nil
@@ -205,8 +192,11 @@ func (s *authService) GetUserByStripeCustomerID(ctx context.Context, customerID
 	return user, nil
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, bool, error) {
+func (s *authService) Login(ctx context.Context, email, password, device, ip string) (string, bool, error) {
 	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return "", false, ErrInvalidCredentials
+	}
 	if err != nil {
 		return "", false, err
 	}
@@ -222,15 +212,23 @@ func (s *authService) Login(ctx context.Context, email, password string) (string
 
 	isAdmin := user.Role == "admin"
 
+	session, err := s.sessions.Create(ctx, user.ID, device, ip, time.Now().Add(time.Hour*24))
+	if err != nil {
+		return "", false, err
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":             session.ID.String(),
 		"user_id":         user.ID.String(),
 		"role":            user.Role,
 		"subscription_id": subscription.ID.String(),
 		"plan_type":       string(subscription.PlanType),
-		"exp":             time.Now().Add(time.Hour * 24).Unix(),
+		"exp":             session.ExpiresAt.Unix(),
 	})
+	signingKey := s.jwtKeys[0]
+	token.Header["kid"] = signingKey.ID
 
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
+	tokenString, err := token.SignedString([]byte(signingKey.Secret))
 	if err != nil {
 		return "", false, err
 	}
@@ -254,7 +252,7 @@ func (s *authService) GetCurrentSubscription(ctx context.Context, userID uuid.UU
 	return subscription, nil
 }
 
-func (s *authService) UpdateUser(ctx context.Context, userID uuid.UUID, name, password string) error {
+func (s *authService) UpdateUser(ctx context.Context, userID uuid.UUID, name, password string, onboardingOptOut *bool) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return err
@@ -273,29 +271,63 @@ func (s *authService) UpdateUser(ctx context.Context, userID uuid.UUID, name, pa
 		user.PasswordHash = string(hashedPassword)
 	}
 
+	if onboardingOptOut != nil {
+		user.OnboardingOptOut = *onboardingOptOut
+	}
+
 	return s.userRepo.Update(ctx, user)
 }
 
-func (s *authService) VerifyToken(tokenString string) (*models.User, *models.Subscription, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, nil, ErrInvalidToken
+// parseToken verifies tokenString and returns the ID of the user it was
+// issued to. Tokens are checked against the key named by their kid header;
+// tokens issued before key IDs were introduced have none and are checked
+// against every key. Tokens without a session, or of a revoked one, are
+// rejected.
+func (s *authService) parseToken(tokenString string) (uuid.UUID, error) {
+	unverified, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
 	}
+	kid, hasKID := unverified.Header["kid"].(string)
+
+	for _, key := range s.jwtKeys {
+		if hasKID && key.ID != kid {
+			continue
+		}
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, ErrInvalidToken
+			}
+			return []byte(key.Secret), nil
+		})
+		if err != nil || !token.Valid {
+			continue
+		}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, nil, ErrInvalidToken
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return uuid.Nil, ErrInvalidToken
+		}
+		rawUserID, _ := claims["user_id"].(string)
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			return uuid.Nil, ErrInvalidToken
+		}
+		// A token without a session can't be revoked, so it isn't accepted
+		jti, _ := claims["jti"].(string)
+		sessionID, err := uuid.Parse(jti)
+		if err != nil || s.sessions.IsRevoked(context.Background(), sessionID) {
+			return uuid.Nil, ErrInvalidToken
+		}
+		return userID, nil
 	}
+	return uuid.Nil, ErrInvalidToken
+}
 
-	userID, err := uuid.Parse(claims["user_id"].(string))
+func (s *authService) VerifyToken(tokenString string) (*models.User, *models.Subscription, error) {
+	userID, err := s.parseToken(tokenString)
 	if err != nil {
-		return nil, nil, ErrInvalidToken
+		return nil, nil, err
 	}
 
 	user, err := s.userRepo.GetByID(context.Background(), userID)
@@ -316,25 +348,9 @@ var (
 )
 
 func (s *authService) VerifyTokenAdmin(tokenString string) (*models.User, *models.Subscription, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return nil, nil, ErrInvalidToken
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, nil, ErrInvalidToken
-	}
-
-	userID, err := uuid.Parse(claims["user_id"].(string))
+	userID, err := s.parseToken(tokenString)
 	if err != nil {
-		return nil, nil, ErrInvalidToken
+		return nil, nil, err
 	}
 
 	user, err := s.userRepo.GetByID(context.Background(), userID)
@@ -372,6 +388,18 @@ func SubscriptionFromContext(ctx context.Context) (*models.Subscription, bool) {
 	return subscription, ok
 }
 
+// WithAPIKeyContext adds the API key a request was made with to ctx, so it
+// is looked up once per request.
+func WithAPIKeyContext(ctx context.Context, apiKey *models.APIKey) context.Context {
+	return context.WithValue(ctx, APIKeyContextKey, apiKey)
+}
+
+// APIKeyFromContext returns the API key added by WithAPIKeyContext.
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	apiKey, ok := ctx.Value(APIKeyContextKey).(*models.APIKey)
+	return apiKey, ok
+}
+
 func generateRandomPassword(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()_+"
 	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -381,46 +409,3 @@ func generateRandomPassword(length int) string {
 	}
 	return string(password)
 }
-
-func (s *authService) sendPasswordEmail(email, password string) error {
-	from := mail.NewEmail("Landmark API", "noreply@landmark-api.com")
-	subject := "Welcome to Landmark API Family!"
-	to := mail.NewEmail("", email)
-	log.Println(email)
-
-	htmlContent := fmt.Sprintf(`
-<html>
-<body style="background-image: linear-gradient(to right, #4338ca, #312e81); color: #ffffff; font-family: ui-sans-serif, system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, 'Noto Sans', sans-serif, 'Apple Color Emoji', 'Segoe UI Emoji', 'Segoe UI Symbol', 'Noto Color Emoji';">
-    <div style="max-width: 42rem; margin-left: auto; margin-right: auto; padding: 2rem;">
-        <div style="background-color: #3730a3; padding: 2rem; border-radius: 0.5rem; box-shadow: 0 10px 15px -3px rgba(0, 0, 0, 0.1), 0 4px 6px -2px rgba(0, 0, 0, 0.05);">
-            <h1 style="font-size: 1.875rem; line-height: 2.25rem; font-weight: 700; margin-bottom: 1.5rem;">Welcome to Landmark API!</h1>
-            <p style="margin-bottom: 1rem;">Your account has been created successfully. Here are your login details:</p>
-            <div style="background-color: #312e81; padding: 1rem; border-radius: 0.375rem; margin-bottom: 1.5rem;">
-                <p style="margin-bottom: 0.5rem;"><strong>Email:</strong> %s</p>
-                <p style="margin-bottom: 0;"><strong>Temporary Password:</strong> %s</p>
-            </div>
-            <p style="margin-bottom: 1.5rem;">Please log in and change your password as soon as possible.</p>
-            <a href="https://landmark-api.com/auth?login=true" style="background-color: #2563eb; color: #ffffff; font-weight: 700; padding: 0.75rem 1.5rem; border-radius: 0.5rem; display: inline-block; text-decoration: none; transition-property: background-color, border-color, color, fill, stroke, opacity, box-shadow, transform; transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1); transition-duration: 300ms;">Login Now</a>
-        </div>
-    </div>
-</body>
-</html>
-	`, email, password)
-
-	message := mail.NewSingleEmail(from, subject, to, "", htmlContent)
-	client := sendgrid.NewSendClient(os.Getenv("SENDGRID_API_KEY"))
-	response, err := client.Send(message)
-	if err != nil {
-		log.Println(err)
-	} else {
-		fmt.Println(response.StatusCode)
-		fmt.Println(response.Body)
-		fmt.Println(response.Headers)
-	}
-
-	if response.StatusCode >= 400 {
-		return fmt.Errorf("error sending email: %v", response.Body)
-	}
-
-	return nil
-}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+)
+
+// fakeSessions reports the sessions in revoked as revoked.
+type fakeSessions struct {
+	SessionService
+	revoked map[uuid.UUID]bool
+}
+
+func (s *fakeSessions) IsRevoked(ctx context.Context, id uuid.UUID) bool {
+	return s.revoked[id]
+}
+
+func TestParseToken(t *testing.T) {
+	key := config.JWTKey{ID: "k1", Secret: "secret"}
+	userID, live, revoked := uuid.New(), uuid.New(), uuid.New()
+	s := &authService{
+		sessions: &fakeSessions{revoked: map[uuid.UUID]bool{revoked: true}},
+		jwtKeys:  []config.JWTKey{key},
+	}
+	sign := func(claims jwt.MapClaims) string {
+		claims["user_id"] = userID.String()
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = key.ID
+		signed, err := token.SignedString([]byte(key.Secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		valid  bool
+	}{
+		{"live session", jwt.MapClaims{"jti": live.String()}, true},
+		{"revoked session", jwt.MapClaims{"jti": revoked.String()}, false},
+		{"no session", jwt.MapClaims{}, false},
+		{"malformed session", jwt.MapClaims{"jti": "session"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.parseToken(sign(tt.claims))
+			if tt.valid && (err != nil || got != userID) {
+				t.Errorf("parseToken = %s, %v, want %s", got, err, userID)
+			}
+			if !tt.valid && err != ErrInvalidToken {
+				t.Errorf("parseToken error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
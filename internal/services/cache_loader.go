@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheResult says where a value loaded through a CacheLoader came from,
+// as reported in the X-Cache header.
+type CacheResult string
+
+const (
+	CacheHit   CacheResult = "HIT"
+	CacheMiss  CacheResult = "MISS"
+	CacheStale CacheResult = "STALE"
+	// CacheBypass values were built afresh as the request asked to skip
+	// the cache.
+	CacheBypass CacheResult = "BYPASS"
+)
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass marks ctx to skip cached values: CachedFetch builds them
+// afresh, and caches them for the requests after.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func bypassesCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// CacheLoader reads values through a CacheService, building the missing
+// ones with the caller's function. Concurrent misses of the same key on an
+// instance share one build, so a popular key expiring doesn't send every
+// request after it to the database. Values are kept for staleFor past
+// their TTL and served, marked stale, while one request rebuilds them in
+// the background.
+//
+// Values are read with CachedFetch, which keys them by namespace. Keys read
+// through a loader must only be read through one, as it stores values with
+// the time they go stale.
+type CacheLoader struct {
+	cache    CacheService
+	staleFor time.Duration
+	version  string
+	jitter   float64
+	ttls     map[models.SubscriptionPlan]time.Duration
+	// defaultTTL is the TTL of plans without one of their own.
+	defaultTTL time.Duration
+	group      singleflight.Group
+
+	mu    sync.Mutex
+	stats map[string]*CacheFetchStats
+}
+
+// CacheFetchStats counts how the values of a namespace were served since
+// startup.
+type CacheFetchStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Stale  int64 `json:"stale"`
+	// Bypassed are values built afresh for requests that skipped the cache.
+	Bypassed int64 `json:"bypassed"`
+	// Errors are failed builds, which are not cached.
+	Errors int64 `json:"errors"`
+}
+
+// loadedEntry is a value as a CacheLoader stores it.
+type loadedEntry struct {
+	Value      json.RawMessage `json:"value"`
+	FreshUntil time.Time       `json:"fresh_until"`
+}
+
+func NewCacheLoader(cache CacheService, cfg *config.CacheConfig) *CacheLoader {
+	return &CacheLoader{
+		cache:      cache,
+		staleFor:   cfg.StaleFor,
+		version:    cfg.Version,
+		jitter:     cfg.TTLJitter,
+		ttls:       cfg.PlanTTLs,
+		defaultTTL: cfg.DefaultTTL,
+		stats:      make(map[string]*CacheFetchStats),
+	}
+}
+
+// TTL is how long values specific to plan are cached.
+func (l *CacheLoader) TTL(plan models.SubscriptionPlan) time.Duration {
+	if ttl, ok := l.ttls[plan]; ok {
+		return ttl
+	}
+	return l.defaultTTL
+}
+
+// DefaultTTL is how long values shared by every plan are cached.
+func (l *CacheLoader) DefaultTTL() time.Duration {
+	return l.defaultTTL
+}
+
+// CachedFetch returns the value of namespace and parts from the cache,
+// building and caching it with build on a miss. The TTL is stretched by a
+// random part of the loader's jitter, so values cached together don't all
+// expire together, and the result is counted in the namespace's stats.
+func CachedFetch[T any](ctx context.Context, l *CacheLoader, namespace string, parts []string, ttl time.Duration, build func(ctx context.Context) (T, error)) (T, CacheResult, error) {
+	var value T
+	result, err := l.load(ctx, l.Key(namespace, parts...), l.jittered(ttl), &value, func(ctx context.Context) (interface{}, error) {
+		return build(ctx)
+	})
+	l.record(namespace, result, err)
+	return value, result, err
+}
+
+// Key is the key CachedFetch keeps the value of namespace and parts at:
+// them joined by colons, with the cache version last. Bumping the version
+// after changing what is cached leaves every old value behind, while keys
+// still start with their namespace for pattern deletes and the local
+// cache's prefixes.
+func (l *CacheLoader) Key(namespace string, parts ...string) string {
+	return strings.Join(append([]string{namespace}, parts...), ":") + ":v" + l.version
+}
+
+// Stats returns the stats of every namespace fetched from since startup.
+func (l *CacheLoader) Stats() map[string]CacheFetchStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]CacheFetchStats, len(l.stats))
+	for namespace, s := range l.stats {
+		stats[namespace] = *s
+	}
+	return stats
+}
+
+func (l *CacheLoader) record(namespace string, result CacheResult, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats, ok := l.stats[namespace]
+	if !ok {
+		stats = &CacheFetchStats{}
+		l.stats[namespace] = stats
+	}
+	switch {
+	case err != nil:
+		stats.Errors++
+	case result == CacheHit:
+		stats.Hits++
+	case result == CacheStale:
+		stats.Stale++
+	case result == CacheBypass:
+		stats.Bypassed++
+	default:
+		stats.Misses++
+	}
+}
+
+func (l *CacheLoader) jittered(ttl time.Duration) time.Duration {
+	if spread := int64(float64(ttl) * l.jitter); spread > 0 {
+		return ttl + time.Duration(rand.Int63n(spread+1))
+	}
+	return ttl
+}
+
+// load decodes the value cached at key into dest. On a miss it calls build
+// for the value and caches it for ttl. Build runs apart from the request,
+// so a request that gives up doesn't fail the others waiting on it, and
+// its errors are returned as they are and not cached. Requests that bypass
+// the cache always build the value, refreshing the cached one.
+func (l *CacheLoader) load(ctx context.Context, key string, ttl time.Duration, dest interface{}, build func(ctx context.Context) (interface{}, error)) (CacheResult, error) {
+	result := CacheMiss
+	if bypassesCache(ctx) {
+		result = CacheBypass
+	} else if cached, err := l.cache.Get(ctx, key); err == nil {
+		var entry loadedEntry
+		// Values cached in another format are rebuilt
+		if err := json.Unmarshal([]byte(cached), &entry); err == nil && entry.Value != nil {
+			if err := json.Unmarshal(entry.Value, dest); err == nil {
+				if time.Now().Before(entry.FreshUntil) {
+					return CacheHit, nil
+				}
+				l.group.DoChan(key, l.rebuild(ctx, key, ttl, build))
+				return CacheStale, nil
+			}
+		}
+	}
+
+	value, err, _ := l.group.Do(key, l.rebuild(ctx, key, ttl, build))
+	if err != nil {
+		return result, err
+	}
+	return result, json.Unmarshal(value.(json.RawMessage), dest)
+}
+
+// rebuild returns the shared call that builds and caches the value at key.
+func (l *CacheLoader) rebuild(ctx context.Context, key string, ttl time.Duration, build func(ctx context.Context) (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		ctx := context.WithoutCancel(ctx)
+		value, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := loadedEntry{Value: data, FreshUntil: time.Now().Add(ttl)}
+		if err := l.cache.Set(ctx, key, entry, ttl+l.staleFor); err != nil {
+			log.Printf("Error caching %s: %v", key, err)
+		}
+		return json.RawMessage(data), nil
+	}
+}
@@ -15,25 +15,43 @@ type CacheService interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
 	DeleteByPattern(ctx context.Context, pattern string) error
+	// Increment adds one to the counter at key and returns its new value. A
+	// new counter expires after window.
+	Increment(ctx context.Context, key string, window time.Duration) (int64, error)
 }
 
 type RedisCacheService struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
+// NewRedisCacheService connects to a single Redis node, a cluster or a
+// master found through sentinels, as cfg.RedisMode says. Connections are
+// made when first needed, so Redis being down is not an error here.
 func NewRedisCacheService(cfg *config.CacheConfig) (*RedisCacheService, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
-
-	ctx := context.Background()
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	var client redis.UniversalClient
+	switch cfg.RedisMode {
+	case "", config.RedisSingle:
+		client = redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+	case config.RedisCluster:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.RedisAddrs,
+			Password: cfg.RedisPassword,
+		})
+	case config.RedisSentinel:
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.RedisMasterName,
+			SentinelAddrs:    cfg.RedisAddrs,
+			SentinelPassword: cfg.RedisSentinelPassword,
+			Password:         cfg.RedisPassword,
+			DB:               cfg.RedisDB,
+		})
+	default:
+		return nil, fmt.Errorf("unknown Redis mode %q", cfg.RedisMode)
 	}
-
 	return &RedisCacheService{client: client}, nil
 }
 
@@ -53,13 +71,39 @@ func (c *RedisCacheService) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
 }
 
+// DeleteByPattern scans every master of a cluster, as each only holds its
+// own keys.
 func (c *RedisCacheService) DeleteByPattern(ctx context.Context, pattern string) error {
-	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return deleteByPattern(ctx, node, pattern)
+		})
+	}
+	return deleteByPattern(ctx, c.client, pattern)
+}
+
+func deleteByPattern(ctx context.Context, client redis.Cmdable, pattern string) error {
+	iter := client.Scan(ctx, 0, pattern, 0).Iterator()
 	for iter.Next(ctx) {
-		err := c.client.Del(ctx, iter.Val()).Err()
+		err := client.Del(ctx, iter.Val()).Err()
 		if err != nil {
 			return err
 		}
 	}
 	return iter.Err()
 }
+
+func (c *RedisCacheService) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Ping checks the Redis connection.
+func (c *RedisCacheService) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
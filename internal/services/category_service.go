@@ -2,23 +2,154 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	maxCategoryNameLength = 50
+	categoryTreeCacheKey  = "categories:tree"
+	categoryTreeCacheTTL  = time.Hour
+)
+
+var (
+	ErrInvalidCategoryName = errors.New("category name must be 1 to 50 characters")
+	ErrMergeIntoSelf       = errors.New("cannot merge a category into itself")
 )
 
 type CategoryService interface {
 	GetAllCategories(ctx context.Context) ([]string, error)
+	CreateCategory(ctx context.Context, name, parent string) (*models.Category, error)
+	RenameCategory(ctx context.Context, oldName, newName string) (int64, error)
+	MergeCategories(ctx context.Context, source, target string) (int64, error)
+	DeleteCategory(ctx context.Context, name string) error
+	GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error)
+	SetParentCategory(ctx context.Context, name, parent string) error
 }
 
 type categoryService struct {
 	categoryRepo repository.CategoryRepository
+	cacheService CacheService
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+func NewCategoryService(categoryRepo repository.CategoryRepository, cacheService CacheService) CategoryService {
 	return &categoryService{
 		categoryRepo: categoryRepo,
+		cacheService: cacheService,
 	}
 }
 
 func (s *categoryService) GetAllCategories(ctx context.Context) ([]string, error) {
 	return s.categoryRepo.ListAllCategories(ctx)
 }
+
+func (s *categoryService) CreateCategory(ctx context.Context, name, parent string) (*models.Category, error) {
+	name, err := normalizeCategoryName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.Create(ctx, name, strings.TrimSpace(parent))
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx)
+	return category, nil
+}
+
+func (s *categoryService) RenameCategory(ctx context.Context, oldName, newName string) (int64, error) {
+	newName, err := normalizeCategoryName(newName)
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := s.categoryRepo.Rename(ctx, oldName, newName)
+	if err != nil {
+		return 0, err
+	}
+
+	s.invalidate(ctx)
+	return moved, nil
+}
+
+func (s *categoryService) MergeCategories(ctx context.Context, source, target string) (int64, error) {
+	if source == target {
+		return 0, ErrMergeIntoSelf
+	}
+
+	moved, err := s.categoryRepo.Merge(ctx, source, target)
+	if err != nil {
+		return 0, err
+	}
+
+	s.invalidate(ctx)
+	return moved, nil
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, name string) error {
+	if err := s.categoryRepo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *categoryService) GetCategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	if cached, err := s.cacheService.Get(ctx, categoryTreeCacheKey); err == nil {
+		var tree []*models.CategoryNode
+		if err := json.Unmarshal([]byte(cached), &tree); err == nil {
+			return tree, nil
+		}
+	}
+
+	tree, err := s.categoryRepo.Tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheService.Set(ctx, categoryTreeCacheKey, tree, categoryTreeCacheTTL); err != nil {
+		log.Printf("Failed to cache category tree: %v", err)
+	}
+	return tree, nil
+}
+
+func (s *categoryService) SetParentCategory(ctx context.Context, name, parent string) error {
+	if err := s.categoryRepo.SetParent(ctx, name, strings.TrimSpace(parent)); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx)
+	return nil
+}
+
+// invalidate drops cached category listings, suggestions, the category tree
+// and stats. Listings of parent categories include their subcategories'
+// landmarks, so every category listing is dropped rather than just the
+// changed ones.
+func (s *categoryService) invalidate(ctx context.Context) {
+	for _, pattern := range []string{"landmark:category:*", "suggestions:category:*"} {
+		if err := s.cacheService.DeleteByPattern(ctx, pattern); err != nil {
+			log.Printf("Failed to invalidate cache pattern %s: %v", pattern, err)
+		}
+	}
+	for _, key := range []string{publicStatsCacheKey, categoryTreeCacheKey} {
+		if err := s.cacheService.Delete(ctx, key); err != nil {
+			log.Printf("Failed to invalidate cache key %s: %v", key, err)
+		}
+	}
+}
+
+func normalizeCategoryName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxCategoryNameLength {
+		return "", ErrInvalidCategoryName
+	}
+	return name, nil
+}
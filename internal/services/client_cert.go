@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+)
+
+var ErrNoCertIdentity = errors.New("client certificate has no email identity")
+
+// ClientCertAccountResolver returns a resolver that maps a verified client
+// certificate to the account whose email is in the certificate's SAN, or in
+// its common name for older certificates.
+func ClientCertAccountResolver(userRepo repository.UserRepository, subRepo repository.SubscriptionRepository) func(ctx context.Context, cert *x509.Certificate) (*models.User, *models.Subscription, error) {
+	return func(ctx context.Context, cert *x509.Certificate) (*models.User, *models.Subscription, error) {
+		email := cert.Subject.CommonName
+		if len(cert.EmailAddresses) > 0 {
+			email = cert.EmailAddresses[0]
+		}
+		if email == "" {
+			return nil, nil, ErrNoCertIdentity
+		}
+
+		user, err := userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		subscription, err := subRepo.GetActiveByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return user, subscription, nil
+	}
+}
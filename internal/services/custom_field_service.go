@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxCustomFields           = 50
+	maxCustomFieldValueLength = 1000
+)
+
+// customFieldKeyPattern is what custom field keys may look like, so they
+// stay usable as identifiers in client code.
+var customFieldKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,64}$`)
+
+var ErrCustomFieldsRequireEnterprise = apperrors.New(apperrors.CodeSubscriptionRequired, "Custom fields require an Enterprise subscription")
+
+// CustomFieldService manages the private key/value fields Enterprise
+// accounts attach to landmarks. Fields are scoped to the account that set
+// them and merged into that account's landmark responses.
+type CustomFieldService interface {
+	Get(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID) (models.JSON, error)
+	// Replace sets the account's fields on the landmark to fields.
+	Replace(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID, fields models.JSON) (models.JSON, error)
+	// Update sets the fields in changes, removing those that are nil, and
+	// keeps the rest.
+	Update(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID, changes map[string]*string) (models.JSON, error)
+	Delete(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID) error
+	// ForLandmarks returns the fields userID set on each of landmarkIDs
+	// that has any.
+	ForLandmarks(ctx context.Context, userID uuid.UUID, landmarkIDs []uuid.UUID) (map[uuid.UUID]models.JSON, error)
+}
+
+type customFieldService struct {
+	customFieldRepo repository.LandmarkCustomFieldRepository
+	landmarkRepo    repository.LandmarkRepository
+}
+
+func NewCustomFieldService(customFieldRepo repository.LandmarkCustomFieldRepository, landmarkRepo repository.LandmarkRepository) CustomFieldService {
+	return &customFieldService{
+		customFieldRepo: customFieldRepo,
+		landmarkRepo:    landmarkRepo,
+	}
+}
+
+func (s *customFieldService) Get(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID) (models.JSON, error) {
+	if err := s.check(ctx, subscription, landmarkID); err != nil {
+		return nil, err
+	}
+	return s.current(ctx, subscription.UserID, landmarkID)
+}
+
+func (s *customFieldService) Replace(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID, fields models.JSON) (models.JSON, error) {
+	if err := s.check(ctx, subscription, landmarkID); err != nil {
+		return nil, err
+	}
+	return s.save(ctx, subscription.UserID, landmarkID, fields)
+}
+
+func (s *customFieldService) Update(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID, changes map[string]*string) (models.JSON, error) {
+	if err := s.check(ctx, subscription, landmarkID); err != nil {
+		return nil, err
+	}
+
+	fields, err := s.current(ctx, subscription.UserID, landmarkID)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(fields, key)
+		} else {
+			fields[key] = *value
+		}
+	}
+	return s.save(ctx, subscription.UserID, landmarkID, fields)
+}
+
+func (s *customFieldService) Delete(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID) error {
+	if err := s.check(ctx, subscription, landmarkID); err != nil {
+		return err
+	}
+	return s.customFieldRepo.Delete(ctx, subscription.UserID, landmarkID)
+}
+
+func (s *customFieldService) ForLandmarks(ctx context.Context, userID uuid.UUID, landmarkIDs []uuid.UUID) (map[uuid.UUID]models.JSON, error) {
+	rows, err := s.customFieldRepo.ListForLandmarks(ctx, userID, landmarkIDs)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[uuid.UUID]models.JSON, len(rows))
+	for _, row := range rows {
+		fields[row.LandmarkID] = row.Fields
+	}
+	return fields, nil
+}
+
+// check returns an error unless subscription may use custom fields and the
+// landmark exists.
+func (s *customFieldService) check(ctx context.Context, subscription *models.Subscription, landmarkID uuid.UUID) error {
+	if subscription.PlanType != models.EnterprisePlan {
+		return ErrCustomFieldsRequireEnterprise
+	}
+	landmark, err := s.landmarkRepo.GetByID(ctx, landmarkID)
+	if err != nil {
+		return err
+	}
+	if landmark == nil {
+		return ErrLandmarkNotFound
+	}
+	return nil
+}
+
+// current returns the user's fields on the landmark, empty if there are
+// none.
+func (s *customFieldService) current(ctx context.Context, userID, landmarkID uuid.UUID) (models.JSON, error) {
+	row, err := s.customFieldRepo.Get(ctx, userID, landmarkID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil || row.Fields == nil {
+		return models.JSON{}, nil
+	}
+	return row.Fields, nil
+}
+
+// save validates and stores fields. Removing every field deletes the row.
+func (s *customFieldService) save(ctx context.Context, userID, landmarkID uuid.UUID, fields models.JSON) (models.JSON, error) {
+	if err := validateCustomFields(fields); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return models.JSON{}, s.customFieldRepo.Delete(ctx, userID, landmarkID)
+	}
+
+	row := &models.LandmarkCustomFields{
+		UserID:     userID,
+		LandmarkID: landmarkID,
+		Fields:     fields,
+	}
+	if err := s.customFieldRepo.Save(ctx, row); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func validateCustomFields(fields models.JSON) error {
+	if len(fields) > maxCustomFields {
+		return apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("A landmark can have at most %d custom fields", maxCustomFields))
+	}
+	for key, value := range fields {
+		if !customFieldKeyPattern.MatchString(key) {
+			return apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("Invalid custom field key %q: use up to 64 letters, digits, '_', '.', ':' or '-'", key))
+		}
+		if utf8.RuneCountInString(value) > maxCustomFieldValueLength {
+			return apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("Custom field %q is longer than %d characters", key, maxCustomFieldValueLength))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/mail"
+	"strings"
+)
+
+// loginURL is where emails send users to log in.
+const loginURL = "https://landmark-api.com/auth?login=true"
+
+// EmailService sends transactional emails about a user's account and billing.
+type EmailService interface {
+	// SendWelcome emails a new user the temporary password of their
+	// account, in the given language when there is a translation.
+	SendWelcome(ctx context.Context, email, password, locale string) error
+	// SendNotification emails a notification: title as its heading and
+	// body, paragraphs separated by blank lines, as its text.
+	SendNotification(ctx context.Context, email, subject, title, body string) error
+}
+
+type emailService struct {
+	mailer    mail.Mailer
+	templates *mail.Templates
+}
+
+func NewEmailService(mailer mail.Mailer, templates *mail.Templates) EmailService {
+	return &emailService{mailer: mailer, templates: templates}
+}
+
+func (s *emailService) SendWelcome(ctx context.Context, email, password, locale string) error {
+	return s.send(ctx, "welcome", locale, email, struct {
+		Email    string
+		Password string
+		LoginURL string
+	}{email, password, loginURL})
+}
+
+func (s *emailService) SendNotification(ctx context.Context, email, subject, title, body string) error {
+	return s.send(ctx, "notification", "", email, struct {
+		Subject    string
+		Title      string
+		Paragraphs []string
+	}{subject, title, strings.Split(body, "\n\n")})
+}
+
+func (s *emailService) send(ctx context.Context, name, locale, email string, data interface{}) error {
+	message, err := s.templates.Render(name, locale, email, data)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, message)
+}
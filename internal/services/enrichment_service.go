@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+)
+
+// EnrichmentService keeps the Wikidata enrichment of landmarks current.
+type EnrichmentService interface {
+	// EnrichDue looks up a batch of landmarks that were never enriched or
+	// whose enrichment is stale, and returns how many matched an entity.
+	EnrichDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type enrichmentService struct {
+	source         EnrichmentSource
+	enrichmentRepo repository.LandmarkEnrichmentRepository
+	config         *config.EnrichmentConfig
+}
+
+func NewEnrichmentService(source EnrichmentSource, enrichmentRepo repository.LandmarkEnrichmentRepository, cfg *config.EnrichmentConfig) EnrichmentService {
+	return &enrichmentService{
+		source:         source,
+		enrichmentRepo: enrichmentRepo,
+		config:         cfg,
+	}
+}
+
+func (s *enrichmentService) EnrichDue(ctx context.Context, now time.Time) (int, error) {
+	landmarks, err := s.enrichmentRepo.ListDue(ctx, now.Add(-s.config.RefreshAfter), s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for _, landmark := range landmarks {
+		enrichment, err := s.source.Lookup(ctx, landmark.Name, landmark.Latitude, landmark.Longitude)
+		if err != nil {
+			// Left due, so it is retried on the next run
+			log.Printf("Error enriching landmark %s: %v", landmark.ID, err)
+			continue
+		}
+		if enrichment == nil {
+			enrichment = &models.LandmarkEnrichment{}
+		} else {
+			matched++
+		}
+		enrichment.LandmarkID = landmark.ID
+		enrichment.EnrichedAt = now
+		if err := s.enrichmentRepo.Save(ctx, enrichment); err != nil {
+			return matched, err
+		}
+	}
+	return matched, nil
+}
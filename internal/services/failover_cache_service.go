@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/config"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// failoverPingTimeout bounds the pings that decide whether Redis is down.
+const failoverPingTimeout = 2 * time.Second
+
+// ErrCacheUnavailable is returned for keys missing from the memory cache
+// while Redis is down, as Redis may still hold them. Callers that need a
+// definite answer, like the session denylist, must not take it for a miss.
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+// FailoverCacheService uses Redis while it can be reached and a memory
+// cache while it can't, so a Redis outage, even at startup, degrades the
+// API rather than taking it down. Counters such as rate limits are then
+// kept per instance. Redis is checked for every retry interval while it is
+// down; once it is back the memory cache is dropped and the deletes made
+// meanwhile are replayed on Redis, so it doesn't serve what they removed.
+type FailoverCacheService struct {
+	redis         *RedisCacheService
+	memory        *MemoryCacheService
+	retryInterval time.Duration
+	down          atomic.Bool
+
+	mu sync.Mutex
+	// deleted and deletedPatterns are the keys and patterns deleted while
+	// Redis was down.
+	deleted         map[string]bool
+	deletedPatterns map[string]bool
+}
+
+func NewFailoverCacheService(redisCache *RedisCacheService, cfg *config.CacheConfig) *FailoverCacheService {
+	c := &FailoverCacheService{
+		redis:           redisCache,
+		memory:          NewMemoryCacheService(cfg.FallbackSize),
+		retryInterval:   cfg.RetryInterval,
+		deleted:         make(map[string]bool),
+		deletedPatterns: make(map[string]bool),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), failoverPingTimeout)
+	defer cancel()
+	if err := redisCache.Ping(ctx); err != nil {
+		c.fail(err)
+	}
+	return c
+}
+
+func (c *FailoverCacheService) Get(ctx context.Context, key string) (string, error) {
+	if !c.down.Load() {
+		value, err := c.redis.Get(ctx, key)
+		if !c.unreachable(ctx, err) {
+			return value, err
+		}
+	}
+	value, err := c.memory.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return "", ErrCacheUnavailable
+	}
+	return value, err
+}
+
+func (c *FailoverCacheService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if !c.down.Load() {
+		err := c.redis.Set(ctx, key, value, expiration)
+		if !c.unreachable(ctx, err) {
+			return err
+		}
+	}
+	return c.memory.Set(ctx, key, value, expiration)
+}
+
+func (c *FailoverCacheService) Delete(ctx context.Context, key string) error {
+	if !c.down.Load() {
+		err := c.redis.Delete(ctx, key)
+		if !c.unreachable(ctx, err) {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Redis may have come back meanwhile, after the deletes were replayed
+	if !c.down.Load() {
+		return c.redis.Delete(ctx, key)
+	}
+	c.deleted[key] = true
+	return c.memory.Delete(ctx, key)
+}
+
+func (c *FailoverCacheService) DeleteByPattern(ctx context.Context, pattern string) error {
+	if !c.down.Load() {
+		err := c.redis.DeleteByPattern(ctx, pattern)
+		if !c.unreachable(ctx, err) {
+			return err
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.down.Load() {
+		return c.redis.DeleteByPattern(ctx, pattern)
+	}
+	c.deletedPatterns[pattern] = true
+	return c.memory.DeleteByPattern(ctx, pattern)
+}
+
+func (c *FailoverCacheService) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	if !c.down.Load() {
+		count, err := c.redis.Increment(ctx, key, window)
+		if !c.unreachable(ctx, err) {
+			return count, err
+		}
+	}
+	return c.memory.Increment(ctx, key, window)
+}
+
+// unreachable reports whether err means Redis is down, in which case it
+// fails over to memory. Misses, canceled requests and errors of a single
+// command, which leave Redis answering pings, don't count.
+func (c *FailoverCacheService) unreachable(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || ctx.Err() != nil {
+		return false
+	}
+	pingCtx, cancel := context.WithTimeout(context.Background(), failoverPingTimeout)
+	defer cancel()
+	if c.redis.Ping(pingCtx) == nil {
+		return false
+	}
+	c.fail(err)
+	return true
+}
+
+// fail moves the cache to memory until Redis is back.
+func (c *FailoverCacheService) fail(err error) {
+	if c.down.CompareAndSwap(false, true) {
+		log.Printf("Redis is unreachable, caching in memory until it is back: %v", err)
+		go c.waitForRedis()
+	}
+}
+
+// waitForRedis checks for Redis every retry interval until it is back.
+func (c *FailoverCacheService) waitForRedis() {
+	for {
+		time.Sleep(c.retryInterval)
+		if err := c.restore(); err == nil {
+			log.Printf("Redis is reachable again, caching in Redis")
+			return
+		}
+	}
+}
+
+// restore replays on Redis the deletes made while it was down and moves
+// the cache back to it.
+func (c *FailoverCacheService) restore() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.retryInterval)
+	defer cancel()
+	if err := c.redis.Ping(ctx); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.deleted {
+		if err := c.redis.Delete(ctx, key); err != nil {
+			return err
+		}
+		delete(c.deleted, key)
+	}
+	for pattern := range c.deletedPatterns {
+		if err := c.redis.DeleteByPattern(ctx, pattern); err != nil {
+			return err
+		}
+		delete(c.deletedPatterns, pattern)
+	}
+	c.memory.Clear()
+	c.down.Store(false)
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	featureFlagsCacheKey = "feature_flags"
+	featureFlagsCacheTTL = time.Minute
+	maxFlagTargetUsers   = 1000
+)
+
+var (
+	ErrInvalidFlagKey     = errors.New("flag key must be 1 to 100 lowercase letters, digits, underscores or dashes")
+	ErrInvalidFlagRollout = errors.New("rollout_percent must be between 0 and 100")
+	ErrInvalidFlagPlan    = errors.New("target_plans may only contain FREE, PRO or ENTERPRISE")
+	ErrInvalidFlagUsers   = errors.New("target_user_ids must be at most 1000 user IDs")
+)
+
+var flagKeyPattern = regexp.MustCompile(`^[a-z0-9_-]{1,100}$`)
+
+// FeatureFlagService evaluates and manages feature flags. Flags are read
+// from Redis and fall back to the database, so changes take effect within
+// featureFlagsCacheTTL on every instance without a deployment.
+type FeatureFlagService interface {
+	// IsEnabled reports whether key is on for the user in ctx. Unknown
+	// flags are off.
+	IsEnabled(ctx context.Context, key string) bool
+	// EvaluateAll returns every flag's value for the user in ctx.
+	EvaluateAll(ctx context.Context) (map[string]bool, error)
+	ListFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	GetFlag(ctx context.Context, key string) (*models.FeatureFlag, error)
+	CreateFlag(ctx context.Context, flag *models.FeatureFlag) error
+	UpdateFlag(ctx context.Context, flag *models.FeatureFlag) error
+	DeleteFlag(ctx context.Context, key string) error
+}
+
+type featureFlagService struct {
+	repo         repository.FeatureFlagRepository
+	cacheService CacheService
+}
+
+func NewFeatureFlagService(repo repository.FeatureFlagRepository, cacheService CacheService) FeatureFlagService {
+	return &featureFlagService{
+		repo:         repo,
+		cacheService: cacheService,
+	}
+}
+
+func (s *featureFlagService) IsEnabled(ctx context.Context, key string) bool {
+	flags, err := s.flags(ctx)
+	if err != nil {
+		log.Printf("Error loading feature flags, treating %s as disabled: %v", key, err)
+		return false
+	}
+
+	flag, ok := flags[key]
+	return ok && evaluateFlag(flag, flagSubjectFromContext(ctx))
+}
+
+func (s *featureFlagService) EvaluateAll(ctx context.Context) (map[string]bool, error) {
+	flags, err := s.flags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := flagSubjectFromContext(ctx)
+	values := make(map[string]bool, len(flags))
+	for key, flag := range flags {
+		values[key] = evaluateFlag(flag, subject)
+	}
+	return values, nil
+}
+
+func (s *featureFlagService) ListFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *featureFlagService) GetFlag(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	return s.repo.GetByKey(ctx, key)
+}
+
+func (s *featureFlagService) CreateFlag(ctx context.Context, flag *models.FeatureFlag) error {
+	if err := validateFlag(flag); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, flag); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *featureFlagService) UpdateFlag(ctx context.Context, flag *models.FeatureFlag) error {
+	if err := validateFlag(flag); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, flag); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *featureFlagService) DeleteFlag(ctx context.Context, key string) error {
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// flags returns all flags keyed by flag key, from Redis when cached.
+func (s *featureFlagService) flags(ctx context.Context) (map[string]models.FeatureFlag, error) {
+	if cached, err := s.cacheService.Get(ctx, featureFlagsCacheKey); err == nil {
+		var flags map[string]models.FeatureFlag
+		if err := json.Unmarshal([]byte(cached), &flags); err == nil {
+			return flags, nil
+		}
+	}
+
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing feature flags: %w", err)
+	}
+
+	flags := make(map[string]models.FeatureFlag, len(list))
+	for _, flag := range list {
+		flags[flag.Key] = flag
+	}
+
+	if err := s.cacheService.Set(ctx, featureFlagsCacheKey, flags, featureFlagsCacheTTL); err != nil {
+		log.Printf("Failed to cache feature flags: %v", err)
+	}
+	return flags, nil
+}
+
+func (s *featureFlagService) invalidate(ctx context.Context) {
+	if err := s.cacheService.Delete(ctx, featureFlagsCacheKey); err != nil {
+		log.Printf("Failed to invalidate feature flag cache: %v", err)
+	}
+}
+
+func validateFlag(flag *models.FeatureFlag) error {
+	if !flagKeyPattern.MatchString(flag.Key) {
+		return ErrInvalidFlagKey
+	}
+	if flag.RolloutPercent < 0 || flag.RolloutPercent > 100 {
+		return ErrInvalidFlagRollout
+	}
+	for _, plan := range flag.TargetPlans {
+		switch models.SubscriptionPlan(plan) {
+		case models.FreePlan, models.ProPlan, models.EnterprisePlan:
+		default:
+			return ErrInvalidFlagPlan
+		}
+	}
+	if len(flag.TargetUserIDs) > maxFlagTargetUsers {
+		return ErrInvalidFlagUsers
+	}
+	for _, id := range flag.TargetUserIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrInvalidFlagUsers
+		}
+	}
+	return nil
+}
+
+// flagSubject identifies who a flag is evaluated for. API keys map to a
+// single user, so the user ID covers both JWT and API key callers.
+type flagSubject struct {
+	userID string
+	plan   models.SubscriptionPlan
+}
+
+func flagSubjectFromContext(ctx context.Context) flagSubject {
+	var subject flagSubject
+	if user, ok := UserFromContext(ctx); ok {
+		subject.userID = user.ID.String()
+	}
+	if subscription, ok := SubscriptionFromContext(ctx); ok {
+		subject.plan = subscription.PlanType
+	}
+	return subject
+}
+
+// evaluateFlag places the subject in one of 100 buckets by hashing the user
+// with the flag key, so each flag rolls out to a different slice of users.
+// Anonymous callers only see fully rolled out flags without plan targeting.
+func evaluateFlag(flag models.FeatureFlag, subject flagSubject) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if subject.userID != "" && flag.TargetUserIDs.Contains(subject.userID) {
+		return true
+	}
+	if len(flag.TargetPlans) > 0 && !flag.TargetPlans.Contains(string(subject.plan)) {
+		return false
+	}
+	if flag.RolloutPercent >= 100 {
+		return true
+	}
+	if subject.userID == "" || flag.RolloutPercent <= 0 {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(flag.Key + ":" + subject.userID))
+	return int(h.Sum32()%100) < flag.RolloutPercent
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/config"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GeocodingResult is a place a provider resolved.
+type GeocodingResult struct {
+	DisplayName string  `json:"display_name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	City        string  `json:"city,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	CountryCode string  `json:"country_code,omitempty"`
+}
+
+// GeocodingProvider turns place names into coordinates and back.
+type GeocodingProvider interface {
+	Geocode(ctx context.Context, query string, limit int) ([]GeocodingResult, error)
+	// Reverse returns the address at a point, or nil if there is none.
+	Reverse(ctx context.Context, lat, lon float64) (*GeocodingResult, error)
+}
+
+// NewGeocodingProvider returns the provider cfg selects, reached through
+// client.
+func NewGeocodingProvider(cfg *config.GeocodingConfig, client *http.Client) (GeocodingProvider, error) {
+	switch cfg.Provider {
+	case "nominatim":
+		return &nominatimProvider{
+			client:    client,
+			baseURL:   strings.TrimRight(cfg.NominatimURL, "/"),
+			userAgent: cfg.UserAgent,
+		}, nil
+	case "google":
+		return &googleGeocodingProvider{client: client, apiKey: cfg.GoogleAPIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown geocoding provider %q", cfg.Provider)
+	}
+}
+
+// getJSON decodes the JSON response to a GET of rawURL into v.
+func getJSON(ctx context.Context, client *http.Client, rawURL, userAgent string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("provider returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type nominatimProvider struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+}
+
+type nominatimPlace struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Error       string `json:"error"`
+	Address     struct {
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+func (p nominatimPlace) result() GeocodingResult {
+	lat, _ := strconv.ParseFloat(p.Lat, 64)
+	lon, _ := strconv.ParseFloat(p.Lon, 64)
+
+	city := p.Address.City
+	if city == "" {
+		city = p.Address.Town
+	}
+	if city == "" {
+		city = p.Address.Village
+	}
+
+	return GeocodingResult{
+		DisplayName: p.DisplayName,
+		Latitude:    lat,
+		Longitude:   lon,
+		City:        city,
+		Country:     p.Address.Country,
+		CountryCode: strings.ToUpper(p.Address.CountryCode),
+	}
+}
+
+func (p *nominatimProvider) Geocode(ctx context.Context, query string, limit int) ([]GeocodingResult, error) {
+	params := url.Values{
+		"q":              {query},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"limit":          {strconv.Itoa(limit)},
+	}
+
+	var places []nominatimPlace
+	if err := getJSON(ctx, p.client, p.baseURL+"/search?"+params.Encode(), p.userAgent, &places); err != nil {
+		return nil, err
+	}
+
+	results := make([]GeocodingResult, 0, len(places))
+	for _, place := range places {
+		results = append(results, place.result())
+	}
+	return results, nil
+}
+
+func (p *nominatimProvider) Reverse(ctx context.Context, lat, lon float64) (*GeocodingResult, error) {
+	params := url.Values{
+		"lat":            {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"lon":            {strconv.FormatFloat(lon, 'f', 6, 64)},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+	}
+
+	var place nominatimPlace
+	if err := getJSON(ctx, p.client, p.baseURL+"/reverse?"+params.Encode(), p.userAgent, &place); err != nil {
+		return nil, err
+	}
+	// Nominatim reports points without an address as an error body
+	if place.Error != "" {
+		return nil, nil
+	}
+
+	result := place.result()
+	return &result, nil
+}
+
+type googleGeocodingProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+const googleGeocodingURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+type googleGeocodingResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		FormattedAddress string `json:"formatted_address"`
+		Geometry         struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+		AddressComponents []struct {
+			LongName  string   `json:"long_name"`
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
+	} `json:"results"`
+}
+
+func (p *googleGeocodingProvider) lookup(ctx context.Context, params url.Values) ([]GeocodingResult, error) {
+	params.Set("key", p.apiKey)
+
+	var response googleGeocodingResponse
+	if err := getJSON(ctx, p.client, googleGeocodingURL+"?"+params.Encode(), "", &response); err != nil {
+		return nil, err
+	}
+	switch response.Status {
+	case "OK", "ZERO_RESULTS":
+	default:
+		return nil, fmt.Errorf("provider returned %s: %s", response.Status, response.ErrorMessage)
+	}
+
+	results := make([]GeocodingResult, 0, len(response.Results))
+	for _, item := range response.Results {
+		result := GeocodingResult{
+			DisplayName: item.FormattedAddress,
+			Latitude:    item.Geometry.Location.Lat,
+			Longitude:   item.Geometry.Location.Lng,
+		}
+		for _, component := range item.AddressComponents {
+			for _, kind := range component.Types {
+				switch kind {
+				case "locality":
+					result.City = component.LongName
+				case "country":
+					result.Country = component.LongName
+					result.CountryCode = component.ShortName
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (p *googleGeocodingProvider) Geocode(ctx context.Context, query string, limit int) ([]GeocodingResult, error) {
+	results, err := p.lookup(ctx, url.Values{"address": {query}})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (p *googleGeocodingProvider) Reverse(ctx context.Context, lat, lon float64) (*GeocodingResult, error) {
+	latlng := strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lon, 'f', 6, 64)
+	results, err := p.lookup(ctx, url.Values{"latlng": {latlng}})
+	if err != nil || len(results) == 0 {
+		return nil, err
+	}
+	return &results[0], nil
+}
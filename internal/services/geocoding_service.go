@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrGeocodingQuotaExceeded = apperrors.New(apperrors.CodeQuotaExceeded, "Daily geocoding quota exceeded. Please upgrade your subscription for a higher quota.")
+	ErrGeocodingUnavailable   = apperrors.New(apperrors.CodeUnavailable, "Geocoding is temporarily unavailable")
+)
+
+// geocodeResultLimit is how many candidates a geocoding query returns.
+const geocodeResultLimit = 5
+
+// GeocodingService resolves places through the configured provider,
+// caching results and enforcing each plan's daily quota.
+type GeocodingService interface {
+	Geocode(ctx context.Context, subscription *models.Subscription, query string) ([]GeocodingResult, error)
+	// Reverse returns the address at a point, or nil if there is none.
+	Reverse(ctx context.Context, subscription *models.Subscription, lat, lon float64) (*GeocodingResult, error)
+}
+
+type geocodingService struct {
+	provider GeocodingProvider
+	cache    CacheService
+	config   *config.GeocodingConfig
+}
+
+func NewGeocodingService(provider GeocodingProvider, cache CacheService, cfg *config.GeocodingConfig) GeocodingService {
+	return &geocodingService{
+		provider: provider,
+		cache:    cache,
+		config:   cfg,
+	}
+}
+
+func (s *geocodingService) Geocode(ctx context.Context, subscription *models.Subscription, query string) ([]GeocodingResult, error) {
+	if err := s.checkQuota(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	key := "geocode:search:" + strings.ToLower(strings.Join(strings.Fields(query), " "))
+	var results []GeocodingResult
+	if s.cached(ctx, key, &results) {
+		return results, nil
+	}
+
+	results, err := s.provider.Geocode(ctx, query, geocodeResultLimit)
+	if err != nil {
+		log.Printf("Error geocoding %q: %v", query, err)
+		return nil, ErrGeocodingUnavailable
+	}
+	s.store(ctx, key, results)
+	return results, nil
+}
+
+func (s *geocodingService) Reverse(ctx context.Context, subscription *models.Subscription, lat, lon float64) (*GeocodingResult, error) {
+	if err := s.checkQuota(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	// About a meter of precision, so nearby lookups share a cache entry
+	key := fmt.Sprintf("geocode:reverse:%.5f:%.5f", lat, lon)
+	var result *GeocodingResult
+	if s.cached(ctx, key, &result) {
+		return result, nil
+	}
+
+	result, err := s.provider.Reverse(ctx, lat, lon)
+	if err != nil {
+		log.Printf("Error reverse geocoding %f,%f: %v", lat, lon, err)
+		return nil, ErrGeocodingUnavailable
+	}
+	s.store(ctx, key, result)
+	return result, nil
+}
+
+// checkQuota counts the request against the user's daily quota. Cached
+// lookups count too, so quotas do not depend on what others searched. If
+// the cache is unavailable the request is let through.
+func (s *geocodingService) checkQuota(ctx context.Context, subscription *models.Subscription) error {
+	quota := s.config.DailyQuota(subscription.PlanType)
+	if quota < 0 {
+		return nil
+	}
+
+	day := time.Now().UTC().Format("2006-01-02")
+	used, err := s.cache.Increment(ctx, "geocode:quota:"+subscription.UserID.String()+":"+day, 24*time.Hour)
+	if err != nil {
+		log.Printf("Error counting geocoding requests of user %s: %v", subscription.UserID, err)
+		return nil
+	}
+	if used > int64(quota) {
+		return ErrGeocodingQuotaExceeded
+	}
+	return nil
+}
+
+func (s *geocodingService) cached(ctx context.Context, key string, v interface{}) bool {
+	data, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal([]byte(data), v) == nil
+}
+
+func (s *geocodingService) store(ctx context.Context, key string, v interface{}) {
+	if err := s.cache.Set(ctx, key, v, s.config.CacheTTL); err != nil {
+		log.Printf("Error caching geocoding result %s: %v", key, err)
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"net/http"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rekognition"
+)
+
+// rekognitionMaxLabels is how many labels Rekognition returns per image.
+const rekognitionMaxLabels = 30
+
+// ImageTagger recognizes what images show.
+type ImageTagger interface {
+	// Labels returns the labels of the image at imageURL with at least
+	// minConfidence, most confident first.
+	Labels(ctx context.Context, imageURL string, minConfidence float64) (models.ImageLabels, error)
+}
+
+// NewImageTagger returns the tagger cfg selects, or nil when tagging is
+// disabled. Images are downloaded, and self-hosted models reached, through
+// client.
+func NewImageTagger(cfg *config.ImageTaggingConfig, client *http.Client) (ImageTagger, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "rekognition":
+		sess, err := session.NewSession(&aws.Config{
+			Region: aws.String(cfg.Region),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &rekognitionTagger{client: rekognition.New(sess), httpClient: client, maxImageBytes: cfg.MaxImageBytes}, nil
+	case "http":
+		return &httpImageTagger{client: client, modelURL: cfg.ModelURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown image tagging provider %q", cfg.Provider)
+	}
+}
+
+// rekognitionTagger tags images with Amazon Rekognition, which can only
+// read images from S3 or the request, so they are downloaded first.
+type rekognitionTagger struct {
+	client        *rekognition.Rekognition
+	httpClient    *http.Client
+	maxImageBytes int64
+}
+
+func (t *rekognitionTagger) Labels(ctx context.Context, imageURL string, minConfidence float64) (models.ImageLabels, error) {
+	image, err := t.download(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := t.client.DetectLabelsWithContext(ctx, &rekognition.DetectLabelsInput{
+		Image:         &rekognition.Image{Bytes: image},
+		MaxLabels:     aws.Int64(rekognitionMaxLabels),
+		MinConfidence: aws.Float64(minConfidence * 100),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(models.ImageLabels, 0, len(output.Labels))
+	for _, label := range output.Labels {
+		labels = append(labels, models.ImageLabel{
+			Name:       aws.StringValue(label.Name),
+			Confidence: aws.Float64Value(label.Confidence) / 100,
+		})
+	}
+	sortLabels(labels)
+	return labels, nil
+}
+
+func (t *rekognitionTagger) download(ctx context.Context, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image returned status %d", resp.StatusCode)
+	}
+	image, err := io.ReadAll(io.LimitReader(resp.Body, t.maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(image)) > t.maxImageBytes {
+		return nil, fmt.Errorf("image is larger than %d bytes", t.maxImageBytes)
+	}
+	return image, nil
+}
+
+// httpImageTagger tags images with a self-hosted model, which is sent
+// {"image_url": ...} and answers {"labels": [{"name": ..., "confidence": ...}]}
+// with confidences from 0 to 1.
+type httpImageTagger struct {
+	client   *http.Client
+	modelURL string
+}
+
+func (t *httpImageTagger) Labels(ctx context.Context, imageURL string, minConfidence float64) (models.ImageLabels, error) {
+	body, err := json.Marshal(map[string]string{"image_url": imageURL})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.modelURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("model returned status %d", resp.StatusCode)
+	}
+	var response struct {
+		Labels models.ImageLabels `json:"labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	labels := make(models.ImageLabels, 0, len(response.Labels))
+	for _, label := range response.Labels {
+		if label.Name != "" && label.Confidence >= minConfidence {
+			labels = append(labels, label)
+		}
+	}
+	sortLabels(labels)
+	return labels, nil
+}
+
+func sortLabels(labels models.ImageLabels) {
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].Confidence > labels[j].Confidence
+	})
+}
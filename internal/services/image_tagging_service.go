@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageTaggingService tags the images of pending submissions, to reject
+// those that don't show a landmark and suggest a category and tags to the
+// admins reviewing them.
+type ImageTaggingService interface {
+	// TagPending tags the untagged images of a batch of pending
+	// submissions and returns how many images it tagged.
+	TagPending(ctx context.Context, now time.Time) (int, error)
+	// ReviewImage accepts or rejects a submitted image, over what the
+	// tagging job made of it.
+	ReviewImage(ctx context.Context, imageID uuid.UUID, rejected bool) (*models.SubmissionImageTags, error)
+}
+
+type imageTaggingService struct {
+	tagger       ImageTagger
+	repo         repository.ImageTagRepository
+	categoryRepo repository.CategoryRepository
+	config       *config.ImageTaggingConfig
+	// relevant holds the normalized RelevantLabels.
+	relevant map[string]bool
+}
+
+func NewImageTaggingService(tagger ImageTagger, repo repository.ImageTagRepository, categoryRepo repository.CategoryRepository, cfg *config.ImageTaggingConfig) ImageTaggingService {
+	relevant := make(map[string]bool, len(cfg.RelevantLabels))
+	for _, label := range cfg.RelevantLabels {
+		relevant[normalizeLabel(label)] = true
+	}
+	return &imageTaggingService{
+		tagger:       tagger,
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		config:       cfg,
+		relevant:     relevant,
+	}
+}
+
+func (s *imageTaggingService) TagPending(ctx context.Context, now time.Time) (int, error) {
+	submissions, err := s.repo.ListUntagged(ctx, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(submissions) == 0 {
+		return 0, nil
+	}
+	categories, err := s.categoryRepo.ListAllCategories(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tagged := 0
+	for i := range submissions {
+		submission := &submissions[i]
+		var tags []models.SubmissionImageTags
+		for j := range submission.Images {
+			image := &submission.Images[j]
+			if image.Tags != nil {
+				continue
+			}
+			image.Tags = s.tag(ctx, image, now)
+			tags = append(tags, *image.Tags)
+		}
+		s.suggest(submission, categories)
+
+		if err := s.repo.Save(ctx, submission, tags); err != nil {
+			return tagged, err
+		}
+		tagged += len(tags)
+	}
+	return tagged, nil
+}
+
+// tag recognizes what image shows and rejects it when that is nothing
+// like a landmark. Images the tagger fails on are kept, with the error for
+// admins to see.
+func (s *imageTaggingService) tag(ctx context.Context, image *models.SubmissionLandmarkImage, now time.Time) *models.SubmissionImageTags {
+	tags := &models.SubmissionImageTags{
+		SubmissionLandmarkImageID: image.ID,
+		Labels:                    models.ImageLabels{},
+		TaggedAt:                  now,
+	}
+	// Labels too weak to suggest may still show the image is relevant
+	labels, err := s.tagger.Labels(ctx, image.ImageURL, min(s.config.MinConfidence, s.config.MinRelevance))
+	if err != nil {
+		log.Printf("Error tagging submission image %s: %v", image.ID, err)
+		tags.Error = err.Error()
+		return tags
+	}
+
+	tags.Labels = labels
+	for _, label := range labels {
+		if s.relevant[normalizeLabel(label.Name)] && label.Confidence > tags.Relevance {
+			tags.Relevance = label.Confidence
+		}
+	}
+	tags.Rejected = tags.Relevance < s.config.MinRelevance
+	return tags
+}
+
+// suggest sets the submission's suggested category and tags from the
+// confident labels of its images that weren't rejected. The category is
+// the existing one named like the most confident label that names one.
+func (s *imageTaggingService) suggest(submission *models.SubmissionLandmark, categories []string) {
+	confidence := make(map[string]float64)
+	for _, image := range submission.Images {
+		if image.Tags == nil || image.Rejected() {
+			continue
+		}
+		for _, label := range image.Tags.Labels {
+			name := strings.ToLower(label.Name)
+			if label.Confidence >= s.config.MinConfidence && label.Confidence > confidence[name] {
+				confidence[name] = label.Confidence
+			}
+		}
+	}
+
+	labels := make(models.ImageLabels, 0, len(confidence))
+	for name, c := range confidence {
+		labels = append(labels, models.ImageLabel{Name: name, Confidence: c})
+	}
+	sortLabels(labels)
+
+	byLabel := make(map[string]string, len(categories))
+	for _, category := range categories {
+		byLabel[normalizeLabel(category)] = category
+	}
+	submission.SuggestedCategory, submission.CategoryConfidence = "", 0
+	submission.SuggestedTags = models.StringList{}
+	for _, label := range labels {
+		if category, ok := byLabel[normalizeLabel(label.Name)]; ok && submission.SuggestedCategory == "" {
+			submission.SuggestedCategory, submission.CategoryConfidence = category, label.Confidence
+		}
+		if len(submission.SuggestedTags) < s.config.MaxTags {
+			submission.SuggestedTags = append(submission.SuggestedTags, label.Name)
+		}
+	}
+}
+
+func (s *imageTaggingService) ReviewImage(ctx context.Context, imageID uuid.UUID, rejected bool) (*models.SubmissionImageTags, error) {
+	return s.repo.Review(ctx, imageID, rejected, time.Now())
+}
+
+// normalizeLabel matches labels and category names regardless of case and
+// of plurals, so "Castles" names the "Castle" category.
+func normalizeLabel(label string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(label)), "s")
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxBulkItems     = 500
+	maxTagLength     = 50
+	maxTagsPerChange = 20
+)
+
+// BulkOperation is a change the bulk endpoint applies to every item.
+type BulkOperation string
+
+const (
+	BulkSetCategory        BulkOperation = "set_category"
+	BulkSetStatus          BulkOperation = "set_status"
+	BulkAddTags            BulkOperation = "add_tags"
+	BulkRemoveTags         BulkOperation = "remove_tags"
+	BulkDelete             BulkOperation = "delete"
+	BulkPublishSubmissions BulkOperation = "publish_submissions"
+)
+
+// Statuses of the items of a bulk operation.
+const (
+	BulkItemOK = "ok"
+	// BulkItemFailed items could not be changed, so nothing was.
+	BulkItemFailed = "failed"
+	// BulkItemRolledBack items could have been changed but were not,
+	// because another item failed.
+	BulkItemRolledBack = "rolled_back"
+)
+
+// BulkRequest is a bulk operation and its items: landmark IDs, or
+// submission IDs for BulkPublishSubmissions.
+type BulkRequest struct {
+	Operation BulkOperation
+	IDs       []uuid.UUID
+	// Category is the category BulkSetCategory moves landmarks to.
+	Category string
+	// Status is the status BulkSetStatus moves landmarks to.
+	Status models.LandmarkStatus
+	// Tags are what BulkAddTags and BulkRemoveTags add or remove.
+	Tags []string
+}
+
+type BulkItemResult struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	// LandmarkID is the landmark a published submission became.
+	LandmarkID *uuid.UUID `json:"landmark_id,omitempty"`
+}
+
+// BulkResult reports what a bulk operation did to each item. Applied is
+// false when an item failed and no change was made.
+type BulkResult struct {
+	Operation BulkOperation    `json:"operation"`
+	Applied   bool             `json:"applied"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// LandmarkBulkService applies one admin change to many landmarks or
+// submissions at once, all or nothing.
+type LandmarkBulkService interface {
+	Apply(ctx context.Context, req BulkRequest) (*BulkResult, error)
+}
+
+type landmarkBulkService struct {
+	bulkRepo      repository.LandmarkBulkRepository
+	notifications NotificationService
+}
+
+func NewLandmarkBulkService(bulkRepo repository.LandmarkBulkRepository, notifications NotificationService) LandmarkBulkService {
+	return &landmarkBulkService{bulkRepo: bulkRepo, notifications: notifications}
+}
+
+func (s *landmarkBulkService) Apply(ctx context.Context, req BulkRequest) (*BulkResult, error) {
+	ids := uniqueIDs(req.IDs)
+	if len(ids) == 0 {
+		return nil, apperrors.New(apperrors.CodeValidationFailed, "At least one ID is required")
+	}
+	if len(ids) > maxBulkItems {
+		return nil, apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("A bulk operation can change at most %d items", maxBulkItems))
+	}
+
+	var (
+		itemErrs    []error
+		landmarkIDs []uuid.UUID
+		err         error
+	)
+	switch req.Operation {
+	case BulkSetCategory:
+		category := strings.TrimSpace(req.Category)
+		if category == "" || utf8.RuneCountInString(category) > maxCategoryNameLength {
+			return nil, apperrors.New(apperrors.CodeValidationFailed, ErrInvalidCategoryName.Error())
+		}
+		itemErrs, err = s.bulkRepo.SetCategory(ctx, ids, category)
+	case BulkSetStatus:
+		if !req.Status.Valid() {
+			return nil, apperrors.New(apperrors.CodeValidationFailed, "Status must be draft, published or archived")
+		}
+		itemErrs, err = s.bulkRepo.SetStatus(ctx, ids, req.Status)
+	case BulkAddTags, BulkRemoveTags:
+		tags, tagErr := normalizeTags(req.Tags)
+		if tagErr != nil {
+			return nil, tagErr
+		}
+		if req.Operation == BulkAddTags {
+			itemErrs, err = s.bulkRepo.AddTags(ctx, ids, tags)
+		} else {
+			itemErrs, err = s.bulkRepo.RemoveTags(ctx, ids, tags)
+		}
+	case BulkDelete:
+		itemErrs, err = s.bulkRepo.Delete(ctx, ids)
+	case BulkPublishSubmissions:
+		landmarkIDs, itemErrs, err = s.bulkRepo.PublishSubmissions(ctx, ids)
+	default:
+		return nil, apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("Unknown bulk operation: %s", req.Operation))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{
+		Operation: req.Operation,
+		Applied:   itemErrs == nil,
+		Results:   make([]BulkItemResult, len(ids)),
+	}
+	for i, id := range ids {
+		item := BulkItemResult{ID: id, Status: BulkItemOK}
+		switch {
+		case itemErrs != nil && itemErrs[i] != nil:
+			item.Status = BulkItemFailed
+			item.Error = bulkItemMessage(req.Operation, itemErrs[i])
+		case itemErrs != nil:
+			item.Status = BulkItemRolledBack
+		case landmarkIDs != nil:
+			landmarkID := landmarkIDs[i]
+			item.LandmarkID = &landmarkID
+		}
+		result.Results[i] = item
+	}
+
+	if req.Operation == BulkPublishSubmissions && result.Applied {
+		s.notifyPublished(ctx, ids)
+	}
+	return result, nil
+}
+
+// notifyPublished tells the submitters of published submissions. The
+// submissions are already published, so failures are only logged.
+func (s *landmarkBulkService) notifyPublished(ctx context.Context, ids []uuid.UUID) {
+	submissions, err := s.bulkRepo.GetSubmissions(ctx, ids)
+	if err != nil {
+		log.Printf("Error getting published submissions to notify their submitters: %v", err)
+		return
+	}
+	for i := range submissions {
+		if err := NotifySubmissionReviewed(ctx, s.notifications, &submissions[i], true); err != nil {
+			log.Printf("Error notifying submitter of submission %s: %v", submissions[i].ID, err)
+		}
+	}
+}
+
+// bulkItemMessage explains why an item of operation failed.
+func bulkItemMessage(operation BulkOperation, err error) string {
+	switch {
+	case errors.Is(err, repository.ErrBulkItemNotFound) && operation == BulkPublishSubmissions:
+		return "Submission not found"
+	case errors.Is(err, repository.ErrBulkItemNotFound):
+		return "Landmark not found"
+	case errors.Is(err, repository.ErrSubmissionNotPending):
+		return "Submission is not pending"
+	case errors.Is(err, repository.ErrTooManyTags):
+		return fmt.Sprintf("A landmark can have at most %d tags", models.MaxLandmarkTags)
+	}
+	return err.Error()
+}
+
+// normalizeTags lowercases and trims tags, dropping duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, apperrors.New(apperrors.CodeValidationFailed, "At least one tag is required")
+	}
+	if len(tags) > maxTagsPerChange {
+		return nil, apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("At most %d tags can be changed at once", maxTagsPerChange))
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, apperrors.New(apperrors.CodeValidationFailed, fmt.Sprintf("Tags must be 1 to %d characters", maxTagLength))
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// uniqueIDs returns ids without duplicates, in their first order.
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
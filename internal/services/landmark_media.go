@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	youTubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoIDPattern   = regexp.MustCompile(`^[0-9]{1,12}$`)
+)
+
+var ErrPrimaryImageNotPhoto = apperrors.New(apperrors.CodeValidationFailed, "Only a photo can be the primary image")
+
+// AddMedia validates media and appends it to the end of the landmark's
+// gallery.
+func (s *landmarkService) AddMedia(ctx context.Context, id uuid.UUID, media *models.LandmarkImage) (*models.LandmarkImage, error) {
+	images, err := s.landmarkImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMedia(media); err != nil {
+		return nil, err
+	}
+
+	media.ID = uuid.New()
+	media.LandmarkID = id
+	media.IsPrimary = false
+	media.DisplayOrder = 0
+	for _, image := range images {
+		if image.DisplayOrder >= media.DisplayOrder {
+			media.DisplayOrder = image.DisplayOrder + 1
+		}
+	}
+	if err := s.landmarkRepo.AddImage(ctx, media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (s *landmarkService) DeleteMedia(ctx context.Context, id, imageID uuid.UUID) error {
+	if _, err := s.landmarkImages(ctx, id); err != nil {
+		return err
+	}
+	return s.landmarkRepo.DeleteImage(ctx, id, imageID)
+}
+
+// validateMedia checks that media has what its type and provider need and
+// nothing else, filling in the thumbnail of YouTube videos.
+func validateMedia(media *models.LandmarkImage) error {
+	switch media.Type() {
+	case models.MediaPhoto, models.MediaPano:
+		if media.ImageURL == "" {
+			return mediaError("image_url is required for photos and panoramas")
+		}
+		if media.Provider != "" || media.ProviderID != "" || media.StreamURL != "" {
+			return mediaError("provider, provider_id and stream_url are only for videos")
+		}
+	case models.MediaVideo:
+		return validateVideo(media)
+	default:
+		return mediaError("type must be photo, video or pano")
+	}
+	return nil
+}
+
+func validateVideo(media *models.LandmarkImage) error {
+	switch media.Provider {
+	case models.ProviderYouTube, models.ProviderVimeo:
+		if media.StreamURL != "" {
+			return mediaError("stream_url is only for s3_hls videos")
+		}
+		if media.Provider == models.ProviderYouTube {
+			if !youTubeIDPattern.MatchString(media.ProviderID) {
+				return mediaError("provider_id must be an 11 character YouTube video ID")
+			}
+			if media.ImageURL == "" {
+				media.ImageURL = "https://img.youtube.com/vi/" + media.ProviderID + "/hqdefault.jpg"
+			}
+		} else if !vimeoIDPattern.MatchString(media.ProviderID) {
+			return mediaError("provider_id must be a numeric Vimeo video ID")
+		}
+	case models.ProviderS3HLS:
+		if media.ProviderID != "" {
+			return mediaError("provider_id is only for youtube and vimeo videos")
+		}
+		stream, err := url.Parse(media.StreamURL)
+		if err != nil || stream.Scheme != "https" || stream.Host == "" || !strings.HasSuffix(stream.Path, ".m3u8") {
+			return mediaError("stream_url must be an https URL of an .m3u8 playlist")
+		}
+	default:
+		return mediaError("provider must be youtube, vimeo or s3_hls for videos")
+	}
+	return nil
+}
+
+func mediaError(message string) error {
+	return apperrors.New(apperrors.CodeValidationFailed, message)
+}
+
+// embedURL is the player URL of a YouTube or Vimeo video, or empty.
+func embedURL(media models.LandmarkImage) string {
+	switch media.Provider {
+	case models.ProviderYouTube:
+		return "https://www.youtube-nocookie.com/embed/" + media.ProviderID
+	case models.ProviderVimeo:
+		return "https://player.vimeo.com/video/" + media.ProviderID
+	}
+	return ""
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"landmark-api/internal/errors"
+	"landmark-api/internal/geo"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,27 +14,63 @@ import (
 type LandmarkService interface {
 	GetLandmark(ctx context.Context, id uuid.UUID) (*models.Landmark, error)
 	ListLandmarks(ctx context.Context, page, pageSize int) ([]models.Landmark, error)
-	GetLandmarksWithFilters(ctx context.Context, page, perPage int, searchTerm, category string) ([]models.Landmark, int64, error)
+	GetLandmarksWithFilters(ctx context.Context, page, perPage int, searchTerm, category string, status models.LandmarkStatus) ([]models.Landmark, int64, error)
 	GetLandmarkDetails(ctx context.Context, id uuid.UUID, userSubscription models.SubscriptionPlan) (*models.LandmarkDetail, error)
 	GetLandmarkAdminDetails(ctx context.Context, id uuid.UUID) (*models.LandmarkDetail, error)
+	// GetLandmarkEnrichment returns what the enrichment job found about a
+	// landmark on Wikidata, or nil if it has not matched an entity. Like
+	// details, it is only shown to Pro and Enterprise plans.
+	GetLandmarkEnrichment(ctx context.Context, id uuid.UUID) (*models.LandmarkEnrichment, error)
 	GetLandmarksByCountry(ctx context.Context, country string) ([]models.Landmark, error)
 	GetLandmarksByName(ctx context.Context, name string) ([]models.Landmark, error)
+	VerifyLandmark(ctx context.Context, id uuid.UUID, confidence float64) error
+	// SetStatus moves a landmark to a draft, published or archived status.
+	SetStatus(ctx context.Context, id uuid.UUID, status models.LandmarkStatus) error
+	// Clusters groups the landmarks inside bounds for a map at zoom.
+	Clusters(ctx context.Context, bounds geo.BBox, zoom int) ([]repository.LandmarkCluster, error)
+	// ReorderImages sets the gallery order of a landmark's images to that
+	// of imageIDs, which must list each of them once, and returns them.
+	ReorderImages(ctx context.Context, id uuid.UUID, imageIDs []uuid.UUID) ([]models.LandmarkImage, error)
+	// SetPrimaryImage makes a photo the one shown for its landmark and
+	// returns the landmark's images.
+	SetPrimaryImage(ctx context.Context, id, imageID uuid.UUID) ([]models.LandmarkImage, error)
+	UpdateImageCaption(ctx context.Context, id, imageID uuid.UUID, caption, altText, credit string) (*models.LandmarkImage, error)
+	// AddMedia adds a photo, video or panorama to the end of a landmark's
+	// gallery.
+	AddMedia(ctx context.Context, id uuid.UUID, media *models.LandmarkImage) (*models.LandmarkImage, error)
+	DeleteMedia(ctx context.Context, id, imageID uuid.UUID) error
 }
 
+var (
+	ErrLandmarkNotFound = errors.New(errors.CodeLandmarkNotFound, "Landmark not found")
+	ErrImageOrder       = errors.New(errors.CodeValidationFailed, "image_ids must list each image of the landmark exactly once")
+)
+
+const (
+	MaxClusterZoom = 20
+	// maxClusters caps a response at high zoom over a large box; only the
+	// largest clusters are returned
+	maxClusters = 2000
+)
+
 type landmarkService struct {
-	landmarkRepo repository.LandmarkRepository
+	landmarkRepo   repository.LandmarkRepository
+	enrichmentRepo repository.LandmarkEnrichmentRepository
 }
 
-func NewLandmarkService(landmarkRepo repository.LandmarkRepository) LandmarkService {
-	return &landmarkService{landmarkRepo: landmarkRepo}
+func NewLandmarkService(landmarkRepo repository.LandmarkRepository, enrichmentRepo repository.LandmarkEnrichmentRepository) LandmarkService {
+	return &landmarkService{
+		landmarkRepo:   landmarkRepo,
+		enrichmentRepo: enrichmentRepo,
+	}
 }
 
 func (s *landmarkService) GetLandmark(ctx context.Context, id uuid.UUID) (*models.Landmark, error) {
 	return s.landmarkRepo.GetByID(ctx, id)
 }
 
-func (s *landmarkService) GetLandmarksWithFilters(ctx context.Context, page, perPage int, searchTerm, category string) ([]models.Landmark, int64, error) {
-	return s.landmarkRepo.ListWithFilters(ctx, page, perPage, searchTerm, category)
+func (s *landmarkService) GetLandmarksWithFilters(ctx context.Context, page, perPage int, searchTerm, category string, status models.LandmarkStatus) ([]models.Landmark, int64, error) {
+	return s.landmarkRepo.ListWithFilters(ctx, page, perPage, searchTerm, category, status)
 }
 
 func (s *landmarkService) ListLandmarks(ctx context.Context, page, pageSize int) ([]models.Landmark, error) {
@@ -51,6 +89,14 @@ func (s *landmarkService) GetLandmarkAdminDetails(ctx context.Context, id uuid.U
 	return s.landmarkRepo.GetDetails(ctx, id)
 }
 
+func (s *landmarkService) GetLandmarkEnrichment(ctx context.Context, id uuid.UUID) (*models.LandmarkEnrichment, error) {
+	enrichment, err := s.enrichmentRepo.GetByLandmarkID(ctx, id)
+	if err != nil || enrichment == nil || enrichment.WikidataID == "" {
+		return nil, err
+	}
+	return enrichment, nil
+}
+
 // GetLandmarksByCountry retrieves landmarks by country from the repository.
 func (s *landmarkService) GetLandmarksByCountry(ctx context.Context, country string) ([]models.Landmark, error) {
 	return s.landmarkRepo.FindByCountry(ctx, country)
@@ -60,3 +106,88 @@ func (s *landmarkService) GetLandmarksByCountry(ctx context.Context, country str
 func (s *landmarkService) GetLandmarksByName(ctx context.Context, name string) ([]models.Landmark, error) {
 	return s.landmarkRepo.FindByName(ctx, name)
 }
+
+// VerifyLandmark records that the landmark's data was checked just now with
+// the given confidence (0 to 1).
+func (s *landmarkService) VerifyLandmark(ctx context.Context, id uuid.UUID, confidence float64) error {
+	if !models.ValidDataConfidence(confidence) {
+		return errors.ErrInvalidInput
+	}
+	return s.landmarkRepo.MarkVerified(ctx, id, time.Now(), confidence)
+}
+
+func (s *landmarkService) SetStatus(ctx context.Context, id uuid.UUID, status models.LandmarkStatus) error {
+	if !status.Valid() {
+		return errors.ErrInvalidInput
+	}
+	return s.landmarkRepo.SetStatus(ctx, id, status)
+}
+
+func (s *landmarkService) Clusters(ctx context.Context, bounds geo.BBox, zoom int) ([]repository.LandmarkCluster, error) {
+	return s.landmarkRepo.Clusters(ctx, repository.ClusterQuery{
+		Bounds:   bounds,
+		CellSize: geo.ClusterCellSize(zoom),
+		Limit:    maxClusters,
+	})
+}
+
+func (s *landmarkService) ReorderImages(ctx context.Context, id uuid.UUID, imageIDs []uuid.UUID) ([]models.LandmarkImage, error) {
+	images, err := s.landmarkImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[uuid.UUID]bool, len(imageIDs))
+	for _, imageID := range imageIDs {
+		listed[imageID] = true
+	}
+	if len(imageIDs) != len(images) || len(listed) != len(images) {
+		return nil, ErrImageOrder
+	}
+	for _, image := range images {
+		if !listed[image.ID] {
+			return nil, ErrImageOrder
+		}
+	}
+
+	if err := s.landmarkRepo.ReorderImages(ctx, id, imageIDs); err != nil {
+		return nil, err
+	}
+	return s.landmarkRepo.ListImages(ctx, id)
+}
+
+func (s *landmarkService) SetPrimaryImage(ctx context.Context, id, imageID uuid.UUID) ([]models.LandmarkImage, error) {
+	images, err := s.landmarkImages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if image.ID == imageID && image.Type() != models.MediaPhoto {
+			return nil, ErrPrimaryImageNotPhoto
+		}
+	}
+	if err := s.landmarkRepo.SetPrimaryImage(ctx, id, imageID); err != nil {
+		return nil, err
+	}
+	return s.landmarkRepo.ListImages(ctx, id)
+}
+
+func (s *landmarkService) UpdateImageCaption(ctx context.Context, id, imageID uuid.UUID, caption, altText, credit string) (*models.LandmarkImage, error) {
+	if _, err := s.landmarkImages(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.landmarkRepo.UpdateImageCaption(ctx, id, imageID, caption, altText, credit)
+}
+
+// landmarkImages returns the images of the landmark with id, or
+// ErrLandmarkNotFound.
+func (s *landmarkService) landmarkImages(ctx context.Context, id uuid.UUID) ([]models.LandmarkImage, error) {
+	landmark, err := s.landmarkRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if landmark == nil {
+		return nil, ErrLandmarkNotFound
+	}
+	return s.landmarkRepo.ListImages(ctx, id)
+}
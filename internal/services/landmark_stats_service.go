@@ -2,21 +2,45 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
+	"log"
+	"time"
+)
+
+const (
+	publicStatsCacheKey = "stats:landmarks:public"
+	publicStatsCacheTTL = time.Hour
+	publicStatsRecent   = 10
+
+	DefaultGrowthWeeks = 12
+	MaxGrowthWeeks     = 104
 )
 
 type LandmarkStatsService interface {
 	GetLandmarkStats(ctx context.Context) (*models.LandmarkStats, error)
+	// GetPublicLandmarkStats returns the customer-facing stats for plan,
+	// computed at most once an hour.
+	GetPublicLandmarkStats(ctx context.Context, plan models.SubscriptionPlan) (*models.PublicLandmarkStats, error)
+	// TakeDailySnapshots records yesterday's final numbers and today's
+	// numbers so far. It is safe to run repeatedly.
+	TakeDailySnapshots(ctx context.Context, now time.Time) error
+	// GetWeeklyGrowth returns the last weeks weeks of snapshots summed per
+	// week, oldest first. The current week is included and may be partial.
+	GetWeeklyGrowth(ctx context.Context, weeks int, now time.Time) ([]models.WeeklyGrowth, error)
 }
 
 type landmarkStatsService struct {
 	landmarkStatsRepo repository.LandmarkStatsRepository
+	cacheService      CacheService
 }
 
-func NewLandmarkStatsService(landmarkStatsRepo repository.LandmarkStatsRepository) LandmarkStatsService {
+func NewLandmarkStatsService(landmarkStatsRepo repository.LandmarkStatsRepository, cacheService CacheService) LandmarkStatsService {
 	return &landmarkStatsService{
 		landmarkStatsRepo: landmarkStatsRepo,
+		cacheService:      cacheService,
 	}
 }
 
@@ -48,3 +72,132 @@ func (s *landmarkStatsService) GetLandmarkStats(ctx context.Context) (*models.La
 		RecentlyAdded:       recentlyAdded,
 	}, nil
 }
+
+// GetPublicLandmarkStats caches the full public stats once for all plans and
+// trims them per plan: Free sees totals and categories, paid plans also get
+// the country breakdown and recently added landmarks.
+func (s *landmarkStatsService) GetPublicLandmarkStats(ctx context.Context, plan models.SubscriptionPlan) (*models.PublicLandmarkStats, error) {
+	stats, err := s.publicStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan == models.FreePlan {
+		stats.LandmarksByCountry = nil
+		stats.RecentlyAdded = nil
+	}
+	return stats, nil
+}
+
+func (s *landmarkStatsService) publicStats(ctx context.Context) (*models.PublicLandmarkStats, error) {
+	if cached, err := s.cacheService.Get(ctx, publicStatsCacheKey); err == nil {
+		var stats models.PublicLandmarkStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	full, err := s.GetLandmarkStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.landmarkStatsRepo.GetRecentlyAddedLandmarks(ctx, publicStatsRecent)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.PublicLandmarkStats{
+		TotalLandmarks:      full.TotalLandmarks,
+		LandmarksByCategory: full.LandmarksByCategory,
+		LandmarksByCountry:  full.LandmarksByCountry,
+		RecentlyAdded:       make([]models.RecentLandmark, 0, len(recent)),
+		GeneratedAt:         time.Now().UTC(),
+	}
+	for _, landmark := range recent {
+		stats.RecentlyAdded = append(stats.RecentlyAdded, models.RecentLandmark{
+			ID:       landmark.ID,
+			Name:     landmark.Name,
+			City:     landmark.City,
+			Country:  landmark.Country,
+			Category: landmark.Category,
+			AddedAt:  landmark.CreatedAt,
+		})
+	}
+
+	if err := s.cacheService.Set(ctx, publicStatsCacheKey, stats, publicStatsCacheTTL); err != nil {
+		log.Printf("Failed to cache public landmark stats: %v", err)
+	}
+
+	return stats, nil
+}
+
+func (s *landmarkStatsService) TakeDailySnapshots(ctx context.Context, now time.Time) error {
+	today := startOfDay(now)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		snapshot, err := s.landmarkStatsRepo.BuildSnapshot(ctx, day)
+		if err != nil {
+			return fmt.Errorf("error building snapshot for %s: %w", day.Format("2006-01-02"), err)
+		}
+		if err := s.landmarkStatsRepo.SaveSnapshot(ctx, snapshot); err != nil {
+			return fmt.Errorf("error saving snapshot for %s: %w", day.Format("2006-01-02"), err)
+		}
+	}
+	return nil
+}
+
+func (s *landmarkStatsService) GetWeeklyGrowth(ctx context.Context, weeks int, now time.Time) ([]models.WeeklyGrowth, error) {
+	if weeks <= 0 {
+		weeks = DefaultGrowthWeeks
+	}
+	if weeks > MaxGrowthWeeks {
+		weeks = MaxGrowthWeeks
+	}
+
+	currentWeek := startOfWeek(now)
+	from := currentWeek.AddDate(0, 0, -7*(weeks-1))
+	snapshots, err := s.landmarkStatsRepo.ListSnapshots(ctx, from, currentWeek.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, fmt.Errorf("error listing stats snapshots: %w", err)
+	}
+
+	growth := make([]models.WeeklyGrowth, weeks)
+	for i := range growth {
+		growth[i].WeekStart = from.AddDate(0, 0, 7*i)
+	}
+	for _, snapshot := range snapshots {
+		i := int(startOfWeek(snapshot.Date).Sub(from).Hours() / (24 * 7))
+		if i < 0 || i >= weeks {
+			continue
+		}
+		week := &growth[i]
+		// Snapshots are ordered by date, so the last one holds the week's total
+		week.TotalLandmarks = snapshot.TotalLandmarks
+		week.LandmarksAdded += snapshot.LandmarksAdded
+		week.SubmissionsCreated += snapshot.SubmissionsCreated
+		week.SubmissionsApproved += snapshot.SubmissionsApproved
+		week.SubmissionsRejected += snapshot.SubmissionsRejected
+	}
+	for i := range growth {
+		reviewed := growth[i].SubmissionsApproved + growth[i].SubmissionsRejected
+		if reviewed > 0 {
+			rate := float64(growth[i].SubmissionsApproved) / float64(reviewed)
+			growth[i].ApprovalRate = &rate
+		}
+	}
+
+	return growth, nil
+}
+
+// startOfDay returns midnight UTC of t's day. Snapshots are keyed by UTC date.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek returns midnight UTC of the Monday starting t's week.
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimelineEvent is one entry in a landmark's history.
+type TimelineEvent struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Type      string          `json:"type"`
+	Source    string          `json:"source"`
+	ActorID   *uuid.UUID      `json:"actor_id,omitempty"`
+	IPAddress string          `json:"ip_address,omitempty"`
+	Summary   string          `json:"summary"`
+	Changes   json.RawMessage `json:"changes,omitempty"`
+}
+
+// LandmarkTimeline is a landmark's full history, oldest event first.
+type LandmarkTimeline struct {
+	LandmarkID uuid.UUID       `json:"landmark_id"`
+	Name       string          `json:"name"`
+	Deleted    bool            `json:"deleted"`
+	Events     []TimelineEvent `json:"events"`
+}
+
+// LandmarkTimelineService combines the audit log, submission approvals and
+// image records into one chronological history per landmark.
+type LandmarkTimelineService interface {
+	// GetTimeline returns nil when the landmark never existed.
+	GetTimeline(ctx context.Context, landmarkID uuid.UUID) (*LandmarkTimeline, error)
+}
+
+type landmarkTimelineService struct {
+	landmarkRepo repository.LandmarkRepository
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewLandmarkTimelineService(landmarkRepo repository.LandmarkRepository, auditLogRepo repository.AuditLogRepository) LandmarkTimelineService {
+	return &landmarkTimelineService{
+		landmarkRepo: landmarkRepo,
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+func (s *landmarkTimelineService) GetTimeline(ctx context.Context, landmarkID uuid.UUID) (*LandmarkTimeline, error) {
+	landmark, err := s.landmarkRepo.GetWithHistory(ctx, landmarkID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching landmark: %w", err)
+	}
+	if landmark == nil {
+		return nil, nil
+	}
+
+	logs, err := s.auditLogRepo.ListForLandmark(ctx, landmarkID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching audit logs: %w", err)
+	}
+
+	events := []TimelineEvent{{
+		Timestamp: landmark.CreatedAt,
+		Type:      "RECORD_CREATED",
+		Source:    "landmark",
+		Summary:   fmt.Sprintf("Landmark %q added to the catalog", landmark.Name),
+	}}
+
+	for _, log := range logs {
+		event := TimelineEvent{
+			Timestamp: log.Timestamp,
+			Type:      log.EntityType + "_" + log.Action,
+			Source:    "audit",
+			IPAddress: log.IPAddress,
+			Summary:   log.Details,
+			Changes:   log.Changes,
+		}
+		if log.AdminID != uuid.Nil {
+			actor := log.AdminID
+			event.ActorID = &actor
+		}
+		events = append(events, event)
+	}
+
+	for _, image := range landmark.Images {
+		events = append(events, TimelineEvent{
+			Timestamp: image.CreatedAt,
+			Type:      "IMAGE_ADDED",
+			Source:    "image",
+			Summary:   "Image added: " + image.ImageURL,
+		})
+	}
+
+	if landmark.DeletedAt.Valid {
+		events = append(events, TimelineEvent{
+			Timestamp: landmark.DeletedAt.Time,
+			Type:      "RECORD_DELETED",
+			Source:    "landmark",
+			Summary:   "Landmark removed from the catalog",
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return &LandmarkTimeline{
+		LandmarkID: landmark.ID,
+		Name:       landmark.Name,
+		Deleted:    landmark.DeletedAt.Valid,
+		Events:     events,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"landmark-api/internal/config"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LocalCacheService keeps the values of the hottest keys in process memory,
+// in front of a shared cache such as Redis, to save the round trip to it.
+// Only keys with one of the configured prefixes are kept, for a short TTL,
+// and the least recently used ones are evicted past the configured size.
+//
+// Writes and deletes go through to the shared cache and drop the local
+// entry, but other instances keep theirs until it expires, so the TTL
+// bounds how stale a value can be.
+type LocalCacheService struct {
+	next       CacheService
+	prefixes   []string
+	maxEntries int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds the entries, most recently used first.
+	order *list.List
+	stats LocalCacheStats
+}
+
+type localCacheEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// LocalCacheStats counts the local cache's lookups since startup. Lookups
+// of keys it doesn't keep are not counted.
+type LocalCacheStats struct {
+	Entries    int   `json:"entries"`
+	MaxEntries int   `json:"max_entries"`
+	Hits       int64 `json:"hits"`
+	Misses     int64 `json:"misses"`
+	Evictions  int64 `json:"evictions"`
+}
+
+func NewLocalCacheService(next CacheService, cfg *config.CacheConfig) *LocalCacheService {
+	return &LocalCacheService{
+		next:       next,
+		prefixes:   cfg.LocalPrefixes,
+		maxEntries: cfg.LocalSize,
+		ttl:        cfg.LocalTTL,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		stats:      LocalCacheStats{MaxEntries: cfg.LocalSize},
+	}
+}
+
+func (c *LocalCacheService) Get(ctx context.Context, key string) (string, error) {
+	if !c.keeps(key) {
+		return c.next.Get(ctx, key)
+	}
+	if value, ok := c.lookup(key); ok {
+		return value, nil
+	}
+
+	value, err := c.next.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	c.store(key, value)
+	return value, nil
+}
+
+func (c *LocalCacheService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	// The next Get loads the value as the shared cache serializes it
+	c.forget(key)
+	return c.next.Set(ctx, key, value, expiration)
+}
+
+func (c *LocalCacheService) Delete(ctx context.Context, key string) error {
+	c.forget(key)
+	return c.next.Delete(ctx, key)
+}
+
+func (c *LocalCacheService) DeleteByPattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	for key, element := range c.entries {
+		if matched, _ := path.Match(pattern, key); matched {
+			c.remove(element)
+		}
+	}
+	c.mu.Unlock()
+	return c.next.DeleteByPattern(ctx, pattern)
+}
+
+// Increment always goes to the shared cache, as counters are shared
+// between instances.
+func (c *LocalCacheService) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return c.next.Increment(ctx, key, window)
+}
+
+// Stats returns the counts of lookups so far and the number of entries.
+func (c *LocalCacheService) Stats() LocalCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+func (c *LocalCacheService) keeps(key string) bool {
+	for _, prefix := range c.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *LocalCacheService) lookup(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return "", false
+	}
+	entry := element.Value.(*localCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		c.stats.Misses++
+		return "", false
+	}
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return entry.value, true
+}
+
+func (c *LocalCacheService) store(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := time.Now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*localCacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&localCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *LocalCacheService) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// remove drops an entry; c.mu must be held.
+func (c *LocalCacheService) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*localCacheEntry).key)
+}
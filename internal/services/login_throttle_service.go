@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/repository"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLoginThrottled = errors.New("too many failed login attempts; try again later")
+	ErrAccountLocked  = errors.New("account is temporarily locked after too many failed login attempts")
+)
+
+// LoginBlockedError is returned when a login may not be attempted yet.
+// Err is ErrLoginThrottled or ErrAccountLocked.
+type LoginBlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Err
+}
+
+// LoginThrottleService protects logins against brute force, counting failed
+// attempts per account and per client IP in the cache. If the cache is
+// unavailable logins are let through rather than blocked.
+type LoginThrottleService interface {
+	// Allow returns a *LoginBlockedError if a login for email from ip must
+	// wait.
+	Allow(ctx context.Context, email, ip string) error
+	// RecordFailure counts a failed login, backing off or locking the
+	// account or IP once they have failed too often.
+	RecordFailure(ctx context.Context, email, ip string)
+	// RecordSuccess clears the failures of the account.
+	RecordSuccess(ctx context.Context, email string)
+}
+
+type loginThrottleService struct {
+	cache         CacheService
+	userRepo      repository.UserRepository
+	notifications NotificationService
+	auditLog      AuditLogService
+	config        *config.LoginThrottleConfig
+}
+
+func NewLoginThrottleService(cache CacheService, userRepo repository.UserRepository, notifications NotificationService, auditLog AuditLogService, cfg *config.LoginThrottleConfig) LoginThrottleService {
+	return &loginThrottleService{
+		cache:         cache,
+		userRepo:      userRepo,
+		notifications: notifications,
+		auditLog:      auditLog,
+		config:        cfg,
+	}
+}
+
+func (s *loginThrottleService) Allow(ctx context.Context, email, ip string) error {
+	account := accountKey(email)
+	checks := []struct {
+		key string
+		err error
+	}{
+		{"login:lock:ip:" + ip, ErrLoginThrottled},
+		{"login:lock:account:" + account, ErrAccountLocked},
+		{"login:wait:account:" + account, ErrLoginThrottled},
+	}
+	for _, check := range checks {
+		if wait := s.remaining(ctx, check.key); wait > 0 {
+			return &LoginBlockedError{Err: check.err, RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+func (s *loginThrottleService) RecordFailure(ctx context.Context, email, ip string) {
+	account := accountKey(email)
+
+	failures, err := s.cache.Increment(ctx, "login:failures:account:"+account, s.config.FailureWindow)
+	if err != nil {
+		log.Printf("Error counting failed logins for %s: %v", account, err)
+	} else if failures >= int64(s.config.MaxAccountFailures) {
+		s.lockAccount(ctx, email, ip, failures)
+	} else {
+		s.block(ctx, "login:wait:account:"+account, s.backoff(failures))
+	}
+
+	failures, err = s.cache.Increment(ctx, "login:failures:ip:"+ip, s.config.FailureWindow)
+	if err != nil {
+		log.Printf("Error counting failed logins from %s: %v", ip, err)
+	} else if failures >= int64(s.config.MaxIPFailures) {
+		s.lockIP(ctx, ip, failures)
+	}
+}
+
+func (s *loginThrottleService) RecordSuccess(ctx context.Context, email string) {
+	account := accountKey(email)
+	for _, key := range []string{"login:failures:account:" + account, "login:wait:account:" + account} {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			log.Printf("Error clearing failed logins for %s: %v", account, err)
+		}
+	}
+}
+
+// backoff is the wait after the given number of consecutive failures:
+// BaseBackoff, doubled for each further failure, up to MaxBackoff.
+func (s *loginThrottleService) backoff(failures int64) time.Duration {
+	wait := s.config.BaseBackoff
+	for i := int64(1); i < failures && wait < s.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > s.config.MaxBackoff {
+		wait = s.config.MaxBackoff
+	}
+	return wait
+}
+
+func (s *loginThrottleService) lockAccount(ctx context.Context, email, ip string, failures int64) {
+	account := accountKey(email)
+	until := s.block(ctx, "login:lock:account:"+account, s.config.LockoutDuration)
+	s.cache.Delete(ctx, "login:failures:account:"+account)
+
+	entityID := account
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		entityID = user.ID.String()
+		err := s.notifications.Notify(ctx, user.ID, NotificationAccountLocked, map[string]string{
+			"LockedUntil": until.UTC().Format("January 2, 2006 15:04 MST"),
+		})
+		if err != nil {
+			log.Printf("Error notifying %s of lockout: %v", user.Email, err)
+		}
+	}
+
+	s.audit(ctx, ip, AuditEntry{
+		Action:     "LOCKOUT",
+		EntityType: "user",
+		EntityID:   entityID,
+		Details:    fmt.Sprintf("Account %s locked until %s after %d failed logins", account, until.UTC().Format(time.RFC3339), failures),
+	})
+}
+
+func (s *loginThrottleService) lockIP(ctx context.Context, ip string, failures int64) {
+	until := s.block(ctx, "login:lock:ip:"+ip, s.config.LockoutDuration)
+	s.cache.Delete(ctx, "login:failures:ip:"+ip)
+
+	s.audit(ctx, ip, AuditEntry{
+		Action:     "LOCKOUT",
+		EntityType: "ip",
+		EntityID:   ip,
+		Details:    fmt.Sprintf("Logins from %s blocked until %s after %d failed logins", ip, until.UTC().Format(time.RFC3339), failures),
+	})
+}
+
+func (s *loginThrottleService) audit(ctx context.Context, ip string, entry AuditEntry) {
+	if err := s.auditLog.CreateAuditLog(WithAuditRequest(ctx, ip, nil), entry); err != nil {
+		log.Printf("Error recording lockout of %s %s: %v", entry.EntityType, entry.EntityID, err)
+	}
+}
+
+// block stores when the block at key ends and returns it.
+func (s *loginThrottleService) block(ctx context.Context, key string, duration time.Duration) time.Time {
+	until := time.Now().Add(duration)
+	if err := s.cache.Set(ctx, key, until.UnixMilli(), duration); err != nil {
+		log.Printf("Error storing login block %s: %v", key, err)
+	}
+	return until
+}
+
+// remaining returns how long the block at key has left, or zero.
+func (s *loginThrottleService) remaining(ctx context.Context, key string) time.Duration {
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return 0
+	}
+	until, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Until(time.UnixMilli(until))
+}
+
+func accountKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/geo"
+	"landmark-api/internal/repository"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	matchCandidateLimit  = 50
+	matchMinSimilarity   = 0.2
+	matchMaxDistanceKm   = 5.0
+	matchMinConfidence   = 0.3
+	matchDefaultResults  = 5
+	matchMaxResults      = 20
+	matchNameWeight      = 0.5
+	matchDistanceWeight  = 0.35
+	matchCityWeight      = 0.15
+	matchConfidenceScale = 1000
+)
+
+// MatchRecord is a partner's landmark record to resolve against our catalog.
+type MatchRecord struct {
+	Name      string   `json:"name"`
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	// IncludeUnpublished also matches draft and archived landmarks, which
+	// the public API does not show.
+	IncludeUnpublished bool `json:"-"`
+}
+
+// LandmarkMatch is one of our landmarks that may correspond to a MatchRecord.
+type LandmarkMatch struct {
+	LandmarkID uuid.UUID `json:"landmark_id"`
+	Name       string    `json:"name"`
+	City       string    `json:"city"`
+	Country    string    `json:"country"`
+	Confidence float64   `json:"confidence"`
+	DistanceKm *float64  `json:"distance_km,omitempty"`
+}
+
+// MatchService resolves partner landmark records to our landmark IDs.
+type MatchService interface {
+	Match(ctx context.Context, record MatchRecord, limit int) ([]LandmarkMatch, error)
+}
+
+type matchService struct {
+	landmarkRepo repository.LandmarkRepository
+}
+
+func NewMatchService(landmarkRepo repository.LandmarkRepository) MatchService {
+	return &matchService{landmarkRepo: landmarkRepo}
+}
+
+// Match returns our best candidates for record, most confident first.
+// Confidence blends name similarity, distance and city agreement, using only
+// the signals the record provides.
+func (s *matchService) Match(ctx context.Context, record MatchRecord, limit int) ([]LandmarkMatch, error) {
+	if limit <= 0 {
+		limit = matchDefaultResults
+	}
+	if limit > matchMaxResults {
+		limit = matchMaxResults
+	}
+
+	hasCoords := record.Latitude != nil && record.Longitude != nil
+	query := repository.MatchCandidateQuery{
+		Name:          strings.TrimSpace(record.Name),
+		MinSimilarity: matchMinSimilarity,
+		Limit:         matchCandidateLimit,
+
+		IncludeUnpublished: record.IncludeUnpublished,
+	}
+	if hasCoords {
+		query.HasBounds = true
+		query.MinLat, query.MaxLat, query.MinLon, query.MaxLon = geo.BoundingBox(*record.Latitude, *record.Longitude, matchMaxDistanceKm)
+	}
+
+	candidates, err := s.landmarkRepo.FindMatchCandidates(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error finding match candidates: %w", err)
+	}
+
+	city := strings.ToLower(strings.TrimSpace(record.City))
+	matches := make([]LandmarkMatch, 0, len(candidates))
+	for _, candidate := range candidates {
+		score := candidate.NameSimilarity * matchNameWeight
+		weight := matchNameWeight
+
+		match := LandmarkMatch{
+			LandmarkID: candidate.ID,
+			Name:       candidate.Name,
+			City:       candidate.City,
+			Country:    candidate.Country,
+		}
+
+		if hasCoords {
+			distance := geo.HaversineKm(*record.Latitude, *record.Longitude, candidate.Latitude, candidate.Longitude)
+			score += math.Max(0, 1-distance/matchMaxDistanceKm) * matchDistanceWeight
+			weight += matchDistanceWeight
+			match.DistanceKm = &distance
+		}
+
+		if city != "" {
+			if strings.ToLower(strings.TrimSpace(candidate.City)) == city {
+				score += matchCityWeight
+			}
+			weight += matchCityWeight
+		}
+
+		match.Confidence = math.Round(score/weight*matchConfidenceScale) / matchConfidenceScale
+		if match.Confidence >= matchMinConfidence {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MemoryCacheService is a CacheService kept in this instance's memory,
+// which behaves like Redis: values are stored as JSON, a missing key is
+// redis.Nil and a zero expiration never expires. It holds up to maxEntries
+// keys; new keys are dropped when it is full of unexpired ones.
+type MemoryCacheService struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value string
+	// expiresAt is zero for keys that never expire.
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func NewMemoryCacheService(maxEntries int) *MemoryCacheService {
+	return &MemoryCacheService{maxEntries: maxEntries, entries: make(map[string]memoryEntry)}
+}
+
+func (c *MemoryCacheService) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return "", redis.Nil
+	}
+	return entry.value, nil
+}
+
+func (c *MemoryCacheService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %v", err)
+	}
+	entry := memoryEntry{value: string(jsonData)}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store(key, entry)
+	return nil
+}
+
+func (c *MemoryCacheService) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *MemoryCacheService) DeleteByPattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if matched, _ := path.Match(pattern, key); matched {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCacheService) Increment(ctx context.Context, key string, window time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	entry, ok := c.entries[key]
+	if !ok || entry.expired(now) {
+		entry = memoryEntry{value: "0", expiresAt: now.Add(window)}
+	}
+	count, err := strconv.ParseInt(entry.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of %s is not a counter", key)
+	}
+	count++
+	entry.value = strconv.FormatInt(count, 10)
+	c.store(key, entry)
+	return count, nil
+}
+
+// Clear forgets every key.
+func (c *MemoryCacheService) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]memoryEntry)
+}
+
+// store saves entry at key, making room by dropping expired keys when the
+// cache is full. c.mu must be held.
+func (c *MemoryCacheService) store(key string, entry memoryEntry) {
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for key, entry := range c.entries {
+			if entry.expired(now) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = entry
+}
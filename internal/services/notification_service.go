@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService tells users about their quota, submissions, billing
+// and account security, in their in-app feed and by email.
+type NotificationService interface {
+	// Notify renders the named template with data, adds the notification
+	// to the user's feed and emails it when the template asks for that. A
+	// failed email is logged; the notification stays in the feed.
+	Notify(ctx context.Context, userID uuid.UUID, name string, data map[string]string) error
+	// List returns a page of the user's notifications, newest first, with
+	// the number of them in total and unread.
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) (notifications []models.Notification, total, unread int64, err error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	// Subscribe calls listener after each notification made from the named
+	// template. Listeners are added at startup, before notifications are
+	// sent.
+	Subscribe(name string, listener NotificationListener)
+}
+
+// NotificationListener reacts to a notification sent to a user.
+type NotificationListener func(ctx context.Context, userID uuid.UUID)
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	emailService     EmailService
+	listeners        map[string][]NotificationListener
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, emailService EmailService) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		emailService:     emailService,
+		listeners:        make(map[string][]NotificationListener),
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, name string, data map[string]string) error {
+	tmpl, ok := notificationTemplates[name]
+	if !ok {
+		return fmt.Errorf("unknown notification template %q", name)
+	}
+
+	var title, body bytes.Buffer
+	if err := tmpl.title.Execute(&title, data); err != nil {
+		return fmt.Errorf("error rendering %s notification: %w", name, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return fmt.Errorf("error rendering %s notification: %w", name, err)
+	}
+
+	notification := &models.Notification{
+		UserID: userID,
+		Kind:   tmpl.kind,
+		Type:   name,
+		Title:  title.String(),
+		Body:   body.String(),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+	defer func() {
+		for _, listener := range s.listeners[name] {
+			listener(ctx, userID)
+		}
+	}()
+
+	if tmpl.email {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			log.Printf("Error getting user %s to email %s notification: %v", userID, name, err)
+			return nil
+		}
+		if err := s.emailService.SendNotification(ctx, user.Email, tmpl.subject, notification.Title, notification.Body); err != nil {
+			log.Printf("Error emailing %s notification to user %s: %v", name, userID, err)
+		}
+	}
+	return nil
+}
+
+func (s *notificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, int64, error) {
+	return s.notificationRepo.List(ctx, userID, unreadOnly, limit, offset)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, id, userID, time.Now())
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.notificationRepo.MarkAllRead(ctx, userID, time.Now())
+}
+
+func (s *notificationService) Subscribe(name string, listener NotificationListener) {
+	s.listeners[name] = append(s.listeners[name], listener)
+}
+
+// NotifySubmissionReviewed tells the submitter of submission whether it was
+// approved. Anonymous submissions and imports have no one to tell.
+func NotifySubmissionReviewed(ctx context.Context, notifications NotificationService, submission *models.SubmissionLandmark, approved bool) error {
+	if submission.UserID == nil {
+		return nil
+	}
+	name := NotificationSubmissionRejected
+	if approved {
+		name = NotificationSubmissionApproved
+	}
+	return notifications.Notify(ctx, *submission.UserID, name, map[string]string{
+		"Name": submission.Name,
+		"City": submission.City,
+	})
+}
+
+// NotificationDate formats a date for notification templates.
+func NotificationDate(t time.Time) string {
+	return t.Format("January 2, 2006")
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"landmark-api/internal/models"
+	"text/template"
+)
+
+// Notification templates, named by the Type they give their notifications.
+const (
+	NotificationQuotaWarning         = "quota_warning"
+	NotificationQuotaExceeded        = "quota_exceeded"
+	NotificationSubmissionApproved   = "submission_approved"
+	NotificationSubmissionRejected   = "submission_rejected"
+	NotificationSubscriptionCanceled = "subscription_canceled"
+	NotificationSubscriptionResumed  = "subscription_resumed"
+	NotificationTrialEnding          = "trial_ending"
+	NotificationAccountLocked        = "account_locked"
+	NotificationAPIKeyExpiring       = "api_key_expiring"
+	NotificationOnboardingWelcome    = "onboarding_welcome"
+	NotificationOnboardingTips       = "onboarding_tips"
+	NotificationOnboardingQuota      = "onboarding_quota"
+	NotificationUsageStatement       = "usage_statement"
+)
+
+// notificationTemplate renders one type of notification. Title and body
+// are text/templates over the data passed to Notify; the body's paragraphs
+// are separated by blank lines. Subject is the email subject, and Email
+// says whether the notification is emailed as well as shown in the feed.
+type notificationTemplate struct {
+	kind    models.NotificationKind
+	subject string
+	title   *template.Template
+	body    *template.Template
+	email   bool
+}
+
+func newNotificationTemplate(kind models.NotificationKind, subject, title, body string, email bool) notificationTemplate {
+	return notificationTemplate{
+		kind:    kind,
+		subject: subject,
+		title:   template.Must(template.New("title").Option("missingkey=error").Parse(title)),
+		body:    template.Must(template.New("body").Option("missingkey=error").Parse(body)),
+		email:   email,
+	}
+}
+
+var notificationTemplates = map[string]notificationTemplate{
+	NotificationQuotaWarning: newNotificationTemplate(models.NotificationQuota,
+		"You have used most of your Landmark API requests",
+		"You've used {{.Percent}}% of your requests",
+		"You have made {{.Used}} of the {{.Limit}} requests your plan includes this period. Your quota resets on {{.ResetsAt}}.\n\nUpgrade your subscription from your dashboard for a higher quota.",
+		true),
+	NotificationQuotaExceeded: newNotificationTemplate(models.NotificationQuota,
+		"You have used all your Landmark API requests",
+		"You've reached your request quota",
+		"You have made all {{.Limit}} requests your plan includes this period, so further requests are rejected until {{.ResetsAt}}.\n\nUpgrade your subscription from your dashboard for a higher quota.",
+		true),
+	NotificationSubmissionApproved: newNotificationTemplate(models.NotificationSubmission,
+		"Your landmark submission was approved",
+		"Your submission was approved",
+		"Thanks for contributing! {{.Name}} in {{.City}} has been reviewed and is now published.",
+		true),
+	NotificationSubmissionRejected: newNotificationTemplate(models.NotificationSubmission,
+		"Your landmark submission was not accepted",
+		"Your submission was not accepted",
+		"{{.Name}} in {{.City}} has been reviewed and was not accepted. Thanks for contributing all the same.",
+		true),
+	NotificationSubscriptionCanceled: newNotificationTemplate(models.NotificationBilling,
+		"Your Landmark API subscription has been canceled",
+		"Your subscription has been canceled",
+		"We're sorry to see you go. Your plan stays active until {{.AccessUntil}}, after which it will not renew.\n\nChanged your mind? You can resume your subscription any time before then from your dashboard.",
+		true),
+	NotificationSubscriptionResumed: newNotificationTemplate(models.NotificationBilling,
+		"Your Landmark API subscription has been resumed",
+		"Welcome back!",
+		"Your subscription has been resumed and will renew on {{.RenewsAt}}.",
+		true),
+	NotificationTrialEnding: newNotificationTemplate(models.NotificationBilling,
+		"Your Landmark API Pro trial ends soon",
+		"Your Pro trial ends soon",
+		"Your free trial of Landmark API Pro ends on {{.TrialEndsAt}}. Your card will be charged then and your Pro access will continue without interruption.\n\nIf you don't want to continue, cancel from your dashboard before the trial ends.",
+		true),
+	NotificationAccountLocked: newNotificationTemplate(models.NotificationSecurity,
+		"Your Landmark API account has been locked",
+		"Your account has been temporarily locked",
+		"We blocked sign-ins to your account after several failed login attempts. You can sign in again after {{.LockedUntil}}.\n\nIf this wasn't you, someone may be trying to guess your password. Consider changing it once the lock expires.",
+		true),
+	NotificationAPIKeyExpiring: newNotificationTemplate(models.NotificationSecurity,
+		"Your Landmark API key expires soon",
+		"Your API key expires soon",
+		"Your Landmark API key expires on {{.ExpiresAt}}. Requests made with it will be rejected after that.\n\nRotate it from your dashboard to get a new key; the current one keeps working for a short grace period so you can switch over.",
+		true),
+	NotificationOnboardingWelcome: newNotificationTemplate(models.NotificationOnboarding,
+		"Getting started with Landmark API",
+		"Welcome to Landmark API",
+		"You'll find your API key on your dashboard; send it in the X-API-Key header of each request, for example to GET /api/v1/landmarks.\n\nThe interactive documentation at /swagger lists every endpoint with example responses, so you can try them out before writing any code.",
+		true),
+	NotificationOnboardingTips: newNotificationTemplate(models.NotificationOnboarding,
+		"Three tips for using Landmark API",
+		"Get more out of Landmark API",
+		"Ask only for the fields you need with the fields parameter, and use format=compact for smaller list responses.\n\nApps that keep a local copy of the catalog can call GET /api/v1/sync with the checkpoint of their last sync to fetch only what changed since.\n\nKeep an eye on the X-RateLimit-Remaining header, or on your dashboard, to see how much of your quota is left.",
+		true),
+	NotificationOnboardingQuota: newNotificationTemplate(models.NotificationOnboarding,
+		"Making the most of your Landmark API quota",
+		"Your app is getting busy",
+		"You're close to your plan's request quota for the first time, which usually means your integration is taking off.\n\nCaching responses and batching lookups go a long way; when you need more, a paid plan raises the quota and you can upgrade from your dashboard at any time.",
+		true),
+	NotificationUsageStatement: newNotificationTemplate(models.NotificationBilling,
+		"Your Landmark API usage statement",
+		"Your usage statement is ready",
+		"From {{.PeriodStart}} to {{.PeriodEnd}} you made {{.Requests}} requests on the {{.Plan}} plan{{if .Overage}}, {{.Overage}} of them past its quota{{end}}.{{if .TopEndpoint}} Your most requested endpoint was {{.TopEndpoint}}.{{end}}\n\nDownload the full statement as CSV or PDF from your dashboard.",
+		true),
+}
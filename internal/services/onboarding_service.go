@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// onboardingBatchSize is how many users one run of SendTips loads at a
+// time.
+const onboardingBatchSize = 100
+
+// OnboardingService sends new users a short sequence of emails through
+// their notifications: a welcome with API key instructions when they
+// register, tips a few days later, and a notice the first time they near
+// their quota. Each step is sent once, and none to users who opted out.
+type OnboardingService interface {
+	// UserRegistered starts the sequence for a new user.
+	UserRegistered(ctx context.Context, userID uuid.UUID)
+	// QuotaNearing sends the quota step. It listens to quota warning
+	// notifications.
+	QuotaNearing(ctx context.Context, userID uuid.UUID)
+	// SendTips sends the tips step to users welcomed long enough before
+	// now, returning how many were sent it.
+	SendTips(ctx context.Context, now time.Time) (int, error)
+}
+
+type onboardingService struct {
+	onboardingRepo repository.OnboardingRepository
+	userRepo       repository.UserRepository
+	notifications  NotificationService
+	// tipsAfter is how long after registering users are sent tips.
+	tipsAfter time.Duration
+}
+
+func NewOnboardingService(onboardingRepo repository.OnboardingRepository, userRepo repository.UserRepository, notifications NotificationService, tipsAfter time.Duration) OnboardingService {
+	return &onboardingService{
+		onboardingRepo: onboardingRepo,
+		userRepo:       userRepo,
+		notifications:  notifications,
+		tipsAfter:      tipsAfter,
+	}
+}
+
+func (s *onboardingService) UserRegistered(ctx context.Context, userID uuid.UUID) {
+	s.send(ctx, userID, models.OnboardingWelcome, NotificationOnboardingWelcome)
+}
+
+func (s *onboardingService) QuotaNearing(ctx context.Context, userID uuid.UUID) {
+	s.send(ctx, userID, models.OnboardingQuota, NotificationOnboardingQuota)
+}
+
+func (s *onboardingService) SendTips(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		userIDs, err := s.onboardingRepo.ListDue(ctx, models.OnboardingTips, models.OnboardingWelcome, now.Add(-s.tipsAfter), onboardingBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		batchSent := 0
+		for _, userID := range userIDs {
+			if s.send(ctx, userID, models.OnboardingTips, NotificationOnboardingTips) {
+				batchSent++
+			}
+		}
+		sent += batchSent
+		// Users that failed are still due, so stop rather than load them
+		// again; the next run retries them
+		if len(userIDs) < onboardingBatchSize || batchSent == 0 {
+			return sent, nil
+		}
+	}
+}
+
+// send notifies the user with the template of step, unless they opted out
+// or were already sent it, and reports whether it did. The step is claimed
+// first, so it is not sent twice even if notifying fails.
+func (s *onboardingService) send(ctx context.Context, userID uuid.UUID, step, name string) bool {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user %s for onboarding %s email: %v", userID, step, err)
+		return false
+	}
+	if user.OnboardingOptOut {
+		return false
+	}
+
+	claimed, err := s.onboardingRepo.Claim(ctx, userID, step, time.Now())
+	if err != nil {
+		log.Printf("Error recording onboarding %s email of user %s: %v", step, userID, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+	if err := s.notifications.Notify(ctx, userID, name, nil); err != nil {
+		log.Printf("Error sending onboarding %s email to user %s: %v", step, userID, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/geo"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownOSMCategory = errors.New("unknown OpenStreetMap category")
+	ErrNoOSMCategories    = errors.New("at least one category is required")
+	ErrImportAreaTooLarge = errors.New("import area is too large")
+	ErrImportAntimeridian = errors.New("import area must not cross the antimeridian")
+)
+
+// osmCategory is the OpenStreetMap tag that selects a kind of point, and the
+// landmark category its submissions are filed under.
+type osmCategory struct {
+	key      string
+	value    string
+	category string
+}
+
+// osmCategories are the kinds of points an import may select.
+var osmCategories = map[string]osmCategory{
+	"museum":              {"tourism", "museum", "Museum"},
+	"gallery":             {"tourism", "gallery", "Museum"},
+	"castle":              {"historic", "castle", "Historical"},
+	"fort":                {"historic", "fort", "Historical"},
+	"ruins":               {"historic", "ruins", "Historical"},
+	"archaeological_site": {"historic", "archaeological_site", "Historical"},
+	"monument":            {"historic", "monument", "Monument"},
+	"memorial":            {"historic", "memorial", "Monument"},
+	"place_of_worship":    {"amenity", "place_of_worship", "Religious"},
+	"viewpoint":           {"tourism", "viewpoint", "Nature"},
+	"park":                {"leisure", "park", "Nature"},
+	"attraction":          {"tourism", "attraction", "Architecture"},
+}
+
+// OSMCategories returns the categories an import may select, sorted.
+func OSMCategories() []string {
+	names := make([]string, 0, len(osmCategories))
+	for name := range osmCategories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OSMImportService imports OpenStreetMap points of interest from the
+// Overpass API as landmark submissions. Imports are queued and run in the
+// background, as large areas take longer than a request may.
+type OSMImportService interface {
+	CreateImport(ctx context.Context, adminID uuid.UUID, bounds geo.BBox, categories []string) (*models.OSMImport, error)
+	GetImport(ctx context.Context, id uuid.UUID) (*models.OSMImport, error)
+	// ProcessPendingImports runs queued imports until none are left.
+	ProcessPendingImports(ctx context.Context) error
+}
+
+type osmImportService struct {
+	importRepo   repository.OSMImportRepository
+	matchService MatchService
+	geocoder     GeocodingProvider
+	client       *http.Client
+	config       *config.OverpassConfig
+}
+
+func NewOSMImportService(importRepo repository.OSMImportRepository, matchService MatchService, geocoder GeocodingProvider, client *http.Client, cfg *config.OverpassConfig) OSMImportService {
+	return &osmImportService{
+		importRepo:   importRepo,
+		matchService: matchService,
+		geocoder:     geocoder,
+		client:       client,
+		config:       cfg,
+	}
+}
+
+func (s *osmImportService) CreateImport(ctx context.Context, adminID uuid.UUID, bounds geo.BBox, categories []string) (*models.OSMImport, error) {
+	if len(categories) == 0 {
+		return nil, ErrNoOSMCategories
+	}
+	selected := models.StringList{}
+	for _, name := range categories {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := osmCategories[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownOSMCategory, name)
+		}
+		if !selected.Contains(name) {
+			selected = append(selected, name)
+		}
+	}
+	if bounds.MinLon > bounds.MaxLon {
+		return nil, ErrImportAntimeridian
+	}
+	if (bounds.MaxLon-bounds.MinLon)*(bounds.MaxLat-bounds.MinLat) > s.config.MaxArea {
+		return nil, fmt.Errorf("%w: at most %g square degrees", ErrImportAreaTooLarge, s.config.MaxArea)
+	}
+
+	osmImport := &models.OSMImport{
+		AdminID:    adminID,
+		BBox:       bounds.String(),
+		Categories: selected,
+		Status:     models.ImportPending,
+	}
+	if err := s.importRepo.Create(ctx, osmImport); err != nil {
+		return nil, fmt.Errorf("error creating OpenStreetMap import: %w", err)
+	}
+	return osmImport, nil
+}
+
+func (s *osmImportService) GetImport(ctx context.Context, id uuid.UUID) (*models.OSMImport, error) {
+	return s.importRepo.GetByID(ctx, id)
+}
+
+func (s *osmImportService) ProcessPendingImports(ctx context.Context) error {
+	for {
+		osmImport, err := s.importRepo.ClaimPending(ctx)
+		if err != nil {
+			return fmt.Errorf("error claiming OpenStreetMap import: %w", err)
+		}
+		if osmImport == nil {
+			return nil
+		}
+
+		created, duplicates, skipped, err := s.run(ctx, osmImport)
+		if err != nil {
+			if failErr := s.importRepo.Fail(ctx, osmImport.ID, err.Error()); failErr != nil {
+				return fmt.Errorf("error marking import %s failed: %w", osmImport.ID, failErr)
+			}
+			continue
+		}
+
+		if err := s.importRepo.Complete(ctx, osmImport.ID, created, duplicates, skipped); err != nil {
+			return fmt.Errorf("error completing import %s: %w", osmImport.ID, err)
+		}
+	}
+}
+
+type osmElement struct {
+	Type   string  `json:"type"`
+	ID     int64   `json:"id"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Center *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"center"`
+	Tags map[string]string `json:"tags"`
+}
+
+// source identifies the element for the submission made from it.
+func (e osmElement) source() string {
+	return fmt.Sprintf("osm:%s/%d", e.Type, e.ID)
+}
+
+// position is the element's location, the center for ways and relations.
+func (e osmElement) position() (lat, lon float64) {
+	if e.Center != nil {
+		return e.Center.Lat, e.Center.Lon
+	}
+	return e.Lat, e.Lon
+}
+
+// category returns the landmark category of the first selected category
+// the element has the tag of.
+func (e osmElement) category(selected []string) string {
+	for _, name := range selected {
+		if c := osmCategories[name]; e.Tags[c.key] == c.value {
+			return c.category
+		}
+	}
+	return ""
+}
+
+// run imports the points of osmImport, returning how many submissions it
+// created and how many points were duplicates or lacked the data a
+// landmark needs.
+func (s *osmImportService) run(ctx context.Context, osmImport *models.OSMImport) (created, duplicates, skipped int, err error) {
+	bounds, err := geo.ParseBBox(osmImport.BBox)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	elements, err := s.query(ctx, bounds, osmImport.Categories)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("error querying Overpass: %w", err)
+	}
+
+	for _, element := range elements {
+		category := element.category(osmImport.Categories)
+		if element.Tags["name"] == "" || category == "" {
+			skipped++
+			continue
+		}
+
+		duplicate, err := s.isDuplicate(ctx, element)
+		if err != nil {
+			return created, duplicates, skipped, err
+		}
+		if duplicate {
+			duplicates++
+			continue
+		}
+
+		submission := s.submission(ctx, element, category)
+		if submission == nil {
+			skipped++
+			continue
+		}
+		if err := s.importRepo.CreateSubmission(ctx, submission); err != nil {
+			return created, duplicates, skipped, fmt.Errorf("error creating submission for %s: %w", submission.Source, err)
+		}
+		created++
+	}
+	return created, duplicates, skipped, nil
+}
+
+// query fetches the named points of the selected categories in bounds.
+func (s *osmImportService) query(ctx context.Context, bounds geo.BBox, categories []string) ([]osmElement, error) {
+	// Overpass boxes are south,west,north,east
+	area := fmt.Sprintf("(%f,%f,%f,%f)", bounds.MinLat, bounds.MinLon, bounds.MaxLat, bounds.MaxLon)
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "[out:json][timeout:%d];(", int(s.config.QueryTimeout.Seconds()))
+	for _, name := range categories {
+		category := osmCategories[name]
+		fmt.Fprintf(&query, `nwr["name"][%q=%q]%s;`, category.key, category.value, area)
+	}
+	fmt.Fprintf(&query, ");out center tags %d;", s.config.MaxResults)
+
+	var response struct {
+		Elements []osmElement `json:"elements"`
+	}
+	if err := getJSON(ctx, s.client, s.config.URL+"?data="+url.QueryEscape(query.String()), s.config.UserAgent, &response); err != nil {
+		return nil, err
+	}
+	return response.Elements, nil
+}
+
+// isDuplicate reports whether element was imported before or matches one
+// of our landmarks, published or not.
+func (s *osmImportService) isDuplicate(ctx context.Context, element osmElement) (bool, error) {
+	exists, err := s.importRepo.SubmissionSourceExists(ctx, element.source())
+	if err != nil || exists {
+		return exists, err
+	}
+
+	lat, lon := element.position()
+	matches, err := s.matchService.Match(ctx, MatchRecord{Name: element.Tags["name"], Latitude: &lat, Longitude: &lon, IncludeUnpublished: true}, 1)
+	if err != nil {
+		return false, err
+	}
+	return len(matches) > 0 && matches[0].Confidence >= s.config.DuplicateConfidence, nil
+}
+
+// submission maps element to a pending submission, or returns nil when its
+// city or country cannot be found.
+func (s *osmImportService) submission(ctx context.Context, element osmElement, category string) *models.SubmissionLandmark {
+	lat, lon := element.position()
+	city, country := s.locate(ctx, element, lat, lon)
+	if city == "" || country == "" {
+		return nil
+	}
+
+	submission := &models.SubmissionLandmark{
+		Name:        truncate(strings.TrimSpace(element.Tags["name"]), 255),
+		Description: element.Tags["description"],
+		Latitude:    lat,
+		Longitude:   lon,
+		Country:     truncate(country, 100),
+		City:        truncate(city, 100),
+		Category:    category,
+		Status:      "pending",
+		Source:      element.source(),
+	}
+	if image := element.Tags["image"]; strings.HasPrefix(image, "https://") || strings.HasPrefix(image, "http://") {
+		submission.Images = []models.SubmissionLandmarkImage{{ImageURL: truncate(image, 500)}}
+	}
+	if wheelchair := element.Tags["wheelchair"]; wheelchair != "" {
+		submission.Detail.AccessibilityInfo = "Wheelchair accessible: " + wheelchair
+	}
+	return submission
+}
+
+// locate returns the city and country at a point, reverse geocoding it
+// because OpenStreetMap points rarely carry a full address. The city tag
+// is used when the provider has none.
+func (s *osmImportService) locate(ctx context.Context, element osmElement, lat, lon float64) (city, country string) {
+	time.Sleep(s.config.GeocodeDelay)
+	result, err := s.geocoder.Reverse(ctx, lat, lon)
+	if err != nil {
+		log.Printf("Error reverse geocoding OpenStreetMap %s/%d: %v", element.Type, element.ID, err)
+	}
+	if result != nil {
+		city, country = result.City, result.Country
+	}
+	if city == "" {
+		city = element.Tags["addr:city"]
+	}
+	return city, country
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
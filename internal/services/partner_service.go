@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/config"
+	apperrors "landmark-api/internal/errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrPartnerAPIRequiresEnterprise = apperrors.New(apperrors.CodeSubscriptionRequired, "The partner API requires an Enterprise subscription")
+
+// PartnerProposal is what became of a landmark a partner proposed.
+type PartnerProposal struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	// Status is pending until the proposal is reviewed, or approved for
+	// trusted partners, whose proposals are published at once.
+	Status     string     `json:"status"`
+	LandmarkID *uuid.UUID `json:"landmark_id,omitempty"`
+}
+
+// PartnerService lets Enterprise data partners propose landmarks, which
+// join the submission queue under the partner's ID.
+type PartnerService interface {
+	// Propose submits a landmark on behalf of user, publishing it without
+	// review when they are a trusted partner.
+	Propose(ctx context.Context, user *models.User, subscription *models.Subscription, submission *models.SubmissionLandmark) (*PartnerProposal, error)
+	// Contributions counts the user's proposals by how their review went.
+	Contributions(ctx context.Context, user *models.User, subscription *models.Subscription) (*models.PartnerContributions, error)
+	// AllContributions counts the proposals of every partner, most
+	// proposals first.
+	AllContributions(ctx context.Context) ([]models.PartnerContributions, error)
+}
+
+type partnerService struct {
+	repo   repository.PartnerRepository
+	config *config.PartnerConfig
+}
+
+func NewPartnerService(repo repository.PartnerRepository, cfg *config.PartnerConfig) PartnerService {
+	return &partnerService{repo: repo, config: cfg}
+}
+
+func (s *partnerService) Propose(ctx context.Context, user *models.User, subscription *models.Subscription, submission *models.SubmissionLandmark) (*PartnerProposal, error) {
+	if subscription.PlanType != models.EnterprisePlan {
+		return nil, ErrPartnerAPIRequiresEnterprise
+	}
+
+	// Partners follow their proposals through their stats rather than a
+	// notification for each review, so UserID is left unset
+	submission.ID = uuid.Nil
+	submission.Status = "pending"
+	submission.Source = ""
+	submission.UserID = nil
+	submission.PartnerID = &user.ID
+	submission.SuggestedCategory = ""
+	submission.CategoryConfidence = 0
+	submission.SuggestedTags = nil
+
+	landmarkID, err := s.repo.CreateSubmission(ctx, submission, s.config.Trusted(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &PartnerProposal{
+		SubmissionID: submission.ID,
+		Status:       submission.Status,
+		LandmarkID:   landmarkID,
+	}, nil
+}
+
+func (s *partnerService) Contributions(ctx context.Context, user *models.User, subscription *models.Subscription) (*models.PartnerContributions, error) {
+	if subscription.PlanType != models.EnterprisePlan {
+		return nil, ErrPartnerAPIRequiresEnterprise
+	}
+
+	contributions, err := s.repo.Contributions(ctx, &user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(contributions) == 0 {
+		return &models.PartnerContributions{PartnerID: user.ID, Email: user.Email}, nil
+	}
+	return &contributions[0], nil
+}
+
+func (s *partnerService) AllContributions(ctx context.Context) ([]models.PartnerContributions, error) {
+	return s.repo.Contributions(ctx, nil)
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"landmark-api/internal/locale"
+	"landmark-api/internal/models"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldPolicy is the part of the landmark representation a plan may see.
+type FieldPolicy struct {
+	// Details adds the detail fields for landmarks that have details.
+	Details bool
+	// Admin adds the editorial status and timestamps.
+	Admin bool
+	// MediaTypes are the kinds of gallery items listed in images.
+	MediaTypes []models.MediaType
+}
+
+// LandmarkDetailFields are the fields of LandmarkDetailView, which Pro and
+// Enterprise plans add to a landmark.
+var LandmarkDetailFields = []string{
+	"opening_hours",
+	"ticket_prices",
+	"historical_significance",
+	"visitor_tips",
+	"accessibility_info",
+	"weather_info",
+	"enrichment",
+}
+
+// PlanFieldPolicies are the fields each plan sees. Plans without a policy
+// see what the free plan does.
+var PlanFieldPolicies = map[models.SubscriptionPlan]FieldPolicy{
+	models.FreePlan: {
+		MediaTypes: []models.MediaType{models.MediaPhoto},
+	},
+	models.ProPlan: {
+		Details:    true,
+		MediaTypes: allMediaTypes,
+	},
+	models.EnterprisePlan: {
+		Details:    true,
+		MediaTypes: allMediaTypes,
+	},
+}
+
+var allMediaTypes = []models.MediaType{models.MediaPhoto, models.MediaVideo, models.MediaPano}
+
+// adminFieldPolicy is what admins see of the landmarks they edit.
+var adminFieldPolicy = FieldPolicy{
+	Details:    true,
+	Admin:      true,
+	MediaTypes: allMediaTypes,
+}
+
+// LandmarkView is the API representation of a landmark. Every plan sees
+// the fields of the landmark itself; the detail fields are added for plans
+// whose FieldPolicy allows them.
+type LandmarkView struct {
+	ID          uuid.UUID           `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Country     string              `json:"country"`
+	City        string              `json:"city"`
+	Category    string              `json:"category"`
+	Latitude    float64             `json:"latitude"`
+	Longitude   float64             `json:"longitude"`
+	ImageURL    string              `json:"image_url"`
+	Images      []LandmarkImageView `json:"images"`
+	Timezone    string              `json:"timezone"`
+	Tags        models.StringList   `json:"tags"`
+	// CountryMetadata holds currency, locale and phone prefix for
+	// formatting, when the country is known.
+	CountryMetadata *locale.Country `json:"country_metadata" extensions:"x-nullable"`
+	// LastVerifiedAt and DataConfidence let clients judge how current the
+	// data is.
+	LastVerifiedAt *time.Time `json:"last_verified_at" extensions:"x-nullable"`
+	DataConfidence float64    `json:"data_confidence"`
+	// DistanceKM is how far the landmark is from the point of a search.
+	DistanceKM *float64 `json:"distance_km,omitempty"`
+
+	// Status and the timestamps are only shown to admins.
+	Status    models.LandmarkStatus `json:"status,omitempty"`
+	CreatedAt *time.Time            `json:"created_at,omitempty"`
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"`
+
+	*LandmarkDetailView
+}
+
+// LandmarkDetailView is the API representation of a landmark's details.
+type LandmarkDetailView struct {
+	OpeningHours           map[string]string `json:"opening_hours"`
+	TicketPrices           map[string]string `json:"ticket_prices"`
+	HistoricalSignificance string            `json:"historical_significance"`
+	VisitorTips            string            `json:"visitor_tips"`
+	AccessibilityInfo      string            `json:"accessibility_info"`
+	// WeatherInfo and Enrichment are looked up for each response, and
+	// left out when they are unavailable.
+	WeatherInfo *WeatherData               `json:"weather_info,omitempty"`
+	Enrichment  *models.LandmarkEnrichment `json:"enrichment,omitempty"`
+}
+
+// PlanSerializer builds landmark representations holding the fields the
+// subscription plan's FieldPolicy allows, so handlers need not know which
+// plan sees what.
+type PlanSerializer interface {
+	// Landmark returns the fields of landmark plan may see, looking up its
+	// details if the plan sees them.
+	Landmark(ctx context.Context, landmark *models.Landmark, plan models.SubscriptionPlan) *LandmarkView
+	// AdminLandmark returns every field of landmark with the given details.
+	AdminLandmark(ctx context.Context, landmark *models.Landmark, details *models.LandmarkDetail) *LandmarkView
+	// AdminLandmarkSummary is AdminLandmark without the weather and
+	// enrichment, which need lookups of their own, for admin lists.
+	AdminLandmarkSummary(landmark *models.Landmark, details *models.LandmarkDetail) *LandmarkView
+}
+
+type planSerializer struct {
+	landmarkService LandmarkService
+	policies        map[models.SubscriptionPlan]FieldPolicy
+}
+
+func NewPlanSerializer(landmarkService LandmarkService) PlanSerializer {
+	return &planSerializer{
+		landmarkService: landmarkService,
+		policies:        PlanFieldPolicies,
+	}
+}
+
+func (s *planSerializer) Landmark(ctx context.Context, landmark *models.Landmark, plan models.SubscriptionPlan) *LandmarkView {
+	policy, ok := s.policies[plan]
+	if !ok {
+		policy = s.policies[models.FreePlan]
+	}
+
+	var details *models.LandmarkDetail
+	if policy.Details {
+		var err error
+		details, err = s.landmarkService.GetLandmarkDetails(ctx, landmark.ID, plan)
+		if err != nil {
+			details = nil
+		}
+	}
+	return s.withLookups(ctx, build(policy, landmark, details), landmark)
+}
+
+func (s *planSerializer) AdminLandmark(ctx context.Context, landmark *models.Landmark, details *models.LandmarkDetail) *LandmarkView {
+	return s.withLookups(ctx, build(adminFieldPolicy, landmark, details), landmark)
+}
+
+func (s *planSerializer) AdminLandmarkSummary(landmark *models.Landmark, details *models.LandmarkDetail) *LandmarkView {
+	return build(adminFieldPolicy, landmark, details)
+}
+
+// withLookups adds the weather and enrichment to a view with details.
+func (s *planSerializer) withLookups(ctx context.Context, view *LandmarkView, landmark *models.Landmark) *LandmarkView {
+	if view.LandmarkDetailView != nil {
+		view.WeatherInfo = s.weather(ctx, landmark)
+		view.Enrichment = s.enrichment(ctx, landmark)
+	}
+	return view
+}
+
+func build(policy FieldPolicy, landmark *models.Landmark, details *models.LandmarkDetail) *LandmarkView {
+	landmark = withMedia(landmark, policy.MediaTypes)
+	view := &LandmarkView{
+		ID:              landmark.ID,
+		Name:            landmark.Name,
+		Description:     landmark.Description,
+		Country:         landmark.Country,
+		City:            landmark.City,
+		Category:        landmark.Category,
+		Latitude:        landmark.Latitude,
+		Longitude:       landmark.Longitude,
+		ImageURL:        landmark.ImageUrl,
+		Images:          NewLandmarkImageViews(landmark.Images),
+		Timezone:        landmark.Timezone,
+		Tags:            tagList(landmark.Tags),
+		CountryMetadata: countryMetadata(landmark.Country),
+		LastVerifiedAt:  landmark.LastVerifiedAt,
+		DataConfidence:  landmark.DataConfidence,
+	}
+	if policy.Admin {
+		view.Status = landmark.Status
+		view.CreatedAt = &landmark.CreatedAt
+		view.UpdatedAt = &landmark.UpdatedAt
+	}
+	if policy.Details && details != nil {
+		view.LandmarkDetailView = &LandmarkDetailView{
+			OpeningHours:           details.OpeningHours,
+			TicketPrices:           details.TicketPrices,
+			HistoricalSignificance: details.HistoricalSignificance,
+			VisitorTips:            details.VisitorTips,
+			AccessibilityInfo:      details.AccessibilityInfo,
+		}
+	}
+	return view
+}
+
+func (s *planSerializer) weather(ctx context.Context, landmark *models.Landmark) *WeatherData {
+	weather, err := FetchWeatherData(ctx, landmark.Latitude, landmark.Longitude)
+	if err != nil {
+		log.Printf("Error fetching weather data: %v", err)
+		return nil
+	}
+	return weather
+}
+
+func (s *planSerializer) enrichment(ctx context.Context, landmark *models.Landmark) *models.LandmarkEnrichment {
+	enrichment, err := s.landmarkService.GetLandmarkEnrichment(ctx, landmark.ID)
+	if err != nil {
+		log.Printf("Error fetching enrichment of landmark %s: %v", landmark.ID, err)
+		return nil
+	}
+	return enrichment
+}
+
+// withMedia returns landmark with only the gallery items of types.
+func withMedia(landmark *models.Landmark, types []models.MediaType) *models.Landmark {
+	allowed := make(map[models.MediaType]bool, len(types))
+	for _, kind := range types {
+		allowed[kind] = true
+	}
+
+	var images []models.LandmarkImage
+	for _, image := range landmark.Images {
+		if allowed[image.Type()] {
+			images = append(images, image)
+		}
+	}
+	if len(images) == len(landmark.Images) {
+		return landmark
+	}
+
+	filtered := *landmark
+	filtered.Images = images
+	if filtered.Images == nil {
+		filtered.Images = []models.LandmarkImage{}
+	}
+	return &filtered
+}
+
+// LandmarkImageView is the API representation of a landmark image. The model
+// hides its ID from JSON, which admin code and audit snapshots rely on; the
+// API exposes it so clients can reference images.
+type LandmarkImageView struct {
+	ID           uuid.UUID `json:"id"`
+	Type         string    `json:"type"`
+	ImageURL     string    `json:"image_url"`
+	Provider     string    `json:"provider,omitempty"`
+	ProviderID   string    `json:"provider_id,omitempty"`
+	EmbedURL     string    `json:"embed_url,omitempty"`
+	StreamURL    string    `json:"stream_url,omitempty"`
+	Caption      string    `json:"caption"`
+	AltText      string    `json:"alt_text"`
+	Credit       string    `json:"credit"`
+	DisplayOrder int       `json:"display_order"`
+	IsPrimary    bool      `json:"is_primary"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewLandmarkImageViews returns the API representation of images in
+// gallery order.
+func NewLandmarkImageViews(images []models.LandmarkImage) []LandmarkImageView {
+	if images == nil {
+		return nil
+	}
+	views := make([]LandmarkImageView, 0, len(images))
+	for _, image := range images {
+		views = append(views, LandmarkImageView{
+			ID:           image.ID,
+			Type:         string(image.Type()),
+			ImageURL:     image.ImageURL,
+			Provider:     string(image.Provider),
+			ProviderID:   image.ProviderID,
+			EmbedURL:     embedURL(image),
+			StreamURL:    image.StreamURL,
+			Caption:      image.Caption,
+			AltText:      image.AltText,
+			Credit:       image.Credit,
+			DisplayOrder: image.DisplayOrder,
+			IsPrimary:    image.IsPrimary,
+			CreatedAt:    image.CreatedAt,
+			UpdatedAt:    image.UpdatedAt,
+		})
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].DisplayOrder != views[j].DisplayOrder {
+			return views[i].DisplayOrder < views[j].DisplayOrder
+		}
+		return views[i].CreatedAt.Before(views[j].CreatedAt)
+	})
+	return views
+}
+
+// tagList returns tags, empty rather than nil for landmarks without any.
+func tagList(tags models.StringList) models.StringList {
+	if tags == nil {
+		return models.StringList{}
+	}
+	return tags
+}
+
+// countryMetadata returns the locale metadata of a landmark's country, or
+// nil when the country is not in the bundled dataset.
+func countryMetadata(name string) *locale.Country {
+	country, ok := locale.Lookup(name)
+	if !ok {
+		return nil
+	}
+	return &country
+}
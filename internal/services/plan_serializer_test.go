@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"landmark-api/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func testLandmark() *models.Landmark {
+	return &models.Landmark{
+		ID:      uuid.New(),
+		Name:    "Eiffel Tower",
+		Country: "France",
+		City:    "Paris",
+		Status:  models.LandmarkPublished,
+		Images: []models.LandmarkImage{
+			{ID: uuid.New(), ImageURL: "https://example.com/photo.jpg", DisplayOrder: 0},
+			{ID: uuid.New(), MediaType: models.MediaVideo, DisplayOrder: 1},
+			{ID: uuid.New(), MediaType: models.MediaPano, ImageURL: "https://example.com/pano.jpg", DisplayOrder: 2},
+		},
+	}
+}
+
+func testLandmarkDetail() *models.LandmarkDetail {
+	return &models.LandmarkDetail{
+		OpeningHours:           map[string]string{"monday": "09:00-18:00"},
+		TicketPrices:           map[string]string{"adult": "29.40 EUR"},
+		HistoricalSignificance: "Built for the 1889 World's Fair",
+		VisitorTips:            "Book ahead",
+		AccessibilityInfo:      "Lifts to the second floor",
+	}
+}
+
+// viewFields returns the JSON fields of view and the media types of its
+// images.
+func viewFields(t *testing.T, view *LandmarkView) (map[string]json.RawMessage, []string) {
+	t.Helper()
+	body, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("error marshaling view: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("error unmarshaling view: %v", err)
+	}
+	var media []string
+	for _, image := range view.Images {
+		media = append(media, image.Type)
+	}
+	return fields, media
+}
+
+// baseFields are the landmark fields every plan sees.
+var baseFields = []string{
+	"id", "name", "description", "country", "city", "category", "latitude",
+	"longitude", "image_url", "images", "timezone", "tags",
+	"country_metadata", "last_verified_at", "data_confidence",
+}
+
+// adminFields are only shown to admins.
+var adminFields = []string{"status", "created_at", "updated_at"}
+
+// storedDetailFields are the detail fields read from the landmark's details;
+// the weather and enrichment are looked up for each response.
+var storedDetailFields = []string{
+	"opening_hours", "ticket_prices", "historical_significance",
+	"visitor_tips", "accessibility_info",
+}
+
+func TestPlanFieldPolicies(t *testing.T) {
+	tests := []struct {
+		plan        models.SubscriptionPlan
+		wantDetails bool
+		wantMedia   []string
+	}{
+		{models.FreePlan, false, []string{"photo"}},
+		{models.ProPlan, true, []string{"photo", "video", "pano"}},
+		{models.EnterprisePlan, true, []string{"photo", "video", "pano"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.plan), func(t *testing.T) {
+			policy, ok := PlanFieldPolicies[tt.plan]
+			if !ok {
+				t.Fatalf("no field policy for %s", tt.plan)
+			}
+			if policy.Admin {
+				t.Errorf("%s sees the admin fields", tt.plan)
+			}
+
+			fields, media := viewFields(t, build(policy, testLandmark(), testLandmarkDetail()))
+			for _, name := range baseFields {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("%s is missing %s", tt.plan, name)
+				}
+			}
+			for _, name := range adminFields {
+				if _, ok := fields[name]; ok {
+					t.Errorf("%s sees admin field %s", tt.plan, name)
+				}
+			}
+			for _, name := range storedDetailFields {
+				if _, ok := fields[name]; ok != tt.wantDetails {
+					t.Errorf("%s sees %s: %v, want %v", tt.plan, name, ok, tt.wantDetails)
+				}
+			}
+			if !slices.Equal(media, tt.wantMedia) {
+				t.Errorf("%s sees media %v, want %v", tt.plan, media, tt.wantMedia)
+			}
+		})
+	}
+}
+
+func TestBuildWithoutDetails(t *testing.T) {
+	fields, _ := viewFields(t, build(PlanFieldPolicies[models.ProPlan], testLandmark(), nil))
+	for _, name := range LandmarkDetailFields {
+		if _, ok := fields[name]; ok {
+			t.Errorf("landmark without details has %s", name)
+		}
+	}
+}
+
+func TestBuildAdmin(t *testing.T) {
+	fields, media := viewFields(t, build(adminFieldPolicy, testLandmark(), testLandmarkDetail()))
+	for _, name := range append(append(append([]string{}, baseFields...), adminFields...), storedDetailFields...) {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("admin view is missing %s", name)
+		}
+	}
+	if want := []string{"photo", "video", "pano"}; !slices.Equal(media, want) {
+		t.Errorf("admin view has media %v, want %v", media, want)
+	}
+}
+
+func TestLandmarkUnknownPlanSeesFree(t *testing.T) {
+	// The free policy has no details, so nothing is looked up
+	serializer := NewPlanSerializer(nil)
+	fields, media := viewFields(t, serializer.Landmark(context.Background(), testLandmark(), models.SubscriptionPlan("legacy")))
+	for _, name := range LandmarkDetailFields {
+		if _, ok := fields[name]; ok {
+			t.Errorf("unknown plan sees %s", name)
+		}
+	}
+	if want := []string{"photo"}; !slices.Equal(media, want) {
+		t.Errorf("unknown plan sees media %v, want %v", media, want)
+	}
+}
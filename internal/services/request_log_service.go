@@ -1,36 +1,65 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"landmark-api/internal/models"
 	"landmark-api/internal/repository"
+	"strconv"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxStreamedExportRange is the largest range exported directly in a
+	// response; larger ranges go through an asynchronous export.
+	MaxStreamedExportRange = 31 * 24 * time.Hour
+	// MaxExportRange is the largest range any export may cover.
+	MaxExportRange = 366 * 24 * time.Hour
 )
 
+var (
+	ErrInvalidExportRange  = errors.New("from must be before to")
+	ErrExportRangeTooLarge = errors.New("export range is too large")
+	ErrExportNotReady      = errors.New("export is not completed")
+)
+
+var requestLogCSVHeader = []string{"id", "timestamp", "method", "endpoint", "status_code", "status", "summary", "trace_id"}
+
 type RequestLogService interface {
-	LogRequest(userID, endpoint, method string, statusCode int, status models.RequestStatus, summary string) error
+	// LogRequest stores entry, timestamped now.
+	LogRequest(entry *models.RequestLog) error
 	GetUserLogs(userID string, from, to time.Time) ([]models.RequestLog, error)
 	GetEndpointLogs(endpoint string, from, to time.Time) ([]models.RequestLog, error)
+	// ExportUserLogsCSV streams the user's logs in the range to w as CSV and
+	// returns the number of rows written.
+	ExportUserLogsCSV(ctx context.Context, userID string, from, to time.Time, w io.Writer) (int64, error)
+	CreateExport(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.RequestLogExport, error)
+	GetExport(ctx context.Context, id, userID uuid.UUID) (*models.RequestLogExport, error)
+	// ProcessPendingExports runs queued exports until none are left.
+	ProcessPendingExports(ctx context.Context) error
 }
 
 type requestLogService struct {
-	repo repository.RequestLogRepository
+	repo       repository.RequestLogRepository
+	exportRepo repository.RequestLogExportRepository
 }
 
-func NewRequestLogService(repo repository.RequestLogRepository) RequestLogService {
-	return &requestLogService{repo: repo}
+func NewRequestLogService(repo repository.RequestLogRepository, exportRepo repository.RequestLogExportRepository) RequestLogService {
+	return &requestLogService{
+		repo:       repo,
+		exportRepo: exportRepo,
+	}
 }
 
-func (s *requestLogService) LogRequest(userID, endpoint, method string, statusCode int, status models.RequestStatus, summary string) error {
-	log := &models.RequestLog{
-		UserID:     userID,
-		Endpoint:   endpoint,
-		Method:     method,
-		Status:     status,
-		StatusCode: statusCode,
-		Summary:    summary,
-		Timestamp:  time.Now(),
-	}
-	return s.repo.Create(log)
+func (s *requestLogService) LogRequest(entry *models.RequestLog) error {
+	entry.Timestamp = time.Now()
+	return s.repo.Create(entry)
 }
 
 func (s *requestLogService) GetUserLogs(userID string, from, to time.Time) ([]models.RequestLog, error) {
@@ -40,3 +69,79 @@ func (s *requestLogService) GetUserLogs(userID string, from, to time.Time) ([]mo
 func (s *requestLogService) GetEndpointLogs(endpoint string, from, to time.Time) ([]models.RequestLog, error) {
 	return s.repo.GetEndpointLogs(endpoint, from, to)
 }
+
+func (s *requestLogService) ExportUserLogsCSV(ctx context.Context, userID string, from, to time.Time, w io.Writer) (int64, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(requestLogCSVHeader); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	err := s.repo.EachUserLog(ctx, userID, from, to, func(log models.RequestLog) error {
+		rows++
+		return writer.Write([]string{
+			strconv.FormatUint(uint64(log.ID), 10),
+			log.Timestamp.UTC().Format(time.RFC3339),
+			log.Method,
+			log.Endpoint,
+			strconv.Itoa(log.StatusCode),
+			string(log.Status),
+			log.Summary,
+			log.TraceID,
+		})
+	})
+	writer.Flush()
+	if err != nil {
+		return rows, err
+	}
+	return rows, writer.Error()
+}
+
+func (s *requestLogService) CreateExport(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.RequestLogExport, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidExportRange
+	}
+	if to.Sub(from) > MaxExportRange {
+		return nil, ErrExportRangeTooLarge
+	}
+
+	export := &models.RequestLogExport{
+		UserID: userID,
+		From:   from,
+		To:     to,
+		Status: models.ExportPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, fmt.Errorf("error creating request log export: %w", err)
+	}
+	return export, nil
+}
+
+func (s *requestLogService) GetExport(ctx context.Context, id, userID uuid.UUID) (*models.RequestLogExport, error) {
+	return s.exportRepo.GetByID(ctx, id, userID)
+}
+
+func (s *requestLogService) ProcessPendingExports(ctx context.Context) error {
+	for {
+		export, err := s.exportRepo.ClaimPending(ctx)
+		if err != nil {
+			return fmt.Errorf("error claiming request log export: %w", err)
+		}
+		if export == nil {
+			return nil
+		}
+
+		var buf bytes.Buffer
+		rows, err := s.ExportUserLogsCSV(ctx, export.UserID.String(), export.From, export.To, &buf)
+		if err != nil {
+			if failErr := s.exportRepo.Fail(ctx, export.ID, err.Error()); failErr != nil {
+				return fmt.Errorf("error marking export %s failed: %w", export.ID, failErr)
+			}
+			continue
+		}
+
+		if err := s.exportRepo.Complete(ctx, export.ID, buf.Bytes(), rows); err != nil {
+			return fmt.Errorf("error completing export %s: %w", export.ID, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers of a signed request. The signature is the hex HMAC-SHA256, keyed
+// with the API key's signing secret, of RequestSigningPayload.
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
+// RequestSigningPayload is the string a request signature covers: the Unix
+// timestamp, method, request URI (path and query) and the hex SHA-256 of
+// the body, separated by newlines.
+func RequestSigningPayload(timestamp int64, method, requestURI string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strconv.FormatInt(timestamp, 10) + "\n" + method + "\n" + requestURI + "\n" + hex.EncodeToString(bodyHash[:])
+}
+
+// SignRequest returns the signature of a request for secret.
+func SignRequest(secret string, timestamp int64, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(RequestSigningPayload(timestamp, method, requestURI, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidRequestSignature reports whether signature is the signature of the
+// request for secret, comparing in constant time.
+func ValidRequestSignature(secret, signature string, timestamp int64, method, requestURI string, body []byte) bool {
+	expected := SignRequest(secret, timestamp, method, requestURI, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"landmark-api/internal/config"
+	"landmark-api/internal/logger"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// archiveBatchSize is how many request logs go into one archive object.
+const archiveBatchSize = 10000
+
+// requestLogArchivePrefix is where request log archives are stored.
+const requestLogArchivePrefix = "request_logs/"
+
+// RetentionService deletes rows older than their table's retention period,
+// archiving request logs first when an archiver is configured.
+type RetentionService interface {
+	Apply(ctx context.Context, now time.Time) error
+}
+
+type retentionService struct {
+	cfg                 *config.RetentionConfig
+	requestLogRepo      repository.RequestLogRepository
+	searchAnalyticsRepo repository.SearchAnalyticsRepository
+	exportRepo          repository.RequestLogExportRepository
+	accountExportRepo   repository.AccountExportRepository
+	archiver            Archiver
+}
+
+// NewRetentionService builds the service; archiver may be nil to delete
+// request logs without archiving them.
+func NewRetentionService(
+	cfg *config.RetentionConfig,
+	requestLogRepo repository.RequestLogRepository,
+	searchAnalyticsRepo repository.SearchAnalyticsRepository,
+	exportRepo repository.RequestLogExportRepository,
+	accountExportRepo repository.AccountExportRepository,
+	archiver Archiver,
+) RetentionService {
+	return &retentionService{
+		cfg:                 cfg,
+		requestLogRepo:      requestLogRepo,
+		searchAnalyticsRepo: searchAnalyticsRepo,
+		exportRepo:          exportRepo,
+		accountExportRepo:   accountExportRepo,
+		archiver:            archiver,
+	}
+}
+
+func (s *retentionService) Apply(ctx context.Context, now time.Time) error {
+	if s.cfg.RequestLogs > 0 {
+		deleted, err := s.expireRequestLogs(ctx, now.Add(-s.cfg.RequestLogs))
+		if err != nil {
+			return fmt.Errorf("error expiring request logs: %w", err)
+		}
+		logRetention("request_logs", deleted)
+	}
+
+	if s.cfg.SearchAnalytics > 0 {
+		deleted, err := s.searchAnalyticsRepo.DeleteBefore(ctx, now.Add(-s.cfg.SearchAnalytics))
+		if err != nil {
+			return fmt.Errorf("error expiring search analytics: %w", err)
+		}
+		logRetention("search_analytics", deleted)
+	}
+
+	if s.cfg.RequestLogExports > 0 {
+		deleted, err := s.exportRepo.DeleteFinishedBefore(ctx, now.Add(-s.cfg.RequestLogExports))
+		if err != nil {
+			return fmt.Errorf("error expiring request log exports: %w", err)
+		}
+		logRetention("request_log_exports", deleted)
+	}
+
+	if s.cfg.AccountExports > 0 {
+		deleted, err := s.accountExportRepo.DeleteFinishedBefore(ctx, now.Add(-s.cfg.AccountExports))
+		if err != nil {
+			return fmt.Errorf("error expiring account exports: %w", err)
+		}
+		logRetention("account_exports", deleted)
+	}
+
+	return nil
+}
+
+// expireRequestLogs archives expired logs in batches, deleting each batch
+// only once its archive is stored. A failed upload leaves the logs in place
+// for the next run.
+func (s *retentionService) expireRequestLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	if s.archiver == nil {
+		return s.requestLogRepo.DeleteBefore(ctx, cutoff, 0)
+	}
+
+	var total int64
+	for {
+		logs, err := s.requestLogRepo.ListBefore(ctx, cutoff, archiveBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(logs) == 0 {
+			return total, nil
+		}
+
+		body, err := encodeNDJSONGzip(logs)
+		if err != nil {
+			return total, fmt.Errorf("error encoding archive: %w", err)
+		}
+
+		first, last := logs[0], logs[len(logs)-1]
+		key := fmt.Sprintf("%s%s/%d-%d.ndjson.gz", requestLogArchivePrefix, first.Timestamp.UTC().Format("2006/01/02"), first.ID, last.ID)
+		if err := s.archiver.Archive(ctx, key, body); err != nil {
+			return total, fmt.Errorf("error archiving %s: %w", key, err)
+		}
+
+		deleted, err := s.requestLogRepo.DeleteBefore(ctx, cutoff, last.ID)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+func encodeNDJSONGzip(logs []models.RequestLog) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, entry := range logs {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeNDJSONGzip reads the logs of an archive, which may have been
+// decompressed already when it was fetched.
+func decodeNDJSONGzip(body []byte) ([]models.RequestLog, error) {
+	var r io.Reader = bytes.NewReader(body)
+	if len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	var logs []models.RequestLog
+	decoder := json.NewDecoder(r)
+	for {
+		var entry models.RequestLog
+		err := decoder.Decode(&entry)
+		if err == io.EOF {
+			return logs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, entry)
+	}
+}
+
+func logRetention(table string, deleted int64) {
+	logger.LogEvent(logrus.InfoLevel, "Expired rows deleted", logrus.Fields{
+		"table":   table,
+		"deleted": deleted,
+	})
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Request logs are only kept for a few hours, so recent API traffic is
+	// complemented by the longer search history.
+	reviewTrafficWindow = 12 * time.Hour
+	reviewSearchWindow  = 30 * 24 * time.Hour
+
+	countryTrafficWeight = 1.0
+	cityTrafficWeight    = 2.0
+	nameSearchWeight     = 3.0
+)
+
+// ReviewPriorityService scores items in the editor review queue by how much
+// API traffic the affected content receives, so popular content is fixed first.
+type ReviewPriorityService interface {
+	ScoreSubmissions(ctx context.Context, submissions []models.SubmissionLandmark) (map[uuid.UUID]float64, error)
+}
+
+type reviewPriorityService struct {
+	requestLogRepo      repository.RequestLogRepository
+	searchAnalyticsRepo repository.SearchAnalyticsRepository
+}
+
+func NewReviewPriorityService(requestLogRepo repository.RequestLogRepository, searchAnalyticsRepo repository.SearchAnalyticsRepository) ReviewPriorityService {
+	return &reviewPriorityService{
+		requestLogRepo:      requestLogRepo,
+		searchAnalyticsRepo: searchAnalyticsRepo,
+	}
+}
+
+// ScoreSubmissions returns a priority score per submission. A submission's
+// score combines traffic to its country and city listings with searches for
+// its country, city and name; cities and names weigh more because they are
+// more specific to the submission.
+func (s *reviewPriorityService) ScoreSubmissions(ctx context.Context, submissions []models.SubmissionLandmark) (map[uuid.UUID]float64, error) {
+	scores := make(map[uuid.UUID]float64, len(submissions))
+	if len(submissions) == 0 {
+		return scores, nil
+	}
+
+	var countries, cities, names, endpoints []string
+	for _, submission := range submissions {
+		countries = append(countries, normalizeTerm(submission.Country))
+		cities = append(cities, normalizeTerm(submission.City))
+		names = append(names, normalizeTerm(submission.Name))
+		endpoints = append(endpoints,
+			listingEndpoint("country", submission.Country),
+			listingEndpoint("city", submission.City),
+		)
+	}
+
+	now := time.Now()
+	requests, err := s.requestLogRepo.CountByEndpoints(endpoints, now.Add(-reviewTrafficWindow))
+	if err != nil {
+		return nil, fmt.Errorf("error counting listing traffic: %w", err)
+	}
+
+	searchSince := now.Add(-reviewSearchWindow)
+	countrySearches, err := s.searchAnalyticsRepo.TermCounts(ctx, "country", countries, searchSince)
+	if err != nil {
+		return nil, fmt.Errorf("error counting country searches: %w", err)
+	}
+	citySearches, err := s.searchAnalyticsRepo.TermCounts(ctx, "city", cities, searchSince)
+	if err != nil {
+		return nil, fmt.Errorf("error counting city searches: %w", err)
+	}
+	nameSearches, err := s.searchAnalyticsRepo.TermCounts(ctx, "name", names, searchSince)
+	if err != nil {
+		return nil, fmt.Errorf("error counting name searches: %w", err)
+	}
+
+	for _, submission := range submissions {
+		country := normalizeTerm(submission.Country)
+		city := normalizeTerm(submission.City)
+
+		countryTraffic := requests[listingEndpoint("country", submission.Country)] + countrySearches[country]
+		cityTraffic := requests[listingEndpoint("city", submission.City)] + citySearches[city]
+
+		scores[submission.ID] = float64(countryTraffic)*countryTrafficWeight +
+			float64(cityTraffic)*cityTrafficWeight +
+			float64(nameSearches[normalizeTerm(submission.Name)])*nameSearchWeight
+	}
+
+	return scores, nil
+}
+
+func normalizeTerm(term string) string {
+	return strings.ToLower(strings.TrimSpace(term))
+}
+
+// listingEndpoint returns the lowercased request log endpoint for a landmark
+// listing, e.g. /api/v1/landmarks/city/paris.
+func listingEndpoint(listing, value string) string {
+	return "/api/v1/landmarks/" + listing + "/" + normalizeTerm(value)
+}
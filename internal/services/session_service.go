@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionService tracks the tokens issued to each user. Revoked sessions
+// are kept on a denylist in the cache until their token would have expired,
+// so verifying a token does not need a database query.
+type SessionService interface {
+	Create(ctx context.Context, userID uuid.UUID, device, ip string, expiresAt time.Time) (*models.Session, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	IsRevoked(ctx context.Context, id uuid.UUID) bool
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+	cache       CacheService
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository, cache CacheService) SessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		cache:       cache,
+	}
+}
+
+func (s *sessionService) Create(ctx context.Context, userID uuid.UUID, device, ip string, expiresAt time.Time) (*models.Session, error) {
+	if len(device) > 255 {
+		device = device[:255]
+	}
+	session := &models.Session{
+		UserID:    userID,
+		Device:    device,
+		IPAddress: ip,
+		IssuedAt:  time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *sessionService) List(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	return s.sessionRepo.ListActive(ctx, userID)
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	session, err := s.sessionRepo.Revoke(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if ttl := time.Until(session.ExpiresAt); ttl > 0 {
+		if err := s.cache.Set(ctx, revokedSessionKey(id), true, ttl); err != nil {
+			log.Printf("Error adding session %s to the denylist: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// IsRevoked checks the denylist, falling back to the database when the
+// cache cannot be reached.
+func (s *sessionService) IsRevoked(ctx context.Context, id uuid.UUID) bool {
+	_, err := s.cache.Get(ctx, revokedSessionKey(id))
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, redis.Nil) {
+		return false
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		// A session that cannot be looked up is treated as revoked
+		return true
+	}
+	return session.RevokedAt != nil
+}
+
+func revokedSessionKey(id uuid.UUID) string {
+	return "session:revoked:" + id.String()
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCompPlan = errors.New("plan must be PRO or ENTERPRISE")
+	ErrInvalidCompEnd  = errors.New("comp end date must be in the future")
+	ErrNoActiveComp    = errors.New("user has no active comp subscription")
+)
+
+// SubscriptionOverrideService lets admins comp plans to users without Stripe,
+// e.g. for partners and internal testers.
+type SubscriptionOverrideService interface {
+	GrantComp(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan, until time.Time) (*models.Subscription, error)
+	RevokeComp(ctx context.Context, userID uuid.UUID) error
+}
+
+type subscriptionOverrideService struct {
+	subRepo  repository.SubscriptionRepository
+	userRepo repository.UserRepository
+}
+
+func NewSubscriptionOverrideService(subRepo repository.SubscriptionRepository, userRepo repository.UserRepository) SubscriptionOverrideService {
+	return &subscriptionOverrideService{
+		subRepo:  subRepo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *subscriptionOverrideService) GrantComp(ctx context.Context, userID uuid.UUID, plan models.SubscriptionPlan, until time.Time) (*models.Subscription, error) {
+	if plan != models.ProPlan && plan != models.EnterprisePlan {
+		return nil, ErrInvalidCompPlan
+	}
+	if !until.After(time.Now()) {
+		return nil, ErrInvalidCompEnd
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription := &models.Subscription{
+		UserID:    userID,
+		PlanType:  plan,
+		StartDate: now,
+		EndDate:   until,
+		Status:    models.SubscriptionStatusActive,
+	}
+
+	if err := s.subRepo.CreateComp(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("error creating comp subscription: %w", err)
+	}
+
+	if err := s.userRepo.GrantAccess(ctx, userID); err != nil {
+		return nil, fmt.Errorf("error granting access: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *subscriptionOverrideService) RevokeComp(ctx context.Context, userID uuid.UUID) error {
+	err := s.subRepo.RevokeComp(ctx, userID)
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return ErrNoActiveComp
+	}
+	if err != nil {
+		return fmt.Errorf("error revoking comp subscription: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/repository"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TimezoneProvider looks up the IANA timezone at a point.
+type TimezoneProvider interface {
+	Lookup(ctx context.Context, lat, lon float64) (string, error)
+}
+
+// NewTimezoneProvider returns the provider cfg selects, reached through
+// client, or nil when lookups are disabled.
+func NewTimezoneProvider(cfg *config.TimezoneConfig, client *http.Client) (TimezoneProvider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "geonames":
+		return &geoNamesTimezoneProvider{client: client, username: cfg.GeoNamesUsername}, nil
+	case "google":
+		return &googleTimezoneProvider{client: client, apiKey: cfg.GoogleAPIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown timezone provider %q", cfg.Provider)
+	}
+}
+
+type geoNamesTimezoneProvider struct {
+	client   *http.Client
+	username string
+}
+
+func (p *geoNamesTimezoneProvider) Lookup(ctx context.Context, lat, lon float64) (string, error) {
+	params := url.Values{
+		"lat":      {strconv.FormatFloat(lat, 'f', 6, 64)},
+		"lng":      {strconv.FormatFloat(lon, 'f', 6, 64)},
+		"username": {p.username},
+	}
+
+	var response struct {
+		TimezoneID string `json:"timezoneId"`
+		Status     *struct {
+			Message string `json:"message"`
+		} `json:"status"`
+	}
+	if err := getJSON(ctx, p.client, "http://api.geonames.org/timezoneJSON?"+params.Encode(), "", &response); err != nil {
+		return "", err
+	}
+	if response.Status != nil {
+		return "", fmt.Errorf("provider returned %s", response.Status.Message)
+	}
+	return response.TimezoneID, nil
+}
+
+type googleTimezoneProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+func (p *googleTimezoneProvider) Lookup(ctx context.Context, lat, lon float64) (string, error) {
+	params := url.Values{
+		"location":  {strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lon, 'f', 6, 64)},
+		"timestamp": {strconv.FormatInt(time.Now().Unix(), 10)},
+		"key":       {p.apiKey},
+	}
+
+	var response struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"errorMessage"`
+		TimeZoneID   string `json:"timeZoneId"`
+	}
+	if err := getJSON(ctx, p.client, "https://maps.googleapis.com/maps/api/timezone/json?"+params.Encode(), "", &response); err != nil {
+		return "", err
+	}
+	switch response.Status {
+	case "OK":
+		return response.TimeZoneID, nil
+	case "ZERO_RESULTS":
+		return "", nil
+	default:
+		return "", fmt.Errorf("provider returned %s: %s", response.Status, response.ErrorMessage)
+	}
+}
+
+// TimezoneService fills in the timezones of landmarks that lack one.
+type TimezoneService interface {
+	// BackfillTimezones looks up the timezone of up to a batch of landmarks
+	// without one and returns how many it set.
+	BackfillTimezones(ctx context.Context) (int, error)
+}
+
+type timezoneService struct {
+	provider     TimezoneProvider
+	landmarkRepo repository.LandmarkRepository
+	config       *config.TimezoneConfig
+}
+
+func NewTimezoneService(provider TimezoneProvider, landmarkRepo repository.LandmarkRepository, cfg *config.TimezoneConfig) TimezoneService {
+	return &timezoneService{
+		provider:     provider,
+		landmarkRepo: landmarkRepo,
+		config:       cfg,
+	}
+}
+
+func (s *timezoneService) BackfillTimezones(ctx context.Context) (int, error) {
+	if s.provider == nil {
+		return 0, nil
+	}
+
+	landmarks, err := s.landmarkRepo.ListMissingTimezone(ctx, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, landmark := range landmarks {
+		timezone, err := s.provider.Lookup(ctx, landmark.Latitude, landmark.Longitude)
+		if err != nil {
+			log.Printf("Error looking up timezone of landmark %s: %v", landmark.ID, err)
+			continue
+		}
+		// Only store zones this server can load, so responses can always
+		// compute the local time. Points without one, such as at sea, get
+		// the nautical zone of their longitude so they are not retried.
+		if _, err := time.LoadLocation(timezone); timezone == "" || err != nil {
+			timezone = nauticalTimezone(landmark.Longitude)
+		}
+		if err := s.landmarkRepo.SetTimezone(ctx, landmark.ID, timezone); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// nauticalTimezone returns the Etc zone of the 15 degree band containing
+// lon. Etc zones have inverted signs, so Etc/GMT-2 is two hours ahead of UTC.
+func nauticalTimezone(lon float64) string {
+	offset := int(math.Round(lon / 15))
+	switch {
+	case offset == 0:
+		return "Etc/GMT"
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+}
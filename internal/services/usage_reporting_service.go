@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"time"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/sub"
+	"github.com/stripe/stripe-go/v72/usagerecord"
+)
+
+// UsageReportingService pushes request counts for metered plans to Stripe
+// so overages are invoiced automatically at the end of the billing period.
+type UsageReportingService interface {
+	ReportDailyUsage(ctx context.Context, reportDate time.Time) error
+}
+
+type usageReportingService struct {
+	subRepo       repository.SubscriptionRepository
+	usageRepo     repository.APIUsageRepository
+	reportRepo    repository.UsageReportRepository
+	billingConfig *config.BillingConfig
+}
+
+func NewUsageReportingService(
+	subRepo repository.SubscriptionRepository,
+	usageRepo repository.APIUsageRepository,
+	reportRepo repository.UsageReportRepository,
+	billingConfig *config.BillingConfig,
+) UsageReportingService {
+	return &usageReportingService{
+		subRepo:       subRepo,
+		usageRepo:     usageRepo,
+		reportRepo:    reportRepo,
+		billingConfig: billingConfig,
+	}
+}
+
+// ReportDailyUsage submits, for every metered subscription, the requests made
+// since the previous report. Each subscription gets at most one report per
+// day; failed reports are retried with the same quantity and idempotency key.
+func (s *usageReportingService) ReportDailyUsage(ctx context.Context, reportDate time.Time) error {
+	reportDate = truncateToDay(reportDate)
+
+	for plan, priceID := range s.billingConfig.MeteredPriceIDs {
+		if priceID == "" {
+			continue
+		}
+
+		subscriptions, err := s.subRepo.ListActiveByPlan(ctx, plan)
+		if err != nil {
+			return fmt.Errorf("failed to list %s subscriptions: %w", plan, err)
+		}
+
+		for _, subscription := range subscriptions {
+			if err := s.reportSubscriptionUsage(ctx, subscription, priceID, reportDate); err != nil {
+				log.Printf("Error reporting usage for subscription %s: %v", subscription.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *usageReportingService) reportSubscriptionUsage(ctx context.Context, subscription *models.Subscription, priceID string, reportDate time.Time) error {
+	// Subscriptions without a Stripe counterpart cannot carry usage records.
+	if subscription.StripePlanID == "" {
+		return nil
+	}
+
+	report, err := s.reportRepo.GetBySubscriptionAndDate(ctx, subscription.ID, reportDate)
+	if err != nil {
+		return err
+	}
+	if report != nil && report.Status == models.UsageReportReported {
+		return nil
+	}
+
+	if report == nil {
+		report, err = s.newUsageReport(ctx, subscription, priceID, reportDate)
+		if err != nil || report == nil {
+			return err
+		}
+	}
+
+	params := &stripe.UsageRecordParams{
+		SubscriptionItem: stripe.String(report.StripeSubscriptionItemID),
+		Quantity:         stripe.Int64(report.Quantity),
+		Timestamp:        stripe.Int64(time.Now().Unix()),
+		Action:           stripe.String(stripe.UsageRecordActionIncrement),
+	}
+	params.SetIdempotencyKey(report.IdempotencyKey)
+
+	record, err := usagerecord.New(params)
+	if err != nil {
+		if markErr := s.reportRepo.MarkFailed(ctx, report.ID, err.Error()); markErr != nil {
+			log.Printf("Error marking usage report %s as failed: %v", report.ID, markErr)
+		}
+		return err
+	}
+
+	return s.reportRepo.MarkReported(ctx, report.ID, record.ID)
+}
+
+func (s *usageReportingService) newUsageReport(ctx context.Context, subscription *models.Subscription, priceID string, reportDate time.Time) (*models.UsageReport, error) {
+	periodStart, periodEnd := billingPeriod(subscription, time.Now())
+
+	usage, err := s.usageRepo.GetCurrentUsage(subscription.UserID.String(), periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		return nil, nil
+	}
+
+	alreadyReported, err := s.reportRepo.SumReportedForPeriod(ctx, subscription.ID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := int64(usage.RequestCount) - alreadyReported
+	if quantity <= 0 {
+		return nil, nil
+	}
+
+	itemID, err := meteredSubscriptionItemID(subscription.StripePlanID, priceID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UsageReport{
+		SubscriptionID:           subscription.ID,
+		UserID:                   subscription.UserID,
+		StripeSubscriptionItemID: itemID,
+		ReportDate:               reportDate,
+		PeriodStart:              periodStart,
+		PeriodEnd:                periodEnd,
+		Quantity:                 quantity,
+		IdempotencyKey:           fmt.Sprintf("usage-%s-%s", subscription.ID, reportDate.Format("2006-01-02")),
+		Status:                   models.UsageReportPending,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// meteredSubscriptionItemID finds the item of a Stripe subscription that is
+// billed with the given metered price.
+func meteredSubscriptionItemID(stripeSubscriptionID, priceID string) (string, error) {
+	stripeSub, err := sub.Get(stripeSubscriptionID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if stripeSub.Items != nil {
+		for _, item := range stripeSub.Items.Data {
+			if item.Price != nil && item.Price.ID == priceID {
+				return item.ID, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("subscription %s has no item for metered price %s", stripeSubscriptionID, priceID)
+}
+
+// billingPeriod returns the period the subscription is currently in, rolling
+// the stored end date forward month by month when it has already passed.
+func billingPeriod(subscription *models.Subscription, now time.Time) (time.Time, time.Time) {
+	periodStart := subscription.StartDate
+	periodEnd := subscription.EndDate
+
+	if now.After(periodEnd) {
+		for periodEnd.Before(now) {
+			periodStart = periodEnd
+			periodEnd = periodEnd.AddDate(0, 1, 0)
+		}
+	}
+
+	return periodStart, periodEnd
+}
+
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"landmark-api/internal/config"
+	"landmark-api/internal/models"
+	"landmark-api/internal/repository"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// statementBatchSize is how many ended periods are read at a time when
+// making statements.
+const statementBatchSize = 100
+
+var usageStatementCSVHeader = []string{"type", "name", "value"}
+
+// UsageStatementService makes a statement for every billing period that
+// ends and emails its owner a summary.
+type UsageStatementService interface {
+	// GenerateStatements makes the statements of the periods that ended
+	// within the lookback and returns how many it made.
+	GenerateStatements(ctx context.Context, now time.Time) (int, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.UsageStatement, error)
+	Get(ctx context.Context, id, userID uuid.UUID) (*models.UsageStatement, error)
+	// WriteCSV writes the statement as type,name,value rows: its summary
+	// first, then one row per endpoint with its requests.
+	WriteCSV(statement *models.UsageStatement, w io.Writer) error
+	// WritePDF writes the statement as a one-page PDF.
+	WritePDF(statement *models.UsageStatement, w io.Writer) error
+}
+
+type usageStatementService struct {
+	repo          repository.UsageStatementRepository
+	subRepo       repository.SubscriptionRepository
+	logRepo       repository.RequestLogRepository
+	usage         APIUsageService
+	notifications NotificationService
+	config        *config.BillingConfig
+}
+
+func NewUsageStatementService(
+	repo repository.UsageStatementRepository,
+	subRepo repository.SubscriptionRepository,
+	logRepo repository.RequestLogRepository,
+	usage APIUsageService,
+	notifications NotificationService,
+	cfg *config.BillingConfig,
+) UsageStatementService {
+	return &usageStatementService{
+		repo:          repo,
+		subRepo:       subRepo,
+		logRepo:       logRepo,
+		usage:         usage,
+		notifications: notifications,
+		config:        cfg,
+	}
+}
+
+func (s *usageStatementService) GenerateStatements(ctx context.Context, now time.Time) (int, error) {
+	made := 0
+	for {
+		periods, err := s.repo.ListUnstated(ctx, now.Add(-s.config.StatementLookback), now, statementBatchSize)
+		if err != nil {
+			return made, err
+		}
+
+		batchMade := 0
+		for _, period := range periods {
+			created, err := s.generate(ctx, period)
+			if err != nil {
+				log.Printf("Error making usage statement for user %s: %v", period.UserID, err)
+				continue
+			}
+			if created {
+				batchMade++
+			}
+		}
+		made += batchMade
+
+		// Periods that failed are listed again, so stop once a batch makes
+		// no progress
+		if len(periods) < statementBatchSize || batchMade == 0 {
+			return made, nil
+		}
+	}
+}
+
+// generate makes the statement of one ended period and notifies its owner,
+// reporting false when another instance made it first.
+func (s *usageStatementService) generate(ctx context.Context, period models.APIUsage) (bool, error) {
+	userID, err := uuid.Parse(period.UserID)
+	if err != nil {
+		return false, err
+	}
+	subscription, err := s.subRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	quota, err := s.usage.QuotaLimit(ctx, userID, subscription.PlanType)
+	if err != nil {
+		return false, err
+	}
+	endpoints, err := s.logRepo.UserTopEndpoints(ctx, period.UserID, period.PeriodStart, period.PeriodEnd, s.config.StatementTopEndpoints)
+	if err != nil {
+		return false, err
+	}
+
+	statement := &models.UsageStatement{
+		UserID:       userID,
+		PeriodStart:  period.PeriodStart,
+		PeriodEnd:    period.PeriodEnd,
+		Plan:         subscription.PlanType,
+		Requests:     int64(period.RequestCount),
+		Quota:        int64(quota),
+		TopEndpoints: endpoints,
+	}
+	if quota >= 0 && statement.Requests > statement.Quota {
+		statement.Overage = statement.Requests - statement.Quota
+	}
+	created, err := s.repo.Create(ctx, statement)
+	if err != nil || !created {
+		return false, err
+	}
+
+	// The statement is kept even if the email fails, as it can still be
+	// downloaded
+	data := map[string]string{
+		"PeriodStart": NotificationDate(statement.PeriodStart),
+		"PeriodEnd":   NotificationDate(statement.PeriodEnd),
+		"Requests":    strconv.FormatInt(statement.Requests, 10),
+		"Plan":        string(statement.Plan),
+		"Overage":     "",
+		"TopEndpoint": "",
+	}
+	if statement.Overage > 0 {
+		data["Overage"] = strconv.FormatInt(statement.Overage, 10)
+	}
+	if len(endpoints) > 0 {
+		data["TopEndpoint"] = endpoints[0].Method + " " + endpoints[0].Route
+	}
+	if err := s.notifications.Notify(ctx, userID, NotificationUsageStatement, data); err != nil {
+		log.Printf("Error sending usage statement to user %s: %v", userID, err)
+	}
+	return true, nil
+}
+
+func (s *usageStatementService) List(ctx context.Context, userID uuid.UUID) ([]models.UsageStatement, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *usageStatementService) Get(ctx context.Context, id, userID uuid.UUID) (*models.UsageStatement, error) {
+	return s.repo.Get(ctx, id, userID)
+}
+
+func (s *usageStatementService) WriteCSV(statement *models.UsageStatement, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		usageStatementCSVHeader,
+		{"summary", "period_start", statement.PeriodStart.UTC().Format(time.RFC3339)},
+		{"summary", "period_end", statement.PeriodEnd.UTC().Format(time.RFC3339)},
+		{"summary", "plan", string(statement.Plan)},
+		{"summary", "requests", strconv.FormatInt(statement.Requests, 10)},
+		{"summary", "quota", strconv.FormatInt(statement.Quota, 10)},
+		{"summary", "overage", strconv.FormatInt(statement.Overage, 10)},
+	}
+	for _, endpoint := range statement.TopEndpoints {
+		rows = append(rows, []string{"endpoint", endpoint.Method + " " + endpoint.Route, strconv.FormatInt(endpoint.Requests, 10)})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("error writing usage statement: %w", err)
+	}
+	return nil
+}
+
+func (s *usageStatementService) WritePDF(statement *models.UsageStatement, w io.Writer) error {
+	quota := "unlimited"
+	if statement.Quota >= 0 {
+		quota = strconv.FormatInt(statement.Quota, 10)
+	}
+	lines := []string{
+		fmt.Sprintf("Period: %s to %s", NotificationDate(statement.PeriodStart), NotificationDate(statement.PeriodEnd)),
+		"Plan: " + string(statement.Plan),
+		"Requests: " + strconv.FormatInt(statement.Requests, 10),
+		"Quota: " + quota,
+		"Overage: " + strconv.FormatInt(statement.Overage, 10),
+		"",
+		"Top endpoints",
+	}
+	if len(statement.TopEndpoints) == 0 {
+		lines = append(lines, "No requests were logged in this period.")
+	}
+	for _, endpoint := range statement.TopEndpoints {
+		lines = append(lines, fmt.Sprintf("%s %s: %d requests, %d errors", endpoint.Method, endpoint.Route, endpoint.Requests, endpoint.Errors))
+	}
+	return writeTextPDF(w, "Landmark API usage statement", lines)
+}
@@ -1,9 +1,9 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 	"strconv"
 )
 
@@ -16,13 +16,33 @@ type WeatherData struct {
 	} `json:"weather"`
 }
 
-func FetchWeatherData(lat, lon float64) (*WeatherData, error) {
-	apiKey := os.Getenv("OPEN_WEATHER_API_KEY")
+var (
+	weatherHTTPClient = http.DefaultClient
+	weatherAPIKey     string
+)
+
+// SetWeatherHTTPClient replaces the client used to reach the weather
+// provider, e.g. with a recording client for offline development.
+func SetWeatherHTTPClient(client *http.Client) {
+	weatherHTTPClient = client
+}
+
+// SetWeatherAPIKey sets the OpenWeather API key used by FetchWeatherData.
+func SetWeatherAPIKey(apiKey string) {
+	weatherAPIKey = apiKey
+}
+
+func FetchWeatherData(ctx context.Context, lat, lon float64) (*WeatherData, error) {
 	latStr := strconv.FormatFloat(lat, 'f', 6, 64)
 	lonStr := strconv.FormatFloat(lon, 'f', 6, 64)
-	url := "http://api.openweathermap.org/data/2.5/weather?lat=" + latStr + "&lon=" + lonStr + "&units=metric&appid=" + apiKey
+	url := "http://api.openweathermap.org/data/2.5/weather?lat=" + latStr + "&lon=" + lonStr + "&units=metric&appid=" + weatherAPIKey
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := http.Get(url)
+	resp, err := weatherHTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
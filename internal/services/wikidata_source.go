@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/geo"
+	"landmark-api/internal/models"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	wikidataAPIURL = "https://www.wikidata.org/w/api.php"
+	commonsFileURL = "https://commons.wikimedia.org/wiki/Special:FilePath/"
+	// wikidataCandidates is how many search results are checked for one
+	// within the match radius
+	wikidataCandidates = 10
+)
+
+// Wikidata properties the enrichment reads
+const (
+	propCoordinates     = "P625"
+	propOfficialWebsite = "P856"
+	propHeritage        = "P1435"
+	propImage           = "P18"
+)
+
+// EnrichmentSource finds what is known about a landmark elsewhere.
+type EnrichmentSource interface {
+	// Lookup returns the enrichment of the entity named name near lat,lon,
+	// or nil if none matches. LandmarkID and EnrichedAt are left unset.
+	Lookup(ctx context.Context, name string, lat, lon float64) (*models.LandmarkEnrichment, error)
+}
+
+type wikidataSource struct {
+	client *http.Client
+	config *config.EnrichmentConfig
+}
+
+// NewWikidataSource returns a source that matches landmarks to Wikidata
+// entities and reads summaries from the configured Wikipedia edition.
+func NewWikidataSource(cfg *config.EnrichmentConfig, client *http.Client) EnrichmentSource {
+	return &wikidataSource{client: client, config: cfg}
+}
+
+type wikidataEntity struct {
+	Claims map[string][]struct {
+		MainSnak struct {
+			DataValue struct {
+				Value json.RawMessage `json:"value"`
+			} `json:"datavalue"`
+		} `json:"mainsnak"`
+	} `json:"claims"`
+	Labels map[string]struct {
+		Value string `json:"value"`
+	} `json:"labels"`
+	Sitelinks map[string]struct {
+		Title string `json:"title"`
+	} `json:"sitelinks"`
+}
+
+// stringClaims returns the string values of property, skipping claims such
+// as "no value" that have none.
+func (e wikidataEntity) stringClaims(property string) []string {
+	var values []string
+	for _, claim := range e.Claims[property] {
+		var value string
+		if json.Unmarshal(claim.MainSnak.DataValue.Value, &value) == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// coordinateClaims returns the coordinates of e as latitude, longitude
+// pairs.
+func (e wikidataEntity) coordinateClaims() [][2]float64 {
+	var values [][2]float64
+	for _, claim := range e.Claims[propCoordinates] {
+		var value struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		}
+		if json.Unmarshal(claim.MainSnak.DataValue.Value, &value) == nil {
+			values = append(values, [2]float64{value.Latitude, value.Longitude})
+		}
+	}
+	return values
+}
+
+// itemClaims returns the IDs of the entities property refers to.
+func (e wikidataEntity) itemClaims(property string) []string {
+	var ids []string
+	for _, claim := range e.Claims[property] {
+		var value struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(claim.MainSnak.DataValue.Value, &value) == nil && value.ID != "" {
+			ids = append(ids, value.ID)
+		}
+	}
+	return ids
+}
+
+func (s *wikidataSource) Lookup(ctx context.Context, name string, lat, lon float64) (*models.LandmarkEnrichment, error) {
+	ids, err := s.search(ctx, name)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	entities, err := s.entities(ctx, ids, "claims|sitelinks")
+	if err != nil {
+		return nil, err
+	}
+
+	// The closest candidate within the radius is the match
+	var matchID string
+	var match wikidataEntity
+	best := s.config.MatchRadiusKm
+	for _, id := range ids {
+		entity, ok := entities[id]
+		if !ok {
+			continue
+		}
+		for _, c := range entity.coordinateClaims() {
+			if distance := geo.HaversineKm(lat, lon, c[0], c[1]); distance <= best {
+				best, matchID, match = distance, id, entity
+			}
+		}
+	}
+	if matchID == "" {
+		return nil, nil
+	}
+
+	enrichment := &models.LandmarkEnrichment{
+		WikidataID: matchID,
+		Images:     models.StringList{},
+	}
+	if websites := match.stringClaims(propOfficialWebsite); len(websites) > 0 {
+		enrichment.OfficialWebsite = websites[0]
+	}
+	for _, file := range match.stringClaims(propImage) {
+		enrichment.Images = append(enrichment.Images, commonsFileURL+url.PathEscape(strings.ReplaceAll(file, " ", "_")))
+	}
+	if enrichment.HeritageStatus, err = s.heritageStatus(ctx, match); err != nil {
+		return nil, err
+	}
+	if link, ok := match.Sitelinks[s.config.Language+"wiki"]; ok {
+		title := url.PathEscape(strings.ReplaceAll(link.Title, " ", "_"))
+		enrichment.WikipediaURL = s.wikipediaURL() + "/wiki/" + title
+		if enrichment.Summary, err = s.summary(ctx, title); err != nil {
+			return nil, err
+		}
+	}
+	return enrichment, nil
+}
+
+func (s *wikidataSource) search(ctx context.Context, name string) ([]string, error) {
+	params := url.Values{
+		"action":   {"wbsearchentities"},
+		"search":   {name},
+		"language": {s.config.Language},
+		"type":     {"item"},
+		"limit":    {fmt.Sprint(wikidataCandidates)},
+		"format":   {"json"},
+	}
+
+	var response struct {
+		Search []struct {
+			ID string `json:"id"`
+		} `json:"search"`
+	}
+	if err := getJSON(ctx, s.client, wikidataAPIURL+"?"+params.Encode(), s.config.UserAgent, &response); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(response.Search))
+	for _, result := range response.Search {
+		ids = append(ids, result.ID)
+	}
+	return ids, nil
+}
+
+func (s *wikidataSource) entities(ctx context.Context, ids []string, props string) (map[string]wikidataEntity, error) {
+	params := url.Values{
+		"action":     {"wbgetentities"},
+		"ids":        {strings.Join(ids, "|")},
+		"props":      {props},
+		"languages":  {s.config.Language},
+		"sitefilter": {s.config.Language + "wiki"},
+		"format":     {"json"},
+	}
+
+	var response struct {
+		Entities map[string]wikidataEntity `json:"entities"`
+	}
+	if err := getJSON(ctx, s.client, wikidataAPIURL+"?"+params.Encode(), s.config.UserAgent, &response); err != nil {
+		return nil, err
+	}
+	return response.Entities, nil
+}
+
+// heritageStatus returns the labels of the heritage designations of entity.
+func (s *wikidataSource) heritageStatus(ctx context.Context, entity wikidataEntity) (models.StringList, error) {
+	ids := entity.itemClaims(propHeritage)
+	if len(ids) == 0 {
+		return models.StringList{}, nil
+	}
+
+	designations, err := s.entities(ctx, ids, "labels")
+	if err != nil {
+		return nil, err
+	}
+	status := models.StringList{}
+	for _, id := range ids {
+		if label, ok := designations[id].Labels[s.config.Language]; ok {
+			status = append(status, label.Value)
+		}
+	}
+	return status, nil
+}
+
+// summary returns the lead extract of the Wikipedia article with the
+// escaped title.
+func (s *wikidataSource) summary(ctx context.Context, title string) (string, error) {
+	var response struct {
+		Extract string `json:"extract"`
+	}
+	if err := getJSON(ctx, s.client, s.wikipediaURL()+"/api/rest_v1/page/summary/"+title, s.config.UserAgent, &response); err != nil {
+		return "", err
+	}
+	return response.Extract, nil
+}
+
+func (s *wikidataSource) wikipediaURL() string {
+	return "https://" + s.config.Language + ".wikipedia.org"
+}
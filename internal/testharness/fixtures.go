@@ -0,0 +1,239 @@
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"landmark-api/internal/models"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// sequence numbers fixtures so their unique fields never collide.
+var sequence atomic.Int64
+
+func next() int64 {
+	return sequence.Add(1)
+}
+
+// UserFixture builds a user and their subscription. By default the user
+// has a unique email and an active Free subscription whose period started
+// now and ends in a month.
+type UserFixture struct {
+	user         models.User
+	subscription models.Subscription
+}
+
+func User() *UserFixture {
+	n := next()
+	now := time.Now()
+	return &UserFixture{
+		user: models.User{
+			ID:           uuid.New(),
+			Name:         fmt.Sprintf("User %d", n),
+			Email:        fmt.Sprintf("user%d@example.com", n),
+			PasswordHash: "not-a-hash",
+			Role:         "user",
+			HasAccess:    true,
+		},
+		subscription: models.Subscription{
+			ID:        uuid.New(),
+			PlanType:  models.FreePlan,
+			StartDate: now,
+			EndDate:   now.AddDate(0, 1, 0),
+			Status:    "active",
+		},
+	}
+}
+
+func (f *UserFixture) Email(email string) *UserFixture {
+	f.user.Email = email
+	return f
+}
+
+func (f *UserFixture) Role(role string) *UserFixture {
+	f.user.Role = role
+	return f
+}
+
+func (f *UserFixture) Plan(plan models.SubscriptionPlan) *UserFixture {
+	f.subscription.PlanType = plan
+	return f
+}
+
+func (f *UserFixture) Status(status string) *UserFixture {
+	f.subscription.Status = status
+	return f
+}
+
+// Period sets the billing period of the subscription, e.g. one that has
+// already ended to exercise its rollover.
+func (f *UserFixture) Period(start, end time.Time) *UserFixture {
+	f.subscription.StartDate = start
+	f.subscription.EndDate = end
+	return f
+}
+
+// Create inserts the user and their subscription.
+func (f *UserFixture) Create(ctx context.Context, db *gorm.DB) (*models.User, *models.Subscription, error) {
+	user := f.user
+	subscription := f.subscription
+	subscription.UserID = user.ID
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&subscription).Error
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating user fixture: %w", err)
+	}
+	return &user, &subscription, nil
+}
+
+// APIUsage inserts requests counted against userID in the period from
+// start to end.
+func APIUsage(ctx context.Context, db *gorm.DB, userID uuid.UUID, start, end time.Time, requests int) (*models.APIUsage, error) {
+	usage := models.APIUsage{
+		UserID:       userID.String(),
+		RequestCount: requests,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+	}
+	if err := db.WithContext(ctx).Create(&usage).Error; err != nil {
+		return nil, fmt.Errorf("error creating API usage fixture: %w", err)
+	}
+	return &usage, nil
+}
+
+// LandmarkFixture builds a landmark with its details and images. By
+// default it is a published historical landmark in Paris with a unique
+// name, open every day from 09:00 to 17:00 and free to visit.
+type LandmarkFixture struct {
+	landmark  models.Landmark
+	detail    models.LandmarkDetail
+	imageURLs []string
+}
+
+func Landmark() *LandmarkFixture {
+	n := next()
+	return &LandmarkFixture{
+		landmark: models.Landmark{
+			ID:          uuid.New(),
+			Name:        fmt.Sprintf("Landmark %d", n),
+			Description: fmt.Sprintf("Description of landmark %d", n),
+			Latitude:    48.8566,
+			Longitude:   2.3522,
+			Country:     "France",
+			City:        "Paris",
+			Category:    "Historical",
+			Timezone:    "Europe/Paris",
+			Status:      models.LandmarkPublished,
+			Tags:        models.StringList{},
+		},
+		detail: models.LandmarkDetail{
+			ID:           uuid.New(),
+			OpeningHours: everyDay("09:00-17:00"),
+			TicketPrices: map[string]string{"Adult": "Free"},
+		},
+	}
+}
+
+func (f *LandmarkFixture) Name(name string) *LandmarkFixture {
+	f.landmark.Name = name
+	return f
+}
+
+// In places the landmark in city, at latitude and longitude.
+func (f *LandmarkFixture) In(city, country string, latitude, longitude float64) *LandmarkFixture {
+	f.landmark.City = city
+	f.landmark.Country = country
+	f.landmark.Latitude = latitude
+	f.landmark.Longitude = longitude
+	return f
+}
+
+func (f *LandmarkFixture) Timezone(timezone string) *LandmarkFixture {
+	f.landmark.Timezone = timezone
+	return f
+}
+
+func (f *LandmarkFixture) Category(category string) *LandmarkFixture {
+	f.landmark.Category = category
+	return f
+}
+
+func (f *LandmarkFixture) Status(status models.LandmarkStatus) *LandmarkFixture {
+	f.landmark.Status = status
+	return f
+}
+
+func (f *LandmarkFixture) Tags(tags ...string) *LandmarkFixture {
+	f.landmark.Tags = append(f.landmark.Tags, tags...)
+	return f
+}
+
+func (f *LandmarkFixture) OpeningHours(hours map[string]string) *LandmarkFixture {
+	f.detail.OpeningHours = hours
+	return f
+}
+
+func (f *LandmarkFixture) TicketPrices(prices map[string]string) *LandmarkFixture {
+	f.detail.TicketPrices = prices
+	return f
+}
+
+// Images adds photos to the gallery, the first as the primary one.
+func (f *LandmarkFixture) Images(urls ...string) *LandmarkFixture {
+	f.imageURLs = append(f.imageURLs, urls...)
+	return f
+}
+
+// Create inserts the landmark with its details and images.
+func (f *LandmarkFixture) Create(ctx context.Context, db *gorm.DB) (*models.Landmark, error) {
+	landmark := f.landmark
+	detail := f.detail
+	detail.LandmarkID = landmark.ID
+	if len(f.imageURLs) > 0 {
+		landmark.ImageUrl = f.imageURLs[0]
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Images").Create(&landmark).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&detail).Error; err != nil {
+			return err
+		}
+		for i, url := range f.imageURLs {
+			image := models.LandmarkImage{
+				ID:           uuid.New(),
+				LandmarkID:   landmark.ID,
+				ImageURL:     url,
+				MediaType:    models.MediaPhoto,
+				DisplayOrder: i,
+				IsPrimary:    i == 0,
+			}
+			if err := tx.Create(&image).Error; err != nil {
+				return err
+			}
+			landmark.Images = append(landmark.Images, image)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating landmark fixture: %w", err)
+	}
+	return &landmark, nil
+}
+
+func everyDay(hours string) map[string]string {
+	days := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+	opening := make(map[string]string, len(days))
+	for _, day := range days {
+		opening[day] = hours
+	}
+	return opening
+}
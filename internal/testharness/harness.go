@@ -0,0 +1,153 @@
+// Package testharness runs the API's dependencies in Docker for integration
+// tests: a migrated Postgres and a Redis, started with testcontainers-go,
+// plus fixture builders for the records tests need. It needs a Docker
+// daemon; nothing in the API itself imports it.
+//
+//	env, err := testharness.Start(ctx)
+//	if err != nil { ... }
+//	defer env.Close()
+//
+//	user, subscription, err := testharness.User().Plan(models.ProPlan).Create(ctx, env.DB)
+//	err = repository.NewAPIUsageRepository(env.DB).IncrementUsage(user.ID)
+//
+// Call Reset between tests to empty the database and Redis without
+// starting new containers.
+package testharness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"landmark-api/internal/config"
+	"landmark-api/internal/migrations"
+	"landmark-api/internal/services"
+	"strings"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	postgresImage = "postgres:16-alpine"
+	redisImage    = "redis:7-alpine"
+)
+
+// Env is a running Postgres and Redis, with clients for both.
+type Env struct {
+	// DB is connected to a database with every migration applied.
+	DB *gorm.DB
+	// Redis is a client for the Redis that Cache also uses.
+	Redis *goredis.Client
+	// Cache and CacheConfig are what the API would build for this Redis.
+	Cache       *services.RedisCacheService
+	CacheConfig *config.CacheConfig
+	// DatabaseURL is the connection string of the database.
+	DatabaseURL string
+
+	postgres *tcpostgres.PostgresContainer
+	redis    *tcredis.RedisContainer
+}
+
+// Start runs Postgres and Redis containers, migrates the database and
+// connects to both. If any step fails, the containers started so far are
+// removed.
+func Start(ctx context.Context) (_ *Env, err error) {
+	env := &Env{}
+	defer func() {
+		if err != nil {
+			env.Close()
+		}
+	}()
+
+	env.postgres, err = tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase("landmarks"),
+		tcpostgres.WithUsername("landmarks"),
+		tcpostgres.WithPassword("landmarks"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error starting postgres: %w", err)
+	}
+	env.DatabaseURL, err = env.postgres.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		return nil, fmt.Errorf("error getting postgres address: %w", err)
+	}
+
+	env.DB, err = gorm.Open(gormpostgres.Open(env.DatabaseURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	if _, err := migrations.NewMigrator(env.DB).Up(ctx, migrations.Options{}); err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+
+	env.redis, err = tcredis.Run(ctx, redisImage)
+	if err != nil {
+		return nil, fmt.Errorf("error starting redis: %w", err)
+	}
+	host, err := env.redis.Host(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting redis address: %w", err)
+	}
+	port, err := env.redis.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		return nil, fmt.Errorf("error getting redis address: %w", err)
+	}
+
+	env.CacheConfig = &config.CacheConfig{RedisHost: host, RedisPort: port.Port()}
+	env.Cache, err = services.NewRedisCacheService(env.CacheConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.Cache.Ping(ctx); err != nil {
+		return nil, fmt.Errorf("error connecting to redis: %w", err)
+	}
+	env.Redis = goredis.NewClient(&goredis.Options{Addr: host + ":" + port.Port()})
+	return env, nil
+}
+
+// Reset deletes every row but the migration history, and every Redis key,
+// so the next test starts from an empty database and cache.
+func (e *Env) Reset(ctx context.Context) error {
+	var tables []string
+	err := e.DB.WithContext(ctx).Raw(`
+		SELECT quote_ident(tablename) FROM pg_tables
+		WHERE schemaname = 'public' AND tablename <> 'schema_migrations'
+	`).Scan(&tables).Error
+	if err != nil {
+		return fmt.Errorf("error listing tables: %w", err)
+	}
+	if len(tables) > 0 {
+		if err := e.DB.WithContext(ctx).Exec("TRUNCATE TABLE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+			return fmt.Errorf("error truncating tables: %w", err)
+		}
+	}
+	return e.Redis.FlushDB(ctx).Err()
+}
+
+// Close disconnects from and removes both containers.
+func (e *Env) Close() error {
+	var errs []error
+	if e.Redis != nil {
+		errs = append(errs, e.Redis.Close())
+	}
+	if e.DB != nil {
+		if db, err := e.DB.DB(); err == nil {
+			errs = append(errs, db.Close())
+		}
+	}
+	if e.redis != nil {
+		errs = append(errs, testcontainers.TerminateContainer(e.redis))
+	}
+	if e.postgres != nil {
+		errs = append(errs, testcontainers.TerminateContainer(e.postgres))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,185 @@
+// Package tracing propagates W3C trace context (traceparent and tracestate)
+// from incoming requests to logs and outbound HTTP calls.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+	// TraceIDHeader echoes the trace ID so clients can find a request in
+	// their own tracing backend without parsing traceparent.
+	TraceIDHeader = "X-Trace-ID"
+
+	version       = "00"
+	sampledFlag   = "01"
+	traceIDLength = 32
+	spanIDLength  = 16
+)
+
+// SpanContext identifies one span of a distributed trace.
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	Flags      string
+	TraceState string
+}
+
+type contextKey struct{}
+
+// Parse reads a traceparent header value. Invalid values, including the
+// all-zero IDs the spec forbids, are rejected.
+func Parse(traceparent string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, false
+	}
+
+	// Version 00 has exactly four fields; later versions may append more.
+	if parts[0] == version && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	if parts[0] == "ff" || !isHex(parts[0], 2) {
+		return SpanContext{}, false
+	}
+
+	traceID, spanID, flags := strings.ToLower(parts[1]), strings.ToLower(parts[2]), strings.ToLower(parts[3])
+	if !isHex(traceID, traceIDLength) || isZero(traceID) {
+		return SpanContext{}, false
+	}
+	if !isHex(spanID, spanIDLength) || isZero(spanID) {
+		return SpanContext{}, false
+	}
+	if !isHex(flags, 2) {
+		return SpanContext{}, false
+	}
+
+	return SpanContext{TraceID: traceID, SpanID: spanID, Flags: flags}, true
+}
+
+// New starts a new sampled trace.
+func New() SpanContext {
+	return SpanContext{
+		TraceID: randomHex(traceIDLength),
+		SpanID:  randomHex(spanIDLength),
+		Flags:   sampledFlag,
+	}
+}
+
+// Child returns a new span in the same trace.
+func (sc SpanContext) Child() SpanContext {
+	return SpanContext{
+		TraceID:    sc.TraceID,
+		SpanID:     randomHex(spanIDLength),
+		Flags:      sc.Flags,
+		TraceState: sc.TraceState,
+	}
+}
+
+// Traceparent formats the span as a version 00 traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%s", version, sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// FromRequest continues the caller's trace when the request carries a valid
+// traceparent, and starts a new trace otherwise.
+func FromRequest(r *http.Request) SpanContext {
+	parent, ok := Parse(r.Header.Get(TraceparentHeader))
+	if !ok {
+		return New()
+	}
+
+	parent.TraceState = r.Header.Get(TracestateHeader)
+	return parent.Child()
+}
+
+func NewContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// TraceID returns the trace ID stored in ctx, or an empty string.
+func TraceID(ctx context.Context) string {
+	sc, _ := FromContext(ctx)
+	return sc.TraceID
+}
+
+// Inject sets trace headers for an outbound call made on behalf of ctx. Each
+// outbound call gets its own span ID so downstream services see us as the
+// parent.
+func Inject(ctx context.Context, header http.Header) {
+	sc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	child := sc.Child()
+	header.Set(TraceparentHeader, child.Traceparent())
+	if child.TraceState != "" {
+		header.Set(TracestateHeader, child.TraceState)
+	}
+}
+
+// Transport injects trace headers into outbound requests.
+type Transport struct {
+	next http.RoundTripper
+}
+
+// NewTransport wraps next, which defaults to http.DefaultTransport.
+func NewTransport(next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{next: next}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := FromContext(req.Context()); !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	outbound := req.Clone(req.Context())
+	Inject(req.Context(), outbound.Header)
+	return t.next.RoundTrip(outbound)
+}
+
+func randomHex(length int) string {
+	b := make([]byte, length/2)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(fmt.Sprintf("tracing: reading random bytes: %v", err))
+		}
+		id := hex.EncodeToString(b)
+		if !isZero(id) {
+			return id
+		}
+	}
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,250 @@
+// Package validation checks request payloads against rules declared in
+// `validate` struct tags, e.g.
+//
+//	Email string `json:"email" validate:"required,email,max=255"`
+//
+// Rules are separated by commas and some take a parameter after "=":
+//
+//	required   the value must not be empty (or only whitespace)
+//	omitempty  skip the remaining rules when the value is empty
+//	min, max   string length in characters, slice length, or number bounds
+//	gt, lt     exclusive number bounds
+//	oneof      the value must be one of a space separated list
+//	email      a plain email address, without a display name
+//	url        an absolute http or https URL
+//	dive       apply the remaining rules to each element of a slice or map
+//
+// Nested structs are always validated, and errors name fields by their JSON
+// path, such as "landmark.name" or "image_urls[2]".
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes why one field of a payload is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is every field of a payload that failed validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct validates v, a struct or a pointer to one, and returns Errors if
+// any field breaks its rules. It panics on a rule it does not know, as that
+// is a mistake in the struct definition rather than in the payload.
+func Struct(v interface{}) error {
+	var errs Errors
+	validateStruct(reflect.Indirect(reflect.ValueOf(v)), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func validateStruct(v reflect.Value, prefix string, errs *Errors) {
+	if v.Kind() != reflect.Struct || v.Type() == timeType {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		path := name
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			// Embedded structs are flattened in JSON, so keep the prefix
+			path = prefix
+		} else if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		rules := field.Tag.Get("validate")
+		if rules == "-" {
+			continue
+		}
+		validateValue(v.Field(i), path, splitRules(rules), errs)
+	}
+}
+
+func validateValue(v reflect.Value, path string, rules []string, errs *Errors) {
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			if isEmpty(v) {
+				return
+			}
+			continue
+		case "dive":
+			diveInto(v, path, rules[i+1:], errs)
+			return
+		}
+
+		if message := check(name, param, v); message != "" {
+			*errs = append(*errs, FieldError{Field: path, Message: message})
+			// Later rules usually repeat the same complaint
+			return
+		}
+	}
+
+	v = reflect.Indirect(v)
+	if v.Kind() == reflect.Struct {
+		validateStruct(v, path, errs)
+	}
+}
+
+func diveInto(v reflect.Value, path string, rules []string, errs *Errors) {
+	v = reflect.Indirect(v)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), rules, errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), rules, errs)
+		}
+	default:
+		panic(fmt.Sprintf("validation: dive on %s, which is not a slice or map", v.Kind()))
+	}
+}
+
+// check returns why v breaks the rule, or "" if it does not.
+func check(rule, param string, v reflect.Value) string {
+	if rule == "required" {
+		if isEmpty(v) {
+			return "is required"
+		}
+		return ""
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch rule {
+	case "min", "max", "gt", "lt":
+		return checkBound(rule, param, v)
+	case "oneof":
+		options := strings.Fields(param)
+		value := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if value == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	case "email":
+		s := v.String()
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return "must be a valid email address"
+		}
+		return ""
+	case "url":
+		u, err := url.ParseRequestURI(v.String())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be a valid http or https URL"
+		}
+		return ""
+	}
+	panic(fmt.Sprintf("validation: unknown rule %q", rule))
+}
+
+func checkBound(rule, param string, v reflect.Value) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: %s=%s is not a number", rule, param))
+	}
+
+	var n float64
+	unit := ""
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, unit = float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		panic(fmt.Sprintf("validation: %s on %s", rule, v.Kind()))
+	}
+
+	switch {
+	case rule == "min" && n < limit:
+		if unit != "" {
+			return fmt.Sprintf("must have at least %s%s", param, unit)
+		}
+		return "must be at least " + param
+	case rule == "max" && n > limit:
+		if unit != "" {
+			return fmt.Sprintf("must have at most %s%s", param, unit)
+		}
+		return "must be at most " + param
+	case rule == "gt" && n <= limit:
+		return "must be greater than " + param
+	case rule == "lt" && n >= limit:
+		return "must be less than " + param
+	}
+	return ""
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Invalid:
+		return true
+	}
+	return v.IsZero()
+}
+
+func splitRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}